@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SchemeAPIKey - схема заголовка "Authorization: ApiKey <key>"
+	SchemeAPIKey = "ApiKey"
+	// APIKeyPrefix - префикс всех API-ключей; по нему ключ легко опознать в логах и при утечке
+	APIKeyPrefix = "tk_"
+)
+
+// ErrInvalidAPIKey возвращается для неизвестного, отозванного или истекшего API-ключа
+var ErrInvalidAPIKey = errors.New("недействительный API-ключ")
+
+// APIKeyInfo - сведения о действительном API-ключе, которые user-service возвращает при проверке ключа
+type APIKeyInfo struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"` // Владелец ключа
+	Roles     []string   `json:"roles"`   // Роли владельца на момент проверки
+	Scopes    []string   `json:"scopes"`
+	RateLimit int        `json:"rate_limit"` // Запросов в минуту
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Claims возвращает содержимое, которое кладется в контекст запроса с этим ключом
+func (i *APIKeyInfo) Claims() *Claims {
+	return &Claims{
+		UserID:   i.UserID,
+		Roles:    i.Roles,
+		Scopes:   i.Scopes,
+		APIKeyID: i.ID,
+	}
+}
+
+// APIKeySource находит API-ключ. Для неизвестного, отозванного или истекшего ключа возвращает ErrInvalidAPIKey.
+type APIKeySource interface {
+	LookupAPIKey(ctx context.Context, key string) (*APIKeyInfo, error)
+}
+
+// APIKeyValidator проверяет API-ключ. Middleware и перехватчики принимают "Authorization: ApiKey ...",
+// только если переданный им валидатор реализует этот интерфейс (см. WithAPIKeys).
+type APIKeyValidator interface {
+	ValidateAPIKey(key string) (*Claims, error)
+}
+
+// apiKeyValidator дополняет проверку токенов проверкой API-ключей и их лимитов запросов
+type apiKeyValidator struct {
+	TokenValidator
+	keys    APIKeySource
+	limiter *rateLimiter
+}
+
+// WithAPIKeys возвращает валидатор, который кроме токенов validator принимает API-ключи из keys
+// и ограничивает частоту запросов каждого ключа его лимитом.
+// Подключается последним, после WithDenylist.
+func WithAPIKeys(v TokenValidator, keys APIKeySource) TokenValidator {
+	return &apiKeyValidator{TokenValidator: v, keys: keys, limiter: newRateLimiter()}
+}
+
+// ValidateAPIKey проверяет ключ и расходует один запрос из его лимита
+func (v *apiKeyValidator) ValidateAPIKey(key string) (*Claims, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	info, err := v.keys.LookupAPIKey(context.Background(), key)
+	if err != nil {
+		if errors.Is(err, ErrInvalidAPIKey) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: не удалось проверить API-ключ: %v", ErrInvalidToken, err)
+	}
+	if info.ExpiresAt != nil && !time.Now().Before(*info.ExpiresAt) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if ok, retryAfter := v.limiter.allow(info.ID, info.RateLimit, time.Now()); !ok {
+		return nil, &RateLimitError{RetryAfter: retryAfter}
+	}
+	return info.Claims(), nil
+}
+
+// remoteAPIKeysMaxEntries ограничивает размер кеша RemoteAPIKeys
+const remoteAPIKeysMaxEntries = 10000
+
+// RemoteAPIKeys проверяет API-ключи в user-service (POST /api/auth/api-keys/verify)
+// и кеширует ответ на ttl, в том числе отрицательный. Отзыв ключа и изменение ролей владельца
+// вступают в силу в других сервисах с задержкой не больше ttl.
+type RemoteAPIKeys struct {
+	url        string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedAPIKey // SHA-256 ключа -> результат проверки
+}
+
+type cachedAPIKey struct {
+	info      *APIKeyInfo
+	err       error
+	expiresAt time.Time
+}
+
+// NewRemoteAPIKeys создает RemoteAPIKeys для адреса проверки url
+func NewRemoteAPIKeys(url string, ttl time.Duration) *RemoteAPIKeys {
+	return &RemoteAPIKeys{
+		url:        url,
+		ttl:        ttl,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		cache:      make(map[string]cachedAPIKey),
+	}
+}
+
+// LookupAPIKey возвращает сведения о ключе из кеша или из user-service.
+// Ошибки соединения не кешируются: ключ отклоняется, пока user-service недоступен.
+func (k *RemoteAPIKeys) LookupAPIKey(ctx context.Context, key string) (*APIKeyInfo, error) {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	k.mu.Lock()
+	cached, ok := k.cache[hash]
+	k.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.info, cached.err
+	}
+
+	info, err := k.fetch(ctx, key)
+	if err != nil && !errors.Is(err, ErrInvalidAPIKey) {
+		return nil, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(k.cache) >= remoteAPIKeysMaxEntries {
+		for h, entry := range k.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(k.cache, h)
+			}
+		}
+		if len(k.cache) >= remoteAPIKeysMaxEntries {
+			k.cache = make(map[string]cachedAPIKey)
+		}
+	}
+	k.cache[hash] = cachedAPIKey{info: info, err: err, expiresAt: now.Add(k.ttl)}
+	return info, err
+}
+
+// fetch запрашивает проверку ключа у user-service
+func (k *RemoteAPIKeys) fetch(ctx context.Context, key string) (*APIKeyInfo, error) {
+	body, err := json.Marshal(map[string]string{"key": key})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка проверки API-ключа: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusNotFound:
+		return nil, ErrInvalidAPIKey
+	default:
+		return nil, fmt.Errorf("ошибка проверки API-ключа: статус %d", resp.StatusCode)
+	}
+
+	var info APIKeyInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("ошибка декодирования сведений об API-ключе: %w", err)
+	}
+	return &info, nil
+}
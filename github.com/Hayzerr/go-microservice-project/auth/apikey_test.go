@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// testAPIKeys - источник API-ключей в памяти
+type testAPIKeys map[string]*APIKeyInfo
+
+func (k testAPIKeys) LookupAPIKey(_ context.Context, key string) (*APIKeyInfo, error) {
+	info, ok := k[key]
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	return info, nil
+}
+
+// failingAPIKeys - источник API-ключей, который недоступен
+type failingAPIKeys struct{}
+
+func (failingAPIKeys) LookupAPIKey(context.Context, string) (*APIKeyInfo, error) {
+	return nil, errors.New("user-service недоступен")
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+	keys := testAPIKeys{
+		"tk_valid":   {ID: "key-1", UserID: "user-1", Roles: []string{RoleCustomer}, Scopes: []string{ScopeOrdersRead}, ExpiresAt: &future},
+		"tk_expired": {ID: "key-2", UserID: "user-1", ExpiresAt: &expired},
+		"other_key":  {ID: "key-3", UserID: "user-1"},
+	}
+	validator := WithAPIKeys(NewValidator(testSecret), keys).(APIKeyValidator)
+
+	claims, err := validator.ValidateAPIKey("tk_valid")
+	if err != nil {
+		t.Fatalf("ValidateAPIKey: %v", err)
+	}
+	if claims.UserID != "user-1" || claims.APIKeyID != "key-1" || !claims.Scoped() {
+		t.Fatalf("неожиданное содержимое: %+v", claims)
+	}
+	if !claims.HasScope(ScopeOrdersRead) || claims.HasScope(ScopeOrdersWrite) {
+		t.Fatalf("разрешения ключа: %v", claims.Scopes)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"неизвестный ключ", "tk_unknown"},
+		{"истекший ключ", "tk_expired"},
+		{"без префикса", "other_key"},
+		{"пустой ключ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := validator.ValidateAPIKey(tt.key); !errors.Is(err, ErrInvalidAPIKey) {
+				t.Fatalf("ожидалась ErrInvalidAPIKey, получено %v", err)
+			}
+		})
+	}
+
+	unavailable := WithAPIKeys(NewValidator(testSecret), failingAPIKeys{}).(APIKeyValidator)
+	if _, err := unavailable.ValidateAPIKey("tk_valid"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("недоступный источник ключей: ожидалась ErrInvalidToken, получено %v", err)
+	}
+}
+
+func TestValidateAPIKeyRateLimit(t *testing.T) {
+	keys := testAPIKeys{"tk_limited": {ID: "key-1", UserID: "user-1", RateLimit: 2}}
+	validator := WithAPIKeys(NewValidator(testSecret), keys).(APIKeyValidator)
+
+	for i := 0; i < 2; i++ {
+		if _, err := validator.ValidateAPIKey("tk_limited"); err != nil {
+			t.Fatalf("запрос %d в пределах лимита: %v", i+1, err)
+		}
+	}
+
+	_, err := validator.ValidateAPIKey("tk_limited")
+	var limited *RateLimitError
+	if !errors.As(err, &limited) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("ожидалась RateLimitError, получено %v", err)
+	}
+	// Лимит 2 в минуту пополняется на один запрос за 30 с
+	if seconds := limited.RetryAfterSeconds(); seconds < 1 || seconds > 30 {
+		t.Fatalf("RetryAfterSeconds = %d, ожидалось от 1 до 30", seconds)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 60; i++ {
+		if ok, _ := limiter.allow("key-1", 60, now); !ok {
+			t.Fatalf("запрос %d отклонен в пределах лимита", i+1)
+		}
+	}
+	ok, retryAfter := limiter.allow("key-1", 60, now)
+	if ok || retryAfter != time.Second {
+		t.Fatalf("61-й запрос: allow = %v, retryAfter = %s, ожидалось false и 1s", ok, retryAfter)
+	}
+	if ok, _ := limiter.allow("key-2", 60, now); !ok {
+		t.Fatalf("лимит одного ключа повлиял на другой")
+	}
+	if ok, _ := limiter.allow("key-1", 60, now.Add(time.Second)); !ok {
+		t.Fatalf("запрос после пополнения корзины отклонен")
+	}
+	if ok, _ := limiter.allow("key-1", 60, now.Add(time.Second)); ok {
+		t.Fatalf("корзина пополнилась больше чем на один запрос за секунду")
+	}
+	if ok, _ := limiter.allow("key-1", 120, now.Add(time.Second)); !ok {
+		t.Fatalf("изменение лимита не начало отсчет заново")
+	}
+	if ok, _ := limiter.allow("key-3", 0, now); !ok {
+		t.Fatalf("нулевой лимит должен отключать ограничение")
+	}
+
+	if seconds := (&RateLimitError{RetryAfter: 1500 * time.Millisecond}).RetryAfterSeconds(); seconds != 2 {
+		t.Fatalf("RetryAfterSeconds(1.5s) = %d, ожидалось 2", seconds)
+	}
+	if seconds := (&RateLimitError{RetryAfter: time.Millisecond}).RetryAfterSeconds(); seconds != 1 {
+		t.Fatalf("RetryAfterSeconds(1ms) = %d, ожидалось 1", seconds)
+	}
+}
+
+func TestMiddlewareAPIKey(t *testing.T) {
+	keys := testAPIKeys{
+		"tk_valid":   {ID: "key-1", UserID: "user-1", Scopes: []string{ScopeOrdersRead}},
+		"tk_limited": {ID: "key-2", UserID: "user-2", RateLimit: 1},
+	}
+	validator := WithAPIKeys(NewValidator(testSecret), keys)
+
+	var seen *http.Request
+	handler := Middleware(validator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r
+	}))
+
+	for _, header := range []string{"ApiKey tk_valid", "apikey tk_valid", "ApiKey  tk_valid "} {
+		seen = nil
+		if rec := serve(handler, http.MethodGet, header); rec.Code != http.StatusOK {
+			t.Fatalf("%q: код %d, ожидался 200", header, rec.Code)
+		}
+		ctx := seen.Context()
+		if userID, _ := UserIDFromContext(ctx); userID != "user-1" {
+			t.Fatalf("%q: пользователь %q", header, userID)
+		}
+		if claims, _ := ClaimsFromContext(ctx); claims.APIKeyID != "key-1" {
+			t.Fatalf("%q: APIKeyID = %q", header, claims.APIKeyID)
+		}
+		if authorization, _ := AuthorizationFromContext(ctx); authorization != "ApiKey tk_valid" {
+			t.Fatalf("%q: AuthorizationFromContext = %q", header, authorization)
+		}
+	}
+
+	// Токены по-прежнему принимаются
+	if rec := serve(handler, http.MethodGet, bearer(t, testClaims("user-1"))); rec.Code != http.StatusOK {
+		t.Fatalf("Bearer-токен: код %d", rec.Code)
+	}
+
+	if rec := serve(handler, http.MethodGet, "ApiKey tk_unknown"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("неизвестный ключ: код %d, ожидался 401", rec.Code)
+	}
+
+	if rec := serve(handler, http.MethodGet, "ApiKey tk_limited"); rec.Code != http.StatusOK {
+		t.Fatalf("первый запрос в пределах лимита: код %d", rec.Code)
+	}
+	rec := serve(handler, http.MethodGet, "ApiKey tk_limited")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("запрос сверх лимита: код %d, ожидался 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("в ответе 429 нет Retry-After")
+	}
+	if challenges := rec.Header().Values("WWW-Authenticate"); len(challenges) != 0 {
+		t.Fatalf("в ответе 429 не должно быть WWW-Authenticate: %v", challenges)
+	}
+}
+
+func TestWriteAuthError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeAuthError(rec, &RateLimitError{RetryAfter: 2500 * time.Millisecond})
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "3" {
+		t.Fatalf("RateLimitError: код %d, Retry-After %q, ожидались 429 и 3", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	rec = httptest.NewRecorder()
+	writeAuthError(rec, ErrInvalidAPIKey)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("Retry-After") != "" {
+		t.Fatalf("ErrInvalidAPIKey: код %d, ожидался 401 без Retry-After", rec.Code)
+	}
+	challenges := rec.Header().Values("WWW-Authenticate")
+	if len(challenges) != 2 || challenges[0] != `Bearer realm="api"` || challenges[1] != `ApiKey realm="api"` {
+		t.Fatalf("WWW-Authenticate: %v", challenges)
+	}
+}
+
+func TestUnaryServerInterceptorAPIKey(t *testing.T) {
+	keys := testAPIKeys{"tk_limited": {ID: "key-1", UserID: "user-1", RateLimit: 1}}
+	interceptor := UnaryServerInterceptor(WithAPIKeys(NewValidator(testSecret), keys))
+
+	if code, resp := callUnary(incoming("ApiKey tk_limited"), interceptor, testOtherMethod); code != codes.OK || resp != "user-1" {
+		t.Fatalf("первый запрос: код %s, ответ %v", code, resp)
+	}
+	if code, _ := callUnary(incoming("ApiKey tk_limited"), interceptor, testOtherMethod); code != codes.ResourceExhausted {
+		t.Fatalf("запрос сверх лимита: код %s, ожидался ResourceExhausted", code)
+	}
+	if code, _ := callUnary(incoming("ApiKey tk_unknown"), interceptor, testOtherMethod); code != codes.Unauthenticated {
+		t.Fatalf("неизвестный ключ: код %s, ожидался Unauthenticated", code)
+	}
+
+	// Валидатор без WithAPIKeys не принимает API-ключи
+	plain := UnaryServerInterceptor(NewValidator(testSecret))
+	if code, _ := callUnary(incoming("ApiKey tk_limited"), plain, testOtherMethod); code != codes.Unauthenticated {
+		t.Fatalf("API-ключ без WithAPIKeys: код %s, ожидался Unauthenticated", code)
+	}
+}
+
+func TestRemoteAPIKeysCache(t *testing.T) {
+	var calls atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			http.Error(w, "недоступен", http.StatusServiceUnavailable)
+			return
+		}
+		var body struct {
+			Key string `json:"key"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Key != "tk_valid" {
+			http.Error(w, "ключ не найден", http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(APIKeyInfo{ID: "key-1", UserID: "user-1"})
+	}))
+	defer server.Close()
+
+	keys := NewRemoteAPIKeys(server.URL, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		info, err := keys.LookupAPIKey(ctx, "tk_valid")
+		if err != nil || info.ID != "key-1" {
+			t.Fatalf("LookupAPIKey: %+v, %v", info, err)
+		}
+		if _, err := keys.LookupAPIKey(ctx, "tk_unknown"); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Fatalf("неизвестный ключ: ожидалась ErrInvalidAPIKey, получено %v", err)
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("обращений к user-service %d, ожидалось 2: ответы должны кешироваться", n)
+	}
+
+	// Ошибка соединения не кешируется
+	failing.Store(true)
+	if _, err := keys.LookupAPIKey(ctx, "tk_other"); err == nil || errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("недоступный user-service: ожидалась ошибка проверки, получено %v", err)
+	}
+	failing.Store(false)
+	if _, err := keys.LookupAPIKey(ctx, "tk_other"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("после восстановления: ожидалась ErrInvalidAPIKey, получено %v", err)
+	}
+	if n := calls.Load(); n != 4 {
+		t.Fatalf("обращений к user-service %d, ожидалось 4", n)
+	}
+}
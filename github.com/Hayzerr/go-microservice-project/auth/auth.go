@@ -0,0 +1,179 @@
+// Package auth содержит общую для всех сервисов проверку JWT-токенов и API-ключей, выданных user-service,
+// а также middleware для net/http и gorilla/mux и перехватчики для gRPC,
+// которые кладут ID, роли и разрешения аутентифицированного пользователя в контекст запроса.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrMissingToken = errors.New("токен авторизации не передан")
+	ErrInvalidToken = errors.New("недействительный токен")
+	ErrTokenExpired = errors.New("срок действия токена истек")
+	ErrTokenRevoked = errors.New("токен отозван")
+)
+
+// Claims описывает содержимое токена, выдаваемого user-service.
+// Для запроса с API-ключом содержимое собирается из сведений о ключе (см. APIKeyInfo).
+type Claims struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	// ClientID - клиент OAuth2, которому выдан токен; пуст для токенов входа пользователя.
+	// Для токена клиента без пользователя (client credentials) совпадает с UserID.
+	ClientID string `json:"client_id,omitempty"`
+	// APIKeyID - ID API-ключа, которым аутентифицирован запрос; пуст для токенов
+	APIKeyID string `json:"-"`
+	jwt.RegisteredClaims
+}
+
+// Scoped сообщает, ограничены ли права вызывающего разрешениями Scopes: так выдаются API-ключи
+// и токены клиентов OAuth2. Токены входа пользователя не ограничены: им доступно все, что позволяют роли.
+func (c *Claims) Scoped() bool {
+	return c.APIKeyID != "" || c.ClientID != ""
+}
+
+// TokenValidator проверяет токен и возвращает его содержимое
+type TokenValidator interface {
+	ValidateToken(token string) (*Claims, error)
+}
+
+// Validator проверяет токены, подписанные общим секретом по алгоритму HS256.
+// Для токенов, подписанных асимметричными ключами, используется KeyValidator.
+type Validator struct {
+	secretKey []byte
+}
+
+// NewValidator создает новый экземпляр Validator
+func NewValidator(secretKey string) *Validator {
+	return &Validator{secretKey: []byte(secretKey)}
+}
+
+// ValidateToken проверяет подпись и срок действия токена и возвращает его содержимое
+func (v *Validator) ValidateToken(token string) (*Claims, error) {
+	return parseClaims(token, func(t *jwt.Token) (interface{}, error) {
+		return v.secretKey, nil
+	}, jwt.SigningMethodHS256.Alg())
+}
+
+// parseClaims разбирает токен, проверяет подпись ключом из keyFunc, алгоритм и срок действия
+func parseClaims(token string, keyFunc jwt.Keyfunc, algorithms ...string) (*Claims, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, keyFunc, jwt.WithValidMethods(algorithms), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if claims.UserID == "" {
+		return nil, fmt.Errorf("%w: не указан ID пользователя", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// ExtractBearerToken извлекает токен из значения заголовка "Authorization: Bearer <token>"
+func ExtractBearerToken(header string) (string, error) {
+	if header == "" {
+		return "", ErrMissingToken
+	}
+
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", fmt.Errorf("%w: ожидается заголовок вида \"Bearer <token>\"", ErrInvalidToken)
+	}
+
+	return strings.TrimSpace(token), nil
+}
+
+// authenticateHeader проверяет значение заголовка Authorization и возвращает контекст с данными вызывающего.
+// Принимаются токены "Bearer <token>" и, если валидатор реализует APIKeyValidator, ключи "ApiKey <key>".
+func authenticateHeader(ctx context.Context, v TokenValidator, header string) (context.Context, error) {
+	if scheme, key, found := strings.Cut(header, " "); found && strings.EqualFold(scheme, SchemeAPIKey) {
+		keys, ok := v.(APIKeyValidator)
+		if !ok {
+			return nil, fmt.Errorf("%w: API-ключи не принимаются", ErrInvalidToken)
+		}
+		key = strings.TrimSpace(key)
+		claims, err := keys.ValidateAPIKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return context.WithValue(ContextWithClaims(ctx, claims, ""), apiKeyKey, key), nil
+	}
+
+	token, err := ExtractBearerToken(header)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := v.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return ContextWithClaims(ctx, claims, token), nil
+}
+
+type contextKey int
+
+const (
+	userIDKey contextKey = iota
+	tokenKey
+	rolesKey
+	claimsKey
+	apiKeyKey
+)
+
+// ContextWithUser возвращает контекст с ID аутентифицированного пользователя и его исходным токеном.
+// Токен сохраняется, чтобы сервис мог передать его дальше при обращении к другим сервисам.
+func ContextWithUser(ctx context.Context, userID, token string) context.Context {
+	ctx = context.WithValue(ctx, userIDKey, userID)
+	return context.WithValue(ctx, tokenKey, token)
+}
+
+// UserIDFromContext возвращает ID аутентифицированного пользователя из контекста
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey).(string)
+	return userID, ok && userID != ""
+}
+
+// TokenFromContext возвращает исходный токен аутентифицированного пользователя из контекста
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenKey).(string)
+	return token, ok && token != ""
+}
+
+// AuthorizationFromContext возвращает значение заголовка Authorization, с которым аутентифицирован запрос:
+// "Bearer <token>" или "ApiKey <key>". Нужно, чтобы передать учетные данные вызывающего другим сервисам.
+func AuthorizationFromContext(ctx context.Context) (string, bool) {
+	if key, ok := ctx.Value(apiKeyKey).(string); ok && key != "" {
+		return SchemeAPIKey + " " + key, true
+	}
+	if token, ok := TokenFromContext(ctx); ok {
+		return "Bearer " + token, true
+	}
+	return "", false
+}
+
+// ContextWithClaims возвращает контекст с ID, ролями и полным содержимым проверенного токена
+func ContextWithClaims(ctx context.Context, claims *Claims, token string) context.Context {
+	ctx = ContextWithRoles(ContextWithUser(ctx, claims.UserID, token), claims.Roles)
+	return context.WithValue(ctx, claimsKey, claims)
+}
+
+// ClaimsFromContext возвращает содержимое проверенного токена (например, jti и срок действия для отзыва)
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*Claims)
+	return claims, ok && claims != nil
+}
@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+// staticKeys - KeySource с фиксированным набором открытых ключей
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) PublicKey(kid string) (crypto.PublicKey, error) {
+	public, ok := k[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+	return public, nil
+}
+
+// testClaims возвращает содержимое токена пользователя userID, действующего еще час
+func testClaims(userID string, roles ...string) *Claims {
+	return &Claims{
+		UserID:           userID,
+		Roles:            roles,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+	}
+}
+
+// signHS256 подписывает claims общим секретом testSecret
+func signHS256(t *testing.T, claims jwt.Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return token
+}
+
+// signWithKey подписывает claims ключом key и указывает его kid в заголовке
+func signWithKey(t *testing.T, key *SigningKey, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.PrivateKey)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+// mustSigningKey создает ключ подписи для алгоритма algorithm
+func mustSigningKey(t *testing.T, algorithm string) *SigningKey {
+	t.Helper()
+	key, err := GenerateSigningKey(algorithm)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey(%s): %v", algorithm, err)
+	}
+	return key
+}
+
+func TestValidatorAcceptsValidToken(t *testing.T) {
+	claims := testClaims("user-1", RoleStaff)
+	claims.Scopes = []string{ScopeOrdersRead}
+
+	got, err := NewValidator(testSecret).ValidateToken(signHS256(t, claims))
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if got.UserID != "user-1" || !got.HasRole(RoleStaff) || len(got.Scopes) != 1 {
+		t.Fatalf("некорректное содержимое токена: %+v", got)
+	}
+}
+
+func TestValidatorRejectsInvalidTokens(t *testing.T) {
+	rsaKey := mustSigningKey(t, AlgRS256)
+
+	expired := testClaims("user-1")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	noExpiry := testClaims("user-1")
+	noExpiry.ExpiresAt = nil
+	noUser := testClaims("")
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims("user-1")).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("SignedString(none): %v", err)
+	}
+	hs512, err := jwt.NewWithClaims(jwt.SigningMethodHS512, testClaims("user-1")).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("SignedString(HS512): %v", err)
+	}
+	otherSecret, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims("user-1")).SignedString([]byte("other-secret"))
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"пустой токен", "", ErrMissingToken},
+		{"не JWT", "not-a-token", ErrInvalidToken},
+		{"alg none", none, ErrInvalidToken},
+		{"HS512 вместо HS256", hs512, ErrInvalidToken},
+		{"RS256 вместо HS256", signWithKey(t, rsaKey, testClaims("user-1")), ErrInvalidToken},
+		{"чужой секрет", otherSecret, ErrInvalidToken},
+		{"истекший токен", signHS256(t, expired), ErrTokenExpired},
+		{"без exp", signHS256(t, noExpiry), ErrInvalidToken},
+		{"пустой user_id", signHS256(t, noUser), ErrInvalidToken},
+	}
+
+	validator := NewValidator(testSecret)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := validator.ValidateToken(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ожидалась ошибка %v, получено %v (claims %+v)", tt.want, err, claims)
+			}
+		})
+	}
+}
+
+func TestKeyValidator(t *testing.T) {
+	rsaKey := mustSigningKey(t, AlgRS256)
+	edKey := mustSigningKey(t, AlgEdDSA)
+	validator := NewKeyValidator(staticKeys{rsaKey.ID: rsaKey.PublicKey(), edKey.ID: edKey.PublicKey()})
+
+	for _, key := range []*SigningKey{rsaKey, edKey} {
+		t.Run("принимает "+key.Algorithm, func(t *testing.T) {
+			claims, err := validator.ValidateToken(signWithKey(t, key, testClaims("user-1", RoleAdmin)))
+			if err != nil {
+				t.Fatalf("ValidateToken: %v", err)
+			}
+			if claims.UserID != "user-1" || !claims.HasRole(RoleAdmin) {
+				t.Fatalf("некорректное содержимое токена: %+v", claims)
+			}
+		})
+	}
+
+	// HS256 с открытым ключом RSA в качестве секрета: классическая подмена алгоритма
+	der, err := x509.MarshalPKIXPublicKey(rsaKey.PublicKey())
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	confused := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims("user-1", RoleAdmin))
+	confused.Header["kid"] = rsaKey.ID
+	confusedToken, err := confused.SignedString(publicPEM)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	// EdDSA-подпись с kid ключа RSA
+	mismatched := jwt.NewWithClaims(jwt.SigningMethodEdDSA, testClaims("user-1"))
+	mismatched.Header["kid"] = rsaKey.ID
+	mismatchedToken, err := mismatched.SignedString(edKey.PrivateKey)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	noKid := jwt.NewWithClaims(rsaKey.Method(), testClaims("user-1"))
+	noKidToken, err := noKid.SignedString(rsaKey.PrivateKey)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims("user-1")).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("SignedString(none): %v", err)
+	}
+
+	expired := testClaims("user-1")
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Second))
+	noExpiry := testClaims("user-1")
+	noExpiry.ExpiresAt = nil
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"HS256 с открытым ключом RSA", confusedToken, ErrInvalidToken},
+		{"HS256 общим секретом", signHS256(t, testClaims("user-1")), ErrInvalidToken},
+		{"алгоритм не подходит к ключу", mismatchedToken, ErrInvalidToken},
+		{"без kid", noKidToken, ErrInvalidToken},
+		{"неизвестный ключ", signWithKey(t, mustSigningKey(t, AlgEdDSA), testClaims("user-1")), ErrInvalidToken},
+		{"alg none", none, ErrInvalidToken},
+		{"истекший токен", signWithKey(t, rsaKey, expired), ErrTokenExpired},
+		{"без exp", signWithKey(t, edKey, noExpiry), ErrInvalidToken},
+		{"пустой user_id", signWithKey(t, edKey, testClaims("")), ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := validator.ValidateToken(tt.token)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ожидалась ошибка %v, получено %v (claims %+v)", tt.want, err, claims)
+			}
+		})
+	}
+}
+
+func TestExtractBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		token  string
+		err    error
+	}{
+		{"Bearer abc", "abc", nil},
+		{"bearer abc", "abc", nil},
+		{"BEARER  abc ", "abc", nil},
+		{"", "", ErrMissingToken},
+		{"Bearer", "", ErrInvalidToken},
+		{"Bearer ", "", ErrInvalidToken},
+		{"Bearer    ", "", ErrInvalidToken},
+		{"abc", "", ErrInvalidToken},
+		{"Basic dXNlcjpwYXNz", "", ErrInvalidToken},
+		{"Token abc", "", ErrInvalidToken},
+		{"Bearerabc", "", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.header), func(t *testing.T) {
+			token, err := ExtractBearerToken(tt.header)
+			if !errors.Is(err, tt.err) || token != tt.token {
+				t.Fatalf("ExtractBearerToken = (%q, %v), ожидалось (%q, %v)", token, err, tt.token, tt.err)
+			}
+		})
+	}
+}
+
+func TestClaimsScopes(t *testing.T) {
+	login := testClaims("user-1", RoleCustomer)
+	oauth := &Claims{UserID: "user-1", ClientID: "mobile-app", Scopes: []string{ScopeOrdersRead}}
+	service := &Claims{UserID: "order-service", ClientID: "order-service", Scopes: []string{ScopeInventoryWrite}}
+	apiKey := &Claims{UserID: "user-1", APIKeyID: "key-1", Roles: []string{RoleStaff}, Scopes: []string{ScopeInventoryWrite}}
+
+	tests := []struct {
+		name    string
+		claims  *Claims
+		scoped  bool
+		orders  bool // HasScope(orders:read)
+		service bool // ServiceClient()
+		allows  bool // AllowsService(inventory:write, STAFF)
+	}{
+		{"токен входа", login, false, true, false, false},
+		{"токен OAuth2 пользователя", oauth, true, true, false, false},
+		{"сервисный клиент", service, true, false, true, true},
+		{"API-ключ сотрудника", apiKey, true, false, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.claims.Scoped(); got != tt.scoped {
+				t.Fatalf("Scoped() = %v, ожидалось %v", got, tt.scoped)
+			}
+			if got := tt.claims.HasScope(ScopeOrdersRead); got != tt.orders {
+				t.Fatalf("HasScope(orders:read) = %v, ожидалось %v", got, tt.orders)
+			}
+			if got := tt.claims.ServiceClient(); got != tt.service {
+				t.Fatalf("ServiceClient() = %v, ожидалось %v", got, tt.service)
+			}
+			if got := tt.claims.AllowsService(ScopeInventoryWrite, RoleStaff); got != tt.allows {
+				t.Fatalf("AllowsService() = %v, ожидалось %v", got, tt.allows)
+			}
+		})
+	}
+}
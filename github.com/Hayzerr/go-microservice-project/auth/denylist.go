@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Denylist хранит токены доступа, отозванные до истечения срока действия (по claim jti).
+// user-service хранит список в PostgreSQL, остальные сервисы получают его через RemoteDenylist.
+type Denylist interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// RevokedToken описывает отозванный токен доступа. Запись нужна только до истечения срока токена.
+type RevokedToken struct {
+	JTI       string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// denylistValidator дополняет проверку токена проверкой по списку отозванных
+type denylistValidator struct {
+	next     TokenValidator
+	denylist Denylist
+}
+
+// WithDenylist возвращает валидатор, который дополнительно отклоняет токены из denylist.
+// Если список недоступен, токен отклоняется: отозванный токен не должен пройти из-за сбоя хранилища.
+func WithDenylist(v TokenValidator, denylist Denylist) TokenValidator {
+	return &denylistValidator{next: v, denylist: denylist}
+}
+
+// ValidateToken проверяет токен и убеждается, что он не отозван
+func (v *denylistValidator) ValidateToken(token string) (*Claims, error) {
+	claims, err := v.next.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.ID == "" {
+		return claims, nil
+	}
+
+	revoked, err := v.denylist.IsRevoked(context.Background(), claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: не удалось проверить отзыв токена: %v", ErrInvalidToken, err)
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// MemoryDenylist хранит отозванные токены в памяти процесса
+type MemoryDenylist struct {
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+}
+
+// NewMemoryDenylist создает пустой MemoryDenylist
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{tokens: make(map[string]time.Time)}
+}
+
+// Add добавляет токен в список до момента expiresAt
+func (d *MemoryDenylist) Add(jti string, expiresAt time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tokens[jti] = expiresAt
+}
+
+// Replace заменяет содержимое списка
+func (d *MemoryDenylist) Replace(tokens []RevokedToken) {
+	updated := make(map[string]time.Time, len(tokens))
+	for _, token := range tokens {
+		updated[token.JTI] = token.ExpiresAt
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tokens = updated
+}
+
+// IsRevoked сообщает, отозван ли токен. Записи с истекшим сроком не учитываются:
+// такой токен и так будет отклонен при проверке срока действия.
+func (d *MemoryDenylist) IsRevoked(_ context.Context, jti string) (bool, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	expiresAt, ok := d.tokens[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// RemoteDenylist периодически загружает список отозванных токенов из user-service
+// (GET /api/auth/revoked) и проверяет токены по локальной копии.
+// Отзыв вступает в силу в других сервисах с задержкой не больше интервала обновления.
+type RemoteDenylist struct {
+	url        string
+	interval   time.Duration
+	httpClient *http.Client
+	cache      *MemoryDenylist
+}
+
+// NewRemoteDenylist создает RemoteDenylist, загружающий список с url каждые interval
+func NewRemoteDenylist(url string, interval time.Duration) *RemoteDenylist {
+	return &RemoteDenylist{
+		url:        url,
+		interval:   interval,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		cache:      NewMemoryDenylist(),
+	}
+}
+
+// IsRevoked проверяет токен по последней загруженной копии списка
+func (d *RemoteDenylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return d.cache.IsRevoked(ctx, jti)
+}
+
+// Refresh загружает актуальный список отозванных токенов
+func (d *RemoteDenylist) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки списка отозванных токенов: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ошибка загрузки списка отозванных токенов: статус %d", resp.StatusCode)
+	}
+
+	var tokens []RevokedToken
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return fmt.Errorf("ошибка декодирования списка отозванных токенов: %w", err)
+	}
+
+	d.cache.Replace(tokens)
+	return nil
+}
+
+// Run обновляет список сразу и затем каждые interval, пока не закрыт stop.
+// При ошибке загрузки сохраняется предыдущая копия списка.
+func (d *RemoteDenylist) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), d.interval)
+		if err := d.Refresh(ctx); err != nil {
+			log.Printf("Не удалось обновить список отозванных токенов: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ReflectionMethods - методы gRPC reflection. Их стоит оставлять открытыми,
+// чтобы grpcurl и подобные инструменты могли получить описание сервиса без токена.
+var ReflectionMethods = []string{
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo",
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+}
+
+// authenticateGRPC проверяет метаданные authorization входящего вызова (токен или API-ключ)
+func authenticateGRPC(ctx context.Context, v TokenValidator) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	authenticated, err := authenticateHeader(ctx, v, header)
+	if err != nil {
+		if errors.Is(err, ErrRateLimited) {
+			return nil, status.Errorf(codes.ResourceExhausted, "%v", err)
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "Требуется авторизация: %v", err)
+	}
+	return authenticated, nil
+}
+
+// publicSet строит множество полных имен методов, не требующих авторизации
+func publicSet(publicMethods []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(publicMethods))
+	for _, method := range publicMethods {
+		set[method] = struct{}{}
+	}
+	return set
+}
+
+// UnaryServerInterceptor проверяет токен у каждого унарного вызова, кроме publicMethods
+// (полные имена вида "/pb.UserService/CreateUser"), и кладет ID и роли пользователя в контекст.
+func UnaryServerInterceptor(v TokenValidator, publicMethods ...string) grpc.UnaryServerInterceptor {
+	public := publicSet(publicMethods)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := public[info.FullMethod]; ok {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticateGRPC(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor проверяет токен у каждого потокового вызова, кроме publicMethods
+func StreamServerInterceptor(v TokenValidator, publicMethods ...string) grpc.StreamServerInterceptor {
+	public := publicSet(publicMethods)
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := public[info.FullMethod]; ok {
+			return handler(srv, stream)
+		}
+
+		ctx, err := authenticateGRPC(stream.Context(), v)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// RolePolicy сопоставляет полное имя метода gRPC с ролями, которым он доступен.
+// Методы, отсутствующие в политике, доступны любому аутентифицированному пользователю.
+type RolePolicy map[string][]string
+
+// authorize проверяет, что у пользователя из контекста есть роль, требуемая для метода
+func (p RolePolicy) authorize(ctx context.Context, method string) error {
+	roles, ok := p[method]
+	if !ok || HasRole(ctx, roles...) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "%v: требуется роль %s", ErrForbidden, strings.Join(roles, " или "))
+}
+
+// UnaryRoleInterceptor проверяет роли по политике policy.
+// Подключается после UnaryServerInterceptor (grpc.ChainUnaryInterceptor), который кладет роли в контекст.
+func UnaryRoleInterceptor(policy RolePolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := policy.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRoleInterceptor проверяет роли потоковых вызовов по политике policy
+func StreamRoleInterceptor(policy RolePolicy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := policy.authorize(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// AuthorizeService возвращает PERMISSION_DENIED, если вызывающему недоступна служебная операция:
+// нужен сервисный клиент OAuth2 с разрешением scope или пользователь с одной из ролей roles (см. AllowsService).
+// Вызывается из обработчика: RolePolicy не пропустила бы сервисного клиента, у которого нет ролей.
+func AuthorizeService(ctx context.Context, scope string, roles ...string) error {
+	if AllowsService(ctx, scope, roles...) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "%v: требуется сервисный клиент с разрешением %s или роль %s",
+		ErrForbidden, scope, strings.Join(roles, " или "))
+}
+
+// ScopePolicy сопоставляет полное имя метода gRPC с разрешениями API-ключа или токена клиента OAuth2,
+// которым он доступен. В отличие от RolePolicy, метод без записи в политике недоступен с такими учетными данными;
+// вызовы с токеном входа пользователя политикой не ограничиваются.
+type ScopePolicy map[string][]string
+
+// authorize проверяет, что у API-ключа или токена OAuth2 из контекста есть разрешение, требуемое для метода
+func (p ScopePolicy) authorize(ctx context.Context, method string) error {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || !claims.Scoped() {
+		return nil
+	}
+	scopes, ok := p[method]
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "%v: метод недоступен с API-ключом или токеном OAuth2", ErrForbidden)
+	}
+	if !claims.HasScope(scopes...) {
+		return status.Errorf(codes.PermissionDenied, "%v: требуется разрешение %s", ErrForbidden, strings.Join(scopes, " или "))
+	}
+	return nil
+}
+
+// UnaryScopeInterceptor проверяет разрешения API-ключей и токенов OAuth2 по политике policy.
+// Подключается после UnaryServerInterceptor.
+func UnaryScopeInterceptor(policy ScopePolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := policy.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamScopeInterceptor проверяет разрешения API-ключей и токенов OAuth2 для потоковых вызовов по политике policy
+func StreamScopeInterceptor(policy ScopePolicy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := policy.authorize(stream.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
+
+// authenticatedStream подменяет контекст потока контекстом с ID и ролями пользователя
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст с ID аутентифицированного пользователя
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testPublicMethod = "/pb.TestService/Public"
+	testStaffMethod  = "/pb.TestService/Staff"
+	testOrdersMethod = "/pb.TestService/Orders"
+	testOtherMethod  = "/pb.TestService/Other"
+)
+
+// incoming возвращает контекст входящего вызова с метаданными authorization (если не пусто)
+func incoming(authorization string) context.Context {
+	if authorization == "" {
+		return context.Background()
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+}
+
+// authenticated возвращает контекст, который кладет в запрос UnaryServerInterceptor для токена claims
+func authenticated(claims *Claims) context.Context {
+	return ContextWithClaims(context.Background(), claims, "token")
+}
+
+// userIDHandler возвращает ID пользователя из контекста
+func userIDHandler(ctx context.Context, _ interface{}) (interface{}, error) {
+	userID, _ := UserIDFromContext(ctx)
+	return userID, nil
+}
+
+// callUnary вызывает перехватчик для метода method и возвращает код ответа и результат обработчика
+func callUnary(ctx context.Context, interceptor grpc.UnaryServerInterceptor, method string) (codes.Code, interface{}) {
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, userIDHandler)
+	return status.Code(err), resp
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(NewValidator(testSecret), testPublicMethod)
+
+	if code, resp := callUnary(incoming(bearer(t, testClaims("user-1"))), interceptor, testOtherMethod); code != codes.OK || resp != "user-1" {
+		t.Fatalf("действительный токен: код %s, ответ %v", code, resp)
+	}
+	if code, _ := callUnary(incoming(""), interceptor, testPublicMethod); code != codes.OK {
+		t.Fatalf("публичный метод без токена: код %s", code)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"без метаданных", ""},
+		{"без схемы", signHS256(t, testClaims("user-1"))},
+		{"испорченный токен", "Bearer abc.def.ghi"},
+		{"пустой user_id", bearer(t, testClaims(""))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := callUnary(incoming(tt.authorization), interceptor, testOtherMethod); code != codes.Unauthenticated {
+				t.Fatalf("код %s, ожидался Unauthenticated", code)
+			}
+		})
+	}
+}
+
+// testServerStream - поток с заданным контекстом
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor(NewValidator(testSecret), testPublicMethod)
+
+	var userID string
+	handler := func(_ interface{}, stream grpc.ServerStream) error {
+		userID, _ = UserIDFromContext(stream.Context())
+		return nil
+	}
+	call := func(authorization, method string) codes.Code {
+		stream := &testServerStream{ctx: incoming(authorization)}
+		return status.Code(interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: method}, handler))
+	}
+
+	if code := call(bearer(t, testClaims("user-1")), testOtherMethod); code != codes.OK || userID != "user-1" {
+		t.Fatalf("действительный токен: код %s, пользователь %q", code, userID)
+	}
+	if code := call("", testOtherMethod); code != codes.Unauthenticated {
+		t.Fatalf("без токена: код %s, ожидался Unauthenticated", code)
+	}
+	if code := call("", testPublicMethod); code != codes.OK {
+		t.Fatalf("публичный метод без токена: код %s", code)
+	}
+}
+
+func TestRoleInterceptors(t *testing.T) {
+	policy := RolePolicy{testStaffMethod: {RoleStaff, RoleAdmin}}
+	unary := UnaryRoleInterceptor(policy)
+	stream := StreamRoleInterceptor(policy)
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		method string
+		want   codes.Code
+	}{
+		{"STAFF вызывает метод STAFF", testClaims("user-1", RoleStaff), testStaffMethod, codes.OK},
+		{"ADMIN вызывает метод STAFF", testClaims("user-1", RoleAdmin), testStaffMethod, codes.OK},
+		{"CUSTOMER вызывает метод STAFF", testClaims("user-1", RoleCustomer), testStaffMethod, codes.PermissionDenied},
+		{"без ролей", testClaims("user-1"), testStaffMethod, codes.PermissionDenied},
+		{"метод вне политики", testClaims("user-1", RoleCustomer), testOtherMethod, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := authenticated(tt.claims)
+			if code, _ := callUnary(ctx, unary, tt.method); code != tt.want {
+				t.Fatalf("unary: код %s, ожидался %s", code, tt.want)
+			}
+			err := stream(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(interface{}, grpc.ServerStream) error { return nil })
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("stream: код %s, ожидался %s", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeInterceptors(t *testing.T) {
+	policy := ScopePolicy{testOrdersMethod: {ScopeOrdersRead}}
+	unary := UnaryScopeInterceptor(policy)
+	stream := StreamScopeInterceptor(policy)
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		method string
+		want   codes.Code
+	}{
+		{"токен входа вызывает метод вне политики", testClaims("user-1"), testOtherMethod, codes.OK},
+		{"токен входа вызывает метод политики", testClaims("user-1"), testOrdersMethod, codes.OK},
+		{"OAuth2 с разрешением", oauthClaims("user-1", "app", ScopeOrdersRead), testOrdersMethod, codes.OK},
+		{"OAuth2 без разрешения", oauthClaims("user-1", "app", ScopeUsersRead), testOrdersMethod, codes.PermissionDenied},
+		{"OAuth2 вызывает метод вне политики", oauthClaims("user-1", "app", ScopeOrdersRead), testOtherMethod, codes.PermissionDenied},
+		{"API-ключ с разрешением", &Claims{UserID: "user-1", APIKeyID: "key-1", Scopes: []string{ScopeOrdersRead}}, testOrdersMethod, codes.OK},
+		{"API-ключ вызывает метод вне политики", &Claims{UserID: "user-1", APIKeyID: "key-1", Scopes: []string{ScopeOrdersRead}}, testOtherMethod, codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := authenticated(tt.claims)
+			if code, _ := callUnary(ctx, unary, tt.method); code != tt.want {
+				t.Fatalf("unary: код %s, ожидался %s", code, tt.want)
+			}
+			err := stream(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(interface{}, grpc.ServerStream) error { return nil })
+			if code := status.Code(err); code != tt.want {
+				t.Fatalf("stream: код %s, ожидался %s", code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeService(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"сервисный клиент", authenticated(oauthClaims("order-service", "order-service", ScopeInventoryWrite)), codes.OK},
+		{"STAFF", authenticated(testClaims("user-1", RoleStaff)), codes.OK},
+		{"CUSTOMER", authenticated(testClaims("user-1", RoleCustomer)), codes.PermissionDenied},
+		{"без аутентификации", context.Background(), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := status.Code(AuthorizeService(tt.ctx, ScopeInventoryWrite, RoleStaff, RoleAdmin)); code != tt.want {
+				t.Fatalf("код %s, ожидался %s", code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Authenticate проверяет заголовок Authorization запроса (токен или API-ключ) и возвращает запрос
+// с контекстом, содержащим ID аутентифицированного пользователя
+func Authenticate(v TokenValidator, r *http.Request) (*http.Request, error) {
+	ctx, err := authenticateHeader(r.Context(), v, r.Header.Get("Authorization"))
+	if err != nil {
+		return nil, err
+	}
+	return r.WithContext(ctx), nil
+}
+
+// Middleware возвращает net/http middleware, которое пропускает только запросы с действительным токеном.
+// ID пользователя доступен обработчику через UserIDFromContext.
+func Middleware(v TokenValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticated, err := Authenticate(v, r)
+			if err != nil {
+				writeAuthError(w, err)
+				return
+			}
+			next.ServeHTTP(w, authenticated)
+		})
+	}
+}
+
+// RequireAuth оборачивает отдельный обработчик проверкой токена.
+// Удобно для http.ServeMux, где middleware подключается к каждому маршруту отдельно.
+func RequireAuth(v TokenValidator, next http.HandlerFunc) http.HandlerFunc {
+	return Middleware(v)(next).ServeHTTP
+}
+
+// MuxMiddleware возвращает middleware для gorilla/mux (router.Use или subrouter.Use)
+func MuxMiddleware(v TokenValidator) mux.MiddlewareFunc {
+	return Middleware(v)
+}
+
+// RequireRole возвращает middleware, которое пропускает только пользователей хотя бы с одной из ролей roles.
+// Подключается после Middleware/MuxMiddleware, которое кладет роли из токена в контекст.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasRole(r.Context(), roles...) {
+				writeForbidden(w, roles)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAuthRole оборачивает отдельный обработчик проверкой токена и роли (для http.ServeMux)
+func RequireAuthRole(v TokenValidator, next http.HandlerFunc, roles ...string) http.HandlerFunc {
+	return RequireAuth(v, RequireRole(roles...)(next).ServeHTTP)
+}
+
+// RequireScope возвращает middleware, которое пропускает запросы с API-ключом или токеном клиента OAuth2,
+// только если у них есть хотя бы одно из разрешений scopes. Запросы с токеном входа пользователя проходят без проверки.
+// Подключается после Middleware/MuxMiddleware.
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !HasScope(r.Context(), scopes...) {
+				http.Error(w, ErrForbidden.Error()+": требуется разрешение "+strings.Join(scopes, " или "), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireMethodScope проверяет разрешение по методу запроса: GET и HEAD требуют readScope,
+// остальные методы - writeScope (например, orders:read и orders:write)
+func RequireMethodScope(readScope, writeScope string) func(http.Handler) http.Handler {
+	read, write := RequireScope(readScope), RequireScope(writeScope)
+	return func(next http.Handler) http.Handler {
+		readNext, writeNext := read(next), write(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				readNext.ServeHTTP(w, r)
+				return
+			}
+			writeNext.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireService возвращает middleware для служебных операций: пропускает сервисных клиентов OAuth2
+// с разрешением scope и пользователей с одной из ролей roles (см. AllowsService). Подключается после Middleware/MuxMiddleware.
+func RequireService(scope string, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !AllowsService(r.Context(), scope, roles...) {
+				http.Error(w, ErrForbidden.Error()+": требуется сервисный клиент с разрешением "+scope+
+					" или роль "+strings.Join(roles, " или "), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsAPIKeyRequest сообщает, передан ли в запросе API-ключ ("Authorization: ApiKey ...")
+func IsAPIKeyRequest(r *http.Request) bool {
+	scheme, _, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	return strings.EqualFold(scheme, SchemeAPIKey)
+}
+
+// writeForbidden отвечает 403 Forbidden с перечислением подходящих ролей
+func writeForbidden(w http.ResponseWriter, roles []string) {
+	http.Error(w, ErrForbidden.Error()+": требуется роль "+strings.Join(roles, " или "), http.StatusForbidden)
+}
+
+// writeAuthError отвечает 429 Too Many Requests, если превышен лимит запросов API-ключа,
+// и 401 Unauthorized с указанием схем аутентификации в остальных случаях
+func writeAuthError(w http.ResponseWriter, err error) {
+	var limited *RateLimitError
+	if errors.As(err, &limited) {
+		w.Header().Set("Retry-After", strconv.Itoa(limited.RetryAfterSeconds()))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	w.Header().Add("WWW-Authenticate", `Bearer realm="api"`)
+	w.Header().Add("WWW-Authenticate", `ApiKey realm="api"`)
+	http.Error(w, "Требуется авторизация: "+err.Error(), http.StatusUnauthorized)
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// okHandler отвечает 200 и пишет в тело ID пользователя из контекста
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	userID, _ := UserIDFromContext(r.Context())
+	w.Write([]byte(userID))
+})
+
+// serve выполняет запрос method с заголовком Authorization authorization (если не пуст)
+func serve(handler http.Handler, method, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/resource", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// bearer возвращает заголовок Authorization с токеном claims, подписанным testSecret
+func bearer(t *testing.T, claims *Claims) string {
+	t.Helper()
+	return "Bearer " + signHS256(t, claims)
+}
+
+func TestMiddleware(t *testing.T) {
+	validator := NewValidator(testSecret)
+	token := signHS256(t, testClaims("user-1", RoleCustomer))
+
+	var seen *http.Request
+	handler := Middleware(validator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r
+	}))
+
+	rec := serve(handler, http.MethodGet, "Bearer "+token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("код %d, ожидался 200: %s", rec.Code, rec.Body)
+	}
+	ctx := seen.Context()
+	if userID, ok := UserIDFromContext(ctx); !ok || userID != "user-1" {
+		t.Fatalf("UserIDFromContext = %q, %v", userID, ok)
+	}
+	if !HasRole(ctx, RoleCustomer) || HasRole(ctx, RoleAdmin) {
+		t.Fatalf("роли в контексте: %v", RolesFromContext(ctx))
+	}
+	if got, _ := TokenFromContext(ctx); got != token {
+		t.Fatalf("TokenFromContext вернул другой токен")
+	}
+	if got, _ := AuthorizationFromContext(ctx); got != "Bearer "+token {
+		t.Fatalf("AuthorizationFromContext = %q", got)
+	}
+	if claims, ok := ClaimsFromContext(ctx); !ok || claims.UserID != "user-1" {
+		t.Fatalf("ClaimsFromContext = %+v, %v", claims, ok)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"без заголовка", ""},
+		{"без схемы", token},
+		{"схема Basic", "Basic dXNlcjpwYXNz"},
+		{"пустой Bearer", "Bearer "},
+		{"испорченный токен", "Bearer " + token + "x"},
+		{"API-ключ без APIKeyValidator", "ApiKey tk_abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, http.MethodGet, tt.authorization)
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("код %d, ожидался 401", rec.Code)
+			}
+			if challenges := rec.Header().Values("WWW-Authenticate"); len(challenges) != 2 {
+				t.Fatalf("WWW-Authenticate: %v", challenges)
+			}
+		})
+	}
+}
+
+func TestMuxMiddlewareAndRequireAuth(t *testing.T) {
+	validator := NewValidator(testSecret)
+	authorization := bearer(t, testClaims("user-1"))
+
+	router := mux.NewRouter()
+	router.Use(MuxMiddleware(validator))
+	router.Handle("/resource", okHandler)
+	if rec := serve(router, http.MethodGet, authorization); rec.Code != http.StatusOK || rec.Body.String() != "user-1" {
+		t.Fatalf("MuxMiddleware: код %d, тело %q", rec.Code, rec.Body)
+	}
+	if rec := serve(router, http.MethodGet, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("MuxMiddleware без токена: код %d", rec.Code)
+	}
+
+	handler := RequireAuth(validator, okHandler)
+	if rec := serve(handler, http.MethodGet, authorization); rec.Code != http.StatusOK {
+		t.Fatalf("RequireAuth: код %d", rec.Code)
+	}
+	if rec := serve(handler, http.MethodGet, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("RequireAuth без токена: код %d", rec.Code)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	validator := NewValidator(testSecret)
+	handler := Middleware(validator)(RequireRole(RoleStaff, RoleAdmin)(okHandler))
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  int
+	}{
+		{"STAFF", []string{RoleStaff}, http.StatusOK},
+		{"ADMIN", []string{RoleCustomer, RoleAdmin}, http.StatusOK},
+		{"CUSTOMER", []string{RoleCustomer}, http.StatusForbidden},
+		{"без ролей", nil, http.StatusForbidden},
+		{"роль в другом регистре", []string{"staff"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(handler, http.MethodGet, bearer(t, testClaims("user-1", tt.roles...))); rec.Code != tt.want {
+				t.Fatalf("код %d, ожидался %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	// Без Middleware в контексте нет ролей
+	if rec := serve(RequireRole(RoleStaff)(okHandler), http.MethodGet, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("без аутентификации: код %d, ожидался 403", rec.Code)
+	}
+	if rec := serve(RequireAuthRole(validator, okHandler, RoleAdmin), http.MethodGet, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("RequireAuthRole без токена: код %d, ожидался 401", rec.Code)
+	}
+	if rec := serve(RequireAuthRole(validator, okHandler, RoleAdmin), http.MethodGet, bearer(t, testClaims("user-1", RoleStaff))); rec.Code != http.StatusForbidden {
+		t.Fatalf("RequireAuthRole без роли: код %d, ожидался 403", rec.Code)
+	}
+}
+
+// oauthClaims возвращает содержимое токена клиента OAuth2 clientID, выданного пользователю userID
+func oauthClaims(userID, clientID string, scopes ...string) *Claims {
+	claims := testClaims(userID)
+	claims.ClientID = clientID
+	claims.Scopes = scopes
+	return claims
+}
+
+func TestRequireScope(t *testing.T) {
+	handler := Middleware(NewValidator(testSecret))(RequireScope(ScopeOrdersRead, ScopeOrdersWrite)(okHandler))
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   int
+	}{
+		{"токен входа не ограничен разрешениями", testClaims("user-1", RoleCustomer), http.StatusOK},
+		{"OAuth2 с orders:read", oauthClaims("user-1", "app", ScopeOrdersRead), http.StatusOK},
+		{"OAuth2 с orders:write", oauthClaims("user-1", "app", ScopeUsersRead, ScopeOrdersWrite), http.StatusOK},
+		{"OAuth2 без нужного разрешения", oauthClaims("user-1", "app", ScopeUsersRead), http.StatusForbidden},
+		{"OAuth2 без разрешений", oauthClaims("user-1", "app"), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, http.MethodGet, bearer(t, tt.claims))
+			if rec.Code != tt.want {
+				t.Fatalf("код %d, ожидался %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusForbidden && !strings.Contains(rec.Body.String(), ScopeOrdersRead) {
+				t.Fatalf("в ответе не указано требуемое разрешение: %s", rec.Body)
+			}
+		})
+	}
+
+	if rec := serve(RequireScope(ScopeOrdersRead)(okHandler), http.MethodGet, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("без аутентификации: код %d, ожидался 403", rec.Code)
+	}
+}
+
+func TestRequireMethodScope(t *testing.T) {
+	handler := Middleware(NewValidator(testSecret))(RequireMethodScope(ScopeOrdersRead, ScopeOrdersWrite)(okHandler))
+
+	readOnly := oauthClaims("user-1", "app", ScopeOrdersRead)
+	writeOnly := oauthClaims("user-1", "app", ScopeOrdersWrite)
+	login := testClaims("user-1")
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		method string
+		want   int
+	}{
+		{"GET с orders:read", readOnly, http.MethodGet, http.StatusOK},
+		{"HEAD с orders:read", readOnly, http.MethodHead, http.StatusOK},
+		{"POST с orders:read", readOnly, http.MethodPost, http.StatusForbidden},
+		{"DELETE с orders:read", readOnly, http.MethodDelete, http.StatusForbidden},
+		{"GET с orders:write", writeOnly, http.MethodGet, http.StatusForbidden},
+		{"PUT с orders:write", writeOnly, http.MethodPut, http.StatusOK},
+		{"POST с токеном входа", login, http.MethodPost, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(handler, tt.method, bearer(t, tt.claims)); rec.Code != tt.want {
+				t.Fatalf("код %d, ожидался %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireService(t *testing.T) {
+	handler := Middleware(NewValidator(testSecret))(RequireService(ScopeInventoryWrite, RoleStaff, RoleAdmin)(okHandler))
+
+	staffOAuth := oauthClaims("user-1", "app", ScopeInventoryWrite)
+	staffOAuth.Roles = []string{RoleStaff}
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   int
+	}{
+		{"сервисный клиент с inventory:write", oauthClaims("order-service", "order-service", ScopeInventoryWrite), http.StatusOK},
+		{"сервисный клиент без inventory:write", oauthClaims("order-service", "order-service", ScopeOrdersRead), http.StatusForbidden},
+		{"токен входа STAFF", testClaims("user-1", RoleStaff), http.StatusOK},
+		{"токен входа CUSTOMER", testClaims("user-1", RoleCustomer), http.StatusForbidden},
+		{"OAuth2 пользователя без роли", oauthClaims("user-1", "app", ScopeInventoryWrite), http.StatusForbidden},
+		{"OAuth2 STAFF с inventory:write", staffOAuth, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serve(handler, http.MethodPost, bearer(t, tt.claims)); rec.Code != tt.want {
+				t.Fatalf("код %d, ожидался %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
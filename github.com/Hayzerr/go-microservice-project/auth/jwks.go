@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JWK - открытый ключ в формате JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519 (RFC 8037)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS - набор открытых ключей, публикуемый по адресу /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewJWK описывает открытый ключ в формате JWK
+func NewJWK(kid string, public crypto.PublicKey) (JWK, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgRS256,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: AlgEdDSA,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("%w: ключ типа %T", ErrUnsupportedAlgorithm, public)
+	}
+}
+
+// PublicKey восстанавливает открытый ключ из JWK
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("некорректный модуль ключа %s: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная экспонента ключа %s: %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: кривая %q", ErrUnsupportedAlgorithm, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("некорректный ключ Ed25519 %s", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: тип ключа %q", ErrUnsupportedAlgorithm, k.Kty)
+	}
+}
+
+// jwksMinRefreshInterval ограничивает частоту внеочередной загрузки JWKS при неизвестном kid,
+// чтобы токены с произвольным kid не приводили к запросу на каждый вызов
+const jwksMinRefreshInterval = 10 * time.Second
+
+// JWKSCache загружает открытые ключи user-service (/.well-known/jwks.json) и кеширует их на ttl.
+// Если токен подписан неизвестным ключом (например, сразу после ротации), набор загружается заново.
+type JWKSCache struct {
+	url        string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWKSCache создает JWKSCache для набора ключей по адресу url
+func NewJWKSCache(url string, ttl time.Duration) *JWKSCache {
+	return &JWKSCache{
+		url:        url,
+		ttl:        ttl,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		keys:       make(map[string]crypto.PublicKey),
+	}
+}
+
+// PublicKey возвращает открытый ключ по kid, при необходимости обновляя кеш.
+// Если user-service недоступен, используются ранее загруженные ключи.
+func (c *JWKSCache) PublicKey(kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key, known := c.keys[kid]
+	sinceFetch := time.Since(c.fetchedAt)
+	if known && sinceFetch < c.ttl {
+		return key, nil
+	}
+
+	if sinceFetch >= c.ttl || (!known && sinceFetch >= jwksMinRefreshInterval) {
+		ctx, cancel := context.WithTimeout(context.Background(), c.httpClient.Timeout)
+		defer cancel()
+		if err := c.refresh(ctx); err != nil {
+			log.Printf("Не удалось обновить набор ключей JWKS: %v", err)
+		}
+		key, known = c.keys[kid]
+	}
+
+	if !known {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// refresh загружает набор ключей. Вызывается под блокировкой c.mu.
+func (c *JWKSCache) refresh(ctx context.Context) error {
+	// Время фиксируется и при ошибке, чтобы недоступный сервис не опрашивался на каждый запрос
+	c.fetchedAt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("статус %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("ошибка декодирования JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.PublicKey()
+		if err != nil {
+			log.Printf("Ключ JWKS пропущен: %v", err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	c.keys = keys
+	return nil
+}
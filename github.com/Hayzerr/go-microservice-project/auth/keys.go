@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Алгоритмы подписи токенов асимметричными ключами
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// rsaKeyBits - размер генерируемых ключей RSA
+const rsaKeyBits = 2048
+
+var (
+	ErrUnknownKey           = errors.New("неизвестный ключ подписи")
+	ErrUnsupportedAlgorithm = errors.New("неподдерживаемый алгоритм подписи")
+)
+
+// SigningKey - закрытый ключ подписи токенов с идентификатором kid
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey crypto.Signer
+}
+
+// PublicKey возвращает открытый ключ для проверки подписи
+func (k *SigningKey) PublicKey() crypto.PublicKey {
+	return k.PrivateKey.Public()
+}
+
+// Method возвращает метод подписи jwt, соответствующий алгоритму ключа
+func (k *SigningKey) Method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// GenerateSigningKey создает новый ключ для алгоритма RS256 или EdDSA
+func GenerateSigningKey(algorithm string) (*SigningKey, error) {
+	var private crypto.Signer
+	switch algorithm {
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case AlgEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+	return newSigningKey(private)
+}
+
+// ParseSigningKeyPEM разбирает закрытый ключ RSA или Ed25519 в формате PEM (PKCS#8 или PKCS#1).
+// Алгоритм определяется типом ключа.
+func ParseSigningKeyPEM(data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("не найден блок PEM")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("неподдерживаемый тип блока PEM: %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора закрытого ключа: %w", err)
+	}
+
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: ключ типа %T", ErrUnsupportedAlgorithm, parsed)
+	}
+	return newSigningKey(private)
+}
+
+// LoadSigningKeyFile читает закрытый ключ из PEM-файла
+func LoadSigningKeyFile(path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParseSigningKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// newSigningKey определяет алгоритм по типу ключа и вычисляет kid
+func newSigningKey(private crypto.Signer) (*SigningKey, error) {
+	algorithm, err := algorithmFor(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	kid, err := KeyID(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{ID: kid, Algorithm: algorithm, PrivateKey: private}, nil
+}
+
+// algorithmFor возвращает алгоритм подписи для открытого ключа
+func algorithmFor(public crypto.PublicKey) (string, error) {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return AlgRS256, nil
+	case ed25519.PublicKey:
+		return AlgEdDSA, nil
+	default:
+		return "", fmt.Errorf("%w: ключ типа %T", ErrUnsupportedAlgorithm, public)
+	}
+}
+
+// KeyID вычисляет kid как SHA-256 от открытого ключа в формате PKIX (первые 16 байт, base64url).
+// Один и тот же ключ всегда получает один и тот же kid, в том числе после перезапуска сервиса.
+func KeyID(public crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+// KeySource возвращает открытый ключ проверки подписи по kid
+type KeySource interface {
+	PublicKey(kid string) (crypto.PublicKey, error)
+}
+
+// KeyValidator проверяет токены, подписанные асимметричными ключами (RS256 или EdDSA).
+// Ключ выбирается по заголовку kid токена, поэтому сервисам не нужен закрытый ключ или общий секрет.
+type KeyValidator struct {
+	keys KeySource
+}
+
+// NewKeyValidator создает новый экземпляр KeyValidator
+func NewKeyValidator(keys KeySource) *KeyValidator {
+	return &KeyValidator{keys: keys}
+}
+
+// ValidateToken проверяет подпись, алгоритм и срок действия токена и возвращает его содержимое
+func (v *KeyValidator) ValidateToken(token string) (*Claims, error) {
+	return parseClaims(token, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("%w: не указан kid", ErrUnknownKey)
+		}
+
+		public, err := v.keys.PublicKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		// Алгоритм из заголовка должен соответствовать типу ключа
+		if algorithm, err := algorithmFor(public); err != nil || algorithm != t.Method.Alg() {
+			return nil, fmt.Errorf("%w: алгоритм %s не подходит для ключа %s", ErrUnsupportedAlgorithm, t.Method.Alg(), kid)
+		}
+		return public, nil
+	}, AlgRS256, AlgEdDSA)
+}
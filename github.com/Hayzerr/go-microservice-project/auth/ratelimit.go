@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited возвращается, если API-ключ превысил лимит запросов
+var ErrRateLimited = errors.New("превышен лимит запросов API-ключа")
+
+// RateLimitError сообщает, через сколько можно повторить запрос
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error возвращает описание ошибки
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v, повторите через %d с", ErrRateLimited, e.RetryAfterSeconds())
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrRateLimited)
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// RetryAfterSeconds возвращает задержку в целых секундах (не меньше одной) для заголовка Retry-After
+func (e *RateLimitError) RetryAfterSeconds() int {
+	return max(1, int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// rateLimiter ограничивает частоту запросов каждого ключа по алгоритму token bucket:
+// емкость корзины равна минутному лимиту, и она равномерно пополняется в течение минуты.
+// Состояние хранится в памяти процесса, поэтому лимит действует на каждый экземпляр сервиса отдельно.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	limit     int
+	tokens    float64
+	updatedAt time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// allow расходует один запрос из лимита perMinute ключа id. Если лимит исчерпан,
+// возвращает false и время до следующего доступного запроса. perMinute <= 0 отключает ограничение.
+func (l *rateLimiter) allow(id string, perMinute int, now time.Time) (bool, time.Duration) {
+	if perMinute <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[id]
+	// Изменение лимита ключа начинает отсчет заново
+	if !ok || bucket.limit != perMinute {
+		bucket = &tokenBucket{limit: perMinute, tokens: float64(perMinute), updatedAt: now}
+		l.buckets[id] = bucket
+	}
+
+	perSecond := float64(perMinute) / 60
+	bucket.tokens = min(float64(perMinute), bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*perSecond)
+	bucket.updatedAt = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second))
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Роли пользователей. Хранятся в таблице users user-service и передаются в токене (claim "roles").
+const (
+	RoleAdmin    = "ADMIN"    // Управление товарами, пользователями и ролями
+	RoleCustomer = "CUSTOMER" // Покупатель; выдается при регистрации
+	RoleStaff    = "STAFF"    // Сотрудник: сканирование билетов на входе, выдача и возврат заказов
+)
+
+var ErrForbidden = errors.New("недостаточно прав")
+
+// Roles перечисляет все известные роли
+var Roles = []string{RoleAdmin, RoleCustomer, RoleStaff}
+
+// NormalizeRole приводит название роли к каноническому виду и сообщает, известна ли такая роль
+func NormalizeRole(role string) (string, bool) {
+	role = strings.ToUpper(strings.TrimSpace(role))
+	for _, known := range Roles {
+		if role == known {
+			return role, true
+		}
+	}
+	return "", false
+}
+
+// hasAnyRole проверяет, что среди roles есть хотя бы одна из allowed
+func hasAnyRole(roles []string, allowed ...string) bool {
+	for _, role := range roles {
+		for _, a := range allowed {
+			if role == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasRole проверяет, что в токене есть хотя бы одна из ролей allowed
+func (c *Claims) HasRole(allowed ...string) bool {
+	return hasAnyRole(c.Roles, allowed...)
+}
+
+// ContextWithRoles возвращает контекст с ролями аутентифицированного пользователя
+func ContextWithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, rolesKey, roles)
+}
+
+// RolesFromContext возвращает роли аутентифицированного пользователя из контекста
+func RolesFromContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(rolesKey).([]string)
+	return roles
+}
+
+// HasRole проверяет, что у аутентифицированного пользователя есть хотя бы одна из ролей allowed
+func HasRole(ctx context.Context, allowed ...string) bool {
+	return hasAnyRole(RolesFromContext(ctx), allowed...)
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+// Разрешения (scopes) API-ключей и токенов клиентов OAuth2. Ключ или токен действует от имени пользователя
+// и с его ролями, но только в пределах выданных разрешений.
+const (
+	ScopeProductsRead  = "products:read"  // Чтение каталога с учетом лимита запросов ключа
+	ScopeProductsWrite = "products:write" // Создание, изменение и удаление товаров (нужна роль ADMIN)
+	ScopeOrdersRead    = "orders:read"    // Просмотр корзины, заказов и их истории
+	ScopeOrdersWrite   = "orders:write"   // Корзина, оформление и смена статуса заказов
+	ScopeUsersRead     = "users:read"     // Просмотр профиля и адресов владельца ключа
+	ScopeScheduleRead  = "schedule:read"  // Личное расписание фестиваля и его экспорт в календарь
+	ScopeScheduleWrite = "schedule:write" // Добавление выступлений в личное расписание и удаление из него
+	// ScopeInventoryWrite - резервирование товара на складе, подтверждение и отмена резервов.
+	// Выдается сервисным клиентам (order-service); пользователю дополнительно нужна роль STAFF или ADMIN.
+	ScopeInventoryWrite = "inventory:write"
+)
+
+// Scopes перечисляет все известные разрешения
+var Scopes = []string{ScopeProductsRead, ScopeProductsWrite, ScopeOrdersRead, ScopeOrdersWrite, ScopeUsersRead,
+	ScopeScheduleRead, ScopeScheduleWrite, ScopeInventoryWrite}
+
+// NormalizeScope приводит название разрешения к каноническому виду и сообщает, известно ли такое разрешение
+func NormalizeScope(scope string) (string, bool) {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	for _, known := range Scopes {
+		if scope == known {
+			return scope, true
+		}
+	}
+	return "", false
+}
+
+// HasScope проверяет, что вызывающему доступно хотя бы одно из разрешений allowed.
+// Для неограниченных учетных данных (токен входа пользователя) всегда возвращает true.
+func (c *Claims) HasScope(allowed ...string) bool {
+	if !c.Scoped() {
+		return true
+	}
+	for _, scope := range c.Scopes {
+		for _, a := range allowed {
+			if scope == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope проверяет, что аутентифицированному вызывающему доступно хотя бы одно из разрешений allowed
+func HasScope(ctx context.Context, allowed ...string) bool {
+	claims, ok := ClaimsFromContext(ctx)
+	return ok && claims.HasScope(allowed...)
+}
+
+// ServiceClient сообщает, выдан ли токен клиенту OAuth2 от его собственного имени (client credentials).
+// Такие токены без пользователя и ролей получают другие сервисы, например order-service.
+func (c *Claims) ServiceClient() bool {
+	return c.APIKeyID == "" && c.ClientID != "" && c.ClientID == c.UserID
+}
+
+// AllowsService проверяет доступ к служебной операции: вызывающему должно быть доступно разрешение scope,
+// и он должен быть сервисным клиентом либо пользователем хотя бы с одной из ролей roles.
+// В отличие от HasScope, токен входа пользователя без нужной роли доступа не получает.
+func (c *Claims) AllowsService(scope string, roles ...string) bool {
+	if !c.HasScope(scope) {
+		return false
+	}
+	return c.ServiceClient() || c.HasRole(roles...)
+}
+
+// AllowsService проверяет доступ аутентифицированного вызывающего к служебной операции, см. Claims.AllowsService
+func AllowsService(ctx context.Context, scope string, roles ...string) bool {
+	claims, ok := ClaimsFromContext(ctx)
+	return ok && claims.AllowsService(scope, roles...)
+}
@@ -20,11 +20,15 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	ProductService_CreateProduct_FullMethodName = "/pb.ProductService/CreateProduct"
-	ProductService_GetProduct_FullMethodName    = "/pb.ProductService/GetProduct"
-	ProductService_ListProducts_FullMethodName  = "/pb.ProductService/ListProducts"
-	ProductService_UpdateProduct_FullMethodName = "/pb.ProductService/UpdateProduct"
-	ProductService_DeleteProduct_FullMethodName = "/pb.ProductService/DeleteProduct"
+	ProductService_CreateProduct_FullMethodName      = "/pb.ProductService/CreateProduct"
+	ProductService_GetProduct_FullMethodName         = "/pb.ProductService/GetProduct"
+	ProductService_ListProducts_FullMethodName       = "/pb.ProductService/ListProducts"
+	ProductService_SearchProducts_FullMethodName     = "/pb.ProductService/SearchProducts"
+	ProductService_UpdateProduct_FullMethodName      = "/pb.ProductService/UpdateProduct"
+	ProductService_DeleteProduct_FullMethodName      = "/pb.ProductService/DeleteProduct"
+	ProductService_ReserveStock_FullMethodName       = "/pb.ProductService/ReserveStock"
+	ProductService_CommitReservation_FullMethodName  = "/pb.ProductService/CommitReservation"
+	ProductService_ReleaseReservation_FullMethodName = "/pb.ProductService/ReleaseReservation"
 )
 
 // ProductServiceClient is the client API for ProductService service.
@@ -34,8 +38,12 @@ type ProductServiceClient interface {
 	CreateProduct(ctx context.Context, in *CreateProductRequest, opts ...grpc.CallOption) (*CreateProductResponse, error)
 	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*GetProductResponse, error)
 	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+	SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error)
 	UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*UpdateProductResponse, error)
 	DeleteProduct(ctx context.Context, in *DeleteProductRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error)
+	CommitReservation(ctx context.Context, in *CommitReservationRequest, opts ...grpc.CallOption) (*CommitReservationResponse, error)
+	ReleaseReservation(ctx context.Context, in *ReleaseReservationRequest, opts ...grpc.CallOption) (*ReleaseReservationResponse, error)
 }
 
 type productServiceClient struct {
@@ -73,6 +81,15 @@ func (c *productServiceClient) ListProducts(ctx context.Context, in *ListProduct
 	return out, nil
 }
 
+func (c *productServiceClient) SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error) {
+	out := new(SearchProductsResponse)
+	err := c.cc.Invoke(ctx, ProductService_SearchProducts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *productServiceClient) UpdateProduct(ctx context.Context, in *UpdateProductRequest, opts ...grpc.CallOption) (*UpdateProductResponse, error) {
 	out := new(UpdateProductResponse)
 	err := c.cc.Invoke(ctx, ProductService_UpdateProduct_FullMethodName, in, out, opts...)
@@ -91,6 +108,33 @@ func (c *productServiceClient) DeleteProduct(ctx context.Context, in *DeleteProd
 	return out, nil
 }
 
+func (c *productServiceClient) ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error) {
+	out := new(ReserveStockResponse)
+	err := c.cc.Invoke(ctx, ProductService_ReserveStock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) CommitReservation(ctx context.Context, in *CommitReservationRequest, opts ...grpc.CallOption) (*CommitReservationResponse, error) {
+	out := new(CommitReservationResponse)
+	err := c.cc.Invoke(ctx, ProductService_CommitReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productServiceClient) ReleaseReservation(ctx context.Context, in *ReleaseReservationRequest, opts ...grpc.CallOption) (*ReleaseReservationResponse, error) {
+	out := new(ReleaseReservationResponse)
+	err := c.cc.Invoke(ctx, ProductService_ReleaseReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServiceServer is the server API for ProductService service.
 // All implementations must embed UnimplementedProductServiceServer
 // for forward compatibility
@@ -98,8 +142,12 @@ type ProductServiceServer interface {
 	CreateProduct(context.Context, *CreateProductRequest) (*CreateProductResponse, error)
 	GetProduct(context.Context, *GetProductRequest) (*GetProductResponse, error)
 	ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error)
+	SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error)
 	UpdateProduct(context.Context, *UpdateProductRequest) (*UpdateProductResponse, error)
 	DeleteProduct(context.Context, *DeleteProductRequest) (*emptypb.Empty, error)
+	ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error)
+	CommitReservation(context.Context, *CommitReservationRequest) (*CommitReservationResponse, error)
+	ReleaseReservation(context.Context, *ReleaseReservationRequest) (*ReleaseReservationResponse, error)
 	mustEmbedUnimplementedProductServiceServer()
 }
 
@@ -116,12 +164,24 @@ func (UnimplementedProductServiceServer) GetProduct(context.Context, *GetProduct
 func (UnimplementedProductServiceServer) ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
 }
+func (UnimplementedProductServiceServer) SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProducts not implemented")
+}
 func (UnimplementedProductServiceServer) UpdateProduct(context.Context, *UpdateProductRequest) (*UpdateProductResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateProduct not implemented")
 }
 func (UnimplementedProductServiceServer) DeleteProduct(context.Context, *DeleteProductRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteProduct not implemented")
 }
+func (UnimplementedProductServiceServer) ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+func (UnimplementedProductServiceServer) CommitReservation(context.Context, *CommitReservationRequest) (*CommitReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReservation not implemented")
+}
+func (UnimplementedProductServiceServer) ReleaseReservation(context.Context, *ReleaseReservationRequest) (*ReleaseReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseReservation not implemented")
+}
 func (UnimplementedProductServiceServer) mustEmbedUnimplementedProductServiceServer() {}
 
 // UnsafeProductServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -189,6 +249,24 @@ func _ProductService_ListProducts_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).SearchProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_SearchProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).SearchProducts(ctx, req.(*SearchProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProductService_UpdateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateProductRequest)
 	if err := dec(in); err != nil {
@@ -225,6 +303,60 @@ func _ProductService_DeleteProduct_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProductService_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ReserveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ReserveStock(ctx, req.(*ReserveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_CommitReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).CommitReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_CommitReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).CommitReservation(ctx, req.(*CommitReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProductService_ReleaseReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ReleaseReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProductService_ReleaseReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ReleaseReservation(ctx, req.(*ReleaseReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProductService_ServiceDesc is the grpc.ServiceDesc for ProductService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -244,6 +376,10 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListProducts",
 			Handler:    _ProductService_ListProducts_Handler,
 		},
+		{
+			MethodName: "SearchProducts",
+			Handler:    _ProductService_SearchProducts_Handler,
+		},
 		{
 			MethodName: "UpdateProduct",
 			Handler:    _ProductService_UpdateProduct_Handler,
@@ -252,6 +388,1197 @@ var ProductService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteProduct",
 			Handler:    _ProductService_DeleteProduct_Handler,
 		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _ProductService_ReserveStock_Handler,
+		},
+		{
+			MethodName: "CommitReservation",
+			Handler:    _ProductService_CommitReservation_Handler,
+		},
+		{
+			MethodName: "ReleaseReservation",
+			Handler:    _ProductService_ReleaseReservation_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/product.proto",
+}
+
+const (
+	FestivalService_CreateVenue_FullMethodName    = "/pb.FestivalService/CreateVenue"
+	FestivalService_GetVenue_FullMethodName       = "/pb.FestivalService/GetVenue"
+	FestivalService_ListVenues_FullMethodName     = "/pb.FestivalService/ListVenues"
+	FestivalService_UpdateVenue_FullMethodName    = "/pb.FestivalService/UpdateVenue"
+	FestivalService_DeleteVenue_FullMethodName    = "/pb.FestivalService/DeleteVenue"
+	FestivalService_CreateFestival_FullMethodName = "/pb.FestivalService/CreateFestival"
+	FestivalService_GetFestival_FullMethodName    = "/pb.FestivalService/GetFestival"
+	FestivalService_ListFestivals_FullMethodName  = "/pb.FestivalService/ListFestivals"
+	FestivalService_UpdateFestival_FullMethodName = "/pb.FestivalService/UpdateFestival"
+	FestivalService_DeleteFestival_FullMethodName = "/pb.FestivalService/DeleteFestival"
+	FestivalService_ListStages_FullMethodName     = "/pb.FestivalService/ListStages"
+	FestivalService_CreateStage_FullMethodName    = "/pb.FestivalService/CreateStage"
+	FestivalService_UpdateStage_FullMethodName    = "/pb.FestivalService/UpdateStage"
+	FestivalService_DeleteStage_FullMethodName    = "/pb.FestivalService/DeleteStage"
+)
+
+// FestivalServiceClient is the client API for FestivalService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FestivalServiceClient interface {
+	CreateVenue(ctx context.Context, in *CreateVenueRequest, opts ...grpc.CallOption) (*VenueResponse, error)
+	GetVenue(ctx context.Context, in *GetVenueRequest, opts ...grpc.CallOption) (*VenueResponse, error)
+	ListVenues(ctx context.Context, in *ListVenuesRequest, opts ...grpc.CallOption) (*ListVenuesResponse, error)
+	UpdateVenue(ctx context.Context, in *UpdateVenueRequest, opts ...grpc.CallOption) (*VenueResponse, error)
+	DeleteVenue(ctx context.Context, in *DeleteVenueRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	CreateFestival(ctx context.Context, in *CreateFestivalRequest, opts ...grpc.CallOption) (*FestivalResponse, error)
+	GetFestival(ctx context.Context, in *GetFestivalRequest, opts ...grpc.CallOption) (*FestivalResponse, error)
+	ListFestivals(ctx context.Context, in *ListFestivalsRequest, opts ...grpc.CallOption) (*ListFestivalsResponse, error)
+	UpdateFestival(ctx context.Context, in *UpdateFestivalRequest, opts ...grpc.CallOption) (*FestivalResponse, error)
+	DeleteFestival(ctx context.Context, in *DeleteFestivalRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListStages(ctx context.Context, in *ListStagesRequest, opts ...grpc.CallOption) (*ListStagesResponse, error)
+	CreateStage(ctx context.Context, in *CreateStageRequest, opts ...grpc.CallOption) (*StageResponse, error)
+	UpdateStage(ctx context.Context, in *UpdateStageRequest, opts ...grpc.CallOption) (*StageResponse, error)
+	DeleteStage(ctx context.Context, in *DeleteStageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type festivalServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFestivalServiceClient(cc grpc.ClientConnInterface) FestivalServiceClient {
+	return &festivalServiceClient{cc}
+}
+
+func (c *festivalServiceClient) CreateVenue(ctx context.Context, in *CreateVenueRequest, opts ...grpc.CallOption) (*VenueResponse, error) {
+	out := new(VenueResponse)
+	err := c.cc.Invoke(ctx, FestivalService_CreateVenue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) GetVenue(ctx context.Context, in *GetVenueRequest, opts ...grpc.CallOption) (*VenueResponse, error) {
+	out := new(VenueResponse)
+	err := c.cc.Invoke(ctx, FestivalService_GetVenue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) ListVenues(ctx context.Context, in *ListVenuesRequest, opts ...grpc.CallOption) (*ListVenuesResponse, error) {
+	out := new(ListVenuesResponse)
+	err := c.cc.Invoke(ctx, FestivalService_ListVenues_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) UpdateVenue(ctx context.Context, in *UpdateVenueRequest, opts ...grpc.CallOption) (*VenueResponse, error) {
+	out := new(VenueResponse)
+	err := c.cc.Invoke(ctx, FestivalService_UpdateVenue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) DeleteVenue(ctx context.Context, in *DeleteVenueRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FestivalService_DeleteVenue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) CreateFestival(ctx context.Context, in *CreateFestivalRequest, opts ...grpc.CallOption) (*FestivalResponse, error) {
+	out := new(FestivalResponse)
+	err := c.cc.Invoke(ctx, FestivalService_CreateFestival_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) GetFestival(ctx context.Context, in *GetFestivalRequest, opts ...grpc.CallOption) (*FestivalResponse, error) {
+	out := new(FestivalResponse)
+	err := c.cc.Invoke(ctx, FestivalService_GetFestival_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) ListFestivals(ctx context.Context, in *ListFestivalsRequest, opts ...grpc.CallOption) (*ListFestivalsResponse, error) {
+	out := new(ListFestivalsResponse)
+	err := c.cc.Invoke(ctx, FestivalService_ListFestivals_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) UpdateFestival(ctx context.Context, in *UpdateFestivalRequest, opts ...grpc.CallOption) (*FestivalResponse, error) {
+	out := new(FestivalResponse)
+	err := c.cc.Invoke(ctx, FestivalService_UpdateFestival_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) DeleteFestival(ctx context.Context, in *DeleteFestivalRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FestivalService_DeleteFestival_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) ListStages(ctx context.Context, in *ListStagesRequest, opts ...grpc.CallOption) (*ListStagesResponse, error) {
+	out := new(ListStagesResponse)
+	err := c.cc.Invoke(ctx, FestivalService_ListStages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) CreateStage(ctx context.Context, in *CreateStageRequest, opts ...grpc.CallOption) (*StageResponse, error) {
+	out := new(StageResponse)
+	err := c.cc.Invoke(ctx, FestivalService_CreateStage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) UpdateStage(ctx context.Context, in *UpdateStageRequest, opts ...grpc.CallOption) (*StageResponse, error) {
+	out := new(StageResponse)
+	err := c.cc.Invoke(ctx, FestivalService_UpdateStage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *festivalServiceClient) DeleteStage(ctx context.Context, in *DeleteStageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FestivalService_DeleteStage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FestivalServiceServer is the server API for FestivalService service.
+// All implementations must embed UnimplementedFestivalServiceServer
+// for forward compatibility
+type FestivalServiceServer interface {
+	CreateVenue(context.Context, *CreateVenueRequest) (*VenueResponse, error)
+	GetVenue(context.Context, *GetVenueRequest) (*VenueResponse, error)
+	ListVenues(context.Context, *ListVenuesRequest) (*ListVenuesResponse, error)
+	UpdateVenue(context.Context, *UpdateVenueRequest) (*VenueResponse, error)
+	DeleteVenue(context.Context, *DeleteVenueRequest) (*emptypb.Empty, error)
+	CreateFestival(context.Context, *CreateFestivalRequest) (*FestivalResponse, error)
+	GetFestival(context.Context, *GetFestivalRequest) (*FestivalResponse, error)
+	ListFestivals(context.Context, *ListFestivalsRequest) (*ListFestivalsResponse, error)
+	UpdateFestival(context.Context, *UpdateFestivalRequest) (*FestivalResponse, error)
+	DeleteFestival(context.Context, *DeleteFestivalRequest) (*emptypb.Empty, error)
+	ListStages(context.Context, *ListStagesRequest) (*ListStagesResponse, error)
+	CreateStage(context.Context, *CreateStageRequest) (*StageResponse, error)
+	UpdateStage(context.Context, *UpdateStageRequest) (*StageResponse, error)
+	DeleteStage(context.Context, *DeleteStageRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedFestivalServiceServer()
+}
+
+// UnimplementedFestivalServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFestivalServiceServer struct {
+}
+
+func (UnimplementedFestivalServiceServer) CreateVenue(context.Context, *CreateVenueRequest) (*VenueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVenue not implemented")
+}
+func (UnimplementedFestivalServiceServer) GetVenue(context.Context, *GetVenueRequest) (*VenueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVenue not implemented")
+}
+func (UnimplementedFestivalServiceServer) ListVenues(context.Context, *ListVenuesRequest) (*ListVenuesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVenues not implemented")
+}
+func (UnimplementedFestivalServiceServer) UpdateVenue(context.Context, *UpdateVenueRequest) (*VenueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVenue not implemented")
+}
+func (UnimplementedFestivalServiceServer) DeleteVenue(context.Context, *DeleteVenueRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVenue not implemented")
+}
+func (UnimplementedFestivalServiceServer) CreateFestival(context.Context, *CreateFestivalRequest) (*FestivalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFestival not implemented")
+}
+func (UnimplementedFestivalServiceServer) GetFestival(context.Context, *GetFestivalRequest) (*FestivalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFestival not implemented")
+}
+func (UnimplementedFestivalServiceServer) ListFestivals(context.Context, *ListFestivalsRequest) (*ListFestivalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFestivals not implemented")
+}
+func (UnimplementedFestivalServiceServer) UpdateFestival(context.Context, *UpdateFestivalRequest) (*FestivalResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFestival not implemented")
+}
+func (UnimplementedFestivalServiceServer) DeleteFestival(context.Context, *DeleteFestivalRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFestival not implemented")
+}
+func (UnimplementedFestivalServiceServer) ListStages(context.Context, *ListStagesRequest) (*ListStagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStages not implemented")
+}
+func (UnimplementedFestivalServiceServer) CreateStage(context.Context, *CreateStageRequest) (*StageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateStage not implemented")
+}
+func (UnimplementedFestivalServiceServer) UpdateStage(context.Context, *UpdateStageRequest) (*StageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateStage not implemented")
+}
+func (UnimplementedFestivalServiceServer) DeleteStage(context.Context, *DeleteStageRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteStage not implemented")
+}
+func (UnimplementedFestivalServiceServer) mustEmbedUnimplementedFestivalServiceServer() {}
+
+// UnsafeFestivalServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FestivalServiceServer will
+// result in compilation errors.
+type UnsafeFestivalServiceServer interface {
+	mustEmbedUnimplementedFestivalServiceServer()
+}
+
+func RegisterFestivalServiceServer(s grpc.ServiceRegistrar, srv FestivalServiceServer) {
+	s.RegisterService(&FestivalService_ServiceDesc, srv)
+}
+
+func _FestivalService_CreateVenue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVenueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).CreateVenue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_CreateVenue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).CreateVenue(ctx, req.(*CreateVenueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_GetVenue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVenueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).GetVenue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_GetVenue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).GetVenue(ctx, req.(*GetVenueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_ListVenues_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVenuesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).ListVenues(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_ListVenues_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).ListVenues(ctx, req.(*ListVenuesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_UpdateVenue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVenueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).UpdateVenue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_UpdateVenue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).UpdateVenue(ctx, req.(*UpdateVenueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_DeleteVenue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVenueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).DeleteVenue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_DeleteVenue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).DeleteVenue(ctx, req.(*DeleteVenueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_CreateFestival_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFestivalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).CreateFestival(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_CreateFestival_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).CreateFestival(ctx, req.(*CreateFestivalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_GetFestival_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFestivalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).GetFestival(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_GetFestival_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).GetFestival(ctx, req.(*GetFestivalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_ListFestivals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFestivalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).ListFestivals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_ListFestivals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).ListFestivals(ctx, req.(*ListFestivalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_UpdateFestival_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFestivalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).UpdateFestival(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_UpdateFestival_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).UpdateFestival(ctx, req.(*UpdateFestivalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_DeleteFestival_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFestivalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).DeleteFestival(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_DeleteFestival_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).DeleteFestival(ctx, req.(*DeleteFestivalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_ListStages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).ListStages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_ListStages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).ListStages(ctx, req.(*ListStagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_CreateStage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).CreateStage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_CreateStage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).CreateStage(ctx, req.(*CreateStageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_UpdateStage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateStageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).UpdateStage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_UpdateStage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).UpdateStage(ctx, req.(*UpdateStageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FestivalService_DeleteStage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteStageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FestivalServiceServer).DeleteStage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FestivalService_DeleteStage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FestivalServiceServer).DeleteStage(ctx, req.(*DeleteStageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FestivalService_ServiceDesc is the grpc.ServiceDesc for FestivalService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FestivalService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.FestivalService",
+	HandlerType: (*FestivalServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateVenue",
+			Handler:    _FestivalService_CreateVenue_Handler,
+		},
+		{
+			MethodName: "GetVenue",
+			Handler:    _FestivalService_GetVenue_Handler,
+		},
+		{
+			MethodName: "ListVenues",
+			Handler:    _FestivalService_ListVenues_Handler,
+		},
+		{
+			MethodName: "UpdateVenue",
+			Handler:    _FestivalService_UpdateVenue_Handler,
+		},
+		{
+			MethodName: "DeleteVenue",
+			Handler:    _FestivalService_DeleteVenue_Handler,
+		},
+		{
+			MethodName: "CreateFestival",
+			Handler:    _FestivalService_CreateFestival_Handler,
+		},
+		{
+			MethodName: "GetFestival",
+			Handler:    _FestivalService_GetFestival_Handler,
+		},
+		{
+			MethodName: "ListFestivals",
+			Handler:    _FestivalService_ListFestivals_Handler,
+		},
+		{
+			MethodName: "UpdateFestival",
+			Handler:    _FestivalService_UpdateFestival_Handler,
+		},
+		{
+			MethodName: "DeleteFestival",
+			Handler:    _FestivalService_DeleteFestival_Handler,
+		},
+		{
+			MethodName: "ListStages",
+			Handler:    _FestivalService_ListStages_Handler,
+		},
+		{
+			MethodName: "CreateStage",
+			Handler:    _FestivalService_CreateStage_Handler,
+		},
+		{
+			MethodName: "UpdateStage",
+			Handler:    _FestivalService_UpdateStage_Handler,
+		},
+		{
+			MethodName: "DeleteStage",
+			Handler:    _FestivalService_DeleteStage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/product.proto",
+}
+
+const (
+	LineupService_CreateArtist_FullMethodName         = "/pb.LineupService/CreateArtist"
+	LineupService_GetArtist_FullMethodName            = "/pb.LineupService/GetArtist"
+	LineupService_ListArtists_FullMethodName          = "/pb.LineupService/ListArtists"
+	LineupService_UpdateArtist_FullMethodName         = "/pb.LineupService/UpdateArtist"
+	LineupService_DeleteArtist_FullMethodName         = "/pb.LineupService/DeleteArtist"
+	LineupService_ListSlots_FullMethodName            = "/pb.LineupService/ListSlots"
+	LineupService_CreateSlot_FullMethodName           = "/pb.LineupService/CreateSlot"
+	LineupService_UpdateSlot_FullMethodName           = "/pb.LineupService/UpdateSlot"
+	LineupService_DeleteSlot_FullMethodName           = "/pb.LineupService/DeleteSlot"
+	LineupService_GetSchedule_FullMethodName          = "/pb.LineupService/GetSchedule"
+	LineupService_ExportSchedule_FullMethodName       = "/pb.LineupService/ExportSchedule"
+	LineupService_GetMySchedule_FullMethodName        = "/pb.LineupService/GetMySchedule"
+	LineupService_ExportMySchedule_FullMethodName     = "/pb.LineupService/ExportMySchedule"
+	LineupService_AddToMySchedule_FullMethodName      = "/pb.LineupService/AddToMySchedule"
+	LineupService_RemoveFromMySchedule_FullMethodName = "/pb.LineupService/RemoveFromMySchedule"
+)
+
+// LineupServiceClient is the client API for LineupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LineupServiceClient interface {
+	CreateArtist(ctx context.Context, in *CreateArtistRequest, opts ...grpc.CallOption) (*ArtistResponse, error)
+	GetArtist(ctx context.Context, in *GetArtistRequest, opts ...grpc.CallOption) (*ArtistResponse, error)
+	ListArtists(ctx context.Context, in *ListArtistsRequest, opts ...grpc.CallOption) (*ListArtistsResponse, error)
+	UpdateArtist(ctx context.Context, in *UpdateArtistRequest, opts ...grpc.CallOption) (*ArtistResponse, error)
+	DeleteArtist(ctx context.Context, in *DeleteArtistRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListSlots(ctx context.Context, in *ListSlotsRequest, opts ...grpc.CallOption) (*ListSlotsResponse, error)
+	CreateSlot(ctx context.Context, in *CreateSlotRequest, opts ...grpc.CallOption) (*SlotResponse, error)
+	UpdateSlot(ctx context.Context, in *UpdateSlotRequest, opts ...grpc.CallOption) (*SlotResponse, error)
+	DeleteSlot(ctx context.Context, in *DeleteSlotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error)
+	ExportSchedule(ctx context.Context, in *ExportScheduleRequest, opts ...grpc.CallOption) (*ExportScheduleResponse, error)
+	GetMySchedule(ctx context.Context, in *GetMyScheduleRequest, opts ...grpc.CallOption) (*GetMyScheduleResponse, error)
+	ExportMySchedule(ctx context.Context, in *GetMyScheduleRequest, opts ...grpc.CallOption) (*ExportScheduleResponse, error)
+	AddToMySchedule(ctx context.Context, in *MyScheduleSlotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveFromMySchedule(ctx context.Context, in *MyScheduleSlotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type lineupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLineupServiceClient(cc grpc.ClientConnInterface) LineupServiceClient {
+	return &lineupServiceClient{cc}
+}
+
+func (c *lineupServiceClient) CreateArtist(ctx context.Context, in *CreateArtistRequest, opts ...grpc.CallOption) (*ArtistResponse, error) {
+	out := new(ArtistResponse)
+	err := c.cc.Invoke(ctx, LineupService_CreateArtist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) GetArtist(ctx context.Context, in *GetArtistRequest, opts ...grpc.CallOption) (*ArtistResponse, error) {
+	out := new(ArtistResponse)
+	err := c.cc.Invoke(ctx, LineupService_GetArtist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) ListArtists(ctx context.Context, in *ListArtistsRequest, opts ...grpc.CallOption) (*ListArtistsResponse, error) {
+	out := new(ListArtistsResponse)
+	err := c.cc.Invoke(ctx, LineupService_ListArtists_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) UpdateArtist(ctx context.Context, in *UpdateArtistRequest, opts ...grpc.CallOption) (*ArtistResponse, error) {
+	out := new(ArtistResponse)
+	err := c.cc.Invoke(ctx, LineupService_UpdateArtist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) DeleteArtist(ctx context.Context, in *DeleteArtistRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LineupService_DeleteArtist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) ListSlots(ctx context.Context, in *ListSlotsRequest, opts ...grpc.CallOption) (*ListSlotsResponse, error) {
+	out := new(ListSlotsResponse)
+	err := c.cc.Invoke(ctx, LineupService_ListSlots_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) CreateSlot(ctx context.Context, in *CreateSlotRequest, opts ...grpc.CallOption) (*SlotResponse, error) {
+	out := new(SlotResponse)
+	err := c.cc.Invoke(ctx, LineupService_CreateSlot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) UpdateSlot(ctx context.Context, in *UpdateSlotRequest, opts ...grpc.CallOption) (*SlotResponse, error) {
+	out := new(SlotResponse)
+	err := c.cc.Invoke(ctx, LineupService_UpdateSlot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) DeleteSlot(ctx context.Context, in *DeleteSlotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LineupService_DeleteSlot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) GetSchedule(ctx context.Context, in *GetScheduleRequest, opts ...grpc.CallOption) (*GetScheduleResponse, error) {
+	out := new(GetScheduleResponse)
+	err := c.cc.Invoke(ctx, LineupService_GetSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) ExportSchedule(ctx context.Context, in *ExportScheduleRequest, opts ...grpc.CallOption) (*ExportScheduleResponse, error) {
+	out := new(ExportScheduleResponse)
+	err := c.cc.Invoke(ctx, LineupService_ExportSchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) GetMySchedule(ctx context.Context, in *GetMyScheduleRequest, opts ...grpc.CallOption) (*GetMyScheduleResponse, error) {
+	out := new(GetMyScheduleResponse)
+	err := c.cc.Invoke(ctx, LineupService_GetMySchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) ExportMySchedule(ctx context.Context, in *GetMyScheduleRequest, opts ...grpc.CallOption) (*ExportScheduleResponse, error) {
+	out := new(ExportScheduleResponse)
+	err := c.cc.Invoke(ctx, LineupService_ExportMySchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) AddToMySchedule(ctx context.Context, in *MyScheduleSlotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LineupService_AddToMySchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lineupServiceClient) RemoveFromMySchedule(ctx context.Context, in *MyScheduleSlotRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, LineupService_RemoveFromMySchedule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LineupServiceServer is the server API for LineupService service.
+// All implementations must embed UnimplementedLineupServiceServer
+// for forward compatibility
+type LineupServiceServer interface {
+	CreateArtist(context.Context, *CreateArtistRequest) (*ArtistResponse, error)
+	GetArtist(context.Context, *GetArtistRequest) (*ArtistResponse, error)
+	ListArtists(context.Context, *ListArtistsRequest) (*ListArtistsResponse, error)
+	UpdateArtist(context.Context, *UpdateArtistRequest) (*ArtistResponse, error)
+	DeleteArtist(context.Context, *DeleteArtistRequest) (*emptypb.Empty, error)
+	ListSlots(context.Context, *ListSlotsRequest) (*ListSlotsResponse, error)
+	CreateSlot(context.Context, *CreateSlotRequest) (*SlotResponse, error)
+	UpdateSlot(context.Context, *UpdateSlotRequest) (*SlotResponse, error)
+	DeleteSlot(context.Context, *DeleteSlotRequest) (*emptypb.Empty, error)
+	GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error)
+	ExportSchedule(context.Context, *ExportScheduleRequest) (*ExportScheduleResponse, error)
+	GetMySchedule(context.Context, *GetMyScheduleRequest) (*GetMyScheduleResponse, error)
+	ExportMySchedule(context.Context, *GetMyScheduleRequest) (*ExportScheduleResponse, error)
+	AddToMySchedule(context.Context, *MyScheduleSlotRequest) (*emptypb.Empty, error)
+	RemoveFromMySchedule(context.Context, *MyScheduleSlotRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedLineupServiceServer()
+}
+
+// UnimplementedLineupServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLineupServiceServer struct {
+}
+
+func (UnimplementedLineupServiceServer) CreateArtist(context.Context, *CreateArtistRequest) (*ArtistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateArtist not implemented")
+}
+func (UnimplementedLineupServiceServer) GetArtist(context.Context, *GetArtistRequest) (*ArtistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtist not implemented")
+}
+func (UnimplementedLineupServiceServer) ListArtists(context.Context, *ListArtistsRequest) (*ListArtistsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArtists not implemented")
+}
+func (UnimplementedLineupServiceServer) UpdateArtist(context.Context, *UpdateArtistRequest) (*ArtistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateArtist not implemented")
+}
+func (UnimplementedLineupServiceServer) DeleteArtist(context.Context, *DeleteArtistRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteArtist not implemented")
+}
+func (UnimplementedLineupServiceServer) ListSlots(context.Context, *ListSlotsRequest) (*ListSlotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSlots not implemented")
+}
+func (UnimplementedLineupServiceServer) CreateSlot(context.Context, *CreateSlotRequest) (*SlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSlot not implemented")
+}
+func (UnimplementedLineupServiceServer) UpdateSlot(context.Context, *UpdateSlotRequest) (*SlotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSlot not implemented")
+}
+func (UnimplementedLineupServiceServer) DeleteSlot(context.Context, *DeleteSlotRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSlot not implemented")
+}
+func (UnimplementedLineupServiceServer) GetSchedule(context.Context, *GetScheduleRequest) (*GetScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSchedule not implemented")
+}
+func (UnimplementedLineupServiceServer) ExportSchedule(context.Context, *ExportScheduleRequest) (*ExportScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSchedule not implemented")
+}
+func (UnimplementedLineupServiceServer) GetMySchedule(context.Context, *GetMyScheduleRequest) (*GetMyScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMySchedule not implemented")
+}
+func (UnimplementedLineupServiceServer) ExportMySchedule(context.Context, *GetMyScheduleRequest) (*ExportScheduleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportMySchedule not implemented")
+}
+func (UnimplementedLineupServiceServer) AddToMySchedule(context.Context, *MyScheduleSlotRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddToMySchedule not implemented")
+}
+func (UnimplementedLineupServiceServer) RemoveFromMySchedule(context.Context, *MyScheduleSlotRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveFromMySchedule not implemented")
+}
+func (UnimplementedLineupServiceServer) mustEmbedUnimplementedLineupServiceServer() {}
+
+// UnsafeLineupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LineupServiceServer will
+// result in compilation errors.
+type UnsafeLineupServiceServer interface {
+	mustEmbedUnimplementedLineupServiceServer()
+}
+
+func RegisterLineupServiceServer(s grpc.ServiceRegistrar, srv LineupServiceServer) {
+	s.RegisterService(&LineupService_ServiceDesc, srv)
+}
+
+func _LineupService_CreateArtist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateArtistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).CreateArtist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_CreateArtist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).CreateArtist(ctx, req.(*CreateArtistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_GetArtist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArtistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).GetArtist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_GetArtist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).GetArtist(ctx, req.(*GetArtistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_ListArtists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListArtistsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).ListArtists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_ListArtists_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).ListArtists(ctx, req.(*ListArtistsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_UpdateArtist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateArtistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).UpdateArtist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_UpdateArtist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).UpdateArtist(ctx, req.(*UpdateArtistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_DeleteArtist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteArtistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).DeleteArtist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_DeleteArtist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).DeleteArtist(ctx, req.(*DeleteArtistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_ListSlots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSlotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).ListSlots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_ListSlots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).ListSlots(ctx, req.(*ListSlotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_CreateSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).CreateSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_CreateSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).CreateSlot(ctx, req.(*CreateSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_UpdateSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).UpdateSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_UpdateSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).UpdateSlot(ctx, req.(*UpdateSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_DeleteSlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).DeleteSlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_DeleteSlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).DeleteSlot(ctx, req.(*DeleteSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_GetSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).GetSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_GetSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).GetSchedule(ctx, req.(*GetScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_ExportSchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).ExportSchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_ExportSchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).ExportSchedule(ctx, req.(*ExportScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_GetMySchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMyScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).GetMySchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_GetMySchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).GetMySchedule(ctx, req.(*GetMyScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_ExportMySchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMyScheduleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).ExportMySchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_ExportMySchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).ExportMySchedule(ctx, req.(*GetMyScheduleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_AddToMySchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MyScheduleSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).AddToMySchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_AddToMySchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).AddToMySchedule(ctx, req.(*MyScheduleSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LineupService_RemoveFromMySchedule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MyScheduleSlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LineupServiceServer).RemoveFromMySchedule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LineupService_RemoveFromMySchedule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LineupServiceServer).RemoveFromMySchedule(ctx, req.(*MyScheduleSlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LineupService_ServiceDesc is the grpc.ServiceDesc for LineupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LineupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.LineupService",
+	HandlerType: (*LineupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateArtist",
+			Handler:    _LineupService_CreateArtist_Handler,
+		},
+		{
+			MethodName: "GetArtist",
+			Handler:    _LineupService_GetArtist_Handler,
+		},
+		{
+			MethodName: "ListArtists",
+			Handler:    _LineupService_ListArtists_Handler,
+		},
+		{
+			MethodName: "UpdateArtist",
+			Handler:    _LineupService_UpdateArtist_Handler,
+		},
+		{
+			MethodName: "DeleteArtist",
+			Handler:    _LineupService_DeleteArtist_Handler,
+		},
+		{
+			MethodName: "ListSlots",
+			Handler:    _LineupService_ListSlots_Handler,
+		},
+		{
+			MethodName: "CreateSlot",
+			Handler:    _LineupService_CreateSlot_Handler,
+		},
+		{
+			MethodName: "UpdateSlot",
+			Handler:    _LineupService_UpdateSlot_Handler,
+		},
+		{
+			MethodName: "DeleteSlot",
+			Handler:    _LineupService_DeleteSlot_Handler,
+		},
+		{
+			MethodName: "GetSchedule",
+			Handler:    _LineupService_GetSchedule_Handler,
+		},
+		{
+			MethodName: "ExportSchedule",
+			Handler:    _LineupService_ExportSchedule_Handler,
+		},
+		{
+			MethodName: "GetMySchedule",
+			Handler:    _LineupService_GetMySchedule_Handler,
+		},
+		{
+			MethodName: "ExportMySchedule",
+			Handler:    _LineupService_ExportMySchedule_Handler,
+		},
+		{
+			MethodName: "AddToMySchedule",
+			Handler:    _LineupService_AddToMySchedule_Handler,
+		},
+		{
+			MethodName: "RemoveFromMySchedule",
+			Handler:    _LineupService_RemoveFromMySchedule_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/product.proto",
@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id UUID PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Снимок адреса доставки на момент оформления; адрес в user-service может измениться позже
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_address JSONB;
+
+-- У пользователя может быть только одна активная корзина
+CREATE UNIQUE INDEX IF NOT EXISTS orders_user_cart_idx ON orders (user_id) WHERE status = 'CART';
+CREATE INDEX IF NOT EXISTS orders_user_status_idx ON orders (user_id, status);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    product_id INT NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    reservation_id VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Вариант продукта (размер, цвет): 0 - продукт без вариантов. Артикул и атрибуты копируются при добавлении в корзину
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id INT NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_sku VARCHAR(64);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_attributes JSONB;
+
+-- Разные варианты одного продукта - разные позиции заказа
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_order_id_product_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS order_items_order_product_variant_idx ON order_items (order_id, product_id, variant_id);
+
+-- Цена единицы товара (с учетом цены варианта), зафиксированная при оформлении заказа. NULL - позиция корзины
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_price NUMERIC(10,2) CHECK (unit_price >= 0);
+-- Название товара, зафиксированное при оформлении заказа: заказ показывается и после удаления товара из каталога
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_name VARCHAR(255);
+
+-- Время отмены резерва в product-service. Резервы отмененных, возвращенных и просроченных заказов
+-- с пустым released_at не удалось вернуть на склад: их повторно отменяет периодическая задача
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS released_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS order_items_unreleased_idx ON order_items (order_id)
+    WHERE reservation_id IS NOT NULL AND released_at IS NULL;
+
+-- История смены статусов заказа: кто и когда перевел заказ из одного статуса в другой
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_idx ON order_status_history (order_id, created_at);
+-- Замена ID пользователя в истории при обезличивании его данных
+CREATE INDEX IF NOT EXISTS order_status_history_actor_idx ON order_status_history (actor);
+-- Поиск неоплаченных заказов с истекшим сроком оплаты
+CREATE INDEX IF NOT EXISTS orders_status_updated_idx ON orders (status, updated_at);
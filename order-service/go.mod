@@ -3,6 +3,7 @@ module github.com/Hayzerr/go-microservice-project/order-service
 go 1.22
 
 require (
+	github.com/Hayzerr/go-microservice-project/auth v0.0.0
 	github.com/Hayzerr/go-microservice-project/pb v0.0.0
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/mux v1.8.1
@@ -19,3 +20,5 @@ require (
 )
 
 replace github.com/Hayzerr/go-microservice-project/pb => ../pb
+
+replace github.com/Hayzerr/go-microservice-project/auth => ../github.com/Hayzerr/go-microservice-project/auth
@@ -1,11 +1,25 @@
 package clients
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrProductNotFound возвращается, когда product-service не знает продукт с указанным ID
+	ErrProductNotFound = errors.New("продукт не найден")
+	// ErrInsufficientStock возвращается, когда на складе недостаточно товара для резерва
+	ErrInsufficientStock = errors.New("недостаточно товара на складе")
+	// ErrReservationNotFound возвращается, когда product-service не знает резерв с указанным ID
+	ErrReservationNotFound = errors.New("резерв не найден")
 )
 
 // ProductClient представляет клиент для взаимодействия с product-service
@@ -14,6 +28,9 @@ type ProductClient struct {
 	client  *http.Client
 	// Для тестирования без реального сервиса
 	mockMode bool
+	// serviceTokens выдает токен order-service для резервов склада; nil - резервы выполняются
+	// с учетными данными вызывающего, что product-service разрешает только ролям STAFF и ADMIN
+	serviceTokens *ServiceTokenSource
 }
 
 // Product представляет упрощенную модель продукта из product-service
@@ -23,6 +40,27 @@ type Product struct {
 	Description string  `json:"description"`
 	Price       float64 `json:"price"`
 	Stock       int     `json:"stock"`
+	// Variants - варианты продукта; если они есть, в корзину добавляется конкретный вариант
+	Variants []ProductVariant `json:"variants"`
+}
+
+// ProductVariant представляет вариант продукта (размер, цвет) из product-service
+type ProductVariant struct {
+	ID         int               `json:"id"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      *float64          `json:"price"` // nil - действует цена продукта
+	Stock      int               `json:"stock"`
+}
+
+// Reservation представляет резерв товара на складе product-service
+type Reservation struct {
+	ID        string `json:"id"`
+	ProductID int    `json:"product_id"`
+	VariantID *int   `json:"variant_id"`
+	OrderID   string `json:"order_id"`
+	Quantity  int    `json:"quantity"`
+	Status    string `json:"status"`
 }
 
 // NewProductClient создает новый экземпляр клиента для работы с product-service
@@ -36,14 +74,35 @@ func NewProductClient() *ProductClient {
 	mockMode := os.Getenv("MOCK_SERVICES") == "true"
 
 	return &ProductClient{
-		baseURL:  baseURL,
-		client:   &http.Client{},
-		mockMode: mockMode,
+		baseURL:       baseURL,
+		client:        &http.Client{},
+		mockMode:      mockMode,
+		serviceTokens: newServiceTokenSourceFromEnv(),
 	}
 }
 
+// HasServiceCredentials сообщает, настроен ли клиент OAuth2 order-service для резервов склада
+func (c *ProductClient) HasServiceCredentials() bool {
+	return c.serviceTokens != nil
+}
+
+// newInventoryRequest создает запрос к резервам склада product-service с токеном order-service.
+// Если клиент OAuth2 не настроен, передаются учетные данные вызывающего (см. newRequest).
+func (c *ProductClient) newInventoryRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := newRequest(ctx, method, url, body)
+	if err != nil || c.serviceTokens == nil {
+		return req, err
+	}
+	token, err := c.serviceTokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req, nil
+}
+
 // GetProductByID получает информацию о продукте по его ID
-func (c *ProductClient) GetProductByID(productID int) (*Product, error) {
+func (c *ProductClient) GetProductByID(ctx context.Context, productID int) (*Product, error) {
 	// Если включен моковый режим, возвращаем моковые данные
 	if c.mockMode {
 		// Создаем моковый продукт с указанным ID
@@ -58,14 +117,19 @@ func (c *ProductClient) GetProductByID(productID int) (*Product, error) {
 
 	url := fmt.Sprintf("%s/api/products/%d", c.baseURL, productID)
 
-	resp, err := c.client.Get(url)
+	req, err := newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка соединения с product-service: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, errors.New("продукт не найден")
+		return nil, ErrProductNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -79,3 +143,96 @@ func (c *ProductClient) GetProductByID(productID int) (*Product, error) {
 
 	return &product, nil
 }
+
+// ReserveStock резервирует quantity единиц продукта (варианта variantID, если он не 0) под заказ orderID
+func (c *ProductClient) ReserveStock(ctx context.Context, productID, variantID int, orderID string, quantity int) (*Reservation, error) {
+	// Если включен моковый режим, резерв всегда успешен
+	if c.mockMode {
+		return &Reservation{
+			ID:        "mock-" + uuid.New().String(),
+			ProductID: productID,
+			OrderID:   orderID,
+			Quantity:  quantity,
+			Status:    "RESERVED",
+		}, nil
+	}
+
+	request := map[string]interface{}{
+		"product_id": productID,
+		"order_id":   orderID,
+		"quantity":   quantity,
+	}
+	if variantID != 0 {
+		request["variant_id"] = variantID
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newInventoryRequest(ctx, http.MethodPost, c.baseURL+"/api/inventory/reservations", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка соединения с product-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+	case http.StatusNotFound:
+		return nil, ErrProductNotFound
+	case http.StatusConflict:
+		return nil, ErrInsufficientStock
+	default:
+		return nil, fmt.Errorf("ошибка резервирования товара: код %d", resp.StatusCode)
+	}
+
+	var reservation Reservation
+	if err := json.NewDecoder(resp.Body).Decode(&reservation); err != nil {
+		return nil, fmt.Errorf("ошибка декодирования ответа: %w", err)
+	}
+
+	return &reservation, nil
+}
+
+// CommitReservation подтверждает резерв после оформления заказа
+func (c *ProductClient) CommitReservation(ctx context.Context, reservationID string) error {
+	return c.reservationAction(ctx, reservationID, "commit")
+}
+
+// ReleaseReservation отменяет резерв и возвращает товар на склад
+func (c *ProductClient) ReleaseReservation(ctx context.Context, reservationID string) error {
+	return c.reservationAction(ctx, reservationID, "release")
+}
+
+// reservationAction выполняет действие (commit/release) над резервом
+func (c *ProductClient) reservationAction(ctx context.Context, reservationID, action string) error {
+	if c.mockMode {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/api/inventory/reservations/%s/%s", c.baseURL, reservationID, action)
+	req, err := c.newInventoryRequest(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка соединения с product-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrReservationNotFound
+	default:
+		return fmt.Errorf("ошибка операции %s над резервом %s: код %d", action, reservationID, resp.StatusCode)
+	}
+}
@@ -0,0 +1,26 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+)
+
+// newRequest создает HTTP-запрос к другому сервису.
+// Если в контексте есть токен или API-ключ аутентифицированного пользователя, он передается дальше
+// в заголовке Authorization, чтобы запрос выполнялся от имени того же пользователя.
+func newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization, ok := auth.AuthorizationFromContext(ctx); ok {
+		req.Header.Set("Authorization", authorization)
+	}
+	return req, nil
+}
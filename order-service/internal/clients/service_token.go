@@ -0,0 +1,103 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+)
+
+// serviceTokenLeeway - за сколько до истечения срока токен получается заново,
+// чтобы он не истек по дороге в product-service
+const serviceTokenLeeway = 30 * time.Second
+
+// ServiceTokenSource получает в user-service токен order-service как клиента OAuth2 (grant client credentials)
+// и кеширует его до истечения срока. Этим токеном с разрешением inventory:write order-service
+// создает и закрывает резервы в product-service: токен покупателя для этого не подходит.
+type ServiceTokenSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	client       *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewServiceTokenSource создает источник токенов клиента clientID для token endpoint tokenURL
+func NewServiceTokenSource(tokenURL, clientID, clientSecret string) *ServiceTokenSource {
+	return &ServiceTokenSource{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// newServiceTokenSourceFromEnv создает источник токенов по SERVICE_CLIENT_ID и SERVICE_CLIENT_SECRET.
+// Возвращает nil, если клиент не настроен.
+func newServiceTokenSourceFromEnv() *ServiceTokenSource {
+	clientID, clientSecret := os.Getenv("SERVICE_CLIENT_ID"), os.Getenv("SERVICE_CLIENT_SECRET")
+	if clientID == "" || clientSecret == "" {
+		return nil
+	}
+	userServiceURL := os.Getenv("USER_SERVICE_URL")
+	if userServiceURL == "" {
+		userServiceURL = "http://localhost:8081" // URL по умолчанию
+	}
+	return NewServiceTokenSource(strings.TrimSuffix(userServiceURL, "/")+"/oauth/token", clientID, clientSecret)
+}
+
+// Token возвращает действующий токен, при необходимости получая новый
+func (s *ServiceTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiresAt) {
+		return s.token, nil
+	}
+
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+		"scope":      {auth.ScopeInventoryWrite},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ошибка соединения с user-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ошибка получения токена сервиса: код %d", resp.StatusCode)
+	}
+
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return "", fmt.Errorf("ошибка декодирования ответа: %w", err)
+	}
+	if tokens.AccessToken == "" {
+		return "", fmt.Errorf("ошибка получения токена сервиса: пустой access_token")
+	}
+
+	s.token = tokens.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn)*time.Second - serviceTokenLeeway)
+	return s.token, nil
+}
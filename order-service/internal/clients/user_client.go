@@ -1,11 +1,20 @@
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"time"
+)
+
+var (
+	// ErrUserNotFound возвращается, когда user-service не знает пользователя с указанным ID
+	ErrUserNotFound = errors.New("пользователь не найден")
+	// ErrAddressNotFound возвращается, когда в адресной книге пользователя нет адреса с указанным ID
+	ErrAddressNotFound = errors.New("адрес не найден")
 )
 
 // UserClient представляет клиент для взаимодействия с user-service
@@ -21,6 +30,27 @@ type User struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	// Время подтверждения email; nil, если email не подтвержден
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+}
+
+// EmailVerified сообщает, подтвержден ли email пользователя
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// Address представляет адрес из адресной книги пользователя в user-service
+type Address struct {
+	ID         string `json:"id"`
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	IsDefault  bool   `json:"is_default"`
 }
 
 // NewUserClient создает новый экземпляр клиента для работы с user-service
@@ -41,26 +71,33 @@ func NewUserClient() *UserClient {
 }
 
 // GetUserByID получает информацию о пользователе по его ID
-func (c *UserClient) GetUserByID(userID string) (*User, error) {
+func (c *UserClient) GetUserByID(ctx context.Context, userID string) (*User, error) {
 	// Если включен моковый режим, возвращаем моковые данные
 	if c.mockMode {
+		verifiedAt := time.Now().UTC()
 		return &User{
-			ID:       userID,
-			Username: "test_user",
-			Email:    "test@example.com",
+			ID:              userID,
+			Username:        "test_user",
+			Email:           "test@example.com",
+			EmailVerifiedAt: &verifiedAt,
 		}, nil
 	}
 
 	url := fmt.Sprintf("%s/api/users/%s", c.baseURL, userID)
 
-	resp, err := c.client.Get(url)
+	req, err := newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка соединения с user-service: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, errors.New("пользователь не найден")
+		return nil, ErrUserNotFound
 	}
 
 	if resp.StatusCode != http.StatusOK {
@@ -74,3 +111,72 @@ func (c *UserClient) GetUserByID(userID string) (*User, error) {
 
 	return &user, nil
 }
+
+// GetShippingAddress получает адрес доставки пользователя: адрес addressID или, если он пуст,
+// адрес по умолчанию. Возвращает nil, nil, если addressID пуст, а адресов у пользователя нет.
+func (c *UserClient) GetShippingAddress(ctx context.Context, userID, addressID string) (*Address, error) {
+	// Если включен моковый режим, возвращаем моковые данные
+	if c.mockMode {
+		if addressID == "" {
+			addressID = "mock-address"
+		}
+		return &Address{
+			ID:         addressID,
+			Recipient:  "Тестовый Пользователь",
+			Line1:      "ул. Тестовая, 1",
+			City:       "Москва",
+			PostalCode: "101000",
+			Country:    "RU",
+			IsDefault:  true,
+		}, nil
+	}
+
+	if addressID != "" {
+		var address Address
+		url := fmt.Sprintf("%s/api/users/%s/addresses/%s", c.baseURL, userID, addressID)
+		if err := c.getJSON(ctx, url, ErrAddressNotFound, &address); err != nil {
+			return nil, err
+		}
+		return &address, nil
+	}
+
+	// Адрес по умолчанию user-service возвращает первым
+	var addresses []Address
+	url := fmt.Sprintf("%s/api/users/%s/addresses", c.baseURL, userID)
+	if err := c.getJSON(ctx, url, ErrUserNotFound, &addresses); err != nil {
+		return nil, err
+	}
+	for i := range addresses {
+		if addresses[i].IsDefault {
+			return &addresses[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getJSON выполняет GET-запрос к user-service и декодирует ответ в result.
+// На ответ 404 возвращается notFound.
+func (c *UserClient) getJSON(ctx context.Context, url string, notFound error, result interface{}) error {
+	req, err := newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка соединения с user-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return notFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ошибка запроса к user-service: код %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("ошибка декодирования ответа: %w", err)
+	}
+	return nil
+}
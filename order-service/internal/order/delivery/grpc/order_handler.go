@@ -0,0 +1,446 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	pb "github.com/Hayzerr/go-microservice-project/pb"
+
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/repository"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// RolePolicy перечисляет методы OrderService, доступные только сотрудникам и администраторам.
+// Проверяется перехватчиками auth.UnaryRoleInterceptor и auth.StreamRoleInterceptor, подключенными в main.go.
+var RolePolicy = auth.RolePolicy{
+	pb.OrderService_PayOrder_FullMethodName:     {auth.RoleStaff, auth.RoleAdmin},
+	pb.OrderService_FulfillOrder_FullMethodName: {auth.RoleStaff, auth.RoleAdmin},
+	pb.OrderService_RefundOrder_FullMethodName:  {auth.RoleStaff, auth.RoleAdmin},
+}
+
+// ScopePolicy перечисляет разрешения API-ключа или токена OAuth2, с которыми доступны методы OrderService.
+// Проверяется перехватчиками auth.UnaryScopeInterceptor и auth.StreamScopeInterceptor, подключенными в main.go.
+var ScopePolicy = auth.ScopePolicy{
+	pb.OrderService_GetOrder_FullMethodName:        {auth.ScopeOrdersRead},
+	pb.OrderService_ListOrders_FullMethodName:      {auth.ScopeOrdersRead},
+	pb.OrderService_GetCart_FullMethodName:         {auth.ScopeOrdersRead},
+	pb.OrderService_GetOrderHistory_FullMethodName: {auth.ScopeOrdersRead},
+	pb.OrderService_CreateOrder_FullMethodName:     {auth.ScopeOrdersWrite},
+	pb.OrderService_DeleteOrder_FullMethodName:     {auth.ScopeOrdersWrite},
+	pb.OrderService_AddToCart_FullMethodName:       {auth.ScopeOrdersWrite},
+	pb.OrderService_RemoveFromCart_FullMethodName:  {auth.ScopeOrdersWrite},
+	pb.OrderService_Checkout_FullMethodName:        {auth.ScopeOrdersWrite},
+	pb.OrderService_PayOrder_FullMethodName:        {auth.ScopeOrdersWrite},
+	pb.OrderService_FulfillOrder_FullMethodName:    {auth.ScopeOrdersWrite},
+	pb.OrderService_CancelOrder_FullMethodName:     {auth.ScopeOrdersWrite},
+	pb.OrderService_RefundOrder_FullMethodName:     {auth.ScopeOrdersWrite},
+}
+
+// OrderGRPCHandler реализует gRPC сервер для OrderService.
+type OrderGRPCHandler struct {
+	pb.UnimplementedOrderServiceServer // Встраивание для обратной совместимости
+	useCase                            usecase.UseCase
+}
+
+// NewOrderGRPCHandler создает новый экземпляр OrderGRPCHandler.
+func NewOrderGRPCHandler(uc usecase.UseCase) *OrderGRPCHandler {
+	return &OrderGRPCHandler{useCase: uc}
+}
+
+// mapOrderModelToProto преобразует заказ с товарами в proto-сообщение Order.
+func mapOrderModelToProto(order *models.Cart) *pb.Order {
+	if order == nil {
+		return nil
+	}
+
+	items := make([]*pb.OrderItem, 0, len(order.Items))
+	productIDs := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		productID := strconv.Itoa(item.ProductID)
+		pbItem := &pb.OrderItem{
+			Id:           item.ID,
+			ProductId:    productID,
+			Quantity:     int32(item.Quantity),
+			ProductName:  item.ProductName,
+			ProductPrice: item.ProductPrice,
+			TotalPrice:   item.TotalPrice,
+		}
+		if item.Variant != nil {
+			pbItem.VariantId = strconv.Itoa(item.Variant.ID)
+			pbItem.VariantSku = item.Variant.SKU
+			pbItem.VariantAttributes = item.Variant.Attributes
+		}
+		items = append(items, pbItem)
+		productIDs = append(productIDs, productID)
+	}
+
+	return &pb.Order{
+		Id:         order.ID,
+		UserId:     order.UserID,
+		ProductIds: productIDs,
+		Total:      order.TotalPrice,
+		Status:     string(order.Status),
+		Items:      items,
+		CreatedAt:  timestamppb.New(order.CreatedAt),
+		UpdatedAt:  timestamppb.New(order.UpdatedAt),
+
+		ShippingAddress: mapShippingAddressToProto(order.ShippingAddress),
+	}
+}
+
+// mapShippingAddressToProto преобразует снимок адреса доставки в proto-сообщение.
+func mapShippingAddressToProto(address *models.ShippingAddress) *pb.ShippingAddress {
+	if address == nil {
+		return nil
+	}
+	return &pb.ShippingAddress{
+		AddressId:  address.AddressID,
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+// mapErrorToStatus преобразует ошибки бизнес-логики в gRPC статусы.
+func mapErrorToStatus(err error, message string) error {
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound),
+		errors.Is(err, repository.ErrCartNotFound),
+		errors.Is(err, repository.ErrNoActiveCart),
+		errors.Is(err, repository.ErrItemNotInCart),
+		errors.Is(err, usecase.ErrUserNotFound),
+		errors.Is(err, usecase.ErrProductNotFound),
+		errors.Is(err, usecase.ErrVariantNotFound),
+		errors.Is(err, usecase.ErrAddressNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", message, err)
+	case errors.Is(err, repository.ErrOrderCheckedOut),
+		errors.Is(err, repository.ErrCartEmpty),
+		errors.Is(err, usecase.ErrInsufficientStock),
+		errors.Is(err, usecase.ErrInvalidTransition),
+		errors.Is(err, usecase.ErrOrderNotDeletable):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrAccessDenied),
+		errors.Is(err, usecase.ErrEmailNotVerified):
+		return status.Errorf(codes.PermissionDenied, "%s: %v", message, err)
+	case errors.Is(err, repository.ErrStatusConflict),
+		errors.Is(err, repository.ErrCartChanged):
+		return status.Errorf(codes.Aborted, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrStockNotReleased):
+		return status.Errorf(codes.Unavailable, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrInvalidQuantity),
+		errors.Is(err, usecase.ErrVariantRequired),
+		errors.Is(err, usecase.ErrInvalidStatus):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", message, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", message, err)
+	}
+}
+
+// mapTransitionModelToProto преобразует запись истории статусов в proto-сообщение.
+func mapTransitionModelToProto(transition *models.StatusTransition) *pb.StatusTransition {
+	return &pb.StatusTransition{
+		Id:         transition.ID,
+		OrderId:    transition.OrderID,
+		FromStatus: string(transition.FromStatus),
+		ToStatus:   string(transition.ToStatus),
+		Actor:      transition.Actor,
+		CreatedAt:  timestamppb.New(transition.CreatedAt),
+	}
+}
+
+// requestUserID возвращает ID пользователя из токена вызова.
+// Поле user_id запроса необязательно; если оно передано, оно должно совпадать с пользователем из токена.
+func requestUserID(ctx context.Context, requested string) (string, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "Требуется авторизация")
+	}
+	if requested != "" && requested != userID {
+		return "", status.Errorf(codes.PermissionDenied, "Нельзя работать с заказами другого пользователя")
+	}
+	return userID, nil
+}
+
+// parseProductID преобразует строковый ID продукта из proto в int.
+func parseProductID(value string) (int, error) {
+	productID, err := strconv.Atoi(value)
+	if err != nil || productID <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Некорректный ID товара: %q", value)
+	}
+	return productID, nil
+}
+
+// parseVariantID преобразует необязательный ID варианта товара из запроса; пустая строка - 0 (без варианта)
+func parseVariantID(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	variantID, err := strconv.Atoi(value)
+	if err != nil || variantID <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "Некорректный ID варианта товара: %q", value)
+	}
+	return variantID, nil
+}
+
+// GetOrder обрабатывает gRPC запрос на получение заказа по ID.
+func (h *OrderGRPCHandler) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID заказа не может быть пустым")
+	}
+
+	order, err := h.useCase.GetOrder(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при получении заказа")
+	}
+
+	return mapOrderModelToProto(order), nil
+}
+
+// ListOrders отправляет в поток оформленные заказы пользователя, опционально отфильтрованные по статусам.
+func (h *OrderGRPCHandler) ListOrders(req *pb.ListOrdersRequest, stream pb.OrderService_ListOrdersServer) error {
+	userID, err := requestUserID(stream.Context(), req.GetUserId())
+	if err != nil {
+		return err
+	}
+
+	statuses := make([]models.OrderStatus, 0, len(req.GetStatuses()))
+	for _, value := range req.GetStatuses() {
+		orderStatus, err := usecase.ParseOrderStatus(value)
+		if err != nil {
+			return mapErrorToStatus(err, "Некорректный фильтр статуса")
+		}
+		statuses = append(statuses, orderStatus)
+	}
+
+	orders, err := h.useCase.ListOrders(stream.Context(), userID, statuses...)
+	if err != nil {
+		return mapErrorToStatus(err, "Ошибка при получении списка заказов")
+	}
+
+	for _, order := range orders {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(mapOrderModelToProto(order)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateOrder обрабатывает gRPC запрос на создание и оформление заказа.
+func (h *OrderGRPCHandler) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.Order, error) {
+	userID, err := requestUserID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetProductIds()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Список товаров не может быть пустым")
+	}
+
+	productIDs := make([]int, 0, len(req.GetProductIds()))
+	for _, value := range req.GetProductIds() {
+		productID, err := parseProductID(value)
+		if err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, productID)
+	}
+
+	order, err := h.useCase.CreateOrder(ctx, userID, productIDs)
+	if err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при создании заказа")
+	}
+
+	return mapOrderModelToProto(order), nil
+}
+
+// DeleteOrder обрабатывает gRPC запрос на удаление заказа.
+func (h *OrderGRPCHandler) DeleteOrder(ctx context.Context, req *pb.DeleteOrderRequest) (*emptypb.Empty, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID заказа не может быть пустым")
+	}
+
+	if err := h.useCase.DeleteOrder(ctx, userID, req.GetId()); err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при удалении заказа")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// AddToCart обрабатывает gRPC запрос на добавление товара в корзину и возвращает обновленную корзину.
+func (h *OrderGRPCHandler) AddToCart(ctx context.Context, req *pb.AddToCartRequest) (*pb.Order, error) {
+	userID, err := requestUserID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	productID, err := parseProductID(req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+	variantID, err := parseVariantID(req.GetVariantId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetQuantity() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Количество должно быть положительным числом")
+	}
+
+	if err := h.useCase.AddToCart(ctx, userID, productID, variantID, int(req.GetQuantity())); err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при добавлении товара в корзину")
+	}
+
+	cart, err := h.useCase.GetCart(ctx, userID)
+	if err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при получении корзины")
+	}
+
+	return mapOrderModelToProto(cart), nil
+}
+
+// RemoveFromCart обрабатывает gRPC запрос на удаление товара из корзины и возвращает обновленную корзину.
+func (h *OrderGRPCHandler) RemoveFromCart(ctx context.Context, req *pb.RemoveFromCartRequest) (*pb.Order, error) {
+	userID, err := requestUserID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	productID, err := parseProductID(req.GetProductId())
+	if err != nil {
+		return nil, err
+	}
+
+	variantID, err := parseVariantID(req.GetVariantId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.useCase.RemoveFromCart(ctx, userID, productID, variantID); err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при удалении товара из корзины")
+	}
+
+	cart, err := h.useCase.GetCart(ctx, userID)
+	if err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при получении корзины")
+	}
+
+	return mapOrderModelToProto(cart), nil
+}
+
+// GetCart обрабатывает gRPC запрос на получение содержимого корзины.
+func (h *OrderGRPCHandler) GetCart(ctx context.Context, req *pb.GetCartRequest) (*pb.Order, error) {
+	userID, err := requestUserID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := h.useCase.GetCart(ctx, userID)
+	if err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при получении корзины")
+	}
+
+	return mapOrderModelToProto(cart), nil
+}
+
+// Checkout обрабатывает gRPC запрос на оформление заказа.
+func (h *OrderGRPCHandler) Checkout(ctx context.Context, req *pb.CheckoutRequest) (*pb.Order, error) {
+	userID, err := requestUserID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := h.useCase.Checkout(ctx, userID, req.GetAddressId())
+	if err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при оформлении заказа")
+	}
+
+	return mapOrderModelToProto(order), nil
+}
+
+// PayOrder обрабатывает gRPC запрос на отметку заказа как оплаченного.
+func (h *OrderGRPCHandler) PayOrder(ctx context.Context, req *pb.OrderTransitionRequest) (*pb.Order, error) {
+	return h.transition(ctx, req, h.useCase.PayOrder, "Ошибка при оплате заказа")
+}
+
+// FulfillOrder обрабатывает gRPC запрос на выполнение заказа.
+func (h *OrderGRPCHandler) FulfillOrder(ctx context.Context, req *pb.OrderTransitionRequest) (*pb.Order, error) {
+	return h.transition(ctx, req, h.useCase.FulfillOrder, "Ошибка при выполнении заказа")
+}
+
+// CancelOrder обрабатывает gRPC запрос на отмену заказа.
+func (h *OrderGRPCHandler) CancelOrder(ctx context.Context, req *pb.OrderTransitionRequest) (*pb.Order, error) {
+	return h.transition(ctx, req, h.useCase.CancelOrder, "Ошибка при отмене заказа")
+}
+
+// RefundOrder обрабатывает gRPC запрос на возврат заказа.
+func (h *OrderGRPCHandler) RefundOrder(ctx context.Context, req *pb.OrderTransitionRequest) (*pb.Order, error) {
+	return h.transition(ctx, req, h.useCase.RefundOrder, "Ошибка при возврате заказа")
+}
+
+// GetOrderHistory обрабатывает gRPC запрос на получение истории статусов заказа.
+func (h *OrderGRPCHandler) GetOrderHistory(ctx context.Context, req *pb.GetOrderHistoryRequest) (*pb.OrderHistory, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID заказа не может быть пустым")
+	}
+
+	history, err := h.useCase.GetOrderHistory(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, mapErrorToStatus(err, "Ошибка при получении истории заказа")
+	}
+
+	transitions := make([]*pb.StatusTransition, 0, len(history))
+	for _, transition := range history {
+		transitions = append(transitions, mapTransitionModelToProto(transition))
+	}
+
+	return &pb.OrderHistory{Transitions: transitions}, nil
+}
+
+// transition выполняет смену статуса заказа через переданный метод бизнес-логики
+// от имени пользователя из токена.
+func (h *OrderGRPCHandler) transition(
+	ctx context.Context,
+	req *pb.OrderTransitionRequest,
+	change func(ctx context.Context, userID string, orderID string) (*models.Cart, error),
+	message string,
+) (*pb.Order, error) {
+	userID, err := requestUserID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID заказа не может быть пустым")
+	}
+
+	order, err := change(ctx, userID, req.GetId())
+	if err != nil {
+		return nil, mapErrorToStatus(err, message)
+	}
+
+	return mapOrderModelToProto(order), nil
+}
@@ -1,40 +1,91 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/repository"
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/usecase"
 	"github.com/gorilla/mux"
 )
 
 // Handler представляет HTTP-обработчик для работы с заказами
 type Handler struct {
-	useCase usecase.UseCase
+	useCase        usecase.UseCase
+	tokenValidator auth.TokenValidator
 }
 
 // NewHandler создает новый экземпляр Handler
-func NewHandler(useCase usecase.UseCase) *Handler {
+func NewHandler(useCase usecase.UseCase, tokenValidator auth.TokenValidator) *Handler {
 	return &Handler{
-		useCase: useCase,
+		useCase:        useCase,
+		tokenValidator: tokenValidator,
 	}
 }
 
-// RegisterRoutes регистрирует маршруты для API заказов
+// RegisterRoutes регистрирует маршруты для API заказов.
+// Все маршруты требуют токен: пользователь определяется по нему, а не по параметрам запроса.
+// Оплата, выполнение и возврат заказа доступны только ролям STAFF и ADMIN.
+// С API-ключом или токеном OAuth2 чтение требует разрешения orders:read, остальные запросы - orders:write.
 func (h *Handler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/api/cart", h.AddToCart).Methods(http.MethodPost)
-	router.HandleFunc("/api/cart/{user_id}/{product_id}", h.RemoveFromCart).Methods(http.MethodDelete)
-	router.HandleFunc("/api/cart/{user_id}", h.GetCart).Methods(http.MethodGet)
-	router.HandleFunc("/api/cart/{user_id}/checkout", h.Checkout).Methods(http.MethodPost)
-	router.HandleFunc("/api/orders/{user_id}", h.GetCompletedOrders).Methods(http.MethodGet)
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(auth.MuxMiddleware(h.tokenValidator))
+	api.Use(auth.RequireMethodScope(auth.ScopeOrdersRead, auth.ScopeOrdersWrite))
+
+	api.HandleFunc("/cart", h.AddToCart).Methods(http.MethodPost)
+	api.HandleFunc("/cart", h.GetCart).Methods(http.MethodGet)
+	api.HandleFunc("/cart/checkout", h.Checkout).Methods(http.MethodPost)
+	api.HandleFunc("/cart/{product_id}", h.RemoveFromCart).Methods(http.MethodDelete)
+	api.HandleFunc("/orders", h.GetCompletedOrders).Methods(http.MethodGet)
+	api.HandleFunc("/orders/{id}", h.GetOrder).Methods(http.MethodGet)
+	api.Handle("/orders/{id}/pay", staffOnly(h.transitionHandler(h.useCase.PayOrder))).Methods(http.MethodPost)
+	api.Handle("/orders/{id}/fulfill", staffOnly(h.transitionHandler(h.useCase.FulfillOrder))).Methods(http.MethodPost)
+	api.HandleFunc("/orders/{id}/cancel", h.transitionHandler(h.useCase.CancelOrder)).Methods(http.MethodPost)
+	api.Handle("/orders/{id}/refund", staffOnly(h.transitionHandler(h.useCase.RefundOrder))).Methods(http.MethodPost)
+	api.HandleFunc("/orders/{id}/history", h.GetOrderHistory).Methods(http.MethodGet)
+	// Заказы конкретного пользователя (выгрузка его данных в user-service) и обезличивание после удаления учетной записи
+	api.HandleFunc("/users/{user_id}/orders", h.GetUserOrders).Methods(http.MethodGet)
+	api.Handle("/users/{user_id}/erase", adminOnly(h.EraseUserData)).Methods(http.MethodPost)
+}
+
+// staffOnly пропускает к обработчику только пользователей с ролью STAFF или ADMIN
+func staffOnly(next http.HandlerFunc) http.Handler {
+	return auth.RequireRole(auth.RoleStaff, auth.RoleAdmin)(next)
+}
+
+// adminOnly пропускает к обработчику только пользователей с ролью ADMIN
+func adminOnly(next http.HandlerFunc) http.Handler {
+	return auth.RequireRole(auth.RoleAdmin)(next)
+}
+
+// currentUserID возвращает ID пользователя, которого аутентифицировало middleware
+func currentUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Требуется авторизация", http.StatusUnauthorized)
+	}
+	return userID, ok
 }
 
 // AddToCartRequest представляет запрос на добавление товара в корзину
 type AddToCartRequest struct {
-	UserID    string `json:"user_id"`
-	ProductID int    `json:"product_id"`
-	Quantity  int    `json:"quantity"`
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id"` // Обязателен для товара с вариантами (размер, цвет)
+	Quantity  int `json:"quantity"`
+}
+
+// CheckoutRequest представляет необязательное тело запроса на оформление заказа
+type CheckoutRequest struct {
+	// AddressID - адрес из адресной книги user-service; если не указан, используется адрес по умолчанию
+	AddressID string `json:"address_id"`
 }
 
 // SuccessResponse представляет успешный ответ
@@ -50,19 +101,24 @@ type ErrorResponse struct {
 
 // AddToCart обрабатывает запрос на добавление товара в корзину
 func (h *Handler) AddToCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var req AddToCartRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
 		return
 	}
 
-	if req.UserID == "" {
-		http.Error(w, "Не указан ID пользователя", http.StatusBadRequest)
+	if req.ProductID <= 0 {
+		http.Error(w, "Некорректный ID товара", http.StatusBadRequest)
 		return
 	}
 
-	if req.ProductID <= 0 {
-		http.Error(w, "Некорректный ID товара", http.StatusBadRequest)
+	if req.VariantID < 0 {
+		http.Error(w, "Некорректный ID варианта товара", http.StatusBadRequest)
 		return
 	}
 
@@ -71,7 +127,7 @@ func (h *Handler) AddToCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.useCase.AddToCart(req.UserID, req.ProductID, req.Quantity)
+	err := h.useCase.AddToCart(r.Context(), userID, req.ProductID, req.VariantID, req.Quantity)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -87,16 +143,14 @@ func (h *Handler) AddToCart(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// RemoveFromCart обрабатывает запрос на удаление товара из корзины
+// RemoveFromCart обрабатывает запрос на удаление товара из корзины.
+// Вариант товара передается параметром variant_id.
 func (h *Handler) RemoveFromCart(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["user_id"]
-	productIDStr := vars["product_id"]
-
-	if userID == "" {
-		http.Error(w, "Не указан ID пользователя", http.StatusBadRequest)
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
+	productIDStr := mux.Vars(r)["product_id"]
 
 	productID, err := strconv.Atoi(productIDStr)
 	if err != nil || productID <= 0 {
@@ -104,7 +158,16 @@ func (h *Handler) RemoveFromCart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.useCase.RemoveFromCart(userID, productID)
+	variantID := 0
+	if value := r.URL.Query().Get("variant_id"); value != "" {
+		variantID, err = strconv.Atoi(value)
+		if err != nil || variantID <= 0 {
+			http.Error(w, "Некорректный ID варианта товара", http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.useCase.RemoveFromCart(r.Context(), userID, productID, variantID)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -122,15 +185,12 @@ func (h *Handler) RemoveFromCart(w http.ResponseWriter, r *http.Request) {
 
 // GetCart обрабатывает запрос на получение содержимого корзины
 func (h *Handler) GetCart(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["user_id"]
-
-	if userID == "" {
-		http.Error(w, "Не указан ID пользователя", http.StatusBadRequest)
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	cart, err := h.useCase.GetCart(userID)
+	cart, err := h.useCase.GetCart(r.Context(), userID)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -145,19 +205,21 @@ func (h *Handler) GetCart(w http.ResponseWriter, r *http.Request) {
 
 // Checkout обрабатывает запрос на оформление заказа
 func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["user_id"]
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
 
-	if userID == "" {
-		http.Error(w, "Не указан ID пользователя", http.StatusBadRequest)
+	// Тело запроса необязательно
+	var req CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Некорректный запрос", http.StatusBadRequest)
 		return
 	}
 
-	err := h.useCase.Checkout(userID)
+	_, err := h.useCase.Checkout(r.Context(), userID, req.AddressID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		writeError(w, err)
 		return
 	}
 
@@ -169,17 +231,27 @@ func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetCompletedOrders обрабатывает запрос на получение выполненных заказов пользователя
+// GetCompletedOrders обрабатывает запрос на получение оформленных заказов пользователя.
+// Параметр status (можно повторять или перечислять через запятую) фильтрует заказы по статусам.
 func (h *Handler) GetCompletedOrders(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["user_id"]
-
-	if userID == "" {
-		http.Error(w, "Не указан ID пользователя", http.StatusBadRequest)
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	orders, err := h.useCase.GetCompletedOrders(userID)
+	var statuses []models.OrderStatus
+	for _, param := range r.URL.Query()["status"] {
+		for _, value := range strings.Split(param, ",") {
+			status, err := usecase.ParseOrderStatus(value)
+			if err != nil {
+				http.Error(w, "Некорректный статус заказа: "+value, http.StatusBadRequest)
+				return
+			}
+			statuses = append(statuses, status)
+		}
+	}
+
+	orders, err := h.useCase.GetCompletedOrders(r.Context(), userID, statuses...)
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -191,3 +263,141 @@ func (h *Handler) GetCompletedOrders(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(orders)
 }
+
+// GetOrder обрабатывает запрос на получение заказа с товарами
+func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := h.useCase.GetOrder(r.Context(), userID, mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(order)
+}
+
+// transitionHandler создает обработчик запроса на смену статуса заказа.
+// Инициатором перехода в истории записывается аутентифицированный пользователь.
+func (h *Handler) transitionHandler(change func(ctx context.Context, userID string, orderID string) (*models.Cart, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		orderID := mux.Vars(r)["id"]
+		if orderID == "" {
+			http.Error(w, "Не указан ID заказа", http.StatusBadRequest)
+			return
+		}
+
+		order, err := change(r.Context(), userID, orderID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(order)
+	}
+}
+
+// GetOrderHistory обрабатывает запрос на получение истории статусов заказа
+func (h *Handler) GetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	orderID := mux.Vars(r)["id"]
+	if orderID == "" {
+		http.Error(w, "Не указан ID заказа", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.useCase.GetOrderHistory(r.Context(), userID, orderID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetUserOrders обрабатывает запрос на получение оформленных заказов пользователя с товарами.
+// Доступен самому пользователю, а также ролям STAFF и ADMIN.
+func (h *Handler) GetUserOrders(w http.ResponseWriter, r *http.Request) {
+	currentID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	userID := mux.Vars(r)["user_id"]
+	if userID != currentID && !auth.HasRole(r.Context(), auth.RoleStaff, auth.RoleAdmin) {
+		writeError(w, usecase.ErrAccessDenied)
+		return
+	}
+
+	orders, err := h.useCase.ListOrders(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(orders)
+}
+
+// EraseUserData обрабатывает уведомление user-service об удалении пользователя (только ADMIN)
+func (h *Handler) EraseUserData(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["user_id"]
+
+	anonymized, err := h.useCase.EraseUserData(r.Context(), userID)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(SuccessResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Данные пользователя обезличены, заказов: %d", anonymized),
+	})
+}
+
+// writeError отправляет ошибку в формате ErrorResponse с HTTP-статусом, соответствующим ее виду
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusBadRequest
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound),
+		errors.Is(err, usecase.ErrAddressNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, usecase.ErrAccessDenied),
+		errors.Is(err, usecase.ErrEmailNotVerified):
+		code = http.StatusForbidden
+	case errors.Is(err, usecase.ErrInvalidTransition),
+		errors.Is(err, repository.ErrStatusConflict),
+		errors.Is(err, repository.ErrCartChanged),
+		errors.Is(err, usecase.ErrOrderNotDeletable):
+		code = http.StatusConflict
+	case errors.Is(err, usecase.ErrStockNotReleased):
+		code = http.StatusBadGateway
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+}
@@ -8,27 +8,89 @@ import (
 type OrderStatus string
 
 const (
-	StatusCart     OrderStatus = "CART"     // Товары в корзине, заказ не оформлен
-	StatusCheckout OrderStatus = "CHECKOUT" // Заказ оформлен
+	StatusCart           OrderStatus = "CART"            // Товары в корзине, заказ не оформлен
+	StatusPendingPayment OrderStatus = "PENDING_PAYMENT" // Заказ оформлен и ожидает оплаты
+	StatusPaid           OrderStatus = "PAID"            // Заказ оплачен
+	StatusFulfilled      OrderStatus = "FULFILLED"       // Заказ выполнен (передан покупателю)
+	StatusCancelled      OrderStatus = "CANCELLED"       // Заказ отменен, товар возвращен на склад
+	StatusRefunded       OrderStatus = "REFUNDED"        // Деньги возвращены, товар возвращен на склад
+	StatusExpired        OrderStatus = "EXPIRED"         // Заказ не оплачен вовремя, товар возвращен на склад
+
+	// StatusCheckout - статус оформленных заказов до появления оплаты.
+	// Новые заказы в него не попадают, а существующие обрабатываются как PENDING_PAYMENT.
+	StatusCheckout OrderStatus = "CHECKOUT"
 )
 
+// StatusTransition представляет запись истории смены статуса заказа
+type StatusTransition struct {
+	ID         string      `json:"id"`
+	OrderID    string      `json:"order_id"`
+	FromStatus OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus `json:"to_status"`
+	Actor      string      `json:"actor"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
 // Order представляет заказ пользователя
 type Order struct {
-	ID        string      `json:"id"`
-	UserID    string      `json:"user_id"`
-	Status    OrderStatus `json:"status"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	ID     string      `json:"id"`
+	UserID string      `json:"user_id"`
+	Status OrderStatus `json:"status"`
+	// ShippingAddress - адрес доставки на момент оформления; nil, если адрес не указан
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// ShippingAddress - копия адреса из адресной книги user-service, сохраненная при оформлении заказа.
+// Последующие изменения адресной книги на заказ не влияют.
+type ShippingAddress struct {
+	AddressID  string `json:"address_id"` // ID адреса в user-service
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone,omitempty"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
 }
 
 // OrderItem представляет товар в заказе
 type OrderItem struct {
-	ID        string    `json:"id"`
-	OrderID   string    `json:"order_id"`
-	ProductID int       `json:"product_id"`
-	Quantity  int       `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string `json:"id"`
+	OrderID   string `json:"order_id"`
+	ProductID int    `json:"product_id"`
+	// Variant - выбранный вариант продукта (размер, цвет); nil, если у продукта нет вариантов
+	Variant  *ItemVariant `json:"variant,omitempty"`
+	Quantity int          `json:"quantity"`
+	// ReservationID - ID резерва товара в product-service, выставляется при оформлении заказа
+	ReservationID string `json:"reservation_id,omitempty"`
+	// UnitPrice - цена единицы товара, зафиксированная при оформлении заказа; nil для позиций корзины
+	UnitPrice *float64 `json:"unit_price,omitempty"`
+	// ProductName - название товара, зафиксированное при оформлении заказа вместе с ценой; пусто для позиций корзины.
+	// Вместе с Variant позволяет показывать заказ и после удаления товара из каталога.
+	ProductName string `json:"-"`
+	// ReleasedAt - время отмены резерва в product-service (товар возвращен на склад); nil, пока резерв действует
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ItemVariant - вариант продукта в позиции заказа. Артикул и атрибуты копируются из product-service
+// при добавлении в корзину, поэтому позиция сохраняет их и после изменения или удаления варианта.
+type ItemVariant struct {
+	ID         int               `json:"variant_id"`
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// VariantID возвращает ID варианта позиции или 0, если вариант не выбран
+func (i *OrderItem) VariantID() int {
+	if i.Variant == nil {
+		return 0
+	}
+	return i.Variant.ID
 }
 
 // CartItem представляет товар в корзине с деталями продукта
@@ -39,7 +101,8 @@ type CartItem struct {
 	TotalPrice   float64 `json:"total_price"`
 }
 
-// Cart представляет корзину пользователя с товарами
+// Cart представляет корзину пользователя с товарами.
+// Используется также для оформленного заказа с деталями товаров.
 type Cart struct {
 	Order
 	Items      []CartItem `json:"items"`
@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"errors"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,10 +12,11 @@ import (
 
 // MemoryRepository представляет репозиторий для работы с заказами, хранящимися в памяти
 type MemoryRepository struct {
-	orders     map[string]*models.Order       // Хранение заказов по ID
-	orderItems map[string][]*models.OrderItem // Хранение товаров в заказе по ID заказа
-	userOrders map[string]string              // Хранение текущей активной корзины пользователя (user_id -> order_id)
-	mu         sync.RWMutex                   // Мьютекс для безопасного доступа к данным
+	orders     map[string]*models.Order              // Хранение заказов по ID
+	orderItems map[string][]*models.OrderItem        // Хранение товаров в заказе по ID заказа
+	userOrders map[string]string                     // Хранение текущей активной корзины пользователя (user_id -> order_id)
+	history    map[string][]*models.StatusTransition // История смены статусов по ID заказа
+	mu         sync.RWMutex                          // Мьютекс для безопасного доступа к данным
 }
 
 // NewMemoryRepository создает новый экземпляр in-memory репозитория
@@ -23,6 +25,7 @@ func NewMemoryRepository() *MemoryRepository {
 		orders:     make(map[string]*models.Order),
 		orderItems: make(map[string][]*models.OrderItem),
 		userOrders: make(map[string]string),
+		history:    make(map[string][]*models.StatusTransition),
 	}
 }
 
@@ -57,28 +60,34 @@ func (r *MemoryRepository) GetOrCreateCart(userID string) (*models.Order, error)
 }
 
 // AddItemToCart добавляет товар в корзину
-func (r *MemoryRepository) AddItemToCart(orderID string, productID int, quantity int) (*models.OrderItem, error) {
+func (r *MemoryRepository) AddItemToCart(orderID string, productID int, variant *models.ItemVariant, quantity int) (*models.OrderItem, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Проверяем существование заказа
 	order, exists := r.orders[orderID]
 	if !exists {
-		return nil, errors.New("заказ не найден")
+		return nil, ErrOrderNotFound
 	}
 
 	if order.Status != models.StatusCart {
-		return nil, errors.New("заказ уже оформлен")
+		return nil, ErrOrderCheckedOut
 	}
 
 	// Проверяем, есть ли уже этот товар в корзине
+	variantID := 0
+	if variant != nil {
+		variantID = variant.ID
+	}
 	items := r.orderItems[orderID]
 	for _, item := range items {
-		if item.ProductID == productID {
+		if item.ProductID == productID && item.VariantID() == variantID {
 			// Увеличиваем количество
 			item.Quantity += quantity
+			item.Variant = variant
 			item.UpdatedAt = time.Now()
-			return item, nil
+			itemCopy := *item
+			return &itemCopy, nil
 		}
 	}
 
@@ -88,41 +97,43 @@ func (r *MemoryRepository) AddItemToCart(orderID string, productID int, quantity
 		ID:        uuid.New().String(),
 		OrderID:   orderID,
 		ProductID: productID,
+		Variant:   variant,
 		Quantity:  quantity,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 
 	r.orderItems[orderID] = append(r.orderItems[orderID], item)
-	return item, nil
+	itemCopy := *item
+	return &itemCopy, nil
 }
 
 // RemoveItemFromCart удаляет товар из корзины
-func (r *MemoryRepository) RemoveItemFromCart(orderID string, productID int) error {
+func (r *MemoryRepository) RemoveItemFromCart(orderID string, productID, variantID int) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Проверяем существование заказа
 	order, exists := r.orders[orderID]
 	if !exists {
-		return errors.New("заказ не найден")
+		return ErrOrderNotFound
 	}
 
 	if order.Status != models.StatusCart {
-		return errors.New("заказ уже оформлен")
+		return ErrOrderCheckedOut
 	}
 
 	// Находим товар в корзине
 	items := r.orderItems[orderID]
 	for i, item := range items {
-		if item.ProductID == productID {
+		if item.ProductID == productID && item.VariantID() == variantID {
 			// Удаляем товар из списка
 			r.orderItems[orderID] = append(items[:i], items[i+1:]...)
 			return nil
 		}
 	}
 
-	return errors.New("товар не найден в корзине")
+	return ErrItemNotInCart
 }
 
 // GetCartItems получает список товаров в корзине
@@ -133,13 +144,16 @@ func (r *MemoryRepository) GetCartItems(orderID string) ([]*models.OrderItem, er
 	// Проверяем существование заказа
 	_, exists := r.orders[orderID]
 	if !exists {
-		return nil, errors.New("заказ не найден")
+		return nil, ErrOrderNotFound
 	}
 
 	items := r.orderItems[orderID]
-	// Копируем слайс, чтобы избежать ошибок с конкурентным доступом
+	// Копируем товары, чтобы избежать ошибок с конкурентным доступом
 	result := make([]*models.OrderItem, len(items))
-	copy(result, items)
+	for i, item := range items {
+		itemCopy := *item
+		result[i] = &itemCopy
+	}
 
 	return result, nil
 }
@@ -151,59 +165,119 @@ func (r *MemoryRepository) GetCartByUserID(userID string) (*models.Order, error)
 
 	orderID, exists := r.userOrders[userID]
 	if !exists {
-		return nil, errors.New("корзина не найдена")
+		return nil, ErrCartNotFound
 	}
 
 	order, exists := r.orders[orderID]
 	if !exists {
-		return nil, errors.New("заказ не найден")
+		return nil, ErrOrderNotFound
 	}
 
 	if order.Status != models.StatusCart {
-		return nil, errors.New("нет активной корзины")
+		return nil, ErrNoActiveCart
 	}
 
 	return order, nil
 }
 
 // CheckoutCart выполняет оформление заказа
-func (r *MemoryRepository) CheckoutCart(orderID string) error {
+func (r *MemoryRepository) CheckoutCart(orderID string, reserved []*models.OrderItem, actor string, shipping *models.ShippingAddress) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Проверяем существование заказа
 	order, exists := r.orders[orderID]
 	if !exists {
-		return errors.New("заказ не найден")
+		return ErrOrderNotFound
 	}
 
 	if order.Status != models.StatusCart {
-		return errors.New("заказ уже оформлен")
+		return ErrOrderCheckedOut
 	}
 
 	// Проверяем, что в корзине есть товары
 	items := r.orderItems[orderID]
 	if len(items) == 0 {
-		return errors.New("корзина пуста")
+		return ErrCartEmpty
+	}
+	// Проверяем, что корзина не изменилась после резервирования, и сохраняем резервы и цены
+	if !matchReservedItems(items, reserved) {
+		return ErrCartChanged
+	}
+	now := time.Now()
+	for _, item := range items {
+		for _, reservedItem := range reserved {
+			if item.ProductID == reservedItem.ProductID && item.VariantID() == reservedItem.VariantID() {
+				unitPrice := *reservedItem.UnitPrice
+				item.ReservationID = reservedItem.ReservationID
+				item.UnitPrice = &unitPrice
+				item.ProductName = reservedItem.ProductName
+				item.UpdatedAt = now
+			}
+		}
 	}
 
 	// Обновляем статус заказа
-	order.Status = models.StatusCheckout
-	order.UpdatedAt = time.Now()
+	if shipping != nil {
+		shippingCopy := *shipping
+		order.ShippingAddress = &shippingCopy
+	}
+	r.setStatus(order, models.StatusPendingPayment, actor)
+
+	return nil
+}
+
+// CreateOrder сохраняет оформленный заказ с товарами, не затрагивая корзину пользователя
+func (r *MemoryRepository) CreateOrder(order *models.Order, items []*models.OrderItem, actor string) error {
+	if len(items) == 0 {
+		return ErrCartEmpty
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	stored := *order
+	if order.ShippingAddress != nil {
+		shippingCopy := *order.ShippingAddress
+		stored.ShippingAddress = &shippingCopy
+	}
+	stored.Status = models.StatusCart
+	stored.CreatedAt = now
+	stored.UpdatedAt = now
+
+	storedItems := make([]*models.OrderItem, 0, len(items))
+	for _, item := range items {
+		itemCopy := *item
+		itemCopy.ID = uuid.New().String()
+		itemCopy.OrderID = stored.ID
+		if item.UnitPrice != nil {
+			unitPrice := *item.UnitPrice
+			itemCopy.UnitPrice = &unitPrice
+		}
+		itemCopy.CreatedAt = now
+		itemCopy.UpdatedAt = now
+		storedItems = append(storedItems, &itemCopy)
+	}
+
+	r.orders[stored.ID] = &stored
+	r.orderItems[stored.ID] = storedItems
+	r.setStatus(&stored, models.StatusPendingPayment, actor)
 
+	*order = stored
 	return nil
 }
 
-// GetCompletedOrders получает список выполненных заказов пользователя
-func (r *MemoryRepository) GetCompletedOrders(userID string) ([]*models.Order, error) {
+// GetCompletedOrders получает список оформленных заказов пользователя
+func (r *MemoryRepository) GetCompletedOrders(userID string, statuses []models.OrderStatus) ([]*models.Order, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var completedOrders []*models.Order
 
-	// Перебираем все заказы и находим выполненные заказы пользователя
+	// Перебираем все заказы и находим оформленные заказы пользователя
 	for _, order := range r.orders {
-		if order.UserID == userID && order.Status == models.StatusCheckout {
+		if order.UserID == userID && order.Status != models.StatusCart && matchStatus(order.Status, statuses) {
 			// Создаем копию заказа, чтобы избежать проблем с конкурентным доступом
 			orderCopy := *order
 			completedOrders = append(completedOrders, &orderCopy)
@@ -211,8 +285,217 @@ func (r *MemoryRepository) GetCompletedOrders(userID string) ([]*models.Order, e
 	}
 
 	if len(completedOrders) == 0 {
-		return nil, errors.New("выполненные заказы не найдены")
+		return nil, ErrCompletedOrdersNotFound
 	}
 
+	sortOrders(completedOrders)
 	return completedOrders, nil
 }
+
+// UpdateOrderStatus переводит заказ из статуса from в статус to и записывает переход в историю
+func (r *MemoryRepository) UpdateOrderStatus(orderID string, from, to models.OrderStatus, actor string) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, exists := r.orders[orderID]
+	if !exists {
+		return nil, ErrOrderNotFound
+	}
+
+	if order.Status != from {
+		return nil, ErrStatusConflict
+	}
+
+	r.setStatus(order, to, actor)
+
+	orderCopy := *order
+	return &orderCopy, nil
+}
+
+// GetStatusHistory получает историю смены статусов заказа
+func (r *MemoryRepository) GetStatusHistory(orderID string) ([]*models.StatusTransition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.orders[orderID]; !exists {
+		return nil, ErrOrderNotFound
+	}
+
+	transitions := r.history[orderID]
+	result := make([]*models.StatusTransition, len(transitions))
+	for i, transition := range transitions {
+		transitionCopy := *transition
+		result[i] = &transitionCopy
+	}
+
+	return result, nil
+}
+
+// GetOrdersByStatus получает заказы с указанными статусами, не изменявшиеся с момента updatedBefore
+func (r *MemoryRepository) GetOrdersByStatus(statuses []models.OrderStatus, updatedBefore time.Time) ([]*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*models.Order, 0)
+	for _, order := range r.orders {
+		if matchStatus(order.Status, statuses) && order.UpdatedAt.Before(updatedBefore) {
+			orderCopy := *order
+			result = append(result, &orderCopy)
+		}
+	}
+
+	sortOrders(result)
+	return result, nil
+}
+
+// setStatus меняет статус заказа и добавляет запись в историю. Вызывается под блокировкой.
+func (r *MemoryRepository) setStatus(order *models.Order, to models.OrderStatus, actor string) {
+	now := time.Now()
+	r.history[order.ID] = append(r.history[order.ID], &models.StatusTransition{
+		ID:         uuid.New().String(),
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   to,
+		Actor:      actor,
+		CreatedAt:  now,
+	})
+	order.Status = to
+	order.UpdatedAt = now
+}
+
+// matchStatus проверяет, входит ли статус в список. Пустой список допускает любой статус.
+func matchStatus(status models.OrderStatus, statuses []models.OrderStatus) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// sortOrders упорядочивает заказы по времени создания, как это делает PostgresRepository
+func sortOrders(orders []*models.Order) {
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+}
+
+// MarkReservationsReleased отмечает резервы reservationIDs позиций заказа как отмененные
+func (r *MemoryRepository) MarkReservationsReleased(orderID string, reservationIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[orderID]; !exists {
+		return ErrOrderNotFound
+	}
+
+	now := time.Now()
+	for _, item := range r.orderItems[orderID] {
+		if item.ReleasedAt == nil && slices.Contains(reservationIDs, item.ReservationID) {
+			releasedAt := now
+			item.ReleasedAt = &releasedAt
+			item.UpdatedAt = now
+		}
+	}
+	return nil
+}
+
+// GetOrdersWithUnreleasedReservations получает заказы с указанными статусами, у позиций которых остались
+// неотмененные резервы
+func (r *MemoryRepository) GetOrdersWithUnreleasedReservations(statuses []models.OrderStatus) ([]*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*models.Order, 0)
+	for _, order := range r.orders {
+		if !slices.Contains(statuses, order.Status) {
+			continue
+		}
+		for _, item := range r.orderItems[order.ID] {
+			if item.ReservationID != "" && item.ReleasedAt == nil {
+				orderCopy := *order
+				result = append(result, &orderCopy)
+				break
+			}
+		}
+	}
+
+	sortOrders(result)
+	return result, nil
+}
+
+// GetOrderByID получает заказ (или корзину) по его ID
+func (r *MemoryRepository) GetOrderByID(orderID string) (*models.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, exists := r.orders[orderID]
+	if !exists {
+		return nil, ErrOrderNotFound
+	}
+
+	orderCopy := *order
+	return &orderCopy, nil
+}
+
+// DeleteOrder удаляет заказ в статусе status вместе с его товарами и историей
+func (r *MemoryRepository) DeleteOrder(orderID string, status models.OrderStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, exists := r.orders[orderID]
+	if !exists {
+		return ErrOrderNotFound
+	}
+	if order.Status != status {
+		return ErrStatusConflict
+	}
+
+	// Если удаляется активная корзина, пользователь получит новую при следующем добавлении товара
+	if r.userOrders[order.UserID] == orderID {
+		delete(r.userOrders, order.UserID)
+	}
+	delete(r.orderItems, orderID)
+	delete(r.history, orderID)
+	delete(r.orders, orderID)
+
+	return nil
+}
+
+// AnonymizeUserOrders удаляет корзину пользователя, передает его заказы псевдониму replacementID
+// и стирает адреса доставки
+func (r *MemoryRepository) AnonymizeUserOrders(userID string, replacementID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// После оформления userOrders указывает на оформленный заказ, пока не создана новая корзина
+	if cartID, exists := r.userOrders[userID]; exists {
+		if cart, ok := r.orders[cartID]; ok && cart.Status == models.StatusCart {
+			delete(r.orderItems, cartID)
+			delete(r.history, cartID)
+			delete(r.orders, cartID)
+		}
+		delete(r.userOrders, userID)
+	}
+
+	anonymized := 0
+	for _, order := range r.orders {
+		if order.UserID == userID {
+			order.UserID = replacementID
+			order.ShippingAddress = nil
+			anonymized++
+		}
+	}
+	for _, transitions := range r.history {
+		for _, transition := range transitions {
+			if transition.Actor == userID {
+				transition.Actor = replacementID
+			}
+		}
+	}
+
+	return anonymized, nil
+}
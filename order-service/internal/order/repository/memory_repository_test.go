@@ -0,0 +1,9 @@
+package repository
+
+import "testing"
+
+func TestMemoryRepositoryContract(t *testing.T) {
+	runRepositoryContract(t, func(t *testing.T) Repository {
+		return NewMemoryRepository()
+	})
+}
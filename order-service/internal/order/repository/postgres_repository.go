@@ -0,0 +1,663 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PostgresRepository представляет репозиторий заказов, хранящий данные в PostgreSQL.
+// Схема таблиц orders, order_items и order_status_history описана в db/init.sql.
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// orderColumns - столбцы заказа в порядке, который ожидает scanOrder
+const orderColumns = `id, user_id, status, shipping_address, created_at, updated_at`
+
+// itemColumns - столбцы товара заказа в порядке, который ожидает scanItem
+const itemColumns = `id, order_id, product_id, variant_id, variant_sku, variant_attributes, quantity,
+	COALESCE(reservation_id, ''), unit_price, COALESCE(product_name, ''), released_at, created_at, updated_at`
+
+// NewPostgresRepository создает новый экземпляр репозитория для PostgreSQL
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// GetOrCreateCart получает или создает корзину для пользователя.
+// Уникальный частичный индекс по (user_id) WHERE status = 'CART' гарантирует,
+// что при конкурентных вызовах у пользователя окажется только одна корзина.
+func (r *PostgresRepository) GetOrCreateCart(userID string) (*models.Order, error) {
+	now := time.Now().UTC()
+	_, err := r.db.Exec(
+		`INSERT INTO orders (id, user_id, status, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $4)
+		 ON CONFLICT (user_id) WHERE status = 'CART' DO NOTHING`,
+		uuid.New().String(), userID, models.StatusCart, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOrder(r.db.QueryRow(
+		`SELECT `+orderColumns+`
+		 FROM orders WHERE user_id = $1 AND status = $2`,
+		userID, models.StatusCart,
+	))
+}
+
+// AddItemToCart добавляет товар в корзину.
+// Если товар (тот же вариант) уже есть в корзине, его количество увеличивается в той же транзакции.
+func (r *PostgresRepository) AddItemToCart(orderID string, productID int, variant *models.ItemVariant, quantity int) (*models.OrderItem, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockCart(tx, orderID); err != nil {
+		return nil, err
+	}
+
+	variantID, variantSKU, variantAttributes, err := variantValues(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	item, err := scanItem(tx.QueryRow(
+		`INSERT INTO order_items (id, order_id, product_id, variant_id, variant_sku, variant_attributes, quantity, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		 ON CONFLICT (order_id, product_id, variant_id)
+		 DO UPDATE SET quantity = order_items.quantity + EXCLUDED.quantity, variant_sku = EXCLUDED.variant_sku,
+			variant_attributes = EXCLUDED.variant_attributes, updated_at = EXCLUDED.updated_at
+		 RETURNING `+itemColumns,
+		uuid.New().String(), orderID, productID, variantID, variantSKU, variantAttributes, quantity, now,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// RemoveItemFromCart удаляет товар из корзины
+func (r *PostgresRepository) RemoveItemFromCart(orderID string, productID, variantID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCart(tx, orderID); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM order_items WHERE order_id = $1 AND product_id = $2 AND variant_id = $3`,
+		orderID, productID, variantID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrItemNotInCart
+	}
+
+	return tx.Commit()
+}
+
+// GetCartItems получает список товаров в корзине
+func (r *PostgresRepository) GetCartItems(orderID string) ([]*models.OrderItem, error) {
+	if _, err := r.GetOrderByID(orderID); err != nil {
+		return nil, err
+	}
+
+	return queryItems(r.db, orderID)
+}
+
+// GetCartByUserID получает корзину пользователя по его ID
+func (r *PostgresRepository) GetCartByUserID(userID string) (*models.Order, error) {
+	order, err := scanOrder(r.db.QueryRow(
+		`SELECT `+orderColumns+`
+		 FROM orders WHERE user_id = $1 AND status = $2`,
+		userID, models.StatusCart,
+	))
+	if err == nil {
+		return order, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// Различаем пользователя без заказов и пользователя, чья корзина уже оформлена
+	var hasOrders bool
+	if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM orders WHERE user_id = $1)`, userID).Scan(&hasOrders); err != nil {
+		return nil, err
+	}
+	if hasOrders {
+		return nil, ErrNoActiveCart
+	}
+	return nil, ErrCartNotFound
+}
+
+// CheckoutCart выполняет оформление заказа, сохраняет резервы и цены позиций и снимок адреса доставки.
+// Позиции корзины сверяются с зарезервированными под той же блокировкой, что и у AddItemToCart
+// и RemoveItemFromCart, поэтому изменить корзину между проверкой и сменой статуса нельзя.
+func (r *PostgresRepository) CheckoutCart(orderID string, reserved []*models.OrderItem, actor string, shipping *models.ShippingAddress) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockCart(tx, orderID); err != nil {
+		return err
+	}
+
+	// Проверяем, что в корзине есть товары и она не изменилась после резервирования
+	items, err := queryItems(tx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return ErrCartEmpty
+	}
+	if !matchReservedItems(items, reserved) {
+		return ErrCartChanged
+	}
+
+	now := time.Now().UTC()
+	for _, item := range reserved {
+		_, err := tx.Exec(
+			`UPDATE order_items SET reservation_id = $1, unit_price = $2, product_name = NULLIF($3, ''), updated_at = $4
+			 WHERE order_id = $5 AND product_id = $6 AND variant_id = $7`,
+			item.ReservationID, *item.UnitPrice, item.ProductName, now, orderID, item.ProductID, item.VariantID(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if shipping != nil {
+		address, err := json.Marshal(shipping)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE orders SET shipping_address = $2 WHERE id = $1`, orderID, address); err != nil {
+			return err
+		}
+	}
+
+	if _, err := setOrderStatus(tx, orderID, models.StatusCart, models.StatusPendingPayment, actor); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateOrder в одной транзакции сохраняет оформленный заказ, его товары и запись в истории статусов
+func (r *PostgresRepository) CreateOrder(order *models.Order, items []*models.OrderItem, actor string) error {
+	if len(items) == 0 {
+		return ErrCartEmpty
+	}
+
+	var shipping interface{}
+	if order.ShippingAddress != nil {
+		address, err := json.Marshal(order.ShippingAddress)
+		if err != nil {
+			return err
+		}
+		shipping = address
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	_, err = tx.Exec(
+		`INSERT INTO orders (id, user_id, status, shipping_address, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $5)`,
+		order.ID, order.UserID, models.StatusPendingPayment, shipping, now,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		variantID, variantSKU, variantAttributes, err := variantValues(item.Variant)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(
+			`INSERT INTO order_items (id, order_id, product_id, variant_id, variant_sku, variant_attributes, quantity,
+				reservation_id, unit_price, product_name, created_at, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, NULLIF($10, ''), $11, $11)`,
+			uuid.New().String(), order.ID, item.ProductID, variantID, variantSKU, variantAttributes, item.Quantity,
+			item.ReservationID, item.UnitPrice, item.ProductName, now,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := insertStatusHistory(tx, order.ID, models.StatusCart, models.StatusPendingPayment, actor, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	order.Status, order.CreatedAt, order.UpdatedAt = models.StatusPendingPayment, now, now
+	return nil
+}
+
+// GetCompletedOrders получает список оформленных заказов пользователя
+func (r *PostgresRepository) GetCompletedOrders(userID string, statuses []models.OrderStatus) ([]*models.Order, error) {
+	rows, err := r.db.Query(
+		`SELECT `+orderColumns+`
+		 FROM orders
+		 WHERE user_id = $1 AND status <> $2 AND (cardinality($3::text[]) = 0 OR status = ANY($3))
+		 ORDER BY created_at`,
+		userID, models.StatusCart, pq.Array(statusStrings(statuses)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	completedOrders, err := scanOrders(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(completedOrders) == 0 {
+		return nil, ErrCompletedOrdersNotFound
+	}
+
+	return completedOrders, nil
+}
+
+// UpdateOrderStatus переводит заказ из статуса from в статус to и записывает переход в историю.
+// Смена статуса выполняется условным UPDATE, поэтому из двух конкурентных переходов
+// из одного и того же статуса успешно завершится только один.
+func (r *PostgresRepository) UpdateOrderStatus(orderID string, from, to models.OrderStatus, actor string) (*models.Order, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	order, err := setOrderStatus(tx, orderID, from, to, actor)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		// Отличаем отсутствующий заказ от заказа, статус которого уже изменился
+		var exists bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, orderID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrOrderNotFound
+		}
+		return nil, ErrStatusConflict
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetStatusHistory получает историю смены статусов заказа
+func (r *PostgresRepository) GetStatusHistory(orderID string) ([]*models.StatusTransition, error) {
+	if _, err := r.GetOrderByID(orderID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT id, order_id, from_status, to_status, actor, created_at
+		 FROM order_status_history WHERE order_id = $1 ORDER BY created_at, id`,
+		orderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := make([]*models.StatusTransition, 0)
+	for rows.Next() {
+		transition := &models.StatusTransition{}
+		if err := rows.Scan(&transition.ID, &transition.OrderID, &transition.FromStatus, &transition.ToStatus, &transition.Actor, &transition.CreatedAt); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
+
+// GetOrdersByStatus получает заказы с указанными статусами, не изменявшиеся с момента updatedBefore
+func (r *PostgresRepository) GetOrdersByStatus(statuses []models.OrderStatus, updatedBefore time.Time) ([]*models.Order, error) {
+	rows, err := r.db.Query(
+		`SELECT `+orderColumns+`
+		 FROM orders
+		 WHERE (cardinality($1::text[]) = 0 OR status = ANY($1)) AND updated_at < $2
+		 ORDER BY created_at`,
+		pq.Array(statusStrings(statuses)), updatedBefore.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOrders(rows)
+}
+
+// MarkReservationsReleased выставляет released_at позициям заказа с резервами reservationIDs.
+// Уже отмеченные позиции не меняются.
+func (r *PostgresRepository) MarkReservationsReleased(orderID string, reservationIDs []string) error {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return ErrOrderNotFound
+	}
+
+	now := time.Now().UTC()
+	_, err := r.db.Exec(
+		`UPDATE order_items SET released_at = $1, updated_at = $1
+		 WHERE order_id = $2 AND reservation_id = ANY($3) AND released_at IS NULL`,
+		now, orderID, pq.Array(reservationIDs),
+	)
+	return err
+}
+
+// GetOrdersWithUnreleasedReservations получает заказы с указанными статусами,
+// у которых есть позиции с резервом и пустым released_at
+func (r *PostgresRepository) GetOrdersWithUnreleasedReservations(statuses []models.OrderStatus) ([]*models.Order, error) {
+	rows, err := r.db.Query(
+		`SELECT `+orderColumns+`
+		 FROM orders
+		 WHERE status = ANY($1) AND EXISTS (
+		     SELECT 1 FROM order_items
+		     WHERE order_items.order_id = orders.id AND order_items.reservation_id IS NOT NULL
+		       AND order_items.reservation_id <> '' AND order_items.released_at IS NULL)
+		 ORDER BY created_at`,
+		pq.Array(statusStrings(statuses)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanOrders(rows)
+}
+
+// GetOrderByID получает заказ (или корзину) по его ID
+func (r *PostgresRepository) GetOrderByID(orderID string) (*models.Order, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	order, err := scanOrder(r.db.QueryRow(`SELECT `+orderColumns+` FROM orders WHERE id = $1`, orderID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// DeleteOrder удаляет заказ в статусе status вместе с его товарами и историей (удаляются каскадно)
+func (r *PostgresRepository) DeleteOrder(orderID string, status models.OrderStatus) error {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return ErrOrderNotFound
+	}
+
+	result, err := r.db.Exec(`DELETE FROM orders WHERE id = $1 AND status = $2`, orderID, status)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		// Отличаем отсутствующий заказ от заказа, статус которого уже изменился
+		var exists bool
+		if err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, orderID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrOrderNotFound
+		}
+		return ErrStatusConflict
+	}
+
+	return nil
+}
+
+// AnonymizeUserOrders в одной транзакции удаляет корзину пользователя (товары удаляются каскадно),
+// передает его заказы и записи истории псевдониму replacementID и стирает адреса доставки
+func (r *PostgresRepository) AnonymizeUserOrders(userID string, replacementID string) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM orders WHERE user_id = $1 AND status = $2`, userID, models.StatusCart); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE order_status_history SET actor = $2 WHERE actor = $1`, userID, replacementID); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(`UPDATE orders SET user_id = $2, shipping_address = NULL WHERE user_id = $1`, userID, replacementID)
+	if err != nil {
+		return 0, err
+	}
+	anonymized, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(anonymized), nil
+}
+
+// lockCart блокирует строку заказа до конца транзакции и проверяет, что это еще корзина
+func lockCart(tx *sql.Tx, orderID string) error {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return ErrOrderNotFound
+	}
+
+	var status models.OrderStatus
+	err := tx.QueryRow(`SELECT status FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrOrderNotFound
+		}
+		return err
+	}
+	if status != models.StatusCart {
+		return ErrOrderCheckedOut
+	}
+
+	return nil
+}
+
+// setOrderStatus выполняет условную смену статуса заказа и добавляет запись в историю.
+// Если заказ не найден или его статус отличается от from, возвращается sql.ErrNoRows.
+func setOrderStatus(tx *sql.Tx, orderID string, from, to models.OrderStatus, actor string) (*models.Order, error) {
+	now := time.Now().UTC()
+
+	order, err := scanOrder(tx.QueryRow(
+		`UPDATE orders SET status = $1, updated_at = $2
+		 WHERE id = $3 AND status = $4
+		 RETURNING `+orderColumns,
+		to, now, orderID, from,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertStatusHistory(tx, orderID, from, to, actor, now); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// insertStatusHistory добавляет запись о переходе заказа из статуса from в статус to
+func insertStatusHistory(tx *sql.Tx, orderID string, from, to models.OrderStatus, actor string, now time.Time) error {
+	_, err := tx.Exec(
+		`INSERT INTO order_status_history (id, order_id, from_status, to_status, actor, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.New().String(), orderID, from, to, actor, now,
+	)
+	return err
+}
+
+// queryer - общий интерфейс *sql.DB и *sql.Tx для чтения
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// queryItems получает товары заказа в порядке добавления
+func queryItems(q queryer, orderID string) ([]*models.OrderItem, error) {
+	rows, err := q.Query(
+		`SELECT `+itemColumns+`
+		 FROM order_items WHERE order_id = $1 ORDER BY created_at, id`,
+		orderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.OrderItem, 0)
+	for rows.Next() {
+		item, err := scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// variantValues возвращает значения столбцов variant_id, variant_sku и variant_attributes позиции.
+// Для продукта без вариантов (variant == nil) variant_id равен 0, а артикул и атрибуты - NULL.
+func variantValues(variant *models.ItemVariant) (int, interface{}, interface{}, error) {
+	if variant == nil {
+		return 0, nil, nil, nil
+	}
+	attributes, err := json.Marshal(variant.Attributes)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return variant.ID, variant.SKU, string(attributes), nil
+}
+
+// scanOrders считывает заказы из результата запроса и закрывает его
+func scanOrders(rows *sql.Rows) ([]*models.Order, error) {
+	defer rows.Close()
+
+	orders := make([]*models.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanOrder считывает заказ из строки с набором столбцов orderColumns
+func scanOrder(row rowScanner) (*models.Order, error) {
+	order := &models.Order{}
+	var shipping []byte
+	if err := row.Scan(&order.ID, &order.UserID, &order.Status, &shipping, &order.CreatedAt, &order.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if shipping != nil {
+		order.ShippingAddress = &models.ShippingAddress{}
+		if err := json.Unmarshal(shipping, order.ShippingAddress); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// scanItem считывает товар заказа из строки с набором столбцов itemColumns
+func scanItem(row rowScanner) (*models.OrderItem, error) {
+	item := &models.OrderItem{}
+	var variantID int
+	var variantSKU sql.NullString
+	var variantAttributes []byte
+	var unitPrice sql.NullFloat64
+	var releasedAt sql.NullTime
+	err := row.Scan(&item.ID, &item.OrderID, &item.ProductID, &variantID, &variantSKU, &variantAttributes, &item.Quantity,
+		&item.ReservationID, &unitPrice, &item.ProductName, &releasedAt, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if unitPrice.Valid {
+		item.UnitPrice = &unitPrice.Float64
+	}
+	if releasedAt.Valid {
+		item.ReleasedAt = &releasedAt.Time
+	}
+	if variantID != 0 {
+		item.Variant = &models.ItemVariant{ID: variantID, SKU: variantSKU.String}
+		if variantAttributes != nil {
+			if err := json.Unmarshal(variantAttributes, &item.Variant.Attributes); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return item, nil
+}
+
+// statusStrings преобразует статусы в строки для передачи массивом в запрос
+func statusStrings(statuses []models.OrderStatus) []string {
+	result := make([]string, len(statuses))
+	for i, status := range statuses {
+		result[i] = string(status)
+	}
+	return result
+}
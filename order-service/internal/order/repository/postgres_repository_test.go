@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// TestPostgresRepositoryContract запускается только при заданной переменной ORDER_TEST_DB_DSN,
+// например: ORDER_TEST_DB_DSN="host=localhost port=5434 user=postgres password=postgres dbname=order_service_db sslmode=disable"
+func TestPostgresRepositoryContract(t *testing.T) {
+	dsn := os.Getenv("ORDER_TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("ORDER_TEST_DB_DSN не задан, тесты PostgreSQL репозитория пропущены")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile("../../../db/init.sql")
+	if err != nil {
+		t.Fatalf("чтение схемы: %v", err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("применение схемы: %v", err)
+	}
+
+	runRepositoryContract(t, func(t *testing.T) Repository {
+		return NewPostgresRepository(db)
+	})
+}
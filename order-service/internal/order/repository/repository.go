@@ -1,19 +1,36 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
 )
 
+var (
+	ErrOrderNotFound           = errors.New("заказ не найден")
+	ErrOrderCheckedOut         = errors.New("заказ уже оформлен")
+	ErrItemNotInCart           = errors.New("товар не найден в корзине")
+	ErrCartNotFound            = errors.New("корзина не найдена")
+	ErrNoActiveCart            = errors.New("нет активной корзины")
+	ErrCartEmpty               = errors.New("корзина пуста")
+	ErrCompletedOrdersNotFound = errors.New("выполненные заказы не найдены")
+	ErrStatusConflict          = errors.New("статус заказа был изменен параллельным запросом")
+	ErrCartChanged             = errors.New("корзина изменилась во время оформления заказа")
+)
+
 // Repository представляет интерфейс для работы с хранилищем заказов
 type Repository interface {
 	// GetOrCreateCart получает или создает корзину для пользователя
 	GetOrCreateCart(userID string) (*models.Order, error)
 
-	// AddItemToCart добавляет товар в корзину
-	AddItemToCart(orderID string, productID int, quantity int) (*models.OrderItem, error)
+	// AddItemToCart добавляет товар (вариант variant, nil - продукт без вариантов) в корзину.
+	// Разные варианты одного продукта - разные позиции; для уже добавленного варианта увеличивается количество
+	// и обновляется сохраненный артикул и атрибуты.
+	AddItemToCart(orderID string, productID int, variant *models.ItemVariant, quantity int) (*models.OrderItem, error)
 
-	// RemoveItemFromCart удаляет товар из корзины
-	RemoveItemFromCart(orderID string, productID int) error
+	// RemoveItemFromCart удаляет товар из корзины (variantID 0 - продукт без вариантов)
+	RemoveItemFromCart(orderID string, productID, variantID int) error
 
 	// GetCartItems получает список товаров в корзине
 	GetCartItems(orderID string) ([]*models.OrderItem, error)
@@ -21,9 +38,70 @@ type Repository interface {
 	// GetCartByUserID получает корзину пользователя по его ID
 	GetCartByUserID(userID string) (*models.Order, error)
 
-	// CheckoutCart выполняет оформление заказа
-	CheckoutCart(orderID string) error
+	// CheckoutCart выполняет оформление заказа: сохраняет в позициях корзины ID резервов product-service, цены
+	// и названия товаров из reserved, переводит корзину в статус PENDING_PAYMENT, сохраняет адрес доставки shipping (может быть nil)
+	// и записывает переход в историю от имени actor - все в одной транзакции.
+	// reserved - позиции, зарезервированные вызывающим, с ReservationID и UnitPrice. Если позиции корзины
+	// к моменту оформления отличаются от reserved (товар добавлен или удален параллельно) или у какой-то позиции
+	// нет резерва или цены, возвращается ErrCartChanged, и корзина не меняется: вызывающий должен отменить свои резервы.
+	CheckoutCart(orderID string, reserved []*models.OrderItem, actor string, shipping *models.ShippingAddress) error
+
+	// CreateOrder в одной транзакции сохраняет заказ order в статусе PENDING_PAYMENT вместе с товарами items
+	// и записывает переход CART -> PENDING_PAYMENT в историю от имени actor. ID заказа задает вызывающий
+	// (под ним уже созданы резервы), цены и названия позиций - UnitPrice и ProductName; статус и время создания репозиторий записывает в order.
+	// Корзина пользователя не затрагивается. Если items пуст, возвращается ErrCartEmpty.
+	CreateOrder(order *models.Order, items []*models.OrderItem, actor string) error
+
+	// GetCompletedOrders получает список оформленных заказов пользователя.
+	// Если statuses не пуст, возвращаются только заказы с указанными статусами.
+	GetCompletedOrders(userID string, statuses []models.OrderStatus) ([]*models.Order, error)
+
+	// UpdateOrderStatus переводит заказ из статуса from в статус to и записывает переход в историю.
+	// Если текущий статус заказа отличается от from, возвращается ErrStatusConflict.
+	UpdateOrderStatus(orderID string, from, to models.OrderStatus, actor string) (*models.Order, error)
+
+	// GetStatusHistory получает историю смены статусов заказа в хронологическом порядке
+	GetStatusHistory(orderID string) ([]*models.StatusTransition, error)
+
+	// GetOrdersByStatus получает заказы с указанными статусами, не изменявшиеся с момента updatedBefore
+	GetOrdersByStatus(statuses []models.OrderStatus, updatedBefore time.Time) ([]*models.Order, error)
+
+	// MarkReservationsReleased отмечает резервы reservationIDs позиций заказа как отмененные в product-service
+	MarkReservationsReleased(orderID string, reservationIDs []string) error
+
+	// GetOrdersWithUnreleasedReservations получает заказы с указанными статусами, у позиций которых
+	// остались неотмененные резервы
+	GetOrdersWithUnreleasedReservations(statuses []models.OrderStatus) ([]*models.Order, error)
+
+	// GetOrderByID получает заказ (или корзину) по его ID
+	GetOrderByID(orderID string) (*models.Order, error)
+
+	// DeleteOrder удаляет заказ вместе с его товарами и историей, если его статус по-прежнему status.
+	// Если статус заказа изменился, возвращается ErrStatusConflict.
+	DeleteOrder(orderID string, status models.OrderStatus) error
+
+	// AnonymizeUserOrders удаляет активную корзину пользователя и передает его оформленные заказы
+	// псевдониму replacementID, заменяя userID и в истории статусов, и стирает адреса доставки.
+	// Возвращает количество переданных заказов.
+	AnonymizeUserOrders(userID string, replacementID string) (int, error)
+}
 
-	// GetCompletedOrders получает список выполненных заказов пользователя
-	GetCompletedOrders(userID string) ([]*models.Order, error)
+// matchReservedItems проверяет, что позиции корзины stored совпадают с зарезервированными позициями reserved:
+// те же товары и варианты в том же количестве, и у каждой позиции есть резерв и зафиксированная цена
+func matchReservedItems(stored, reserved []*models.OrderItem) bool {
+	if len(stored) != len(reserved) {
+		return false
+	}
+	type itemKey struct{ productID, variantID int }
+	byKey := make(map[itemKey]*models.OrderItem, len(reserved))
+	for _, item := range reserved {
+		byKey[itemKey{item.ProductID, item.VariantID()}] = item
+	}
+	for _, item := range stored {
+		want, ok := byKey[itemKey{item.ProductID, item.VariantID()}]
+		if !ok || want.ReservationID == "" || want.UnitPrice == nil || item.Quantity != want.Quantity {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,948 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+	"github.com/google/uuid"
+)
+
+// runRepositoryContract проверяет поведение, общее для всех реализаций Repository.
+// Каждый подтест работает со своим пользователем, поэтому реализации могут
+// переиспользовать одно хранилище между подтестами.
+func runRepositoryContract(t *testing.T, newRepo func(t *testing.T) Repository) {
+	t.Run("GetOrCreateCartReturnsSameCart", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+
+		first, err := repo.GetOrCreateCart(userID)
+		if err != nil {
+			t.Fatalf("GetOrCreateCart: %v", err)
+		}
+		second, err := repo.GetOrCreateCart(userID)
+		if err != nil {
+			t.Fatalf("GetOrCreateCart (повторно): %v", err)
+		}
+
+		if first.ID != second.ID {
+			t.Fatalf("ожидалась одна корзина, получены %s и %s", first.ID, second.ID)
+		}
+		if first.UserID != userID || first.Status != models.StatusCart {
+			t.Fatalf("некорректная корзина: %+v", first)
+		}
+	})
+
+	t.Run("AddItemToCartMergesQuantity", func(t *testing.T) {
+		repo := newRepo(t)
+		cart := mustCart(t, repo, newUserID())
+
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 2); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		item, err := repo.AddItemToCart(cart.ID, 1, nil, 3)
+		if err != nil {
+			t.Fatalf("AddItemToCart (повторно): %v", err)
+		}
+		if item.Quantity != 5 {
+			t.Fatalf("ожидалось количество 5, получено %d", item.Quantity)
+		}
+		if item.UnitPrice != nil {
+			t.Fatalf("цена позиции корзины зафиксирована до оформления: %v", *item.UnitPrice)
+		}
+		if _, err := repo.AddItemToCart(cart.ID, 2, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart (другой товар): %v", err)
+		}
+
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("ожидалось 2 позиции, получено %d", len(items))
+		}
+		quantities := map[int]int{}
+		for _, item := range items {
+			quantities[item.ProductID] = item.Quantity
+		}
+		if quantities[1] != 5 || quantities[2] != 1 {
+			t.Fatalf("некорректное содержимое корзины: %v", quantities)
+		}
+	})
+
+	t.Run("VariantsAreSeparateItems", func(t *testing.T) {
+		repo := newRepo(t)
+		cart := mustCart(t, repo, newUserID())
+
+		small := &models.ItemVariant{ID: 11, SKU: "TSHIRT-S", Attributes: map[string]string{"size": "S"}}
+		large := &models.ItemVariant{ID: 13, SKU: "TSHIRT-L", Attributes: map[string]string{"size": "L"}}
+		if _, err := repo.AddItemToCart(cart.ID, 1, small, 1); err != nil {
+			t.Fatalf("AddItemToCart (S): %v", err)
+		}
+		if _, err := repo.AddItemToCart(cart.ID, 1, large, 2); err != nil {
+			t.Fatalf("AddItemToCart (L): %v", err)
+		}
+		item, err := repo.AddItemToCart(cart.ID, 1, small, 3)
+		if err != nil {
+			t.Fatalf("AddItemToCart (S повторно): %v", err)
+		}
+		if item.Quantity != 4 || item.Variant == nil || item.Variant.ID != small.ID {
+			t.Fatalf("ожидался вариант S с количеством 4, получено %+v", item)
+		}
+
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("ожидалось 2 позиции, получено %d", len(items))
+		}
+		for _, item := range items {
+			if item.Variant == nil {
+				t.Fatalf("у позиции потерян вариант: %+v", item)
+			}
+			switch item.Variant.ID {
+			case small.ID:
+				if item.Quantity != 4 || item.Variant.SKU != "TSHIRT-S" || item.Variant.Attributes["size"] != "S" {
+					t.Fatalf("некорректная позиция S: %+v %+v", item, item.Variant)
+				}
+			case large.ID:
+				if item.Quantity != 2 || item.Variant.Attributes["size"] != "L" {
+					t.Fatalf("некорректная позиция L: %+v %+v", item, item.Variant)
+				}
+			default:
+				t.Fatalf("неожиданный вариант %d", item.Variant.ID)
+			}
+		}
+
+		if err := repo.RemoveItemFromCart(cart.ID, 1, small.ID); err != nil {
+			t.Fatalf("RemoveItemFromCart: %v", err)
+		}
+		if err := repo.RemoveItemFromCart(cart.ID, 1, small.ID); !errors.Is(err, ErrItemNotInCart) {
+			t.Fatalf("ожидалась ошибка ErrItemNotInCart, получено %v", err)
+		}
+		items, err = repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if len(items) != 1 || items[0].VariantID() != large.ID {
+			t.Fatalf("ожидалась одна позиция L, получено %+v", items)
+		}
+
+		// Резерв сохраняется в позиции своего варианта
+		if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "res"), "tester", nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+		items, err = repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if want := fmt.Sprintf("res-1-%d", large.ID); len(items) != 1 || items[0].ReservationID != want {
+			t.Fatalf("ожидался резерв %s позиции L, получено %+v", want, items)
+		}
+	})
+
+	t.Run("ConcurrentAddItemToCartIsAtomic", func(t *testing.T) {
+		repo := newRepo(t)
+		cart := mustCart(t, repo, newUserID())
+
+		const workers = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := repo.AddItemToCart(cart.ID, 7, nil, 1); err != nil {
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if len(items) != 1 || items[0].Quantity != workers {
+			t.Fatalf("ожидалась одна позиция с количеством %d, получено %+v", workers, items)
+		}
+	})
+
+	t.Run("RemoveItemFromCart", func(t *testing.T) {
+		repo := newRepo(t)
+		cart := mustCart(t, repo, newUserID())
+
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.RemoveItemFromCart(cart.ID, 1, 0); err != nil {
+			t.Fatalf("RemoveItemFromCart: %v", err)
+		}
+		if err := repo.RemoveItemFromCart(cart.ID, 1, 0); !errors.Is(err, ErrItemNotInCart) {
+			t.Fatalf("ожидалась ошибка ErrItemNotInCart, получено %v", err)
+		}
+
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if len(items) != 0 {
+			t.Fatalf("ожидалась пустая корзина, получено %d позиций", len(items))
+		}
+	})
+
+	t.Run("CheckoutCartSavesReservations", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 2); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+
+		// Позиция без резерва или без цены не оформляется
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, items, userID, nil); !errors.Is(err, ErrCartChanged) {
+			t.Fatalf("позиция без резерва: ожидалась ошибка ErrCartChanged, получено %v", err)
+		}
+		items[0].ReservationID = "res-1"
+		if err := repo.CheckoutCart(cart.ID, items, userID, nil); !errors.Is(err, ErrCartChanged) {
+			t.Fatalf("позиция без цены: ожидалась ошибка ErrCartChanged, получено %v", err)
+		}
+
+		price := 99.9
+		items[0].UnitPrice = &price
+		items[0].ProductName = "Футболка"
+		if err := repo.CheckoutCart(cart.ID, items, userID, nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+		items, err = repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if len(items) != 1 || items[0].ReservationID != "res-1" {
+			t.Fatalf("ожидался резерв res-1, получено %+v", items)
+		}
+		if items[0].UnitPrice == nil || *items[0].UnitPrice != 99.9 {
+			t.Fatalf("ожидалась цена 99.9, получено %v", items[0].UnitPrice)
+		}
+		if items[0].ProductName != "Футболка" {
+			t.Fatalf("ожидалось название Футболка, получено %q", items[0].ProductName)
+		}
+	})
+
+	t.Run("GetCartByUserID", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+
+		if _, err := repo.GetCartByUserID(userID); !errors.Is(err, ErrCartNotFound) {
+			t.Fatalf("ожидалась ошибка ErrCartNotFound, получено %v", err)
+		}
+
+		cart := mustCart(t, repo, userID)
+		found, err := repo.GetCartByUserID(userID)
+		if err != nil {
+			t.Fatalf("GetCartByUserID: %v", err)
+		}
+		if found.ID != cart.ID {
+			t.Fatalf("ожидалась корзина %s, получена %s", cart.ID, found.ID)
+		}
+	})
+
+	t.Run("CheckoutCart", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+
+		if err := repo.CheckoutCart(cart.ID, nil, userID, nil); !errors.Is(err, ErrCartEmpty) {
+			t.Fatalf("ожидалась ошибка ErrCartEmpty, получено %v", err)
+		}
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		shipping := newShippingAddress()
+		if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "res"), userID, shipping); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+
+		order, err := repo.GetOrderByID(cart.ID)
+		if err != nil {
+			t.Fatalf("GetOrderByID: %v", err)
+		}
+		if order.Status != models.StatusPendingPayment {
+			t.Fatalf("ожидался статус %s, получен %s", models.StatusPendingPayment, order.Status)
+		}
+		if order.ShippingAddress == nil || *order.ShippingAddress != *shipping {
+			t.Fatalf("ожидался адрес доставки %+v, получен %+v", shipping, order.ShippingAddress)
+		}
+
+		history, err := repo.GetStatusHistory(cart.ID)
+		if err != nil {
+			t.Fatalf("GetStatusHistory: %v", err)
+		}
+		if len(history) != 1 || history[0].FromStatus != models.StatusCart ||
+			history[0].ToStatus != models.StatusPendingPayment || history[0].Actor != userID {
+			t.Fatalf("неожиданная история статусов: %+v", history)
+		}
+
+		if err := repo.CheckoutCart(cart.ID, nil, userID, nil); !errors.Is(err, ErrOrderCheckedOut) {
+			t.Fatalf("ожидалась ошибка ErrOrderCheckedOut, получено %v", err)
+		}
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); !errors.Is(err, ErrOrderCheckedOut) {
+			t.Fatalf("ожидалась ошибка ErrOrderCheckedOut при добавлении, получено %v", err)
+		}
+		if err := repo.RemoveItemFromCart(cart.ID, 1, 0); !errors.Is(err, ErrOrderCheckedOut) {
+			t.Fatalf("ожидалась ошибка ErrOrderCheckedOut при удалении, получено %v", err)
+		}
+		if _, err := repo.GetCartByUserID(userID); !errors.Is(err, ErrNoActiveCart) {
+			t.Fatalf("ожидалась ошибка ErrNoActiveCart, получено %v", err)
+		}
+
+		next, err := repo.GetOrCreateCart(userID)
+		if err != nil {
+			t.Fatalf("GetOrCreateCart после оформления: %v", err)
+		}
+		if next.ID == cart.ID {
+			t.Fatal("после оформления должна создаваться новая корзина")
+		}
+	})
+
+	t.Run("ConcurrentCheckoutSucceedsOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+
+		reserved := reserveCart(t, repo, cart.ID, "res")
+
+		const workers = 5
+		var wg sync.WaitGroup
+		results := make(chan error, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results <- repo.CheckoutCart(cart.ID, reserved, userID, nil)
+			}()
+		}
+		wg.Wait()
+		close(results)
+
+		succeeded := 0
+		for err := range results {
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrOrderCheckedOut):
+			default:
+				t.Fatalf("CheckoutCart: %v", err)
+			}
+		}
+		if succeeded != 1 {
+			t.Fatalf("ожидалось ровно одно успешное оформление, получено %d", succeeded)
+		}
+	})
+
+	t.Run("CheckoutCartRejectsCartChangedAfterReservation", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		for productID := 1; productID <= 2; productID++ {
+			if _, err := repo.AddItemToCart(cart.ID, productID, nil, 1); err != nil {
+				t.Fatalf("AddItemToCart: %v", err)
+			}
+		}
+
+		// Пока шло резервирование, в корзину добавили еще единицу уже зарезервированного товара
+		reserved := reserveCart(t, repo, cart.ID, "res")
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, reserved, userID, nil); !errors.Is(err, ErrCartChanged) {
+			t.Fatalf("добавление количества: ожидалась ошибка ErrCartChanged, получено %v", err)
+		}
+
+		// Позицию с подтвержденным резервом удалили из корзины
+		reserved = reserveCart(t, repo, cart.ID, "res")
+		if err := repo.RemoveItemFromCart(cart.ID, 2, 0); err != nil {
+			t.Fatalf("RemoveItemFromCart: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, reserved, userID, nil); !errors.Is(err, ErrCartChanged) {
+			t.Fatalf("удаление позиции: ожидалась ошибка ErrCartChanged, получено %v", err)
+		}
+
+		// Добавили новый товар, для которого резерва нет
+		reserved = reserveCart(t, repo, cart.ID, "res")
+		if _, err := repo.AddItemToCart(cart.ID, 3, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, reserved, userID, nil); !errors.Is(err, ErrCartChanged) {
+			t.Fatalf("новая позиция: ожидалась ошибка ErrCartChanged, получено %v", err)
+		}
+
+		order, err := repo.GetOrderByID(cart.ID)
+		if err != nil {
+			t.Fatalf("GetOrderByID: %v", err)
+		}
+		if order.Status != models.StatusCart {
+			t.Fatalf("отклоненное оформление изменило статус на %s", order.Status)
+		}
+		// Отклоненное оформление не оставляет в корзине резервов и цен
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		for _, item := range items {
+			if item.ReservationID != "" || item.UnitPrice != nil {
+				t.Fatalf("в позиции корзины остались резерв и цена: %+v", item)
+			}
+		}
+
+		// Оформление с актуальными резервами проходит
+		if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "res"), userID, nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+	})
+
+	t.Run("DoubleCheckoutKeepsWinnerReservations", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		for productID := 1; productID <= 2; productID++ {
+			if _, err := repo.AddItemToCart(cart.ID, productID, nil, 1); err != nil {
+				t.Fatalf("AddItemToCart: %v", err)
+			}
+		}
+
+		// Оба оформления успели зарезервировать товар; второе должно отменить свои резервы,
+		// а в заказе остаются резервы первого
+		first := reserveCart(t, repo, cart.ID, "first")
+		second := reserveCart(t, repo, cart.ID, "second")
+		if err := repo.CheckoutCart(cart.ID, first, userID, nil); err != nil {
+			t.Fatalf("первое оформление: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, second, userID, nil); !errors.Is(err, ErrOrderCheckedOut) {
+			t.Fatalf("второе оформление: ожидалась ошибка ErrOrderCheckedOut, получено %v", err)
+		}
+
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		for _, item := range items {
+			if want := fmt.Sprintf("first-%d-0", item.ProductID); item.ReservationID != want {
+				t.Fatalf("товар %d: ожидался резерв %s, сохранен %s", item.ProductID, want, item.ReservationID)
+			}
+		}
+	})
+
+	t.Run("CreateOrderLeavesCartUntouched", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+
+		shipping := newShippingAddress()
+		order := &models.Order{ID: uuid.New().String(), UserID: userID, ShippingAddress: shipping}
+		price2, price3 := 100.0, 249.5
+		items := []*models.OrderItem{
+			{ProductID: 2, Quantity: 3, ReservationID: "res-2", UnitPrice: &price2, ProductName: "Кружка"},
+			{ProductID: 3, Variant: &models.ItemVariant{ID: 7, SKU: "CAP-RED", Attributes: map[string]string{"color": "red"}},
+				Quantity: 1, ReservationID: "res-3", UnitPrice: &price3},
+		}
+		if err := repo.CreateOrder(order, items, userID); err != nil {
+			t.Fatalf("CreateOrder: %v", err)
+		}
+		if order.Status != models.StatusPendingPayment || order.CreatedAt.IsZero() {
+			t.Fatalf("CreateOrder не заполнил статус и время создания: %+v", order)
+		}
+
+		stored, err := repo.GetOrderByID(order.ID)
+		if err != nil {
+			t.Fatalf("GetOrderByID: %v", err)
+		}
+		if stored.UserID != userID || stored.Status != models.StatusPendingPayment {
+			t.Fatalf("некорректный заказ: %+v", stored)
+		}
+		if stored.ShippingAddress == nil || *stored.ShippingAddress != *shipping {
+			t.Fatalf("адрес доставки не сохранен: %+v", stored.ShippingAddress)
+		}
+
+		orderItems, err := repo.GetCartItems(order.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		if len(orderItems) != 2 {
+			t.Fatalf("ожидалось 2 позиции заказа, получено %d", len(orderItems))
+		}
+		byProduct := map[int]*models.OrderItem{}
+		for _, item := range orderItems {
+			if item.ID == "" || item.OrderID != order.ID {
+				t.Fatalf("позиция без ID или с чужим заказом: %+v", item)
+			}
+			byProduct[item.ProductID] = item
+		}
+		if item := byProduct[2]; item == nil || item.Quantity != 3 || item.ReservationID != "res-2" || item.Variant != nil ||
+			item.UnitPrice == nil || *item.UnitPrice != 100 || item.ProductName != "Кружка" {
+			t.Fatalf("некорректная позиция товара 2: %+v", item)
+		}
+		if item := byProduct[3]; item == nil || item.VariantID() != 7 || item.Variant.SKU != "CAP-RED" ||
+			item.Variant.Attributes["color"] != "red" || item.ReservationID != "res-3" ||
+			item.UnitPrice == nil || *item.UnitPrice != 249.5 || item.ProductName != "" {
+			t.Fatalf("некорректная позиция товара 3: %+v", item)
+		}
+
+		history, err := repo.GetStatusHistory(order.ID)
+		if err != nil {
+			t.Fatalf("GetStatusHistory: %v", err)
+		}
+		if len(history) != 1 || history[0].FromStatus != models.StatusCart ||
+			history[0].ToStatus != models.StatusPendingPayment || history[0].Actor != userID {
+			t.Fatalf("некорректная история заказа: %+v", history)
+		}
+
+		// Корзина осталась активной и содержит только то, что было в ней до заказа
+		current, err := repo.GetCartByUserID(userID)
+		if err != nil {
+			t.Fatalf("GetCartByUserID: %v", err)
+		}
+		if current.ID != cart.ID {
+			t.Fatalf("активная корзина сменилась: %s вместо %s", current.ID, cart.ID)
+		}
+		cartItems, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems (корзина): %v", err)
+		}
+		if len(cartItems) != 1 || cartItems[0].ProductID != 1 {
+			t.Fatalf("содержимое корзины изменилось: %+v", cartItems)
+		}
+
+		if err := repo.CreateOrder(&models.Order{ID: uuid.New().String(), UserID: userID}, nil, userID); !errors.Is(err, ErrCartEmpty) {
+			t.Fatalf("CreateOrder без товаров: ожидалась ошибка ErrCartEmpty, получено %v", err)
+		}
+	})
+
+	t.Run("GetCompletedOrders", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+
+		if _, err := repo.GetCompletedOrders(userID, nil); !errors.Is(err, ErrCompletedOrdersNotFound) {
+			t.Fatalf("ожидалась ошибка ErrCompletedOrdersNotFound, получено %v", err)
+		}
+
+		checkedOut := map[string]bool{}
+		for i := 0; i < 2; i++ {
+			cart := mustCart(t, repo, userID)
+			if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+				t.Fatalf("AddItemToCart: %v", err)
+			}
+			if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "res"), userID, nil); err != nil {
+				t.Fatalf("CheckoutCart: %v", err)
+			}
+			checkedOut[cart.ID] = true
+		}
+		// Активная корзина не должна попадать в выполненные заказы
+		mustCart(t, repo, userID)
+
+		orders, err := repo.GetCompletedOrders(userID, nil)
+		if err != nil {
+			t.Fatalf("GetCompletedOrders: %v", err)
+		}
+		if len(orders) != len(checkedOut) {
+			t.Fatalf("ожидалось %d заказа, получено %d", len(checkedOut), len(orders))
+		}
+		for _, order := range orders {
+			if !checkedOut[order.ID] || order.Status != models.StatusPendingPayment {
+				t.Fatalf("неожиданный заказ в списке: %+v", order)
+			}
+		}
+
+		// Фильтр по статусу
+		paid, err := repo.UpdateOrderStatus(orders[0].ID, models.StatusPendingPayment, models.StatusPaid, "tester")
+		if err != nil {
+			t.Fatalf("UpdateOrderStatus: %v", err)
+		}
+		filtered, err := repo.GetCompletedOrders(userID, []models.OrderStatus{models.StatusPaid})
+		if err != nil {
+			t.Fatalf("GetCompletedOrders с фильтром: %v", err)
+		}
+		if len(filtered) != 1 || filtered[0].ID != paid.ID {
+			t.Fatalf("ожидался только оплаченный заказ %s, получено %+v", paid.ID, filtered)
+		}
+		if _, err := repo.GetCompletedOrders(userID, []models.OrderStatus{models.StatusRefunded}); !errors.Is(err, ErrCompletedOrdersNotFound) {
+			t.Fatalf("ожидалась ошибка ErrCompletedOrdersNotFound для фильтра, получено %v", err)
+		}
+	})
+
+	t.Run("UpdateOrderStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "res"), userID, nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+
+		order, err := repo.UpdateOrderStatus(cart.ID, models.StatusPendingPayment, models.StatusPaid, "payments")
+		if err != nil {
+			t.Fatalf("UpdateOrderStatus: %v", err)
+		}
+		if order.Status != models.StatusPaid {
+			t.Fatalf("ожидался статус %s, получен %s", models.StatusPaid, order.Status)
+		}
+
+		// Переход из устаревшего статуса должен завершаться конфликтом
+		if _, err := repo.UpdateOrderStatus(cart.ID, models.StatusPendingPayment, models.StatusCancelled, "ops"); !errors.Is(err, ErrStatusConflict) {
+			t.Fatalf("ожидалась ошибка ErrStatusConflict, получено %v", err)
+		}
+		if _, err := repo.UpdateOrderStatus(uuid.New().String(), models.StatusPaid, models.StatusFulfilled, "ops"); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("ожидалась ошибка ErrOrderNotFound, получено %v", err)
+		}
+
+		history, err := repo.GetStatusHistory(cart.ID)
+		if err != nil {
+			t.Fatalf("GetStatusHistory: %v", err)
+		}
+		if len(history) != 2 {
+			t.Fatalf("ожидалось 2 записи в истории, получено %d", len(history))
+		}
+		last := history[1]
+		if last.FromStatus != models.StatusPendingPayment || last.ToStatus != models.StatusPaid || last.Actor != "payments" || last.CreatedAt.IsZero() {
+			t.Fatalf("неожиданная запись истории: %+v", last)
+		}
+	})
+
+	t.Run("ConcurrentUpdateOrderStatusSucceedsOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "res"), userID, nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+
+		targets := []models.OrderStatus{models.StatusPaid, models.StatusCancelled, models.StatusExpired}
+		var wg sync.WaitGroup
+		results := make(chan error, len(targets))
+		for _, target := range targets {
+			wg.Add(1)
+			go func(target models.OrderStatus) {
+				defer wg.Done()
+				_, err := repo.UpdateOrderStatus(cart.ID, models.StatusPendingPayment, target, "worker")
+				results <- err
+			}(target)
+		}
+		wg.Wait()
+		close(results)
+
+		succeeded := 0
+		for err := range results {
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrStatusConflict):
+			default:
+				t.Fatalf("UpdateOrderStatus: %v", err)
+			}
+		}
+		if succeeded != 1 {
+			t.Fatalf("ожидался ровно один успешный переход, получено %d", succeeded)
+		}
+	})
+
+	t.Run("GetOrdersByStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "res"), userID, nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+
+		pending := []models.OrderStatus{models.StatusPendingPayment}
+		orders, err := repo.GetOrdersByStatus(pending, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("GetOrdersByStatus: %v", err)
+		}
+		if containsOrder(orders, cart.ID) {
+			t.Fatal("недавно оформленный заказ не должен попадать в выборку за прошлый час")
+		}
+
+		orders, err = repo.GetOrdersByStatus(pending, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("GetOrdersByStatus: %v", err)
+		}
+		if !containsOrder(orders, cart.ID) {
+			t.Fatal("ожидался оформленный заказ в выборке")
+		}
+
+		orders, err = repo.GetOrdersByStatus([]models.OrderStatus{models.StatusPaid}, time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("GetOrdersByStatus: %v", err)
+		}
+		if containsOrder(orders, cart.ID) {
+			t.Fatal("заказ с другим статусом не должен попадать в выборку")
+		}
+	})
+
+	t.Run("MarkReservationsReleased", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		for _, productID := range []int{1, 2} {
+			if _, err := repo.AddItemToCart(cart.ID, productID, nil, 1); err != nil {
+				t.Fatalf("AddItemToCart: %v", err)
+			}
+		}
+		if err := repo.CheckoutCart(cart.ID, reserveCart(t, repo, cart.ID, "rel"), userID, nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+		releasing := []models.OrderStatus{models.StatusCancelled}
+
+		// Резервы неоплаченного заказа действуют и не считаются неотмененными
+		orders, err := repo.GetOrdersWithUnreleasedReservations(releasing)
+		if err != nil {
+			t.Fatalf("GetOrdersWithUnreleasedReservations: %v", err)
+		}
+		if containsOrder(orders, cart.ID) {
+			t.Fatal("неоплаченный заказ не должен попадать в выборку")
+		}
+
+		if _, err := repo.UpdateOrderStatus(cart.ID, models.StatusPendingPayment, models.StatusCancelled, userID); err != nil {
+			t.Fatalf("UpdateOrderStatus: %v", err)
+		}
+		if err := repo.MarkReservationsReleased(cart.ID, []string{"rel-1-0"}); err != nil {
+			t.Fatalf("MarkReservationsReleased: %v", err)
+		}
+		orders, err = repo.GetOrdersWithUnreleasedReservations(releasing)
+		if err != nil {
+			t.Fatalf("GetOrdersWithUnreleasedReservations: %v", err)
+		}
+		if !containsOrder(orders, cart.ID) {
+			t.Fatal("ожидался отмененный заказ с неотмененным резервом rel-2-0")
+		}
+
+		if err := repo.MarkReservationsReleased(cart.ID, []string{"rel-2-0"}); err != nil {
+			t.Fatalf("MarkReservationsReleased: %v", err)
+		}
+		orders, err = repo.GetOrdersWithUnreleasedReservations(releasing)
+		if err != nil {
+			t.Fatalf("GetOrdersWithUnreleasedReservations: %v", err)
+		}
+		if containsOrder(orders, cart.ID) {
+			t.Fatal("заказ без неотмененных резервов не должен попадать в выборку")
+		}
+
+		items, err := repo.GetCartItems(cart.ID)
+		if err != nil {
+			t.Fatalf("GetCartItems: %v", err)
+		}
+		for _, item := range items {
+			if item.ReleasedAt == nil {
+				t.Fatalf("у позиции %d не сохранено время отмены резерва", item.ProductID)
+			}
+		}
+	})
+
+	t.Run("DeleteOrder", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+
+		if err := repo.DeleteOrder(cart.ID, models.StatusPendingPayment); !errors.Is(err, ErrStatusConflict) {
+			t.Fatalf("DeleteOrder с другим статусом: ожидалась ошибка ErrStatusConflict, получено %v", err)
+		}
+		if _, err := repo.GetOrderByID(cart.ID); err != nil {
+			t.Fatalf("заказ не должен удаляться при несовпадении статуса: %v", err)
+		}
+
+		if err := repo.DeleteOrder(cart.ID, models.StatusCart); err != nil {
+			t.Fatalf("DeleteOrder: %v", err)
+		}
+		if _, err := repo.GetOrderByID(cart.ID); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("ожидалась ошибка ErrOrderNotFound, получено %v", err)
+		}
+		if _, err := repo.GetCartItems(cart.ID); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("ожидалась ошибка ErrOrderNotFound для товаров, получено %v", err)
+		}
+		if err := repo.DeleteOrder(cart.ID, models.StatusCart); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("ожидалась ошибка ErrOrderNotFound при повторном удалении, получено %v", err)
+		}
+
+		next := mustCart(t, repo, userID)
+		if next.ID == cart.ID {
+			t.Fatal("после удаления корзины должна создаваться новая")
+		}
+	})
+
+	t.Run("AnonymizeUserOrders", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+		replacementID := newUserID()
+
+		order := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(order.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.CheckoutCart(order.ID, reserveCart(t, repo, order.ID, "res"), userID, newShippingAddress()); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+		cart := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(cart.ID, 2, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+
+		anonymized, err := repo.AnonymizeUserOrders(userID, replacementID)
+		if err != nil {
+			t.Fatalf("AnonymizeUserOrders: %v", err)
+		}
+		if anonymized != 1 {
+			t.Fatalf("ожидался 1 обезличенный заказ, получено %d", anonymized)
+		}
+
+		// Корзина удаляется, оформленный заказ сохраняется под псевдонимом
+		if _, err := repo.GetOrderByID(cart.ID); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("ожидалась ошибка ErrOrderNotFound для корзины, получено %v", err)
+		}
+		kept, err := repo.GetOrderByID(order.ID)
+		if err != nil {
+			t.Fatalf("GetOrderByID: %v", err)
+		}
+		if kept.UserID != replacementID {
+			t.Fatalf("ожидался пользователь %s, получен %s", replacementID, kept.UserID)
+		}
+		if kept.ShippingAddress != nil {
+			t.Fatalf("адрес доставки должен быть стерт: %+v", kept.ShippingAddress)
+		}
+		history, err := repo.GetStatusHistory(order.ID)
+		if err != nil {
+			t.Fatalf("GetStatusHistory: %v", err)
+		}
+		for _, transition := range history {
+			if transition.Actor == userID {
+				t.Fatalf("в истории остался ID пользователя: %+v", transition)
+			}
+		}
+		if _, err := repo.GetCompletedOrders(userID, nil); !errors.Is(err, ErrCompletedOrdersNotFound) {
+			t.Fatalf("ожидалась ошибка ErrCompletedOrdersNotFound, получено %v", err)
+		}
+
+		// Повторное обезличивание ничего не меняет
+		if anonymized, err := repo.AnonymizeUserOrders(userID, replacementID); err != nil || anonymized != 0 {
+			t.Fatalf("повторный AnonymizeUserOrders: %d, %v", anonymized, err)
+		}
+	})
+
+	t.Run("AnonymizeUserOrdersWithoutCart", func(t *testing.T) {
+		repo := newRepo(t)
+		userID := newUserID()
+
+		// После оформления новая корзина не создана: удалять нечего, заказ должен сохраниться
+		order := mustCart(t, repo, userID)
+		if _, err := repo.AddItemToCart(order.ID, 1, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+		if err := repo.CheckoutCart(order.ID, reserveCart(t, repo, order.ID, "res"), userID, nil); err != nil {
+			t.Fatalf("CheckoutCart: %v", err)
+		}
+
+		if anonymized, err := repo.AnonymizeUserOrders(userID, newUserID()); err != nil || anonymized != 1 {
+			t.Fatalf("AnonymizeUserOrders: %d, %v", anonymized, err)
+		}
+		if _, err := repo.GetOrderByID(order.ID); err != nil {
+			t.Fatalf("GetOrderByID: %v", err)
+		}
+	})
+
+	t.Run("UnknownOrder", func(t *testing.T) {
+		repo := newRepo(t)
+		unknownID := uuid.New().String()
+
+		if _, err := repo.GetOrderByID(unknownID); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("GetOrderByID: ожидалась ошибка ErrOrderNotFound, получено %v", err)
+		}
+		if _, err := repo.AddItemToCart(unknownID, 1, nil, 1); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("AddItemToCart: ожидалась ошибка ErrOrderNotFound, получено %v", err)
+		}
+		if err := repo.CheckoutCart(unknownID, nil, "tester", nil); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("CheckoutCart: ожидалась ошибка ErrOrderNotFound, получено %v", err)
+		}
+		if _, err := repo.GetStatusHistory(unknownID); !errors.Is(err, ErrOrderNotFound) {
+			t.Fatalf("GetStatusHistory: ожидалась ошибка ErrOrderNotFound, получено %v", err)
+		}
+	})
+}
+
+// containsOrder проверяет, есть ли заказ id в выборке orders
+func containsOrder(orders []*models.Order, id string) bool {
+	for _, order := range orders {
+		if order.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func newUserID() string {
+	return "user-" + uuid.New().String()
+}
+
+func newShippingAddress() *models.ShippingAddress {
+	return &models.ShippingAddress{
+		AddressID:  uuid.New().String(),
+		Recipient:  "Иван Иванов",
+		Phone:      "+77011234567",
+		Line1:      "ул. Абая, 1",
+		City:       "Алматы",
+		PostalCode: "050000",
+		Country:    "KZ",
+	}
+}
+
+// reserveCart возвращает позиции корзины с резервом "<prefix>-<товар>-<вариант>" и ценой,
+// как их передает в CheckoutCart оформление заказа после резервирования
+func reserveCart(t *testing.T, repo Repository, orderID, prefix string) []*models.OrderItem {
+	t.Helper()
+	items, err := repo.GetCartItems(orderID)
+	if err != nil {
+		t.Fatalf("GetCartItems: %v", err)
+	}
+	price := 100.0
+	for _, item := range items {
+		item.ReservationID = fmt.Sprintf("%s-%d-%d", prefix, item.ProductID, item.VariantID())
+		item.UnitPrice = &price
+	}
+	return items
+}
+
+func mustCart(t *testing.T, repo Repository, userID string) *models.Order {
+	t.Helper()
+	cart, err := repo.GetOrCreateCart(userID)
+	if err != nil {
+		t.Fatalf("GetOrCreateCart: %v", err)
+	}
+	return cart
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/clients"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/repository"
+)
+
+// fakeProductService - product-service для тестов usecase: отдает продукты по цене 100
+// и запоминает, какие резервы отменены. Продукты из removed не найдены,
+// а отмена резервов из failRelease завершается ошибкой 500.
+type fakeProductService struct {
+	mu          sync.Mutex
+	released    []string
+	removed     map[int]bool
+	failRelease map[string]bool
+}
+
+// newTestProductClient запускает fakeProductService и возвращает клиент, настроенный на него
+func newTestProductClient(t *testing.T) (*clients.ProductClient, *fakeProductService) {
+	t.Helper()
+	service := &fakeProductService{removed: make(map[int]bool), failRelease: make(map[string]bool)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(r.PathValue("id"))
+		service.mu.Lock()
+		removed := service.removed[id]
+		service.mu.Unlock()
+		if err != nil || removed {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(clients.Product{ID: id, Name: "Товар " + r.PathValue("id"), Price: 100, Stock: -1})
+	})
+	mux.HandleFunc("POST /api/inventory/reservations/{id}/release", func(w http.ResponseWriter, r *http.Request) {
+		service.mu.Lock()
+		defer service.mu.Unlock()
+		id := r.PathValue("id")
+		if service.failRelease[id] {
+			http.Error(w, "сбой", http.StatusInternalServerError)
+			return
+		}
+		service.released = append(service.released, id)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	t.Setenv("PRODUCT_SERVICE_URL", server.URL)
+	t.Setenv("MOCK_SERVICES", "false")
+	t.Setenv("SERVICE_CLIENT_ID", "")
+	return clients.NewProductClient(), service
+}
+
+// releasedReservations возвращает отмененные резервы в порядке отмены
+func (s *fakeProductService) releasedReservations() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.released...)
+}
+
+// removeProduct удаляет продукт productID из каталога
+func (s *fakeProductService) removeProduct(productID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removed[productID] = true
+}
+
+// failReleaseOf заставляет отмену резерва reservationID завершаться ошибкой
+func (s *fakeProductService) failReleaseOf(reservationID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failRelease[reservationID] = true
+}
+
+// restoreReleaseOf снова разрешает отмену резерва reservationID
+func (s *fakeProductService) restoreReleaseOf(reservationID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failRelease, reservationID)
+}
+
+// errItemsUnavailable - ошибка чтения товаров заказа в failingItemsRepository
+var errItemsUnavailable = errors.New("товары заказа недоступны")
+
+// failingItemsRepository - репозиторий в памяти, в котором чтение товаров заказа завершается ошибкой
+type failingItemsRepository struct {
+	*repository.MemoryRepository
+}
+
+func (r failingItemsRepository) GetCartItems(string) ([]*models.OrderItem, error) {
+	return nil, errItemsUnavailable
+}
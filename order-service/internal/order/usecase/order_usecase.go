@@ -1,57 +1,98 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
+	"github.com/Hayzerr/go-microservice-project/auth"
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/clients"
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/repository"
+	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidQuantity   = errors.New("количество должно быть положительным числом")
+	ErrUserNotFound      = errors.New("пользователь не найден")
+	ErrProductNotFound   = errors.New("товар не найден")
+	ErrInsufficientStock = errors.New("недостаточное количество товара на складе")
+	ErrAccessDenied      = errors.New("нет доступа к заказу")
+	ErrEmailNotVerified  = errors.New("для оформления заказа необходимо подтвердить email")
+	ErrAddressNotFound   = errors.New("адрес доставки не найден")
+	ErrVariantRequired   = errors.New("для товара нужно выбрать вариант")
+	ErrVariantNotFound   = errors.New("вариант товара не найден")
+	ErrOrderNotDeletable = errors.New("заказ в этом статусе нельзя удалить")
+	ErrStockNotReleased  = errors.New("статус заказа изменен, но товар не удалось вернуть на склад: отмена резервов будет повторена")
+)
+
+// CheckoutPolicy задает дополнительные проверки при оформлении заказа
+type CheckoutPolicy struct {
+	// RequireVerifiedEmail запрещает оформление заказа пользователям с неподтвержденным email
+	RequireVerifiedEmail bool
+}
+
 // OrderUseCase представляет реализацию интерфейса UseCase
 type OrderUseCase struct {
 	repo          repository.Repository
 	userClient    *clients.UserClient
 	productClient *clients.ProductClient
+	policy        CheckoutPolicy
 }
 
 // NewOrderUseCase создает новый экземпляр OrderUseCase
-func NewOrderUseCase(repo repository.Repository, userClient *clients.UserClient, productClient *clients.ProductClient) *OrderUseCase {
+func NewOrderUseCase(repo repository.Repository, userClient *clients.UserClient, productClient *clients.ProductClient, policy CheckoutPolicy) *OrderUseCase {
 	return &OrderUseCase{
 		repo:          repo,
 		userClient:    userClient,
 		productClient: productClient,
+		policy:        policy,
 	}
 }
 
-// AddToCart добавляет товар в корзину пользователя
-func (u *OrderUseCase) AddToCart(userID string, productID int, quantity int) error {
+// AddToCart добавляет товар в корзину пользователя.
+// Для продукта с вариантами нужно выбрать вариант variantID; его артикул и атрибуты сохраняются в позиции корзины.
+func (u *OrderUseCase) AddToCart(ctx context.Context, userID string, productID, variantID int, quantity int) error {
 	if quantity <= 0 {
-		return errors.New("количество должно быть положительным числом")
+		return ErrInvalidQuantity
 	}
 
 	// Проверяем существование пользователя
-	user, err := u.userClient.GetUserByID(userID)
-	if err != nil {
-		return fmt.Errorf("ошибка проверки пользователя: %w", err)
-	}
-	if user == nil {
-		return errors.New("пользователь не найден")
+	if err := u.checkUserExists(ctx, userID); err != nil {
+		return err
 	}
 
 	// Проверяем существование товара
-	product, err := u.productClient.GetProductByID(productID)
+	product, err := u.productClient.GetProductByID(ctx, productID)
 	if err != nil {
+		if errors.Is(err, clients.ErrProductNotFound) {
+			return ErrProductNotFound
+		}
 		return fmt.Errorf("ошибка проверки товара: %w", err)
 	}
 	if product == nil {
-		return errors.New("товар не найден")
+		return ErrProductNotFound
 	}
 
-	// Проверяем наличие товара на складе
-	if product.Stock != -1 && product.Stock < quantity {
-		return fmt.Errorf("недостаточное количество товара на складе (доступно: %d)", product.Stock)
+	// Проверяем наличие товара (выбранного варианта) на складе
+	stock := product.Stock
+	var variant *models.ItemVariant
+	switch {
+	case variantID != 0:
+		productVariant := findVariant(product, variantID)
+		if productVariant == nil {
+			return ErrVariantNotFound
+		}
+		stock = productVariant.Stock
+		variant = &models.ItemVariant{ID: productVariant.ID, SKU: productVariant.SKU, Attributes: productVariant.Attributes}
+	case len(product.Variants) > 0:
+		return ErrVariantRequired
+	}
+	if stock != -1 && stock < quantity {
+		return fmt.Errorf("%w (доступно: %d)", ErrInsufficientStock, stock)
 	}
 
 	// Получаем или создаем корзину пользователя
@@ -61,7 +102,7 @@ func (u *OrderUseCase) AddToCart(userID string, productID int, quantity int) err
 	}
 
 	// Добавляем товар в корзину
-	_, err = u.repo.AddItemToCart(cart.ID, productID, quantity)
+	_, err = u.repo.AddItemToCart(cart.ID, productID, variant, quantity)
 	if err != nil {
 		return fmt.Errorf("ошибка добавления товара в корзину: %w", err)
 	}
@@ -69,8 +110,8 @@ func (u *OrderUseCase) AddToCart(userID string, productID int, quantity int) err
 	return nil
 }
 
-// RemoveFromCart удаляет товар из корзины пользователя
-func (u *OrderUseCase) RemoveFromCart(userID string, productID int) error {
+// RemoveFromCart удаляет товар (вариант variantID, 0 - товар без вариантов) из корзины пользователя
+func (u *OrderUseCase) RemoveFromCart(ctx context.Context, userID string, productID, variantID int) error {
 	// Получаем корзину пользователя
 	cart, err := u.repo.GetCartByUserID(userID)
 	if err != nil {
@@ -78,7 +119,7 @@ func (u *OrderUseCase) RemoveFromCart(userID string, productID int) error {
 	}
 
 	// Удаляем товар из корзины
-	err = u.repo.RemoveItemFromCart(cart.ID, productID)
+	err = u.repo.RemoveItemFromCart(cart.ID, productID, variantID)
 	if err != nil {
 		return fmt.Errorf("ошибка удаления товара из корзины: %w", err)
 	}
@@ -87,80 +128,644 @@ func (u *OrderUseCase) RemoveFromCart(userID string, productID int) error {
 }
 
 // GetCart получает содержимое корзины пользователя
-func (u *OrderUseCase) GetCart(userID string) (*models.Cart, error) {
+func (u *OrderUseCase) GetCart(ctx context.Context, userID string) (*models.Cart, error) {
+	// Получаем корзину пользователя
+	cart, err := u.repo.GetCartByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения корзины: %w", err)
+	}
+
+	return u.orderDetails(ctx, cart)
+}
+
+// Checkout оформляет заказ пользователя и возвращает оформленный заказ с товарами.
+//
+// Оформление выполняется как сага: каждая позиция резервируется в product-service,
+// резервы подтверждаются, после чего заказ переводится в статус PENDING_PAYMENT.
+// Если любой шаг завершается ошибкой, все уже созданные резервы отменяются,
+// и товар возвращается на склад. Если корзина изменилась во время резервирования
+// (например, параллельный AddToCart или повторное оформление), возвращается repository.ErrCartChanged.
+// В заказ копируется адрес доставки addressID из адресной книги user-service,
+// а если он не указан - адрес по умолчанию (при его наличии).
+// Текущие цены позиций (с учетом цен вариантов) и названия товаров фиксируются в заказе.
+func (u *OrderUseCase) Checkout(ctx context.Context, userID string, addressID string) (*models.Cart, error) {
+	if u.policy.RequireVerifiedEmail {
+		if err := u.checkEmailVerified(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	shipping, err := u.shippingAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Получаем корзину пользователя
 	cart, err := u.repo.GetCartByUserID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения корзины: %w", err)
 	}
 
-	// Получаем товары в корзине
 	items, err := u.repo.GetCartItems(cart.ID)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения товаров из корзины: %w", err)
 	}
-
-	// Собираем полную информацию о корзине
-	result := &models.Cart{
-		Order:      *cart,
-		Items:      make([]models.CartItem, 0, len(items)),
-		TotalPrice: 0,
+	if len(items) == 0 {
+		return nil, fmt.Errorf("ошибка оформления заказа: %w", repository.ErrCartEmpty)
 	}
 
+	// Фиксируем цены и названия позиций: дальнейшие изменения и удаление товаров в product-service на заказ не влияют
 	for _, item := range items {
-		// Получаем информацию о товаре
-		product, err := u.productClient.GetProductByID(item.ProductID)
+		product, err := u.productClient.GetProductByID(ctx, item.ProductID)
 		if err != nil {
+			if errors.Is(err, clients.ErrProductNotFound) {
+				return nil, fmt.Errorf("%w (товар %d)", ErrProductNotFound, item.ProductID)
+			}
 			return nil, fmt.Errorf("ошибка получения информации о товаре %d: %w", item.ProductID, err)
 		}
+		if product == nil {
+			return nil, fmt.Errorf("%w (товар %d)", ErrProductNotFound, item.ProductID)
+		}
+		price := itemPrice(product, item)
+		item.UnitPrice = &price
+		item.ProductName = product.Name
+	}
 
-		totalPrice := product.Price * float64(item.Quantity)
-		result.Items = append(result.Items, models.CartItem{
-			OrderItem:    *item,
-			ProductName:  product.Name,
-			ProductPrice: product.Price,
-			TotalPrice:   totalPrice,
-		})
+	reservationIDs, err := u.reserveItems(ctx, cart.ID, items)
+	if err != nil {
+		return nil, err
+	}
 
-		result.TotalPrice += totalPrice
+	// Оформляем заказ: резервы и цены сохраняются в позициях вместе со сменой статуса. Если корзину изменили
+	// или параллельно оформили во время резервирования, репозиторий отклоняет оформление, не меняя корзину,
+	// и наши резервы отменяются
+	err = u.repo.CheckoutCart(cart.ID, items, userID, shipping)
+	if err != nil {
+		u.releaseReservations(ctx, reservationIDs)
+		return nil, fmt.Errorf("ошибка оформления заказа: %w", err)
 	}
 
-	return result, nil
+	return u.GetOrder(ctx, userID, cart.ID)
 }
 
-// Checkout оформляет заказ пользователя
-func (u *OrderUseCase) Checkout(userID string) error {
-	// Получаем корзину пользователя
-	cart, err := u.repo.GetCartByUserID(userID)
-	if err != nil {
-		return fmt.Errorf("ошибка получения корзины: %w", err)
+// reserveItems резервирует в product-service все позиции заказа orderID, записывает ID резервов
+// в ReservationID позиций и подтверждает резервы. При любой ошибке уже созданные резервы отменяются.
+// Возвращает ID созданных резервов в порядке позиций.
+func (u *OrderUseCase) reserveItems(ctx context.Context, orderID string, items []*models.OrderItem) ([]string, error) {
+	// Резервируем все позиции заказа
+	reservationIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		reservation, err := u.productClient.ReserveStock(ctx, item.ProductID, item.VariantID(), orderID, item.Quantity)
+		if err != nil {
+			u.releaseReservations(ctx, reservationIDs)
+			switch {
+			case errors.Is(err, clients.ErrInsufficientStock):
+				return nil, fmt.Errorf("%w (товар %d)", ErrInsufficientStock, item.ProductID)
+			case errors.Is(err, clients.ErrProductNotFound):
+				return nil, fmt.Errorf("%w (товар %d)", ErrProductNotFound, item.ProductID)
+			default:
+				return nil, fmt.Errorf("ошибка резервирования товара %d: %w", item.ProductID, err)
+			}
+		}
+		reservationIDs = append(reservationIDs, reservation.ID)
+		item.ReservationID = reservation.ID
 	}
 
-	// Оформляем заказ
-	err = u.repo.CheckoutCart(cart.ID)
-	if err != nil {
-		return fmt.Errorf("ошибка оформления заказа: %w", err)
+	// Подтверждаем резервы
+	for _, reservationID := range reservationIDs {
+		if err := u.productClient.CommitReservation(ctx, reservationID); err != nil {
+			u.releaseReservations(ctx, reservationIDs)
+			return nil, fmt.Errorf("ошибка подтверждения резерва %s: %w", reservationID, err)
+		}
 	}
 
+	return reservationIDs, nil
+}
+
+// checkUserExists проверяет в user-service, что пользователь существует
+func (u *OrderUseCase) checkUserExists(ctx context.Context, userID string) error {
+	user, err := u.userClient.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, clients.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("ошибка проверки пользователя: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
 	return nil
 }
 
-// GetCompletedOrders получает список выполненных заказов пользователя
-func (u *OrderUseCase) GetCompletedOrders(userID string) ([]*models.Order, error) {
-	// Проверяем существование пользователя
-	user, err := u.userClient.GetUserByID(userID)
+// checkEmailVerified проверяет в user-service, что email пользователя подтвержден
+func (u *OrderUseCase) checkEmailVerified(ctx context.Context, userID string) error {
+	user, err := u.userClient.GetUserByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка проверки пользователя: %w", err)
+		if errors.Is(err, clients.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("ошибка проверки пользователя: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("пользователь не найден")
+		return ErrUserNotFound
+	}
+	if !user.EmailVerified() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// shippingAddress получает в user-service адрес доставки для заказа.
+// Возвращает nil, nil, если адрес не указан и адреса по умолчанию у пользователя нет.
+func (u *OrderUseCase) shippingAddress(ctx context.Context, userID string, addressID string) (*models.ShippingAddress, error) {
+	address, err := u.userClient.GetShippingAddress(ctx, userID, addressID)
+	if err != nil {
+		switch {
+		case errors.Is(err, clients.ErrAddressNotFound):
+			return nil, ErrAddressNotFound
+		case errors.Is(err, clients.ErrUserNotFound):
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("ошибка получения адреса доставки: %w", err)
+	}
+	if address == nil {
+		return nil, nil
+	}
+	return &models.ShippingAddress{
+		AddressID:  address.ID,
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}, nil
+}
+
+// releaseReservations отменяет резервы, которые не удалось сохранить при оформлении заказа.
+// Ошибки отмены только логируются: исходная ошибка оформления важнее для вызывающего,
+// а несохраненный резерв product-service снимает сам по истечении срока.
+func (u *OrderUseCase) releaseReservations(ctx context.Context, reservationIDs []string) {
+	for _, reservationID := range reservationIDs {
+		if err := u.productClient.ReleaseReservation(ctx, reservationID); err != nil {
+			log.Printf("Не удалось отменить резерв %s: %v", reservationID, err)
+		}
+	}
+}
+
+// GetCompletedOrders получает список оформленных заказов пользователя.
+// Если переданы статусы, возвращаются только заказы с этими статусами.
+func (u *OrderUseCase) GetCompletedOrders(ctx context.Context, userID string, statuses ...models.OrderStatus) ([]*models.Order, error) {
+	// Проверяем существование пользователя
+	if err := u.checkUserExists(ctx, userID); err != nil {
+		return nil, err
 	}
 
 	// Получаем выполненные заказы пользователя
-	orders, err := u.repo.GetCompletedOrders(userID)
+	orders, err := u.repo.GetCompletedOrders(userID, statuses)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка получения выполненных заказов: %w", err)
 	}
 
 	return orders, nil
 }
+
+// GetOrder получает заказ пользователя userID по ID вместе с товарами и итоговой суммой
+func (u *OrderUseCase) GetOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error) {
+	order, err := u.getOwnedOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.orderDetails(ctx, order)
+}
+
+// getOwnedOrder получает заказ по ID и проверяет, что он принадлежит пользователю userID.
+// Сотрудникам и администраторам (роли STAFF и ADMIN из токена) доступны любые заказы.
+func (u *OrderUseCase) getOwnedOrder(ctx context.Context, userID string, orderID string) (*models.Order, error) {
+	order, err := u.repo.GetOrderByID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения заказа: %w", err)
+	}
+	if order.UserID != userID && !auth.HasRole(ctx, auth.RoleAdmin, auth.RoleStaff) {
+		return nil, ErrAccessDenied
+	}
+	return order, nil
+}
+
+// ListOrders получает оформленные заказы пользователя вместе с товарами.
+// Отсутствие заказов не считается ошибкой: возвращается пустой список.
+func (u *OrderUseCase) ListOrders(ctx context.Context, userID string, statuses ...models.OrderStatus) ([]*models.Cart, error) {
+	orders, err := u.GetCompletedOrders(ctx, userID, statuses...)
+	if err != nil {
+		if errors.Is(err, repository.ErrCompletedOrdersNotFound) {
+			return []*models.Cart{}, nil
+		}
+		return nil, err
+	}
+
+	result := make([]*models.Cart, 0, len(orders))
+	for _, order := range orders {
+		details, err := u.orderDetails(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, details)
+	}
+
+	return result, nil
+}
+
+// CreateOrder оформляет заказ из переданных товаров, минуя корзину пользователя: ее содержимое не меняется
+// и в заказ не попадает. Каждое вхождение ID в productIDs соответствует одной единице товара.
+// Товары с вариантами так заказать нельзя (ErrVariantRequired): их добавляют в корзину через AddToCart.
+// Товары резервируются, как при Checkout, после чего заказ со всеми позициями сохраняется одной транзакцией;
+// если сохранить его не удалось, резервы отменяются. Адрес доставки - адрес пользователя по умолчанию.
+func (u *OrderUseCase) CreateOrder(ctx context.Context, userID string, productIDs []int) (*models.Cart, error) {
+	if len(productIDs) == 0 {
+		return nil, repository.ErrCartEmpty
+	}
+
+	if u.policy.RequireVerifiedEmail {
+		if err := u.checkEmailVerified(ctx, userID); err != nil {
+			return nil, err
+		}
+	} else if err := u.checkUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	shipping, err := u.shippingAddress(ctx, userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Складываем количество одинаковых товаров, сохраняя порядок их появления
+	items := make([]*models.OrderItem, 0, len(productIDs))
+	byProduct := make(map[int]*models.OrderItem, len(productIDs))
+	for _, productID := range productIDs {
+		if item, seen := byProduct[productID]; seen {
+			item.Quantity++
+			continue
+		}
+		item := &models.OrderItem{ProductID: productID, Quantity: 1}
+		byProduct[productID] = item
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		product, err := u.productClient.GetProductByID(ctx, item.ProductID)
+		if err != nil {
+			if errors.Is(err, clients.ErrProductNotFound) {
+				return nil, fmt.Errorf("%w (товар %d)", ErrProductNotFound, item.ProductID)
+			}
+			return nil, fmt.Errorf("ошибка проверки товара: %w", err)
+		}
+		if product == nil {
+			return nil, fmt.Errorf("%w (товар %d)", ErrProductNotFound, item.ProductID)
+		}
+		if len(product.Variants) > 0 {
+			return nil, fmt.Errorf("%w (товар %d)", ErrVariantRequired, item.ProductID)
+		}
+		price := product.Price
+		item.UnitPrice = &price
+		item.ProductName = product.Name
+	}
+
+	order := &models.Order{ID: uuid.New().String(), UserID: userID, ShippingAddress: shipping}
+	reservationIDs, err := u.reserveItems(ctx, order.ID, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.CreateOrder(order, items, userID); err != nil {
+		u.releaseReservations(ctx, reservationIDs)
+		return nil, fmt.Errorf("ошибка оформления заказа: %w", err)
+	}
+
+	return u.orderDetails(ctx, order)
+}
+
+// DeleteOrder удаляет заказ пользователя userID по ID. Корзину и завершенный заказ (отмененный, возвращенный,
+// просроченный) удаляет владелец; неоплаченный заказ сначала отменяется, и товар возвращается на склад.
+// Оплаченные и выполненные заказы удаляют только роли STAFF и ADMIN, остальным возвращается ErrOrderNotDeletable.
+// Заказ, резервы которого не удалось отменить, не удаляется: возвращается ErrStockNotReleased.
+func (u *OrderUseCase) DeleteOrder(ctx context.Context, userID string, orderID string) error {
+	order, err := u.getOwnedOrder(ctx, userID, orderID)
+	if err != nil {
+		return err
+	}
+
+	switch order.Status {
+	case models.StatusPendingPayment, models.StatusCheckout:
+		if order, err = u.transition(ctx, order, models.StatusCancelled, userID); err != nil {
+			return err
+		}
+	case models.StatusPaid, models.StatusFulfilled:
+		if !auth.HasRole(ctx, auth.RoleStaff, auth.RoleAdmin) {
+			return fmt.Errorf("%w: %s", ErrOrderNotDeletable, order.Status)
+		}
+	case models.StatusCancelled, models.StatusRefunded, models.StatusExpired:
+		// Вместе с заказом удалились бы и резервы, которые еще должен отменить RetryStockRelease
+		items, err := u.repo.GetCartItems(order.ID)
+		if err != nil {
+			return fmt.Errorf("ошибка получения товаров заказа: %w", err)
+		}
+		if failed := u.releaseOrderReservations(ctx, order.ID, unreleasedReservations(items)); len(failed) > 0 {
+			return stockNotReleased(order.ID, failed)
+		}
+	}
+
+	if err := u.repo.DeleteOrder(order.ID, order.Status); err != nil {
+		return fmt.Errorf("ошибка удаления заказа: %w", err)
+	}
+	return nil
+}
+
+// PayOrder отмечает заказ как оплаченный. Платежного сервиса нет: оплату (в кассе, переводом) подтверждает
+// сотрудник, поэтому переход доступен только ролям STAFF и ADMIN, а не владельцу заказа.
+// Без него заказ не попадает в PAID, и выполнение и возврат недостижимы.
+func (u *OrderUseCase) PayOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error) {
+	if !auth.HasRole(ctx, auth.RoleStaff, auth.RoleAdmin) {
+		return nil, ErrAccessDenied
+	}
+	return u.changeStatus(ctx, userID, orderID, models.StatusPaid)
+}
+
+// FulfillOrder отмечает оплаченный заказ как выполненный. Переход доступен только ролям STAFF и ADMIN.
+func (u *OrderUseCase) FulfillOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error) {
+	if !auth.HasRole(ctx, auth.RoleStaff, auth.RoleAdmin) {
+		return nil, ErrAccessDenied
+	}
+	return u.changeStatus(ctx, userID, orderID, models.StatusFulfilled)
+}
+
+// CancelOrder отменяет неоплаченный заказ пользователя userID и возвращает товар на склад
+func (u *OrderUseCase) CancelOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error) {
+	return u.changeStatus(ctx, userID, orderID, models.StatusCancelled)
+}
+
+// RefundOrder оформляет возврат оплаченного или выполненного заказа и возвращает товар на склад.
+// Возврат доступен только ролям STAFF и ADMIN, а не владельцу заказа.
+func (u *OrderUseCase) RefundOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error) {
+	if !auth.HasRole(ctx, auth.RoleStaff, auth.RoleAdmin) {
+		return nil, ErrAccessDenied
+	}
+	return u.changeStatus(ctx, userID, orderID, models.StatusRefunded)
+}
+
+// GetOrderHistory получает историю смены статусов заказа пользователя userID
+func (u *OrderUseCase) GetOrderHistory(ctx context.Context, userID string, orderID string) ([]*models.StatusTransition, error) {
+	if _, err := u.getOwnedOrder(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	history, err := u.repo.GetStatusHistory(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения истории заказа: %w", err)
+	}
+	return history, nil
+}
+
+// ExpireOrders переводит в статус EXPIRED заказы, не оплаченные в течение paymentTimeout,
+// и возвращает товар на склад. Возвращает количество просроченных заказов.
+func (u *OrderUseCase) ExpireOrders(ctx context.Context, paymentTimeout time.Duration) (int, error) {
+	orders, err := u.repo.GetOrdersByStatus(
+		[]models.OrderStatus{models.StatusPendingPayment, models.StatusCheckout},
+		time.Now().Add(-paymentTimeout),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка поиска неоплаченных заказов: %w", err)
+	}
+
+	expired := 0
+	for _, order := range orders {
+		_, err := u.transition(ctx, order, models.StatusExpired, ActorSystem)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrStockNotReleased):
+			// Заказ уже просрочен, резервы повторно отменит RetryStockRelease
+			log.Printf("Просроченный заказ %s завершен, но товар не возвращен на склад: %v", order.ID, err)
+		case errors.Is(err, repository.ErrStatusConflict):
+			// Заказ могли оплатить или отменить, пока мы его обрабатывали
+			continue
+		default:
+			log.Printf("Не удалось завершить просроченный заказ %s: %v", order.ID, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// RetryStockRelease отменяет резервы, оставшиеся у отмененных, возвращенных и просроченных заказов
+// после неудачной отмены при смене статуса. Возвращает количество отмененных резервов;
+// резервы, которые снова не удалось отменить, останутся до следующего вызова.
+func (u *OrderUseCase) RetryStockRelease(ctx context.Context) (int, error) {
+	orders, err := u.repo.GetOrdersWithUnreleasedReservations(releasingStatuses)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка поиска заказов с неотмененными резервами: %w", err)
+	}
+
+	released := 0
+	for _, order := range orders {
+		items, err := u.repo.GetCartItems(order.ID)
+		if err != nil {
+			return released, fmt.Errorf("ошибка получения товаров заказа %s: %w", order.ID, err)
+		}
+		reservationIDs := unreleasedReservations(items)
+		failed := u.releaseOrderReservations(ctx, order.ID, reservationIDs)
+		released += len(reservationIDs) - len(failed)
+	}
+	return released, nil
+}
+
+// ErasedUserPrefix - начало псевдонима, которому передаются заказы удаленного пользователя
+const ErasedUserPrefix = "erased-"
+
+// EraseUserData обезличивает данные пользователя после удаления его учетной записи в user-service.
+// Неоплаченные заказы отменяются (товар возвращается на склад), корзина удаляется,
+// а оформленные заказы сохраняются для учета под случайным псевдонимом вместо ID пользователя.
+// Повторный вызов безопасен. Возвращает количество обезличенных заказов.
+func (u *OrderUseCase) EraseUserData(ctx context.Context, userID string) (int, error) {
+	unpaid, err := u.repo.GetCompletedOrders(userID, []models.OrderStatus{models.StatusPendingPayment, models.StatusCheckout})
+	if err != nil && !errors.Is(err, repository.ErrCompletedOrdersNotFound) {
+		return 0, fmt.Errorf("ошибка поиска неоплаченных заказов: %w", err)
+	}
+	for _, order := range unpaid {
+		// Заказ могли оплатить параллельно: тогда он просто обезличивается.
+		// Неотмененные резервы повторно отменит RetryStockRelease.
+		if _, err := u.transition(ctx, order, models.StatusCancelled, ActorSystem); err != nil &&
+			!errors.Is(err, repository.ErrStatusConflict) && !errors.Is(err, ErrStockNotReleased) {
+			return 0, fmt.Errorf("ошибка отмены заказа %s: %w", order.ID, err)
+		}
+	}
+
+	anonymized, err := u.repo.AnonymizeUserOrders(userID, ErasedUserPrefix+uuid.New().String())
+	if err != nil {
+		return 0, fmt.Errorf("ошибка обезличивания заказов: %w", err)
+	}
+	return anonymized, nil
+}
+
+// changeStatus переводит заказ пользователя userID в новый статус от его имени
+// и возвращает заказ вместе с товарами
+func (u *OrderUseCase) changeStatus(ctx context.Context, userID string, orderID string, to models.OrderStatus) (*models.Cart, error) {
+	order, err := u.getOwnedOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := u.transition(ctx, order, to, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.orderDetails(ctx, updated)
+}
+
+// transition проверяет допустимость перехода, сохраняет новый статус вместе с записью в истории
+// и при отмене, возврате или истечении заказа возвращает зарезервированный товар на склад.
+// Статус меняется до отмены резервов: если заказ параллельно изменили, товар не будет возвращен дважды.
+// Отмененные резервы отмечаются в репозитории. Если какой-то резерв отменить не удалось, статус
+// не откатывается: возвращается обновленный заказ вместе с ошибкой ErrStockNotReleased,
+// а оставшиеся резервы повторно отменит RetryStockRelease.
+func (u *OrderUseCase) transition(ctx context.Context, order *models.Order, to models.OrderStatus, actor string) (*models.Order, error) {
+	if !canTransition(order.Status, to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, order.Status, to)
+	}
+
+	var reservationIDs []string
+	if releasesStock(to) {
+		items, err := u.repo.GetCartItems(order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка получения товаров заказа: %w", err)
+		}
+		reservationIDs = unreleasedReservations(items)
+	}
+
+	updated, err := u.repo.UpdateOrderStatus(order.ID, order.Status, to, actor)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка изменения статуса заказа: %w", err)
+	}
+
+	if failed := u.releaseOrderReservations(ctx, order.ID, reservationIDs); len(failed) > 0 {
+		return updated, stockNotReleased(order.ID, failed)
+	}
+	return updated, nil
+}
+
+// stockNotReleased возвращает ErrStockNotReleased с ID заказа и резервов, которые не удалось отменить
+func stockNotReleased(orderID string, reservationIDs []string) error {
+	return fmt.Errorf("%w: заказ %s, резервы %s", ErrStockNotReleased, orderID, strings.Join(reservationIDs, ", "))
+}
+
+// unreleasedReservations возвращает ID резервов позиций, которые еще не отменены
+func unreleasedReservations(items []*models.OrderItem) []string {
+	var reservationIDs []string
+	for _, item := range items {
+		if item.ReservationID != "" && item.ReleasedAt == nil {
+			reservationIDs = append(reservationIDs, item.ReservationID)
+		}
+	}
+	return reservationIDs
+}
+
+// releaseOrderReservations отменяет резервы заказа orderID в product-service, отмечает отмененные
+// в репозитории и возвращает ID резервов, которые отменить не удалось.
+// Если отметку сохранить не удалось, резерв будет отменен повторно, что безопасно.
+func (u *OrderUseCase) releaseOrderReservations(ctx context.Context, orderID string, reservationIDs []string) []string {
+	var released, failed []string
+	for _, reservationID := range reservationIDs {
+		if err := u.productClient.ReleaseReservation(ctx, reservationID); err != nil {
+			log.Printf("Не удалось отменить резерв %s заказа %s: %v", reservationID, orderID, err)
+			failed = append(failed, reservationID)
+			continue
+		}
+		released = append(released, reservationID)
+	}
+	if len(released) > 0 {
+		if err := u.repo.MarkReservationsReleased(orderID, released); err != nil {
+			log.Printf("Не удалось отметить отмененные резервы заказа %s: %v", orderID, err)
+		}
+	}
+	return failed
+}
+
+// orderDetails собирает полную информацию о заказе: товары с ценами и итоговую сумму.
+// Для оформленных позиций используются цена и название, зафиксированные при оформлении,
+// поэтому заказ показывается и после удаления товара из каталога.
+func (u *OrderUseCase) orderDetails(ctx context.Context, order *models.Order) (*models.Cart, error) {
+	// Получаем товары заказа
+	items, err := u.repo.GetCartItems(order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения товаров из корзины: %w", err)
+	}
+
+	result := &models.Cart{
+		Order:      *order,
+		Items:      make([]models.CartItem, 0, len(items)),
+		TotalPrice: 0,
+	}
+
+	for _, item := range items {
+		// В оформленном заказе действуют цена и название на момент оформления, в корзине - текущие
+		name, price := item.ProductName, 0.0
+		if item.UnitPrice != nil {
+			price = *item.UnitPrice
+		}
+
+		// Позиции корзины и заказов, оформленных до сохранения названий, дополняются данными product-service
+		if item.UnitPrice == nil || name == "" {
+			product, err := u.productClient.GetProductByID(ctx, item.ProductID)
+			if err != nil && !errors.Is(err, clients.ErrProductNotFound) {
+				return nil, fmt.Errorf("ошибка получения информации о товаре %d: %w", item.ProductID, err)
+			}
+			switch {
+			case product != nil:
+				name = product.Name
+				if item.UnitPrice == nil {
+					price = itemPrice(product, item)
+				}
+			case item.UnitPrice == nil:
+				// Товар удален из каталога: в корзине он не показывается, а оформление вернет ErrProductNotFound
+				continue
+			}
+		}
+
+		totalPrice := price * float64(item.Quantity)
+		result.Items = append(result.Items, models.CartItem{
+			OrderItem:    *item,
+			ProductName:  name,
+			ProductPrice: price,
+			TotalPrice:   totalPrice,
+		})
+
+		result.TotalPrice += totalPrice
+	}
+
+	return result, nil
+}
+
+// itemPrice возвращает текущую цену единицы товара позиции item: цена варианта, если она задана,
+// заменяет цену продукта
+func itemPrice(product *clients.Product, item *models.OrderItem) float64 {
+	if item.Variant != nil {
+		if variant := findVariant(product, item.Variant.ID); variant != nil && variant.Price != nil {
+			return *variant.Price
+		}
+	}
+	return product.Price
+}
+
+// findVariant возвращает вариант variantID продукта или nil, если такого варианта нет
+func findVariant(product *clients.Product, variantID int) *clients.ProductVariant {
+	for i := range product.Variants {
+		if product.Variants[i].ID == variantID {
+			return &product.Variants[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/clients"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/repository"
+	"github.com/google/uuid"
+)
+
+// mustPlacedOrder сохраняет в repo оформленный заказ пользователя userID с резервами reservationIDs
+func mustPlacedOrder(t *testing.T, repo repository.Repository, userID string, reservationIDs ...string) *models.Order {
+	t.Helper()
+	items := make([]*models.OrderItem, 0, len(reservationIDs))
+	for i, reservationID := range reservationIDs {
+		items = append(items, &models.OrderItem{ProductID: i + 1, Quantity: 1, ReservationID: reservationID})
+	}
+	order := &models.Order{ID: uuid.New().String(), UserID: userID}
+	if err := repo.CreateOrder(order, items, userID); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	return order
+}
+
+// orderStatus возвращает сохраненный статус заказа
+func orderStatus(t *testing.T, repo repository.Repository, orderID string) models.OrderStatus {
+	t.Helper()
+	order, err := repo.GetOrderByID(orderID)
+	if err != nil {
+		t.Fatalf("GetOrderByID: %v", err)
+	}
+	return order.Status
+}
+
+func TestCancelOrderReleasesReservations(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, nil, productClient, CheckoutPolicy{})
+	order := mustPlacedOrder(t, repo, "user-1", "res-1", "", "res-3")
+
+	cancelled, err := uc.CancelOrder(ctx, "user-1", order.ID)
+	if err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	if cancelled.Status != models.StatusCancelled || orderStatus(t, repo, order.ID) != models.StatusCancelled {
+		t.Fatalf("заказ не отменен: %s", cancelled.Status)
+	}
+	// Позиция без резерва пропускается
+	if released := products.releasedReservations(); !reflect.DeepEqual(released, []string{"res-1", "res-3"}) {
+		t.Fatalf("отменены резервы %v, ожидались [res-1 res-3]", released)
+	}
+
+	// Повторная отмена запрещена графом переходов и не возвращает товар на склад второй раз
+	if _, err := uc.CancelOrder(ctx, "user-1", order.ID); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("повторная отмена: ожидалась ErrInvalidTransition, получено %v", err)
+	}
+	if released := products.releasedReservations(); len(released) != 2 {
+		t.Fatalf("резервы отменены повторно: %v", released)
+	}
+
+	if _, err := uc.CancelOrder(ctx, "user-2", mustPlacedOrder(t, repo, "user-1", "res-4").ID); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("отмена чужого заказа: ожидалась ErrAccessDenied, получено %v", err)
+	}
+}
+
+func TestStaffOnlyTransitions(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, nil, productClient, CheckoutPolicy{})
+	order := mustPlacedOrder(t, repo, "user-1", "res-1")
+	owner := auth.ContextWithRoles(ctx, []string{auth.RoleCustomer})
+	staff := auth.ContextWithRoles(ctx, []string{auth.RoleStaff})
+
+	// Владелец заказа не может сам отметить его оплаченным, выполненным или оформить возврат
+	if _, err := uc.PayOrder(owner, "user-1", order.ID); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("PayOrder владельцем: ожидалась ErrAccessDenied, получено %v", err)
+	}
+	if _, err := repo.UpdateOrderStatus(order.ID, models.StatusPendingPayment, models.StatusPaid, "staff-1"); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+	if _, err := uc.RefundOrder(owner, "user-1", order.ID); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("RefundOrder владельцем: ожидалась ErrAccessDenied, получено %v", err)
+	}
+	if _, err := uc.FulfillOrder(owner, "user-1", order.ID); !errors.Is(err, ErrAccessDenied) {
+		t.Fatalf("FulfillOrder владельцем: ожидалась ErrAccessDenied, получено %v", err)
+	}
+	if status := orderStatus(t, repo, order.ID); status != models.StatusPaid {
+		t.Fatalf("статус заказа изменен на %s", status)
+	}
+	if released := products.releasedReservations(); len(released) != 0 {
+		t.Fatalf("резервы отменены без возврата: %v", released)
+	}
+
+	if _, err := uc.FulfillOrder(staff, "staff-1", order.ID); err != nil {
+		t.Fatalf("FulfillOrder сотрудником: %v", err)
+	}
+	if _, err := uc.RefundOrder(staff, "staff-1", order.ID); err != nil {
+		t.Fatalf("RefundOrder сотрудником: %v", err)
+	}
+	if released := products.releasedReservations(); !reflect.DeepEqual(released, []string{"res-1"}) {
+		t.Fatalf("отменены резервы %v, ожидался [res-1]", released)
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, nil, productClient, CheckoutPolicy{})
+	owner := auth.ContextWithRoles(ctx, []string{auth.RoleCustomer})
+	staff := auth.ContextWithRoles(ctx, []string{auth.RoleStaff})
+
+	// Оплаченный заказ владелец удалить не может: товар уже продан, а заказ нужен для учета
+	paid := mustPlacedOrder(t, repo, "user-1", "res-paid")
+	if _, err := repo.UpdateOrderStatus(paid.ID, models.StatusPendingPayment, models.StatusPaid, "staff-1"); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+	if err := uc.DeleteOrder(owner, "user-1", paid.ID); !errors.Is(err, ErrOrderNotDeletable) {
+		t.Fatalf("DeleteOrder оплаченного заказа: ожидалась ErrOrderNotDeletable, получено %v", err)
+	}
+	if status := orderStatus(t, repo, paid.ID); status != models.StatusPaid {
+		t.Fatalf("статус оплаченного заказа изменен на %s", status)
+	}
+	if released := products.releasedReservations(); len(released) != 0 {
+		t.Fatalf("резервы оплаченного заказа отменены: %v", released)
+	}
+	if err := uc.DeleteOrder(staff, "staff-1", paid.ID); err != nil {
+		t.Fatalf("DeleteOrder оплаченного заказа сотрудником: %v", err)
+	}
+
+	// Неоплаченный заказ перед удалением отменяется, и товар возвращается на склад
+	pending := mustPlacedOrder(t, repo, "user-1", "res-1", "res-2")
+	if err := uc.DeleteOrder(owner, "user-1", pending.ID); err != nil {
+		t.Fatalf("DeleteOrder неоплаченного заказа: %v", err)
+	}
+	if _, err := repo.GetOrderByID(pending.ID); !errors.Is(err, repository.ErrOrderNotFound) {
+		t.Fatalf("неоплаченный заказ не удален: %v", err)
+	}
+	if released := products.releasedReservations(); !reflect.DeepEqual(released, []string{"res-1", "res-2"}) {
+		t.Fatalf("отменены резервы %v, ожидались [res-1 res-2]", released)
+	}
+
+	// Заказ с неотмененным резервом остается отмененным, пока товар не вернется на склад
+	failing := mustPlacedOrder(t, repo, "user-1", "res-3")
+	products.failReleaseOf("res-3")
+	if err := uc.DeleteOrder(owner, "user-1", failing.ID); !errors.Is(err, ErrStockNotReleased) {
+		t.Fatalf("DeleteOrder при сбое отмены резерва: ожидалась ErrStockNotReleased, получено %v", err)
+	}
+	if err := uc.DeleteOrder(owner, "user-1", failing.ID); !errors.Is(err, ErrStockNotReleased) {
+		t.Fatalf("повторный DeleteOrder отмененного заказа: ожидалась ErrStockNotReleased, получено %v", err)
+	}
+	if status := orderStatus(t, repo, failing.ID); status != models.StatusCancelled {
+		t.Fatalf("статус заказа %s, ожидался CANCELLED", status)
+	}
+	products.restoreReleaseOf("res-3")
+	if err := uc.DeleteOrder(owner, "user-1", failing.ID); err != nil {
+		t.Fatalf("DeleteOrder после восстановления product-service: %v", err)
+	}
+	if released := products.releasedReservations(); !reflect.DeepEqual(released, []string{"res-1", "res-2", "res-3"}) {
+		t.Fatalf("отменены резервы %v, ожидались [res-1 res-2 res-3]", released)
+	}
+}
+
+func TestTransitionKeepsStatusWhenReleaseFails(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, nil, productClient, CheckoutPolicy{})
+	order := mustPlacedOrder(t, repo, "user-1", "res-1", "res-2")
+	products.failReleaseOf("res-1")
+
+	// Статус уже сохранен, поэтому сбой отмены одного резерва не откатывает переход
+	// и не мешает отменить остальные, но вызывающий узнает о нем
+	updated, err := uc.transition(ctx, order, models.StatusExpired, ActorSystem)
+	if !errors.Is(err, ErrStockNotReleased) {
+		t.Fatalf("transition: ожидалась ErrStockNotReleased, получено %v", err)
+	}
+	if updated == nil || updated.Status != models.StatusExpired || orderStatus(t, repo, order.ID) != models.StatusExpired {
+		t.Fatalf("статус заказа %s, ожидался EXPIRED", orderStatus(t, repo, order.ID))
+	}
+	if released := products.releasedReservations(); !reflect.DeepEqual(released, []string{"res-2"}) {
+		t.Fatalf("отменены резервы %v, ожидался [res-2]", released)
+	}
+
+	history, err := repo.GetStatusHistory(order.ID)
+	if err != nil {
+		t.Fatalf("GetStatusHistory: %v", err)
+	}
+	last := history[len(history)-1]
+	if last.FromStatus != models.StatusPendingPayment || last.ToStatus != models.StatusExpired || last.Actor != ActorSystem {
+		t.Fatalf("некорректная запись истории: %+v", last)
+	}
+}
+
+func TestRetryStockReleaseReleasesRemainingReservations(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, nil, productClient, CheckoutPolicy{})
+	order := mustPlacedOrder(t, repo, "user-1", "res-1", "res-2")
+	mustPlacedOrder(t, repo, "user-1", "res-pending")
+	products.failReleaseOf("res-1")
+
+	if _, err := uc.CancelOrder(ctx, "user-1", order.ID); !errors.Is(err, ErrStockNotReleased) {
+		t.Fatalf("CancelOrder: ожидалась ErrStockNotReleased, получено %v", err)
+	}
+
+	// Пока product-service отклоняет отмену, резерв остается неотмененным
+	released, err := uc.RetryStockRelease(ctx)
+	if err != nil || released != 0 {
+		t.Fatalf("RetryStockRelease = %d, %v; ожидалось 0, nil", released, err)
+	}
+
+	// Уже отмененный res-2 и резерв неоплаченного заказа повторно не отменяются
+	products.restoreReleaseOf("res-1")
+	released, err = uc.RetryStockRelease(ctx)
+	if err != nil || released != 1 {
+		t.Fatalf("RetryStockRelease = %d, %v; ожидалось 1, nil", released, err)
+	}
+	if got := products.releasedReservations(); !reflect.DeepEqual(got, []string{"res-2", "res-1"}) {
+		t.Fatalf("отменены резервы %v, ожидались [res-2 res-1]", got)
+	}
+
+	if released, err = uc.RetryStockRelease(ctx); err != nil || released != 0 {
+		t.Fatalf("повторный RetryStockRelease = %d, %v; ожидалось 0, nil", released, err)
+	}
+}
+
+func TestTransitionDoesNotChangeStatusWhenItemsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	memory := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(failingItemsRepository{memory}, nil, productClient, CheckoutPolicy{})
+	order := mustPlacedOrder(t, memory, "user-1", "res-1")
+
+	// Товары читаются до смены статуса: иначе заказ остался бы отмененным без возврата товара на склад
+	if _, err := uc.transition(ctx, order, models.StatusCancelled, "user-1"); !errors.Is(err, errItemsUnavailable) {
+		t.Fatalf("transition: ожидалась errItemsUnavailable, получено %v", err)
+	}
+	if status := orderStatus(t, memory, order.ID); status != models.StatusPendingPayment {
+		t.Fatalf("статус изменен до чтения товаров: %s", status)
+	}
+	if released := products.releasedReservations(); len(released) != 0 {
+		t.Fatalf("отменены резервы %v", released)
+	}
+
+	// Переход без возврата товара на склад товары не читает
+	if _, err := uc.transition(ctx, order, models.StatusPaid, "staff-1"); err != nil {
+		t.Fatalf("transition в PAID: %v", err)
+	}
+}
+
+func TestTransitionConflictDoesNotReleaseStock(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, nil, productClient, CheckoutPolicy{})
+	stale := mustPlacedOrder(t, repo, "user-1", "res-1")
+
+	// Заказ оплатили, пока планировщик держал его устаревшую копию
+	if _, err := repo.UpdateOrderStatus(stale.ID, models.StatusPendingPayment, models.StatusPaid, "staff-1"); err != nil {
+		t.Fatalf("UpdateOrderStatus: %v", err)
+	}
+
+	if _, err := uc.transition(ctx, stale, models.StatusExpired, ActorSystem); !errors.Is(err, repository.ErrStatusConflict) {
+		t.Fatalf("transition: ожидалась ErrStatusConflict, получено %v", err)
+	}
+	if status := orderStatus(t, repo, stale.ID); status != models.StatusPaid {
+		t.Fatalf("статус оплаченного заказа изменен на %s", status)
+	}
+	if released := products.releasedReservations(); len(released) != 0 {
+		t.Fatalf("резервы оплаченного заказа отменены: %v", released)
+	}
+
+	if _, err := uc.transition(ctx, &models.Order{ID: stale.ID, Status: models.StatusCancelled}, models.StatusPaid, "staff-1"); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("CANCELLED -> PAID: ожидалась ErrInvalidTransition, получено %v", err)
+	}
+}
+
+func TestOrderDetailsUsesCheckoutPrice(t *testing.T) {
+	ctx := context.Background()
+	productClient, _ := newTestProductClient(t)
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, nil, productClient, CheckoutPolicy{})
+
+	// Текущая цена продуктов в product-service - 100, при оформлении действовали другие
+	checkoutPrice, variantPrice := 80.0, 120.5
+	order := &models.Order{ID: uuid.New().String(), UserID: "user-1"}
+	items := []*models.OrderItem{
+		{ProductID: 1, Quantity: 2, UnitPrice: &checkoutPrice},
+		{ProductID: 2, Variant: &models.ItemVariant{ID: 5, SKU: "TEE-L"}, Quantity: 1, UnitPrice: &variantPrice},
+	}
+	if err := repo.CreateOrder(order, items, "user-1"); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+
+	for _, to := range []models.OrderStatus{models.StatusPaid, models.StatusFulfilled, models.StatusRefunded} {
+		if _, err := repo.UpdateOrderStatus(order.ID, orderStatus(t, repo, order.ID), to, "user-1"); err != nil {
+			t.Fatalf("UpdateOrderStatus(%s): %v", to, err)
+		}
+		details, err := uc.GetOrder(ctx, "user-1", order.ID)
+		if err != nil {
+			t.Fatalf("GetOrder: %v", err)
+		}
+		if details.TotalPrice != 2*80+120.5 {
+			t.Fatalf("%s: ожидалась сумма по ценам оформления 280.5, получено %v", to, details.TotalPrice)
+		}
+		for _, item := range details.Items {
+			if item.ProductPrice != *item.UnitPrice {
+				t.Fatalf("%s: цена позиции %d %v вместо зафиксированной %v", to, item.ProductID, item.ProductPrice, *item.UnitPrice)
+			}
+		}
+	}
+
+	// В корзине цена не зафиксирована, действует текущая
+	cart, err := repo.GetOrCreateCart("user-1")
+	if err != nil {
+		t.Fatalf("GetOrCreateCart: %v", err)
+	}
+	if _, err := repo.AddItemToCart(cart.ID, 1, nil, 3); err != nil {
+		t.Fatalf("AddItemToCart: %v", err)
+	}
+	details, err := uc.GetCart(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("GetCart: %v", err)
+	}
+	if details.TotalPrice != 300 || details.Items[0].UnitPrice != nil {
+		t.Fatalf("ожидалась сумма корзины 300 по текущей цене, получено %v", details.TotalPrice)
+	}
+}
+
+func TestOrderDetailsWithRemovedProducts(t *testing.T) {
+	ctx := context.Background()
+	productClient, products := newTestProductClient(t)
+	// user-service в моковом режиме: пользователь существует, адрес по умолчанию есть
+	t.Setenv("MOCK_SERVICES", "true")
+	repo := repository.NewMemoryRepository()
+	uc := NewOrderUseCase(repo, clients.NewUserClient(), productClient, CheckoutPolicy{})
+
+	price := 80.0
+	order := &models.Order{ID: uuid.New().String(), UserID: "user-1"}
+	items := []*models.OrderItem{
+		{ProductID: 1, Quantity: 2, UnitPrice: &price, ProductName: "Футболка"},
+		// Заказ, оформленный до сохранения названий: название берется из каталога, пока товар в нем есть
+		{ProductID: 2, Quantity: 1, UnitPrice: &price},
+	}
+	if err := repo.CreateOrder(order, items, "user-1"); err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	cart, err := repo.GetOrCreateCart("user-1")
+	if err != nil {
+		t.Fatalf("GetOrCreateCart: %v", err)
+	}
+	for _, productID := range []int{1, 3} {
+		if _, err := repo.AddItemToCart(cart.ID, productID, nil, 1); err != nil {
+			t.Fatalf("AddItemToCart: %v", err)
+		}
+	}
+
+	details, err := uc.GetOrder(ctx, "user-1", order.ID)
+	if err != nil {
+		t.Fatalf("GetOrder: %v", err)
+	}
+	if details.Items[0].ProductName != "Футболка" || details.Items[1].ProductName != "Товар 2" {
+		t.Fatalf("названия позиций %q, %q", details.Items[0].ProductName, details.Items[1].ProductName)
+	}
+
+	// Удаление товаров из каталога не мешает читать оформленный заказ и корзину
+	products.removeProduct(1)
+	products.removeProduct(2)
+	details, err = uc.GetOrder(ctx, "user-1", order.ID)
+	if err != nil {
+		t.Fatalf("GetOrder после удаления товаров: %v", err)
+	}
+	if len(details.Items) != 2 || details.TotalPrice != 3*80 {
+		t.Fatalf("ожидались 2 позиции на 240, получено %d на %v", len(details.Items), details.TotalPrice)
+	}
+	if details.Items[0].ProductName != "Футболка" || details.Items[1].ProductName != "" {
+		t.Fatalf("названия позиций %q, %q", details.Items[0].ProductName, details.Items[1].ProductName)
+	}
+
+	orders, err := uc.ListOrders(ctx, "user-1")
+	if err != nil || len(orders) != 1 {
+		t.Fatalf("ListOrders = %d заказов, %v", len(orders), err)
+	}
+
+	// Удаленный товар не показывается в корзине, оформить его нельзя
+	cartDetails, err := uc.GetCart(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("GetCart: %v", err)
+	}
+	if len(cartDetails.Items) != 1 || cartDetails.Items[0].ProductID != 3 || cartDetails.TotalPrice != 100 {
+		t.Fatalf("в корзине ожидался только товар 3 за 100, получено %+v", cartDetails.Items)
+	}
+	if _, err := uc.Checkout(ctx, "user-1", ""); !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("Checkout: ожидалась ErrProductNotFound, получено %v", err)
+	}
+}
+
+func TestItemPrice(t *testing.T) {
+	override := 150.0
+	product := &clients.Product{ID: 1, Price: 100, Variants: []clients.ProductVariant{
+		{ID: 1, SKU: "TEE-S"},
+		{ID: 2, SKU: "TEE-XL", Price: &override},
+	}}
+
+	tests := []struct {
+		name    string
+		variant *models.ItemVariant
+		want    float64
+	}{
+		{"без варианта", nil, 100},
+		{"вариант без своей цены", &models.ItemVariant{ID: 1}, 100},
+		{"цена варианта", &models.ItemVariant{ID: 2}, 150},
+		{"удаленный вариант", &models.ItemVariant{ID: 3}, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := itemPrice(product, &models.OrderItem{ProductID: 1, Variant: tt.variant}); got != tt.want {
+				t.Fatalf("ожидалась цена %v, получено %v", tt.want, got)
+			}
+		})
+	}
+}
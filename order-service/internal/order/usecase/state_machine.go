@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+)
+
+var (
+	ErrInvalidTransition = errors.New("недопустимый переход статуса заказа")
+	ErrInvalidStatus     = errors.New("неизвестный статус заказа")
+)
+
+// ActorSystem - инициатор переходов, выполняемых самим сервисом (например, истечение срока оплаты)
+const ActorSystem = "system"
+
+// transitions описывает допустимые переходы между статусами заказа.
+// Переход CART -> PENDING_PAYMENT выполняется только при оформлении корзины (Checkout).
+var transitions = map[models.OrderStatus][]models.OrderStatus{
+	models.StatusCart:           {models.StatusPendingPayment},
+	models.StatusPendingPayment: {models.StatusPaid, models.StatusCancelled, models.StatusExpired},
+	models.StatusCheckout:       {models.StatusPaid, models.StatusCancelled, models.StatusExpired},
+	models.StatusPaid:           {models.StatusFulfilled, models.StatusRefunded},
+	models.StatusFulfilled:      {models.StatusRefunded},
+}
+
+// canTransition проверяет, допустим ли переход заказа из статуса from в статус to
+func canTransition(from, to models.OrderStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// releasingStatuses - статусы, при переходе в которые товар возвращается на склад
+var releasingStatuses = []models.OrderStatus{models.StatusCancelled, models.StatusRefunded, models.StatusExpired}
+
+// releasesStock сообщает, нужно ли вернуть товар на склад при переходе в статус
+func releasesStock(status models.OrderStatus) bool {
+	return slices.Contains(releasingStatuses, status)
+}
+
+// ParseOrderStatus преобразует строку в статус оформленного заказа.
+// Статус CART не принимается: корзина не является заказом.
+func ParseOrderStatus(value string) (models.OrderStatus, error) {
+	status := models.OrderStatus(strings.ToUpper(strings.TrimSpace(value)))
+	switch status {
+	case models.StatusPendingPayment, models.StatusPaid, models.StatusFulfilled,
+		models.StatusCancelled, models.StatusRefunded, models.StatusExpired, models.StatusCheckout:
+		return status, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidStatus, value)
+	}
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
+)
+
+// allStatuses - все статусы заказа, включая корзину и устаревший CHECKOUT
+var allStatuses = []models.OrderStatus{
+	models.StatusCart,
+	models.StatusPendingPayment,
+	models.StatusCheckout,
+	models.StatusPaid,
+	models.StatusFulfilled,
+	models.StatusCancelled,
+	models.StatusRefunded,
+	models.StatusExpired,
+}
+
+func TestCanTransition(t *testing.T) {
+	// Ожидаемый граф переходов задан здесь независимо от transitions:
+	// любое ребро, не перечисленное ниже, должно быть запрещено
+	allowed := map[models.OrderStatus][]models.OrderStatus{
+		models.StatusCart:           {models.StatusPendingPayment},
+		models.StatusPendingPayment: {models.StatusPaid, models.StatusCancelled, models.StatusExpired},
+		models.StatusCheckout:       {models.StatusPaid, models.StatusCancelled, models.StatusExpired},
+		models.StatusPaid:           {models.StatusFulfilled, models.StatusRefunded},
+		models.StatusFulfilled:      {models.StatusRefunded},
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			want := false
+			for _, status := range allowed[from] {
+				if status == to {
+					want = true
+				}
+			}
+			t.Run(string(from)+"->"+string(to), func(t *testing.T) {
+				if got := canTransition(from, to); got != want {
+					t.Fatalf("canTransition(%s, %s) = %v, ожидалось %v", from, to, got, want)
+				}
+			})
+		}
+	}
+
+	// Неизвестные статусы никуда не переходят, и в них нельзя перейти
+	if canTransition("UNKNOWN", models.StatusPaid) || canTransition(models.StatusPendingPayment, "UNKNOWN") {
+		t.Fatalf("разрешен переход с неизвестным статусом")
+	}
+}
+
+func TestTransitionsHaveNoUnknownStatuses(t *testing.T) {
+	known := make(map[models.OrderStatus]bool, len(allStatuses))
+	for _, status := range allStatuses {
+		known[status] = true
+	}
+	for from, targets := range transitions {
+		if !known[from] {
+			t.Fatalf("переход из неизвестного статуса %s", from)
+		}
+		for _, to := range targets {
+			if !known[to] {
+				t.Fatalf("переход %s -> %s в неизвестный статус", from, to)
+			}
+		}
+	}
+}
+
+func TestReleasesStock(t *testing.T) {
+	tests := []struct {
+		status models.OrderStatus
+		want   bool
+	}{
+		{models.StatusCart, false},
+		{models.StatusPendingPayment, false},
+		{models.StatusCheckout, false},
+		{models.StatusPaid, false},
+		{models.StatusFulfilled, false},
+		{models.StatusCancelled, true},
+		{models.StatusRefunded, true},
+		{models.StatusExpired, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := releasesStock(tt.status); got != tt.want {
+				t.Fatalf("releasesStock(%s) = %v, ожидалось %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOrderStatus(t *testing.T) {
+	tests := []struct {
+		value string
+		want  models.OrderStatus
+	}{
+		{"PENDING_PAYMENT", models.StatusPendingPayment},
+		{"paid", models.StatusPaid},
+		{" Fulfilled ", models.StatusFulfilled},
+		{"cancelled", models.StatusCancelled},
+		{"REFUNDED", models.StatusRefunded},
+		{"expired", models.StatusExpired},
+		{"checkout", models.StatusCheckout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseOrderStatus(tt.value)
+			if err != nil {
+				t.Fatalf("ParseOrderStatus(%q): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseOrderStatus(%q) = %s, ожидалось %s", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "   ", "CART", "cart", "SHIPPED", "PENDING PAYMENT", "PAID,FULFILLED"} {
+		t.Run("отклоняет "+value, func(t *testing.T) {
+			if _, err := ParseOrderStatus(value); !errors.Is(err, ErrInvalidStatus) {
+				t.Fatalf("ParseOrderStatus(%q): ожидалась ошибка ErrInvalidStatus, получено %v", value, err)
+			}
+		})
+	}
+}
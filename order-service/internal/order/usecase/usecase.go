@@ -1,23 +1,68 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/models"
 )
 
-// UseCase представляет интерфейс бизнес-логики для работы с заказами
+// UseCase представляет интерфейс бизнес-логики для работы с заказами.
+// userID - ID аутентифицированного пользователя: операции с заказом по его ID
+// разрешены только владельцу заказа, иначе возвращается ErrAccessDenied.
 type UseCase interface {
-	// AddToCart добавляет товар в корзину пользователя
-	AddToCart(userID string, productID int, quantity int) error
+	// AddToCart добавляет товар в корзину пользователя.
+	// variantID - выбранный вариант продукта (размер, цвет), 0 - продукт без вариантов.
+	AddToCart(ctx context.Context, userID string, productID, variantID int, quantity int) error
 
-	// RemoveFromCart удаляет товар из корзины пользователя
-	RemoveFromCart(userID string, productID int) error
+	// RemoveFromCart удаляет товар (вариант variantID) из корзины пользователя
+	RemoveFromCart(ctx context.Context, userID string, productID, variantID int) error
 
 	// GetCart получает содержимое корзины пользователя
-	GetCart(userID string) (*models.Cart, error)
+	GetCart(ctx context.Context, userID string) (*models.Cart, error)
+
+	// Checkout оформляет заказ пользователя с адресом доставки addressID
+	// (пустая строка - адрес по умолчанию, если он есть)
+	Checkout(ctx context.Context, userID string, addressID string) (*models.Cart, error)
+
+	// GetCompletedOrders получает список оформленных заказов пользователя, опционально фильтруя по статусам
+	GetCompletedOrders(ctx context.Context, userID string, statuses ...models.OrderStatus) ([]*models.Order, error)
+
+	// GetOrder получает заказ по ID вместе с товарами
+	GetOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error)
+
+	// ListOrders получает оформленные заказы пользователя вместе с товарами, опционально фильтруя по статусам
+	ListOrders(ctx context.Context, userID string, statuses ...models.OrderStatus) ([]*models.Cart, error)
+
+	// CreateOrder оформляет заказ из переданных товаров, не затрагивая корзину пользователя
+	CreateOrder(ctx context.Context, userID string, productIDs []int) (*models.Cart, error)
+
+	// DeleteOrder удаляет корзину или завершенный заказ; неоплаченный заказ сначала отменяется.
+	// Оплаченные и выполненные заказы удаляют только STAFF и ADMIN.
+	DeleteOrder(ctx context.Context, userID string, orderID string) error
+
+	// PayOrder отмечает заказ как оплаченный (подтверждение оплаты сотрудником, только STAFF и ADMIN)
+	PayOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error)
+
+	// FulfillOrder отмечает оплаченный заказ как выполненный
+	FulfillOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error)
+
+	// CancelOrder отменяет неоплаченный заказ и возвращает товар на склад
+	CancelOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error)
+
+	// RefundOrder оформляет возврат заказа и возвращает товар на склад
+	RefundOrder(ctx context.Context, userID string, orderID string) (*models.Cart, error)
+
+	// GetOrderHistory получает историю смены статусов заказа
+	GetOrderHistory(ctx context.Context, userID string, orderID string) ([]*models.StatusTransition, error)
+
+	// ExpireOrders завершает заказы, не оплаченные в течение paymentTimeout
+	ExpireOrders(ctx context.Context, paymentTimeout time.Duration) (int, error)
 
-	// Checkout оформляет заказ пользователя
-	Checkout(userID string) error
+	// RetryStockRelease повторно отменяет резервы отмененных, возвращенных и просроченных заказов,
+	// которые не удалось вернуть на склад при смене статуса
+	RetryStockRelease(ctx context.Context) (int, error)
 
-	// GetCompletedOrders получает список выполненных заказов пользователя
-	GetCompletedOrders(userID string) ([]*models.Order, error)
+	// EraseUserData обезличивает заказы пользователя, удаленного в user-service
+	EraseUserData(ctx context.Context, userID string) (int, error)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"net"
 	"net/http"
@@ -10,20 +11,20 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/Hayzerr/go-microservice-project/auth"
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/clients"
+	orderGrpc "github.com/Hayzerr/go-microservice-project/order-service/internal/order/delivery/grpc"
 	orderHttp "github.com/Hayzerr/go-microservice-project/order-service/internal/order/delivery/http"
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/repository"
 	"github.com/Hayzerr/go-microservice-project/order-service/internal/order/usecase"
 	"github.com/gorilla/mux"
+	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 
 	pb "github.com/Hayzerr/go-microservice-project/pb"
 )
 
-type server struct {
-	pb.UnimplementedOrderServiceServer
-}
-
 // checkServiceAvailability проверяет доступность сервиса по указанному URL
 func checkServiceAvailability(url string) bool {
 	client := http.Client{
@@ -64,23 +65,116 @@ func main() {
 	// Инициализируем клиенты для других сервисов
 	userClient := clients.NewUserClient()
 	productClient := clients.NewProductClient()
+	// Резервы склада в product-service принимаются только от клиента OAuth2 с inventory:write:
+	// без его учетных данных ни один заказ не оформить, а отмененный заказ не вернет товар на склад
+	if !productClient.HasServiceCredentials() && os.Getenv("MOCK_SERVICES") != "true" {
+		log.Fatal("SERVICE_CLIENT_ID и SERVICE_CLIENT_SECRET не заданы: order-service не сможет резервировать товар в product-service")
+	}
+
+	// Инициализируем репозиторий: PostgreSQL, если задан DB_DSN, иначе in-memory
+	var orderRepo repository.Repository
+	if dsn := os.Getenv("DB_DSN"); dsn != "" {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			log.Fatalf("Ошибка подключения к базе данных: %v", err)
+		}
+		defer db.Close()
 
-	// Инициализируем репозиторий (in-memory)
-	orderRepo := repository.NewMemoryRepository()
+		if err := db.Ping(); err != nil {
+			log.Fatalf("Ошибка проверки соединения с базой данных: %v", err)
+		}
+		log.Println("Успешное подключение к базе данных, используется PostgreSQL репозиторий")
+		orderRepo = repository.NewPostgresRepository(db)
+	} else {
+		log.Println("DB_DSN не задан, используется in-memory репозиторий")
+		orderRepo = repository.NewMemoryRepository()
+	}
+
+	// Оформление заказа без подтвержденного email запрещается, если CHECKOUT_REQUIRE_VERIFIED_EMAIL=true
+	checkoutPolicy := usecase.CheckoutPolicy{
+		RequireVerifiedEmail: getenv("CHECKOUT_REQUIRE_VERIFIED_EMAIL", "false") == "true",
+	}
 
 	// Инициализируем usecase
-	orderUseCase := usecase.NewOrderUseCase(orderRepo, userClient, productClient)
+	orderUseCase := usecase.NewOrderUseCase(orderRepo, userClient, productClient, checkoutPolicy)
+
+	// Периодически завершаем заказы, не оплаченные за ORDER_PAYMENT_TIMEOUT (0 отключает проверку)
+	paymentTimeout, err := time.ParseDuration(getenv("ORDER_PAYMENT_TIMEOUT", "30m"))
+	if err != nil {
+		log.Fatalf("Некорректное значение ORDER_PAYMENT_TIMEOUT: %v", err)
+	}
+	stopExpiry := make(chan struct{})
+	if paymentTimeout > 0 {
+		go expireOrders(orderUseCase, paymentTimeout, stopExpiry)
+	}
+	// Резервы, которые не удалось отменить при отмене, возврате или истечении заказа,
+	// повторно отменяются каждые STOCK_RELEASE_RETRY_INTERVAL (0 отключает повтор)
+	releaseRetryInterval, err := time.ParseDuration(getenv("STOCK_RELEASE_RETRY_INTERVAL", "1m"))
+	if err != nil {
+		log.Fatalf("Некорректное значение STOCK_RELEASE_RETRY_INTERVAL: %v", err)
+	}
+	if releaseRetryInterval > 0 {
+		go retryStockRelease(orderUseCase, releaseRetryInterval, stopExpiry)
+	}
+
+	// Токены проверяются открытыми ключами user-service (JWKS, кешируется на JWT_JWKS_CACHE_TTL).
+	// Если задан JWT_SECRET, используется прежняя проверка общим секретом (user-service с JWT_ALGORITHM=HS256).
+	// Отозванные при выходе токены отклоняются по списку, который загружается из user-service
+	// каждые TOKEN_DENYLIST_INTERVAL (0 отключает проверку).
+	var tokenValidator auth.TokenValidator
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		tokenValidator = auth.NewValidator(secret)
+	} else {
+		jwksCacheTTL, err := time.ParseDuration(getenv("JWT_JWKS_CACHE_TTL", "5m"))
+		if err != nil {
+			log.Fatalf("Некорректное значение JWT_JWKS_CACHE_TTL: %v", err)
+		}
+		jwksURL := getenv("JWT_JWKS_URL", userServiceURL+"/.well-known/jwks.json")
+		tokenValidator = auth.NewKeyValidator(auth.NewJWKSCache(jwksURL, jwksCacheTTL))
+	}
+	denylistInterval, err := time.ParseDuration(getenv("TOKEN_DENYLIST_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("Некорректное значение TOKEN_DENYLIST_INTERVAL: %v", err)
+	}
+	stopDenylist := make(chan struct{})
+	if denylistInterval > 0 {
+		denylist := auth.NewRemoteDenylist(userServiceURL+"/api/auth/revoked", denylistInterval)
+		go denylist.Run(stopDenylist)
+		tokenValidator = auth.WithDenylist(tokenValidator, denylist)
+	}
+	// API-ключи проверяются в user-service; ответ кешируется на API_KEY_CACHE_TTL,
+	// поэтому отзыв ключа вступает в силу с такой же задержкой
+	apiKeyCacheTTL, err := time.ParseDuration(getenv("API_KEY_CACHE_TTL", "30s"))
+	if err != nil {
+		log.Fatalf("Некорректное значение API_KEY_CACHE_TTL: %v", err)
+	}
+	tokenValidator = auth.WithAPIKeys(tokenValidator, auth.NewRemoteAPIKeys(userServiceURL+"/api/auth/api-keys/verify", apiKeyCacheTTL))
 
 	// Инициализируем HTTP-обработчики
-	orderHandler := orderHttp.NewHandler(orderUseCase)
+	orderHandler := orderHttp.NewHandler(orderUseCase, tokenValidator)
+
+	// Инициализируем gRPC-обработчик
+	orderGRPCHandler := orderGrpc.NewOrderGRPCHandler(orderUseCase)
 
 	// gRPC сервер
 	lis, err := net.Listen("tcp", ":"+grpcPort)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	g := grpc.NewServer()
-	pb.RegisterOrderServiceServer(g, &server{})
+	g := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			auth.UnaryServerInterceptor(tokenValidator, auth.ReflectionMethods...),
+			auth.UnaryRoleInterceptor(orderGrpc.RolePolicy),
+			auth.UnaryScopeInterceptor(orderGrpc.ScopePolicy),
+		),
+		grpc.ChainStreamInterceptor(
+			auth.StreamServerInterceptor(tokenValidator, auth.ReflectionMethods...),
+			auth.StreamRoleInterceptor(orderGrpc.RolePolicy),
+			auth.StreamScopeInterceptor(orderGrpc.ScopePolicy),
+		),
+	)
+	pb.RegisterOrderServiceServer(g, orderGRPCHandler)
+	reflection.Register(g)
 
 	go func() {
 		log.Printf("gRPC server listening on :%s", grpcPort)
@@ -119,6 +213,8 @@ func main() {
 	<-sigs
 
 	log.Println("shutting down servers...")
+	close(stopExpiry)
+	close(stopDenylist)
 
 	// Корректное завершение HTTP сервера
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -133,6 +229,49 @@ func main() {
 	log.Println("servers stopped")
 }
 
+// expireOrders раз в минуту переводит просроченные неоплаченные заказы в статус EXPIRED
+func expireOrders(uc usecase.UseCase, paymentTimeout time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			expired, err := uc.ExpireOrders(context.Background(), paymentTimeout)
+			if err != nil {
+				log.Printf("Ошибка завершения просроченных заказов: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("Завершено просроченных заказов: %d", expired)
+			}
+		}
+	}
+}
+
+// retryStockRelease периодически отменяет резервы, оставшиеся у отмененных, возвращенных и просроченных заказов
+func retryStockRelease(uc usecase.UseCase, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			released, err := uc.RetryStockRelease(context.Background())
+			if err != nil {
+				log.Printf("Ошибка повторной отмены резервов: %v", err)
+			}
+			if released > 0 {
+				log.Printf("Повторно отменено резервов: %d", released)
+			}
+		}
+	}
+}
+
 func getenv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -1,6 +1,90 @@
 -- Удаляем расширение pgcrypto, оно нам больше не нужно
 -- CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
+-- Площадки, фестивали и сцены фестивалей
+CREATE TABLE IF NOT EXISTS venues (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    city VARCHAR(128) NOT NULL,
+    country VARCHAR(128) NOT NULL DEFAULT '',
+    capacity INT NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS festivals (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    venue_id INT NOT NULL REFERENCES venues (id),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    status VARCHAR(16) NOT NULL DEFAULT 'DRAFT',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS festivals_start_idx ON festivals (start_date, id);
+CREATE INDEX IF NOT EXISTS festivals_venue_idx ON festivals (venue_id);
+
+CREATE TABLE IF NOT EXISTS stages (
+    id SERIAL PRIMARY KEY,
+    festival_id INT NOT NULL REFERENCES festivals (id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    capacity INT NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Названия сцен уникальны в пределах фестиваля без учета регистра
+CREATE UNIQUE INDEX IF NOT EXISTS stages_festival_name_idx ON stages (festival_id, lower(name));
+
+-- Исполнители и выступления (лайн-ап) фестивалей
+CREATE TABLE IF NOT EXISTS artists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    genre VARCHAR(128) NOT NULL DEFAULT '',
+    country VARCHAR(128) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS artists_name_idx ON artists (name, id);
+
+-- btree_gist нужен для ограничения исключения по сцене и интервалу времени
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS lineup_slots (
+    id SERIAL PRIMARY KEY,
+    festival_id INT NOT NULL REFERENCES festivals (id) ON DELETE CASCADE,
+    stage_id INT NOT NULL REFERENCES stages (id),
+    artist_id INT NOT NULL REFERENCES artists (id),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    -- Выступления на одной сцене не пересекаются; выступления встык допустимы ([) интервалы)
+    CONSTRAINT lineup_slots_no_overlap EXCLUDE USING gist (stage_id WITH =, tstzrange(starts_at, ends_at) WITH &&)
+);
+
+CREATE INDEX IF NOT EXISTS lineup_slots_festival_idx ON lineup_slots (festival_id, starts_at);
+CREATE INDEX IF NOT EXISTS lineup_slots_artist_idx ON lineup_slots (artist_id);
+
+-- Личные расписания: выступления, которые пользователь собирается посетить
+CREATE TABLE IF NOT EXISTS schedule_selections (
+    user_id VARCHAR(255) NOT NULL,
+    slot_id INT NOT NULL REFERENCES lineup_slots (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, slot_id)
+);
+
+CREATE INDEX IF NOT EXISTS schedule_selections_slot_idx ON schedule_selections (slot_id);
+
 CREATE TABLE IF NOT EXISTS products (
     id SERIAL PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
@@ -8,14 +92,80 @@ CREATE TABLE IF NOT EXISTS products (
     price NUMERIC(10,2) NOT NULL,
     type VARCHAR(32) NOT NULL,
     stock INT NOT NULL,
-    festival_id INT,
+    festival_id INT REFERENCES festivals (id), -- Фестиваль с продуктами удалить нельзя
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Индексы каталога: сортировка с id вторым ключом (keyset-пагинация) и фильтры ListProducts
+CREATE INDEX IF NOT EXISTS products_created_idx ON products (created_at, id);
+CREATE INDEX IF NOT EXISTS products_price_idx ON products (price, id);
+CREATE INDEX IF NOT EXISTS products_name_idx ON products (name, id);
+CREATE INDEX IF NOT EXISTS products_type_idx ON products (type, created_at, id);
+CREATE INDEX IF NOT EXISTS products_festival_idx ON products (festival_id, created_at, id) WHERE festival_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS products_in_stock_idx ON products (created_at, id) WHERE stock <> 0;
+-- Поиск подстроки (ILIKE '%...%') в названии и описании
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS products_name_trgm_idx ON products USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS products_description_trgm_idx ON products USING GIN (description gin_trgm_ops);
+
+-- Полнотекстовый поиск: лексемы названия (вес A) и описания (вес B) по русской и английской конфигурациям
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS products_search_idx ON products USING GIN (search_vector);
+
+-- Варианты продукта (например, размер и цвет футболки) со своим артикулом, ценой и остатком.
+-- Если у продукта есть варианты, products.stock хранит их суммарный остаток (-1, если хотя бы один неограничен)
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL UNIQUE,
+    attributes JSONB NOT NULL DEFAULT '{}',
+    price NUMERIC(10,2) CHECK (price >= 0), -- NULL - цена продукта
+    stock INT NOT NULL CHECK (stock >= -1),
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Набор атрибутов уникален в пределах продукта
+CREATE UNIQUE INDEX IF NOT EXISTS product_variants_attributes_idx ON product_variants (product_id, attributes);
+
+-- Резервы остатков под заказы из order-service
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id UUID PRIMARY KEY,
+    product_id INT NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    order_id VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    status VARCHAR(16) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservations_order_idx ON stock_reservations (order_id);
+
+-- Резерв варианта. Вариант с действующими резервами удалить нельзя (проверяется при замене вариантов),
+-- у отмененных резервов удаленного варианта ссылка обнуляется
+ALTER TABLE stock_reservations ADD COLUMN IF NOT EXISTS variant_id INT REFERENCES product_variants (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS stock_reservations_variant_idx ON stock_reservations (variant_id) WHERE variant_id IS NOT NULL;
+
 -- Добавим несколько базовых товаров
 INSERT INTO products (name, description, price, type, stock)
 VALUES
   ('VIP Ticket', 'Access to VIP zone', 150.00, 'TICKET', 1),
   ('Festival T-Shirt', 'Official festival merchandise', 25.00, 'MERCHANDISE', 100),
   ('Standard Ticket', 'General admission', 50.00, 'TICKET', 500);
+
+-- Размеры футболки; остаток продукта (100) - сумма остатков вариантов
+INSERT INTO product_variants (product_id, sku, attributes, stock)
+SELECT p.id, v.sku, v.attributes::jsonb, v.stock
+FROM products p, (VALUES
+  ('TSHIRT-S', '{"size": "S"}', 20),
+  ('TSHIRT-M', '{"size": "M"}', 30),
+  ('TSHIRT-L', '{"size": "L"}', 30),
+  ('TSHIRT-XL', '{"size": "XL"}', 20)
+) AS v (sku, attributes, stock)
+WHERE p.name = 'Festival T-Shirt';
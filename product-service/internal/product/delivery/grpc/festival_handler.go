@@ -0,0 +1,424 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	pb "github.com/Hayzerr/go-microservice-project/pb"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FestivalGRPCHandler реализует gRPC сервер для FestivalService.
+// Доступ к методам задается общими PublicMethods, RolePolicy и ScopePolicy.
+type FestivalGRPCHandler struct {
+	pb.UnimplementedFestivalServiceServer
+	festivalUsecase usecase.FestivalUsecase
+}
+
+// NewFestivalGRPCHandler создает новый экземпляр FestivalGRPCHandler.
+func NewFestivalGRPCHandler(uc usecase.FestivalUsecase) *FestivalGRPCHandler {
+	return &FestivalGRPCHandler{festivalUsecase: uc}
+}
+
+// festivalStatusesToProto сопоставляет статусы фестиваля значениям перечисления FestivalStatusProto
+var festivalStatusesToProto = map[models.FestivalStatus]pb.FestivalStatusProto{
+	models.FestivalDraft:    pb.FestivalStatusProto_FESTIVAL_DRAFT,
+	models.FestivalOnSale:   pb.FestivalStatusProto_FESTIVAL_ON_SALE,
+	models.FestivalSoldOut:  pb.FestivalStatusProto_FESTIVAL_SOLD_OUT,
+	models.FestivalFinished: pb.FestivalStatusProto_FESTIVAL_FINISHED,
+}
+
+// mapFestivalStatusToProto преобразует models.FestivalStatus в pb.FestivalStatusProto.
+func mapFestivalStatusToProto(festivalStatus models.FestivalStatus) pb.FestivalStatusProto {
+	return festivalStatusesToProto[festivalStatus]
+}
+
+// mapProtoToFestivalStatus преобразует pb.FestivalStatusProto в models.FestivalStatus; для UNSPECIFIED - пустая строка.
+func mapProtoToFestivalStatus(protoStatus pb.FestivalStatusProto) models.FestivalStatus {
+	for festivalStatus, value := range festivalStatusesToProto {
+		if value == protoStatus {
+			return festivalStatus
+		}
+	}
+	return ""
+}
+
+// mapVenueModelToProto преобразует модель Venue в proto-сообщение Venue.
+func mapVenueModelToProto(venue *models.Venue) *pb.Venue {
+	if venue == nil {
+		return nil
+	}
+	return &pb.Venue{
+		Id:        strconv.Itoa(venue.ID),
+		Name:      venue.Name,
+		Address:   venue.Address,
+		City:      venue.City,
+		Country:   venue.Country,
+		Capacity:  int32(venue.Capacity),
+		CreatedAt: timestamppb.New(venue.CreatedAt),
+		UpdatedAt: timestamppb.New(venue.UpdatedAt),
+	}
+}
+
+// mapStageModelToProto преобразует модель Stage в proto-сообщение Stage.
+func mapStageModelToProto(stage *models.Stage) *pb.Stage {
+	return &pb.Stage{
+		Id:         strconv.Itoa(stage.ID),
+		FestivalId: strconv.Itoa(stage.FestivalID),
+		Name:       stage.Name,
+		Capacity:   int32(stage.Capacity),
+		CreatedAt:  timestamppb.New(stage.CreatedAt),
+		UpdatedAt:  timestamppb.New(stage.UpdatedAt),
+	}
+}
+
+// mapFestivalModelToProto преобразует модель Festival в proto-сообщение Festival.
+func mapFestivalModelToProto(festival *models.Festival) *pb.Festival {
+	stages := make([]*pb.Stage, len(festival.Stages))
+	for i, stage := range festival.Stages {
+		stages[i] = mapStageModelToProto(stage)
+	}
+	return &pb.Festival{
+		Id:          strconv.Itoa(festival.ID),
+		Name:        festival.Name,
+		Description: festival.Description,
+		Venue:       mapVenueModelToProto(festival.Venue),
+		StartDate:   festival.StartDate,
+		EndDate:     festival.EndDate,
+		Timezone:    festival.Timezone,
+		Status:      mapFestivalStatusToProto(festival.Status),
+		Stages:      stages,
+		CreatedAt:   timestamppb.New(festival.CreatedAt),
+		UpdatedAt:   timestamppb.New(festival.UpdatedAt),
+	}
+}
+
+// mapFestivalSummaryToProto преобразует краткие сведения о фестивале продукта; nil, если их не запрашивали.
+func mapFestivalSummaryToProto(summary *models.FestivalSummary) *pb.FestivalSummary {
+	if summary == nil {
+		return nil
+	}
+	return &pb.FestivalSummary{
+		Id:        strconv.Itoa(summary.ID),
+		Name:      summary.Name,
+		StartDate: summary.StartDate,
+		EndDate:   summary.EndDate,
+		Status:    mapFestivalStatusToProto(summary.Status),
+		VenueName: summary.VenueName,
+		City:      summary.City,
+	}
+}
+
+// mapFestivalErrorToStatus преобразует ошибки площадок, фестивалей, сцен и лайн-апа в gRPC статусы.
+func mapFestivalErrorToStatus(err error, message string) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrFestivalNotFound), errors.Is(err, usecase.ErrVenueNotFound),
+		errors.Is(err, usecase.ErrStageNotFound), errors.Is(err, usecase.ErrArtistNotFound),
+		errors.Is(err, usecase.ErrSlotNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrStageExists):
+		return status.Errorf(codes.AlreadyExists, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrFestivalInUse), errors.Is(err, usecase.ErrVenueInUse),
+		errors.Is(err, usecase.ErrInvalidStatusTransition), errors.Is(err, usecase.ErrStageInUse),
+		errors.Is(err, usecase.ErrArtistInUse), errors.Is(err, usecase.ErrSlotClash):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", message, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", message, err)
+	}
+}
+
+// parseID разбирает числовой ID из запроса; name используется в тексте ошибки.
+func parseID(value, name string) (int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "Неверный формат ID %s: %q", name, value)
+	}
+	return id, nil
+}
+
+// CreateVenue обрабатывает gRPC запрос на создание площадки.
+func (h *FestivalGRPCHandler) CreateVenue(ctx context.Context, req *pb.CreateVenueRequest) (*pb.VenueResponse, error) {
+	venue, err := h.festivalUsecase.CreateVenue(ctx, usecase.CreateVenueInput{
+		Name:     req.GetName(),
+		Address:  req.GetAddress(),
+		City:     req.GetCity(),
+		Country:  req.GetCountry(),
+		Capacity: int(req.GetCapacity()),
+	})
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при создании площадки")
+	}
+	return &pb.VenueResponse{Venue: mapVenueModelToProto(venue)}, nil
+}
+
+// GetVenue обрабатывает gRPC запрос на получение площадки.
+func (h *FestivalGRPCHandler) GetVenue(ctx context.Context, req *pb.GetVenueRequest) (*pb.VenueResponse, error) {
+	venueID, err := parseID(req.GetId(), "площадки")
+	if err != nil {
+		return nil, err
+	}
+	venue, err := h.festivalUsecase.GetVenue(ctx, venueID)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении площадки")
+	}
+	return &pb.VenueResponse{Venue: mapVenueModelToProto(venue)}, nil
+}
+
+// ListVenues обрабатывает gRPC запрос на получение списка площадок.
+func (h *FestivalGRPCHandler) ListVenues(ctx context.Context, req *pb.ListVenuesRequest) (*pb.ListVenuesResponse, error) {
+	venues, err := h.festivalUsecase.ListVenues(ctx)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении списка площадок")
+	}
+	pbVenues := make([]*pb.Venue, len(venues))
+	for i, venue := range venues {
+		pbVenues[i] = mapVenueModelToProto(venue)
+	}
+	return &pb.ListVenuesResponse{Venues: pbVenues}, nil
+}
+
+// UpdateVenue обрабатывает gRPC запрос на изменение площадки.
+func (h *FestivalGRPCHandler) UpdateVenue(ctx context.Context, req *pb.UpdateVenueRequest) (*pb.VenueResponse, error) {
+	venueID, err := parseID(req.GetId(), "площадки")
+	if err != nil {
+		return nil, err
+	}
+	input := usecase.UpdateVenueInput{}
+	if req.Name != nil {
+		input.Name = &req.Name.Value
+	}
+	if req.Address != nil {
+		input.Address = &req.Address.Value
+	}
+	if req.City != nil {
+		input.City = &req.City.Value
+	}
+	if req.Country != nil {
+		input.Country = &req.Country.Value
+	}
+	if req.Capacity != nil {
+		capacity := int(req.GetCapacity().GetValue())
+		input.Capacity = &capacity
+	}
+
+	venue, err := h.festivalUsecase.UpdateVenue(ctx, venueID, input)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при изменении площадки")
+	}
+	return &pb.VenueResponse{Venue: mapVenueModelToProto(venue)}, nil
+}
+
+// DeleteVenue обрабатывает gRPC запрос на удаление площадки.
+func (h *FestivalGRPCHandler) DeleteVenue(ctx context.Context, req *pb.DeleteVenueRequest) (*emptypb.Empty, error) {
+	venueID, err := parseID(req.GetId(), "площадки")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.festivalUsecase.DeleteVenue(ctx, venueID); err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при удалении площадки")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// CreateFestival обрабатывает gRPC запрос на создание фестиваля со сценами.
+func (h *FestivalGRPCHandler) CreateFestival(ctx context.Context, req *pb.CreateFestivalRequest) (*pb.FestivalResponse, error) {
+	venueID, err := parseID(req.GetVenueId(), "площадки")
+	if err != nil {
+		return nil, err
+	}
+	input := usecase.CreateFestivalInput{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+		VenueID:     venueID,
+		StartDate:   req.GetStartDate(),
+		EndDate:     req.GetEndDate(),
+		Timezone:    req.GetTimezone(),
+		Status:      mapProtoToFestivalStatus(req.GetStatus()),
+	}
+	for _, stage := range req.GetStages() {
+		input.Stages = append(input.Stages, usecase.StageInput{Name: stage.GetName(), Capacity: int(stage.GetCapacity())})
+	}
+
+	festival, err := h.festivalUsecase.CreateFestival(ctx, input)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при создании фестиваля")
+	}
+	return &pb.FestivalResponse{Festival: mapFestivalModelToProto(festival)}, nil
+}
+
+// GetFestival обрабатывает gRPC запрос на получение фестиваля с площадкой и сценами.
+func (h *FestivalGRPCHandler) GetFestival(ctx context.Context, req *pb.GetFestivalRequest) (*pb.FestivalResponse, error) {
+	festivalID, err := parseID(req.GetId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	festival, err := h.festivalUsecase.GetFestival(ctx, festivalID)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении фестиваля")
+	}
+	return &pb.FestivalResponse{Festival: mapFestivalModelToProto(festival)}, nil
+}
+
+// ListFestivals обрабатывает gRPC запрос на получение списка фестивалей.
+func (h *FestivalGRPCHandler) ListFestivals(ctx context.Context, req *pb.ListFestivalsRequest) (*pb.ListFestivalsResponse, error) {
+	filter := repository.FestivalFilter{
+		Status: mapProtoToFestivalStatus(req.GetStatus()),
+		City:   req.GetCity(),
+		From:   req.GetFrom(),
+	}
+	if req.GetVenueId() != "" {
+		venueID, err := parseID(req.GetVenueId(), "площадки")
+		if err != nil {
+			return nil, err
+		}
+		filter.VenueID = &venueID
+	}
+
+	festivals, err := h.festivalUsecase.ListFestivals(ctx, filter)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении списка фестивалей")
+	}
+	pbFestivals := make([]*pb.Festival, len(festivals))
+	for i, festival := range festivals {
+		pbFestivals[i] = mapFestivalModelToProto(festival)
+	}
+	return &pb.ListFestivalsResponse{Festivals: pbFestivals}, nil
+}
+
+// UpdateFestival обрабатывает gRPC запрос на изменение фестиваля.
+func (h *FestivalGRPCHandler) UpdateFestival(ctx context.Context, req *pb.UpdateFestivalRequest) (*pb.FestivalResponse, error) {
+	festivalID, err := parseID(req.GetId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	input := usecase.UpdateFestivalInput{}
+	if req.Name != nil {
+		input.Name = &req.Name.Value
+	}
+	if req.Description != nil {
+		input.Description = &req.Description.Value
+	}
+	if req.VenueId != nil {
+		venueID, err := parseID(req.GetVenueId().GetValue(), "площадки")
+		if err != nil {
+			return nil, err
+		}
+		input.VenueID = &venueID
+	}
+	if req.StartDate != nil {
+		input.StartDate = &req.StartDate.Value
+	}
+	if req.EndDate != nil {
+		input.EndDate = &req.EndDate.Value
+	}
+	if req.Timezone != nil {
+		input.Timezone = &req.Timezone.Value
+	}
+	if req.GetStatus() != pb.FestivalStatusProto_FESTIVAL_STATUS_UNSPECIFIED {
+		festivalStatus := mapProtoToFestivalStatus(req.GetStatus())
+		input.Status = &festivalStatus
+	}
+
+	festival, err := h.festivalUsecase.UpdateFestival(ctx, festivalID, input)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при изменении фестиваля")
+	}
+	return &pb.FestivalResponse{Festival: mapFestivalModelToProto(festival)}, nil
+}
+
+// DeleteFestival обрабатывает gRPC запрос на удаление фестиваля.
+func (h *FestivalGRPCHandler) DeleteFestival(ctx context.Context, req *pb.DeleteFestivalRequest) (*emptypb.Empty, error) {
+	festivalID, err := parseID(req.GetId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.festivalUsecase.DeleteFestival(ctx, festivalID); err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при удалении фестиваля")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListStages обрабатывает gRPC запрос на получение сцен фестиваля.
+func (h *FestivalGRPCHandler) ListStages(ctx context.Context, req *pb.ListStagesRequest) (*pb.ListStagesResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	stages, err := h.festivalUsecase.ListStages(ctx, festivalID)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении сцен")
+	}
+	pbStages := make([]*pb.Stage, len(stages))
+	for i, stage := range stages {
+		pbStages[i] = mapStageModelToProto(stage)
+	}
+	return &pb.ListStagesResponse{Stages: pbStages}, nil
+}
+
+// CreateStage обрабатывает gRPC запрос на добавление сцены.
+func (h *FestivalGRPCHandler) CreateStage(ctx context.Context, req *pb.CreateStageRequest) (*pb.StageResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	stage, err := h.festivalUsecase.CreateStage(ctx, festivalID, usecase.StageInput{
+		Name:     req.GetName(),
+		Capacity: int(req.GetCapacity()),
+	})
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при добавлении сцены")
+	}
+	return &pb.StageResponse{Stage: mapStageModelToProto(stage)}, nil
+}
+
+// UpdateStage обрабатывает gRPC запрос на изменение сцены.
+func (h *FestivalGRPCHandler) UpdateStage(ctx context.Context, req *pb.UpdateStageRequest) (*pb.StageResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	stageID, err := parseID(req.GetId(), "сцены")
+	if err != nil {
+		return nil, err
+	}
+	input := usecase.UpdateStageInput{}
+	if req.Name != nil {
+		input.Name = &req.Name.Value
+	}
+	if req.Capacity != nil {
+		capacity := int(req.GetCapacity().GetValue())
+		input.Capacity = &capacity
+	}
+
+	stage, err := h.festivalUsecase.UpdateStage(ctx, festivalID, stageID, input)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при изменении сцены")
+	}
+	return &pb.StageResponse{Stage: mapStageModelToProto(stage)}, nil
+}
+
+// DeleteStage обрабатывает gRPC запрос на удаление сцены.
+func (h *FestivalGRPCHandler) DeleteStage(ctx context.Context, req *pb.DeleteStageRequest) (*emptypb.Empty, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	stageID, err := parseID(req.GetId(), "сцены")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.festivalUsecase.DeleteStage(ctx, festivalID, stageID); err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при удалении сцены")
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,423 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	pb "github.com/Hayzerr/go-microservice-project/pb"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// LineupGRPCHandler реализует gRPC сервер для LineupService.
+// Доступ к методам задается общими PublicMethods, RolePolicy и ScopePolicy.
+type LineupGRPCHandler struct {
+	pb.UnimplementedLineupServiceServer
+	lineupUsecase usecase.LineupUsecase
+}
+
+// NewLineupGRPCHandler создает новый экземпляр LineupGRPCHandler.
+func NewLineupGRPCHandler(uc usecase.LineupUsecase) *LineupGRPCHandler {
+	return &LineupGRPCHandler{lineupUsecase: uc}
+}
+
+// mapArtistModelToProto преобразует модель Artist в proto-сообщение Artist.
+func mapArtistModelToProto(artist *models.Artist) *pb.Artist {
+	if artist == nil {
+		return nil
+	}
+	return &pb.Artist{
+		Id:          strconv.Itoa(artist.ID),
+		Name:        artist.Name,
+		Genre:       artist.Genre,
+		Country:     artist.Country,
+		Description: artist.Description,
+		CreatedAt:   timestamppb.New(artist.CreatedAt),
+		UpdatedAt:   timestamppb.New(artist.UpdatedAt),
+	}
+}
+
+// mapSlotModelToProto преобразует модель Slot в proto-сообщение Slot.
+func mapSlotModelToProto(slot *models.Slot) *pb.Slot {
+	return &pb.Slot{
+		Id:         strconv.Itoa(slot.ID),
+		FestivalId: strconv.Itoa(slot.FestivalID),
+		StageId:    strconv.Itoa(slot.StageID),
+		StageName:  slot.StageName,
+		Artist:     mapArtistModelToProto(slot.Artist),
+		StartsAt:   timestamppb.New(slot.StartsAt),
+		EndsAt:     timestamppb.New(slot.EndsAt),
+		CreatedAt:  timestamppb.New(slot.CreatedAt),
+		UpdatedAt:  timestamppb.New(slot.UpdatedAt),
+	}
+}
+
+// mapSlotsToProto преобразует список выступлений.
+func mapSlotsToProto(slots []*models.Slot) []*pb.Slot {
+	pbSlots := make([]*pb.Slot, len(slots))
+	for i, slot := range slots {
+		pbSlots[i] = mapSlotModelToProto(slot)
+	}
+	return pbSlots
+}
+
+// timestampValue возвращает время из необязательного Timestamp; nil - нулевое время.
+func timestampValue(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+// callerUserID возвращает ID пользователя из токена вызова.
+func callerUserID(ctx context.Context) (string, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "Требуется авторизация")
+	}
+	return userID, nil
+}
+
+// CreateArtist обрабатывает gRPC запрос на создание исполнителя.
+func (h *LineupGRPCHandler) CreateArtist(ctx context.Context, req *pb.CreateArtistRequest) (*pb.ArtistResponse, error) {
+	artist, err := h.lineupUsecase.CreateArtist(ctx, usecase.CreateArtistInput{
+		Name:        req.GetName(),
+		Genre:       req.GetGenre(),
+		Country:     req.GetCountry(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при создании исполнителя")
+	}
+	return &pb.ArtistResponse{Artist: mapArtistModelToProto(artist)}, nil
+}
+
+// GetArtist обрабатывает gRPC запрос на получение исполнителя.
+func (h *LineupGRPCHandler) GetArtist(ctx context.Context, req *pb.GetArtistRequest) (*pb.ArtistResponse, error) {
+	artistID, err := parseID(req.GetId(), "исполнителя")
+	if err != nil {
+		return nil, err
+	}
+	artist, err := h.lineupUsecase.GetArtist(ctx, artistID)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении исполнителя")
+	}
+	return &pb.ArtistResponse{Artist: mapArtistModelToProto(artist)}, nil
+}
+
+// ListArtists обрабатывает gRPC запрос на получение списка исполнителей.
+func (h *LineupGRPCHandler) ListArtists(ctx context.Context, req *pb.ListArtistsRequest) (*pb.ListArtistsResponse, error) {
+	artists, err := h.lineupUsecase.ListArtists(ctx, req.GetQuery())
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении списка исполнителей")
+	}
+	pbArtists := make([]*pb.Artist, len(artists))
+	for i, artist := range artists {
+		pbArtists[i] = mapArtistModelToProto(artist)
+	}
+	return &pb.ListArtistsResponse{Artists: pbArtists}, nil
+}
+
+// UpdateArtist обрабатывает gRPC запрос на изменение исполнителя.
+func (h *LineupGRPCHandler) UpdateArtist(ctx context.Context, req *pb.UpdateArtistRequest) (*pb.ArtistResponse, error) {
+	artistID, err := parseID(req.GetId(), "исполнителя")
+	if err != nil {
+		return nil, err
+	}
+	input := usecase.UpdateArtistInput{}
+	if req.Name != nil {
+		input.Name = &req.Name.Value
+	}
+	if req.Genre != nil {
+		input.Genre = &req.Genre.Value
+	}
+	if req.Country != nil {
+		input.Country = &req.Country.Value
+	}
+	if req.Description != nil {
+		input.Description = &req.Description.Value
+	}
+
+	artist, err := h.lineupUsecase.UpdateArtist(ctx, artistID, input)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при изменении исполнителя")
+	}
+	return &pb.ArtistResponse{Artist: mapArtistModelToProto(artist)}, nil
+}
+
+// DeleteArtist обрабатывает gRPC запрос на удаление исполнителя.
+func (h *LineupGRPCHandler) DeleteArtist(ctx context.Context, req *pb.DeleteArtistRequest) (*emptypb.Empty, error) {
+	artistID, err := parseID(req.GetId(), "исполнителя")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.lineupUsecase.DeleteArtist(ctx, artistID); err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при удалении исполнителя")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListSlots обрабатывает gRPC запрос на получение выступлений фестиваля.
+func (h *LineupGRPCHandler) ListSlots(ctx context.Context, req *pb.ListSlotsRequest) (*pb.ListSlotsResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.SlotFilter{}
+	if req.GetStageId() != "" {
+		stageID, err := parseID(req.GetStageId(), "сцены")
+		if err != nil {
+			return nil, err
+		}
+		filter.StageID = &stageID
+	}
+	if req.GetArtistId() != "" {
+		artistID, err := parseID(req.GetArtistId(), "исполнителя")
+		if err != nil {
+			return nil, err
+		}
+		filter.ArtistID = &artistID
+	}
+	if req.From != nil {
+		from := req.GetFrom().AsTime()
+		filter.From = &from
+	}
+	if req.To != nil {
+		to := req.GetTo().AsTime()
+		filter.To = &to
+	}
+
+	slots, err := h.lineupUsecase.ListSlots(ctx, festivalID, filter)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении выступлений")
+	}
+	return &pb.ListSlotsResponse{Slots: mapSlotsToProto(slots)}, nil
+}
+
+// CreateSlot обрабатывает gRPC запрос на добавление выступления.
+// Пересечение с другим выступлением на сцене возвращается как FailedPrecondition с описанием пересечений.
+func (h *LineupGRPCHandler) CreateSlot(ctx context.Context, req *pb.CreateSlotRequest) (*pb.SlotResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	stageID, err := parseID(req.GetStageId(), "сцены")
+	if err != nil {
+		return nil, err
+	}
+	artistID, err := parseID(req.GetArtistId(), "исполнителя")
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := h.lineupUsecase.CreateSlot(ctx, festivalID, usecase.SlotInput{
+		StageID:  stageID,
+		ArtistID: artistID,
+		StartsAt: timestampValue(req.GetStartsAt()),
+		EndsAt:   timestampValue(req.GetEndsAt()),
+	})
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при добавлении выступления")
+	}
+	return &pb.SlotResponse{Slot: mapSlotModelToProto(slot)}, nil
+}
+
+// UpdateSlot обрабатывает gRPC запрос на изменение выступления.
+func (h *LineupGRPCHandler) UpdateSlot(ctx context.Context, req *pb.UpdateSlotRequest) (*pb.SlotResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	slotID, err := parseID(req.GetId(), "выступления")
+	if err != nil {
+		return nil, err
+	}
+	input := usecase.UpdateSlotInput{}
+	if req.StageId != nil {
+		stageID, err := parseID(req.GetStageId().GetValue(), "сцены")
+		if err != nil {
+			return nil, err
+		}
+		input.StageID = &stageID
+	}
+	if req.ArtistId != nil {
+		artistID, err := parseID(req.GetArtistId().GetValue(), "исполнителя")
+		if err != nil {
+			return nil, err
+		}
+		input.ArtistID = &artistID
+	}
+	if req.StartsAt != nil {
+		startsAt := req.GetStartsAt().AsTime()
+		input.StartsAt = &startsAt
+	}
+	if req.EndsAt != nil {
+		endsAt := req.GetEndsAt().AsTime()
+		input.EndsAt = &endsAt
+	}
+
+	slot, err := h.lineupUsecase.UpdateSlot(ctx, festivalID, slotID, input)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при изменении выступления")
+	}
+	return &pb.SlotResponse{Slot: mapSlotModelToProto(slot)}, nil
+}
+
+// DeleteSlot обрабатывает gRPC запрос на удаление выступления.
+func (h *LineupGRPCHandler) DeleteSlot(ctx context.Context, req *pb.DeleteSlotRequest) (*emptypb.Empty, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	slotID, err := parseID(req.GetId(), "выступления")
+	if err != nil {
+		return nil, err
+	}
+	if err := h.lineupUsecase.DeleteSlot(ctx, festivalID, slotID); err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при удалении выступления")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// GetSchedule обрабатывает gRPC запрос на получение сетки расписания "день x сцена".
+func (h *LineupGRPCHandler) GetSchedule(ctx context.Context, req *pb.GetScheduleRequest) (*pb.GetScheduleResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := h.lineupUsecase.GetSchedule(ctx, festivalID, req.GetDay())
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении расписания")
+	}
+
+	resp := &pb.GetScheduleResponse{
+		FestivalId:   strconv.Itoa(schedule.FestivalID),
+		FestivalName: schedule.FestivalName,
+		Timezone:     schedule.Timezone,
+		Days:         make([]*pb.ScheduleDay, len(schedule.Days)),
+	}
+	for i, day := range schedule.Days {
+		pbDay := &pb.ScheduleDay{Date: day.Date, Stages: make([]*pb.ScheduleStage, len(day.Stages))}
+		for j, stage := range day.Stages {
+			pbDay.Stages[j] = &pb.ScheduleStage{
+				StageId:   strconv.Itoa(stage.StageID),
+				StageName: stage.StageName,
+				Slots:     mapSlotsToProto(stage.Slots),
+			}
+		}
+		resp.Days[i] = pbDay
+	}
+	return resp, nil
+}
+
+// ExportSchedule обрабатывает gRPC запрос на выгрузку расписания фестиваля в iCalendar.
+func (h *LineupGRPCHandler) ExportSchedule(ctx context.Context, req *pb.ExportScheduleRequest) (*pb.ExportScheduleResponse, error) {
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := h.lineupUsecase.ExportICS(ctx, festivalID, "")
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при выгрузке расписания")
+	}
+	return &pb.ExportScheduleResponse{
+		Filename:    fmt.Sprintf("festival-%d.ics", festivalID),
+		ContentType: "text/calendar",
+		Ics:         string(calendar),
+	}, nil
+}
+
+// GetMySchedule обрабатывает gRPC запрос на получение личного расписания.
+func (h *LineupGRPCHandler) GetMySchedule(ctx context.Context, req *pb.GetMyScheduleRequest) (*pb.GetMyScheduleResponse, error) {
+	userID, err := callerUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := h.lineupUsecase.GetMySchedule(ctx, festivalID, userID)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при получении личного расписания")
+	}
+
+	resp := &pb.GetMyScheduleResponse{Slots: mapSlotsToProto(schedule.Slots)}
+	for _, clash := range schedule.Clashes {
+		resp.Clashes = append(resp.Clashes, &pb.SlotClash{
+			SlotIds: []string{strconv.Itoa(clash.SlotIDs[0]), strconv.Itoa(clash.SlotIDs[1])},
+		})
+	}
+	return resp, nil
+}
+
+// ExportMySchedule обрабатывает gRPC запрос на выгрузку личного расписания в iCalendar.
+func (h *LineupGRPCHandler) ExportMySchedule(ctx context.Context, req *pb.GetMyScheduleRequest) (*pb.ExportScheduleResponse, error) {
+	userID, err := callerUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := h.lineupUsecase.ExportICS(ctx, festivalID, userID)
+	if err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при выгрузке личного расписания")
+	}
+	return &pb.ExportScheduleResponse{
+		Filename:    fmt.Sprintf("festival-%d-my-schedule.ics", festivalID),
+		ContentType: "text/calendar",
+		Ics:         string(calendar),
+	}, nil
+}
+
+// AddToMySchedule обрабатывает gRPC запрос на добавление выступления в личное расписание.
+func (h *LineupGRPCHandler) AddToMySchedule(ctx context.Context, req *pb.MyScheduleSlotRequest) (*emptypb.Empty, error) {
+	userID, festivalID, slotID, err := parseMyScheduleRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.lineupUsecase.AddToMySchedule(ctx, festivalID, userID, slotID); err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при добавлении в личное расписание")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RemoveFromMySchedule обрабатывает gRPC запрос на удаление выступления из личного расписания.
+func (h *LineupGRPCHandler) RemoveFromMySchedule(ctx context.Context, req *pb.MyScheduleSlotRequest) (*emptypb.Empty, error) {
+	userID, festivalID, slotID, err := parseMyScheduleRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.lineupUsecase.RemoveFromMySchedule(ctx, festivalID, userID, slotID); err != nil {
+		return nil, mapFestivalErrorToStatus(err, "Ошибка при удалении из личного расписания")
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// parseMyScheduleRequest возвращает пользователя из токена и ID фестиваля и выступления из запроса.
+func parseMyScheduleRequest(ctx context.Context, req *pb.MyScheduleSlotRequest) (string, int, int, error) {
+	userID, err := callerUserID(ctx)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	festivalID, err := parseID(req.GetFestivalId(), "фестиваля")
+	if err != nil {
+		return "", 0, 0, err
+	}
+	slotID, err := parseID(req.GetSlotId(), "выступления")
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return userID, festivalID, slotID, nil
+}
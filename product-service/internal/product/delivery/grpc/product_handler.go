@@ -4,20 +4,105 @@ import (
 	"context"
 	"errors"
 	"strconv"
+	"strings"
 
 	// ВАЖНО: Замените 'your_product_module_path' на имя вашего модуля product-service из go.mod
 	// Например: "github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
 	// и "github.com/Hayzerr/go-microservice-project/pb"
+	"github.com/Hayzerr/go-microservice-project/auth"
 	pb "github.com/Hayzerr/go-microservice-project/pb" // Сгенерированные proto-файлы
 	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
 	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/usecase"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
+// PublicMethods перечисляет методы ProductService, FestivalService и LineupService, доступные без токена:
+// чтение каталога, фестивалей и расписания.
+// Создание, изменение и удаление продуктов, фестивалей и выступлений требуют токен (перехватчики auth подключаются
+// в main.go) и роль ADMIN (см. RolePolicy). Личное расписание доступно любому пользователю с токеном.
+// Резервы склада доступны сервисному клиенту с разрешением inventory:write и ролям STAFF и ADMIN (см. authorizeInventory).
+var PublicMethods = append([]string{
+	pb.ProductService_GetProduct_FullMethodName,
+	pb.ProductService_ListProducts_FullMethodName,
+	pb.ProductService_SearchProducts_FullMethodName,
+	pb.FestivalService_GetVenue_FullMethodName,
+	pb.FestivalService_ListVenues_FullMethodName,
+	pb.FestivalService_GetFestival_FullMethodName,
+	pb.FestivalService_ListFestivals_FullMethodName,
+	pb.FestivalService_ListStages_FullMethodName,
+	pb.LineupService_GetArtist_FullMethodName,
+	pb.LineupService_ListArtists_FullMethodName,
+	pb.LineupService_ListSlots_FullMethodName,
+	pb.LineupService_GetSchedule_FullMethodName,
+	pb.LineupService_ExportSchedule_FullMethodName,
+}, auth.ReflectionMethods...)
+
+// RolePolicy перечисляет методы ProductService, FestivalService и LineupService, доступные только пользователям с ролью ADMIN.
+var RolePolicy = auth.RolePolicy{
+	pb.ProductService_CreateProduct_FullMethodName:   {auth.RoleAdmin},
+	pb.ProductService_UpdateProduct_FullMethodName:   {auth.RoleAdmin},
+	pb.ProductService_DeleteProduct_FullMethodName:   {auth.RoleAdmin},
+	pb.FestivalService_CreateVenue_FullMethodName:    {auth.RoleAdmin},
+	pb.FestivalService_UpdateVenue_FullMethodName:    {auth.RoleAdmin},
+	pb.FestivalService_DeleteVenue_FullMethodName:    {auth.RoleAdmin},
+	pb.FestivalService_CreateFestival_FullMethodName: {auth.RoleAdmin},
+	pb.FestivalService_UpdateFestival_FullMethodName: {auth.RoleAdmin},
+	pb.FestivalService_DeleteFestival_FullMethodName: {auth.RoleAdmin},
+	pb.FestivalService_CreateStage_FullMethodName:    {auth.RoleAdmin},
+	pb.FestivalService_UpdateStage_FullMethodName:    {auth.RoleAdmin},
+	pb.FestivalService_DeleteStage_FullMethodName:    {auth.RoleAdmin},
+	pb.LineupService_CreateArtist_FullMethodName:     {auth.RoleAdmin},
+	pb.LineupService_UpdateArtist_FullMethodName:     {auth.RoleAdmin},
+	pb.LineupService_DeleteArtist_FullMethodName:     {auth.RoleAdmin},
+	pb.LineupService_CreateSlot_FullMethodName:       {auth.RoleAdmin},
+	pb.LineupService_UpdateSlot_FullMethodName:       {auth.RoleAdmin},
+	pb.LineupService_DeleteSlot_FullMethodName:       {auth.RoleAdmin},
+}
+
+// ScopePolicy перечисляет разрешения API-ключа или токена OAuth2, с которыми доступны изменения каталога,
+// личное расписание и резервы склада. Фестивали и лайн-ап - часть каталога, поэтому их изменения тоже требуют products:write.
+// Открытые методы из PublicMethods вызываются без аутентификации и политикой не проверяются.
+var ScopePolicy = auth.ScopePolicy{
+	pb.ProductService_CreateProduct_FullMethodName:       {auth.ScopeProductsWrite},
+	pb.ProductService_UpdateProduct_FullMethodName:       {auth.ScopeProductsWrite},
+	pb.ProductService_DeleteProduct_FullMethodName:       {auth.ScopeProductsWrite},
+	pb.FestivalService_CreateVenue_FullMethodName:        {auth.ScopeProductsWrite},
+	pb.FestivalService_UpdateVenue_FullMethodName:        {auth.ScopeProductsWrite},
+	pb.FestivalService_DeleteVenue_FullMethodName:        {auth.ScopeProductsWrite},
+	pb.FestivalService_CreateFestival_FullMethodName:     {auth.ScopeProductsWrite},
+	pb.FestivalService_UpdateFestival_FullMethodName:     {auth.ScopeProductsWrite},
+	pb.FestivalService_DeleteFestival_FullMethodName:     {auth.ScopeProductsWrite},
+	pb.FestivalService_CreateStage_FullMethodName:        {auth.ScopeProductsWrite},
+	pb.FestivalService_UpdateStage_FullMethodName:        {auth.ScopeProductsWrite},
+	pb.FestivalService_DeleteStage_FullMethodName:        {auth.ScopeProductsWrite},
+	pb.LineupService_CreateArtist_FullMethodName:         {auth.ScopeProductsWrite},
+	pb.LineupService_UpdateArtist_FullMethodName:         {auth.ScopeProductsWrite},
+	pb.LineupService_DeleteArtist_FullMethodName:         {auth.ScopeProductsWrite},
+	pb.LineupService_CreateSlot_FullMethodName:           {auth.ScopeProductsWrite},
+	pb.LineupService_UpdateSlot_FullMethodName:           {auth.ScopeProductsWrite},
+	pb.LineupService_DeleteSlot_FullMethodName:           {auth.ScopeProductsWrite},
+	pb.LineupService_GetMySchedule_FullMethodName:        {auth.ScopeScheduleRead},
+	pb.LineupService_ExportMySchedule_FullMethodName:     {auth.ScopeScheduleRead},
+	pb.LineupService_AddToMySchedule_FullMethodName:      {auth.ScopeScheduleWrite},
+	pb.LineupService_RemoveFromMySchedule_FullMethodName: {auth.ScopeScheduleWrite},
+	pb.ProductService_ReserveStock_FullMethodName:        {auth.ScopeInventoryWrite},
+	pb.ProductService_CommitReservation_FullMethodName:   {auth.ScopeInventoryWrite},
+	pb.ProductService_ReleaseReservation_FullMethodName:  {auth.ScopeInventoryWrite},
+}
+
+// authorizeInventory проверяет доступ к резервам склада: их создает и закрывает order-service
+// со своим токеном клиента (client credentials) с разрешением inventory:write, а вручную - сотрудники и администраторы.
+// Проверка выполняется в обработчике, потому что у сервисного клиента нет ролей для RolePolicy.
+func authorizeInventory(ctx context.Context) error {
+	return auth.AuthorizeService(ctx, auth.ScopeInventoryWrite, auth.RoleStaff, auth.RoleAdmin)
+}
+
 // ProductGRPCHandler реализует gRPC сервер для ProductService.
 type ProductGRPCHandler struct {
 	pb.UnimplementedProductServiceServer // Встраивание для обратной совместимости
@@ -54,7 +139,68 @@ func mapProductModelToProto(product *models.Product) *pb.Product {
 		FestivalId:  festivalID,
 		CreatedAt:   timestamppb.New(product.CreatedAt),
 		UpdatedAt:   timestamppb.New(product.UpdatedAt),
+		Festival:    mapFestivalSummaryToProto(product.Festival),
+		Variants:    mapVariantsToProto(product.Variants),
+	}
+}
+
+// mapVariantsToProto преобразует варианты продукта в proto-сообщения ProductVariant.
+func mapVariantsToProto(variants []*models.ProductVariant) []*pb.ProductVariant {
+	if len(variants) == 0 {
+		return nil
+	}
+	result := make([]*pb.ProductVariant, len(variants))
+	for i, variant := range variants {
+		result[i] = &pb.ProductVariant{
+			Id:         strconv.Itoa(variant.ID),
+			ProductId:  strconv.Itoa(variant.ProductID),
+			Sku:        variant.SKU,
+			Attributes: variant.Attributes,
+			Stock:      int32(variant.Stock),
+			CreatedAt:  timestamppb.New(variant.CreatedAt),
+			UpdatedAt:  timestamppb.New(variant.UpdatedAt),
+		}
+		if variant.Price != nil {
+			result[i].Price = wrapperspb.Double(*variant.Price)
+		}
 	}
+	return result
+}
+
+// mapProtoToVariantInputs преобразует варианты из запроса во входные данные usecase.
+func mapProtoToVariantInputs(variants []*pb.ProductVariantInput) ([]usecase.VariantInput, error) {
+	inputs := make([]usecase.VariantInput, len(variants))
+	for i, variant := range variants {
+		inputs[i] = usecase.VariantInput{
+			SKU:        variant.GetSku(),
+			Attributes: variant.GetAttributes(),
+			Stock:      int(variant.GetStock()),
+		}
+		if variant.GetId() != "" {
+			id, err := parseID(variant.GetId(), "варианта")
+			if err != nil {
+				return nil, err
+			}
+			inputs[i].ID = id
+		}
+		if variant.Price != nil {
+			price := variant.GetPrice().GetValue()
+			inputs[i].Price = &price
+		}
+	}
+	return inputs, nil
+}
+
+// mapProductSaveErrorToStatus преобразует ошибки вариантов при создании и обновлении продукта в gRPC статусы.
+// Возвращает nil для остальных ошибок.
+func mapProductSaveErrorToStatus(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrVariantNotFound):
+		return status.Errorf(codes.InvalidArgument, "Вариант с указанным id не найден у продукта")
+	case errors.Is(err, usecase.ErrVariantExists), errors.Is(err, usecase.ErrVariantInUse):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	}
+	return nil
 }
 
 // mapProductTypeToProto преобразует models.ProductType в pb.ProductTypeProto.
@@ -84,8 +230,8 @@ func mapProtoToProductType(protoType pb.ProductTypeProto) models.ProductType {
 // CreateProduct обрабатывает gRPC запрос на создание продукта.
 func (h *ProductGRPCHandler) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.CreateProductResponse, error) {
 	// Валидация входных данных (базовая)
-	if req.GetName() == "" || req.GetPrice() < 0 || req.GetStock() < 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "Имя, цена (>=0) и количество на складе (>=0) обязательны")
+	if req.GetName() == "" || req.GetPrice() < 0 || req.GetStock() < usecase.UnlimitedStock {
+		return nil, status.Errorf(codes.InvalidArgument, "Имя, цена (>=0) и количество на складе (>=0 или -1 для неограниченного) обязательны")
 	}
 
 	// Преобразуем festivalId из string в *int, если он задан
@@ -106,13 +252,25 @@ func (h *ProductGRPCHandler) CreateProduct(ctx context.Context, req *pb.CreatePr
 		Stock:       int(req.GetStock()), // Преобразуем int32 в int
 		FestivalID:  festivalID,
 	}
+	if len(req.GetVariants()) > 0 {
+		variants, err := mapProtoToVariantInputs(req.GetVariants())
+		if err != nil {
+			return nil, err
+		}
+		createInput.Variants = variants
+	}
 
 	product, err := h.productUsecase.CreateProduct(ctx, createInput)
 	if err != nil {
 		if errors.Is(err, usecase.ErrInvalidInput) {
 			return nil, status.Errorf(codes.InvalidArgument, "Некорректные входные данные: %v", err)
 		}
-		// TODO: Обработка других специфичных ошибок usecase
+		if errors.Is(err, usecase.ErrFestivalNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "Фестиваль с указанным festival_id не найден")
+		}
+		if st := mapProductSaveErrorToStatus(err); st != nil {
+			return nil, st
+		}
 		return nil, status.Errorf(codes.Internal, "Ошибка при создании продукта: %v", err)
 	}
 
@@ -143,21 +301,97 @@ func (h *ProductGRPCHandler) GetProduct(ctx context.Context, req *pb.GetProductR
 	return &pb.GetProductResponse{Product: mapProductModelToProto(product)}, nil
 }
 
-// ListProducts обрабатывает gRPC запрос на получение списка продуктов.
+// ListProducts обрабатывает gRPC запрос на получение страницы каталога.
 func (h *ProductGRPCHandler) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
-	// TODO: Обработать параметры фильтрации и пагинации из req, если они будут добавлены
+	query := usecase.ProductListQuery{
+		Filter: repository.ProductFilter{
+			Type:    mapProtoToProductType(req.GetType()),
+			InStock: req.GetInStock(),
+			Query:   req.GetQuery(),
+		},
+		Sort:             req.GetSort(),
+		PageSize:         int(req.GetPageSize()),
+		PageToken:        req.GetPageToken(),
+		WithTotal:        true,
+		IncludeFestivals: req.GetIncludeFestivals(),
+	}
+	if req.GetFestivalId() != "" {
+		festivalID, err := strconv.Atoi(req.GetFestivalId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Неверный формат FestivalID: %v", err)
+		}
+		query.Filter.FestivalID = &festivalID
+	}
+	if req.GetMinPrice() != nil {
+		minPrice := req.GetMinPrice().GetValue()
+		query.Filter.MinPrice = &minPrice
+	}
+	if req.GetMaxPrice() != nil {
+		maxPrice := req.GetMaxPrice().GetValue()
+		query.Filter.MaxPrice = &maxPrice
+	}
 
-	products, err := h.productUsecase.ListProducts(ctx)
+	page, err := h.productUsecase.ListProducts(ctx, query)
 	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidPageToken):
+			return nil, status.Errorf(codes.InvalidArgument, "Некорректный page_token: токен выдан для других фильтров или поврежден")
+		case errors.Is(err, usecase.ErrInvalidSort):
+			return nil, status.Errorf(codes.InvalidArgument, "Некорректное значение sort: допустимы created_at, price, name")
+		case errors.Is(err, usecase.ErrInvalidInput):
+			return nil, status.Errorf(codes.InvalidArgument, "Некорректный фильтр: цены неотрицательны, min_price не больше max_price")
+		}
 		return nil, status.Errorf(codes.Internal, "Ошибка при получении списка продуктов: %v", err)
 	}
 
-	pbProducts := make([]*pb.Product, len(products))
-	for i, p := range products {
+	pbProducts := make([]*pb.Product, len(page.Products))
+	for i, p := range page.Products {
 		pbProducts[i] = mapProductModelToProto(p)
 	}
 
-	return &pb.ListProductsResponse{Products: pbProducts}, nil
+	return &pb.ListProductsResponse{Products: pbProducts, NextPageToken: page.NextPageToken, Total: page.Total}, nil
+}
+
+// SearchProducts обрабатывает gRPC запрос полнотекстового поиска по каталогу.
+func (h *ProductGRPCHandler) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Запрос поиска не может быть пустым")
+	}
+	query := usecase.ProductSearchQuery{
+		Text:     req.GetQuery(),
+		Language: req.GetLang(),
+		Filter: repository.ProductFilter{
+			Type:    mapProtoToProductType(req.GetType()),
+			InStock: req.GetInStock(),
+		},
+		Limit: int(req.GetLimit()),
+	}
+	if req.GetFestivalId() != "" {
+		festivalID, err := strconv.Atoi(req.GetFestivalId())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Неверный формат FestivalID: %v", err)
+		}
+		query.Filter.FestivalID = &festivalID
+	}
+
+	results, err := h.productUsecase.SearchProducts(ctx, query)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			return nil, status.Errorf(codes.InvalidArgument, "Некорректный запрос поиска: query до 200 символов, lang ru или en")
+		}
+		return nil, status.Errorf(codes.Internal, "Ошибка поиска продуктов: %v", err)
+	}
+
+	pbResults := make([]*pb.ProductSearchResult, len(results))
+	for i, result := range results {
+		pbResults[i] = &pb.ProductSearchResult{
+			Product:       mapProductModelToProto(result.Product),
+			Rank:          result.Rank,
+			NameHighlight: result.NameHighlight,
+			Snippet:       result.Snippet,
+		}
+	}
+	return &pb.SearchProductsResponse{Results: pbResults}, nil
 }
 
 // UpdateProduct обрабатывает gRPC запрос на обновление продукта.
@@ -205,8 +439,16 @@ func (h *ProductGRPCHandler) UpdateProduct(ctx context.Context, req *pb.UpdatePr
 		}
 	}
 
+	if req.Variants != nil {
+		variants, err := mapProtoToVariantInputs(req.GetVariants().GetVariants())
+		if err != nil {
+			return nil, err
+		}
+		updateInput.Variants = &variants
+	}
+
 	if updateInput.Name == nil && updateInput.Description == nil && updateInput.Price == nil &&
-		updateInput.Type == nil && updateInput.Stock == nil && updateInput.FestivalID == nil {
+		updateInput.Type == nil && updateInput.Stock == nil && updateInput.FestivalID == nil && updateInput.Variants == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Нет данных для обновления")
 	}
 
@@ -217,7 +459,10 @@ func (h *ProductGRPCHandler) UpdateProduct(ctx context.Context, req *pb.UpdatePr
 			return nil, status.Errorf(codes.NotFound, "Продукт для обновления не найден: %v", err)
 		case errors.Is(err, usecase.ErrInvalidInput):
 			return nil, status.Errorf(codes.InvalidArgument, "Некорректные входные данные для обновления: %v", err)
-		// TODO: Обработать другие специфичные ошибки usecase
+		case errors.Is(err, usecase.ErrFestivalNotFound):
+			return nil, status.Errorf(codes.InvalidArgument, "Фестиваль с указанным festival_id не найден")
+		case mapProductSaveErrorToStatus(err) != nil:
+			return nil, mapProductSaveErrorToStatus(err)
 		default:
 			return nil, status.Errorf(codes.Internal, "Ошибка при обновлении продукта: %v", err)
 		}
@@ -248,3 +493,102 @@ func (h *ProductGRPCHandler) DeleteProduct(ctx context.Context, req *pb.DeletePr
 
 	return &emptypb.Empty{}, nil
 }
+
+// mapReservationModelToProto преобразует модель Reservation в proto-сообщение Reservation.
+func mapReservationModelToProto(reservation *models.Reservation) *pb.Reservation {
+	if reservation == nil {
+		return nil
+	}
+	result := &pb.Reservation{
+		Id:        reservation.ID,
+		ProductId: strconv.Itoa(reservation.ProductID),
+		OrderId:   reservation.OrderID,
+		Quantity:  int32(reservation.Quantity),
+		Status:    string(reservation.Status),
+		CreatedAt: timestamppb.New(reservation.CreatedAt),
+		UpdatedAt: timestamppb.New(reservation.UpdatedAt),
+	}
+	if reservation.VariantID != nil {
+		result.VariantId = strconv.Itoa(*reservation.VariantID)
+	}
+	return result
+}
+
+// mapReservationErrorToStatus преобразует ошибки резервирования в gRPC статусы.
+func mapReservationErrorToStatus(err error, message string) error {
+	switch {
+	case errors.Is(err, usecase.ErrProductNotFound), errors.Is(err, usecase.ErrReservationNotFound),
+		errors.Is(err, usecase.ErrVariantNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrInsufficientStock), errors.Is(err, usecase.ErrReservationClosed):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", message, err)
+	case errors.Is(err, usecase.ErrInvalidInput), errors.Is(err, usecase.ErrVariantRequired):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", message, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", message, err)
+	}
+}
+
+// ReserveStock обрабатывает gRPC запрос на резервирование товара под заказ.
+func (h *ProductGRPCHandler) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error) {
+	if err := authorizeInventory(ctx); err != nil {
+		return nil, err
+	}
+	productID, err := strconv.Atoi(req.GetProductId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Неверный формат ID продукта: %v", err)
+	}
+	if req.GetOrderId() == "" || req.GetQuantity() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ID заказа и количество (>0) обязательны")
+	}
+
+	var variantID *int
+	if req.GetVariantId() != "" {
+		id, err := parseID(req.GetVariantId(), "варианта")
+		if err != nil {
+			return nil, err
+		}
+		variantID = &id
+	}
+
+	reservation, err := h.productUsecase.ReserveStock(ctx, productID, variantID, req.GetOrderId(), int(req.GetQuantity()))
+	if err != nil {
+		return nil, mapReservationErrorToStatus(err, "Ошибка при резервировании товара")
+	}
+
+	return &pb.ReserveStockResponse{Reservation: mapReservationModelToProto(reservation)}, nil
+}
+
+// CommitReservation обрабатывает gRPC запрос на подтверждение резерва.
+func (h *ProductGRPCHandler) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.CommitReservationResponse, error) {
+	if err := authorizeInventory(ctx); err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID резерва не может быть пустым")
+	}
+
+	reservation, err := h.productUsecase.CommitReservation(ctx, req.GetId())
+	if err != nil {
+		return nil, mapReservationErrorToStatus(err, "Ошибка при подтверждении резерва")
+	}
+
+	return &pb.CommitReservationResponse{Reservation: mapReservationModelToProto(reservation)}, nil
+}
+
+// ReleaseReservation обрабатывает gRPC запрос на отмену резерва.
+func (h *ProductGRPCHandler) ReleaseReservation(ctx context.Context, req *pb.ReleaseReservationRequest) (*pb.ReleaseReservationResponse, error) {
+	if err := authorizeInventory(ctx); err != nil {
+		return nil, err
+	}
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID резерва не может быть пустым")
+	}
+
+	reservation, err := h.productUsecase.ReleaseReservation(ctx, req.GetId())
+	if err != nil {
+		return nil, mapReservationErrorToStatus(err, "Ошибка при отмене резерва")
+	}
+
+	return &pb.ReleaseReservationResponse{Reservation: mapReservationModelToProto(reservation)}, nil
+}
@@ -0,0 +1,307 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/usecase"
+)
+
+// FestivalHTTPHandler обрабатывает HTTP запросы к площадкам, фестивалям, сценам и лайн-апу.
+// Чтение открыто, изменения доступны администраторам (как и для каталога продуктов).
+// Личное расписание (/my-schedule) доступно любому пользователю после аутентификации.
+type FestivalHTTPHandler struct {
+	festivalUsecase usecase.FestivalUsecase
+	lineupUsecase   usecase.LineupUsecase
+	tokenValidator  auth.TokenValidator
+}
+
+// NewFestivalHTTPHandler создает новый экземпляр FestivalHTTPHandler.
+func NewFestivalHTTPHandler(uc usecase.FestivalUsecase, lu usecase.LineupUsecase, tv auth.TokenValidator) *FestivalHTTPHandler {
+	return &FestivalHTTPHandler{festivalUsecase: uc, lineupUsecase: lu, tokenValidator: tv}
+}
+
+// RegisterRoutes регистрирует HTTP маршруты площадок, фестивалей и лайн-апа.
+func (h *FestivalHTTPHandler) RegisterRoutes(router *http.ServeMux) {
+	router.HandleFunc("/api/venues", requireAdminForWrites(h.tokenValidator, h.handleVenues))       // GET (list), POST (create)
+	router.HandleFunc("/api/venues/", requireAdminForWrites(h.tokenValidator, h.handleVenueByID))   // GET, PUT, DELETE /{id}
+	router.HandleFunc("/api/artists", requireAdminForWrites(h.tokenValidator, h.handleArtists))     // GET (list), POST (create)
+	router.HandleFunc("/api/artists/", requireAdminForWrites(h.tokenValidator, h.handleArtistByID)) // GET, PUT, DELETE /{id}
+	router.HandleFunc("/api/festivals", requireAdminForWrites(h.tokenValidator, h.handleFestivals)) // GET (list), POST (create)
+
+	// /{id}, /{id}/stages, /{id}/slots, /{id}/schedule[.ics] - каталог; /{id}/my-schedule - личное расписание
+	catalog := requireAdminForWrites(h.tokenValidator, h.handleFestivalPath)
+	personal := auth.RequireAuth(h.tokenValidator,
+		auth.RequireMethodScope(auth.ScopeScheduleRead, auth.ScopeScheduleWrite)(http.HandlerFunc(h.handleFestivalPath)).ServeHTTP)
+	router.HandleFunc("/api/festivals/", func(w http.ResponseWriter, r *http.Request) {
+		if isMySchedulePath(r.URL.Path) {
+			personal(w, r)
+			return
+		}
+		catalog(w, r)
+	})
+}
+
+// handleVenues обрабатывает запросы к /api/venues
+func (h *FestivalHTTPHandler) handleVenues(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		venues, err := h.festivalUsecase.ListVenues(r.Context())
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, venues)
+	case http.MethodPost:
+		var input usecase.CreateVenueInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		venue, err := h.festivalUsecase.CreateVenue(r.Context(), input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, venue)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleVenueByID обрабатывает запросы к /api/venues/{id}
+func (h *FestivalHTTPHandler) handleVenueByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/venues/"), "/"))
+	if err != nil {
+		http.Error(w, "Некорректный формат ID площадки", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		venue, err := h.festivalUsecase.GetVenue(r.Context(), id)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, venue)
+	case http.MethodPut:
+		var input usecase.UpdateVenueInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		venue, err := h.festivalUsecase.UpdateVenue(r.Context(), id, input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, venue)
+	case http.MethodDelete:
+		if err := h.festivalUsecase.DeleteVenue(r.Context(), id); err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleFestivals обрабатывает запросы к /api/festivals.
+// Параметры списка: status (DRAFT, ON_SALE, SOLD_OUT, FINISHED), venue_id, city,
+// from (ГГГГ-ММ-ДД - фестивали, которые еще не закончились к этой дате).
+func (h *FestivalHTTPHandler) handleFestivals(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		filter := repository.FestivalFilter{
+			Status: models.FestivalStatus(strings.ToUpper(query.Get("status"))),
+			City:   query.Get("city"),
+			From:   query.Get("from"),
+		}
+		if value := query.Get("venue_id"); value != "" {
+			venueID, err := strconv.Atoi(value)
+			if err != nil {
+				http.Error(w, "Некорректное значение venue_id", http.StatusBadRequest)
+				return
+			}
+			filter.VenueID = &venueID
+		}
+		festivals, err := h.festivalUsecase.ListFestivals(r.Context(), filter)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, festivals)
+	case http.MethodPost:
+		var input usecase.CreateFestivalInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		festival, err := h.festivalUsecase.CreateFestival(r.Context(), input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, festival)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleFestivalPath разбирает путь /api/festivals/{id}[/stages|/slots|/schedule|/my-schedule...]
+func (h *FestivalHTTPHandler) handleFestivalPath(w http.ResponseWriter, r *http.Request) {
+	pathParts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/festivals/"), "/"), "/")
+	festivalID, err := strconv.Atoi(pathParts[0])
+	if err != nil {
+		http.Error(w, "Некорректный формат ID фестиваля", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(pathParts) == 1:
+		h.handleFestivalByID(w, r, festivalID)
+	case pathParts[1] == "stages" && len(pathParts) <= 3:
+		h.handleStages(w, r, festivalID, pathParts[2:])
+	case pathParts[1] == "slots" && len(pathParts) <= 3:
+		h.handleSlots(w, r, festivalID, pathParts[2:])
+	case (pathParts[1] == "schedule" || pathParts[1] == "schedule.ics") && len(pathParts) == 2:
+		h.handleSchedule(w, r, festivalID, pathParts[1] == "schedule.ics")
+	case pathParts[1] == "my-schedule.ics" && len(pathParts) == 2:
+		h.handleMyScheduleICS(w, r, festivalID)
+	case pathParts[1] == "my-schedule" && len(pathParts) <= 3:
+		h.handleMySchedule(w, r, festivalID, pathParts[2:])
+	default:
+		http.Error(w, "Некорректный путь фестиваля", http.StatusNotFound)
+	}
+}
+
+// handleFestivalByID обрабатывает запросы к /api/festivals/{id}
+func (h *FestivalHTTPHandler) handleFestivalByID(w http.ResponseWriter, r *http.Request, festivalID int) {
+	switch r.Method {
+	case http.MethodGet:
+		festival, err := h.festivalUsecase.GetFestival(r.Context(), festivalID)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, festival)
+	case http.MethodPut:
+		var input usecase.UpdateFestivalInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		festival, err := h.festivalUsecase.UpdateFestival(r.Context(), festivalID, input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, festival)
+	case http.MethodDelete:
+		if err := h.festivalUsecase.DeleteFestival(r.Context(), festivalID); err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleStages обрабатывает сцены фестиваля:
+//
+//	GET    /api/festivals/{id}/stages
+//	POST   /api/festivals/{id}/stages
+//	PUT    /api/festivals/{id}/stages/{stage_id}
+//	DELETE /api/festivals/{id}/stages/{stage_id}
+func (h *FestivalHTTPHandler) handleStages(w http.ResponseWriter, r *http.Request, festivalID int, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			stages, err := h.festivalUsecase.ListStages(r.Context(), festivalID)
+			if err != nil {
+				writeFestivalError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, stages)
+		case http.MethodPost:
+			var input usecase.StageInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			stage, err := h.festivalUsecase.CreateStage(r.Context(), festivalID, input)
+			if err != nil {
+				writeFestivalError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, stage)
+		default:
+			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	stageID, err := strconv.Atoi(rest[0])
+	if err != nil {
+		http.Error(w, "Некорректный формат ID сцены", http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		var input usecase.UpdateStageInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		stage, err := h.festivalUsecase.UpdateStage(r.Context(), festivalID, stageID, input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, stage)
+	case http.MethodDelete:
+		if err := h.festivalUsecase.DeleteStage(r.Context(), festivalID, stageID); err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeJSON записывает ответ в формате JSON с кодом status
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeFestivalError переводит ошибки площадок, фестивалей, сцен и лайн-апа в HTTP-статусы.
+func writeFestivalError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		http.Error(w, "Некорректные входные данные: "+err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrFestivalNotFound), errors.Is(err, usecase.ErrVenueNotFound),
+		errors.Is(err, usecase.ErrStageNotFound), errors.Is(err, usecase.ErrArtistNotFound),
+		errors.Is(err, usecase.ErrSlotNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.ErrFestivalInUse), errors.Is(err, usecase.ErrVenueInUse),
+		errors.Is(err, usecase.ErrStageExists), errors.Is(err, usecase.ErrInvalidStatusTransition),
+		errors.Is(err, usecase.ErrStageInUse), errors.Is(err, usecase.ErrArtistInUse),
+		errors.Is(err, usecase.ErrSlotClash):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+	}
+}
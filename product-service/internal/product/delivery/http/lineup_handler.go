@@ -0,0 +1,297 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/usecase"
+)
+
+// handleArtists обрабатывает запросы к /api/artists. Параметр списка q - часть имени исполнителя.
+func (h *FestivalHTTPHandler) handleArtists(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		artists, err := h.lineupUsecase.ListArtists(r.Context(), r.URL.Query().Get("q"))
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, artists)
+	case http.MethodPost:
+		var input usecase.CreateArtistInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		artist, err := h.lineupUsecase.CreateArtist(r.Context(), input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, artist)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleArtistByID обрабатывает запросы к /api/artists/{id}
+func (h *FestivalHTTPHandler) handleArtistByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/artists/"), "/"))
+	if err != nil {
+		http.Error(w, "Некорректный формат ID исполнителя", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		artist, err := h.lineupUsecase.GetArtist(r.Context(), id)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, artist)
+	case http.MethodPut:
+		var input usecase.UpdateArtistInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		artist, err := h.lineupUsecase.UpdateArtist(r.Context(), id, input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, artist)
+	case http.MethodDelete:
+		if err := h.lineupUsecase.DeleteArtist(r.Context(), id); err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSlots обрабатывает выступления фестиваля:
+//
+//	GET    /api/festivals/{id}/slots?stage_id=&artist_id=&from=&to=
+//	POST   /api/festivals/{id}/slots
+//	GET    /api/festivals/{id}/slots/{slot_id}
+//	PUT    /api/festivals/{id}/slots/{slot_id}
+//	DELETE /api/festivals/{id}/slots/{slot_id}
+//
+// from и to передаются в RFC 3339. Пересечение с другим выступлением на сцене - 409 с описанием пересечений.
+func (h *FestivalHTTPHandler) handleSlots(w http.ResponseWriter, r *http.Request, festivalID int, rest []string) {
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			filter, err := parseSlotFilter(r)
+			if err != nil {
+				http.Error(w, "Некорректные параметры запроса: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			slots, err := h.lineupUsecase.ListSlots(r.Context(), festivalID, filter)
+			if err != nil {
+				writeFestivalError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, slots)
+		case http.MethodPost:
+			var input usecase.SlotInput
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			slot, err := h.lineupUsecase.CreateSlot(r.Context(), festivalID, input)
+			if err != nil {
+				writeFestivalError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusCreated, slot)
+		default:
+			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	slotID, err := strconv.Atoi(rest[0])
+	if err != nil {
+		http.Error(w, "Некорректный формат ID выступления", http.StatusBadRequest)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		slot, err := h.lineupUsecase.GetSlot(r.Context(), festivalID, slotID)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, slot)
+	case http.MethodPut:
+		var input usecase.UpdateSlotInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		slot, err := h.lineupUsecase.UpdateSlot(r.Context(), festivalID, slotID, input)
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, slot)
+	case http.MethodDelete:
+		if err := h.lineupUsecase.DeleteSlot(r.Context(), festivalID, slotID); err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSchedule обрабатывает GET /api/festivals/{id}/schedule[?day=ГГГГ-ММ-ДД] (сетка "день x сцена")
+// и GET /api/festivals/{id}/schedule.ics (все выступления в формате iCalendar).
+func (h *FestivalHTTPHandler) handleSchedule(w http.ResponseWriter, r *http.Request, festivalID int, ics bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	if ics {
+		calendar, err := h.lineupUsecase.ExportICS(r.Context(), festivalID, "")
+		if err != nil {
+			writeFestivalError(w, err)
+			return
+		}
+		writeICS(w, fmt.Sprintf("festival-%d.ics", festivalID), calendar)
+		return
+	}
+
+	schedule, err := h.lineupUsecase.GetSchedule(r.Context(), festivalID, r.URL.Query().Get("day"))
+	if err != nil {
+		writeFestivalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// handleMySchedule обрабатывает личное расписание пользователя:
+//
+//	GET    /api/festivals/{id}/my-schedule            - выбранные выступления и их пересечения
+//	POST   /api/festivals/{id}/my-schedule            - {"slot_id": 1} добавить выступление
+//	DELETE /api/festivals/{id}/my-schedule/{slot_id}  - убрать выступление
+func (h *FestivalHTTPHandler) handleMySchedule(w http.ResponseWriter, r *http.Request, festivalID int, rest []string) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Требуется аутентификация", http.StatusUnauthorized)
+		return
+	}
+
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			schedule, err := h.lineupUsecase.GetMySchedule(r.Context(), festivalID, userID)
+			if err != nil {
+				writeFestivalError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, schedule)
+		case http.MethodPost:
+			var input struct {
+				SlotID int `json:"slot_id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := h.lineupUsecase.AddToMySchedule(r.Context(), festivalID, userID, input.SlotID); err != nil {
+				writeFestivalError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	slotID, err := strconv.Atoi(rest[0])
+	if err != nil {
+		http.Error(w, "Некорректный формат ID выступления", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := h.lineupUsecase.RemoveFromMySchedule(r.Context(), festivalID, userID, slotID); err != nil {
+		writeFestivalError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleMyScheduleICS обрабатывает GET /api/festivals/{id}/my-schedule.ics
+func (h *FestivalHTTPHandler) handleMyScheduleICS(w http.ResponseWriter, r *http.Request, festivalID int) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Требуется аутентификация", http.StatusUnauthorized)
+		return
+	}
+	calendar, err := h.lineupUsecase.ExportICS(r.Context(), festivalID, userID)
+	if err != nil {
+		writeFestivalError(w, err)
+		return
+	}
+	writeICS(w, fmt.Sprintf("festival-%d-my-schedule.ics", festivalID), calendar)
+}
+
+// isMySchedulePath сообщает, относится ли путь к личному расписанию (/api/festivals/{id}/my-schedule...).
+func isMySchedulePath(path string) bool {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/festivals/"), "/"), "/")
+	return len(parts) >= 2 && (parts[1] == "my-schedule" || parts[1] == "my-schedule.ics")
+}
+
+// parseSlotFilter разбирает параметры списка выступлений.
+func parseSlotFilter(r *http.Request) (repository.SlotFilter, error) {
+	query := r.URL.Query()
+	var filter repository.SlotFilter
+	for name, target := range map[string]**int{"stage_id": &filter.StageID, "artist_id": &filter.ArtistID} {
+		if value := query.Get(name); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, fmt.Errorf("%s должен быть числом", name)
+			}
+			*target = &id
+		}
+	}
+	for name, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := query.Get(name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, fmt.Errorf("%s должен быть временем в формате RFC 3339", name)
+			}
+			*target = &t
+		}
+	}
+	return filter, nil
+}
+
+// writeICS отдает календарь iCalendar как вложение с именем filename.
+func writeICS(w http.ResponseWriter, filename string, calendar []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(calendar)
+}
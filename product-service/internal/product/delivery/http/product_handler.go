@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings" // Для извлечения ID из URL
 
+	"github.com/Hayzerr/go-microservice-project/auth"
 	// ВАЖНО: Замените 'your_product_module_path' на имя вашего модуля product-service из go.mod
 	// Например: "github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
 	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
@@ -20,19 +21,58 @@ import (
 type ProductHTTPHandler struct {
 	productUsecase usecase.ProductUsecase
 	repo           repository.ProductRepository
+	tokenValidator auth.TokenValidator
 }
 
 // NewProductHTTPHandler создает новый экземпляр ProductHTTPHandler.
-func NewProductHTTPHandler(uc usecase.ProductUsecase, repo repository.ProductRepository) *ProductHTTPHandler {
-	return &ProductHTTPHandler{productUsecase: uc, repo: repo}
+func NewProductHTTPHandler(uc usecase.ProductUsecase, repo repository.ProductRepository, tv auth.TokenValidator) *ProductHTTPHandler {
+	return &ProductHTTPHandler{productUsecase: uc, repo: repo, tokenValidator: tv}
 }
 
 // RegisterRoutes регистрирует HTTP маршруты для обработчика продуктов.
 // Этот метод адаптирован для стандартного http.ServeMux.
 // При использовании роутера типа chi, регистрация будет выглядеть иначе.
 func (h *ProductHTTPHandler) RegisterRoutes(router *http.ServeMux) {
-	router.HandleFunc("/api/products", h.handleProducts)     // GET (list), POST (create)
-	router.HandleFunc("/api/products/", h.handleProductByID) // GET (by ID), PUT (update), DELETE (by ID)
+	router.HandleFunc("/api/products", h.requireAdminForWrites(h.handleProducts))        // GET (list), POST (create)
+	router.HandleFunc("/api/products/", h.requireAdminForWrites(h.handleProductByID))    // GET (by ID), PUT (update), DELETE (by ID)
+	router.HandleFunc("/api/products/search", h.requireAdminForWrites(h.searchProducts)) // GET (full-text search)
+
+	router.HandleFunc("/api/inventory/reservations", h.requireInventoryAccess(h.handleReservations))       // POST (reserve)
+	router.HandleFunc("/api/inventory/reservations/", h.requireInventoryAccess(h.handleReservationAction)) // POST /{id}/commit, POST /{id}/release
+}
+
+// requireInventoryAccess пропускает к резервам склада только сервисного клиента с разрешением inventory:write
+// (order-service получает такой токен по client credentials) и пользователей с ролью STAFF или ADMIN.
+func (h *ProductHTTPHandler) requireInventoryAccess(next http.HandlerFunc) http.HandlerFunc {
+	return auth.RequireAuth(h.tokenValidator,
+		auth.RequireService(auth.ScopeInventoryWrite, auth.RoleStaff, auth.RoleAdmin)(next).ServeHTTP)
+}
+
+// requireAdminForWrites пропускает чтение каталога без токена,
+// а для остальных методов (POST, PUT, DELETE) требует действительный токен с ролью ADMIN.
+// API-ключу и токену OAuth2 для изменений нужно разрешение products:write. Чтение с API-ключом тоже проверяется,
+// чтобы действовал лимит запросов ключа: нужно products:read или orders:write
+// (order-service читает товар при добавлении в корзину от имени владельца ключа).
+// Токен OAuth2 при чтении не проверяется: каталог открыт и без токена.
+func (h *ProductHTTPHandler) requireAdminForWrites(next http.HandlerFunc) http.HandlerFunc {
+	return requireAdminForWrites(h.tokenValidator, next)
+}
+
+// requireAdminForWrites - общая для каталога и фестивалей проверка доступа, см. ProductHTTPHandler.requireAdminForWrites
+func requireAdminForWrites(tokenValidator auth.TokenValidator, next http.HandlerFunc) http.HandlerFunc {
+	protected := auth.RequireAuthRole(tokenValidator, auth.RequireScope(auth.ScopeProductsWrite)(next).ServeHTTP, auth.RoleAdmin)
+	readWithAPIKey := auth.RequireAuth(tokenValidator, auth.RequireScope(auth.ScopeProductsRead, auth.ScopeOrdersWrite)(next).ServeHTTP)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			if auth.IsAPIKeyRequest(r) {
+				readWithAPIKey(w, r)
+				return
+			}
+			next(w, r)
+			return
+		}
+		protected(w, r)
+	}
 }
 
 // handleProducts обрабатывает запросы к /api/products (список и создание)
@@ -85,8 +125,8 @@ func (h *ProductHTTPHandler) createProduct(w http.ResponseWriter, r *http.Reques
 	defer r.Body.Close()
 
 	// Базовая валидация (более сложная валидация должна быть в usecase или отдельном слое)
-	if input.Name == "" || input.Price < 0 || input.Stock < 0 {
-		http.Error(w, "Имя, цена (>=0) и количество (>=0) обязательны", http.StatusBadRequest)
+	if input.Name == "" || input.Price < 0 || input.Stock < usecase.UnlimitedStock {
+		http.Error(w, "Имя, цена (>=0) и количество (>=0 или -1 для неограниченного) обязательны", http.StatusBadRequest)
 		return
 	}
 	// Валидация типа продукта
@@ -97,10 +137,16 @@ func (h *ProductHTTPHandler) createProduct(w http.ResponseWriter, r *http.Reques
 
 	product, err := h.productUsecase.CreateProduct(r.Context(), input)
 	if err != nil {
-		if errors.Is(err, usecase.ErrInvalidInput) {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidInput):
 			http.Error(w, "Некорректные входные данные: "+err.Error(), http.StatusBadRequest)
-		} else {
-			// TODO: Обработка других специфичных ошибок usecase
+		case errors.Is(err, usecase.ErrFestivalNotFound):
+			http.Error(w, "Фестиваль с указанным festival_id не найден", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrVariantNotFound):
+			http.Error(w, "Вариант с указанным id не найден у продукта", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrVariantExists), errors.Is(err, usecase.ErrVariantInUse):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
 			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
 		}
 		return
@@ -127,20 +173,153 @@ func (h *ProductHTTPHandler) getProductByID(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(product)
 }
 
-// listProducts обрабатывает запрос на получение списка всех продуктов.
+// productListResponse - страница каталога
+type productListResponse struct {
+	Products      []*models.Product `json:"products"`
+	NextPageToken string            `json:"next_page_token,omitempty"`
+	Total         int64             `json:"total"` // Число продуктов по фильтру на всех страницах
+}
+
+// listProducts возвращает страницу каталога.
+// Параметры: type (TICKET, MERCHANDISE), festival_id, min_price и max_price (включительно),
+// in_stock (true - только в наличии), q (подстрока названия или описания),
+// sort (created_at, price, name; "-" в начале - по убыванию; по умолчанию -created_at), page_size, page_token,
+// include=festival - добавить к продуктам краткие сведения о фестивале.
 func (h *ProductHTTPHandler) listProducts(w http.ResponseWriter, r *http.Request) {
-	// TODO: Реализовать получение параметров фильтрации и пагинации из r.URL.Query()
-	// и передать их в productUsecase.ListProducts, если этот метод будет их поддерживать.
+	query := r.URL.Query()
+	listQuery := usecase.ProductListQuery{
+		Filter: repository.ProductFilter{
+			Type:  models.ProductType(strings.ToUpper(query.Get("type"))),
+			Query: query.Get("q"),
+		},
+		Sort:      query.Get("sort"),
+		PageToken: query.Get("page_token"),
+		WithTotal: true,
+	}
+	switch query.Get("include") {
+	case "":
+	case "festival":
+		listQuery.IncludeFestivals = true
+	default:
+		http.Error(w, "Некорректное значение include: допустимо festival", http.StatusBadRequest)
+		return
+	}
 
-	products, err := h.productUsecase.ListProducts(r.Context())
+	if value := query.Get("festival_id"); value != "" {
+		festivalID, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Некорректное значение festival_id", http.StatusBadRequest)
+			return
+		}
+		listQuery.Filter.FestivalID = &festivalID
+	}
+	for param, target := range map[string]**float64{"min_price": &listQuery.Filter.MinPrice, "max_price": &listQuery.Filter.MaxPrice} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil || price < 0 {
+			http.Error(w, "Некорректное значение "+param+": ожидается неотрицательное число", http.StatusBadRequest)
+			return
+		}
+		*target = &price
+	}
+	if value := query.Get("in_stock"); value != "" {
+		inStock, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Некорректное значение in_stock: ожидается true или false", http.StatusBadRequest)
+			return
+		}
+		listQuery.Filter.InStock = inStock
+	}
+	if value := query.Get("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize <= 0 {
+			http.Error(w, "Некорректное значение page_size", http.StatusBadRequest)
+			return
+		}
+		listQuery.PageSize = pageSize
+	}
+
+	page, err := h.productUsecase.ListProducts(r.Context(), listQuery)
 	if err != nil {
-		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, usecase.ErrInvalidPageToken):
+			http.Error(w, "Некорректный page_token: токен выдан для других фильтров или поврежден", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrInvalidSort):
+			http.Error(w, "Некорректное значение sort: допустимы created_at, price, name", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrInvalidInput):
+			http.Error(w, "Некорректный фильтр: тип TICKET или MERCHANDISE, min_price не больше max_price", http.StatusBadRequest)
+		default:
+			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(products)
+	json.NewEncoder(w).Encode(productListResponse{Products: page.Products, NextPageToken: page.NextPageToken, Total: page.Total})
+}
+
+// searchProducts выполняет полнотекстовый поиск по каталогу.
+// Параметры: q (обязательный), lang (ru, en; по умолчанию оба языка), type, festival_id, in_stock, limit (до 100).
+func (h *ProductHTTPHandler) searchProducts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	searchQuery := usecase.ProductSearchQuery{
+		Text:     query.Get("q"),
+		Language: query.Get("lang"),
+		Filter: repository.ProductFilter{
+			Type: models.ProductType(strings.ToUpper(query.Get("type"))),
+		},
+	}
+	if strings.TrimSpace(searchQuery.Text) == "" {
+		http.Error(w, "Параметр q обязателен", http.StatusBadRequest)
+		return
+	}
+	if value := query.Get("festival_id"); value != "" {
+		festivalID, err := strconv.Atoi(value)
+		if err != nil {
+			http.Error(w, "Некорректное значение festival_id", http.StatusBadRequest)
+			return
+		}
+		searchQuery.Filter.FestivalID = &festivalID
+	}
+	if value := query.Get("in_stock"); value != "" {
+		inStock, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Некорректное значение in_stock: ожидается true или false", http.StatusBadRequest)
+			return
+		}
+		searchQuery.Filter.InStock = inStock
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Некорректное значение limit", http.StatusBadRequest)
+			return
+		}
+		searchQuery.Limit = limit
+	}
+
+	results, err := h.productUsecase.SearchProducts(r.Context(), searchQuery)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidInput) {
+			http.Error(w, "Некорректный запрос поиска: q до 200 символов, lang ru или en, тип TICKET или MERCHANDISE", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
 }
 
 // updateProduct обрабатывает запрос на обновление продукта.
@@ -154,7 +333,7 @@ func (h *ProductHTTPHandler) updateProduct(w http.ResponseWriter, r *http.Reques
 
 	// Проверка, есть ли вообще что обновлять
 	if input.Name == nil && input.Description == nil && input.Price == nil &&
-		input.Type == nil && input.Stock == nil && input.FestivalID == nil {
+		input.Type == nil && input.Stock == nil && input.FestivalID == nil && input.Variants == nil {
 		http.Error(w, "Нет данных для обновления", http.StatusBadRequest)
 		return
 	}
@@ -163,8 +342,8 @@ func (h *ProductHTTPHandler) updateProduct(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Цена не может быть отрицательной", http.StatusBadRequest)
 		return
 	}
-	if input.Stock != nil && *input.Stock < 0 {
-		http.Error(w, "Количество на складе не может быть отрицательным", http.StatusBadRequest)
+	if input.Stock != nil && *input.Stock < usecase.UnlimitedStock {
+		http.Error(w, "Количество на складе не может быть меньше -1", http.StatusBadRequest)
 		return
 	}
 	if input.Type != nil && *input.Type != models.Ticket && *input.Type != models.Merchandise {
@@ -179,7 +358,12 @@ func (h *ProductHTTPHandler) updateProduct(w http.ResponseWriter, r *http.Reques
 			http.Error(w, "Продукт для обновления не найден", http.StatusNotFound)
 		case errors.Is(err, usecase.ErrInvalidInput):
 			http.Error(w, "Некорректные входные данные для обновления: "+err.Error(), http.StatusBadRequest)
-		// TODO: Обработать другие специфичные ошибки usecase
+		case errors.Is(err, usecase.ErrFestivalNotFound):
+			http.Error(w, "Фестиваль с указанным festival_id не найден", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrVariantNotFound):
+			http.Error(w, "Вариант с указанным id не найден у продукта", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrVariantExists), errors.Is(err, usecase.ErrVariantInUse):
+			http.Error(w, err.Error(), http.StatusConflict)
 		default:
 			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
 		}
@@ -211,6 +395,103 @@ func (h *ProductHTTPHandler) deleteProduct(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// handleReservations обрабатывает запросы к /api/inventory/reservations
+func (h *ProductHTTPHandler) handleReservations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+	h.reserveStock(w, r)
+}
+
+// handleReservationAction обрабатывает запросы к /api/inventory/reservations/{id}/commit и /release
+func (h *ProductHTTPHandler) handleReservationAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pathParts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/inventory/reservations/"), "/"), "/")
+	if len(pathParts) != 2 || pathParts[0] == "" {
+		http.Error(w, "Путь должен иметь вид /api/inventory/reservations/{id}/commit или /release", http.StatusNotFound)
+		return
+	}
+	reservationID, action := pathParts[0], pathParts[1]
+
+	var (
+		reservation *models.Reservation
+		err         error
+	)
+	switch action {
+	case "commit":
+		reservation, err = h.productUsecase.CommitReservation(r.Context(), reservationID)
+	case "release":
+		reservation, err = h.productUsecase.ReleaseReservation(r.Context(), reservationID)
+	default:
+		http.Error(w, "Неизвестное действие с резервом: "+action, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrReservationNotFound):
+			http.Error(w, "Резерв не найден", http.StatusNotFound)
+		case errors.Is(err, usecase.ErrReservationClosed):
+			http.Error(w, "Резерв уже отменен", http.StatusConflict)
+		default:
+			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// reserveStock обрабатывает запрос на резервирование товара под заказ.
+// Для продукта с вариантами обязателен variant_id.
+func (h *ProductHTTPHandler) reserveStock(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		ProductID int    `json:"product_id"`
+		VariantID *int   `json:"variant_id"`
+		OrderID   string `json:"order_id"`
+		Quantity  int    `json:"quantity"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if requestBody.ProductID <= 0 || requestBody.OrderID == "" || requestBody.Quantity <= 0 {
+		http.Error(w, "ID продукта, ID заказа и количество (>0) обязательны", http.StatusBadRequest)
+		return
+	}
+
+	reservation, err := h.productUsecase.ReserveStock(r.Context(), requestBody.ProductID, requestBody.VariantID, requestBody.OrderID, requestBody.Quantity)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrProductNotFound):
+			http.Error(w, "Продукт не найден", http.StatusNotFound)
+		case errors.Is(err, usecase.ErrVariantNotFound):
+			http.Error(w, "Вариант продукта не найден", http.StatusNotFound)
+		case errors.Is(err, usecase.ErrVariantRequired):
+			http.Error(w, "Для продукта с вариантами нужно указать variant_id", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrInsufficientStock):
+			http.Error(w, "Недостаточно товара на складе", http.StatusConflict)
+		case errors.Is(err, usecase.ErrInvalidInput):
+			http.Error(w, "Некорректные входные данные: "+err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
 // Получить все товары
 func (h *ProductHTTPHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
 	products, err := h.repo.ListAll(r.Context())
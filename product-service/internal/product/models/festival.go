@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+)
+
+// FestivalStatus определяет стадию жизненного цикла фестиваля.
+type FestivalStatus string
+
+const (
+	FestivalDraft    FestivalStatus = "DRAFT"    // Готовится, продажи не открыты
+	FestivalOnSale   FestivalStatus = "ON_SALE"  // Идут продажи
+	FestivalSoldOut  FestivalStatus = "SOLD_OUT" // Билеты распроданы
+	FestivalFinished FestivalStatus = "FINISHED" // Фестиваль прошел
+)
+
+// Формат дат проведения фестиваля (без времени)
+const FestivalDateLayout = "2006-01-02"
+
+// Venue - площадка, на которой проходят фестивали.
+type Venue struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	City      string    `json:"city"`
+	Country   string    `json:"country"`
+	Capacity  int       `json:"capacity"` // Вместимость площадки, 0 - не указана
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Stage - сцена фестиваля. Сцены принадлежат фестивалю и удаляются вместе с ним.
+type Stage struct {
+	ID         int       `json:"id"`
+	FestivalID int       `json:"festival_id"`
+	Name       string    `json:"name"`
+	Capacity   int       `json:"capacity"` // Вместимость сцены, 0 - не указана
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Festival представляет фестиваль, к которому относятся продукты (Product.FestivalID).
+type Festival struct {
+	ID          int            `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	VenueID     int            `json:"venue_id"`
+	Venue       *Venue         `json:"venue,omitempty"`
+	StartDate   string         `json:"start_date"` // Первый день фестиваля, ГГГГ-ММ-ДД
+	EndDate     string         `json:"end_date"`   // Последний день фестиваля (включительно), ГГГГ-ММ-ДД
+	Timezone    string         `json:"timezone"`   // Часовой пояс площадки (IANA, например Europe/Moscow)
+	Status      FestivalStatus `json:"status"`
+	Stages      []*Stage       `json:"stages,omitempty"` // Заполняется при выборке одного фестиваля
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// FestivalSummary - краткие сведения о фестивале, которые встраиваются в продукты каталога.
+type FestivalSummary struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	StartDate string         `json:"start_date"`
+	EndDate   string         `json:"end_date"`
+	Status    FestivalStatus `json:"status"`
+	VenueName string         `json:"venue_name"`
+	City      string         `json:"city"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// Artist - исполнитель. Исполнители общие для всех фестивалей.
+type Artist struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Genre       string    `json:"genre"`
+	Country     string    `json:"country"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Slot - выступление исполнителя на сцене фестиваля. Выступления одной сцены не пересекаются по времени.
+type Slot struct {
+	ID         int       `json:"id"`
+	FestivalID int       `json:"festival_id"`
+	StageID    int       `json:"stage_id"`
+	StageName  string    `json:"stage_name"`
+	ArtistID   int       `json:"artist_id"`
+	Artist     *Artist   `json:"artist,omitempty"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Schedule - расписание фестиваля в виде сетки "день x сцена".
+type Schedule struct {
+	FestivalID   int            `json:"festival_id"`
+	FestivalName string         `json:"festival_name"`
+	Timezone     string         `json:"timezone"` // Часовой пояс, в котором определены дни фестиваля
+	Days         []*ScheduleDay `json:"days"`
+}
+
+// ScheduleDay - один день фестиваля. Выступления после полуночи до начала следующего дня
+// фестиваля (см. usecase.FestivalDayStart) относятся к предыдущему дню.
+type ScheduleDay struct {
+	Date   string           `json:"date"` // ГГГГ-ММ-ДД
+	Stages []*ScheduleStage `json:"stages"`
+}
+
+// ScheduleStage - выступления на одной сцене за день в порядке начала. Сцена без выступлений входит с пустым списком.
+type ScheduleStage struct {
+	StageID   int     `json:"stage_id"`
+	StageName string  `json:"stage_name"`
+	Slots     []*Slot `json:"slots"`
+}
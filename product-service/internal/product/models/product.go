@@ -20,8 +20,35 @@ type Product struct {
 	Description string      `json:"description"` // Описание продукта
 	Price       float64     `json:"price"`       // Цена продукта
 	Type        ProductType `json:"type"`        // Тип продукта (TICKET, MERCHANDISE)
-	Stock       int         `json:"stock"`       // Количество на складе (актуально для Merchandise, может быть 1 для уникальных билетов или -1 для неограниченных)
+	Stock       int         `json:"stock"`       // Количество на складе (актуально для Merchandise, может быть 1 для уникальных билетов или -1 для неограниченных); у продукта с вариантами - суммарный остаток вариантов
 	FestivalID  *int        `json:"festival_id"` // ID фестиваля, к которому относится продукт (если применимо)
 	CreatedAt   time.Time   `json:"created_at"`  // Время создания записи
 	UpdatedAt   time.Time   `json:"updated_at"`  // Время последнего обновления записи
+
+	// Festival - краткие сведения о фестивале; заполняется, только если их запросили в списке каталога
+	Festival *FestivalSummary `json:"festival,omitempty"`
+	// Variants - варианты продукта (размер, цвет); заполняется в карточке продукта, в списках каталога пусто
+	Variants []*ProductVariant `json:"variants,omitempty"`
+}
+
+// ProductVariant представляет вариант продукта (например, футболка размера M синего цвета)
+// со своим артикулом, ценой и остатком. Резервируется и продается именно вариант.
+type ProductVariant struct {
+	ID         int               `json:"id"`         // Уникальный идентификатор варианта
+	ProductID  int               `json:"product_id"` // ID продукта, к которому относится вариант
+	SKU        string            `json:"sku"`        // Артикул, уникальный во всем каталоге
+	Attributes map[string]string `json:"attributes"` // Атрибуты варианта, например {"size": "M", "color": "blue"}
+	Price      *float64          `json:"price"`      // Цена варианта; nil - действует цена продукта
+	Stock      int               `json:"stock"`      // Остаток варианта на складе, -1 - неограниченное количество
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// ProductSearchResult - продукт, найденный полнотекстовым поиском.
+// В NameHighlight и Snippet найденные слова обрамлены тегами <mark>, остальной текст экранирован для HTML.
+type ProductSearchResult struct {
+	Product       *Product `json:"product"`
+	Rank          float64  `json:"rank"`           // Релевантность: чем больше, тем выше в выдаче
+	NameHighlight string   `json:"name_highlight"` // Название с выделенными совпадениями
+	Snippet       string   `json:"snippet"`        // Фрагменты описания с совпадениями
 }
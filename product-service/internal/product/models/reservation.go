@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// ReservationStatus определяет состояние резерва товара на складе.
+type ReservationStatus string
+
+const (
+	ReservationReserved  ReservationStatus = "RESERVED"  // Товар списан со склада и удерживается за заказом
+	ReservationCommitted ReservationStatus = "COMMITTED" // Заказ оформлен, резерв подтвержден
+	ReservationReleased  ReservationStatus = "RELEASED"  // Резерв отменен, товар возвращен на склад
+)
+
+// Reservation представляет резерв определенного количества продукта под заказ.
+type Reservation struct {
+	ID        string            `json:"id"`         // Уникальный идентификатор резерва (UUID)
+	ProductID int               `json:"product_id"` // ID зарезервированного продукта
+	VariantID *int              `json:"variant_id"` // ID зарезервированного варианта, если у продукта есть варианты
+	OrderID   string            `json:"order_id"`   // ID заказа в order-service
+	Quantity  int               `json:"quantity"`   // Зарезервированное количество
+	Status    ReservationStatus `json:"status"`     // Текущее состояние резерва
+	CreatedAt time.Time         `json:"created_at"` // Время создания резерва
+	UpdatedAt time.Time         `json:"updated_at"` // Время последнего изменения состояния
+}
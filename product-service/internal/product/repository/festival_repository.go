@@ -0,0 +1,450 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrReferenced - запись нельзя удалить: на нее ссылаются другие записи (фестивали площадки, продукты фестиваля)
+	ErrReferenced = errors.New("запись используется другими записями")
+	// ErrDuplicate - запись с таким названием уже есть (например, сцена фестиваля)
+	ErrDuplicate = errors.New("запись с таким названием уже существует")
+)
+
+// Коды ошибок PostgreSQL, которые переводятся в ошибки репозитория
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+)
+
+// FestivalFilter задает условия выборки фестивалей. Пустые поля не ограничивают выборку.
+type FestivalFilter struct {
+	Status  models.FestivalStatus
+	VenueID *int
+	City    string // Город площадки, без учета регистра
+	From    string // Фестивали, которые заканчиваются в этот день или позже (ГГГГ-ММ-ДД)
+}
+
+// FestivalRepository определяет операции с площадками, фестивалями и их сценами.
+// Отсутствующая запись при выборке обозначается nil, nil, при изменении и удалении - ошибкой sql.ErrNoRows.
+type FestivalRepository interface {
+	CreateVenue(ctx context.Context, venue *models.Venue) (*models.Venue, error)
+	GetVenue(ctx context.Context, id int) (*models.Venue, error)
+	ListVenues(ctx context.Context) ([]*models.Venue, error)
+	UpdateVenue(ctx context.Context, venue *models.Venue) (*models.Venue, error)
+	// DeleteVenue возвращает ErrReferenced, если на площадке есть фестивали
+	DeleteVenue(ctx context.Context, id int) error
+
+	// Create сохраняет фестиваль вместе со сценами festival.Stages
+	Create(ctx context.Context, festival *models.Festival) (*models.Festival, error)
+	// GetByID возвращает фестиваль с площадкой и сценами
+	GetByID(ctx context.Context, id int) (*models.Festival, error)
+	// List возвращает фестивали с площадками (без сцен) в порядке начала
+	List(ctx context.Context, filter FestivalFilter) ([]*models.Festival, error)
+	// Update изменяет поля фестиваля; сцены изменяются отдельно
+	Update(ctx context.Context, festival *models.Festival) (*models.Festival, error)
+	// Delete удаляет фестиваль вместе со сценами. Возвращает ErrReferenced, если к фестивалю привязаны продукты.
+	Delete(ctx context.Context, id int) error
+	// Exists сообщает, есть ли фестиваль с таким ID
+	Exists(ctx context.Context, id int) (bool, error)
+	// Summaries возвращает краткие сведения о фестивалях по ID; отсутствующих фестивалей в результате нет
+	Summaries(ctx context.Context, ids []int) (map[int]*models.FestivalSummary, error)
+
+	ListStages(ctx context.Context, festivalID int) ([]*models.Stage, error)
+	GetStage(ctx context.Context, festivalID, stageID int) (*models.Stage, error)
+	// CreateStage возвращает ErrDuplicate, если у фестиваля уже есть сцена с таким названием
+	CreateStage(ctx context.Context, stage *models.Stage) (*models.Stage, error)
+	UpdateStage(ctx context.Context, stage *models.Stage) (*models.Stage, error)
+	DeleteStage(ctx context.Context, festivalID, stageID int) error
+}
+
+// PostgresFestivalRepository реализует FestivalRepository для PostgreSQL
+type PostgresFestivalRepository struct {
+	db *sql.DB
+}
+
+// NewFestivalRepository создает новый экземпляр PostgresFestivalRepository
+func NewFestivalRepository(db *sql.DB) FestivalRepository {
+	return &PostgresFestivalRepository{db: db}
+}
+
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// venueColumns - столбцы таблицы venues в порядке, ожидаемом scanVenue
+const venueColumns = `id, name, address, city, country, capacity, created_at, updated_at`
+
+// CreateVenue сохраняет площадку.
+func (r *PostgresFestivalRepository) CreateVenue(ctx context.Context, venue *models.Venue) (*models.Venue, error) {
+	now := time.Now().UTC()
+	return scanVenue(r.db.QueryRowContext(ctx,
+		`INSERT INTO venues (name, address, city, country, capacity, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $6)
+		 RETURNING `+venueColumns,
+		venue.Name, venue.Address, venue.City, venue.Country, venue.Capacity, now))
+}
+
+// GetVenue выбирает площадку по ID.
+func (r *PostgresFestivalRepository) GetVenue(ctx context.Context, id int) (*models.Venue, error) {
+	return scanVenue(r.db.QueryRowContext(ctx, `SELECT `+venueColumns+` FROM venues WHERE id = $1`, id))
+}
+
+// ListVenues выбирает все площадки по названию.
+func (r *PostgresFestivalRepository) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+venueColumns+` FROM venues ORDER BY name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	venues := make([]*models.Venue, 0)
+	for rows.Next() {
+		venue, err := scanVenue(rows)
+		if err != nil {
+			return nil, err
+		}
+		venues = append(venues, venue)
+	}
+	return venues, rows.Err()
+}
+
+// UpdateVenue сохраняет все поля площадки.
+func (r *PostgresFestivalRepository) UpdateVenue(ctx context.Context, venue *models.Venue) (*models.Venue, error) {
+	updated, err := scanVenue(r.db.QueryRowContext(ctx,
+		`UPDATE venues SET name = $1, address = $2, city = $3, country = $4, capacity = $5, updated_at = $6
+		 WHERE id = $7
+		 RETURNING `+venueColumns,
+		venue.Name, venue.Address, venue.City, venue.Country, venue.Capacity, time.Now().UTC(), venue.ID))
+	if err == nil && updated == nil {
+		return nil, sql.ErrNoRows
+	}
+	return updated, err
+}
+
+// DeleteVenue удаляет площадку. Фестивали ссылаются на площадку внешним ключом, поэтому занятую площадку удалить нельзя.
+func (r *PostgresFestivalRepository) DeleteVenue(ctx context.Context, id int) error {
+	return execDelete(ctx, r.db, `DELETE FROM venues WHERE id = $1`, id)
+}
+
+// festivalColumns - столбцы фестиваля и его площадки в порядке, ожидаемом scanFestival
+const festivalColumns = `f.id, f.name, f.description, f.venue_id, f.start_date, f.end_date, f.timezone, f.status,
+		 f.created_at, f.updated_at,
+		 v.id, v.name, v.address, v.city, v.country, v.capacity, v.created_at, v.updated_at`
+
+// Create в одной транзакции сохраняет фестиваль и его сцены.
+func (r *PostgresFestivalRepository) Create(ctx context.Context, festival *models.Festival) (*models.Festival, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	var id int
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO festivals (name, description, venue_id, start_date, end_date, timezone, status, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		 RETURNING id`,
+		festival.Name, festival.Description, festival.VenueID, festival.StartDate, festival.EndDate, festival.Timezone,
+		festival.Status, now,
+	).Scan(&id)
+	if err != nil {
+		return nil, mapConstraintError(err)
+	}
+	for _, stage := range festival.Stages {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO stages (festival_id, name, capacity, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)`,
+			id, stage.Name, stage.Capacity, now); err != nil {
+			return nil, mapConstraintError(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetByID выбирает фестиваль с площадкой и сценами.
+func (r *PostgresFestivalRepository) GetByID(ctx context.Context, id int) (*models.Festival, error) {
+	festival, err := scanFestival(r.db.QueryRowContext(ctx,
+		`SELECT `+festivalColumns+` FROM festivals f JOIN venues v ON v.id = f.venue_id WHERE f.id = $1`, id))
+	if err != nil || festival == nil {
+		return nil, err
+	}
+	festival.Stages, err = r.ListStages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return festival, nil
+}
+
+// List выбирает фестивали по фильтру.
+func (r *PostgresFestivalRepository) List(ctx context.Context, filter FestivalFilter) ([]*models.Festival, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+	if filter.Status != "" {
+		addCondition("f.status = $?", filter.Status)
+	}
+	if filter.VenueID != nil {
+		addCondition("f.venue_id = $?", *filter.VenueID)
+	}
+	if filter.City != "" {
+		addCondition("lower(v.city) = lower($?)", filter.City)
+	}
+	if filter.From != "" {
+		addCondition("f.end_date >= $?::DATE", filter.From)
+	}
+
+	query := `SELECT ` + festivalColumns + ` FROM festivals f JOIN venues v ON v.id = f.venue_id`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY f.start_date, f.id"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	festivals := make([]*models.Festival, 0)
+	for rows.Next() {
+		festival, err := scanFestival(rows)
+		if err != nil {
+			return nil, err
+		}
+		festivals = append(festivals, festival)
+	}
+	return festivals, rows.Err()
+}
+
+// Update сохраняет поля фестиваля и возвращает его вместе с площадкой и сценами.
+func (r *PostgresFestivalRepository) Update(ctx context.Context, festival *models.Festival) (*models.Festival, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE festivals
+		 SET name = $1, description = $2, venue_id = $3, start_date = $4, end_date = $5, timezone = $6, status = $7,
+		     updated_at = $8
+		 WHERE id = $9`,
+		festival.Name, festival.Description, festival.VenueID, festival.StartDate, festival.EndDate, festival.Timezone,
+		festival.Status, time.Now().UTC(), festival.ID)
+	if err != nil {
+		return nil, mapConstraintError(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return r.GetByID(ctx, festival.ID)
+}
+
+// Delete удаляет фестиваль; сцены удаляются каскадно, а продукты ссылаются на фестиваль внешним ключом.
+func (r *PostgresFestivalRepository) Delete(ctx context.Context, id int) error {
+	return execDelete(ctx, r.db, `DELETE FROM festivals WHERE id = $1`, id)
+}
+
+// Exists проверяет наличие фестиваля.
+func (r *PostgresFestivalRepository) Exists(ctx context.Context, id int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM festivals WHERE id = $1)`, id).Scan(&exists)
+	return exists, err
+}
+
+// Summaries выбирает краткие сведения о фестивалях одним запросом.
+func (r *PostgresFestivalRepository) Summaries(ctx context.Context, ids []int) (map[int]*models.FestivalSummary, error) {
+	summaries := make(map[int]*models.FestivalSummary, len(ids))
+	if len(ids) == 0 {
+		return summaries, nil
+	}
+	ids64 := make([]int64, len(ids))
+	for i, id := range ids {
+		ids64[i] = int64(id)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT f.id, f.name, f.start_date, f.end_date, f.status, v.name, v.city
+		 FROM festivals f JOIN venues v ON v.id = f.venue_id
+		 WHERE f.id = ANY($1)`,
+		pq.Array(ids64))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		summary := &models.FestivalSummary{}
+		var startDate, endDate time.Time
+		if err := rows.Scan(&summary.ID, &summary.Name, &startDate, &endDate, &summary.Status,
+			&summary.VenueName, &summary.City); err != nil {
+			return nil, err
+		}
+		summary.StartDate = startDate.Format(models.FestivalDateLayout)
+		summary.EndDate = endDate.Format(models.FestivalDateLayout)
+		summaries[summary.ID] = summary
+	}
+	return summaries, rows.Err()
+}
+
+// stageColumns - столбцы таблицы stages в порядке, ожидаемом scanStage
+const stageColumns = `id, festival_id, name, capacity, created_at, updated_at`
+
+// ListStages выбирает сцены фестиваля в порядке добавления.
+func (r *PostgresFestivalRepository) ListStages(ctx context.Context, festivalID int) ([]*models.Stage, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+stageColumns+` FROM stages WHERE festival_id = $1 ORDER BY id`, festivalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stages := make([]*models.Stage, 0)
+	for rows.Next() {
+		stage, err := scanStage(rows)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+	}
+	return stages, rows.Err()
+}
+
+// GetStage выбирает сцену фестиваля.
+func (r *PostgresFestivalRepository) GetStage(ctx context.Context, festivalID, stageID int) (*models.Stage, error) {
+	return scanStage(r.db.QueryRowContext(ctx,
+		`SELECT `+stageColumns+` FROM stages WHERE id = $1 AND festival_id = $2`, stageID, festivalID))
+}
+
+// CreateStage сохраняет сцену фестиваля.
+func (r *PostgresFestivalRepository) CreateStage(ctx context.Context, stage *models.Stage) (*models.Stage, error) {
+	now := time.Now().UTC()
+	created, err := scanStage(r.db.QueryRowContext(ctx,
+		`INSERT INTO stages (festival_id, name, capacity, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $4)
+		 RETURNING `+stageColumns,
+		stage.FestivalID, stage.Name, stage.Capacity, now))
+	if err != nil {
+		return nil, mapConstraintError(err)
+	}
+	return created, nil
+}
+
+// UpdateStage сохраняет название и вместимость сцены.
+func (r *PostgresFestivalRepository) UpdateStage(ctx context.Context, stage *models.Stage) (*models.Stage, error) {
+	updated, err := scanStage(r.db.QueryRowContext(ctx,
+		`UPDATE stages SET name = $1, capacity = $2, updated_at = $3
+		 WHERE id = $4 AND festival_id = $5
+		 RETURNING `+stageColumns,
+		stage.Name, stage.Capacity, time.Now().UTC(), stage.ID, stage.FestivalID))
+	if err != nil {
+		return nil, mapConstraintError(err)
+	}
+	if updated == nil {
+		return nil, sql.ErrNoRows
+	}
+	return updated, nil
+}
+
+// DeleteStage удаляет сцену фестиваля.
+func (r *PostgresFestivalRepository) DeleteStage(ctx context.Context, festivalID, stageID int) error {
+	return execDelete(ctx, r.db, `DELETE FROM stages WHERE id = $1 AND festival_id = $2`, stageID, festivalID)
+}
+
+// execDelete выполняет DELETE и возвращает sql.ErrNoRows, если запись не найдена,
+// и ErrReferenced, если на нее ссылаются другие записи.
+func execDelete(ctx context.Context, db *sql.DB, query string, args ...interface{}) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// mapConstraintError переводит нарушения внешнего ключа и уникальности в ErrReferenced и ErrDuplicate.
+func mapConstraintError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pqForeignKeyViolation:
+			return ErrReferenced
+		case pqUniqueViolation:
+			return ErrDuplicate
+		}
+	}
+	return err
+}
+
+// scanVenue читает площадку из строки со столбцами venueColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanVenue(row rowScanner) (*models.Venue, error) {
+	venue := &models.Venue{}
+	err := row.Scan(&venue.ID, &venue.Name, &venue.Address, &venue.City, &venue.Country, &venue.Capacity,
+		&venue.CreatedAt, &venue.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return venue, nil
+}
+
+// scanFestival читает фестиваль с площадкой из строки со столбцами festivalColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanFestival(row rowScanner) (*models.Festival, error) {
+	festival := &models.Festival{Venue: &models.Venue{}}
+	venue := festival.Venue
+	var startDate, endDate time.Time
+	err := row.Scan(
+		&festival.ID, &festival.Name, &festival.Description, &festival.VenueID, &startDate, &endDate,
+		&festival.Timezone, &festival.Status, &festival.CreatedAt, &festival.UpdatedAt,
+		&venue.ID, &venue.Name, &venue.Address, &venue.City, &venue.Country, &venue.Capacity,
+		&venue.CreatedAt, &venue.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	festival.StartDate = startDate.Format(models.FestivalDateLayout)
+	festival.EndDate = endDate.Format(models.FestivalDateLayout)
+	return festival, nil
+}
+
+// scanStage читает сцену из строки со столбцами stageColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanStage(row rowScanner) (*models.Stage, error) {
+	stage := &models.Stage{}
+	err := row.Scan(&stage.ID, &stage.FestivalID, &stage.Name, &stage.Capacity, &stage.CreatedAt, &stage.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return stage, nil
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInsufficientStock = errors.New("недостаточно товара на складе")
+	ErrReservationClosed = errors.New("резерв уже отменен")
+)
+
+// InventoryRepository определяет операции резервирования остатков на складе.
+// Отсутствующие продукт или резерв обозначаются ошибкой sql.ErrNoRows.
+type InventoryRepository interface {
+	// Reserve резервирует продукт или, если variantID не nil, его вариант.
+	// Возвращает ErrVariantRequired для продукта с вариантами без variantID и ErrVariantNotFound для чужого варианта.
+	Reserve(ctx context.Context, productID int, variantID *int, orderID string, quantity int) (*models.Reservation, error)
+	Commit(ctx context.Context, reservationID string) (*models.Reservation, error)
+	Release(ctx context.Context, reservationID string) (*models.Reservation, error)
+}
+
+// PostgresInventoryRepository реализует InventoryRepository для PostgreSQL
+type PostgresInventoryRepository struct {
+	db *sql.DB
+}
+
+// NewInventoryRepository создает новый экземпляр PostgresInventoryRepository
+func NewInventoryRepository(db *sql.DB) InventoryRepository {
+	return &PostgresInventoryRepository{db: db}
+}
+
+// Reserve списывает quantity единиц продукта (или его варианта) со склада и создает резерв под заказ.
+// Списание выполняется условным UPDATE, поэтому конкурентные резервы не уводят остаток в минус.
+// Продукты и варианты с остатком -1 (неограниченное количество) резервируются без изменения остатка.
+// При резерве варианта суммарный остаток продукта пересчитывается в той же транзакции.
+func (r *PostgresInventoryRepository) Reserve(ctx context.Context, productID int, variantID *int, orderID string, quantity int) (*models.Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Блокировка продукта упорядочивает резервы с изменением набора вариантов
+	hasVariants, err := lockProduct(ctx, tx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case variantID != nil:
+		if err := reserveVariant(ctx, tx, productID, *variantID, quantity, now); err != nil {
+			return nil, err
+		}
+	case hasVariants:
+		return nil, ErrVariantRequired
+	default:
+		result, err := tx.ExecContext(ctx,
+			`UPDATE products
+			 SET stock = CASE WHEN stock = -1 THEN -1 ELSE stock - $2 END, updated_at = $3
+			 WHERE id = $1 AND (stock = -1 OR stock >= $2)`,
+			productID, quantity, now,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rowsAffected == 0 {
+			return nil, ErrInsufficientStock // Продукт заблокирован выше, значит, он есть
+		}
+	}
+
+	reservation := &models.Reservation{
+		ID:        uuid.NewString(),
+		ProductID: productID,
+		VariantID: variantID,
+		OrderID:   orderID,
+		Quantity:  quantity,
+		Status:    models.ReservationReserved,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO stock_reservations (id, product_id, variant_id, order_id, quantity, status, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		reservation.ID, reservation.ProductID, reservation.VariantID, reservation.OrderID, reservation.Quantity, reservation.Status, reservation.CreatedAt, reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Commit подтверждает резерв. Повторное подтверждение не является ошибкой.
+func (r *PostgresInventoryRepository) Commit(ctx context.Context, reservationID string) (*models.Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reservation, err := lockReservation(ctx, tx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reservation.Status {
+	case models.ReservationCommitted:
+		return reservation, nil
+	case models.ReservationReleased:
+		return nil, ErrReservationClosed
+	}
+
+	if err := setReservationStatus(ctx, tx, reservation, models.ReservationCommitted); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Release отменяет резерв и возвращает товар на склад.
+// Отменить можно как неподтвержденный, так и подтвержденный резерв (например, при отмене заказа).
+// Повторная отмена не является ошибкой и не возвращает товар второй раз.
+func (r *PostgresInventoryRepository) Release(ctx context.Context, reservationID string) (*models.Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reservation, err := lockReservation(ctx, tx, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status == models.ReservationReleased {
+		return reservation, nil
+	}
+
+	if reservation.VariantID != nil {
+		err = releaseVariant(ctx, tx, reservation)
+	} else {
+		_, err = tx.ExecContext(ctx,
+			`UPDATE products
+			 SET stock = CASE WHEN stock = -1 THEN -1 ELSE stock + $2 END, updated_at = $3
+			 WHERE id = $1`,
+			reservation.ProductID, reservation.Quantity, time.Now().UTC(),
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setReservationStatus(ctx, tx, reservation, models.ReservationReleased); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// reserveVariant списывает quantity единиц варианта и пересчитывает остаток продукта.
+// Строка продукта должна быть заблокирована вызывающим.
+func reserveVariant(ctx context.Context, tx *sql.Tx, productID, variantID, quantity int, now time.Time) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE product_variants
+		 SET stock = CASE WHEN stock = -1 THEN -1 ELSE stock - $3 END, updated_at = $4
+		 WHERE id = $1 AND product_id = $2 AND (stock = -1 OR stock >= $3)`,
+		variantID, productID, quantity, now,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		// Отличаем чужой или отсутствующий вариант от нехватки остатка
+		var exists bool
+		err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM product_variants WHERE id = $1 AND product_id = $2)`, variantID, productID,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrVariantNotFound
+		}
+		return ErrInsufficientStock
+	}
+	_, err = syncProductStock(ctx, tx, productID, now)
+	return err
+}
+
+// releaseVariant возвращает зарезервированное количество варианту и пересчитывает остаток продукта
+func releaseVariant(ctx context.Context, tx *sql.Tx, reservation *models.Reservation) error {
+	if _, err := lockProduct(ctx, tx, reservation.ProductID); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	_, err := tx.ExecContext(ctx,
+		`UPDATE product_variants
+		 SET stock = CASE WHEN stock = -1 THEN -1 ELSE stock + $2 END, updated_at = $3
+		 WHERE id = $1`,
+		*reservation.VariantID, reservation.Quantity, now,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = syncProductStock(ctx, tx, reservation.ProductID, now)
+	return err
+}
+
+// lockReservation читает резерв с блокировкой строки до конца транзакции
+func lockReservation(ctx context.Context, tx *sql.Tx, reservationID string) (*models.Reservation, error) {
+	if _, err := uuid.Parse(reservationID); err != nil {
+		return nil, sql.ErrNoRows
+	}
+
+	reservation := &models.Reservation{}
+	err := tx.QueryRowContext(ctx,
+		`SELECT id, product_id, variant_id, order_id, quantity, status, created_at, updated_at
+		 FROM stock_reservations WHERE id = $1 FOR UPDATE`,
+		reservationID,
+	).Scan(
+		&reservation.ID, &reservation.ProductID, &reservation.VariantID, &reservation.OrderID, &reservation.Quantity, &reservation.Status, &reservation.CreatedAt, &reservation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// setReservationStatus обновляет состояние резерва в рамках транзакции
+func setReservationStatus(ctx context.Context, tx *sql.Tx, reservation *models.Reservation, status models.ReservationStatus) error {
+	now := time.Now().UTC()
+	_, err := tx.ExecContext(ctx,
+		`UPDATE stock_reservations SET status = $1, updated_at = $2 WHERE id = $3`,
+		status, now, reservation.ID,
+	)
+	if err != nil {
+		return err
+	}
+	reservation.Status = status
+	reservation.UpdatedAt = now
+	return nil
+}
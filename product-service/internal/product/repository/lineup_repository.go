@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrSlotOverlap - выступление пересекается с другим выступлением на той же сцене
+// (срабатывает ограничение исключения lineup_slots_no_overlap, если параллельная запись обошла проверку в usecase)
+var ErrSlotOverlap = errors.New("выступление пересекается с другим на этой сцене")
+
+// pqExclusionViolation - код ошибки PostgreSQL для нарушения ограничения исключения (EXCLUDE)
+const pqExclusionViolation = "23P01"
+
+// SlotFilter задает условия выборки выступлений фестиваля. Пустые поля не ограничивают выборку.
+type SlotFilter struct {
+	StageID  *int
+	ArtistID *int
+	From     *time.Time // Выступления, которые заканчиваются позже этого момента
+	To       *time.Time // Выступления, которые начинаются раньше этого момента
+}
+
+// LineupRepository определяет операции с исполнителями, выступлениями и личными расписаниями.
+// Отсутствующая запись при выборке обозначается nil, nil, при изменении и удалении - ошибкой sql.ErrNoRows.
+type LineupRepository interface {
+	CreateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error)
+	GetArtist(ctx context.Context, id int) (*models.Artist, error)
+	// ListArtists возвращает исполнителей по названию; query - подстрока имени без учета регистра
+	ListArtists(ctx context.Context, query string) ([]*models.Artist, error)
+	UpdateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error)
+	// DeleteArtist возвращает ErrReferenced, если у исполнителя есть выступления
+	DeleteArtist(ctx context.Context, id int) error
+
+	// CreateSlot возвращает ErrSlotOverlap, если выступление пересекается с другим на той же сцене
+	CreateSlot(ctx context.Context, slot *models.Slot) (*models.Slot, error)
+	// GetSlot возвращает выступление фестиваля с исполнителем и названием сцены
+	GetSlot(ctx context.Context, festivalID, slotID int) (*models.Slot, error)
+	// ListSlots возвращает выступления фестиваля в порядке начала
+	ListSlots(ctx context.Context, festivalID int, filter SlotFilter) ([]*models.Slot, error)
+	UpdateSlot(ctx context.Context, slot *models.Slot) (*models.Slot, error)
+	DeleteSlot(ctx context.Context, festivalID, slotID int) error
+	// FindOverlaps возвращает выступления сцены, пересекающиеся с интервалом [startsAt, endsAt).
+	// Выступление excludeSlotID (изменяемое) не учитывается. Выступления встык не пересекаются.
+	FindOverlaps(ctx context.Context, stageID int, startsAt, endsAt time.Time, excludeSlotID int) ([]*models.Slot, error)
+
+	// AddSelection добавляет выступление в личное расписание пользователя; повторное добавление ничего не меняет
+	AddSelection(ctx context.Context, userID string, slotID int) error
+	// RemoveSelection удаляет выступление из личного расписания. Возвращает sql.ErrNoRows, если его там нет.
+	RemoveSelection(ctx context.Context, userID string, slotID int) error
+	// ListSelectedSlots возвращает выступления фестиваля из личного расписания пользователя в порядке начала
+	ListSelectedSlots(ctx context.Context, festivalID int, userID string) ([]*models.Slot, error)
+}
+
+// PostgresLineupRepository реализует LineupRepository для PostgreSQL
+type PostgresLineupRepository struct {
+	db *sql.DB
+}
+
+// NewLineupRepository создает новый экземпляр PostgresLineupRepository
+func NewLineupRepository(db *sql.DB) LineupRepository {
+	return &PostgresLineupRepository{db: db}
+}
+
+// artistColumns - столбцы таблицы artists в порядке, ожидаемом scanArtist
+const artistColumns = `id, name, genre, country, description, created_at, updated_at`
+
+// CreateArtist сохраняет исполнителя.
+func (r *PostgresLineupRepository) CreateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	now := time.Now().UTC()
+	return scanArtist(r.db.QueryRowContext(ctx,
+		`INSERT INTO artists (name, genre, country, description, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $5)
+		 RETURNING `+artistColumns,
+		artist.Name, artist.Genre, artist.Country, artist.Description, now))
+}
+
+// GetArtist выбирает исполнителя по ID.
+func (r *PostgresLineupRepository) GetArtist(ctx context.Context, id int) (*models.Artist, error) {
+	return scanArtist(r.db.QueryRowContext(ctx, `SELECT `+artistColumns+` FROM artists WHERE id = $1`, id))
+}
+
+// ListArtists выбирает исполнителей, имя которых содержит query.
+func (r *PostgresLineupRepository) ListArtists(ctx context.Context, query string) ([]*models.Artist, error) {
+	sqlQuery := `SELECT ` + artistColumns + ` FROM artists`
+	var args []interface{}
+	if query != "" {
+		sqlQuery += ` WHERE name ILIKE $1`
+		args = append(args, likeContains(query))
+	}
+	sqlQuery += ` ORDER BY name, id`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	artists := make([]*models.Artist, 0)
+	for rows.Next() {
+		artist, err := scanArtist(rows)
+		if err != nil {
+			return nil, err
+		}
+		artists = append(artists, artist)
+	}
+	return artists, rows.Err()
+}
+
+// UpdateArtist сохраняет все поля исполнителя.
+func (r *PostgresLineupRepository) UpdateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	updated, err := scanArtist(r.db.QueryRowContext(ctx,
+		`UPDATE artists SET name = $1, genre = $2, country = $3, description = $4, updated_at = $5
+		 WHERE id = $6
+		 RETURNING `+artistColumns,
+		artist.Name, artist.Genre, artist.Country, artist.Description, time.Now().UTC(), artist.ID))
+	if err == nil && updated == nil {
+		return nil, sql.ErrNoRows
+	}
+	return updated, err
+}
+
+// DeleteArtist удаляет исполнителя. Выступления ссылаются на исполнителя внешним ключом.
+func (r *PostgresLineupRepository) DeleteArtist(ctx context.Context, id int) error {
+	return execDelete(ctx, r.db, `DELETE FROM artists WHERE id = $1`, id)
+}
+
+// slotColumns - столбцы выступления, его сцены и исполнителя в порядке, ожидаемом scanSlot
+const slotColumns = `sl.id, sl.festival_id, sl.stage_id, st.name, sl.artist_id, sl.starts_at, sl.ends_at,
+		 sl.created_at, sl.updated_at,
+		 a.id, a.name, a.genre, a.country, a.description, a.created_at, a.updated_at`
+
+// slotFrom - таблицы выборки выступлений со сценой и исполнителем
+const slotFrom = ` FROM lineup_slots sl JOIN stages st ON st.id = sl.stage_id JOIN artists a ON a.id = sl.artist_id`
+
+// CreateSlot сохраняет выступление.
+func (r *PostgresLineupRepository) CreateSlot(ctx context.Context, slot *models.Slot) (*models.Slot, error) {
+	now := time.Now().UTC()
+	var id int
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO lineup_slots (festival_id, stage_id, artist_id, starts_at, ends_at, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $6)
+		 RETURNING id`,
+		slot.FestivalID, slot.StageID, slot.ArtistID, slot.StartsAt, slot.EndsAt, now,
+	).Scan(&id)
+	if err != nil {
+		return nil, mapSlotError(err)
+	}
+	return r.GetSlot(ctx, slot.FestivalID, id)
+}
+
+// GetSlot выбирает выступление фестиваля.
+func (r *PostgresLineupRepository) GetSlot(ctx context.Context, festivalID, slotID int) (*models.Slot, error) {
+	return scanSlot(r.db.QueryRowContext(ctx,
+		`SELECT `+slotColumns+slotFrom+` WHERE sl.id = $1 AND sl.festival_id = $2`, slotID, festivalID))
+}
+
+// ListSlots выбирает выступления фестиваля по фильтру.
+func (r *PostgresLineupRepository) ListSlots(ctx context.Context, festivalID int, filter SlotFilter) ([]*models.Slot, error) {
+	conditions := []string{"sl.festival_id = $1"}
+	args := []interface{}{festivalID}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+	if filter.StageID != nil {
+		addCondition("sl.stage_id = $?", *filter.StageID)
+	}
+	if filter.ArtistID != nil {
+		addCondition("sl.artist_id = $?", *filter.ArtistID)
+	}
+	if filter.From != nil {
+		addCondition("sl.ends_at > $?", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("sl.starts_at < $?", *filter.To)
+	}
+
+	return r.querySlots(ctx, `SELECT `+slotColumns+slotFrom+` WHERE `+strings.Join(conditions, " AND ")+
+		` ORDER BY sl.starts_at, sl.stage_id, sl.id`, args...)
+}
+
+// UpdateSlot сохраняет сцену, исполнителя и время выступления.
+func (r *PostgresLineupRepository) UpdateSlot(ctx context.Context, slot *models.Slot) (*models.Slot, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE lineup_slots SET stage_id = $1, artist_id = $2, starts_at = $3, ends_at = $4, updated_at = $5
+		 WHERE id = $6 AND festival_id = $7`,
+		slot.StageID, slot.ArtistID, slot.StartsAt, slot.EndsAt, time.Now().UTC(), slot.ID, slot.FestivalID)
+	if err != nil {
+		return nil, mapSlotError(err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return r.GetSlot(ctx, slot.FestivalID, slot.ID)
+}
+
+// DeleteSlot удаляет выступление; оно удаляется и из личных расписаний.
+func (r *PostgresLineupRepository) DeleteSlot(ctx context.Context, festivalID, slotID int) error {
+	return execDelete(ctx, r.db, `DELETE FROM lineup_slots WHERE id = $1 AND festival_id = $2`, slotID, festivalID)
+}
+
+// FindOverlaps выбирает пересекающиеся выступления сцены (индекс lineup_slots_no_overlap).
+func (r *PostgresLineupRepository) FindOverlaps(ctx context.Context, stageID int, startsAt, endsAt time.Time, excludeSlotID int) ([]*models.Slot, error) {
+	return r.querySlots(ctx, `SELECT `+slotColumns+slotFrom+`
+		 WHERE sl.stage_id = $1 AND sl.starts_at < $3 AND sl.ends_at > $2 AND sl.id <> $4
+		 ORDER BY sl.starts_at, sl.id`,
+		stageID, startsAt, endsAt, excludeSlotID)
+}
+
+// AddSelection добавляет выступление в личное расписание.
+func (r *PostgresLineupRepository) AddSelection(ctx context.Context, userID string, slotID int) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO schedule_selections (user_id, slot_id, created_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, slot_id) DO NOTHING`,
+		userID, slotID, time.Now().UTC())
+	return mapConstraintError(err)
+}
+
+// RemoveSelection удаляет выступление из личного расписания.
+func (r *PostgresLineupRepository) RemoveSelection(ctx context.Context, userID string, slotID int) error {
+	return execDelete(ctx, r.db, `DELETE FROM schedule_selections WHERE user_id = $1 AND slot_id = $2`, userID, slotID)
+}
+
+// ListSelectedSlots выбирает выступления фестиваля из личного расписания.
+func (r *PostgresLineupRepository) ListSelectedSlots(ctx context.Context, festivalID int, userID string) ([]*models.Slot, error) {
+	return r.querySlots(ctx, `SELECT `+slotColumns+slotFrom+`
+		 JOIN schedule_selections ss ON ss.slot_id = sl.id
+		 WHERE sl.festival_id = $1 AND ss.user_id = $2
+		 ORDER BY sl.starts_at, sl.stage_id, sl.id`,
+		festivalID, userID)
+}
+
+// querySlots выполняет запрос со столбцами slotColumns и читает все выступления.
+func (r *PostgresLineupRepository) querySlots(ctx context.Context, query string, args ...interface{}) ([]*models.Slot, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := make([]*models.Slot, 0)
+	for rows.Next() {
+		slot, err := scanSlot(rows)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
+
+// mapSlotError переводит нарушение ограничения исключения в ErrSlotOverlap, остальные - как mapConstraintError.
+func mapSlotError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqExclusionViolation {
+		return ErrSlotOverlap
+	}
+	return mapConstraintError(err)
+}
+
+// scanArtist читает исполнителя из строки со столбцами artistColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanArtist(row rowScanner) (*models.Artist, error) {
+	artist := &models.Artist{}
+	err := row.Scan(&artist.ID, &artist.Name, &artist.Genre, &artist.Country, &artist.Description,
+		&artist.CreatedAt, &artist.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return artist, nil
+}
+
+// scanSlot читает выступление с исполнителем из строки со столбцами slotColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanSlot(row rowScanner) (*models.Slot, error) {
+	slot := &models.Slot{Artist: &models.Artist{}}
+	artist := slot.Artist
+	err := row.Scan(
+		&slot.ID, &slot.FestivalID, &slot.StageID, &slot.StageName, &slot.ArtistID, &slot.StartsAt, &slot.EndsAt,
+		&slot.CreatedAt, &slot.UpdatedAt,
+		&artist.ID, &artist.Name, &artist.Genre, &artist.Country, &artist.Description, &artist.CreatedAt, &artist.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	slot.StartsAt = slot.StartsAt.UTC()
+	slot.EndsAt = slot.EndsAt.UTC()
+	return slot, nil
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	// ВАЖНО: Замените 'your_product_module_path' на имя вашего модуля product-service из go.mod
 	// Например: "github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
@@ -13,39 +18,144 @@ import (
 
 // ProductRepository определяет интерфейс для взаимодействия с хранилищем данных продуктов
 type ProductRepository interface {
+	// Create и Update сохраняют продукт вместе с вариантами в одной транзакции.
+	// Update возвращает ErrVariantNotFound, ErrVariantInUse и ErrDuplicate при ошибках в наборе вариантов.
 	Create(ctx context.Context, product *models.Product) (*models.Product, error)
+	// GetByID возвращает продукт с вариантами
 	GetByID(ctx context.Context, id int) (*models.Product, error)
 	ListAll(ctx context.Context) ([]*models.Product, error)
+	// ListPage возвращает страницу продуктов по фильтру, сортировке и курсору
+	ListPage(ctx context.Context, opts ProductListOptions) ([]*models.Product, error)
+	// Count считает продукты по фильтру
+	Count(ctx context.Context, filter ProductFilter) (int64, error)
+	// Search ищет продукты по словам названия и описания в порядке релевантности
+	Search(ctx context.Context, opts ProductSearchOptions) ([]*models.ProductSearchResult, error)
 	Update(ctx context.Context, product *models.Product) (*models.Product, error)
 	Delete(ctx context.Context, id int) error
 }
 
+// Поля сортировки списка продуктов
+const (
+	ProductSortCreatedAt = "created_at"
+	ProductSortPrice     = "price"
+	ProductSortName      = "name"
+)
+
+// productSortColumns - поля сортировки и соответствующие им столбцы с приведением типа значения курсора.
+// Вторым ключом сортировки всегда служит id, чтобы порядок был однозначным.
+var productSortColumns = map[string]struct{ column, cast string }{
+	ProductSortCreatedAt: {"created_at", "::TIMESTAMPTZ"},
+	ProductSortPrice:     {"price", "::NUMERIC"},
+	ProductSortName:      {"name", ""},
+}
+
+// IsValidProductSort сообщает, поддерживается ли сортировка по полю field
+func IsValidProductSort(field string) bool {
+	_, ok := productSortColumns[field]
+	return ok
+}
+
+// ProductSortValue возвращает значение поля сортировки field продукта для курсора
+func ProductSortValue(product *models.Product, field string) string {
+	switch field {
+	case ProductSortPrice:
+		return strconv.FormatFloat(product.Price, 'f', -1, 64)
+	case ProductSortName:
+		return product.Name
+	default:
+		return product.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// ProductFilter задает условия выборки продуктов. Пустые поля не ограничивают выборку.
+type ProductFilter struct {
+	Type       models.ProductType
+	FestivalID *int
+	MinPrice   *float64 // Включительно
+	MaxPrice   *float64 // Включительно
+	InStock    bool     // Только продукты в наличии (остаток больше нуля или неограничен)
+	Query      string   // Подстрока названия или описания, без учета регистра
+}
+
+// ProductCursor - позиция в списке: значение поля сортировки (ProductSortValue) и id последнего продукта
+type ProductCursor struct {
+	Value string
+	ID    int
+}
+
+// ProductListOptions задает страницу списка продуктов
+type ProductListOptions struct {
+	Filter ProductFilter
+	SortBy string // ProductSort*; по умолчанию ProductSortCreatedAt
+	Desc   bool
+	After  *ProductCursor
+	Limit  int
+}
+
+// Конфигурации полнотекстового поиска PostgreSQL: каталог двуязычный,
+// поэтому search_vector содержит лексемы обеих конфигураций.
+const (
+	SearchConfigRussian = "russian"
+	SearchConfigEnglish = "english"
+)
+
+// ProductSearchOptions задает полнотекстовый поиск
+type ProductSearchOptions struct {
+	Text string
+	// Configs - конфигурации, по которым разбирается запрос (SearchConfig*); совпадение по любой из них.
+	// Первая используется и для выделения совпадений.
+	Configs []string
+	Filter  ProductFilter
+	Limit   int
+}
+
 // PostgresProductRepository реализует интерфейс ProductRepository для PostgreSQL
 type PostgresProductRepository struct {
 	db *sql.DB
 }
 
-// Create создает новую запись продукта в базе данных
+// Create создает новую запись продукта в базе данных вместе с его вариантами (product.Variants).
+// Остаток продукта с вариантами вычисляется по остаткам вариантов.
 func (r *PostgresProductRepository) Create(ctx context.Context, product *models.Product) (*models.Product, error) {
 	product.CreatedAt = time.Now().UTC()
 	product.UpdatedAt = time.Now().UTC()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `INSERT INTO products (name, description, price, type, stock, festival_id, created_at, updated_at)
 			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 			   RETURNING id, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		product.Name, product.Description, product.Price, product.Type, product.Stock, product.FestivalID, product.CreatedAt, product.UpdatedAt,
 	).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt)
 
 	if err != nil {
-		// TODO: Обработка специфичных ошибок БД (например, нарушение ограничений)
+		// Нарушение внешнего ключа festival_id - фестиваля нет
+		return nil, mapConstraintError(err)
+	}
+
+	if len(product.Variants) > 0 {
+		product.Variants, err = replaceVariants(ctx, tx, product.ID, product.Variants, product.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if product.Stock, err = syncProductStock(ctx, tx, product.ID, product.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 	return product, nil
 }
 
-// GetByID извлекает продукт из базы данных по его ID
+// GetByID извлекает продукт из базы данных по его ID вместе с вариантами
 func (r *PostgresProductRepository) GetByID(ctx context.Context, id int) (*models.Product, error) {
 	product := &models.Product{}
 	query := `SELECT id, name, description, price, type, stock, festival_id, created_at, updated_at
@@ -61,11 +171,16 @@ func (r *PostgresProductRepository) GetByID(ctx context.Context, id int) (*model
 		}
 		return nil, err
 	}
+
+	product.Variants, err = listVariants(ctx, r.db, id)
+	if err != nil {
+		return nil, err
+	}
 	return product, nil
 }
 
-// ListAll извлекает все продукты из базы данных
-// В реальном приложении здесь, скорее всего, понадобится пагинация.
+// ListAll извлекает все продукты из базы данных.
+// Для каталога используется ListPage с фильтрами и пагинацией.
 func (r *PostgresProductRepository) ListAll(ctx context.Context) ([]*models.Product, error) {
 	query := `SELECT id, name, description, price, type, stock, festival_id, created_at, updated_at
 			   FROM products ORDER BY created_at DESC` // Пример сортировки
@@ -94,16 +209,200 @@ func (r *PostgresProductRepository) ListAll(ctx context.Context) ([]*models.Prod
 	return products, nil
 }
 
-// Update обновляет существующую запись продукта в базе данных
+// ListPage выбирает страницу продуктов по курсору (keyset-пагинация: без OFFSET,
+// новые продукты не сдвигают страницы).
+func (r *PostgresProductRepository) ListPage(ctx context.Context, opts ProductListOptions) ([]*models.Product, error) {
+	sort, ok := productSortColumns[opts.SortBy]
+	if !ok {
+		sort = productSortColumns[ProductSortCreatedAt]
+	}
+	direction, comparison := "ASC", ">"
+	if opts.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := productFilterConditions(opts.Filter)
+	if opts.After != nil {
+		args = append(args, opts.After.Value, opts.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s ($%d%s, $%d)", sort.column, comparison, len(args)-1, sort.cast, len(args)))
+	}
+
+	query := `SELECT id, name, description, price, type, stock, festival_id, created_at, updated_at FROM products`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, opts.Limit)
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", sort.column, direction, direction, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make([]*models.Product, 0)
+	for rows.Next() {
+		product := &models.Product{}
+		if err := rows.Scan(
+			&product.ID, &product.Name, &product.Description, &product.Price, &product.Type, &product.Stock, &product.FestivalID, &product.CreatedAt, &product.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+// Count считает продукты по фильтру.
+func (r *PostgresProductRepository) Count(ctx context.Context, filter ProductFilter) (int64, error) {
+	conditions, args := productFilterConditions(filter)
+	query := `SELECT COUNT(*) FROM products`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&total)
+	return total, err
+}
+
+// productFilterConditions строит условия WHERE для фильтра с параметрами $1, $2, ...
+func productFilterConditions(filter ProductFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.Type != "" {
+		addCondition("type = $?", filter.Type)
+	}
+	if filter.FestivalID != nil {
+		addCondition("festival_id = $?", *filter.FestivalID)
+	}
+	if filter.MinPrice != nil {
+		addCondition("price >= $?", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		addCondition("price <= $?", *filter.MaxPrice)
+	}
+	if filter.InStock {
+		// -1 - неограниченное количество
+		conditions = append(conditions, "stock <> 0")
+	}
+	// ILIKE по подстроке использует триграммные индексы (pg_trgm)
+	if filter.Query != "" {
+		addCondition("(name ILIKE $? OR description ILIKE $?)", likeContains(filter.Query))
+	}
+	return conditions, args
+}
+
+// likeContains возвращает шаблон LIKE для поиска подстроки с экранированными спецсимволами
+func likeContains(value string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value) + "%"
+}
+
+// Маркеры совпадений в ts_headline: управляющие символы не встречаются в тексте каталога,
+// поэтому после экранирования HTML их можно безопасно заменить на теги
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// Search выполняет полнотекстовый поиск по search_vector (GIN-индекс products_search_idx).
+// Запрос превращается в tsquery из слов через &, последнее слово ищется по префиксу (подсказки при наборе).
+// Продукты упорядочены по ts_rank_cd: совпадения в названии весят больше, чем в описании.
+func (r *PostgresProductRepository) Search(ctx context.Context, opts ProductSearchOptions) ([]*models.ProductSearchResult, error) {
+	tsQuery := prefixTSQuery(opts.Text)
+	if tsQuery == "" || len(opts.Configs) == 0 {
+		return []*models.ProductSearchResult{}, nil
+	}
+
+	conditions, args := productFilterConditions(opts.Filter)
+	args = append(args, tsQuery)
+	queryArg := len(args)
+	parts := make([]string, len(opts.Configs))
+	for i, config := range opts.Configs {
+		// Имя конфигурации подставляется как литерал: to_tsquery с regconfig-константой использует индекс
+		parts[i] = fmt.Sprintf("to_tsquery('%s', $%d)", config, queryArg)
+	}
+	conditions = append(conditions, "search_vector @@ q.query")
+
+	headline := fmt.Sprintf(`'StartSel=%s, StopSel=%s, HighlightAll=true'`, highlightStart, highlightStop)
+	snippet := fmt.Sprintf(`'StartSel=%s, StopSel=%s, MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=" … "'`,
+		highlightStart, highlightStop)
+	args = append(args, opts.Limit)
+	query := fmt.Sprintf(`SELECT id, name, description, price, type, stock, festival_id, created_at, updated_at,
+			   ts_rank_cd(search_vector, q.query) AS rank,
+			   ts_headline('%[1]s', name, q.query, %[2]s),
+			   ts_headline('%[1]s', coalesce(description, ''), q.query, %[3]s)
+			   FROM products, (SELECT %[4]s AS query) q
+			   WHERE %[5]s
+			   ORDER BY rank DESC, id
+			   LIMIT $%[6]d`,
+		opts.Configs[0], headline, snippet, strings.Join(parts, " || "), strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := make([]*models.ProductSearchResult, 0)
+	for rows.Next() {
+		product := &models.Product{}
+		result := &models.ProductSearchResult{Product: product}
+		if err := rows.Scan(
+			&product.ID, &product.Name, &product.Description, &product.Price, &product.Type, &product.Stock, &product.FestivalID, &product.CreatedAt, &product.UpdatedAt,
+			&result.Rank, &result.NameHighlight, &result.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		result.NameHighlight = markHighlights(result.NameHighlight)
+		result.Snippet = markHighlights(result.Snippet)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// prefixTSQuery строит текст tsquery из слов запроса: "vip weekend pa" -> "vip & weekend & pa:*".
+// В запрос попадают только буквы и цифры, поэтому синтаксис tsquery из ввода пользователя не разбирается.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
+
+// markHighlights экранирует фрагмент для HTML и заменяет маркеры совпадений тегами <mark>
+func markHighlights(fragment string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(fragment))
+}
+
+// Update обновляет существующую запись продукта в базе данных.
+// Если product.Variants не nil, варианты продукта заменяются этим набором (см. replaceVariants),
+// иначе остаются прежними. Остаток продукта с вариантами всегда пересчитывается по вариантам.
 func (r *PostgresProductRepository) Update(ctx context.Context, product *models.Product) (*models.Product, error) {
 	product.UpdatedAt = time.Now().UTC()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	query := `UPDATE products
 			   SET name = $1, description = $2, price = $3, type = $4, stock = $5, festival_id = $6, updated_at = $7
 			   WHERE id = $8
 			   RETURNING id, name, description, price, type, stock, festival_id, created_at, updated_at`
 
 	updatedProduct := &models.Product{}
-	err := r.db.QueryRowContext(ctx, query,
+	err = tx.QueryRowContext(ctx, query,
 		product.Name, product.Description, product.Price, product.Type, product.Stock, product.FestivalID, product.UpdatedAt, product.ID,
 	).Scan(
 		&updatedProduct.ID, &updatedProduct.Name, &updatedProduct.Description, &updatedProduct.Price, &updatedProduct.Type, &updatedProduct.Stock, &updatedProduct.FestivalID, &updatedProduct.CreatedAt, &updatedProduct.UpdatedAt,
@@ -113,6 +412,25 @@ func (r *PostgresProductRepository) Update(ctx context.Context, product *models.
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil // Продукт не найден для обновления
 		}
+		return nil, mapConstraintError(err)
+	}
+
+	// UPDATE заблокировал строку продукта, поэтому конкурентные резервы ждут конца транзакции
+	if product.Variants != nil {
+		updatedProduct.Variants, err = replaceVariants(ctx, tx, product.ID, product.Variants, product.UpdatedAt)
+	} else {
+		updatedProduct.Variants, err = listVariants(ctx, tx, product.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(updatedProduct.Variants) > 0 {
+		if updatedProduct.Stock, err = syncProductStock(ctx, tx, product.ID, product.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 	return updatedProduct, nil
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+
+	_ "github.com/lib/pq"
+)
+
+// openTestDB подключается к базе из PRODUCT_TEST_DB_DSN и применяет схему; без переменной тест пропускается.
+// Например: PRODUCT_TEST_DB_DSN="host=localhost port=5433 user=postgres password=postgres dbname=product_service_db sslmode=disable"
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("PRODUCT_TEST_DB_DSN")
+	if dsn == "" {
+		t.Skip("PRODUCT_TEST_DB_DSN не задан, тесты PostgreSQL репозитория пропущены")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema, err := os.ReadFile("../../../db/init.sql")
+	if err != nil {
+		t.Fatalf("чтение схемы: %v", err)
+	}
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("применение схемы: %v", err)
+	}
+	return db
+}
+
+// createTiedProducts создает продукты с повторяющимися ценой, названием и временем создания,
+// помеченные в описании строкой marker, и удаляет их после теста
+func createTiedProducts(t *testing.T, db *sql.DB, marker string) []*models.Product {
+	t.Helper()
+	ctx := context.Background()
+	repo := NewProductRepository(db)
+	base := time.Date(2026, 5, 1, 12, 0, 0, 123456000, time.UTC)
+
+	specs := []struct {
+		name      string
+		price     float64
+		createdAt time.Time
+	}{
+		{"bbb", 100, base},
+		{"aaa", 25.5, base},
+		{"bbb", 100, base.Add(time.Microsecond)},
+		{"ccc", 25.5, base},
+		{"bbb", 100, base.Add(-time.Hour)},
+		{"ccc", 0, base.Add(time.Microsecond)},
+		{"aaa", 100, base},
+	}
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM products WHERE description = $1`, marker)
+	})
+
+	products := make([]*models.Product, 0, len(specs))
+	for _, spec := range specs {
+		product, err := repo.Create(ctx, &models.Product{
+			Name: spec.name, Description: marker, Price: spec.price, Type: models.Merchandise, Stock: 1,
+		})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		// Время создания задается явно, чтобы получить одинаковые значения у разных продуктов
+		if _, err := db.Exec(`UPDATE products SET created_at = $1 WHERE id = $2`, spec.createdAt, product.ID); err != nil {
+			t.Fatalf("UPDATE created_at: %v", err)
+		}
+		product.CreatedAt = spec.createdAt
+		products = append(products, product)
+	}
+	return products
+}
+
+// expectedOrder возвращает ID продуктов в порядке (поле сортировки, id)
+func expectedOrder(products []*models.Product, sortBy string, desc bool) []int {
+	sorted := append([]*models.Product(nil), products...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		var less, equal bool
+		switch sortBy {
+		case ProductSortPrice:
+			less, equal = a.Price < b.Price, a.Price == b.Price
+		case ProductSortName:
+			less, equal = a.Name < b.Name, a.Name == b.Name
+		default:
+			less, equal = a.CreatedAt.Before(b.CreatedAt), a.CreatedAt.Equal(b.CreatedAt)
+		}
+		if equal {
+			less = a.ID < b.ID
+		}
+		return less != desc
+	})
+	ids := make([]int, len(sorted))
+	for i, product := range sorted {
+		ids[i] = product.ID
+	}
+	return ids
+}
+
+func TestListPageKeysetPagination(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := NewProductRepository(db)
+	marker := fmt.Sprintf("keyset-test-%d", time.Now().UnixNano())
+	products := createTiedProducts(t, db, marker)
+
+	for _, sortBy := range []string{ProductSortCreatedAt, ProductSortPrice, ProductSortName} {
+		for _, desc := range []bool{false, true} {
+			for _, limit := range []int{1, 2, 3, len(products)} {
+				t.Run(fmt.Sprintf("%s desc=%v limit=%d", sortBy, desc, limit), func(t *testing.T) {
+					var got []int
+					opts := ProductListOptions{Filter: ProductFilter{Query: marker}, SortBy: sortBy, Desc: desc, Limit: limit}
+					for {
+						page, err := repo.ListPage(ctx, opts)
+						if err != nil {
+							t.Fatalf("ListPage: %v", err)
+						}
+						if len(page) > limit {
+							t.Fatalf("на странице %d продуктов при Limit %d", len(page), limit)
+						}
+						for _, product := range page {
+							got = append(got, product.ID)
+						}
+						if len(page) < limit || len(got) > len(products) {
+							break
+						}
+						last := page[len(page)-1]
+						opts.After = &ProductCursor{Value: ProductSortValue(last, sortBy), ID: last.ID}
+					}
+					if want := expectedOrder(products, sortBy, desc); !slices.Equal(got, want) {
+						t.Fatalf("порядок %v, ожидался %v", got, want)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestListPageRejectsUncastableCursor(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewProductRepository(db)
+
+	// Значение курсора приводится к типу столбца в запросе: мусор дает ошибку, а не произвольную выборку.
+	// Usecase проверяет значение до запроса (validSortValue), чтобы клиент получил 400.
+	for _, sortBy := range []string{ProductSortCreatedAt, ProductSortPrice} {
+		_, err := repo.ListPage(context.Background(), ProductListOptions{
+			SortBy: sortBy, Limit: 1, After: &ProductCursor{Value: "'; DROP TABLE products; --", ID: 1},
+		})
+		if err == nil {
+			t.Fatalf("%s: курсор с некорректным значением принят", sortBy)
+		}
+	}
+
+	// Значение курсора по имени передается параметром и не меняет запрос
+	if _, err := repo.ListPage(context.Background(), ProductListOptions{
+		SortBy: ProductSortName, Limit: 1, After: &ProductCursor{Value: "'; DROP TABLE products; --", ID: 1},
+	}); err != nil {
+		t.Fatalf("курсор по имени: %v", err)
+	}
+}
+
+func TestListPageUnknownSortFallsBackToCreatedAt(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewProductRepository(db)
+	marker := fmt.Sprintf("keyset-test-%d", time.Now().UnixNano())
+	products := createTiedProducts(t, db, marker)
+
+	// Поле сортировки не попадает в запрос как есть: вне списка используется created_at
+	page, err := repo.ListPage(context.Background(), ProductListOptions{
+		Filter: ProductFilter{Query: marker}, SortBy: "price; DROP TABLE products", Limit: len(products),
+	})
+	if err != nil {
+		t.Fatalf("ListPage: %v", err)
+	}
+	got := make([]int, len(page))
+	for i, product := range page {
+		got[i] = product.ID
+	}
+	if want := expectedOrder(products, ProductSortCreatedAt, false); !slices.Equal(got, want) {
+		t.Fatalf("порядок %v, ожидался %v", got, want)
+	}
+}
+
+func TestProductSortWhitelist(t *testing.T) {
+	for _, field := range []string{ProductSortCreatedAt, ProductSortPrice, ProductSortName} {
+		if !IsValidProductSort(field) {
+			t.Fatalf("поле %q не поддерживается", field)
+		}
+	}
+	for _, field := range []string{"", "id", "stock", "Price", "-price", "price desc", "name; DROP TABLE products"} {
+		if IsValidProductSort(field) {
+			t.Fatalf("поле %q не должно поддерживаться", field)
+		}
+	}
+
+	product := &models.Product{
+		Name:      "Футболка, XL",
+		Price:     25.5,
+		CreatedAt: time.Date(2026, 5, 1, 15, 0, 0, 123456000, time.FixedZone("MSK", 3*60*60)),
+	}
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{ProductSortPrice, "25.5"},
+		{ProductSortName, "Футболка, XL"},
+		{ProductSortCreatedAt, "2026-05-01T12:00:00.123456Z"},
+	}
+	for _, tt := range tests {
+		if got := ProductSortValue(product, tt.field); got != tt.want {
+			t.Fatalf("ProductSortValue(%s) = %q, ожидалось %q", tt.field, got, tt.want)
+		}
+	}
+	if got := ProductSortValue(&models.Product{Price: 100}, ProductSortPrice); got != "100" {
+		t.Fatalf("ProductSortValue целой цены = %q, ожидалось \"100\"", got)
+	}
+}
+
+func TestPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"одно слово", "vip", "vip:*"},
+		{"несколько слов", "vip weekend pa", "vip & weekend & pa:*"},
+		{"кириллица и цифры", "билет 2026", "билет & 2026:*"},
+		{"лишние пробелы", "  vip \t  weekend\n", "vip & weekend:*"},
+		{"операторы tsquery", "vip & !weekend | (pass):*", "vip & weekend & pass:*"},
+		{"оператор следования", "vip <-> pass <2> day", "vip & pass & 2 & day:*"},
+		{"кавычки и вес", "'vip':A*", "vip & A:*"},
+		{"операторы внутри слова", "rock&roll!", "rock & roll:*"},
+		{"экранирование", `vip\:*`, "vip:*"},
+		{"дефис и точка", "т-shirt v.2", "т & shirt & v & 2:*"},
+		{"только операторы", "& | ! ( ) : *", ""},
+		{"только знаки", "'\"<->\\", ""},
+		{"пустая строка", "", ""},
+		{"только пробелы", "   ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prefixTSQuery(tt.text); got != tt.want {
+				t.Fatalf("prefixTSQuery(%q) = %q, ожидалось %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkHighlights(t *testing.T) {
+	tests := []struct {
+		name     string
+		fragment string
+		want     string
+	}{
+		{"без совпадений", "Билет на фестиваль", "Билет на фестиваль"},
+		{"одно совпадение", "Билет \x02VIP\x03", "Билет <mark>VIP</mark>"},
+		{"несколько совпадений", "\x02VIP\x03 и \x02VIP\x03 … \x02pass\x03", "<mark>VIP</mark> и <mark>VIP</mark> … <mark>pass</mark>"},
+		{"script в тексте", "<script>alert(1)</script> \x02VIP\x03", "&lt;script&gt;alert(1)&lt;/script&gt; <mark>VIP</mark>"},
+		{"script в совпадении", "\x02<script>\x03alert(1)", "<mark>&lt;script&gt;</mark>alert(1)"},
+		{"готовый тег mark в тексте", "<mark>VIP</mark>", "&lt;mark&gt;VIP&lt;/mark&gt;"},
+		{"атрибут и кавычки", `<img src=x onerror="alert('1')">`, "&lt;img src=x onerror=&#34;alert(&#39;1&#39;)&#34;&gt;"},
+		{"амперсанд", "Rock & \x02Roll\x03", "Rock &amp; <mark>Roll</mark>"},
+		{"пустой фрагмент", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := markHighlights(tt.fragment)
+			if got != tt.want {
+				t.Fatalf("markHighlights(%q) = %q, ожидалось %q", tt.fragment, got, tt.want)
+			}
+			// Кроме тегов <mark> в результате нет разметки
+			rest := strings.NewReplacer("<mark>", "", "</mark>", "").Replace(got)
+			if strings.ContainsAny(rest, "<>\x02\x03") {
+				t.Fatalf("в результате осталась разметка или маркеры: %q", got)
+			}
+		})
+	}
+}
+
+func TestSearchSanitizesQueryAndHighlights(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	repo := NewProductRepository(db)
+	marker := fmt.Sprintf("search-test-%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		db.Exec(`DELETE FROM products WHERE description LIKE $1`, marker+"%")
+	})
+
+	product, err := repo.Create(ctx, &models.Product{
+		Name: "<script>alert(1)</script> Rockfest", Description: marker + " <b>weekend</b> pass",
+		Price: 10, Type: models.Ticket, Stock: 1,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Операторы tsquery во вводе не ломают запрос: to_tsquery получает только слова
+	for _, text := range []string{"rockfest & !(", "rock", "weekend | ):*", "'rockfest'"} {
+		results, err := repo.Search(ctx, ProductSearchOptions{
+			Text: text, Configs: []string{SearchConfigEnglish, SearchConfigRussian}, Filter: ProductFilter{Query: marker}, Limit: 10,
+		})
+		if err != nil {
+			t.Fatalf("Search(%q): %v", text, err)
+		}
+		if len(results) != 1 || results[0].Product.ID != product.ID {
+			t.Fatalf("Search(%q): найдено %d продуктов", text, len(results))
+		}
+		for _, highlighted := range []string{results[0].NameHighlight, results[0].Snippet} {
+			rest := strings.NewReplacer("<mark>", "", "</mark>", "").Replace(highlighted)
+			if strings.ContainsAny(rest, "<>") {
+				t.Fatalf("Search(%q): разметка из данных не экранирована: %q", text, highlighted)
+			}
+		}
+	}
+
+	results, err := repo.Search(ctx, ProductSearchOptions{Text: "& | !", Configs: []string{SearchConfigEnglish}, Limit: 10})
+	if err != nil || len(results) != 0 {
+		t.Fatalf("запрос без слов: %d результатов, ошибка %v", len(results), err)
+	}
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrVariantNotFound - у продукта нет варианта с таким ID
+	ErrVariantNotFound = errors.New("вариант продукта не найден")
+	// ErrVariantRequired - продукт с вариантами резервируется только по варианту
+	ErrVariantRequired = errors.New("для продукта с вариантами нужно указать вариант")
+	// ErrVariantInUse - вариант нельзя удалить: под него есть действующие резервы
+	ErrVariantInUse = errors.New("вариант зарезервирован под заказы")
+)
+
+// Варианты хранятся в product_variants и читаются и изменяются вместе с продуктом
+// в ProductRepository; резервы вариантов - в InventoryRepository.
+// Если у продукта есть варианты, products.stock - их суммарный остаток (-1, если хотя бы один неограничен),
+// он пересчитывается syncProductStock в той же транзакции, что и изменение вариантов.
+
+const variantColumns = `id, product_id, sku, attributes, price, stock, created_at, updated_at`
+
+// dbQuerier - общий интерфейс *sql.DB и *sql.Tx для чтения
+type dbQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// listVariants возвращает варианты продукта в порядке создания
+func listVariants(ctx context.Context, q dbQuerier, productID int) ([]*models.ProductVariant, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT `+variantColumns+` FROM product_variants WHERE product_id = $1 ORDER BY id`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var variants []*models.ProductVariant
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+// replaceVariants приводит варианты продукта к набору variants: варианты с ID обновляются,
+// без ID - создаются, отсутствующие в наборе - удаляются. Возвращает сохраненные варианты в порядке variants.
+// Возвращает ErrVariantNotFound, если вариант с ID не принадлежит продукту, ErrVariantInUse, если под
+// удаляемый вариант есть действующие резервы, и ErrDuplicate при повторе артикула или набора атрибутов.
+// Строка продукта должна быть заблокирована вызывающим, чтобы не пропустить конкурентный резерв.
+func replaceVariants(ctx context.Context, tx *sql.Tx, productID int, variants []*models.ProductVariant, now time.Time) ([]*models.ProductVariant, error) {
+	keep := []int64{}
+	for _, variant := range variants {
+		if variant.ID != 0 {
+			keep = append(keep, int64(variant.ID))
+		}
+	}
+
+	var inUse bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM stock_reservations r JOIN product_variants v ON v.id = r.variant_id
+			WHERE v.product_id = $1 AND NOT (v.id = ANY($2)) AND r.status <> $3
+		 )`,
+		productID, pq.Array(keep), models.ReservationReleased,
+	).Scan(&inUse)
+	if err != nil {
+		return nil, err
+	}
+	if inUse {
+		return nil, ErrVariantInUse
+	}
+	// Удаляем до вставки, чтобы новый вариант мог занять артикул удаленного
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM product_variants WHERE product_id = $1 AND NOT (id = ANY($2))`, productID, pq.Array(keep))
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make([]*models.ProductVariant, 0, len(variants))
+	for _, variant := range variants {
+		attributes, err := json.Marshal(variant.Attributes)
+		if err != nil {
+			return nil, err
+		}
+
+		var row *sql.Row
+		if variant.ID != 0 {
+			row = tx.QueryRowContext(ctx,
+				`UPDATE product_variants SET sku = $1, attributes = $2, price = $3, stock = $4, updated_at = $5
+				 WHERE id = $6 AND product_id = $7
+				 RETURNING `+variantColumns,
+				variant.SKU, string(attributes), variant.Price, variant.Stock, now, variant.ID, productID)
+		} else {
+			row = tx.QueryRowContext(ctx,
+				`INSERT INTO product_variants (product_id, sku, attributes, price, stock, created_at, updated_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $6)
+				 RETURNING `+variantColumns,
+				productID, variant.SKU, string(attributes), variant.Price, variant.Stock, now)
+		}
+		savedVariant, err := scanVariant(row)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrVariantNotFound
+			}
+			return nil, mapConstraintError(err)
+		}
+		saved = append(saved, savedVariant)
+	}
+	return saved, nil
+}
+
+// lockProduct блокирует строку продукта до конца транзакции и сообщает, есть ли у него варианты.
+// Возвращает sql.ErrNoRows, если продукта нет.
+func lockProduct(ctx context.Context, tx *sql.Tx, productID int) (bool, error) {
+	var hasVariants bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+		 FROM products p WHERE p.id = $1 FOR UPDATE OF p`,
+		productID,
+	).Scan(&hasVariants)
+	return hasVariants, err
+}
+
+// syncProductStock записывает в products.stock суммарный остаток вариантов и возвращает его.
+// Возвращает sql.ErrNoRows, если у продукта нет вариантов: тогда остаток продукта не меняется.
+func syncProductStock(ctx context.Context, tx *sql.Tx, productID int, now time.Time) (int, error) {
+	var stock int
+	err := tx.QueryRowContext(ctx,
+		`UPDATE products p SET stock = v.total, updated_at = $2
+		 FROM (
+			SELECT CASE WHEN bool_or(stock = -1) THEN -1 ELSE sum(stock) END AS total
+			FROM product_variants WHERE product_id = $1
+			HAVING count(*) > 0
+		 ) v
+		 WHERE p.id = $1
+		 RETURNING p.stock`,
+		productID, now,
+	).Scan(&stock)
+	return stock, err
+}
+
+// scanVariant читает вариант из строки со столбцами variantColumns
+func scanVariant(row rowScanner) (*models.ProductVariant, error) {
+	variant := &models.ProductVariant{}
+	var attributes []byte
+	var price sql.NullFloat64
+	err := row.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &attributes, &price, &variant.Stock,
+		&variant.CreatedAt, &variant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(attributes, &variant.Attributes); err != nil {
+		return nil, err
+	}
+	if price.Valid {
+		variant.Price = &price.Float64
+	}
+	return variant, nil
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // Часовые пояса фестивалей не зависят от системной базы, как и в main.go
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+)
+
+// Фейковые хранилища в памяти для тестов usecase. Встроенный интерфейс репозитория остается nil:
+// вызов метода, который фейк не реализует, завершит тест паникой и сразу покажет, чего не хватает.
+
+// fakeFestivalRepository хранит фестивали со сценами в памяти
+type fakeFestivalRepository struct {
+	repository.FestivalRepository
+
+	festivals map[int]*models.Festival
+}
+
+func newFakeFestivalRepository(festivals ...*models.Festival) *fakeFestivalRepository {
+	repo := &fakeFestivalRepository{festivals: make(map[int]*models.Festival)}
+	for _, festival := range festivals {
+		repo.festivals[festival.ID] = festival
+	}
+	return repo
+}
+
+func (r *fakeFestivalRepository) GetByID(_ context.Context, id int) (*models.Festival, error) {
+	festival, ok := r.festivals[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *festival
+	return &found, nil
+}
+
+// fakeLineupRepository хранит исполнителей, выступления и личные расписания в памяти
+type fakeLineupRepository struct {
+	repository.LineupRepository
+
+	mu         sync.Mutex
+	festivals  *fakeFestivalRepository // Для названий сцен
+	artists    map[int]*models.Artist
+	slots      map[int]*models.Slot
+	nextSlotID int
+	selections map[string]map[int]bool // userID -> ID выступлений
+}
+
+func newFakeLineupRepository(festivals *fakeFestivalRepository, artists ...*models.Artist) *fakeLineupRepository {
+	repo := &fakeLineupRepository{
+		festivals:  festivals,
+		artists:    make(map[int]*models.Artist),
+		slots:      make(map[int]*models.Slot),
+		selections: make(map[string]map[int]bool),
+	}
+	for _, artist := range artists {
+		repo.artists[artist.ID] = artist
+	}
+	return repo
+}
+
+func (r *fakeLineupRepository) GetArtist(_ context.Context, id int) (*models.Artist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	artist, ok := r.artists[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *artist
+	return &found, nil
+}
+
+func (r *fakeLineupRepository) CreateSlot(_ context.Context, slot *models.Slot) (*models.Slot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextSlotID++
+	stored := *slot
+	stored.ID = r.nextSlotID
+	r.fill(&stored)
+	r.slots[stored.ID] = &stored
+	created := stored
+	return &created, nil
+}
+
+func (r *fakeLineupRepository) UpdateSlot(_ context.Context, slot *models.Slot) (*models.Slot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *slot
+	r.fill(&stored)
+	r.slots[stored.ID] = &stored
+	updated := stored
+	return &updated, nil
+}
+
+// fill заполняет исполнителя и название сцены выступления, как это делает выборка из базы; вызывается под r.mu
+func (r *fakeLineupRepository) fill(slot *models.Slot) {
+	slot.Artist = r.artists[slot.ArtistID]
+	for _, stage := range r.festivals.festivals[slot.FestivalID].Stages {
+		if stage.ID == slot.StageID {
+			slot.StageName = stage.Name
+		}
+	}
+	slot.UpdatedAt = time.Now().UTC()
+}
+
+func (r *fakeLineupRepository) GetSlot(_ context.Context, festivalID, slotID int) (*models.Slot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	slot, ok := r.slots[slotID]
+	if !ok || slot.FestivalID != festivalID {
+		return nil, nil
+	}
+	found := *slot
+	return &found, nil
+}
+
+func (r *fakeLineupRepository) ListSlots(_ context.Context, festivalID int, filter repository.SlotFilter) ([]*models.Slot, error) {
+	return r.list(func(slot *models.Slot) bool {
+		return slot.FestivalID == festivalID &&
+			(filter.StageID == nil || slot.StageID == *filter.StageID) &&
+			(filter.ArtistID == nil || slot.ArtistID == *filter.ArtistID) &&
+			(filter.From == nil || slot.EndsAt.After(*filter.From)) &&
+			(filter.To == nil || slot.StartsAt.Before(*filter.To))
+	}), nil
+}
+
+func (r *fakeLineupRepository) FindOverlaps(_ context.Context, stageID int, startsAt, endsAt time.Time, excludeSlotID int) ([]*models.Slot, error) {
+	return r.list(func(slot *models.Slot) bool {
+		return slot.StageID == stageID && slot.ID != excludeSlotID &&
+			slot.StartsAt.Before(endsAt) && slot.EndsAt.After(startsAt)
+	}), nil
+}
+
+func (r *fakeLineupRepository) AddSelection(_ context.Context, userID string, slotID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.selections[userID] == nil {
+		r.selections[userID] = make(map[int]bool)
+	}
+	r.selections[userID][slotID] = true
+	return nil
+}
+
+func (r *fakeLineupRepository) ListSelectedSlots(_ context.Context, festivalID int, userID string) ([]*models.Slot, error) {
+	r.mu.Lock()
+	selected := r.selections[userID]
+	r.mu.Unlock()
+	return r.list(func(slot *models.Slot) bool {
+		return slot.FestivalID == festivalID && selected[slot.ID]
+	}), nil
+}
+
+// list возвращает выступления, подходящие под match, в порядке начала
+func (r *fakeLineupRepository) list(match func(slot *models.Slot) bool) []*models.Slot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var slots []*models.Slot
+	for _, slot := range r.slots {
+		if match(slot) {
+			found := *slot
+			slots = append(slots, &found)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if !slots[i].StartsAt.Equal(slots[j].StartsAt) {
+			return slots[i].StartsAt.Before(slots[j].StartsAt)
+		}
+		return slots[i].ID < slots[j].ID
+	})
+	return slots
+}
+
+// fakeProductRepository хранит продукты в памяти и выбирает страницы так же, как ListPage в PostgreSQL:
+// по паре (поле сортировки, id) строго после курсора
+type fakeProductRepository struct {
+	repository.ProductRepository
+
+	products []*models.Product
+	pages    []repository.ProductListOptions // Параметры каждого вызова ListPage
+}
+
+func (r *fakeProductRepository) ListPage(_ context.Context, opts repository.ProductListOptions) ([]*models.Product, error) {
+	r.pages = append(r.pages, opts)
+
+	sorted := append([]*models.Product(nil), r.products...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return compareProducts(sorted[i], sorted[j], opts.SortBy, opts.Desc) < 0
+	})
+
+	page := make([]*models.Product, 0)
+	for _, product := range sorted {
+		if opts.After != nil && compareToCursor(product, *opts.After, opts.SortBy, opts.Desc) <= 0 {
+			continue
+		}
+		if len(page) == opts.Limit {
+			break
+		}
+		page = append(page, product)
+	}
+	return page, nil
+}
+
+func (r *fakeProductRepository) Count(_ context.Context, _ repository.ProductFilter) (int64, error) {
+	return int64(len(r.products)), nil
+}
+
+// compareProducts сравнивает продукты по полю sortBy, при равенстве - по id
+func compareProducts(a, b *models.Product, sortBy string, desc bool) int {
+	return compareToCursor(a, repository.ProductCursor{Value: repository.ProductSortValue(b, sortBy), ID: b.ID}, sortBy, desc)
+}
+
+// compareToCursor сравнивает продукт с курсором в порядке выборки: отрицательное значение - продукт идет раньше
+func compareToCursor(product *models.Product, cursor repository.ProductCursor, sortBy string, desc bool) int {
+	value := repository.ProductSortValue(product, sortBy)
+	result := 0
+	switch sortBy {
+	case repository.ProductSortPrice:
+		a, _ := strconv.ParseFloat(value, 64)
+		b, _ := strconv.ParseFloat(cursor.Value, 64)
+		result = compareOrdered(a, b)
+	case repository.ProductSortName:
+		result = strings.Compare(value, cursor.Value)
+	default:
+		a, _ := time.Parse(time.RFC3339Nano, value)
+		b, _ := time.Parse(time.RFC3339Nano, cursor.Value)
+		result = a.Compare(b)
+	}
+	if result == 0 {
+		result = compareOrdered(product.ID, cursor.ID)
+	}
+	if desc {
+		return -result
+	}
+	return result
+}
+
+// compareOrdered возвращает -1, 0 или 1
+func compareOrdered[T int | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
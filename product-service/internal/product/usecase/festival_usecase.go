@@ -0,0 +1,494 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+)
+
+var (
+	ErrFestivalNotFound        = errors.New("фестиваль не найден")
+	ErrVenueNotFound           = errors.New("площадка не найдена")
+	ErrStageNotFound           = errors.New("сцена не найдена")
+	ErrFestivalInUse           = errors.New("к фестивалю привязаны продукты")
+	ErrVenueInUse              = errors.New("на площадке запланированы фестивали")
+	ErrStageExists             = errors.New("у фестиваля уже есть сцена с таким названием")
+	ErrInvalidStatusTransition = errors.New("недопустимая смена статуса фестиваля")
+)
+
+// festivalStatusTransitions - допустимые переходы между статусами фестиваля.
+// Продажи можно приостановить (ON_SALE -> DRAFT) и возобновить после возврата билетов (SOLD_OUT -> ON_SALE);
+// прошедший фестиваль (FINISHED) больше не меняет статус.
+var festivalStatusTransitions = map[models.FestivalStatus][]models.FestivalStatus{
+	models.FestivalDraft:    {models.FestivalOnSale},
+	models.FestivalOnSale:   {models.FestivalDraft, models.FestivalSoldOut, models.FestivalFinished},
+	models.FestivalSoldOut:  {models.FestivalOnSale, models.FestivalFinished},
+	models.FestivalFinished: {},
+}
+
+// CreateVenueInput - данные новой площадки
+type CreateVenueInput struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	City     string `json:"city"`
+	Country  string `json:"country"`
+	Capacity int    `json:"capacity"`
+}
+
+// UpdateVenueInput - изменяемые поля площадки; nil - поле не меняется
+type UpdateVenueInput struct {
+	Name     *string `json:"name"`
+	Address  *string `json:"address"`
+	City     *string `json:"city"`
+	Country  *string `json:"country"`
+	Capacity *int    `json:"capacity"`
+}
+
+// StageInput - данные новой сцены
+type StageInput struct {
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+}
+
+// UpdateStageInput - изменяемые поля сцены; nil - поле не меняется
+type UpdateStageInput struct {
+	Name     *string `json:"name"`
+	Capacity *int    `json:"capacity"`
+}
+
+// CreateFestivalInput - данные нового фестиваля.
+// Даты передаются в формате ГГГГ-ММ-ДД; без часового пояса используется UTC, без статуса - DRAFT.
+type CreateFestivalInput struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	VenueID     int                   `json:"venue_id"`
+	StartDate   string                `json:"start_date"`
+	EndDate     string                `json:"end_date"`
+	Timezone    string                `json:"timezone"`
+	Status      models.FestivalStatus `json:"status"`
+	Stages      []StageInput          `json:"stages"`
+}
+
+// UpdateFestivalInput - изменяемые поля фестиваля; nil - поле не меняется
+type UpdateFestivalInput struct {
+	Name        *string                `json:"name"`
+	Description *string                `json:"description"`
+	VenueID     *int                   `json:"venue_id"`
+	StartDate   *string                `json:"start_date"`
+	EndDate     *string                `json:"end_date"`
+	Timezone    *string                `json:"timezone"`
+	Status      *models.FestivalStatus `json:"status"`
+}
+
+// FestivalUsecase определяет бизнес-логику площадок, фестивалей и сцен.
+type FestivalUsecase interface {
+	CreateVenue(ctx context.Context, input CreateVenueInput) (*models.Venue, error)
+	GetVenue(ctx context.Context, id int) (*models.Venue, error)
+	ListVenues(ctx context.Context) ([]*models.Venue, error)
+	UpdateVenue(ctx context.Context, id int, input UpdateVenueInput) (*models.Venue, error)
+	// DeleteVenue возвращает ErrVenueInUse, если на площадке есть фестивали
+	DeleteVenue(ctx context.Context, id int) error
+
+	CreateFestival(ctx context.Context, input CreateFestivalInput) (*models.Festival, error)
+	// GetFestival возвращает фестиваль с площадкой и сценами
+	GetFestival(ctx context.Context, id int) (*models.Festival, error)
+	ListFestivals(ctx context.Context, filter repository.FestivalFilter) ([]*models.Festival, error)
+	// UpdateFestival изменяет фестиваль; смена статуса проверяется по festivalStatusTransitions
+	UpdateFestival(ctx context.Context, id int, input UpdateFestivalInput) (*models.Festival, error)
+	// DeleteFestival возвращает ErrFestivalInUse, если к фестивалю привязаны продукты
+	DeleteFestival(ctx context.Context, id int) error
+
+	ListStages(ctx context.Context, festivalID int) ([]*models.Stage, error)
+	CreateStage(ctx context.Context, festivalID int, input StageInput) (*models.Stage, error)
+	UpdateStage(ctx context.Context, festivalID, stageID int, input UpdateStageInput) (*models.Stage, error)
+	DeleteStage(ctx context.Context, festivalID, stageID int) error
+}
+
+type festivalUsecase struct {
+	festivalRepo repository.FestivalRepository
+}
+
+// NewFestivalUsecase создает новый экземпляр festivalUsecase.
+func NewFestivalUsecase(festivalRepo repository.FestivalRepository) FestivalUsecase {
+	return &festivalUsecase{festivalRepo: festivalRepo}
+}
+
+// CreateVenue проверяет и сохраняет площадку.
+func (uc *festivalUsecase) CreateVenue(ctx context.Context, input CreateVenueInput) (*models.Venue, error) {
+	venue := &models.Venue{
+		Name:     strings.TrimSpace(input.Name),
+		Address:  strings.TrimSpace(input.Address),
+		City:     strings.TrimSpace(input.City),
+		Country:  strings.TrimSpace(input.Country),
+		Capacity: input.Capacity,
+	}
+	if err := validateVenue(venue); err != nil {
+		return nil, err
+	}
+	return uc.festivalRepo.CreateVenue(ctx, venue)
+}
+
+// GetVenue находит площадку по ID.
+func (uc *festivalUsecase) GetVenue(ctx context.Context, id int) (*models.Venue, error) {
+	venue, err := uc.festivalRepo.GetVenue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if venue == nil {
+		return nil, ErrVenueNotFound
+	}
+	return venue, nil
+}
+
+// ListVenues возвращает все площадки.
+func (uc *festivalUsecase) ListVenues(ctx context.Context) ([]*models.Venue, error) {
+	return uc.festivalRepo.ListVenues(ctx)
+}
+
+// UpdateVenue изменяет переданные поля площадки.
+func (uc *festivalUsecase) UpdateVenue(ctx context.Context, id int, input UpdateVenueInput) (*models.Venue, error) {
+	venue, err := uc.GetVenue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		venue.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Address != nil {
+		venue.Address = strings.TrimSpace(*input.Address)
+	}
+	if input.City != nil {
+		venue.City = strings.TrimSpace(*input.City)
+	}
+	if input.Country != nil {
+		venue.Country = strings.TrimSpace(*input.Country)
+	}
+	if input.Capacity != nil {
+		venue.Capacity = *input.Capacity
+	}
+	if err := validateVenue(venue); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.festivalRepo.UpdateVenue(ctx, venue)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVenueNotFound
+	}
+	return updated, err
+}
+
+// DeleteVenue удаляет площадку без фестивалей.
+func (uc *festivalUsecase) DeleteVenue(ctx context.Context, id int) error {
+	err := uc.festivalRepo.DeleteVenue(ctx, id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrVenueNotFound
+	case errors.Is(err, repository.ErrReferenced):
+		return ErrVenueInUse
+	}
+	return err
+}
+
+// CreateFestival проверяет даты, часовой пояс, площадку и сцены и сохраняет фестиваль.
+func (uc *festivalUsecase) CreateFestival(ctx context.Context, input CreateFestivalInput) (*models.Festival, error) {
+	festival := &models.Festival{
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		VenueID:     input.VenueID,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+		Timezone:    input.Timezone,
+		Status:      input.Status,
+	}
+	if festival.Timezone == "" {
+		festival.Timezone = "UTC"
+	}
+	if festival.Status == "" {
+		festival.Status = models.FestivalDraft
+	}
+	if _, ok := festivalStatusTransitions[festival.Status]; !ok {
+		return nil, fmt.Errorf("%w: статус должен быть одним из DRAFT, ON_SALE, SOLD_OUT, FINISHED", ErrInvalidInput)
+	}
+	if err := validateFestival(festival); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(input.Stages))
+	for _, stageInput := range input.Stages {
+		stage := &models.Stage{Name: strings.TrimSpace(stageInput.Name), Capacity: stageInput.Capacity}
+		if err := validateStage(stage); err != nil {
+			return nil, err
+		}
+		if names[strings.ToLower(stage.Name)] {
+			return nil, ErrStageExists
+		}
+		names[strings.ToLower(stage.Name)] = true
+		festival.Stages = append(festival.Stages, stage)
+	}
+
+	if err := uc.checkVenue(ctx, festival.VenueID); err != nil {
+		return nil, err
+	}
+	created, err := uc.festivalRepo.Create(ctx, festival)
+	switch {
+	case errors.Is(err, repository.ErrReferenced):
+		return nil, venueNotFoundInput(festival.VenueID) // Площадку удалили после проверки
+	case errors.Is(err, repository.ErrDuplicate):
+		return nil, ErrStageExists
+	}
+	return created, err
+}
+
+// GetFestival находит фестиваль по ID.
+func (uc *festivalUsecase) GetFestival(ctx context.Context, id int) (*models.Festival, error) {
+	festival, err := uc.festivalRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if festival == nil {
+		return nil, ErrFestivalNotFound
+	}
+	return festival, nil
+}
+
+// ListFestivals возвращает фестивали по фильтру.
+func (uc *festivalUsecase) ListFestivals(ctx context.Context, filter repository.FestivalFilter) ([]*models.Festival, error) {
+	if filter.Status != "" {
+		if _, ok := festivalStatusTransitions[filter.Status]; !ok {
+			return nil, fmt.Errorf("%w: статус должен быть одним из DRAFT, ON_SALE, SOLD_OUT, FINISHED", ErrInvalidInput)
+		}
+	}
+	if filter.From != "" {
+		if _, err := time.Parse(models.FestivalDateLayout, filter.From); err != nil {
+			return nil, fmt.Errorf("%w: дата from должна иметь формат ГГГГ-ММ-ДД", ErrInvalidInput)
+		}
+	}
+	filter.City = strings.TrimSpace(filter.City)
+	return uc.festivalRepo.List(ctx, filter)
+}
+
+// UpdateFestival изменяет переданные поля фестиваля.
+func (uc *festivalUsecase) UpdateFestival(ctx context.Context, id int, input UpdateFestivalInput) (*models.Festival, error) {
+	festival, err := uc.GetFestival(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		festival.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		festival.Description = *input.Description
+	}
+	if input.StartDate != nil {
+		festival.StartDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		festival.EndDate = *input.EndDate
+	}
+	if input.Timezone != nil {
+		festival.Timezone = *input.Timezone
+	}
+	if input.Status != nil && *input.Status != festival.Status {
+		if _, ok := festivalStatusTransitions[*input.Status]; !ok {
+			return nil, fmt.Errorf("%w: статус должен быть одним из DRAFT, ON_SALE, SOLD_OUT, FINISHED", ErrInvalidInput)
+		}
+		if !canChangeFestivalStatus(festival.Status, *input.Status) {
+			return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, festival.Status, *input.Status)
+		}
+		festival.Status = *input.Status
+	}
+	if err := validateFestival(festival); err != nil {
+		return nil, err
+	}
+	if input.VenueID != nil && *input.VenueID != festival.VenueID {
+		if err := uc.checkVenue(ctx, *input.VenueID); err != nil {
+			return nil, err
+		}
+		festival.VenueID = *input.VenueID
+	}
+
+	updated, err := uc.festivalRepo.Update(ctx, festival)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrFestivalNotFound
+	case errors.Is(err, repository.ErrReferenced):
+		return nil, venueNotFoundInput(festival.VenueID)
+	}
+	return updated, err
+}
+
+// DeleteFestival удаляет фестиваль вместе со сценами.
+func (uc *festivalUsecase) DeleteFestival(ctx context.Context, id int) error {
+	err := uc.festivalRepo.Delete(ctx, id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrFestivalNotFound
+	case errors.Is(err, repository.ErrReferenced):
+		return ErrFestivalInUse
+	}
+	return err
+}
+
+// ListStages возвращает сцены фестиваля.
+func (uc *festivalUsecase) ListStages(ctx context.Context, festivalID int) ([]*models.Stage, error) {
+	if err := uc.requireFestival(ctx, festivalID); err != nil {
+		return nil, err
+	}
+	return uc.festivalRepo.ListStages(ctx, festivalID)
+}
+
+// CreateStage добавляет сцену фестивалю.
+func (uc *festivalUsecase) CreateStage(ctx context.Context, festivalID int, input StageInput) (*models.Stage, error) {
+	stage := &models.Stage{FestivalID: festivalID, Name: strings.TrimSpace(input.Name), Capacity: input.Capacity}
+	if err := validateStage(stage); err != nil {
+		return nil, err
+	}
+	if err := uc.requireFestival(ctx, festivalID); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.festivalRepo.CreateStage(ctx, stage)
+	switch {
+	case errors.Is(err, repository.ErrDuplicate):
+		return nil, ErrStageExists
+	case errors.Is(err, repository.ErrReferenced):
+		return nil, ErrFestivalNotFound
+	}
+	return created, err
+}
+
+// UpdateStage изменяет название или вместимость сцены.
+func (uc *festivalUsecase) UpdateStage(ctx context.Context, festivalID, stageID int, input UpdateStageInput) (*models.Stage, error) {
+	stage, err := uc.festivalRepo.GetStage(ctx, festivalID, stageID)
+	if err != nil {
+		return nil, err
+	}
+	if stage == nil {
+		return nil, ErrStageNotFound
+	}
+	if input.Name != nil {
+		stage.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Capacity != nil {
+		stage.Capacity = *input.Capacity
+	}
+	if err := validateStage(stage); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.festivalRepo.UpdateStage(ctx, stage)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrStageNotFound
+	case errors.Is(err, repository.ErrDuplicate):
+		return nil, ErrStageExists
+	}
+	return updated, err
+}
+
+// DeleteStage удаляет сцену фестиваля без выступлений.
+func (uc *festivalUsecase) DeleteStage(ctx context.Context, festivalID, stageID int) error {
+	err := uc.festivalRepo.DeleteStage(ctx, festivalID, stageID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrStageNotFound
+	case errors.Is(err, repository.ErrReferenced):
+		return ErrStageInUse
+	}
+	return err
+}
+
+// requireFestival возвращает ErrFestivalNotFound, если фестиваля нет.
+func (uc *festivalUsecase) requireFestival(ctx context.Context, festivalID int) error {
+	exists, err := uc.festivalRepo.Exists(ctx, festivalID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrFestivalNotFound
+	}
+	return nil
+}
+
+// checkVenue проверяет площадку, указанную в данных фестиваля.
+// Отсутствующая площадка - ошибка входных данных, а не отсутствие запрошенного ресурса.
+func (uc *festivalUsecase) checkVenue(ctx context.Context, venueID int) error {
+	venue, err := uc.festivalRepo.GetVenue(ctx, venueID)
+	if err != nil {
+		return err
+	}
+	if venue == nil {
+		return venueNotFoundInput(venueID)
+	}
+	return nil
+}
+
+// venueNotFoundInput возвращает ошибку входных данных о несуществующей площадке
+func venueNotFoundInput(venueID int) error {
+	return fmt.Errorf("%w: площадка %d не найдена", ErrInvalidInput, venueID)
+}
+
+// canChangeFestivalStatus сообщает, допустим ли переход из статуса from в статус to.
+func canChangeFestivalStatus(from, to models.FestivalStatus) bool {
+	for _, allowed := range festivalStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// validateVenue проверяет обязательные поля площадки.
+func validateVenue(venue *models.Venue) error {
+	if venue.Name == "" || venue.City == "" {
+		return fmt.Errorf("%w: название и город площадки обязательны", ErrInvalidInput)
+	}
+	if venue.Capacity < 0 {
+		return fmt.Errorf("%w: вместимость не может быть отрицательной", ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateFestival проверяет название, даты и часовой пояс фестиваля.
+func validateFestival(festival *models.Festival) error {
+	if festival.Name == "" {
+		return fmt.Errorf("%w: название фестиваля обязательно", ErrInvalidInput)
+	}
+	if festival.VenueID <= 0 {
+		return fmt.Errorf("%w: площадка фестиваля обязательна", ErrInvalidInput)
+	}
+	startDate, err := time.Parse(models.FestivalDateLayout, festival.StartDate)
+	if err != nil {
+		return fmt.Errorf("%w: дата начала должна иметь формат ГГГГ-ММ-ДД", ErrInvalidInput)
+	}
+	endDate, err := time.Parse(models.FestivalDateLayout, festival.EndDate)
+	if err != nil {
+		return fmt.Errorf("%w: дата окончания должна иметь формат ГГГГ-ММ-ДД", ErrInvalidInput)
+	}
+	if endDate.Before(startDate) {
+		return fmt.Errorf("%w: дата окончания раньше даты начала", ErrInvalidInput)
+	}
+	// "Local" зависит от настроек сервера, поэтому принимаются только явные зоны IANA и UTC
+	if _, err := time.LoadLocation(festival.Timezone); err != nil || festival.Timezone == "" || festival.Timezone == "Local" {
+		return fmt.Errorf("%w: неизвестный часовой пояс %q", ErrInvalidInput, festival.Timezone)
+	}
+	return nil
+}
+
+// validateStage проверяет название и вместимость сцены.
+func validateStage(stage *models.Stage) error {
+	if stage.Name == "" {
+		return fmt.Errorf("%w: название сцены обязательно", ErrInvalidInput)
+	}
+	if stage.Capacity < 0 {
+		return fmt.Errorf("%w: вместимость сцены не может быть отрицательной", ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+)
+
+// icsTimeLayout - формат даты и времени iCalendar в UTC (RFC 5545, 3.3.5)
+const icsTimeLayout = "20060102T150405Z"
+
+// icsMaxLineOctets - наибольшая длина строки iCalendar без перевода строки; длинные строки переносятся
+const icsMaxLineOctets = 75
+
+// icsTextEscaper экранирует значения типа TEXT (RFC 5545, 3.3.11)
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// renderICS строит календарь iCalendar, в котором каждое выступление - отдельное событие.
+// Время событий записывается в UTC, поэтому календарю не нужен блок VTIMEZONE.
+func renderICS(calendarName string, festival *models.Festival, slots []*models.Slot, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeLine := func(name, value string) {
+		writeICSLine(&buf, name+":"+value)
+	}
+
+	writeLine("BEGIN", "VCALENDAR")
+	writeLine("VERSION", "2.0")
+	writeLine("PRODID", "-//go-microservice-project//product-service//RU")
+	writeLine("CALSCALE", "GREGORIAN")
+	writeLine("METHOD", "PUBLISH")
+	writeLine("X-WR-CALNAME", icsText(calendarName))
+	writeLine("X-WR-TIMEZONE", festival.Timezone)
+
+	location := ""
+	if festival.Venue != nil {
+		location = festival.Venue.Name
+		if festival.Venue.City != "" {
+			location += ", " + festival.Venue.City
+		}
+	}
+	for _, slot := range slots {
+		summary := fmt.Sprintf("выступление %d", slot.ID)
+		description := festival.Name
+		if slot.Artist != nil {
+			summary = slot.Artist.Name
+			if slot.Artist.Genre != "" {
+				description += "\n" + slot.Artist.Genre
+			}
+		}
+		slotLocation := slot.StageName
+		if location != "" {
+			slotLocation += ", " + location
+		}
+
+		writeLine("BEGIN", "VEVENT")
+		writeLine("UID", fmt.Sprintf("slot-%d@product-service", slot.ID))
+		writeLine("DTSTAMP", now.UTC().Format(icsTimeLayout))
+		writeLine("DTSTART", slot.StartsAt.UTC().Format(icsTimeLayout))
+		writeLine("DTEND", slot.EndsAt.UTC().Format(icsTimeLayout))
+		writeLine("LAST-MODIFIED", slot.UpdatedAt.UTC().Format(icsTimeLayout))
+		writeLine("SUMMARY", icsText(summary))
+		writeLine("LOCATION", icsText(slotLocation))
+		writeLine("DESCRIPTION", icsText(description))
+		writeLine("END", "VEVENT")
+	}
+	writeLine("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// icsText экранирует текстовое значение свойства.
+func icsText(value string) string {
+	return icsTextEscaper.Replace(value)
+}
+
+// writeICSLine записывает строку с CRLF, перенося ее по icsMaxLineOctets байт (RFC 5545, 3.1).
+// Строка продолжения начинается с пробела; многобайтовые символы UTF-8 не разрываются.
+func writeICSLine(buf *bytes.Buffer, line string) {
+	limit := icsMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icsMaxLineOctets - 1 // Пробел в начале строки продолжения входит в ее длину
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
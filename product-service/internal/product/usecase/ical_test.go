@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+)
+
+// unfoldICS разбирает вывод writeICSLine на физические строки и собирает из них исходную строку (RFC 5545, 3.1)
+func unfoldICS(t *testing.T, output string) (string, []string) {
+	t.Helper()
+	if !strings.HasSuffix(output, "\r\n") {
+		t.Fatalf("строка не заканчивается CRLF: %q", output)
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n")
+	unfolded := lines[0]
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, " ") {
+			t.Fatalf("строка продолжения не начинается с пробела: %q", line)
+		}
+		unfolded += line[1:]
+	}
+	return unfolded, lines
+}
+
+func TestWriteICSLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantLines int
+	}{
+		{"короткая строка", "VERSION:2.0", 1},
+		{"ровно 75 октетов", "SUMMARY:" + strings.Repeat("a", 67), 1},
+		{"76 октетов", "SUMMARY:" + strings.Repeat("a", 68), 2},
+		{"ровно две строки", "SUMMARY:" + strings.Repeat("a", 67+74), 2},
+		{"три строки", "SUMMARY:" + strings.Repeat("a", 67+74+1), 3},
+		// "я" - 2 байта: граница 75 октетов приходится на середину символа
+		{"кириллица на границе", "SUMMARY:" + strings.Repeat("я", 34), 2},
+		{"кириллица ровно до границы", "SUMMARY:a" + strings.Repeat("я", 33), 1},
+		// "€" - 3 байта, "🎸" - 4 байта
+		{"трехбайтовые символы", "SUMMARY:" + strings.Repeat("€", 40), 2},
+		{"четырехбайтовые символы", "SUMMARY:" + strings.Repeat("🎸", 60), 4},
+		{"смешанный текст", "DESCRIPTION:" + strings.Repeat("Рок-н-ролл 🎸 на сцене «Главная», ", 5), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeICSLine(&buf, tt.line)
+
+			unfolded, lines := unfoldICS(t, buf.String())
+			if unfolded != tt.line {
+				t.Fatalf("после сборки строка изменилась:\n%q\n%q", unfolded, tt.line)
+			}
+			if tt.wantLines != 0 && len(lines) != tt.wantLines {
+				t.Fatalf("строк %d, ожидалось %d: %q", len(lines), tt.wantLines, lines)
+			}
+			for _, line := range lines {
+				if len(line) > icsMaxLineOctets {
+					t.Fatalf("строка длиннее %d октетов (%d): %q", icsMaxLineOctets, len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Fatalf("перенос разорвал символ UTF-8: %q", line)
+				}
+			}
+			// Перенос не делает строки короче, чем нужно: следующий символ уже не поместился бы
+			offset := 0
+			for i, line := range lines[:len(lines)-1] {
+				offset += len(line)
+				if i > 0 {
+					offset-- // Пробел продолжения не входит в исходную строку
+				}
+				next, _ := utf8.DecodeRuneInString(tt.line[offset:])
+				if len(line)+utf8.RuneLen(next) <= icsMaxLineOctets {
+					t.Fatalf("строка %q перенесена раньше, чем нужно", line)
+				}
+			}
+		})
+	}
+}
+
+func TestICSText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Кино", "Кино"},
+		{"Главная; вход А", `Главная\; вход А`},
+		{"Москва, Лужники", `Москва\, Лужники`},
+		{`C:\музыка`, `C:\\музыка`},
+		{"рок\nпанк", `рок\nпанк`},
+		{"рок\r\nпанк", `рок\nпанк`},
+		{"рок\rпанк", `рок\nпанк`},
+		{`\;`, `\\\;`},
+		{"a;b,c\\d\ne", `a\;b\,c\\d\ne`},
+	}
+	for _, tt := range tests {
+		if got := icsText(tt.value); got != tt.want {
+			t.Fatalf("icsText(%q) = %q, ожидалось %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRenderICS(t *testing.T) {
+	festival := &models.Festival{
+		Name:     "Летний фестиваль",
+		Timezone: "Europe/Moscow",
+		Venue:    &models.Venue{Name: "Парк", City: "Москва"},
+	}
+	startsAt := time.Date(2026, 7, 10, 17, 0, 0, 0, time.UTC)
+	slots := []*models.Slot{{
+		ID:        7,
+		StageName: "Главная; вход А",
+		Artist:    &models.Artist{Name: "Кино, Сплин", Genre: "рок"},
+		StartsAt:  startsAt,
+		EndsAt:    startsAt.Add(time.Hour),
+		UpdatedAt: startsAt,
+	}}
+
+	output := string(renderICS("Летний фестиваль: мое расписание", festival, slots, startsAt))
+	if strings.Contains(strings.ReplaceAll(output, "\r\n", ""), "\n") {
+		t.Fatalf("строки календаря должны заканчиваться CRLF")
+	}
+
+	var unfolded []string
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		unfolded = append(unfolded, line)
+	}
+	for _, want := range []string{
+		"BEGIN:VCALENDAR",
+		`X-WR-CALNAME:Летний фестиваль: мое расписание`,
+		"BEGIN:VEVENT",
+		"UID:slot-7@product-service",
+		"DTSTART:20260710T170000Z",
+		"DTEND:20260710T180000Z",
+		`SUMMARY:Кино\, Сплин`,
+		`LOCATION:Главная\; вход А\, Парк\, Москва`,
+		`DESCRIPTION:Летний фестиваль\nрок`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+	} {
+		if !slices.Contains(unfolded, want) {
+			t.Fatalf("в календаре нет строки %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,514 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+)
+
+var (
+	ErrArtistNotFound = errors.New("исполнитель не найден")
+	ErrSlotNotFound   = errors.New("выступление не найдено")
+	ErrArtistInUse    = errors.New("у исполнителя есть выступления")
+	ErrStageInUse     = errors.New("на сцене запланированы выступления")
+	ErrSlotClash      = repository.ErrSlotOverlap
+)
+
+// FestivalDayStart - время, с которого начинается день фестиваля в его часовом поясе.
+// Выступления после полуночи и до этого времени относятся к предыдущему дню, как в программках фестивалей.
+const FestivalDayStart = 6 * time.Hour
+
+// MaxSlotDuration - наибольшая длительность одного выступления
+const MaxSlotDuration = 12 * time.Hour
+
+// CreateArtistInput - данные нового исполнителя
+type CreateArtistInput struct {
+	Name        string `json:"name"`
+	Genre       string `json:"genre"`
+	Country     string `json:"country"`
+	Description string `json:"description"`
+}
+
+// UpdateArtistInput - изменяемые поля исполнителя; nil - поле не меняется
+type UpdateArtistInput struct {
+	Name        *string `json:"name"`
+	Genre       *string `json:"genre"`
+	Country     *string `json:"country"`
+	Description *string `json:"description"`
+}
+
+// SlotInput - данные нового выступления. Время передается в RFC 3339 с часовым поясом.
+type SlotInput struct {
+	StageID  int       `json:"stage_id"`
+	ArtistID int       `json:"artist_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// UpdateSlotInput - изменяемые поля выступления; nil - поле не меняется
+type UpdateSlotInput struct {
+	StageID  *int       `json:"stage_id"`
+	ArtistID *int       `json:"artist_id"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// SlotClash - два выступления личного расписания, которые идут одновременно на разных сценах
+type SlotClash struct {
+	SlotIDs [2]int `json:"slot_ids"`
+}
+
+// MySchedule - личное расписание пользователя на фестивале
+type MySchedule struct {
+	Slots   []*models.Slot `json:"slots"`   // В порядке начала
+	Clashes []SlotClash    `json:"clashes"` // Пересечения выбранных выступлений
+}
+
+// LineupUsecase определяет бизнес-логику исполнителей, выступлений и расписания фестиваля.
+type LineupUsecase interface {
+	CreateArtist(ctx context.Context, input CreateArtistInput) (*models.Artist, error)
+	GetArtist(ctx context.Context, id int) (*models.Artist, error)
+	ListArtists(ctx context.Context, query string) ([]*models.Artist, error)
+	UpdateArtist(ctx context.Context, id int, input UpdateArtistInput) (*models.Artist, error)
+	// DeleteArtist возвращает ErrArtistInUse, если у исполнителя есть выступления
+	DeleteArtist(ctx context.Context, id int) error
+
+	ListSlots(ctx context.Context, festivalID int, filter repository.SlotFilter) ([]*models.Slot, error)
+	GetSlot(ctx context.Context, festivalID, slotID int) (*models.Slot, error)
+	// CreateSlot возвращает ErrSlotClash с перечнем пересечений, если сцена в это время занята
+	CreateSlot(ctx context.Context, festivalID int, input SlotInput) (*models.Slot, error)
+	UpdateSlot(ctx context.Context, festivalID, slotID int, input UpdateSlotInput) (*models.Slot, error)
+	DeleteSlot(ctx context.Context, festivalID, slotID int) error
+
+	// GetSchedule возвращает сетку "день x сцена"; day (ГГГГ-ММ-ДД) ограничивает сетку одним днем
+	GetSchedule(ctx context.Context, festivalID int, day string) (*models.Schedule, error)
+	// ExportICS возвращает расписание фестиваля в формате iCalendar.
+	// С непустым userID в календарь попадают только выступления из личного расписания пользователя.
+	ExportICS(ctx context.Context, festivalID int, userID string) ([]byte, error)
+
+	GetMySchedule(ctx context.Context, festivalID int, userID string) (*MySchedule, error)
+	AddToMySchedule(ctx context.Context, festivalID int, userID string, slotID int) error
+	RemoveFromMySchedule(ctx context.Context, festivalID int, userID string, slotID int) error
+}
+
+type lineupUsecase struct {
+	lineupRepo   repository.LineupRepository
+	festivalRepo repository.FestivalRepository
+}
+
+// NewLineupUsecase создает новый экземпляр lineupUsecase.
+func NewLineupUsecase(lineupRepo repository.LineupRepository, festivalRepo repository.FestivalRepository) LineupUsecase {
+	return &lineupUsecase{lineupRepo: lineupRepo, festivalRepo: festivalRepo}
+}
+
+// CreateArtist проверяет и сохраняет исполнителя.
+func (uc *lineupUsecase) CreateArtist(ctx context.Context, input CreateArtistInput) (*models.Artist, error) {
+	artist := &models.Artist{
+		Name:        strings.TrimSpace(input.Name),
+		Genre:       strings.TrimSpace(input.Genre),
+		Country:     strings.TrimSpace(input.Country),
+		Description: input.Description,
+	}
+	if artist.Name == "" {
+		return nil, fmt.Errorf("%w: имя исполнителя обязательно", ErrInvalidInput)
+	}
+	return uc.lineupRepo.CreateArtist(ctx, artist)
+}
+
+// GetArtist находит исполнителя по ID.
+func (uc *lineupUsecase) GetArtist(ctx context.Context, id int) (*models.Artist, error) {
+	artist, err := uc.lineupRepo.GetArtist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if artist == nil {
+		return nil, ErrArtistNotFound
+	}
+	return artist, nil
+}
+
+// ListArtists возвращает исполнителей, имя которых содержит query.
+func (uc *lineupUsecase) ListArtists(ctx context.Context, query string) ([]*models.Artist, error) {
+	return uc.lineupRepo.ListArtists(ctx, strings.TrimSpace(query))
+}
+
+// UpdateArtist изменяет переданные поля исполнителя.
+func (uc *lineupUsecase) UpdateArtist(ctx context.Context, id int, input UpdateArtistInput) (*models.Artist, error) {
+	artist, err := uc.GetArtist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		artist.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Genre != nil {
+		artist.Genre = strings.TrimSpace(*input.Genre)
+	}
+	if input.Country != nil {
+		artist.Country = strings.TrimSpace(*input.Country)
+	}
+	if input.Description != nil {
+		artist.Description = *input.Description
+	}
+	if artist.Name == "" {
+		return nil, fmt.Errorf("%w: имя исполнителя обязательно", ErrInvalidInput)
+	}
+
+	updated, err := uc.lineupRepo.UpdateArtist(ctx, artist)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrArtistNotFound
+	}
+	return updated, err
+}
+
+// DeleteArtist удаляет исполнителя без выступлений.
+func (uc *lineupUsecase) DeleteArtist(ctx context.Context, id int) error {
+	err := uc.lineupRepo.DeleteArtist(ctx, id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return ErrArtistNotFound
+	case errors.Is(err, repository.ErrReferenced):
+		return ErrArtistInUse
+	}
+	return err
+}
+
+// ListSlots возвращает выступления фестиваля.
+func (uc *lineupUsecase) ListSlots(ctx context.Context, festivalID int, filter repository.SlotFilter) ([]*models.Slot, error) {
+	if _, err := uc.getFestival(ctx, festivalID); err != nil {
+		return nil, err
+	}
+	return uc.lineupRepo.ListSlots(ctx, festivalID, filter)
+}
+
+// GetSlot находит выступление фестиваля.
+func (uc *lineupUsecase) GetSlot(ctx context.Context, festivalID, slotID int) (*models.Slot, error) {
+	slot, err := uc.lineupRepo.GetSlot(ctx, festivalID, slotID)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, ErrSlotNotFound
+	}
+	return slot, nil
+}
+
+// CreateSlot проверяет сцену, исполнителя, время и пересечения и сохраняет выступление.
+func (uc *lineupUsecase) CreateSlot(ctx context.Context, festivalID int, input SlotInput) (*models.Slot, error) {
+	festival, err := uc.getFestival(ctx, festivalID)
+	if err != nil {
+		return nil, err
+	}
+	slot := &models.Slot{
+		FestivalID: festivalID,
+		StageID:    input.StageID,
+		ArtistID:   input.ArtistID,
+		StartsAt:   input.StartsAt.UTC(),
+		EndsAt:     input.EndsAt.UTC(),
+	}
+	if err := uc.validateSlot(ctx, festival, slot); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.lineupRepo.CreateSlot(ctx, slot)
+	if err != nil {
+		return nil, uc.mapSlotWriteError(ctx, festival, slot, err)
+	}
+	return created, nil
+}
+
+// UpdateSlot изменяет переданные поля выступления с теми же проверками, что и при создании.
+func (uc *lineupUsecase) UpdateSlot(ctx context.Context, festivalID, slotID int, input UpdateSlotInput) (*models.Slot, error) {
+	festival, err := uc.getFestival(ctx, festivalID)
+	if err != nil {
+		return nil, err
+	}
+	slot, err := uc.GetSlot(ctx, festivalID, slotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.StageID != nil {
+		slot.StageID = *input.StageID
+	}
+	if input.ArtistID != nil {
+		slot.ArtistID = *input.ArtistID
+	}
+	if input.StartsAt != nil {
+		slot.StartsAt = input.StartsAt.UTC()
+	}
+	if input.EndsAt != nil {
+		slot.EndsAt = input.EndsAt.UTC()
+	}
+	if err := uc.validateSlot(ctx, festival, slot); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.lineupRepo.UpdateSlot(ctx, slot)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSlotNotFound
+		}
+		return nil, uc.mapSlotWriteError(ctx, festival, slot, err)
+	}
+	return updated, nil
+}
+
+// DeleteSlot удаляет выступление фестиваля.
+func (uc *lineupUsecase) DeleteSlot(ctx context.Context, festivalID, slotID int) error {
+	err := uc.lineupRepo.DeleteSlot(ctx, festivalID, slotID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSlotNotFound
+	}
+	return err
+}
+
+// GetSchedule раскладывает выступления по дням фестиваля и сценам.
+// В сетку входят все дни и все сцены, в том числе без выступлений.
+func (uc *lineupUsecase) GetSchedule(ctx context.Context, festivalID int, day string) (*models.Schedule, error) {
+	festival, err := uc.getFestival(ctx, festivalID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(festival.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	days := festivalDays(festival)
+	if day != "" {
+		if !containsString(days, day) {
+			return nil, fmt.Errorf("%w: day должен быть днем фестиваля с %s по %s", ErrInvalidInput,
+				festival.StartDate, festival.EndDate)
+		}
+		days = []string{day}
+	}
+	from := festivalDayStart(days[0], location)
+	to := festivalDayStart(days[len(days)-1], location).AddDate(0, 0, 1)
+
+	// Выступление попадает в день, в который оно начинается
+	slots, err := uc.lineupRepo.ListSlots(ctx, festivalID, repository.SlotFilter{From: &from, To: &to})
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &models.Schedule{
+		FestivalID:   festival.ID,
+		FestivalName: festival.Name,
+		Timezone:     festival.Timezone,
+		Days:         make([]*models.ScheduleDay, len(days)),
+	}
+	cells := make(map[string]map[int]*models.ScheduleStage, len(days))
+	for i, date := range days {
+		scheduleDay := &models.ScheduleDay{Date: date, Stages: make([]*models.ScheduleStage, len(festival.Stages))}
+		cells[date] = make(map[int]*models.ScheduleStage, len(festival.Stages))
+		for j, stage := range festival.Stages {
+			cell := &models.ScheduleStage{StageID: stage.ID, StageName: stage.Name, Slots: make([]*models.Slot, 0)}
+			scheduleDay.Stages[j] = cell
+			cells[date][stage.ID] = cell
+		}
+		schedule.Days[i] = scheduleDay
+	}
+	for _, slot := range slots {
+		if cell, ok := cells[festivalDay(slot.StartsAt, location)][slot.StageID]; ok {
+			cell.Slots = append(cell.Slots, slot)
+		}
+	}
+	return schedule, nil
+}
+
+// ExportICS выбирает выступления (все или из личного расписания) и строит календарь.
+func (uc *lineupUsecase) ExportICS(ctx context.Context, festivalID int, userID string) ([]byte, error) {
+	festival, err := uc.getFestival(ctx, festivalID)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []*models.Slot
+	calendarName := festival.Name
+	if userID != "" {
+		slots, err = uc.lineupRepo.ListSelectedSlots(ctx, festivalID, userID)
+		calendarName += ": мое расписание"
+	} else {
+		slots, err = uc.lineupRepo.ListSlots(ctx, festivalID, repository.SlotFilter{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return renderICS(calendarName, festival, slots, time.Now().UTC()), nil
+}
+
+// GetMySchedule возвращает выбранные пользователем выступления и их пересечения.
+func (uc *lineupUsecase) GetMySchedule(ctx context.Context, festivalID int, userID string) (*MySchedule, error) {
+	if _, err := uc.getFestival(ctx, festivalID); err != nil {
+		return nil, err
+	}
+	slots, err := uc.lineupRepo.ListSelectedSlots(ctx, festivalID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Выступления отсортированы по началу, поэтому достаточно сравнить каждое с последующими, пока те начинаются раньше его конца
+	schedule := &MySchedule{Slots: slots, Clashes: make([]SlotClash, 0)}
+	for i, slot := range slots {
+		for _, next := range slots[i+1:] {
+			if !next.StartsAt.Before(slot.EndsAt) {
+				break
+			}
+			schedule.Clashes = append(schedule.Clashes, SlotClash{SlotIDs: [2]int{slot.ID, next.ID}})
+		}
+	}
+	return schedule, nil
+}
+
+// AddToMySchedule добавляет выступление фестиваля в личное расписание.
+func (uc *lineupUsecase) AddToMySchedule(ctx context.Context, festivalID int, userID string, slotID int) error {
+	if _, err := uc.GetSlot(ctx, festivalID, slotID); err != nil {
+		return err
+	}
+	err := uc.lineupRepo.AddSelection(ctx, userID, slotID)
+	if errors.Is(err, repository.ErrReferenced) {
+		return ErrSlotNotFound // Выступление удалили после проверки
+	}
+	return err
+}
+
+// RemoveFromMySchedule удаляет выступление из личного расписания.
+func (uc *lineupUsecase) RemoveFromMySchedule(ctx context.Context, festivalID int, userID string, slotID int) error {
+	if _, err := uc.GetSlot(ctx, festivalID, slotID); err != nil {
+		return err
+	}
+	err := uc.lineupRepo.RemoveSelection(ctx, userID, slotID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSlotNotFound
+	}
+	return err
+}
+
+// getFestival находит фестиваль со сценами.
+func (uc *lineupUsecase) getFestival(ctx context.Context, festivalID int) (*models.Festival, error) {
+	festival, err := uc.festivalRepo.GetByID(ctx, festivalID)
+	if err != nil {
+		return nil, err
+	}
+	if festival == nil {
+		return nil, ErrFestivalNotFound
+	}
+	return festival, nil
+}
+
+// validateSlot проверяет, что сцена принадлежит фестивалю, исполнитель существует, выступление идет
+// в один из дней фестиваля и не пересекается с другими выступлениями на этой сцене.
+func (uc *lineupUsecase) validateSlot(ctx context.Context, festival *models.Festival, slot *models.Slot) error {
+	if slot.StartsAt.IsZero() || slot.EndsAt.IsZero() {
+		return fmt.Errorf("%w: starts_at и ends_at обязательны", ErrInvalidInput)
+	}
+	if !slot.EndsAt.After(slot.StartsAt) {
+		return fmt.Errorf("%w: выступление должно заканчиваться позже, чем начинается", ErrInvalidInput)
+	}
+	if slot.EndsAt.Sub(slot.StartsAt) > MaxSlotDuration {
+		return fmt.Errorf("%w: выступление не может длиться дольше %v", ErrInvalidInput, MaxSlotDuration)
+	}
+
+	location, err := time.LoadLocation(festival.Timezone)
+	if err != nil {
+		return err
+	}
+	if day := festivalDay(slot.StartsAt, location); day < festival.StartDate || day > festival.EndDate {
+		return fmt.Errorf("%w: выступление должно начинаться в дни фестиваля с %s по %s (%s)", ErrInvalidInput,
+			festival.StartDate, festival.EndDate, festival.Timezone)
+	}
+
+	var stage *models.Stage
+	for _, s := range festival.Stages {
+		if s.ID == slot.StageID {
+			stage = s
+		}
+	}
+	if stage == nil {
+		return fmt.Errorf("%w: у фестиваля нет сцены %d", ErrInvalidInput, slot.StageID)
+	}
+	artist, err := uc.lineupRepo.GetArtist(ctx, slot.ArtistID)
+	if err != nil {
+		return err
+	}
+	if artist == nil {
+		return fmt.Errorf("%w: исполнитель %d не найден", ErrInvalidInput, slot.ArtistID)
+	}
+
+	overlaps, err := uc.lineupRepo.FindOverlaps(ctx, slot.StageID, slot.StartsAt, slot.EndsAt, slot.ID)
+	if err != nil {
+		return err
+	}
+	if len(overlaps) > 0 {
+		return clashError(stage, overlaps, location)
+	}
+	return nil
+}
+
+// mapSlotWriteError переводит ошибки сохранения выступления. Пересечение, которое обошло проверку
+// из-за параллельной записи, описывается повторным поиском пересечений.
+func (uc *lineupUsecase) mapSlotWriteError(ctx context.Context, festival *models.Festival, slot *models.Slot, err error) error {
+	switch {
+	case errors.Is(err, repository.ErrSlotOverlap):
+		if validateErr := uc.validateSlot(ctx, festival, slot); validateErr != nil {
+			return validateErr
+		}
+		return ErrSlotClash
+	case errors.Is(err, repository.ErrReferenced):
+		return fmt.Errorf("%w: сцена или исполнитель удалены", ErrInvalidInput)
+	}
+	return err
+}
+
+// clashError описывает выступления, с которыми пересекается новое, во времени фестиваля.
+func clashError(stage *models.Stage, overlaps []*models.Slot, location *time.Location) error {
+	descriptions := make([]string, len(overlaps))
+	for i, overlap := range overlaps {
+		descriptions[i] = fmt.Sprintf("%s %s-%s (выступление %d)", overlap.Artist.Name,
+			overlap.StartsAt.In(location).Format("2006-01-02 15:04"), overlap.EndsAt.In(location).Format("15:04"), overlap.ID)
+	}
+	return fmt.Errorf("%w: сцена %q занята: %s", ErrSlotClash, stage.Name, strings.Join(descriptions, "; "))
+}
+
+// festivalDays перечисляет дни фестиваля с первого по последний.
+func festivalDays(festival *models.Festival) []string {
+	start, _ := time.Parse(models.FestivalDateLayout, festival.StartDate)
+	end, _ := time.Parse(models.FestivalDateLayout, festival.EndDate)
+	var days []string
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		days = append(days, day.Format(models.FestivalDateLayout))
+	}
+	return days
+}
+
+// festivalDay возвращает день фестиваля (ГГГГ-ММ-ДД), к которому относится момент t.
+func festivalDay(t time.Time, location *time.Location) string {
+	local := t.In(location)
+	if time.Duration(local.Hour())*time.Hour+time.Duration(local.Minute())*time.Minute < FestivalDayStart {
+		local = local.AddDate(0, 0, -1)
+	}
+	return local.Format(models.FestivalDateLayout)
+}
+
+// festivalDayStart возвращает момент начала дня фестиваля date.
+func festivalDayStart(date string, location *time.Location) time.Time {
+	day, _ := time.ParseInLocation(models.FestivalDateLayout, date, location)
+	return day.Add(FestivalDayStart)
+}
+
+// containsString сообщает, есть ли value в срезе values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
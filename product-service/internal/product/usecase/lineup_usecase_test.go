@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+)
+
+const testFestivalID = 1
+
+// newTestLineupUsecase создает lineupUsecase для фестиваля с 10 по 12 июля 2026 года по московскому времени
+// с двумя сценами и двумя исполнителями
+func newTestLineupUsecase() (LineupUsecase, *fakeLineupRepository) {
+	festivals := newFakeFestivalRepository(&models.Festival{
+		ID:        testFestivalID,
+		Name:      "Летний фестиваль",
+		StartDate: "2026-07-10",
+		EndDate:   "2026-07-12",
+		Timezone:  "Europe/Moscow",
+		Stages: []*models.Stage{
+			{ID: 1, FestivalID: testFestivalID, Name: "Главная"},
+			{ID: 2, FestivalID: testFestivalID, Name: "Малая"},
+		},
+	})
+	lineup := newFakeLineupRepository(festivals,
+		&models.Artist{ID: 1, Name: "Кино"},
+		&models.Artist{ID: 2, Name: "Сплин"},
+	)
+	return NewLineupUsecase(lineup, festivals), lineup
+}
+
+// msk возвращает момент по московскому времени; value - "ГГГГ-ММ-ДД ЧЧ:ММ"
+func msk(t *testing.T, value string) time.Time {
+	t.Helper()
+	location, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	moment, err := time.ParseInLocation("2006-01-02 15:04", value, location)
+	if err != nil {
+		t.Fatalf("ParseInLocation(%q): %v", value, err)
+	}
+	return moment
+}
+
+// mustCreateSlot создает выступление исполнителя artistID на сцене stageID с from до to (московское время)
+func mustCreateSlot(t *testing.T, uc LineupUsecase, stageID, artistID int, from, to string) *models.Slot {
+	t.Helper()
+	slot, err := uc.CreateSlot(context.Background(), testFestivalID,
+		SlotInput{StageID: stageID, ArtistID: artistID, StartsAt: msk(t, from), EndsAt: msk(t, to)})
+	if err != nil {
+		t.Fatalf("CreateSlot %s-%s: %v", from, to, err)
+	}
+	return slot
+}
+
+func TestCreateSlotValidation(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestLineupUsecase()
+	existing := mustCreateSlot(t, uc, 1, 1, "2026-07-10 20:00", "2026-07-10 21:00")
+	if !existing.StartsAt.Equal(msk(t, "2026-07-10 20:00")) || existing.StartsAt.Location() != time.UTC {
+		t.Fatalf("время начала должно сохраняться в UTC: %v", existing.StartsAt)
+	}
+
+	tests := []struct {
+		name     string
+		stageID  int
+		artistID int
+		from, to string
+		wantErr  error
+	}{
+		{"встык после", 1, 2, "2026-07-10 21:00", "2026-07-10 22:00", nil},
+		{"встык до", 1, 2, "2026-07-10 19:00", "2026-07-10 20:00", nil},
+		{"то же время на другой сцене", 2, 2, "2026-07-10 20:00", "2026-07-10 21:00", nil},
+		{"после полуночи последнего дня", 1, 2, "2026-07-13 02:00", "2026-07-13 03:00", nil},
+		{"пересекает начало", 1, 2, "2026-07-10 19:30", "2026-07-10 20:30", ErrSlotClash},
+		{"пересекает конец", 1, 2, "2026-07-10 20:59", "2026-07-10 21:30", ErrSlotClash},
+		{"внутри", 1, 2, "2026-07-10 20:15", "2026-07-10 20:45", ErrSlotClash},
+		{"охватывает", 1, 2, "2026-07-10 19:00", "2026-07-10 22:30", ErrSlotClash},
+		{"конец раньше начала", 1, 2, "2026-07-11 21:00", "2026-07-11 20:00", ErrInvalidInput},
+		{"нулевая длительность", 1, 2, "2026-07-11 21:00", "2026-07-11 21:00", ErrInvalidInput},
+		{"дольше 12 часов", 1, 2, "2026-07-11 08:00", "2026-07-11 20:01", ErrInvalidInput},
+		{"до фестиваля", 1, 2, "2026-07-09 20:00", "2026-07-09 21:00", ErrInvalidInput},
+		{"утро после фестиваля", 1, 2, "2026-07-13 06:00", "2026-07-13 07:00", ErrInvalidInput},
+		{"чужая сцена", 3, 2, "2026-07-11 20:00", "2026-07-11 21:00", ErrInvalidInput},
+		{"неизвестный исполнитель", 1, 3, "2026-07-11 20:00", "2026-07-11 21:00", ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.CreateSlot(ctx, testFestivalID,
+				SlotInput{StageID: tt.stageID, ArtistID: tt.artistID, StartsAt: msk(t, tt.from), EndsAt: msk(t, tt.to)})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("CreateSlot: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ожидалась %v, получено %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Ошибка пересечения называет занятое выступление во времени фестиваля
+	_, err := uc.CreateSlot(ctx, testFestivalID,
+		SlotInput{StageID: 1, ArtistID: 2, StartsAt: msk(t, "2026-07-10 20:30"), EndsAt: msk(t, "2026-07-10 20:45")})
+	if err == nil || !strings.Contains(err.Error(), "Кино 2026-07-10 20:00-21:00") || !strings.Contains(err.Error(), `"Главная"`) {
+		t.Fatalf("неинформативная ошибка пересечения: %v", err)
+	}
+
+	if _, err := uc.CreateSlot(ctx, 2, SlotInput{StageID: 1, ArtistID: 1}); !errors.Is(err, ErrFestivalNotFound) {
+		t.Fatalf("неизвестный фестиваль: ожидалась ErrFestivalNotFound, получено %v", err)
+	}
+}
+
+func TestUpdateSlotIgnoresItself(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestLineupUsecase()
+	slot := mustCreateSlot(t, uc, 1, 1, "2026-07-10 20:00", "2026-07-10 21:00")
+	other := mustCreateSlot(t, uc, 1, 2, "2026-07-10 22:00", "2026-07-10 23:00")
+
+	// Сдвиг внутри своего же интервала - не пересечение
+	endsAt := msk(t, "2026-07-10 21:30")
+	updated, err := uc.UpdateSlot(ctx, testFestivalID, slot.ID, UpdateSlotInput{EndsAt: &endsAt})
+	if err != nil {
+		t.Fatalf("UpdateSlot: %v", err)
+	}
+	if !updated.EndsAt.Equal(endsAt) || !updated.StartsAt.Equal(slot.StartsAt) {
+		t.Fatalf("неожиданное время после изменения: %v-%v", updated.StartsAt, updated.EndsAt)
+	}
+
+	endsAt = msk(t, "2026-07-10 22:01")
+	if _, err := uc.UpdateSlot(ctx, testFestivalID, slot.ID, UpdateSlotInput{EndsAt: &endsAt}); !errors.Is(err, ErrSlotClash) {
+		t.Fatalf("продление на следующее выступление: ожидалась ErrSlotClash, получено %v", err)
+	}
+	stageID := 2
+	if _, err := uc.UpdateSlot(ctx, testFestivalID, other.ID, UpdateSlotInput{StageID: &stageID}); err != nil {
+		t.Fatalf("перенос на другую сцену: %v", err)
+	}
+	if _, err := uc.UpdateSlot(ctx, testFestivalID, 100, UpdateSlotInput{}); !errors.Is(err, ErrSlotNotFound) {
+		t.Fatalf("неизвестное выступление: ожидалась ErrSlotNotFound, получено %v", err)
+	}
+}
+
+// scheduleSlotIDs возвращает ID выступлений сетки по дню и названию сцены
+func scheduleSlotIDs(schedule *models.Schedule) map[string]map[string][]int {
+	ids := make(map[string]map[string][]int)
+	for _, day := range schedule.Days {
+		ids[day.Date] = make(map[string][]int)
+		for _, stage := range day.Stages {
+			ids[day.Date][stage.StageName] = []int{}
+			for _, slot := range stage.Slots {
+				ids[day.Date][stage.StageName] = append(ids[day.Date][stage.StageName], slot.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func TestGetSchedule(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestLineupUsecase()
+	evening := mustCreateSlot(t, uc, 1, 1, "2026-07-10 20:00", "2026-07-10 21:00")
+	afterMidnight := mustCreateSlot(t, uc, 1, 2, "2026-07-11 01:00", "2026-07-11 02:00")
+	dawn := mustCreateSlot(t, uc, 2, 1, "2026-07-11 05:30", "2026-07-11 06:30")
+	morning := mustCreateSlot(t, uc, 1, 2, "2026-07-11 06:00", "2026-07-11 07:00")
+	lastNight := mustCreateSlot(t, uc, 1, 1, "2026-07-13 03:00", "2026-07-13 04:00")
+
+	schedule, err := uc.GetSchedule(ctx, testFestivalID, "")
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if len(schedule.Days) != 3 || schedule.Timezone != "Europe/Moscow" {
+		t.Fatalf("ожидалось 3 дня в Europe/Moscow, получено %d в %s", len(schedule.Days), schedule.Timezone)
+	}
+	got := scheduleSlotIDs(schedule)
+	want := map[string]map[string][]int{
+		// Выступления до 06:00 относятся к предыдущему дню фестиваля
+		"2026-07-10": {"Главная": {evening.ID, afterMidnight.ID}, "Малая": {dawn.ID}},
+		"2026-07-11": {"Главная": {morning.ID}, "Малая": {}},
+		"2026-07-12": {"Главная": {lastNight.ID}, "Малая": {}},
+	}
+	for date, stages := range want {
+		for stage, ids := range stages {
+			if !slices.Equal(got[date][stage], ids) {
+				t.Fatalf("%s, сцена %s: выступления %v, ожидались %v", date, stage, got[date][stage], ids)
+			}
+		}
+	}
+
+	// Фильтр по дню не захватывает выступление предыдущего дня, которое заканчивается после начала этого
+	oneDay, err := uc.GetSchedule(ctx, testFestivalID, "2026-07-11")
+	if err != nil {
+		t.Fatalf("GetSchedule за день: %v", err)
+	}
+	got = scheduleSlotIDs(oneDay)
+	if len(oneDay.Days) != 1 || !slices.Equal(got["2026-07-11"]["Главная"], []int{morning.ID}) || len(got["2026-07-11"]["Малая"]) != 0 {
+		t.Fatalf("сетка за 2026-07-11: %v", got)
+	}
+
+	for _, day := range []string{"2026-07-09", "2026-07-13", "11.07.2026"} {
+		if _, err := uc.GetSchedule(ctx, testFestivalID, day); !errors.Is(err, ErrInvalidInput) {
+			t.Fatalf("день %s: ожидалась ErrInvalidInput, получено %v", day, err)
+		}
+	}
+}
+
+func TestGetMyScheduleClashes(t *testing.T) {
+	ctx := context.Background()
+	uc, _ := newTestLineupUsecase()
+	a := mustCreateSlot(t, uc, 1, 1, "2026-07-10 20:00", "2026-07-10 21:00")
+	b := mustCreateSlot(t, uc, 2, 2, "2026-07-10 20:10", "2026-07-10 23:00")           // Длинное, пересекается с a и c
+	c := mustCreateSlot(t, uc, 1, 2, "2026-07-10 21:00", "2026-07-10 22:00")           // Встык с a
+	d := mustCreateSlot(t, uc, 1, 1, "2026-07-10 23:00", "2026-07-10 23:30")           // Встык с b и после c
+	notSelected := mustCreateSlot(t, uc, 1, 1, "2026-07-10 22:00", "2026-07-10 23:00") // Пересекается с b
+
+	for _, slot := range []*models.Slot{d, c, b, a} {
+		if err := uc.AddToMySchedule(ctx, testFestivalID, "user-1", slot.ID); err != nil {
+			t.Fatalf("AddToMySchedule: %v", err)
+		}
+	}
+
+	schedule, err := uc.GetMySchedule(ctx, testFestivalID, "user-1")
+	if err != nil {
+		t.Fatalf("GetMySchedule: %v", err)
+	}
+	if len(schedule.Slots) != 4 || schedule.Slots[0].ID != a.ID || schedule.Slots[3].ID != d.ID {
+		t.Fatalf("выступления должны идти в порядке начала: %v", schedule.Slots)
+	}
+	want := []SlotClash{{SlotIDs: [2]int{a.ID, b.ID}}, {SlotIDs: [2]int{b.ID, c.ID}}}
+	if !slices.Equal(schedule.Clashes, want) {
+		t.Fatalf("пересечения %v, ожидались %v", schedule.Clashes, want)
+	}
+	for _, clash := range schedule.Clashes {
+		if clash.SlotIDs[0] == notSelected.ID || clash.SlotIDs[1] == notSelected.ID {
+			t.Fatalf("в пересечения попало невыбранное выступление: %v", clash)
+		}
+	}
+
+	empty, err := uc.GetMySchedule(ctx, testFestivalID, "user-2")
+	if err != nil {
+		t.Fatalf("GetMySchedule без выбора: %v", err)
+	}
+	if len(empty.Slots) != 0 || empty.Clashes == nil || len(empty.Clashes) != 0 {
+		t.Fatalf("пустое расписание: %+v", empty)
+	}
+
+	if err := uc.AddToMySchedule(ctx, testFestivalID, "user-1", 100); !errors.Is(err, ErrSlotNotFound) {
+		t.Fatalf("неизвестное выступление: ожидалась ErrSlotNotFound, получено %v", err)
+	}
+}
@@ -2,8 +2,16 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
 	// ВАЖНО: Замените 'your_product_module_path' на имя вашего модуля product-service из go.mod
@@ -11,59 +19,159 @@ import (
 )
 
 var (
-	ErrProductNotFound = errors.New("продукт не найден")
-	ErrInvalidInput    = errors.New("некорректные входные данные")
+	ErrProductNotFound     = errors.New("продукт не найден")
+	ErrInvalidInput        = errors.New("некорректные входные данные")
+	ErrReservationNotFound = errors.New("резерв не найден")
+	ErrInvalidPageToken    = errors.New("некорректный токен страницы")
+	ErrInvalidSort         = errors.New("некорректное поле сортировки")
+	ErrInsufficientStock   = repository.ErrInsufficientStock
+	ErrReservationClosed   = repository.ErrReservationClosed
+	ErrVariantNotFound     = repository.ErrVariantNotFound
+	ErrVariantRequired     = repository.ErrVariantRequired
+	ErrVariantInUse        = repository.ErrVariantInUse
+	ErrVariantExists       = errors.New("вариант с таким артикулом или набором атрибутов уже существует")
 	// Добавьте другие ошибки бизнес-логики, если необходимо
 )
 
+// UnlimitedStock обозначает продукт с неограниченным количеством на складе.
+const UnlimitedStock = -1
+
+// Размер страницы списка продуктов
+const (
+	DefaultProductsPageSize = 50
+	MaxProductsPageSize     = 200
+)
+
+// ProductListQuery - параметры выборки страницы каталога
+type ProductListQuery struct {
+	Filter repository.ProductFilter
+	// Sort - поле сортировки (created_at, price, name), с префиксом "-" - по убыванию.
+	// По умолчанию -created_at: сначала новые продукты.
+	Sort      string
+	PageSize  int
+	PageToken string // NextPageToken предыдущей страницы или пустая строка для первой
+	WithTotal bool   // Посчитать число всех продуктов по фильтру
+	// IncludeFestivals - заполнить Product.Festival краткими сведениями о фестивале
+	IncludeFestivals bool
+}
+
+// ProductPage - страница каталога
+type ProductPage struct {
+	Products      []*models.Product
+	NextPageToken string // Пусто на последней странице
+	Total         int64  // Заполняется, только если запрошено WithTotal
+}
+
+// Число результатов полнотекстового поиска
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Языки полнотекстового поиска
+const (
+	SearchLanguageRussian = "ru"
+	SearchLanguageEnglish = "en"
+)
+
+// ProductSearchQuery - параметры полнотекстового поиска по каталогу
+type ProductSearchQuery struct {
+	Text string
+	// Language - язык запроса (ru, en). Пусто - запрос разбирается по обеим конфигурациям,
+	// совпадения выделяются по русской (она стеммит и латиницу английским стеммером).
+	Language string
+	Filter   repository.ProductFilter
+	Limit    int
+}
+
+// Ограничения вариантов продукта
+const (
+	MaxVariantsPerProduct = 100
+	MaxSKULength          = 64
+)
+
 // CreateProductInput определяет структуру для входных данных при создании продукта.
+// Если заданы варианты, Stock не используется: остаток продукта - сумма остатков вариантов.
 type CreateProductInput struct {
 	Name        string
 	Description string
 	Price       float64
 	Type        models.ProductType
 	Stock       int
-	FestivalID  *int
+	FestivalID  *int           `json:"festival_id"`
+	Variants    []VariantInput `json:"variants"`
 }
 
 // UpdateProductInput определяет структуру для входных данных при обновлении продукта.
+// Variants - полный новый набор вариантов: варианты с id изменяются, без id - создаются,
+// не вошедшие в набор - удаляются. nil оставляет варианты как есть, пустой список удаляет все.
+// Stock можно менять только у продукта без вариантов.
 type UpdateProductInput struct {
 	Name        *string
 	Description *string
 	Price       *float64
 	Type        *models.ProductType
 	Stock       *int
-	FestivalID  *int
+	FestivalID  *int            `json:"festival_id"`
+	Variants    *[]VariantInput `json:"variants"`
+}
+
+// VariantInput - вариант продукта при создании и обновлении. Price nil - действует цена продукта.
+type VariantInput struct {
+	ID         int               `json:"id"` // 0 - новый вариант
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      *float64          `json:"price"`
+	Stock      int               `json:"stock"`
 }
 
 // ProductUsecase определяет интерфейс для бизнес-логики, связанной с продуктами.
 type ProductUsecase interface {
 	CreateProduct(ctx context.Context, input CreateProductInput) (*models.Product, error)
 	GetProductByID(ctx context.Context, id int) (*models.Product, error)
-	ListProducts(ctx context.Context) ([]*models.Product, error)
+	// ListProducts возвращает страницу каталога по фильтру и сортировке.
+	// Возвращает ErrInvalidPageToken, если токен выдан для другой выборки.
+	ListProducts(ctx context.Context, query ProductListQuery) (*ProductPage, error)
+	// SearchProducts ищет продукты по словам названия и описания и возвращает их по убыванию релевантности
+	// с выделенными совпадениями. Последнее слово запроса ищется по префиксу.
+	SearchProducts(ctx context.Context, query ProductSearchQuery) ([]*models.ProductSearchResult, error)
 	UpdateProduct(ctx context.Context, id int, input UpdateProductInput) (*models.Product, error)
 	DeleteProduct(ctx context.Context, id int) error
+
+	// Резервирование остатков для оформления заказов. Продукт с вариантами резервируется по варианту variantID.
+	ReserveStock(ctx context.Context, productID int, variantID *int, orderID string, quantity int) (*models.Reservation, error)
+	CommitReservation(ctx context.Context, reservationID string) (*models.Reservation, error)
+	ReleaseReservation(ctx context.Context, reservationID string) (*models.Reservation, error)
 }
 
 type productUsecase struct {
-	productRepo repository.ProductRepository
-	// Здесь могут быть другие зависимости, например, клиент к сервису фестивалей
+	productRepo   repository.ProductRepository
+	inventoryRepo repository.InventoryRepository
+	festivalRepo  repository.FestivalRepository
 }
 
 // NewProductUsecase создает новый экземпляр productUsecase.
-func NewProductUsecase(productRepo repository.ProductRepository) ProductUsecase {
+func NewProductUsecase(productRepo repository.ProductRepository, inventoryRepo repository.InventoryRepository, festivalRepo repository.FestivalRepository) ProductUsecase {
 	return &productUsecase{
-		productRepo: productRepo,
+		productRepo:   productRepo,
+		inventoryRepo: inventoryRepo,
+		festivalRepo:  festivalRepo,
 	}
 }
 
 // CreateProduct создает новый продукт.
 func (uc *productUsecase) CreateProduct(ctx context.Context, input CreateProductInput) (*models.Product, error) {
 	// Валидация входных данных
-	if input.Name == "" || input.Price < 0 || input.Stock < 0 {
+	if input.Name == "" || input.Price < 0 || input.Stock < UnlimitedStock {
 		return nil, ErrInvalidInput
 	}
-	// Дополнительная валидация типа продукта, festival_id и т.д.
+	if err := uc.checkFestival(ctx, input.FestivalID); err != nil {
+		return nil, err
+	}
+	variants, err := buildVariants(input.Variants, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	product := &models.Product{
 		Name:        input.Name,
@@ -72,12 +180,12 @@ func (uc *productUsecase) CreateProduct(ctx context.Context, input CreateProduct
 		Type:        input.Type,
 		Stock:       input.Stock,
 		FestivalID:  input.FestivalID,
+		Variants:    variants,
 	}
 
 	createdProduct, err := uc.productRepo.Create(ctx, product)
 	if err != nil {
-		// TODO: Обработка специфичных ошибок репозитория
-		return nil, err
+		return nil, mapProductSaveError(err)
 	}
 	return createdProduct, nil
 }
@@ -94,17 +202,213 @@ func (uc *productUsecase) GetProductByID(ctx context.Context, id int) (*models.P
 	return product, nil
 }
 
-// ListProducts возвращает список всех продуктов.
-func (uc *productUsecase) ListProducts(ctx context.Context) ([]*models.Product, error) {
-	products, err := uc.productRepo.ListAll(ctx)
+// ListProducts выбирает страницу каталога. Токен страницы - закодированный курсор
+// (значение поля сортировки и id последнего продукта предыдущей страницы).
+func (uc *productUsecase) ListProducts(ctx context.Context, query ProductListQuery) (*ProductPage, error) {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultProductsPageSize
+	}
+	if pageSize > MaxProductsPageSize {
+		pageSize = MaxProductsPageSize
+	}
+
+	sortBy, desc, err := parseProductSort(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+	filter := query.Filter
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Type != "" && filter.Type != models.Ticket && filter.Type != models.Merchandise {
+		return nil, ErrInvalidInput
+	}
+	if (filter.MinPrice != nil && *filter.MinPrice < 0) || (filter.MaxPrice != nil && *filter.MaxPrice < 0) ||
+		(filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice) {
+		return nil, ErrInvalidInput
+	}
+
+	// Токен привязан к фильтру и сортировке: курсор от другой выборки указывал бы не туда
+	fingerprint := productListFingerprint(filter, query.Sort)
+	opts := repository.ProductListOptions{Filter: filter, SortBy: sortBy, Desc: desc, Limit: pageSize + 1}
+	if query.PageToken != "" {
+		cursor, err := decodeProductCursor(query.PageToken, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if !validSortValue(sortBy, cursor.Value) {
+			return nil, ErrInvalidPageToken
+		}
+		opts.After = cursor
+	}
+
+	// Запрашиваем на один продукт больше, чтобы узнать, есть ли следующая страница
+	products, err := uc.productRepo.ListPage(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
-	// Если список пуст, возвращаем пустой слайс, а не ошибку
-	if products == nil {
-		return []*models.Product{}, nil
+
+	page := &ProductPage{Products: products}
+	if len(products) > pageSize {
+		page.Products = products[:pageSize]
+		last := page.Products[pageSize-1]
+		page.NextPageToken = encodeProductCursor(repository.ProductCursor{
+			Value: repository.ProductSortValue(last, sortBy),
+			ID:    last.ID,
+		}, fingerprint)
+	}
+
+	if query.WithTotal {
+		page.Total, err = uc.productRepo.Count(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if query.IncludeFestivals {
+		if err := uc.attachFestivals(ctx, page.Products); err != nil {
+			return nil, err
+		}
 	}
-	return products, nil
+	return page, nil
+}
+
+// attachFestivals заполняет Product.Festival одним запросом на всю страницу
+func (uc *productUsecase) attachFestivals(ctx context.Context, products []*models.Product) error {
+	var ids []int
+	seen := make(map[int]bool)
+	for _, product := range products {
+		if product.FestivalID != nil && !seen[*product.FestivalID] {
+			seen[*product.FestivalID] = true
+			ids = append(ids, *product.FestivalID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	summaries, err := uc.festivalRepo.Summaries(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		if product.FestivalID != nil {
+			product.Festival = summaries[*product.FestivalID]
+		}
+	}
+	return nil
+}
+
+// checkFestival возвращает ErrFestivalNotFound, если продукт ссылается на несуществующий фестиваль
+func (uc *productUsecase) checkFestival(ctx context.Context, festivalID *int) error {
+	if festivalID == nil {
+		return nil
+	}
+	exists, err := uc.festivalRepo.Exists(ctx, *festivalID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrFestivalNotFound
+	}
+	return nil
+}
+
+// SearchProducts проверяет запрос и выбирает конфигурации поиска по языку.
+func (uc *productUsecase) SearchProducts(ctx context.Context, query ProductSearchQuery) ([]*models.ProductSearchResult, error) {
+	text := strings.TrimSpace(query.Text)
+	if text == "" || len([]rune(text)) > 200 {
+		return nil, ErrInvalidInput
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	var configs []string
+	switch query.Language {
+	case "":
+		configs = []string{repository.SearchConfigRussian, repository.SearchConfigEnglish}
+	case SearchLanguageRussian:
+		configs = []string{repository.SearchConfigRussian}
+	case SearchLanguageEnglish:
+		configs = []string{repository.SearchConfigEnglish}
+	default:
+		return nil, ErrInvalidInput
+	}
+	if query.Filter.Type != "" && query.Filter.Type != models.Ticket && query.Filter.Type != models.Merchandise {
+		return nil, ErrInvalidInput
+	}
+
+	return uc.productRepo.Search(ctx, repository.ProductSearchOptions{
+		Text:    text,
+		Configs: configs,
+		Filter:  query.Filter,
+		Limit:   limit,
+	})
+}
+
+// parseProductSort разбирает параметр сортировки вида "price" или "-created_at"
+func parseProductSort(sort string) (string, bool, error) {
+	if sort == "" {
+		return repository.ProductSortCreatedAt, true, nil
+	}
+	field := strings.TrimPrefix(sort, "-")
+	if !repository.IsValidProductSort(field) {
+		return "", false, ErrInvalidSort
+	}
+	return field, field != sort, nil
+}
+
+// validSortValue проверяет, что значение курсора можно привести к типу поля сортировки
+func validSortValue(sortBy, value string) bool {
+	switch sortBy {
+	case repository.ProductSortCreatedAt:
+		_, err := time.Parse(time.RFC3339Nano, value)
+		return err == nil
+	case repository.ProductSortPrice:
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	default:
+		return true
+	}
+}
+
+// productListFingerprint возвращает короткий отпечаток фильтра и сортировки для токена страницы
+func productListFingerprint(filter repository.ProductFilter, sort string) string {
+	data, _ := json.Marshal(struct {
+		Filter repository.ProductFilter
+		Sort   string
+	}{filter, sort})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// productCursorToken - содержимое токена страницы
+type productCursorToken struct {
+	Value       string `json:"v"`
+	ID          int    `json:"i"`
+	Fingerprint string `json:"q"`
+}
+
+// encodeProductCursor кодирует курсор в непрозрачный для клиента токен страницы
+func encodeProductCursor(cursor repository.ProductCursor, fingerprint string) string {
+	data, _ := json.Marshal(productCursorToken{Value: cursor.Value, ID: cursor.ID, Fingerprint: fingerprint})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeProductCursor разбирает токен страницы и проверяет, что он выдан для той же выборки
+func decodeProductCursor(token, fingerprint string) (*repository.ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var cursor productCursorToken
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID <= 0 || cursor.Fingerprint != fingerprint {
+		return nil, ErrInvalidPageToken
+	}
+	return &repository.ProductCursor{Value: cursor.Value, ID: cursor.ID}, nil
 }
 
 // UpdateProduct обновляет существующий продукт.
@@ -141,16 +445,36 @@ func (uc *productUsecase) UpdateProduct(ctx context.Context, id int, input Updat
 		productToUpdate.Type = *input.Type
 		changed = true
 	}
+	if input.Variants != nil {
+		variants, err := buildVariants(*input.Variants, currentProduct.Variants)
+		if err != nil {
+			return nil, err
+		}
+		if variants == nil {
+			variants = []*models.ProductVariant{} // Пустой набор удаляет все варианты, nil оставил бы их
+		}
+		productToUpdate.Variants = variants
+		changed = true
+	} else {
+		productToUpdate.Variants = nil
+	}
 	if input.Stock != nil && *input.Stock != productToUpdate.Stock {
-		if *input.Stock < 0 {
+		if *input.Stock < UnlimitedStock {
 			return nil, ErrInvalidInput
-		} // Валидация остатка
+		} // Валидация остатка (-1 означает неограниченное количество)
+		// Остаток продукта с вариантами складывается из остатков вариантов
+		if len(productToUpdate.Variants) > 0 || (input.Variants == nil && len(currentProduct.Variants) > 0) {
+			return nil, fmt.Errorf("%w: остаток продукта с вариантами задается в вариантах", ErrInvalidInput)
+		}
 		productToUpdate.Stock = *input.Stock
 		changed = true
 	}
 	if input.FestivalID != nil {
 		// Сравниваем указатели на int
 		if productToUpdate.FestivalID == nil || *input.FestivalID != *productToUpdate.FestivalID {
+			if err := uc.checkFestival(ctx, input.FestivalID); err != nil {
+				return nil, err
+			}
 			productToUpdate.FestivalID = input.FestivalID
 			changed = true
 		}
@@ -162,12 +486,96 @@ func (uc *productUsecase) UpdateProduct(ctx context.Context, id int, input Updat
 
 	updatedProduct, err := uc.productRepo.Update(ctx, &productToUpdate)
 	if err != nil {
-		// TODO: Обработка специфичных ошибок репозитория
-		return nil, err
+		return nil, mapProductSaveError(err)
+	}
+	if updatedProduct == nil {
+		return nil, ErrProductNotFound // Продукт удалили после чтения
 	}
 	return updatedProduct, nil
 }
 
+// mapProductSaveError переводит ошибки сохранения продукта в ошибки бизнес-логики
+func mapProductSaveError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrReferenced):
+		return ErrFestivalNotFound // Фестиваль удалили после проверки
+	case errors.Is(err, repository.ErrDuplicate):
+		return ErrVariantExists // Артикул занят вариантом другого продукта
+	}
+	return err
+}
+
+// buildVariants проверяет набор вариантов и переводит его в модели. existing - текущие варианты продукта:
+// вариант с ID должен быть среди них. Артикулы и наборы атрибутов в наборе не повторяются.
+func buildVariants(inputs []VariantInput, existing []*models.ProductVariant) ([]*models.ProductVariant, error) {
+	if len(inputs) > MaxVariantsPerProduct {
+		return nil, fmt.Errorf("%w: у продукта может быть не больше %d вариантов", ErrInvalidInput, MaxVariantsPerProduct)
+	}
+	known := make(map[int]bool, len(existing))
+	for _, variant := range existing {
+		known[variant.ID] = true
+	}
+
+	var variants []*models.ProductVariant
+	seenIDs := make(map[int]bool)
+	seenSKUs := make(map[string]bool)
+	seenAttributes := make(map[string]bool)
+	for _, input := range inputs {
+		if input.ID != 0 {
+			if !known[input.ID] {
+				return nil, ErrVariantNotFound
+			}
+			if seenIDs[input.ID] {
+				return nil, fmt.Errorf("%w: вариант %d указан дважды", ErrInvalidInput, input.ID)
+			}
+			seenIDs[input.ID] = true
+		}
+
+		sku := strings.TrimSpace(input.SKU)
+		if sku == "" || len(sku) > MaxSKULength {
+			return nil, fmt.Errorf("%w: артикул варианта обязателен и не длиннее %d символов", ErrInvalidInput, MaxSKULength)
+		}
+		if seenSKUs[sku] {
+			return nil, fmt.Errorf("%w: артикул %s указан дважды", ErrInvalidInput, sku)
+		}
+		seenSKUs[sku] = true
+
+		if len(input.Attributes) == 0 {
+			return nil, fmt.Errorf("%w: у варианта %s нет атрибутов", ErrInvalidInput, sku)
+		}
+		attributes := make(map[string]string, len(input.Attributes))
+		for name, value := range input.Attributes {
+			name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+			if name == "" || value == "" {
+				return nil, fmt.Errorf("%w: пустой атрибут у варианта %s", ErrInvalidInput, sku)
+			}
+			attributes[name] = value
+		}
+		// json.Marshal сортирует ключи, поэтому одинаковые наборы дают одинаковую строку
+		key, _ := json.Marshal(attributes)
+		if seenAttributes[string(key)] {
+			return nil, fmt.Errorf("%w: набор атрибутов варианта %s повторяется", ErrInvalidInput, sku)
+		}
+		seenAttributes[string(key)] = true
+
+		if input.Price != nil && *input.Price < 0 {
+			return nil, fmt.Errorf("%w: отрицательная цена варианта %s", ErrInvalidInput, sku)
+		}
+		if input.Stock < UnlimitedStock {
+			return nil, fmt.Errorf("%w: некорректный остаток варианта %s", ErrInvalidInput, sku)
+		}
+
+		variants = append(variants, &models.ProductVariant{
+			ID:         input.ID,
+			SKU:        sku,
+			Attributes: attributes,
+			Price:      input.Price,
+			Stock:      input.Stock,
+		})
+	}
+	return variants, nil
+}
+
 // DeleteProduct удаляет продукт по ID.
 func (uc *productUsecase) DeleteProduct(ctx context.Context, id int) error {
 	err := uc.productRepo.Delete(ctx, id)
@@ -179,3 +587,43 @@ func (uc *productUsecase) DeleteProduct(ctx context.Context, id int) error {
 	}
 	return nil
 }
+
+// ReserveStock резервирует quantity единиц продукта (варианта variantID, если он задан) под заказ orderID.
+func (uc *productUsecase) ReserveStock(ctx context.Context, productID int, variantID *int, orderID string, quantity int) (*models.Reservation, error) {
+	if quantity <= 0 || orderID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	reservation, err := uc.inventoryRepo.Reserve(ctx, productID, variantID, orderID, quantity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// CommitReservation подтверждает резерв после оформления заказа.
+func (uc *productUsecase) CommitReservation(ctx context.Context, reservationID string) (*models.Reservation, error) {
+	reservation, err := uc.inventoryRepo.Commit(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ReleaseReservation отменяет резерв и возвращает товар на склад.
+func (uc *productUsecase) ReleaseReservation(ctx context.Context, reservationID string) (*models.Reservation, error) {
+	reservation, err := uc.inventoryRepo.Release(ctx, reservationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return reservation, nil
+}
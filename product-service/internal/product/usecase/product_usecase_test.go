@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/models"
+	"github.com/Hayzerr/go-microservice-project/product-service/internal/product/repository"
+)
+
+// tiedProducts возвращает продукты, у которых совпадают значения каждого поля сортировки
+// у нескольких продуктов подряд, чтобы порядок на границах страниц решал id
+func tiedProducts() []*models.Product {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 123456000, time.UTC)
+	return []*models.Product{
+		{ID: 1, Name: "VIP", Price: 100, CreatedAt: base},
+		{ID: 2, Name: "Футболка", Price: 25.5, CreatedAt: base},
+		{ID: 3, Name: "VIP", Price: 100, CreatedAt: base.Add(time.Microsecond)},
+		{ID: 4, Name: "Кепка", Price: 25.5, CreatedAt: base},
+		{ID: 5, Name: "VIP", Price: 100, CreatedAt: base.Add(-time.Hour)},
+		{ID: 6, Name: "Кепка", Price: 0, CreatedAt: base.Add(time.Microsecond)},
+		{ID: 7, Name: "Стандарт", Price: 100, CreatedAt: base},
+	}
+}
+
+// collectPages проходит все страницы выборки и возвращает ID продуктов в порядке выдачи
+func collectPages(t *testing.T, uc ProductUsecase, sort string, pageSize int) []int {
+	t.Helper()
+	var ids []int
+	token := ""
+	for page := 0; ; page++ {
+		if page > 10 {
+			t.Fatalf("пагинация не заканчивается")
+		}
+		result, err := uc.ListProducts(context.Background(), ProductListQuery{Sort: sort, PageSize: pageSize, PageToken: token})
+		if err != nil {
+			t.Fatalf("ListProducts (страница %d): %v", page+1, err)
+		}
+		if len(result.Products) > pageSize {
+			t.Fatalf("на странице %d продуктов, размер страницы %d", len(result.Products), pageSize)
+		}
+		for _, product := range result.Products {
+			ids = append(ids, product.ID)
+		}
+		if result.NextPageToken == "" {
+			return ids
+		}
+		token = result.NextPageToken
+	}
+}
+
+func TestListProductsKeysetPagination(t *testing.T) {
+	tests := []struct {
+		sort string
+		want []int
+	}{
+		// По умолчанию - сначала новые; при равном времени создания - больший id
+		{"", []int{6, 3, 7, 4, 2, 1, 5}},
+		{"created_at", []int{5, 1, 2, 4, 7, 3, 6}},
+		{"-created_at", []int{6, 3, 7, 4, 2, 1, 5}},
+		{"price", []int{6, 2, 4, 1, 3, 5, 7}},
+		{"-price", []int{7, 5, 3, 1, 4, 2, 6}},
+		{"name", []int{1, 3, 5, 4, 6, 7, 2}},
+		{"-name", []int{2, 7, 6, 4, 5, 3, 1}},
+	}
+	for _, tt := range tests {
+		for _, pageSize := range []int{1, 2, 3, 7, 50} {
+			repo := &fakeProductRepository{products: tiedProducts()}
+			uc := NewProductUsecase(repo, nil, nil)
+
+			got := collectPages(t, uc, tt.sort, pageSize)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("сортировка %q, страница %d: порядок %v, ожидался %v", tt.sort, pageSize, got, tt.want)
+			}
+			// Запрашивается на один продукт больше размера страницы
+			for _, opts := range repo.pages {
+				if opts.Limit != pageSize+1 {
+					t.Fatalf("сортировка %q: Limit %d, ожидался %d", tt.sort, opts.Limit, pageSize+1)
+				}
+			}
+		}
+	}
+}
+
+func TestListProductsCursorValues(t *testing.T) {
+	ctx := context.Background()
+	for _, sort := range []string{"created_at", "price", "name"} {
+		repo := &fakeProductRepository{products: tiedProducts()}
+		uc := NewProductUsecase(repo, nil, nil)
+
+		first, err := uc.ListProducts(ctx, ProductListQuery{Sort: sort, PageSize: 2})
+		if err != nil {
+			t.Fatalf("ListProducts: %v", err)
+		}
+		if _, err := uc.ListProducts(ctx, ProductListQuery{Sort: sort, PageSize: 2, PageToken: first.NextPageToken}); err != nil {
+			t.Fatalf("ListProducts по токену: %v", err)
+		}
+
+		// Курсор второй страницы - значение поля сортировки и id последнего продукта первой
+		last := first.Products[1]
+		after := repo.pages[1].After
+		if after == nil || after.ID != last.ID || after.Value != repository.ProductSortValue(last, sort) {
+			t.Fatalf("сортировка %s: курсор %+v для последнего продукта %+v", sort, after, last)
+		}
+		if repo.pages[1].SortBy != sort || repo.pages[1].Desc {
+			t.Fatalf("сортировка %s: в репозиторий передано %q, Desc=%v", sort, repo.pages[1].SortBy, repo.pages[1].Desc)
+		}
+	}
+
+	// Время создания в курсоре не теряет микросекунды, иначе продукты с близким временем пропускались бы
+	created := time.Date(2026, 5, 1, 12, 0, 0, 123456000, time.UTC)
+	if value := repository.ProductSortValue(&models.Product{CreatedAt: created}, "created_at"); value != "2026-05-01T12:00:00.123456Z" {
+		t.Fatalf("значение курсора created_at: %s", value)
+	}
+}
+
+func TestProductCursorRoundTrip(t *testing.T) {
+	fingerprint := productListFingerprint(repository.ProductFilter{}, "price")
+	cursors := []repository.ProductCursor{
+		{Value: "25.5", ID: 2},
+		{Value: "2026-05-01T12:00:00.123456Z", ID: 1},
+		{Value: "Футболка \"XL\", синяя", ID: 1 << 30},
+		{Value: "", ID: 1},
+	}
+	for _, cursor := range cursors {
+		token := encodeProductCursor(cursor, fingerprint)
+		decoded, err := decodeProductCursor(token, fingerprint)
+		if err != nil {
+			t.Fatalf("decodeProductCursor(%+v): %v", cursor, err)
+		}
+		if *decoded != cursor {
+			t.Fatalf("курсор %+v после разбора стал %+v", cursor, *decoded)
+		}
+	}
+}
+
+func TestDecodeProductCursorRejectsTampered(t *testing.T) {
+	fingerprint := productListFingerprint(repository.ProductFilter{}, "price")
+	encode := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	valid := encodeProductCursor(repository.ProductCursor{Value: "25.5", ID: 2}, fingerprint)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"не base64", "!!!"},
+		{"base64 с дополнением", base64.URLEncoding.EncodeToString([]byte(`{"v":"1","i":2,"q":"` + fingerprint + `"}`))},
+		{"не JSON", base64.RawURLEncoding.EncodeToString([]byte("price=25.5"))},
+		{"JSON другого типа", encode([]int{1, 2})},
+		{"нулевой id", encode(productCursorToken{Value: "25.5", ID: 0, Fingerprint: fingerprint})},
+		{"отрицательный id", encode(productCursorToken{Value: "25.5", ID: -1, Fingerprint: fingerprint})},
+		{"id строкой", encode(map[string]string{"v": "25.5", "i": "2", "q": fingerprint})},
+		{"без отпечатка", encode(productCursorToken{Value: "25.5", ID: 2})},
+		{"чужой отпечаток", encodeProductCursor(repository.ProductCursor{Value: "25.5", ID: 2}, productListFingerprint(repository.ProductFilter{}, "name"))},
+		{"обрезанный токен", valid[:len(valid)-3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeProductCursor(tt.token, fingerprint); !errors.Is(err, ErrInvalidPageToken) {
+				t.Fatalf("ожидалась ErrInvalidPageToken, получено %v", err)
+			}
+		})
+	}
+}
+
+func TestListProductsRejectsInvalidPageTokens(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeProductRepository{products: tiedProducts()}
+	uc := NewProductUsecase(repo, nil, nil)
+
+	first, err := uc.ListProducts(ctx, ProductListQuery{Sort: "price", PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListProducts: %v", err)
+	}
+	minPrice := 10.0
+	withFilter := repository.ProductFilter{MinPrice: &minPrice}
+	forged := func(sort, value string) string {
+		return encodeProductCursor(repository.ProductCursor{Value: value, ID: 1}, productListFingerprint(repository.ProductFilter{}, sort))
+	}
+
+	tests := []struct {
+		name  string
+		query ProductListQuery
+	}{
+		{"токен другой сортировки", ProductListQuery{Sort: "-price", PageToken: first.NextPageToken}},
+		{"токен без фильтра для выборки с фильтром", ProductListQuery{Sort: "price", Filter: withFilter, PageToken: first.NextPageToken}},
+		{"цена не число", ProductListQuery{Sort: "price", PageToken: forged("price", "дорого")}},
+		{"время не RFC 3339", ProductListQuery{Sort: "created_at", PageToken: forged("created_at", "01.05.2026")}},
+		{"испорченный токен", ProductListQuery{Sort: "price", PageToken: first.NextPageToken + "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := len(repo.pages)
+			if _, err := uc.ListProducts(ctx, tt.query); !errors.Is(err, ErrInvalidPageToken) {
+				t.Fatalf("ожидалась ErrInvalidPageToken, получено %v", err)
+			}
+			if len(repo.pages) != calls {
+				t.Fatalf("недействительный токен дошел до репозитория")
+			}
+		})
+	}
+
+	// Значение имени не приводится к типу, поэтому любое подходит
+	if _, err := uc.ListProducts(ctx, ProductListQuery{Sort: "name", PageToken: forged("name", "'; DROP TABLE products; --")}); err != nil {
+		t.Fatalf("курсор по имени: %v", err)
+	}
+	if _, err := uc.ListProducts(ctx, ProductListQuery{Sort: "stock"}); !errors.Is(err, ErrInvalidSort) {
+		t.Fatalf("сортировка вне списка: ожидалась ErrInvalidSort, получено %v", err)
+	}
+}
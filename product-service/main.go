@@ -11,6 +11,8 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// База часовых поясов встраивается в бинарник: в образе alpine нет tzdata, а фестивали хранят зону IANA
+	_ "time/tzdata"
 
 	// Драйвер базы данных PostgreSQL
 	_ "github.com/lib/pq"
@@ -23,6 +25,9 @@ import (
 	// Путь к модулю с protobuf определениями (из pb/go.mod)
 	pb "github.com/Hayzerr/go-microservice-project/pb" // Пример
 
+	// Общая проверка JWT-токенов, выданных user-service
+	"github.com/Hayzerr/go-microservice-project/auth"
+
 	// Пути к внутренним пакетам product-service.
 	// Замените "github.com/Hayzerr/go-microservice-project/product-service"
 	// на актуальное имя модуля из product-service/go.mod, если оно другое.
@@ -66,18 +71,59 @@ func main() {
 
 	// 2. Создание экземпляра репозитория
 	productRepo := repository.NewProductRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	festivalRepo := repository.NewFestivalRepository(db)
+	lineupRepo := repository.NewLineupRepository(db)
 	log.Println("Репозиторий продуктов инициализирован.")
 
 	// 3. Создание экземпляра бизнес-логики (usecase)
-	productUsecase := usecase.NewProductUsecase(productRepo)
+	productUsecase := usecase.NewProductUsecase(productRepo, inventoryRepo, festivalRepo)
+	festivalUsecase := usecase.NewFestivalUsecase(festivalRepo)
+	lineupUsecase := usecase.NewLineupUsecase(lineupRepo, festivalRepo)
 	log.Println("Бизнес-логика продуктов инициализирована.")
 
 	// 4. Создание экземпляра gRPC обработчика
 	productGRPCHandler := grpcProductDelivery.NewProductGRPCHandler(productUsecase)
+	festivalGRPCHandler := grpcProductDelivery.NewFestivalGRPCHandler(festivalUsecase)
+	lineupGRPCHandler := grpcProductDelivery.NewLineupGRPCHandler(lineupUsecase)
 	log.Println("gRPC обработчик продуктов инициализирован.")
 
 	// 5. Создание экземпляра HTTP обработчика
-	productHTTPHandler := httpProductDelivery.NewProductHTTPHandler(productUsecase, productRepo)
+	userServiceURL := getenv("USER_SERVICE_URL", "http://localhost:8081")
+	// Токены проверяются открытыми ключами user-service (JWKS, кешируется на JWT_JWKS_CACHE_TTL).
+	// Если задан JWT_SECRET, используется прежняя проверка общим секретом (user-service с JWT_ALGORITHM=HS256).
+	// Отозванные при выходе токены отклоняются по списку, который загружается из user-service
+	// каждые TOKEN_DENYLIST_INTERVAL (0 отключает проверку).
+	var tokenValidator auth.TokenValidator
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		tokenValidator = auth.NewValidator(secret)
+	} else {
+		jwksCacheTTL, err := time.ParseDuration(getenv("JWT_JWKS_CACHE_TTL", "5m"))
+		if err != nil {
+			log.Fatalf("Некорректное значение JWT_JWKS_CACHE_TTL: %v", err)
+		}
+		jwksURL := getenv("JWT_JWKS_URL", userServiceURL+"/.well-known/jwks.json")
+		tokenValidator = auth.NewKeyValidator(auth.NewJWKSCache(jwksURL, jwksCacheTTL))
+	}
+	denylistInterval, err := time.ParseDuration(getenv("TOKEN_DENYLIST_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("Некорректное значение TOKEN_DENYLIST_INTERVAL: %v", err)
+	}
+	stopDenylist := make(chan struct{})
+	if denylistInterval > 0 {
+		denylist := auth.NewRemoteDenylist(userServiceURL+"/api/auth/revoked", denylistInterval)
+		go denylist.Run(stopDenylist)
+		tokenValidator = auth.WithDenylist(tokenValidator, denylist)
+	}
+	// API-ключи проверяются в user-service; ответ кешируется на API_KEY_CACHE_TTL,
+	// поэтому отзыв ключа вступает в силу с такой же задержкой
+	apiKeyCacheTTL, err := time.ParseDuration(getenv("API_KEY_CACHE_TTL", "30s"))
+	if err != nil {
+		log.Fatalf("Некорректное значение API_KEY_CACHE_TTL: %v", err)
+	}
+	tokenValidator = auth.WithAPIKeys(tokenValidator, auth.NewRemoteAPIKeys(userServiceURL+"/api/auth/api-keys/verify", apiKeyCacheTTL))
+	productHTTPHandler := httpProductDelivery.NewProductHTTPHandler(productUsecase, productRepo, tokenValidator)
+	festivalHTTPHandler := httpProductDelivery.NewFestivalHTTPHandler(festivalUsecase, lineupUsecase, tokenValidator)
 	log.Println("HTTP обработчик продуктов инициализирован.")
 
 	var gRPCServer *grpc.Server
@@ -89,8 +135,21 @@ func main() {
 		if err != nil {
 			log.Fatalf("Ошибка прослушивания gRPC порта %s для product-service: %v", grpcPort, err)
 		}
-		gRPCServer = grpc.NewServer()
-		pb.RegisterProductServiceServer(gRPCServer, productGRPCHandler) // Регистрация ProductService
+		gRPCServer = grpc.NewServer(
+			grpc.ChainUnaryInterceptor(
+				auth.UnaryServerInterceptor(tokenValidator, grpcProductDelivery.PublicMethods...),
+				auth.UnaryRoleInterceptor(grpcProductDelivery.RolePolicy),
+				auth.UnaryScopeInterceptor(grpcProductDelivery.ScopePolicy),
+			),
+			grpc.ChainStreamInterceptor(
+				auth.StreamServerInterceptor(tokenValidator, grpcProductDelivery.PublicMethods...),
+				auth.StreamRoleInterceptor(grpcProductDelivery.RolePolicy),
+				auth.StreamScopeInterceptor(grpcProductDelivery.ScopePolicy),
+			),
+		)
+		pb.RegisterProductServiceServer(gRPCServer, productGRPCHandler)   // Регистрация ProductService
+		pb.RegisterFestivalServiceServer(gRPCServer, festivalGRPCHandler) // Регистрация FestivalService
+		pb.RegisterLineupServiceServer(gRPCServer, lineupGRPCHandler)     // Регистрация LineupService
 		reflection.Register(gRPCServer)
 
 		log.Printf("Product-service gRPC сервер запущен на порту: %s", grpcPort)
@@ -110,6 +169,7 @@ func main() {
 		})
 		// Регистрация маршрутов для product HTTP handler
 		productHTTPHandler.RegisterRoutes(mux)
+		festivalHTTPHandler.RegisterRoutes(mux)
 
 		httpServer = &http.Server{
 			Addr:    ":" + httpPort,
@@ -130,6 +190,8 @@ func main() {
 	sig := <-quit
 	log.Printf("Product-service: получен сигнал %v, начинаю корректное выключение...", sig)
 
+	close(stopDenylist)
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
@@ -73,6 +73,61 @@ func (ProductTypeProto) EnumDescriptor() ([]byte, []int) {
 	return file_proto_product_proto_rawDescGZIP(), []int{0}
 }
 
+type FestivalStatusProto int32
+
+const (
+	FestivalStatusProto_FESTIVAL_STATUS_UNSPECIFIED FestivalStatusProto = 0
+	FestivalStatusProto_FESTIVAL_DRAFT              FestivalStatusProto = 1
+	FestivalStatusProto_FESTIVAL_ON_SALE            FestivalStatusProto = 2
+	FestivalStatusProto_FESTIVAL_SOLD_OUT           FestivalStatusProto = 3
+	FestivalStatusProto_FESTIVAL_FINISHED           FestivalStatusProto = 4
+)
+
+// Enum value maps for FestivalStatusProto.
+var (
+	FestivalStatusProto_name = map[int32]string{
+		0: "FESTIVAL_STATUS_UNSPECIFIED",
+		1: "FESTIVAL_DRAFT",
+		2: "FESTIVAL_ON_SALE",
+		3: "FESTIVAL_SOLD_OUT",
+		4: "FESTIVAL_FINISHED",
+	}
+	FestivalStatusProto_value = map[string]int32{
+		"FESTIVAL_STATUS_UNSPECIFIED": 0,
+		"FESTIVAL_DRAFT":              1,
+		"FESTIVAL_ON_SALE":            2,
+		"FESTIVAL_SOLD_OUT":           3,
+		"FESTIVAL_FINISHED":           4,
+	}
+)
+
+func (x FestivalStatusProto) Enum() *FestivalStatusProto {
+	p := new(FestivalStatusProto)
+	*p = x
+	return p
+}
+
+func (x FestivalStatusProto) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FestivalStatusProto) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_product_proto_enumTypes[1].Descriptor()
+}
+
+func (FestivalStatusProto) Type() protoreflect.EnumType {
+	return &file_proto_product_proto_enumTypes[1]
+}
+
+func (x FestivalStatusProto) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FestivalStatusProto.Descriptor instead.
+func (FestivalStatusProto) EnumDescriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{1}
+}
+
 type Product struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -84,6 +139,8 @@ type Product struct {
 	FestivalId    string                 `protobuf:"bytes,7,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Festival      *FestivalSummary       `protobuf:"bytes,10,opt,name=festival,proto3" json:"festival,omitempty"` // Заполняется в ListProducts с include_festivals
+	Variants      []*ProductVariant      `protobuf:"bytes,11,rep,name=variants,proto3" json:"variants,omitempty"` // Заполняется в карточке продукта (Get, Create, Update)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -181,21 +238,260 @@ func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Product) GetFestival() *FestivalSummary {
+	if x != nil {
+		return x.Festival
+	}
+	return nil
+}
+
+func (x *Product) GetVariants() []*ProductVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// ProductVariant - вариант продукта (размер, цвет) со своим артикулом, ценой и остатком.
+// Если у продукта есть варианты, Product.stock - их суммарный остаток.
+type ProductVariant struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                  `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Sku           string                  `protobuf:"bytes,3,opt,name=sku,proto3" json:"sku,omitempty"`
+	Attributes    map[string]string       `protobuf:"bytes,4,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Например {"size": "M", "color": "blue"}
+	Price         *wrapperspb.DoubleValue `protobuf:"bytes,5,opt,name=price,proto3" json:"price,omitempty"`                                                                                     // Не задана - действует цена продукта
+	Stock         int32                   `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`                                                                                    // -1 - неограниченное количество
+	CreatedAt     *timestamppb.Timestamp  `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp  `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVariant) Reset() {
+	*x = ProductVariant{}
+	mi := &file_proto_product_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVariant) ProtoMessage() {}
+
+func (x *ProductVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVariant.ProtoReflect.Descriptor instead.
+func (*ProductVariant) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProductVariant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ProductVariant) GetPrice() *wrapperspb.DoubleValue {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductVariant) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ProductVariant) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// ProductVariantInput - вариант при создании и обновлении продукта. Пустой id - новый вариант.
+type ProductVariantInput struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sku           string                  `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Attributes    map[string]string       `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Price         *wrapperspb.DoubleValue `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         int32                   `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVariantInput) Reset() {
+	*x = ProductVariantInput{}
+	mi := &file_proto_product_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVariantInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVariantInput) ProtoMessage() {}
+
+func (x *ProductVariantInput) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVariantInput.ProtoReflect.Descriptor instead.
+func (*ProductVariantInput) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProductVariantInput) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProductVariantInput) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductVariantInput) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ProductVariantInput) GetPrice() *wrapperspb.DoubleValue {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *ProductVariantInput) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+// ProductVariantList - полный набор вариантов продукта при обновлении
+type ProductVariantList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Variants      []*ProductVariantInput `protobuf:"bytes,1,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVariantList) Reset() {
+	*x = ProductVariantList{}
+	mi := &file_proto_product_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVariantList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVariantList) ProtoMessage() {}
+
+func (x *ProductVariantList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVariantList.ProtoReflect.Descriptor instead.
+func (*ProductVariantList) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProductVariantList) GetVariants() []*ProductVariantInput {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
 type CreateProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
 	Type          ProductTypeProto       `protobuf:"varint,4,opt,name=type,proto3,enum=pb.ProductTypeProto" json:"type,omitempty"`
-	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"` // Не используется, если заданы варианты
 	FestivalId    string                 `protobuf:"bytes,6,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Variants      []*ProductVariantInput `protobuf:"bytes,7,rep,name=variants,proto3" json:"variants,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateProductRequest) Reset() {
 	*x = CreateProductRequest{}
-	mi := &file_proto_product_proto_msgTypes[1]
+	mi := &file_proto_product_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -207,7 +503,7 @@ func (x *CreateProductRequest) String() string {
 func (*CreateProductRequest) ProtoMessage() {}
 
 func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[1]
+	mi := &file_proto_product_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -220,7 +516,7 @@ func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
 func (*CreateProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{1}
+	return file_proto_product_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateProductRequest) GetName() string {
@@ -265,6 +561,13 @@ func (x *CreateProductRequest) GetFestivalId() string {
 	return ""
 }
 
+func (x *CreateProductRequest) GetVariants() []*ProductVariantInput {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
 type CreateProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -274,7 +577,7 @@ type CreateProductResponse struct {
 
 func (x *CreateProductResponse) Reset() {
 	*x = CreateProductResponse{}
-	mi := &file_proto_product_proto_msgTypes[2]
+	mi := &file_proto_product_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -286,7 +589,7 @@ func (x *CreateProductResponse) String() string {
 func (*CreateProductResponse) ProtoMessage() {}
 
 func (x *CreateProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[2]
+	mi := &file_proto_product_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -299,7 +602,7 @@ func (x *CreateProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateProductResponse.ProtoReflect.Descriptor instead.
 func (*CreateProductResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{2}
+	return file_proto_product_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateProductResponse) GetProduct() *Product {
@@ -318,7 +621,7 @@ type GetProductRequest struct {
 
 func (x *GetProductRequest) Reset() {
 	*x = GetProductRequest{}
-	mi := &file_proto_product_proto_msgTypes[3]
+	mi := &file_proto_product_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -330,7 +633,7 @@ func (x *GetProductRequest) String() string {
 func (*GetProductRequest) ProtoMessage() {}
 
 func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[3]
+	mi := &file_proto_product_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +646,7 @@ func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
 func (*GetProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{3}
+	return file_proto_product_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetProductRequest) GetId() string {
@@ -362,7 +665,7 @@ type GetProductResponse struct {
 
 func (x *GetProductResponse) Reset() {
 	*x = GetProductResponse{}
-	mi := &file_proto_product_proto_msgTypes[4]
+	mi := &file_proto_product_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -374,7 +677,7 @@ func (x *GetProductResponse) String() string {
 func (*GetProductResponse) ProtoMessage() {}
 
 func (x *GetProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[4]
+	mi := &file_proto_product_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -387,7 +690,7 @@ func (x *GetProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductResponse.ProtoReflect.Descriptor instead.
 func (*GetProductResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{4}
+	return file_proto_product_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetProductResponse) GetProduct() *Product {
@@ -397,15 +700,26 @@ func (x *GetProductResponse) GetProduct() *Product {
 	return nil
 }
 
+// ListProducts возвращает страницу каталога: следующую страницу запрашивают с next_page_token.
 type ListProductsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState  `protogen:"open.v1"`
+	PageSize         int32                   `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // По умолчанию 50, не больше 200
+	PageToken        string                  `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // Действителен только для тех же фильтров и сортировки
+	Type             ProductTypeProto        `protobuf:"varint,3,opt,name=type,proto3,enum=pb.ProductTypeProto" json:"type,omitempty"`  // Не задано - все типы
+	FestivalId       string                  `protobuf:"bytes,4,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	MinPrice         *wrapperspb.DoubleValue `protobuf:"bytes,5,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`                           // Включительно
+	MaxPrice         *wrapperspb.DoubleValue `protobuf:"bytes,6,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`                           // Включительно
+	InStock          bool                    `protobuf:"varint,7,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`                             // Только продукты в наличии
+	Query            string                  `protobuf:"bytes,8,opt,name=query,proto3" json:"query,omitempty"`                                                 // Подстрока названия или описания
+	Sort             string                  `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`                                                   // created_at, price, name; "-" в начале - по убыванию; по умолчанию -created_at
+	IncludeFestivals bool                    `protobuf:"varint,10,opt,name=include_festivals,json=includeFestivals,proto3" json:"include_festivals,omitempty"` // Добавить к продуктам краткие сведения о фестивале
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *ListProductsRequest) Reset() {
 	*x = ListProductsRequest{}
-	mi := &file_proto_product_proto_msgTypes[5]
+	mi := &file_proto_product_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -417,7 +731,7 @@ func (x *ListProductsRequest) String() string {
 func (*ListProductsRequest) ProtoMessage() {}
 
 func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[5]
+	mi := &file_proto_product_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -430,31 +744,103 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
 func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{5}
+	return file_proto_product_proto_rawDescGZIP(), []int{8}
 }
 
-type ListProductsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ListProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
 }
 
-func (x *ListProductsResponse) Reset() {
-	*x = ListProductsResponse{}
-	mi := &file_proto_product_proto_msgTypes[6]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ListProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
 }
 
-func (x *ListProductsResponse) String() string {
+func (x *ListProductsRequest) GetType() ProductTypeProto {
+	if x != nil {
+		return x.Type
+	}
+	return ProductTypeProto_PRODUCT_TYPE_PROTO_UNSPECIFIED
+}
+
+func (x *ListProductsRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetMinPrice() *wrapperspb.DoubleValue {
+	if x != nil {
+		return x.MinPrice
+	}
+	return nil
+}
+
+func (x *ListProductsRequest) GetMaxPrice() *wrapperspb.DoubleValue {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return nil
+}
+
+func (x *ListProductsRequest) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetIncludeFestivals() bool {
+	if x != nil {
+		return x.IncludeFestivals
+	}
+	return false
+}
+
+type ListProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Пусто на последней странице
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`                                       // Число продуктов по фильтру на всех страницах
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsResponse) Reset() {
+	*x = ListProductsResponse{}
+	mi := &file_proto_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
 func (*ListProductsResponse) ProtoMessage() {}
 
 func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[6]
+	mi := &file_proto_product_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -467,7 +853,7 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
 func (*ListProductsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{6}
+	return file_proto_product_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListProductsResponse) GetProducts() []*Product {
@@ -477,22 +863,236 @@ func (x *ListProductsResponse) GetProducts() []*Product {
 	return nil
 }
 
+func (x *ListProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListProductsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// SearchProducts ищет продукты по словам названия и описания; последнее слово ищется по префиксу
+type SearchProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Lang          string                 `protobuf:"bytes,2,opt,name=lang,proto3" json:"lang,omitempty"` // ru, en; пусто - оба языка
+	Type          ProductTypeProto       `protobuf:"varint,3,opt,name=type,proto3,enum=pb.ProductTypeProto" json:"type,omitempty"`
+	FestivalId    string                 `protobuf:"bytes,4,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	InStock       bool                   `protobuf:"varint,5,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"` // По умолчанию 20, не больше 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRequest) Reset() {
+	*x = SearchProductsRequest{}
+	mi := &file_proto_product_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRequest) ProtoMessage() {}
+
+func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SearchProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetType() ProductTypeProto {
+	if x != nil {
+		return x.Type
+	}
+	return ProductTypeProto_PRODUCT_TYPE_PROTO_UNSPECIFIED
+}
+
+func (x *SearchProductsRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *SearchProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ProductSearchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	Rank          float64                `protobuf:"fixed64,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	NameHighlight string                 `protobuf:"bytes,3,opt,name=name_highlight,json=nameHighlight,proto3" json:"name_highlight,omitempty"` // Совпадения обрамлены <mark>, остальной текст экранирован для HTML
+	Snippet       string                 `protobuf:"bytes,4,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSearchResult) Reset() {
+	*x = ProductSearchResult{}
+	mi := &file_proto_product_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSearchResult) ProtoMessage() {}
+
+func (x *ProductSearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSearchResult.ProtoReflect.Descriptor instead.
+func (*ProductSearchResult) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ProductSearchResult) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductSearchResult) GetRank() float64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *ProductSearchResult) GetNameHighlight() string {
+	if x != nil {
+		return x.NameHighlight
+	}
+	return ""
+}
+
+func (x *ProductSearchResult) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+type SearchProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*ProductSearchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // По убыванию релевантности
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse) Reset() {
+	*x = SearchProductsResponse{}
+	mi := &file_proto_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse) ProtoMessage() {}
+
+func (x *SearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchProductsResponse) GetResults() []*ProductSearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
 type UpdateProductRequest struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          *wrapperspb.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         *wrapperspb.DoubleValue `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
-	Type          ProductTypeProto        `protobuf:"varint,5,opt,name=type,proto3,enum=pb.ProductTypeProto" json:"type,omitempty"`
-	Stock         *wrapperspb.Int32Value  `protobuf:"bytes,6,opt,name=stock,proto3" json:"stock,omitempty"`
-	FestivalId    *wrapperspb.StringValue `protobuf:"bytes,7,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	state       protoimpl.MessageState  `protogen:"open.v1"`
+	Id          string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        *wrapperspb.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price       *wrapperspb.DoubleValue `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Type        ProductTypeProto        `protobuf:"varint,5,opt,name=type,proto3,enum=pb.ProductTypeProto" json:"type,omitempty"`
+	Stock       *wrapperspb.Int32Value  `protobuf:"bytes,6,opt,name=stock,proto3" json:"stock,omitempty"`
+	FestivalId  *wrapperspb.StringValue `protobuf:"bytes,7,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	// Задан - заменяет варианты продукта: варианты с id изменяются, без id создаются, остальные удаляются.
+	// Пустой список удаляет все варианты.
+	Variants      *ProductVariantList `protobuf:"bytes,8,opt,name=variants,proto3" json:"variants,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateProductRequest) Reset() {
 	*x = UpdateProductRequest{}
-	mi := &file_proto_product_proto_msgTypes[7]
+	mi := &file_proto_product_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -504,7 +1104,7 @@ func (x *UpdateProductRequest) String() string {
 func (*UpdateProductRequest) ProtoMessage() {}
 
 func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[7]
+	mi := &file_proto_product_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,7 +1117,7 @@ func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
 func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{7}
+	return file_proto_product_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UpdateProductRequest) GetId() string {
@@ -569,6 +1169,13 @@ func (x *UpdateProductRequest) GetFestivalId() *wrapperspb.StringValue {
 	return nil
 }
 
+func (x *UpdateProductRequest) GetVariants() *ProductVariantList {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
 type UpdateProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -578,7 +1185,7 @@ type UpdateProductResponse struct {
 
 func (x *UpdateProductResponse) Reset() {
 	*x = UpdateProductResponse{}
-	mi := &file_proto_product_proto_msgTypes[8]
+	mi := &file_proto_product_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -590,7 +1197,7 @@ func (x *UpdateProductResponse) String() string {
 func (*UpdateProductResponse) ProtoMessage() {}
 
 func (x *UpdateProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[8]
+	mi := &file_proto_product_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -603,7 +1210,7 @@ func (x *UpdateProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateProductResponse.ProtoReflect.Descriptor instead.
 func (*UpdateProductResponse) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{8}
+	return file_proto_product_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *UpdateProductResponse) GetProduct() *Product {
@@ -622,7 +1229,7 @@ type DeleteProductRequest struct {
 
 func (x *DeleteProductRequest) Reset() {
 	*x = DeleteProductRequest{}
-	mi := &file_proto_product_proto_msgTypes[9]
+	mi := &file_proto_product_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -634,7 +1241,7 @@ func (x *DeleteProductRequest) String() string {
 func (*DeleteProductRequest) ProtoMessage() {}
 
 func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_product_proto_msgTypes[9]
+	mi := &file_proto_product_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -647,7 +1254,7 @@ func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
 func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
-	return file_proto_product_proto_rawDescGZIP(), []int{9}
+	return file_proto_product_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *DeleteProductRequest) GetId() string {
@@ -657,66 +1264,3891 @@ func (x *DeleteProductRequest) GetId() string {
 	return ""
 }
 
-var File_proto_product_proto protoreflect.FileDescriptor
+type Reservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	VariantId     string                 `protobuf:"bytes,8,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // Пусто, если у продукта нет вариантов
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_product_proto_rawDesc = "" +
-	"\n" +
-	"\x13proto/product.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xbc\x02\n" +
-	"\aProduct\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12(\n" +
-	"\x04type\x18\x05 \x01(\x0e2\x14.pb.ProductTypeProtoR\x04type\x12\x14\n" +
-	"\x05stock\x18\x06 \x01(\x05R\x05stock\x12\x1f\n" +
-	"\vfestival_id\x18\a \x01(\tR\n" +
-	"festivalId\x129\n" +
-	"\n" +
-	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc3\x01\n" +
-	"\x14CreateProductRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x01R\x05price\x12(\n" +
-	"\x04type\x18\x04 \x01(\x0e2\x14.pb.ProductTypeProtoR\x04type\x12\x14\n" +
-	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1f\n" +
-	"\vfestival_id\x18\x06 \x01(\tR\n" +
-	"festivalId\">\n" +
-	"\x15CreateProductResponse\x12%\n" +
-	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\"#\n" +
-	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
-	"\x12GetProductResponse\x12%\n" +
-	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\"\x15\n" +
-	"\x13ListProductsRequest\"?\n" +
-	"\x14ListProductsResponse\x12'\n" +
-	"\bproducts\x18\x01 \x03(\v2\v.pb.ProductR\bproducts\"\xe8\x02\n" +
-	"\x14UpdateProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_proto_product_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Reservation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reservation) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Reservation) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Reservation) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Reservation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Reservation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId     string                 `protobuf:"bytes,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"` // Обязателен для продукта с вариантами
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_proto_product_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReserveStockRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ReserveStockRequest) GetVariantId() string {
+	if x != nil {
+		return x.VariantId
+	}
+	return ""
+}
+
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_proto_product_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReserveStockResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_proto_product_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CommitReservationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CommitReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationResponse) Reset() {
+	*x = CommitReservationResponse{}
+	mi := &file_proto_product_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationResponse) ProtoMessage() {}
+
+func (x *CommitReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationResponse.ProtoReflect.Descriptor instead.
+func (*CommitReservationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CommitReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_proto_product_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReleaseReservationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ReleaseReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationResponse) Reset() {
+	*x = ReleaseReservationResponse{}
+	mi := &file_proto_product_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationResponse) ProtoMessage() {}
+
+func (x *ReleaseReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ReleaseReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type Venue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Country       string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	Capacity      int32                  `protobuf:"varint,6,opt,name=capacity,proto3" json:"capacity,omitempty"` // 0 - не указана
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Venue) Reset() {
+	*x = Venue{}
+	mi := &file_proto_product_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Venue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Venue) ProtoMessage() {}
+
+func (x *Venue) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Venue.ProtoReflect.Descriptor instead.
+func (*Venue) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Venue) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Venue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Venue) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Venue) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Venue) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Venue) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Venue) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Venue) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Stage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FestivalId    string                 `protobuf:"bytes,2,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Capacity      int32                  `protobuf:"varint,4,opt,name=capacity,proto3" json:"capacity,omitempty"` // 0 - не указана
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Stage) Reset() {
+	*x = Stage{}
+	mi := &file_proto_product_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stage) ProtoMessage() {}
+
+func (x *Stage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stage.ProtoReflect.Descriptor instead.
+func (*Stage) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Stage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Stage) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *Stage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Stage) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *Stage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Stage) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Festival struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Venue         *Venue                 `protobuf:"bytes,4,opt,name=venue,proto3" json:"venue,omitempty"`
+	StartDate     string                 `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"` // ГГГГ-ММ-ДД
+	EndDate       string                 `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`       // ГГГГ-ММ-ДД, включительно
+	Timezone      string                 `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`                    // Часовой пояс площадки (IANA)
+	Status        FestivalStatusProto    `protobuf:"varint,8,opt,name=status,proto3,enum=pb.FestivalStatusProto" json:"status,omitempty"`
+	Stages        []*Stage               `protobuf:"bytes,9,rep,name=stages,proto3" json:"stages,omitempty"` // Заполняется в GetFestival
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Festival) Reset() {
+	*x = Festival{}
+	mi := &file_proto_product_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Festival) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Festival) ProtoMessage() {}
+
+func (x *Festival) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Festival.ProtoReflect.Descriptor instead.
+func (*Festival) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Festival) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Festival) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Festival) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Festival) GetVenue() *Venue {
+	if x != nil {
+		return x.Venue
+	}
+	return nil
+}
+
+func (x *Festival) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *Festival) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *Festival) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Festival) GetStatus() FestivalStatusProto {
+	if x != nil {
+		return x.Status
+	}
+	return FestivalStatusProto_FESTIVAL_STATUS_UNSPECIFIED
+}
+
+func (x *Festival) GetStages() []*Stage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *Festival) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Festival) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// FestivalSummary - краткие сведения о фестивале в продуктах каталога
+type FestivalSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	StartDate     string                 `protobuf:"bytes,3,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,4,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Status        FestivalStatusProto    `protobuf:"varint,5,opt,name=status,proto3,enum=pb.FestivalStatusProto" json:"status,omitempty"`
+	VenueName     string                 `protobuf:"bytes,6,opt,name=venue_name,json=venueName,proto3" json:"venue_name,omitempty"`
+	City          string                 `protobuf:"bytes,7,opt,name=city,proto3" json:"city,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FestivalSummary) Reset() {
+	*x = FestivalSummary{}
+	mi := &file_proto_product_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FestivalSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FestivalSummary) ProtoMessage() {}
+
+func (x *FestivalSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FestivalSummary.ProtoReflect.Descriptor instead.
+func (*FestivalSummary) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *FestivalSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FestivalSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FestivalSummary) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *FestivalSummary) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *FestivalSummary) GetStatus() FestivalStatusProto {
+	if x != nil {
+		return x.Status
+	}
+	return FestivalStatusProto_FESTIVAL_STATUS_UNSPECIFIED
+}
+
+func (x *FestivalSummary) GetVenueName() string {
+	if x != nil {
+		return x.VenueName
+	}
+	return ""
+}
+
+func (x *FestivalSummary) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+type CreateVenueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	Country       string                 `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Capacity      int32                  `protobuf:"varint,5,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVenueRequest) Reset() {
+	*x = CreateVenueRequest{}
+	mi := &file_proto_product_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVenueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVenueRequest) ProtoMessage() {}
+
+func (x *CreateVenueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVenueRequest.ProtoReflect.Descriptor instead.
+func (*CreateVenueRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateVenueRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateVenueRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CreateVenueRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *CreateVenueRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *CreateVenueRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+type GetVenueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVenueRequest) Reset() {
+	*x = GetVenueRequest{}
+	mi := &file_proto_product_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVenueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVenueRequest) ProtoMessage() {}
+
+func (x *GetVenueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVenueRequest.ProtoReflect.Descriptor instead.
+func (*GetVenueRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetVenueRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListVenuesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVenuesRequest) Reset() {
+	*x = ListVenuesRequest{}
+	mi := &file_proto_product_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVenuesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVenuesRequest) ProtoMessage() {}
+
+func (x *ListVenuesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVenuesRequest.ProtoReflect.Descriptor instead.
+func (*ListVenuesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{29}
+}
+
+type ListVenuesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venues        []*Venue               `protobuf:"bytes,1,rep,name=venues,proto3" json:"venues,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVenuesResponse) Reset() {
+	*x = ListVenuesResponse{}
+	mi := &file_proto_product_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVenuesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVenuesResponse) ProtoMessage() {}
+
+func (x *ListVenuesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVenuesResponse.ProtoReflect.Descriptor instead.
+func (*ListVenuesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListVenuesResponse) GetVenues() []*Venue {
+	if x != nil {
+		return x.Venues
+	}
+	return nil
+}
+
+type UpdateVenueRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *wrapperspb.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Address       *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	City          *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Country       *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	Capacity      *wrapperspb.Int32Value  `protobuf:"bytes,6,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateVenueRequest) Reset() {
+	*x = UpdateVenueRequest{}
+	mi := &file_proto_product_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVenueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVenueRequest) ProtoMessage() {}
+
+func (x *UpdateVenueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVenueRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVenueRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UpdateVenueRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateVenueRequest) GetName() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *UpdateVenueRequest) GetAddress() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *UpdateVenueRequest) GetCity() *wrapperspb.StringValue {
+	if x != nil {
+		return x.City
+	}
+	return nil
+}
+
+func (x *UpdateVenueRequest) GetCountry() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Country
+	}
+	return nil
+}
+
+func (x *UpdateVenueRequest) GetCapacity() *wrapperspb.Int32Value {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+type DeleteVenueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVenueRequest) Reset() {
+	*x = DeleteVenueRequest{}
+	mi := &file_proto_product_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVenueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVenueRequest) ProtoMessage() {}
+
+func (x *DeleteVenueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVenueRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVenueRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteVenueRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type VenueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Venue         *Venue                 `protobuf:"bytes,1,opt,name=venue,proto3" json:"venue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VenueResponse) Reset() {
+	*x = VenueResponse{}
+	mi := &file_proto_product_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VenueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VenueResponse) ProtoMessage() {}
+
+func (x *VenueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VenueResponse.ProtoReflect.Descriptor instead.
+func (*VenueResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *VenueResponse) GetVenue() *Venue {
+	if x != nil {
+		return x.Venue
+	}
+	return nil
+}
+
+type StageInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Capacity      int32                  `protobuf:"varint,2,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StageInput) Reset() {
+	*x = StageInput{}
+	mi := &file_proto_product_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StageInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageInput) ProtoMessage() {}
+
+func (x *StageInput) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageInput.ProtoReflect.Descriptor instead.
+func (*StageInput) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *StageInput) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StageInput) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+type CreateFestivalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	VenueId       string                 `protobuf:"bytes,3,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	StartDate     string                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Timezone      string                 `protobuf:"bytes,6,opt,name=timezone,proto3" json:"timezone,omitempty"`                          // Пусто - UTC
+	Status        FestivalStatusProto    `protobuf:"varint,7,opt,name=status,proto3,enum=pb.FestivalStatusProto" json:"status,omitempty"` // Не задано - FESTIVAL_DRAFT
+	Stages        []*StageInput          `protobuf:"bytes,8,rep,name=stages,proto3" json:"stages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFestivalRequest) Reset() {
+	*x = CreateFestivalRequest{}
+	mi := &file_proto_product_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFestivalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFestivalRequest) ProtoMessage() {}
+
+func (x *CreateFestivalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFestivalRequest.ProtoReflect.Descriptor instead.
+func (*CreateFestivalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CreateFestivalRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateFestivalRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateFestivalRequest) GetVenueId() string {
+	if x != nil {
+		return x.VenueId
+	}
+	return ""
+}
+
+func (x *CreateFestivalRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *CreateFestivalRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *CreateFestivalRequest) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *CreateFestivalRequest) GetStatus() FestivalStatusProto {
+	if x != nil {
+		return x.Status
+	}
+	return FestivalStatusProto_FESTIVAL_STATUS_UNSPECIFIED
+}
+
+func (x *CreateFestivalRequest) GetStages() []*StageInput {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+type GetFestivalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFestivalRequest) Reset() {
+	*x = GetFestivalRequest{}
+	mi := &file_proto_product_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFestivalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFestivalRequest) ProtoMessage() {}
+
+func (x *GetFestivalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFestivalRequest.ProtoReflect.Descriptor instead.
+func (*GetFestivalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetFestivalRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListFestivalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        FestivalStatusProto    `protobuf:"varint,1,opt,name=status,proto3,enum=pb.FestivalStatusProto" json:"status,omitempty"` // Не задано - все статусы
+	VenueId       string                 `protobuf:"bytes,2,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	From          string                 `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"` // ГГГГ-ММ-ДД: фестивали, которые заканчиваются в этот день или позже
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFestivalsRequest) Reset() {
+	*x = ListFestivalsRequest{}
+	mi := &file_proto_product_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFestivalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFestivalsRequest) ProtoMessage() {}
+
+func (x *ListFestivalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFestivalsRequest.ProtoReflect.Descriptor instead.
+func (*ListFestivalsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListFestivalsRequest) GetStatus() FestivalStatusProto {
+	if x != nil {
+		return x.Status
+	}
+	return FestivalStatusProto_FESTIVAL_STATUS_UNSPECIFIED
+}
+
+func (x *ListFestivalsRequest) GetVenueId() string {
+	if x != nil {
+		return x.VenueId
+	}
+	return ""
+}
+
+func (x *ListFestivalsRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *ListFestivalsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+type ListFestivalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Festivals     []*Festival            `protobuf:"bytes,1,rep,name=festivals,proto3" json:"festivals,omitempty"` // В порядке начала
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFestivalsResponse) Reset() {
+	*x = ListFestivalsResponse{}
+	mi := &file_proto_product_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFestivalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFestivalsResponse) ProtoMessage() {}
+
+func (x *ListFestivalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFestivalsResponse.ProtoReflect.Descriptor instead.
+func (*ListFestivalsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListFestivalsResponse) GetFestivals() []*Festival {
+	if x != nil {
+		return x.Festivals
+	}
+	return nil
+}
+
+// UpdateFestivalRequest меняет только заданные поля; смена статуса проверяется по допустимым переходам
+type UpdateFestivalRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *wrapperspb.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	VenueId       *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	StartDate     *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *wrapperspb.StringValue `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Timezone      *wrapperspb.StringValue `protobuf:"bytes,7,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Status        FestivalStatusProto     `protobuf:"varint,8,opt,name=status,proto3,enum=pb.FestivalStatusProto" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFestivalRequest) Reset() {
+	*x = UpdateFestivalRequest{}
+	mi := &file_proto_product_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFestivalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFestivalRequest) ProtoMessage() {}
+
+func (x *UpdateFestivalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFestivalRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFestivalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UpdateFestivalRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateFestivalRequest) GetName() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *UpdateFestivalRequest) GetDescription() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Description
+	}
+	return nil
+}
+
+func (x *UpdateFestivalRequest) GetVenueId() *wrapperspb.StringValue {
+	if x != nil {
+		return x.VenueId
+	}
+	return nil
+}
+
+func (x *UpdateFestivalRequest) GetStartDate() *wrapperspb.StringValue {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *UpdateFestivalRequest) GetEndDate() *wrapperspb.StringValue {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *UpdateFestivalRequest) GetTimezone() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Timezone
+	}
+	return nil
+}
+
+func (x *UpdateFestivalRequest) GetStatus() FestivalStatusProto {
+	if x != nil {
+		return x.Status
+	}
+	return FestivalStatusProto_FESTIVAL_STATUS_UNSPECIFIED
+}
+
+type DeleteFestivalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFestivalRequest) Reset() {
+	*x = DeleteFestivalRequest{}
+	mi := &file_proto_product_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFestivalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFestivalRequest) ProtoMessage() {}
+
+func (x *DeleteFestivalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFestivalRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFestivalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteFestivalRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type FestivalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Festival      *Festival              `protobuf:"bytes,1,opt,name=festival,proto3" json:"festival,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FestivalResponse) Reset() {
+	*x = FestivalResponse{}
+	mi := &file_proto_product_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FestivalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FestivalResponse) ProtoMessage() {}
+
+func (x *FestivalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FestivalResponse.ProtoReflect.Descriptor instead.
+func (*FestivalResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *FestivalResponse) GetFestival() *Festival {
+	if x != nil {
+		return x.Festival
+	}
+	return nil
+}
+
+type ListStagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStagesRequest) Reset() {
+	*x = ListStagesRequest{}
+	mi := &file_proto_product_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStagesRequest) ProtoMessage() {}
+
+func (x *ListStagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStagesRequest.ProtoReflect.Descriptor instead.
+func (*ListStagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListStagesRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+type ListStagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stages        []*Stage               `protobuf:"bytes,1,rep,name=stages,proto3" json:"stages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListStagesResponse) Reset() {
+	*x = ListStagesResponse{}
+	mi := &file_proto_product_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListStagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListStagesResponse) ProtoMessage() {}
+
+func (x *ListStagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListStagesResponse.ProtoReflect.Descriptor instead.
+func (*ListStagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListStagesResponse) GetStages() []*Stage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+type CreateStageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Capacity      int32                  `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateStageRequest) Reset() {
+	*x = CreateStageRequest{}
+	mi := &file_proto_product_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateStageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateStageRequest) ProtoMessage() {}
+
+func (x *CreateStageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateStageRequest.ProtoReflect.Descriptor instead.
+func (*CreateStageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *CreateStageRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *CreateStageRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateStageRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+type UpdateStageRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	FestivalId    string                  `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Id            string                  `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Capacity      *wrapperspb.Int32Value  `protobuf:"bytes,4,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStageRequest) Reset() {
+	*x = UpdateStageRequest{}
+	mi := &file_proto_product_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStageRequest) ProtoMessage() {}
+
+func (x *UpdateStageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStageRequest.ProtoReflect.Descriptor instead.
+func (*UpdateStageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *UpdateStageRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *UpdateStageRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateStageRequest) GetName() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *UpdateStageRequest) GetCapacity() *wrapperspb.Int32Value {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+type DeleteStageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteStageRequest) Reset() {
+	*x = DeleteStageRequest{}
+	mi := &file_proto_product_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteStageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteStageRequest) ProtoMessage() {}
+
+func (x *DeleteStageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteStageRequest.ProtoReflect.Descriptor instead.
+func (*DeleteStageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DeleteStageRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *DeleteStageRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         *Stage                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StageResponse) Reset() {
+	*x = StageResponse{}
+	mi := &file_proto_product_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StageResponse) ProtoMessage() {}
+
+func (x *StageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StageResponse.ProtoReflect.Descriptor instead.
+func (*StageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *StageResponse) GetStage() *Stage {
+	if x != nil {
+		return x.Stage
+	}
+	return nil
+}
+
+type Artist struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Genre         string                 `protobuf:"bytes,3,opt,name=genre,proto3" json:"genre,omitempty"`
+	Country       string                 `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Artist) Reset() {
+	*x = Artist{}
+	mi := &file_proto_product_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Artist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artist) ProtoMessage() {}
+
+func (x *Artist) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artist.ProtoReflect.Descriptor instead.
+func (*Artist) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *Artist) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Artist) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Artist) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *Artist) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Artist) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Artist) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Artist) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Slot - выступление исполнителя на сцене фестиваля
+type Slot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FestivalId    string                 `protobuf:"bytes,2,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	StageId       string                 `protobuf:"bytes,3,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	StageName     string                 `protobuf:"bytes,4,opt,name=stage_name,json=stageName,proto3" json:"stage_name,omitempty"`
+	Artist        *Artist                `protobuf:"bytes,5,opt,name=artist,proto3" json:"artist,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Slot) Reset() {
+	*x = Slot{}
+	mi := &file_proto_product_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Slot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Slot) ProtoMessage() {}
+
+func (x *Slot) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Slot.ProtoReflect.Descriptor instead.
+func (*Slot) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Slot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Slot) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *Slot) GetStageId() string {
+	if x != nil {
+		return x.StageId
+	}
+	return ""
+}
+
+func (x *Slot) GetStageName() string {
+	if x != nil {
+		return x.StageName
+	}
+	return ""
+}
+
+func (x *Slot) GetArtist() *Artist {
+	if x != nil {
+		return x.Artist
+	}
+	return nil
+}
+
+func (x *Slot) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *Slot) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *Slot) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Slot) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ScheduleStage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StageId       string                 `protobuf:"bytes,1,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	StageName     string                 `protobuf:"bytes,2,opt,name=stage_name,json=stageName,proto3" json:"stage_name,omitempty"`
+	Slots         []*Slot                `protobuf:"bytes,3,rep,name=slots,proto3" json:"slots,omitempty"` // В порядке начала; пусто, если на сцене в этот день нет выступлений
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleStage) Reset() {
+	*x = ScheduleStage{}
+	mi := &file_proto_product_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleStage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleStage) ProtoMessage() {}
+
+func (x *ScheduleStage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleStage.ProtoReflect.Descriptor instead.
+func (*ScheduleStage) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ScheduleStage) GetStageId() string {
+	if x != nil {
+		return x.StageId
+	}
+	return ""
+}
+
+func (x *ScheduleStage) GetStageName() string {
+	if x != nil {
+		return x.StageName
+	}
+	return ""
+}
+
+func (x *ScheduleStage) GetSlots() []*Slot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+// ScheduleDay - день фестиваля; выступления до 06:00 по времени фестиваля относятся к предыдущему дню
+type ScheduleDay struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Date          string                 `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"` // ГГГГ-ММ-ДД
+	Stages        []*ScheduleStage       `protobuf:"bytes,2,rep,name=stages,proto3" json:"stages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleDay) Reset() {
+	*x = ScheduleDay{}
+	mi := &file_proto_product_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleDay) ProtoMessage() {}
+
+func (x *ScheduleDay) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleDay.ProtoReflect.Descriptor instead.
+func (*ScheduleDay) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ScheduleDay) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *ScheduleDay) GetStages() []*ScheduleStage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+type CreateArtistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Genre         string                 `protobuf:"bytes,2,opt,name=genre,proto3" json:"genre,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateArtistRequest) Reset() {
+	*x = CreateArtistRequest{}
+	mi := &file_proto_product_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateArtistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateArtistRequest) ProtoMessage() {}
+
+func (x *CreateArtistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateArtistRequest.ProtoReflect.Descriptor instead.
+func (*CreateArtistRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *CreateArtistRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateArtistRequest) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *CreateArtistRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *CreateArtistRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type GetArtistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArtistRequest) Reset() {
+	*x = GetArtistRequest{}
+	mi := &file_proto_product_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArtistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArtistRequest) ProtoMessage() {}
+
+func (x *GetArtistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArtistRequest.ProtoReflect.Descriptor instead.
+func (*GetArtistRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetArtistRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListArtistsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"` // Часть имени без учета регистра
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArtistsRequest) Reset() {
+	*x = ListArtistsRequest{}
+	mi := &file_proto_product_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArtistsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArtistsRequest) ProtoMessage() {}
+
+func (x *ListArtistsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArtistsRequest.ProtoReflect.Descriptor instead.
+func (*ListArtistsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ListArtistsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type ListArtistsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Artists       []*Artist              `protobuf:"bytes,1,rep,name=artists,proto3" json:"artists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListArtistsResponse) Reset() {
+	*x = ListArtistsResponse{}
+	mi := &file_proto_product_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListArtistsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListArtistsResponse) ProtoMessage() {}
+
+func (x *ListArtistsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListArtistsResponse.ProtoReflect.Descriptor instead.
+func (*ListArtistsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListArtistsResponse) GetArtists() []*Artist {
+	if x != nil {
+		return x.Artists
+	}
+	return nil
+}
+
+type UpdateArtistRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Id            string                  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *wrapperspb.StringValue `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Genre         *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=genre,proto3" json:"genre,omitempty"`
+	Country       *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Description   *wrapperspb.StringValue `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateArtistRequest) Reset() {
+	*x = UpdateArtistRequest{}
+	mi := &file_proto_product_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateArtistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateArtistRequest) ProtoMessage() {}
+
+func (x *UpdateArtistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateArtistRequest.ProtoReflect.Descriptor instead.
+func (*UpdateArtistRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *UpdateArtistRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateArtistRequest) GetName() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *UpdateArtistRequest) GetGenre() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Genre
+	}
+	return nil
+}
+
+func (x *UpdateArtistRequest) GetCountry() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Country
+	}
+	return nil
+}
+
+func (x *UpdateArtistRequest) GetDescription() *wrapperspb.StringValue {
+	if x != nil {
+		return x.Description
+	}
+	return nil
+}
+
+type DeleteArtistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteArtistRequest) Reset() {
+	*x = DeleteArtistRequest{}
+	mi := &file_proto_product_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteArtistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteArtistRequest) ProtoMessage() {}
+
+func (x *DeleteArtistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteArtistRequest.ProtoReflect.Descriptor instead.
+func (*DeleteArtistRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *DeleteArtistRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ArtistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Artist        *Artist                `protobuf:"bytes,1,opt,name=artist,proto3" json:"artist,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArtistResponse) Reset() {
+	*x = ArtistResponse{}
+	mi := &file_proto_product_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArtistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArtistResponse) ProtoMessage() {}
+
+func (x *ArtistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArtistResponse.ProtoReflect.Descriptor instead.
+func (*ArtistResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ArtistResponse) GetArtist() *Artist {
+	if x != nil {
+		return x.Artist
+	}
+	return nil
+}
+
+type ListSlotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	StageId       string                 `protobuf:"bytes,2,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	ArtistId      string                 `protobuf:"bytes,3,opt,name=artist_id,json=artistId,proto3" json:"artist_id,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"` // Выступления, которые заканчиваются позже
+	To            *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`     // Выступления, которые начинаются раньше
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSlotsRequest) Reset() {
+	*x = ListSlotsRequest{}
+	mi := &file_proto_product_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSlotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSlotsRequest) ProtoMessage() {}
+
+func (x *ListSlotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSlotsRequest.ProtoReflect.Descriptor instead.
+func (*ListSlotsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListSlotsRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *ListSlotsRequest) GetStageId() string {
+	if x != nil {
+		return x.StageId
+	}
+	return ""
+}
+
+func (x *ListSlotsRequest) GetArtistId() string {
+	if x != nil {
+		return x.ArtistId
+	}
+	return ""
+}
+
+func (x *ListSlotsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListSlotsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type ListSlotsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slots         []*Slot                `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"` // В порядке начала
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSlotsResponse) Reset() {
+	*x = ListSlotsResponse{}
+	mi := &file_proto_product_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSlotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSlotsResponse) ProtoMessage() {}
+
+func (x *ListSlotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSlotsResponse.ProtoReflect.Descriptor instead.
+func (*ListSlotsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ListSlotsResponse) GetSlots() []*Slot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+type CreateSlotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	StageId       string                 `protobuf:"bytes,2,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	ArtistId      string                 `protobuf:"bytes,3,opt,name=artist_id,json=artistId,proto3" json:"artist_id,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSlotRequest) Reset() {
+	*x = CreateSlotRequest{}
+	mi := &file_proto_product_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSlotRequest) ProtoMessage() {}
+
+func (x *CreateSlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSlotRequest.ProtoReflect.Descriptor instead.
+func (*CreateSlotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CreateSlotRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *CreateSlotRequest) GetStageId() string {
+	if x != nil {
+		return x.StageId
+	}
+	return ""
+}
+
+func (x *CreateSlotRequest) GetArtistId() string {
+	if x != nil {
+		return x.ArtistId
+	}
+	return ""
+}
+
+func (x *CreateSlotRequest) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *CreateSlotRequest) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+type UpdateSlotRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	FestivalId    string                  `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Id            string                  `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	StageId       *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	ArtistId      *wrapperspb.StringValue `protobuf:"bytes,4,opt,name=artist_id,json=artistId,proto3" json:"artist_id,omitempty"`
+	StartsAt      *timestamppb.Timestamp  `protobuf:"bytes,5,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"` // Не задано - не меняется
+	EndsAt        *timestamppb.Timestamp  `protobuf:"bytes,6,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`       // Не задано - не меняется
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSlotRequest) Reset() {
+	*x = UpdateSlotRequest{}
+	mi := &file_proto_product_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSlotRequest) ProtoMessage() {}
+
+func (x *UpdateSlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSlotRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSlotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *UpdateSlotRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *UpdateSlotRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSlotRequest) GetStageId() *wrapperspb.StringValue {
+	if x != nil {
+		return x.StageId
+	}
+	return nil
+}
+
+func (x *UpdateSlotRequest) GetArtistId() *wrapperspb.StringValue {
+	if x != nil {
+		return x.ArtistId
+	}
+	return nil
+}
+
+func (x *UpdateSlotRequest) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *UpdateSlotRequest) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+type DeleteSlotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSlotRequest) Reset() {
+	*x = DeleteSlotRequest{}
+	mi := &file_proto_product_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSlotRequest) ProtoMessage() {}
+
+func (x *DeleteSlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSlotRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSlotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *DeleteSlotRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *DeleteSlotRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SlotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slot          *Slot                  `protobuf:"bytes,1,opt,name=slot,proto3" json:"slot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlotResponse) Reset() {
+	*x = SlotResponse{}
+	mi := &file_proto_product_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlotResponse) ProtoMessage() {}
+
+func (x *SlotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlotResponse.ProtoReflect.Descriptor instead.
+func (*SlotResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *SlotResponse) GetSlot() *Slot {
+	if x != nil {
+		return x.Slot
+	}
+	return nil
+}
+
+type GetScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	Day           string                 `protobuf:"bytes,2,opt,name=day,proto3" json:"day,omitempty"` // ГГГГ-ММ-ДД; пусто - все дни фестиваля
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleRequest) Reset() {
+	*x = GetScheduleRequest{}
+	mi := &file_proto_product_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleRequest) ProtoMessage() {}
+
+func (x *GetScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetScheduleRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *GetScheduleRequest) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+type GetScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	FestivalName  string                 `protobuf:"bytes,2,opt,name=festival_name,json=festivalName,proto3" json:"festival_name,omitempty"`
+	Timezone      string                 `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Days          []*ScheduleDay         `protobuf:"bytes,4,rep,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetScheduleResponse) Reset() {
+	*x = GetScheduleResponse{}
+	mi := &file_proto_product_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetScheduleResponse) ProtoMessage() {}
+
+func (x *GetScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *GetScheduleResponse) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *GetScheduleResponse) GetFestivalName() string {
+	if x != nil {
+		return x.FestivalName
+	}
+	return ""
+}
+
+func (x *GetScheduleResponse) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GetScheduleResponse) GetDays() []*ScheduleDay {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+type ExportScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportScheduleRequest) Reset() {
+	*x = ExportScheduleRequest{}
+	mi := &file_proto_product_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportScheduleRequest) ProtoMessage() {}
+
+func (x *ExportScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportScheduleRequest.ProtoReflect.Descriptor instead.
+func (*ExportScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ExportScheduleRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+type ExportScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // text/calendar
+	Ics           string                 `protobuf:"bytes,3,opt,name=ics,proto3" json:"ics,omitempty"`                                    // Календарь iCalendar (RFC 5545)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportScheduleResponse) Reset() {
+	*x = ExportScheduleResponse{}
+	mi := &file_proto_product_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportScheduleResponse) ProtoMessage() {}
+
+func (x *ExportScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportScheduleResponse.ProtoReflect.Descriptor instead.
+func (*ExportScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ExportScheduleResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportScheduleResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *ExportScheduleResponse) GetIcs() string {
+	if x != nil {
+		return x.Ics
+	}
+	return ""
+}
+
+type GetMyScheduleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMyScheduleRequest) Reset() {
+	*x = GetMyScheduleRequest{}
+	mi := &file_proto_product_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMyScheduleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMyScheduleRequest) ProtoMessage() {}
+
+func (x *GetMyScheduleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMyScheduleRequest.ProtoReflect.Descriptor instead.
+func (*GetMyScheduleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetMyScheduleRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+// SlotClash - два выбранных выступления, которые идут одновременно
+type SlotClash struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SlotIds       []string               `protobuf:"bytes,1,rep,name=slot_ids,json=slotIds,proto3" json:"slot_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlotClash) Reset() {
+	*x = SlotClash{}
+	mi := &file_proto_product_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlotClash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlotClash) ProtoMessage() {}
+
+func (x *SlotClash) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlotClash.ProtoReflect.Descriptor instead.
+func (*SlotClash) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *SlotClash) GetSlotIds() []string {
+	if x != nil {
+		return x.SlotIds
+	}
+	return nil
+}
+
+type GetMyScheduleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slots         []*Slot                `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"` // В порядке начала
+	Clashes       []*SlotClash           `protobuf:"bytes,2,rep,name=clashes,proto3" json:"clashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMyScheduleResponse) Reset() {
+	*x = GetMyScheduleResponse{}
+	mi := &file_proto_product_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMyScheduleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMyScheduleResponse) ProtoMessage() {}
+
+func (x *GetMyScheduleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMyScheduleResponse.ProtoReflect.Descriptor instead.
+func (*GetMyScheduleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetMyScheduleResponse) GetSlots() []*Slot {
+	if x != nil {
+		return x.Slots
+	}
+	return nil
+}
+
+func (x *GetMyScheduleResponse) GetClashes() []*SlotClash {
+	if x != nil {
+		return x.Clashes
+	}
+	return nil
+}
+
+type MyScheduleSlotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FestivalId    string                 `protobuf:"bytes,1,opt,name=festival_id,json=festivalId,proto3" json:"festival_id,omitempty"`
+	SlotId        string                 `protobuf:"bytes,2,opt,name=slot_id,json=slotId,proto3" json:"slot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MyScheduleSlotRequest) Reset() {
+	*x = MyScheduleSlotRequest{}
+	mi := &file_proto_product_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MyScheduleSlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MyScheduleSlotRequest) ProtoMessage() {}
+
+func (x *MyScheduleSlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_product_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MyScheduleSlotRequest.ProtoReflect.Descriptor instead.
+func (*MyScheduleSlotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_product_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *MyScheduleSlotRequest) GetFestivalId() string {
+	if x != nil {
+		return x.FestivalId
+	}
+	return ""
+}
+
+func (x *MyScheduleSlotRequest) GetSlotId() string {
+	if x != nil {
+		return x.SlotId
+	}
+	return ""
+}
+
+var File_proto_product_proto protoreflect.FileDescriptor
+
+const file_proto_product_proto_rawDesc = "" +
+	"\n" +
+	"\x13proto/product.proto\x12\x02pb\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/wrappers.proto\x1a\x1bgoogle/protobuf/empty.proto\"\x9d\x03\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12(\n" +
+	"\x04type\x18\x05 \x01(\x0e2\x14.pb.ProductTypeProtoR\x04type\x12\x14\n" +
+	"\x05stock\x18\x06 \x01(\x05R\x05stock\x12\x1f\n" +
+	"\vfestival_id\x18\a \x01(\tR\n" +
+	"festivalId\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12/\n" +
+	"\bfestival\x18\n" +
+	" \x01(\v2\x13.pb.FestivalSummaryR\bfestival\x12.\n" +
+	"\bvariants\x18\v \x03(\v2\x12.pb.ProductVariantR\bvariants\"\x94\x03\n" +
+	"\x0eProductVariant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x10\n" +
+	"\x03sku\x18\x03 \x01(\tR\x03sku\x12B\n" +
+	"\n" +
+	"attributes\x18\x04 \x03(\v2\".pb.ProductVariant.AttributesEntryR\n" +
+	"attributes\x122\n" +
+	"\x05price\x18\x05 \x01(\v2\x1c.google.protobuf.DoubleValueR\x05price\x12\x14\n" +
+	"\x05stock\x18\x06 \x01(\x05R\x05stock\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x89\x02\n" +
+	"\x13ProductVariantInput\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12G\n" +
+	"\n" +
+	"attributes\x18\x03 \x03(\v2'.pb.ProductVariantInput.AttributesEntryR\n" +
+	"attributes\x122\n" +
+	"\x05price\x18\x04 \x01(\v2\x1c.google.protobuf.DoubleValueR\x05price\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x05R\x05stock\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"I\n" +
+	"\x12ProductVariantList\x123\n" +
+	"\bvariants\x18\x01 \x03(\v2\x17.pb.ProductVariantInputR\bvariants\"\xf8\x01\n" +
+	"\x14CreateProductRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12(\n" +
+	"\x04type\x18\x04 \x01(\x0e2\x14.pb.ProductTypeProtoR\x04type\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1f\n" +
+	"\vfestival_id\x18\x06 \x01(\tR\n" +
+	"festivalId\x123\n" +
+	"\bvariants\x18\a \x03(\v2\x17.pb.ProductVariantInputR\bvariants\">\n" +
+	"\x15CreateProductResponse\x12%\n" +
+	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\"#\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
+	"\x12GetProductResponse\x12%\n" +
+	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\"\x84\x03\n" +
+	"\x13ListProductsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12(\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x14.pb.ProductTypeProtoR\x04type\x12\x1f\n" +
+	"\vfestival_id\x18\x04 \x01(\tR\n" +
+	"festivalId\x129\n" +
+	"\tmin_price\x18\x05 \x01(\v2\x1c.google.protobuf.DoubleValueR\bminPrice\x129\n" +
+	"\tmax_price\x18\x06 \x01(\v2\x1c.google.protobuf.DoubleValueR\bmaxPrice\x12\x19\n" +
+	"\bin_stock\x18\a \x01(\bR\ainStock\x12\x14\n" +
+	"\x05query\x18\b \x01(\tR\x05query\x12\x12\n" +
+	"\x04sort\x18\t \x01(\tR\x04sort\x12+\n" +
+	"\x11include_festivals\x18\n" +
+	" \x01(\bR\x10includeFestivals\"}\n" +
+	"\x14ListProductsResponse\x12'\n" +
+	"\bproducts\x18\x01 \x03(\v2\v.pb.ProductR\bproducts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\"\xbd\x01\n" +
+	"\x15SearchProductsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x12\n" +
+	"\x04lang\x18\x02 \x01(\tR\x04lang\x12(\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x14.pb.ProductTypeProtoR\x04type\x12\x1f\n" +
+	"\vfestival_id\x18\x04 \x01(\tR\n" +
+	"festivalId\x12\x19\n" +
+	"\bin_stock\x18\x05 \x01(\bR\ainStock\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\"\x91\x01\n" +
+	"\x13ProductSearchResult\x12%\n" +
+	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x01R\x04rank\x12%\n" +
+	"\x0ename_highlight\x18\x03 \x01(\tR\rnameHighlight\x12\x18\n" +
+	"\asnippet\x18\x04 \x01(\tR\asnippet\"K\n" +
+	"\x16SearchProductsResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.pb.ProductSearchResultR\aresults\"\x9c\x03\n" +
+	"\x14UpdateProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
 	"\x04name\x18\x02 \x01(\v2\x1c.google.protobuf.StringValueR\x04name\x12>\n" +
 	"\vdescription\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\vdescription\x122\n" +
 	"\x05price\x18\x04 \x01(\v2\x1c.google.protobuf.DoubleValueR\x05price\x12(\n" +
 	"\x04type\x18\x05 \x01(\x0e2\x14.pb.ProductTypeProtoR\x04type\x121\n" +
 	"\x05stock\x18\x06 \x01(\v2\x1b.google.protobuf.Int32ValueR\x05stock\x12=\n" +
 	"\vfestival_id\x18\a \x01(\v2\x1c.google.protobuf.StringValueR\n" +
-	"festivalId\">\n" +
+	"festivalId\x122\n" +
+	"\bvariants\x18\b \x01(\v2\x16.pb.ProductVariantListR\bvariants\">\n" +
 	"\x15UpdateProductResponse\x12%\n" +
 	"\aproduct\x18\x01 \x01(\v2\v.pb.ProductR\aproduct\"&\n" +
 	"\x14DeleteProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id*S\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xa0\x02\n" +
+	"\vReservation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\b \x01(\tR\tvariantId\"\x8a\x01\n" +
+	"\x13ReserveStockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\tR\tvariantId\"I\n" +
+	"\x14ReserveStockResponse\x121\n" +
+	"\vreservation\x18\x01 \x01(\v2\x0f.pb.ReservationR\vreservation\"*\n" +
+	"\x18CommitReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"N\n" +
+	"\x19CommitReservationResponse\x121\n" +
+	"\vreservation\x18\x01 \x01(\v2\x0f.pb.ReservationR\vreservation\"+\n" +
+	"\x19ReleaseReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"O\n" +
+	"\x1aReleaseReservationResponse\x121\n" +
+	"\vreservation\x18\x01 \x01(\v2\x0f.pb.ReservationR\vreservation\"\x85\x02\n" +
+	"\x05Venue\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x18\n" +
+	"\acountry\x18\x05 \x01(\tR\acountry\x12\x1a\n" +
+	"\bcapacity\x18\x06 \x01(\x05R\bcapacity\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xde\x01\n" +
+	"\x05Stage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vfestival_id\x18\x02 \x01(\tR\n" +
+	"festivalId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcapacity\x18\x04 \x01(\x05R\bcapacity\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x91\x03\n" +
+	"\bFestival\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\x05venue\x18\x04 \x01(\v2\t.pb.VenueR\x05venue\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x06 \x01(\tR\aendDate\x12\x1a\n" +
+	"\btimezone\x18\a \x01(\tR\btimezone\x12/\n" +
+	"\x06status\x18\b \x01(\x0e2\x17.pb.FestivalStatusProtoR\x06status\x12!\n" +
+	"\x06stages\x18\t \x03(\v2\t.pb.StageR\x06stages\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xd3\x01\n" +
+	"\x0fFestivalSummary\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x03 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x04 \x01(\tR\aendDate\x12/\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x17.pb.FestivalStatusProtoR\x06status\x12\x1d\n" +
+	"\n" +
+	"venue_name\x18\x06 \x01(\tR\tvenueName\x12\x12\n" +
+	"\x04city\x18\a \x01(\tR\x04city\"\x8c\x01\n" +
+	"\x12CreateVenueRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12\x1a\n" +
+	"\bcapacity\x18\x05 \x01(\x05R\bcapacity\"!\n" +
+	"\x0fGetVenueRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x13\n" +
+	"\x11ListVenuesRequest\"7\n" +
+	"\x12ListVenuesResponse\x12!\n" +
+	"\x06venues\x18\x01 \x03(\v2\t.pb.VenueR\x06venues\"\xb1\x02\n" +
+	"\x12UpdateVenueRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x04name\x18\x02 \x01(\v2\x1c.google.protobuf.StringValueR\x04name\x126\n" +
+	"\aaddress\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\aaddress\x120\n" +
+	"\x04city\x18\x04 \x01(\v2\x1c.google.protobuf.StringValueR\x04city\x126\n" +
+	"\acountry\x18\x05 \x01(\v2\x1c.google.protobuf.StringValueR\acountry\x127\n" +
+	"\bcapacity\x18\x06 \x01(\v2\x1b.google.protobuf.Int32ValueR\bcapacity\"$\n" +
+	"\x12DeleteVenueRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"0\n" +
+	"\rVenueResponse\x12\x1f\n" +
+	"\x05venue\x18\x01 \x01(\v2\t.pb.VenueR\x05venue\"<\n" +
+	"\n" +
+	"StageInput\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcapacity\x18\x02 \x01(\x05R\bcapacity\"\x97\x02\n" +
+	"\x15CreateFestivalRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x19\n" +
+	"\bvenue_id\x18\x03 \x01(\tR\avenueId\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x05 \x01(\tR\aendDate\x12\x1a\n" +
+	"\btimezone\x18\x06 \x01(\tR\btimezone\x12/\n" +
+	"\x06status\x18\a \x01(\x0e2\x17.pb.FestivalStatusProtoR\x06status\x12&\n" +
+	"\x06stages\x18\b \x03(\v2\x0e.pb.StageInputR\x06stages\"$\n" +
+	"\x12GetFestivalRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8a\x01\n" +
+	"\x14ListFestivalsRequest\x12/\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x17.pb.FestivalStatusProtoR\x06status\x12\x19\n" +
+	"\bvenue_id\x18\x02 \x01(\tR\avenueId\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x12\n" +
+	"\x04from\x18\x04 \x01(\tR\x04from\"C\n" +
+	"\x15ListFestivalsResponse\x12*\n" +
+	"\tfestivals\x18\x01 \x03(\v2\f.pb.FestivalR\tfestivals\"\xb3\x03\n" +
+	"\x15UpdateFestivalRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x04name\x18\x02 \x01(\v2\x1c.google.protobuf.StringValueR\x04name\x12>\n" +
+	"\vdescription\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\vdescription\x127\n" +
+	"\bvenue_id\x18\x04 \x01(\v2\x1c.google.protobuf.StringValueR\avenueId\x12;\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\v2\x1c.google.protobuf.StringValueR\tstartDate\x127\n" +
+	"\bend_date\x18\x06 \x01(\v2\x1c.google.protobuf.StringValueR\aendDate\x128\n" +
+	"\btimezone\x18\a \x01(\v2\x1c.google.protobuf.StringValueR\btimezone\x12/\n" +
+	"\x06status\x18\b \x01(\x0e2\x17.pb.FestivalStatusProtoR\x06status\"'\n" +
+	"\x15DeleteFestivalRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"<\n" +
+	"\x10FestivalResponse\x12(\n" +
+	"\bfestival\x18\x01 \x01(\v2\f.pb.FestivalR\bfestival\"4\n" +
+	"\x11ListStagesRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\"7\n" +
+	"\x12ListStagesResponse\x12!\n" +
+	"\x06stages\x18\x01 \x03(\v2\t.pb.StageR\x06stages\"e\n" +
+	"\x12CreateStageRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcapacity\x18\x03 \x01(\x05R\bcapacity\"\xb0\x01\n" +
+	"\x12UpdateStageRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x120\n" +
+	"\x04name\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\x04name\x127\n" +
+	"\bcapacity\x18\x04 \x01(\v2\x1b.google.protobuf.Int32ValueR\bcapacity\"E\n" +
+	"\x12DeleteStageRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"0\n" +
+	"\rStageResponse\x12\x1f\n" +
+	"\x05stage\x18\x01 \x01(\v2\t.pb.StageR\x05stage\"\xf4\x01\n" +
+	"\x06Artist\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05genre\x18\x03 \x01(\tR\x05genre\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xf9\x02\n" +
+	"\x04Slot\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vfestival_id\x18\x02 \x01(\tR\n" +
+	"festivalId\x12\x19\n" +
+	"\bstage_id\x18\x03 \x01(\tR\astageId\x12\x1d\n" +
+	"\n" +
+	"stage_name\x18\x04 \x01(\tR\tstageName\x12\"\n" +
+	"\x06artist\x18\x05 \x01(\v2\n" +
+	".pb.ArtistR\x06artist\x127\n" +
+	"\tstarts_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"i\n" +
+	"\rScheduleStage\x12\x19\n" +
+	"\bstage_id\x18\x01 \x01(\tR\astageId\x12\x1d\n" +
+	"\n" +
+	"stage_name\x18\x02 \x01(\tR\tstageName\x12\x1e\n" +
+	"\x05slots\x18\x03 \x03(\v2\b.pb.SlotR\x05slots\"L\n" +
+	"\vScheduleDay\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12)\n" +
+	"\x06stages\x18\x02 \x03(\v2\x11.pb.ScheduleStageR\x06stages\"{\n" +
+	"\x13CreateArtistRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05genre\x18\x02 \x01(\tR\x05genre\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\"\"\n" +
+	"\x10GetArtistRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"*\n" +
+	"\x12ListArtistsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\";\n" +
+	"\x13ListArtistsResponse\x12$\n" +
+	"\aartists\x18\x01 \x03(\v2\n" +
+	".pb.ArtistR\aartists\"\x83\x02\n" +
+	"\x13UpdateArtistRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x04name\x18\x02 \x01(\v2\x1c.google.protobuf.StringValueR\x04name\x122\n" +
+	"\x05genre\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\x05genre\x126\n" +
+	"\acountry\x18\x04 \x01(\v2\x1c.google.protobuf.StringValueR\acountry\x12>\n" +
+	"\vdescription\x18\x05 \x01(\v2\x1c.google.protobuf.StringValueR\vdescription\"%\n" +
+	"\x13DeleteArtistRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"4\n" +
+	"\x0eArtistResponse\x12\"\n" +
+	"\x06artist\x18\x01 \x01(\v2\n" +
+	".pb.ArtistR\x06artist\"\xc7\x01\n" +
+	"\x10ListSlotsRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x19\n" +
+	"\bstage_id\x18\x02 \x01(\tR\astageId\x12\x1b\n" +
+	"\tartist_id\x18\x03 \x01(\tR\bartistId\x12.\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"3\n" +
+	"\x11ListSlotsResponse\x12\x1e\n" +
+	"\x05slots\x18\x01 \x03(\v2\b.pb.SlotR\x05slots\"\xda\x01\n" +
+	"\x11CreateSlotRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x19\n" +
+	"\bstage_id\x18\x02 \x01(\tR\astageId\x12\x1b\n" +
+	"\tartist_id\x18\x03 \x01(\tR\bartistId\x127\n" +
+	"\tstarts_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\"\xa6\x02\n" +
+	"\x11UpdateSlotRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x127\n" +
+	"\bstage_id\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\astageId\x129\n" +
+	"\tartist_id\x18\x04 \x01(\v2\x1c.google.protobuf.StringValueR\bartistId\x127\n" +
+	"\tstarts_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x123\n" +
+	"\aends_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\"D\n" +
+	"\x11DeleteSlotRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\",\n" +
+	"\fSlotResponse\x12\x1c\n" +
+	"\x04slot\x18\x01 \x01(\v2\b.pb.SlotR\x04slot\"G\n" +
+	"\x12GetScheduleRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x10\n" +
+	"\x03day\x18\x02 \x01(\tR\x03day\"\x9c\x01\n" +
+	"\x13GetScheduleResponse\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12#\n" +
+	"\rfestival_name\x18\x02 \x01(\tR\ffestivalName\x12\x1a\n" +
+	"\btimezone\x18\x03 \x01(\tR\btimezone\x12#\n" +
+	"\x04days\x18\x04 \x03(\v2\x0f.pb.ScheduleDayR\x04days\"8\n" +
+	"\x15ExportScheduleRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\"i\n" +
+	"\x16ExportScheduleResponse\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x10\n" +
+	"\x03ics\x18\x03 \x01(\tR\x03ics\"7\n" +
+	"\x14GetMyScheduleRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\"&\n" +
+	"\tSlotClash\x12\x19\n" +
+	"\bslot_ids\x18\x01 \x03(\tR\aslotIds\"`\n" +
+	"\x15GetMyScheduleResponse\x12\x1e\n" +
+	"\x05slots\x18\x01 \x03(\v2\b.pb.SlotR\x05slots\x12'\n" +
+	"\aclashes\x18\x02 \x03(\v2\r.pb.SlotClashR\aclashes\"Q\n" +
+	"\x15MyScheduleSlotRequest\x12\x1f\n" +
+	"\vfestival_id\x18\x01 \x01(\tR\n" +
+	"festivalId\x12\x17\n" +
+	"\aslot_id\x18\x02 \x01(\tR\x06slotId*S\n" +
 	"\x10ProductTypeProto\x12\"\n" +
 	"\x1ePRODUCT_TYPE_PROTO_UNSPECIFIED\x10\x00\x12\n" +
 	"\n" +
 	"\x06TICKET\x10\x01\x12\x0f\n" +
-	"\vMERCHANDISE\x10\x022\xdf\x02\n" +
+	"\vMERCHANDISE\x10\x02*\x8e\x01\n" +
+	"\x13FestivalStatusProto\x12\x1f\n" +
+	"\x1bFESTIVAL_STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eFESTIVAL_DRAFT\x10\x01\x12\x14\n" +
+	"\x10FESTIVAL_ON_SALE\x10\x02\x12\x15\n" +
+	"\x11FESTIVAL_SOLD_OUT\x10\x03\x12\x15\n" +
+	"\x11FESTIVAL_FINISHED\x10\x042\x92\x05\n" +
 	"\x0eProductService\x12D\n" +
 	"\rCreateProduct\x12\x18.pb.CreateProductRequest\x1a\x19.pb.CreateProductResponse\x12;\n" +
 	"\n" +
 	"GetProduct\x12\x15.pb.GetProductRequest\x1a\x16.pb.GetProductResponse\x12A\n" +
-	"\fListProducts\x12\x17.pb.ListProductsRequest\x1a\x18.pb.ListProductsResponse\x12D\n" +
+	"\fListProducts\x12\x17.pb.ListProductsRequest\x1a\x18.pb.ListProductsResponse\x12G\n" +
+	"\x0eSearchProducts\x12\x19.pb.SearchProductsRequest\x1a\x1a.pb.SearchProductsResponse\x12D\n" +
 	"\rUpdateProduct\x12\x18.pb.UpdateProductRequest\x1a\x19.pb.UpdateProductResponse\x12A\n" +
-	"\rDeleteProduct\x12\x18.pb.DeleteProductRequest\x1a\x16.google.protobuf.EmptyB/Z-github.com/Hayzerr/go-microservice-project/pbb\x06proto3"
+	"\rDeleteProduct\x12\x18.pb.DeleteProductRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\fReserveStock\x12\x17.pb.ReserveStockRequest\x1a\x18.pb.ReserveStockResponse\x12P\n" +
+	"\x11CommitReservation\x12\x1c.pb.CommitReservationRequest\x1a\x1d.pb.CommitReservationResponse\x12S\n" +
+	"\x12ReleaseReservation\x12\x1d.pb.ReleaseReservationRequest\x1a\x1e.pb.ReleaseReservationResponse2\xf3\x06\n" +
+	"\x0fFestivalService\x128\n" +
+	"\vCreateVenue\x12\x16.pb.CreateVenueRequest\x1a\x11.pb.VenueResponse\x122\n" +
+	"\bGetVenue\x12\x13.pb.GetVenueRequest\x1a\x11.pb.VenueResponse\x12;\n" +
+	"\n" +
+	"ListVenues\x12\x15.pb.ListVenuesRequest\x1a\x16.pb.ListVenuesResponse\x128\n" +
+	"\vUpdateVenue\x12\x16.pb.UpdateVenueRequest\x1a\x11.pb.VenueResponse\x12=\n" +
+	"\vDeleteVenue\x12\x16.pb.DeleteVenueRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\x0eCreateFestival\x12\x19.pb.CreateFestivalRequest\x1a\x14.pb.FestivalResponse\x12;\n" +
+	"\vGetFestival\x12\x16.pb.GetFestivalRequest\x1a\x14.pb.FestivalResponse\x12D\n" +
+	"\rListFestivals\x12\x18.pb.ListFestivalsRequest\x1a\x19.pb.ListFestivalsResponse\x12A\n" +
+	"\x0eUpdateFestival\x12\x19.pb.UpdateFestivalRequest\x1a\x14.pb.FestivalResponse\x12C\n" +
+	"\x0eDeleteFestival\x12\x19.pb.DeleteFestivalRequest\x1a\x16.google.protobuf.Empty\x12;\n" +
+	"\n" +
+	"ListStages\x12\x15.pb.ListStagesRequest\x1a\x16.pb.ListStagesResponse\x128\n" +
+	"\vCreateStage\x12\x16.pb.CreateStageRequest\x1a\x11.pb.StageResponse\x128\n" +
+	"\vUpdateStage\x12\x16.pb.UpdateStageRequest\x1a\x11.pb.StageResponse\x12=\n" +
+	"\vDeleteStage\x12\x16.pb.DeleteStageRequest\x1a\x16.google.protobuf.Empty2\xd0\a\n" +
+	"\rLineupService\x12;\n" +
+	"\fCreateArtist\x12\x17.pb.CreateArtistRequest\x1a\x12.pb.ArtistResponse\x125\n" +
+	"\tGetArtist\x12\x14.pb.GetArtistRequest\x1a\x12.pb.ArtistResponse\x12>\n" +
+	"\vListArtists\x12\x16.pb.ListArtistsRequest\x1a\x17.pb.ListArtistsResponse\x12;\n" +
+	"\fUpdateArtist\x12\x17.pb.UpdateArtistRequest\x1a\x12.pb.ArtistResponse\x12?\n" +
+	"\fDeleteArtist\x12\x17.pb.DeleteArtistRequest\x1a\x16.google.protobuf.Empty\x128\n" +
+	"\tListSlots\x12\x14.pb.ListSlotsRequest\x1a\x15.pb.ListSlotsResponse\x125\n" +
+	"\n" +
+	"CreateSlot\x12\x15.pb.CreateSlotRequest\x1a\x10.pb.SlotResponse\x125\n" +
+	"\n" +
+	"UpdateSlot\x12\x15.pb.UpdateSlotRequest\x1a\x10.pb.SlotResponse\x12;\n" +
+	"\n" +
+	"DeleteSlot\x12\x15.pb.DeleteSlotRequest\x1a\x16.google.protobuf.Empty\x12>\n" +
+	"\vGetSchedule\x12\x16.pb.GetScheduleRequest\x1a\x17.pb.GetScheduleResponse\x12G\n" +
+	"\x0eExportSchedule\x12\x19.pb.ExportScheduleRequest\x1a\x1a.pb.ExportScheduleResponse\x12D\n" +
+	"\rGetMySchedule\x12\x18.pb.GetMyScheduleRequest\x1a\x19.pb.GetMyScheduleResponse\x12H\n" +
+	"\x10ExportMySchedule\x12\x18.pb.GetMyScheduleRequest\x1a\x1a.pb.ExportScheduleResponse\x12D\n" +
+	"\x0fAddToMySchedule\x12\x19.pb.MyScheduleSlotRequest\x1a\x16.google.protobuf.Empty\x12I\n" +
+	"\x14RemoveFromMySchedule\x12\x19.pb.MyScheduleSlotRequest\x1a\x16.google.protobuf.EmptyB/Z-github.com/Hayzerr/go-microservice-project/pbb\x06proto3"
 
 var (
 	file_proto_product_proto_rawDescOnce sync.Once
@@ -730,56 +5162,271 @@ func file_proto_product_proto_rawDescGZIP() []byte {
 	return file_proto_product_proto_rawDescData
 }
 
-var file_proto_product_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_product_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_proto_product_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_product_proto_msgTypes = make([]protoimpl.MessageInfo, 75)
 var file_proto_product_proto_goTypes = []any{
-	(ProductTypeProto)(0),          // 0: pb.ProductTypeProto
-	(*Product)(nil),                // 1: pb.Product
-	(*CreateProductRequest)(nil),   // 2: pb.CreateProductRequest
-	(*CreateProductResponse)(nil),  // 3: pb.CreateProductResponse
-	(*GetProductRequest)(nil),      // 4: pb.GetProductRequest
-	(*GetProductResponse)(nil),     // 5: pb.GetProductResponse
-	(*ListProductsRequest)(nil),    // 6: pb.ListProductsRequest
-	(*ListProductsResponse)(nil),   // 7: pb.ListProductsResponse
-	(*UpdateProductRequest)(nil),   // 8: pb.UpdateProductRequest
-	(*UpdateProductResponse)(nil),  // 9: pb.UpdateProductResponse
-	(*DeleteProductRequest)(nil),   // 10: pb.DeleteProductRequest
-	(*timestamppb.Timestamp)(nil),  // 11: google.protobuf.Timestamp
-	(*wrapperspb.StringValue)(nil), // 12: google.protobuf.StringValue
-	(*wrapperspb.DoubleValue)(nil), // 13: google.protobuf.DoubleValue
-	(*wrapperspb.Int32Value)(nil),  // 14: google.protobuf.Int32Value
-	(*emptypb.Empty)(nil),          // 15: google.protobuf.Empty
+	(ProductTypeProto)(0),              // 0: pb.ProductTypeProto
+	(FestivalStatusProto)(0),           // 1: pb.FestivalStatusProto
+	(*Product)(nil),                    // 2: pb.Product
+	(*ProductVariant)(nil),             // 3: pb.ProductVariant
+	(*ProductVariantInput)(nil),        // 4: pb.ProductVariantInput
+	(*ProductVariantList)(nil),         // 5: pb.ProductVariantList
+	(*CreateProductRequest)(nil),       // 6: pb.CreateProductRequest
+	(*CreateProductResponse)(nil),      // 7: pb.CreateProductResponse
+	(*GetProductRequest)(nil),          // 8: pb.GetProductRequest
+	(*GetProductResponse)(nil),         // 9: pb.GetProductResponse
+	(*ListProductsRequest)(nil),        // 10: pb.ListProductsRequest
+	(*ListProductsResponse)(nil),       // 11: pb.ListProductsResponse
+	(*SearchProductsRequest)(nil),      // 12: pb.SearchProductsRequest
+	(*ProductSearchResult)(nil),        // 13: pb.ProductSearchResult
+	(*SearchProductsResponse)(nil),     // 14: pb.SearchProductsResponse
+	(*UpdateProductRequest)(nil),       // 15: pb.UpdateProductRequest
+	(*UpdateProductResponse)(nil),      // 16: pb.UpdateProductResponse
+	(*DeleteProductRequest)(nil),       // 17: pb.DeleteProductRequest
+	(*Reservation)(nil),                // 18: pb.Reservation
+	(*ReserveStockRequest)(nil),        // 19: pb.ReserveStockRequest
+	(*ReserveStockResponse)(nil),       // 20: pb.ReserveStockResponse
+	(*CommitReservationRequest)(nil),   // 21: pb.CommitReservationRequest
+	(*CommitReservationResponse)(nil),  // 22: pb.CommitReservationResponse
+	(*ReleaseReservationRequest)(nil),  // 23: pb.ReleaseReservationRequest
+	(*ReleaseReservationResponse)(nil), // 24: pb.ReleaseReservationResponse
+	(*Venue)(nil),                      // 25: pb.Venue
+	(*Stage)(nil),                      // 26: pb.Stage
+	(*Festival)(nil),                   // 27: pb.Festival
+	(*FestivalSummary)(nil),            // 28: pb.FestivalSummary
+	(*CreateVenueRequest)(nil),         // 29: pb.CreateVenueRequest
+	(*GetVenueRequest)(nil),            // 30: pb.GetVenueRequest
+	(*ListVenuesRequest)(nil),          // 31: pb.ListVenuesRequest
+	(*ListVenuesResponse)(nil),         // 32: pb.ListVenuesResponse
+	(*UpdateVenueRequest)(nil),         // 33: pb.UpdateVenueRequest
+	(*DeleteVenueRequest)(nil),         // 34: pb.DeleteVenueRequest
+	(*VenueResponse)(nil),              // 35: pb.VenueResponse
+	(*StageInput)(nil),                 // 36: pb.StageInput
+	(*CreateFestivalRequest)(nil),      // 37: pb.CreateFestivalRequest
+	(*GetFestivalRequest)(nil),         // 38: pb.GetFestivalRequest
+	(*ListFestivalsRequest)(nil),       // 39: pb.ListFestivalsRequest
+	(*ListFestivalsResponse)(nil),      // 40: pb.ListFestivalsResponse
+	(*UpdateFestivalRequest)(nil),      // 41: pb.UpdateFestivalRequest
+	(*DeleteFestivalRequest)(nil),      // 42: pb.DeleteFestivalRequest
+	(*FestivalResponse)(nil),           // 43: pb.FestivalResponse
+	(*ListStagesRequest)(nil),          // 44: pb.ListStagesRequest
+	(*ListStagesResponse)(nil),         // 45: pb.ListStagesResponse
+	(*CreateStageRequest)(nil),         // 46: pb.CreateStageRequest
+	(*UpdateStageRequest)(nil),         // 47: pb.UpdateStageRequest
+	(*DeleteStageRequest)(nil),         // 48: pb.DeleteStageRequest
+	(*StageResponse)(nil),              // 49: pb.StageResponse
+	(*Artist)(nil),                     // 50: pb.Artist
+	(*Slot)(nil),                       // 51: pb.Slot
+	(*ScheduleStage)(nil),              // 52: pb.ScheduleStage
+	(*ScheduleDay)(nil),                // 53: pb.ScheduleDay
+	(*CreateArtistRequest)(nil),        // 54: pb.CreateArtistRequest
+	(*GetArtistRequest)(nil),           // 55: pb.GetArtistRequest
+	(*ListArtistsRequest)(nil),         // 56: pb.ListArtistsRequest
+	(*ListArtistsResponse)(nil),        // 57: pb.ListArtistsResponse
+	(*UpdateArtistRequest)(nil),        // 58: pb.UpdateArtistRequest
+	(*DeleteArtistRequest)(nil),        // 59: pb.DeleteArtistRequest
+	(*ArtistResponse)(nil),             // 60: pb.ArtistResponse
+	(*ListSlotsRequest)(nil),           // 61: pb.ListSlotsRequest
+	(*ListSlotsResponse)(nil),          // 62: pb.ListSlotsResponse
+	(*CreateSlotRequest)(nil),          // 63: pb.CreateSlotRequest
+	(*UpdateSlotRequest)(nil),          // 64: pb.UpdateSlotRequest
+	(*DeleteSlotRequest)(nil),          // 65: pb.DeleteSlotRequest
+	(*SlotResponse)(nil),               // 66: pb.SlotResponse
+	(*GetScheduleRequest)(nil),         // 67: pb.GetScheduleRequest
+	(*GetScheduleResponse)(nil),        // 68: pb.GetScheduleResponse
+	(*ExportScheduleRequest)(nil),      // 69: pb.ExportScheduleRequest
+	(*ExportScheduleResponse)(nil),     // 70: pb.ExportScheduleResponse
+	(*GetMyScheduleRequest)(nil),       // 71: pb.GetMyScheduleRequest
+	(*SlotClash)(nil),                  // 72: pb.SlotClash
+	(*GetMyScheduleResponse)(nil),      // 73: pb.GetMyScheduleResponse
+	(*MyScheduleSlotRequest)(nil),      // 74: pb.MyScheduleSlotRequest
+	nil,                                // 75: pb.ProductVariant.AttributesEntry
+	nil,                                // 76: pb.ProductVariantInput.AttributesEntry
+	(*timestamppb.Timestamp)(nil),      // 77: google.protobuf.Timestamp
+	(*wrapperspb.DoubleValue)(nil),     // 78: google.protobuf.DoubleValue
+	(*wrapperspb.StringValue)(nil),     // 79: google.protobuf.StringValue
+	(*wrapperspb.Int32Value)(nil),      // 80: google.protobuf.Int32Value
+	(*emptypb.Empty)(nil),              // 81: google.protobuf.Empty
 }
 var file_proto_product_proto_depIdxs = []int32{
-	0,  // 0: pb.Product.type:type_name -> pb.ProductTypeProto
-	11, // 1: pb.Product.created_at:type_name -> google.protobuf.Timestamp
-	11, // 2: pb.Product.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 3: pb.CreateProductRequest.type:type_name -> pb.ProductTypeProto
-	1,  // 4: pb.CreateProductResponse.product:type_name -> pb.Product
-	1,  // 5: pb.GetProductResponse.product:type_name -> pb.Product
-	1,  // 6: pb.ListProductsResponse.products:type_name -> pb.Product
-	12, // 7: pb.UpdateProductRequest.name:type_name -> google.protobuf.StringValue
-	12, // 8: pb.UpdateProductRequest.description:type_name -> google.protobuf.StringValue
-	13, // 9: pb.UpdateProductRequest.price:type_name -> google.protobuf.DoubleValue
-	0,  // 10: pb.UpdateProductRequest.type:type_name -> pb.ProductTypeProto
-	14, // 11: pb.UpdateProductRequest.stock:type_name -> google.protobuf.Int32Value
-	12, // 12: pb.UpdateProductRequest.festival_id:type_name -> google.protobuf.StringValue
-	1,  // 13: pb.UpdateProductResponse.product:type_name -> pb.Product
-	2,  // 14: pb.ProductService.CreateProduct:input_type -> pb.CreateProductRequest
-	4,  // 15: pb.ProductService.GetProduct:input_type -> pb.GetProductRequest
-	6,  // 16: pb.ProductService.ListProducts:input_type -> pb.ListProductsRequest
-	8,  // 17: pb.ProductService.UpdateProduct:input_type -> pb.UpdateProductRequest
-	10, // 18: pb.ProductService.DeleteProduct:input_type -> pb.DeleteProductRequest
-	3,  // 19: pb.ProductService.CreateProduct:output_type -> pb.CreateProductResponse
-	5,  // 20: pb.ProductService.GetProduct:output_type -> pb.GetProductResponse
-	7,  // 21: pb.ProductService.ListProducts:output_type -> pb.ListProductsResponse
-	9,  // 22: pb.ProductService.UpdateProduct:output_type -> pb.UpdateProductResponse
-	15, // 23: pb.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
-	19, // [19:24] is the sub-list for method output_type
-	14, // [14:19] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	0,   // 0: pb.Product.type:type_name -> pb.ProductTypeProto
+	77,  // 1: pb.Product.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 2: pb.Product.updated_at:type_name -> google.protobuf.Timestamp
+	28,  // 3: pb.Product.festival:type_name -> pb.FestivalSummary
+	3,   // 4: pb.Product.variants:type_name -> pb.ProductVariant
+	75,  // 5: pb.ProductVariant.attributes:type_name -> pb.ProductVariant.AttributesEntry
+	78,  // 6: pb.ProductVariant.price:type_name -> google.protobuf.DoubleValue
+	77,  // 7: pb.ProductVariant.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 8: pb.ProductVariant.updated_at:type_name -> google.protobuf.Timestamp
+	76,  // 9: pb.ProductVariantInput.attributes:type_name -> pb.ProductVariantInput.AttributesEntry
+	78,  // 10: pb.ProductVariantInput.price:type_name -> google.protobuf.DoubleValue
+	4,   // 11: pb.ProductVariantList.variants:type_name -> pb.ProductVariantInput
+	0,   // 12: pb.CreateProductRequest.type:type_name -> pb.ProductTypeProto
+	4,   // 13: pb.CreateProductRequest.variants:type_name -> pb.ProductVariantInput
+	2,   // 14: pb.CreateProductResponse.product:type_name -> pb.Product
+	2,   // 15: pb.GetProductResponse.product:type_name -> pb.Product
+	0,   // 16: pb.ListProductsRequest.type:type_name -> pb.ProductTypeProto
+	78,  // 17: pb.ListProductsRequest.min_price:type_name -> google.protobuf.DoubleValue
+	78,  // 18: pb.ListProductsRequest.max_price:type_name -> google.protobuf.DoubleValue
+	2,   // 19: pb.ListProductsResponse.products:type_name -> pb.Product
+	0,   // 20: pb.SearchProductsRequest.type:type_name -> pb.ProductTypeProto
+	2,   // 21: pb.ProductSearchResult.product:type_name -> pb.Product
+	13,  // 22: pb.SearchProductsResponse.results:type_name -> pb.ProductSearchResult
+	79,  // 23: pb.UpdateProductRequest.name:type_name -> google.protobuf.StringValue
+	79,  // 24: pb.UpdateProductRequest.description:type_name -> google.protobuf.StringValue
+	78,  // 25: pb.UpdateProductRequest.price:type_name -> google.protobuf.DoubleValue
+	0,   // 26: pb.UpdateProductRequest.type:type_name -> pb.ProductTypeProto
+	80,  // 27: pb.UpdateProductRequest.stock:type_name -> google.protobuf.Int32Value
+	79,  // 28: pb.UpdateProductRequest.festival_id:type_name -> google.protobuf.StringValue
+	5,   // 29: pb.UpdateProductRequest.variants:type_name -> pb.ProductVariantList
+	2,   // 30: pb.UpdateProductResponse.product:type_name -> pb.Product
+	77,  // 31: pb.Reservation.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 32: pb.Reservation.updated_at:type_name -> google.protobuf.Timestamp
+	18,  // 33: pb.ReserveStockResponse.reservation:type_name -> pb.Reservation
+	18,  // 34: pb.CommitReservationResponse.reservation:type_name -> pb.Reservation
+	18,  // 35: pb.ReleaseReservationResponse.reservation:type_name -> pb.Reservation
+	77,  // 36: pb.Venue.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 37: pb.Venue.updated_at:type_name -> google.protobuf.Timestamp
+	77,  // 38: pb.Stage.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 39: pb.Stage.updated_at:type_name -> google.protobuf.Timestamp
+	25,  // 40: pb.Festival.venue:type_name -> pb.Venue
+	1,   // 41: pb.Festival.status:type_name -> pb.FestivalStatusProto
+	26,  // 42: pb.Festival.stages:type_name -> pb.Stage
+	77,  // 43: pb.Festival.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 44: pb.Festival.updated_at:type_name -> google.protobuf.Timestamp
+	1,   // 45: pb.FestivalSummary.status:type_name -> pb.FestivalStatusProto
+	25,  // 46: pb.ListVenuesResponse.venues:type_name -> pb.Venue
+	79,  // 47: pb.UpdateVenueRequest.name:type_name -> google.protobuf.StringValue
+	79,  // 48: pb.UpdateVenueRequest.address:type_name -> google.protobuf.StringValue
+	79,  // 49: pb.UpdateVenueRequest.city:type_name -> google.protobuf.StringValue
+	79,  // 50: pb.UpdateVenueRequest.country:type_name -> google.protobuf.StringValue
+	80,  // 51: pb.UpdateVenueRequest.capacity:type_name -> google.protobuf.Int32Value
+	25,  // 52: pb.VenueResponse.venue:type_name -> pb.Venue
+	1,   // 53: pb.CreateFestivalRequest.status:type_name -> pb.FestivalStatusProto
+	36,  // 54: pb.CreateFestivalRequest.stages:type_name -> pb.StageInput
+	1,   // 55: pb.ListFestivalsRequest.status:type_name -> pb.FestivalStatusProto
+	27,  // 56: pb.ListFestivalsResponse.festivals:type_name -> pb.Festival
+	79,  // 57: pb.UpdateFestivalRequest.name:type_name -> google.protobuf.StringValue
+	79,  // 58: pb.UpdateFestivalRequest.description:type_name -> google.protobuf.StringValue
+	79,  // 59: pb.UpdateFestivalRequest.venue_id:type_name -> google.protobuf.StringValue
+	79,  // 60: pb.UpdateFestivalRequest.start_date:type_name -> google.protobuf.StringValue
+	79,  // 61: pb.UpdateFestivalRequest.end_date:type_name -> google.protobuf.StringValue
+	79,  // 62: pb.UpdateFestivalRequest.timezone:type_name -> google.protobuf.StringValue
+	1,   // 63: pb.UpdateFestivalRequest.status:type_name -> pb.FestivalStatusProto
+	27,  // 64: pb.FestivalResponse.festival:type_name -> pb.Festival
+	26,  // 65: pb.ListStagesResponse.stages:type_name -> pb.Stage
+	79,  // 66: pb.UpdateStageRequest.name:type_name -> google.protobuf.StringValue
+	80,  // 67: pb.UpdateStageRequest.capacity:type_name -> google.protobuf.Int32Value
+	26,  // 68: pb.StageResponse.stage:type_name -> pb.Stage
+	77,  // 69: pb.Artist.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 70: pb.Artist.updated_at:type_name -> google.protobuf.Timestamp
+	50,  // 71: pb.Slot.artist:type_name -> pb.Artist
+	77,  // 72: pb.Slot.starts_at:type_name -> google.protobuf.Timestamp
+	77,  // 73: pb.Slot.ends_at:type_name -> google.protobuf.Timestamp
+	77,  // 74: pb.Slot.created_at:type_name -> google.protobuf.Timestamp
+	77,  // 75: pb.Slot.updated_at:type_name -> google.protobuf.Timestamp
+	51,  // 76: pb.ScheduleStage.slots:type_name -> pb.Slot
+	52,  // 77: pb.ScheduleDay.stages:type_name -> pb.ScheduleStage
+	50,  // 78: pb.ListArtistsResponse.artists:type_name -> pb.Artist
+	79,  // 79: pb.UpdateArtistRequest.name:type_name -> google.protobuf.StringValue
+	79,  // 80: pb.UpdateArtistRequest.genre:type_name -> google.protobuf.StringValue
+	79,  // 81: pb.UpdateArtistRequest.country:type_name -> google.protobuf.StringValue
+	79,  // 82: pb.UpdateArtistRequest.description:type_name -> google.protobuf.StringValue
+	50,  // 83: pb.ArtistResponse.artist:type_name -> pb.Artist
+	77,  // 84: pb.ListSlotsRequest.from:type_name -> google.protobuf.Timestamp
+	77,  // 85: pb.ListSlotsRequest.to:type_name -> google.protobuf.Timestamp
+	51,  // 86: pb.ListSlotsResponse.slots:type_name -> pb.Slot
+	77,  // 87: pb.CreateSlotRequest.starts_at:type_name -> google.protobuf.Timestamp
+	77,  // 88: pb.CreateSlotRequest.ends_at:type_name -> google.protobuf.Timestamp
+	79,  // 89: pb.UpdateSlotRequest.stage_id:type_name -> google.protobuf.StringValue
+	79,  // 90: pb.UpdateSlotRequest.artist_id:type_name -> google.protobuf.StringValue
+	77,  // 91: pb.UpdateSlotRequest.starts_at:type_name -> google.protobuf.Timestamp
+	77,  // 92: pb.UpdateSlotRequest.ends_at:type_name -> google.protobuf.Timestamp
+	51,  // 93: pb.SlotResponse.slot:type_name -> pb.Slot
+	53,  // 94: pb.GetScheduleResponse.days:type_name -> pb.ScheduleDay
+	51,  // 95: pb.GetMyScheduleResponse.slots:type_name -> pb.Slot
+	72,  // 96: pb.GetMyScheduleResponse.clashes:type_name -> pb.SlotClash
+	6,   // 97: pb.ProductService.CreateProduct:input_type -> pb.CreateProductRequest
+	8,   // 98: pb.ProductService.GetProduct:input_type -> pb.GetProductRequest
+	10,  // 99: pb.ProductService.ListProducts:input_type -> pb.ListProductsRequest
+	12,  // 100: pb.ProductService.SearchProducts:input_type -> pb.SearchProductsRequest
+	15,  // 101: pb.ProductService.UpdateProduct:input_type -> pb.UpdateProductRequest
+	17,  // 102: pb.ProductService.DeleteProduct:input_type -> pb.DeleteProductRequest
+	19,  // 103: pb.ProductService.ReserveStock:input_type -> pb.ReserveStockRequest
+	21,  // 104: pb.ProductService.CommitReservation:input_type -> pb.CommitReservationRequest
+	23,  // 105: pb.ProductService.ReleaseReservation:input_type -> pb.ReleaseReservationRequest
+	29,  // 106: pb.FestivalService.CreateVenue:input_type -> pb.CreateVenueRequest
+	30,  // 107: pb.FestivalService.GetVenue:input_type -> pb.GetVenueRequest
+	31,  // 108: pb.FestivalService.ListVenues:input_type -> pb.ListVenuesRequest
+	33,  // 109: pb.FestivalService.UpdateVenue:input_type -> pb.UpdateVenueRequest
+	34,  // 110: pb.FestivalService.DeleteVenue:input_type -> pb.DeleteVenueRequest
+	37,  // 111: pb.FestivalService.CreateFestival:input_type -> pb.CreateFestivalRequest
+	38,  // 112: pb.FestivalService.GetFestival:input_type -> pb.GetFestivalRequest
+	39,  // 113: pb.FestivalService.ListFestivals:input_type -> pb.ListFestivalsRequest
+	41,  // 114: pb.FestivalService.UpdateFestival:input_type -> pb.UpdateFestivalRequest
+	42,  // 115: pb.FestivalService.DeleteFestival:input_type -> pb.DeleteFestivalRequest
+	44,  // 116: pb.FestivalService.ListStages:input_type -> pb.ListStagesRequest
+	46,  // 117: pb.FestivalService.CreateStage:input_type -> pb.CreateStageRequest
+	47,  // 118: pb.FestivalService.UpdateStage:input_type -> pb.UpdateStageRequest
+	48,  // 119: pb.FestivalService.DeleteStage:input_type -> pb.DeleteStageRequest
+	54,  // 120: pb.LineupService.CreateArtist:input_type -> pb.CreateArtistRequest
+	55,  // 121: pb.LineupService.GetArtist:input_type -> pb.GetArtistRequest
+	56,  // 122: pb.LineupService.ListArtists:input_type -> pb.ListArtistsRequest
+	58,  // 123: pb.LineupService.UpdateArtist:input_type -> pb.UpdateArtistRequest
+	59,  // 124: pb.LineupService.DeleteArtist:input_type -> pb.DeleteArtistRequest
+	61,  // 125: pb.LineupService.ListSlots:input_type -> pb.ListSlotsRequest
+	63,  // 126: pb.LineupService.CreateSlot:input_type -> pb.CreateSlotRequest
+	64,  // 127: pb.LineupService.UpdateSlot:input_type -> pb.UpdateSlotRequest
+	65,  // 128: pb.LineupService.DeleteSlot:input_type -> pb.DeleteSlotRequest
+	67,  // 129: pb.LineupService.GetSchedule:input_type -> pb.GetScheduleRequest
+	69,  // 130: pb.LineupService.ExportSchedule:input_type -> pb.ExportScheduleRequest
+	71,  // 131: pb.LineupService.GetMySchedule:input_type -> pb.GetMyScheduleRequest
+	71,  // 132: pb.LineupService.ExportMySchedule:input_type -> pb.GetMyScheduleRequest
+	74,  // 133: pb.LineupService.AddToMySchedule:input_type -> pb.MyScheduleSlotRequest
+	74,  // 134: pb.LineupService.RemoveFromMySchedule:input_type -> pb.MyScheduleSlotRequest
+	7,   // 135: pb.ProductService.CreateProduct:output_type -> pb.CreateProductResponse
+	9,   // 136: pb.ProductService.GetProduct:output_type -> pb.GetProductResponse
+	11,  // 137: pb.ProductService.ListProducts:output_type -> pb.ListProductsResponse
+	14,  // 138: pb.ProductService.SearchProducts:output_type -> pb.SearchProductsResponse
+	16,  // 139: pb.ProductService.UpdateProduct:output_type -> pb.UpdateProductResponse
+	81,  // 140: pb.ProductService.DeleteProduct:output_type -> google.protobuf.Empty
+	20,  // 141: pb.ProductService.ReserveStock:output_type -> pb.ReserveStockResponse
+	22,  // 142: pb.ProductService.CommitReservation:output_type -> pb.CommitReservationResponse
+	24,  // 143: pb.ProductService.ReleaseReservation:output_type -> pb.ReleaseReservationResponse
+	35,  // 144: pb.FestivalService.CreateVenue:output_type -> pb.VenueResponse
+	35,  // 145: pb.FestivalService.GetVenue:output_type -> pb.VenueResponse
+	32,  // 146: pb.FestivalService.ListVenues:output_type -> pb.ListVenuesResponse
+	35,  // 147: pb.FestivalService.UpdateVenue:output_type -> pb.VenueResponse
+	81,  // 148: pb.FestivalService.DeleteVenue:output_type -> google.protobuf.Empty
+	43,  // 149: pb.FestivalService.CreateFestival:output_type -> pb.FestivalResponse
+	43,  // 150: pb.FestivalService.GetFestival:output_type -> pb.FestivalResponse
+	40,  // 151: pb.FestivalService.ListFestivals:output_type -> pb.ListFestivalsResponse
+	43,  // 152: pb.FestivalService.UpdateFestival:output_type -> pb.FestivalResponse
+	81,  // 153: pb.FestivalService.DeleteFestival:output_type -> google.protobuf.Empty
+	45,  // 154: pb.FestivalService.ListStages:output_type -> pb.ListStagesResponse
+	49,  // 155: pb.FestivalService.CreateStage:output_type -> pb.StageResponse
+	49,  // 156: pb.FestivalService.UpdateStage:output_type -> pb.StageResponse
+	81,  // 157: pb.FestivalService.DeleteStage:output_type -> google.protobuf.Empty
+	60,  // 158: pb.LineupService.CreateArtist:output_type -> pb.ArtistResponse
+	60,  // 159: pb.LineupService.GetArtist:output_type -> pb.ArtistResponse
+	57,  // 160: pb.LineupService.ListArtists:output_type -> pb.ListArtistsResponse
+	60,  // 161: pb.LineupService.UpdateArtist:output_type -> pb.ArtistResponse
+	81,  // 162: pb.LineupService.DeleteArtist:output_type -> google.protobuf.Empty
+	62,  // 163: pb.LineupService.ListSlots:output_type -> pb.ListSlotsResponse
+	66,  // 164: pb.LineupService.CreateSlot:output_type -> pb.SlotResponse
+	66,  // 165: pb.LineupService.UpdateSlot:output_type -> pb.SlotResponse
+	81,  // 166: pb.LineupService.DeleteSlot:output_type -> google.protobuf.Empty
+	68,  // 167: pb.LineupService.GetSchedule:output_type -> pb.GetScheduleResponse
+	70,  // 168: pb.LineupService.ExportSchedule:output_type -> pb.ExportScheduleResponse
+	73,  // 169: pb.LineupService.GetMySchedule:output_type -> pb.GetMyScheduleResponse
+	70,  // 170: pb.LineupService.ExportMySchedule:output_type -> pb.ExportScheduleResponse
+	81,  // 171: pb.LineupService.AddToMySchedule:output_type -> google.protobuf.Empty
+	81,  // 172: pb.LineupService.RemoveFromMySchedule:output_type -> google.protobuf.Empty
+	135, // [135:173] is the sub-list for method output_type
+	97,  // [97:135] is the sub-list for method input_type
+	97,  // [97:97] is the sub-list for extension type_name
+	97,  // [97:97] is the sub-list for extension extendee
+	0,   // [0:97] is the sub-list for field type_name
 }
 
 func init() { file_proto_product_proto_init() }
@@ -792,10 +5439,10 @@ func file_proto_product_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_product_proto_rawDesc), len(file_proto_product_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   10,
+			NumEnums:      2,
+			NumMessages:   75,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_proto_product_proto_goTypes,
 		DependencyIndexes: file_proto_product_proto_depIdxs,
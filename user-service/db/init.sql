@@ -3,6 +3,240 @@ CREATE TABLE IF NOT EXISTS users (
     username VARCHAR(100) NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
+    roles TEXT[] NOT NULL DEFAULT ARRAY['CUSTOMER']::TEXT[],
+    email_verified_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
     );
+
+-- Для баз, созданных до появления ролей
+ALTER TABLE users ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT ARRAY['CUSTOMER']::TEXT[];
+-- Для баз, созданных до появления подтверждения email
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+-- Деактивация, удаление с отложенным обезличиванием и уведомление order-service об обезличивании
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erasure_notified_at TIMESTAMPTZ;
+-- Расширенный профиль
+ALTER TABLE users ADD COLUMN IF NOT EXISTS full_name VARCHAR(200) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(2048) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS date_of_birth DATE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '';
+
+-- Постраничный список пользователей (ListUsers) идет по (поле сортировки, id)
+CREATE INDEX IF NOT EXISTS users_created_idx ON users (created_at, id);
+CREATE INDEX IF NOT EXISTS users_email_sort_idx ON users (email, id);
+CREATE INDEX IF NOT EXISTS users_username_sort_idx ON users (username, id);
+-- Фильтры по префиксу email и username: lower(...) LIKE 'префикс%'
+CREATE INDEX IF NOT EXISTS users_email_prefix_idx ON users (lower(email) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS users_username_prefix_idx ON users (lower(username) text_pattern_ops);
+-- Фильтр по роли: roles @> ARRAY['ROLE']
+CREATE INDEX IF NOT EXISTS users_roles_idx ON users USING GIN (roles);
+-- Фильтр неподтвержденных email (таких пользователей обычно немного)
+CREATE INDEX IF NOT EXISTS users_unverified_idx ON users (created_at, id) WHERE email_verified_at IS NULL;
+-- Поиск удаленных пользователей с истекшим сроком ожидания и обезличенных, о которых не сообщено order-service
+CREATE INDEX IF NOT EXISTS users_pending_erasure_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL AND anonymized_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_unnotified_erasure_idx ON users (anonymized_at) WHERE anonymized_at IS NOT NULL AND erasure_notified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_idx ON refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_idx ON refresh_tokens (user_id);
+
+-- Отозванные до истечения срока токены доступа (claim jti)
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS revoked_access_tokens_expires_idx ON revoked_access_tokens (expires_at);
+
+-- Одноразовые токены сброса пароля (хранится только SHA-256 хеш)
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS password_reset_tokens_user_idx ON password_reset_tokens (user_id);
+
+-- Одноразовые токены подтверждения email. Токен подтверждает именно тот адрес,
+-- на который был отправлен: после смены email старые токены не действуют.
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS email_verification_tokens_user_idx ON email_verification_tokens (user_id);
+
+-- Счетчики неудачных попыток входа по email ("email:...") и IP-адресу ("ip:...")
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key VARCHAR(300) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ
+    );
+
+-- Журнал аутентификации. user_id не ссылается на users, чтобы записи сохранялись после удаления пользователя.
+CREATE TABLE IF NOT EXISTS auth_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    event VARCHAR(50) NOT NULL,
+    user_id UUID,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS auth_audit_log_created_idx ON auth_audit_log (created_at DESC);
+CREATE INDEX IF NOT EXISTS auth_audit_log_user_idx ON auth_audit_log (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS auth_audit_log_email_idx ON auth_audit_log (lower(email), created_at DESC);
+
+-- Двухфакторная аутентификация (TOTP). confirmed_at заполняется после ввода первого кода.
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Одноразовые коды восстановления (хранится только SHA-256 хеш)
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    code_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS two_factor_recovery_codes_user_idx ON two_factor_recovery_codes (user_id);
+
+-- Незавершенные входы, ожидающие второй фактор
+CREATE TABLE IF NOT EXISTS login_challenges (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMPTZ
+    );
+
+-- Адресная книга пользователя. Адрес по умолчанию у пользователя не больше одного.
+CREATE TABLE IF NOT EXISTS user_addresses (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(50) NOT NULL DEFAULT '',
+    recipient VARCHAR(200) NOT NULL,
+    phone VARCHAR(16) NOT NULL DEFAULT '',
+    line1 VARCHAR(200) NOT NULL,
+    line2 VARCHAR(200) NOT NULL DEFAULT '',
+    city VARCHAR(100) NOT NULL,
+    region VARCHAR(100) NOT NULL DEFAULT '',
+    postal_code VARCHAR(20) NOT NULL,
+    country CHAR(2) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS user_addresses_user_idx ON user_addresses (user_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS user_addresses_default_idx ON user_addresses (user_id) WHERE is_default;
+
+-- API-ключи машинных клиентов. Хранится только SHA-256 хеш ключа; prefix - его начало для списка ключей.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL,
+    rate_limit INTEGER NOT NULL CHECK (rate_limit > 0),
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS api_keys_user_idx ON api_keys (user_id, created_at DESC);
+
+-- Учетные записи внешних провайдеров OpenID Connect, привязанные к пользователям.
+-- subject - постоянный ID пользователя у провайдера (claim sub); у пользователя не больше одной учетной записи каждого провайдера.
+CREATE TABLE IF NOT EXISTS external_identities (
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMPTZ,
+    PRIMARY KEY (provider, subject),
+    UNIQUE (user_id, provider)
+    );
+
+-- Начатые входы через провайдер OpenID Connect до возврата пользователя с кодом авторизации.
+-- Хранится хеш state; verifier PKCE и nonce нужны для обмена кода и проверки ID-токена.
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state_hash CHAR(64) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    nonce VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Клиенты OAuth2 - приложения, которым user-service выдает токены. Хранится только SHA-256 хеш секрета;
+-- у публичных клиентов (SPA, мобильные приложения) секрета нет, и они подтверждают обмен кода только через PKCE.
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    secret_hash CHAR(64),
+    redirect_uris TEXT[] NOT NULL,
+    grant_types TEXT[] NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
+
+-- Токены обновления, выданные клиентам OAuth2, помнят клиента и разрешения (у токенов входа оба поля пусты)
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS client_id UUID REFERENCES oauth_clients(id) ON DELETE CASCADE;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS scopes TEXT[];
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_client_idx ON refresh_tokens (client_id, user_id) WHERE client_id IS NOT NULL;
+
+-- Согласия пользователей: какие разрешения пользователь уже выдал клиенту
+CREATE TABLE IF NOT EXISTS oauth_consents (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    scopes TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, client_id)
+    );
+
+-- Одноразовые коды авторизации (хранится только SHA-256 хеш) вместе с code_challenge PKCE
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+    code_hash CHAR(64) PRIMARY KEY,
+    client_id UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri TEXT NOT NULL,
+    scopes TEXT[] NOT NULL,
+    code_challenge VARCHAR(128) NOT NULL,
+    nonce VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+    );
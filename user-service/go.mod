@@ -3,6 +3,7 @@ module github.com/Hayzerr/go-microservice-project/user-service
 go 1.22
 
 require (
+	github.com/Hayzerr/go-microservice-project/auth v0.0.0
 	github.com/Hayzerr/go-microservice-project/pb v0.0.0
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
@@ -20,3 +21,5 @@ require (
 )
 
 replace github.com/Hayzerr/go-microservice-project/pb => ./github.com/Hayzerr/go-microservice-project/pb
+
+replace github.com/Hayzerr/go-microservice-project/auth => ../github.com/Hayzerr/go-microservice-project/auth
@@ -0,0 +1,78 @@
+// Package notifier доставляет пользователям служебные сообщения: ссылки для сброса пароля,
+// подтверждения email и т.п. Для локальной разработки есть реализации, которые пишут сообщения
+// в лог или в файл; для рабочего окружения достаточно реализовать интерфейс Notifier (SMTP, SMS и т.д.).
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Message - сообщение пользователю
+type Message struct {
+	To      string `json:"to"`      // Адрес получателя (email)
+	Subject string `json:"subject"` // Тема
+	Body    string `json:"body"`    // Текст сообщения
+}
+
+// Notifier отправляет сообщения пользователям
+type Notifier interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier пишет сообщения в стандартный лог. Только для локальной разработки:
+// сообщения содержат секретные токены.
+type LogNotifier struct{}
+
+// NewLogNotifier создает новый экземпляр LogNotifier
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Send выводит сообщение в лог
+func (n *LogNotifier) Send(_ context.Context, msg Message) error {
+	log.Printf("Сообщение для %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// FileNotifier дописывает сообщения в файл в формате JSON Lines (одно сообщение на строку)
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileNotifier создает FileNotifier, пишущий в файл path
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+// fileRecord - строка файла FileNotifier
+type fileRecord struct {
+	Message
+	SentAt time.Time `json:"sent_at"`
+}
+
+// Send дописывает сообщение в файл
+func (n *FileNotifier) Send(_ context.Context, msg Message) error {
+	line, err := json.Marshal(fileRecord{Message: msg, SentAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла сообщений: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
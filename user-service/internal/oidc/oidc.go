@@ -0,0 +1,324 @@
+// Package oidc - клиент внешнего провайдера OpenID Connect ("Войти через Google"):
+// discovery-документ, authorization code flow с PKCE и проверка ID-токена по JWKS провайдера.
+package oidc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrDiscovery      = errors.New("ошибка загрузки discovery-документа провайдера")
+	ErrTokenExchange  = errors.New("провайдер отклонил обмен кода авторизации")
+	ErrInvalidIDToken = errors.New("недействительный ID-токен")
+)
+
+// defaultScopes запрашиваются, если в Config.Scopes ничего не указано
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// clockSkew - допустимое расхождение часов с провайдером при проверке сроков ID-токена
+const clockSkew = time.Minute
+
+// jwksCacheTTL - срок кеширования открытых ключей провайдера
+const jwksCacheTTL = time.Hour
+
+// Config - параметры клиента, зарегистрированного у провайдера
+type Config struct {
+	Name         string // Короткое имя провайдера в API (google, apple)
+	Issuer       string // Идентификатор провайдера; discovery-документ загружается с {Issuer}/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string // Пусто для публичного клиента: тогда его защищает только PKCE
+	RedirectURL  string // Адрес, на который провайдер вернет пользователя с кодом авторизации
+	Scopes       []string
+}
+
+// Metadata - используемые поля discovery-документа (OpenID Connect Discovery 1.0)
+type Metadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// Tokens - ответ token endpoint
+type Tokens struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Identity - пользователь, подтвержденный ID-токеном провайдера
+type Identity struct {
+	Issuer        string
+	Subject       string // Постоянный ID пользователя у провайдера
+	Email         string
+	EmailVerified bool // Провайдер подтвердил, что email принадлежит пользователю
+	Name          string
+}
+
+// Provider - клиент одного провайдера. Discovery-документ загружается при первом обращении
+// и кешируется; если провайдер был недоступен, загрузка повторяется при следующем обращении.
+type Provider struct {
+	config     Config
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     *auth.JWKSCache
+}
+
+// NewProvider создает клиент провайдера
+func NewProvider(config Config) *Provider {
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultScopes
+	} else if !slices.Contains(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+	return &Provider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name возвращает короткое имя провайдера
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// Metadata возвращает discovery-документ провайдера, загружая его при первом обращении
+func (p *Provider) Metadata(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: статус %d", ErrDiscovery, resp.StatusCode)
+	}
+
+	var metadata Metadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	// Документ должен описывать того же провайдера, иначе ID-токены другого издателя пройдут проверку iss
+	if strings.TrimSuffix(metadata.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q не совпадает с настроенным %q", ErrDiscovery, metadata.Issuer, p.config.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: не указаны authorization_endpoint, token_endpoint или jwks_uri", ErrDiscovery)
+	}
+
+	p.metadata = &metadata
+	p.keys = auth.NewJWKSCache(metadata.JWKSURI, jwksCacheTTL)
+	return p.metadata, nil
+}
+
+// AuthCodeURL возвращает адрес страницы входа провайдера. state защищает от подмены ответа (CSRF),
+// nonce связывает ID-токен с этой попыткой входа, а verifier - секрет PKCE (в запрос попадает только его хеш).
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: некорректный authorization_endpoint: %v", ErrDiscovery, err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange обменивает код авторизации на токены, передавая verifier PKCE
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Tokens, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	// По умолчанию (RFC 6749, OIDC Core) секрет передается в заголовке Authorization: Basic
+	basicAuth := p.config.ClientSecret != "" && (len(metadata.TokenEndpointAuthMethodsSupported) == 0 ||
+		slices.Contains(metadata.TokenEndpointAuthMethodsSupported, "client_secret_basic"))
+	if !basicAuth {
+		form.Set("client_id", p.config.ClientID)
+		if p.config.ClientSecret != "" {
+			form.Set("client_secret", p.config.ClientSecret)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if basicAuth {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса к token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.NewDecoder(resp.Body).Decode(&oauthErr)
+		return nil, fmt.Errorf("%w: статус %d %s %s", ErrTokenExchange, resp.StatusCode, oauthErr.Error, oauthErr.Description)
+	}
+
+	var tokens Tokens
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("%w: ошибка декодирования ответа: %v", ErrTokenExchange, err)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: в ответе нет id_token", ErrTokenExchange)
+	}
+	return &tokens, nil
+}
+
+// idTokenClaims - проверяемые утверждения ID-токена (OpenID Connect Core 1.0, раздел 2)
+type idTokenClaims struct {
+	Nonce           string       `json:"nonce"`
+	AuthorizedParty string       `json:"azp"`
+	Email           string       `json:"email"`
+	EmailVerified   flexibleBool `json:"email_verified"`
+	Name            string       `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// flexibleBool принимает и true, и "true": некоторые провайдеры (Apple) передают email_verified строкой
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = s == "true"
+		return nil
+	}
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = flexibleBool(v)
+	return nil
+}
+
+// VerifyIDToken проверяет подпись ID-токена ключом из JWKS провайдера, издателя, получателя,
+// сроки действия и nonce и возвращает подтвержденного пользователя
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*Identity, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	keys := p.keys
+	p.mu.Unlock()
+
+	var claims idTokenClaims
+	_, err = jwt.ParseWithClaims(rawIDToken, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("%w: не указан kid", auth.ErrUnknownKey)
+		}
+		public, err := keys.PublicKey(kid)
+		if err != nil {
+			return nil, err
+		}
+		// Алгоритм из заголовка должен соответствовать типу ключа
+		switch public.(type) {
+		case *rsa.PublicKey:
+			if t.Method.Alg() == auth.AlgRS256 {
+				return public, nil
+			}
+		case ed25519.PublicKey:
+			if t.Method.Alg() == auth.AlgEdDSA {
+				return public, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: алгоритм %s не подходит для ключа %s", auth.ErrUnsupportedAlgorithm, t.Method.Alg(), kid)
+	},
+		jwt.WithValidMethods([]string{auth.AlgRS256, auth.AlgEdDSA}),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: не указан sub", ErrInvalidIDToken)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce не совпадает", ErrInvalidIDToken)
+	}
+	// Токен, выданный для нескольких получателей, должен называть нас уполномоченной стороной
+	if (len(claims.Audience) > 1 || claims.AuthorizedParty != "") && claims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("%w: azp %q не совпадает с client_id", ErrInvalidIDToken, claims.AuthorizedParty)
+	}
+
+	return &Identity{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// Authenticate завершает вход: обменивает код на токены и проверяет ID-токен
+func (p *Provider) Authenticate(ctx context.Context, code, verifier, nonce string) (*Identity, error) {
+	tokens, err := p.Exchange(ctx, code, verifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.VerifyIDToken(ctx, tokens.IDToken, nonce)
+}
@@ -0,0 +1,475 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "festival-app"
+	testClientSecret = "s3cret"
+	testRedirectURL  = "https://app.example.com/oidc/callback"
+)
+
+// authRequest - попытка входа, запомненная фейковым провайдером до обмена кода
+type authRequest struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+}
+
+// fakeProvider - провайдер OpenID Connect в памяти процесса: discovery, JWKS,
+// authorization endpoint (сразу выдает код, как будто пользователь вошел) и token endpoint с PKCE
+type fakeProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	// authMethods - token_endpoint_auth_methods_supported в discovery-документе
+	authMethods []string
+	// issuer - издатель в discovery-документе; пусто - адрес сервера
+	issuer string
+	// claims дополняет или изменяет утверждения выдаваемых ID-токенов
+	claims func(claims jwt.MapClaims)
+
+	mu    sync.Mutex
+	codes map[string]authRequest
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	f := &fakeProvider{t: t, key: key, kid: "test-key", codes: make(map[string]authRequest)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", f.handleDiscovery)
+	mux.HandleFunc("/jwks", f.handleJWKS)
+	mux.HandleFunc("/authorize", f.handleAuthorize)
+	mux.HandleFunc("/token", f.handleToken)
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	issuer := f.issuer
+	if issuer == "" {
+		issuer = f.server.URL
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                issuer,
+		"authorization_endpoint":                f.server.URL + "/authorize",
+		"token_endpoint":                        f.server.URL + "/token",
+		"jwks_uri":                              f.server.URL + "/jwks",
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": f.authMethods,
+	})
+}
+
+func (f *fakeProvider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	jwk, err := auth.NewJWK(f.kid, &f.key.PublicKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(auth.JWKS{Keys: []auth.JWK{jwk}})
+}
+
+func (f *fakeProvider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != testClientID ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	code, err := NewRandomString()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	f.mu.Lock()
+	f.codes[code] = authRequest{
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		redirectURI: query.Get("redirect_uri"),
+	}
+	f.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (f *fakeProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, "invalid_request")
+		return
+	}
+	clientID, clientSecret, basic := r.BasicAuth()
+	if !basic {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != testClientID || clientSecret != testClientSecret {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	f.mu.Lock()
+	request, ok := f.codes[r.PostForm.Get("code")]
+	delete(f.codes, r.PostForm.Get("code")) // Код одноразовый
+	f.mu.Unlock()
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != request.redirectURI ||
+		CodeChallenge(r.PostForm.Get("code_verifier")) != request.challenge {
+		writeOAuthError(w, "invalid_grant")
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "provider-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     f.idToken(request.nonce),
+	})
+}
+
+func writeOAuthError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+// idToken выпускает ID-токен пользователя alice@example.com
+func (f *fakeProvider) idToken(nonce string) string {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            f.server.URL,
+		"sub":            "provider-user-42",
+		"aud":            testClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          nonce,
+		"email":          "alice@example.com",
+		"email_verified": true,
+		"name":           "Alice",
+	}
+	if f.claims != nil {
+		f.claims(claims)
+	}
+	return f.sign(claims, f.key, f.kid)
+}
+
+func (f *fakeProvider) sign(claims jwt.MapClaims, key *rsa.PrivateKey, kid string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	raw, err := token.SignedString(key)
+	if err != nil {
+		f.t.Fatalf("SignedString: %v", err)
+	}
+	return raw
+}
+
+func (f *fakeProvider) provider() *Provider {
+	return NewProvider(Config{
+		Name:         "fake",
+		Issuer:       f.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+}
+
+// authorize проходит authorization endpoint так, как это сделал бы браузер пользователя, и возвращает код
+func (f *fakeProvider) authorize(t *testing.T, authURL, wantState string) string {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("GET authorization endpoint: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("ожидалось перенаправление, получен статус %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("некорректный Location: %v", err)
+	}
+	if got := location.Scheme + "://" + location.Host + location.Path; got != testRedirectURL {
+		t.Fatalf("перенаправление на %s, ожидался %s", got, testRedirectURL)
+	}
+	if state := location.Query().Get("state"); state != wantState {
+		t.Fatalf("state %q, ожидался %q", state, wantState)
+	}
+	return location.Query().Get("code")
+}
+
+// mustRandom возвращает случайную строку для state, nonce или verifier
+func mustRandom(t *testing.T) string {
+	t.Helper()
+	s, err := NewRandomString()
+	if err != nil {
+		t.Fatalf("NewRandomString: %v", err)
+	}
+	return s
+}
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := fake.provider()
+	ctx := context.Background()
+	state, nonce, verifier := mustRandom(t), mustRandom(t), mustRandom(t)
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	query := parsed.Query()
+	if query.Get("code_challenge") != CodeChallenge(verifier) || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("в адресе нет challenge PKCE: %s", authURL)
+	}
+	if query.Get("code_verifier") != "" {
+		t.Fatalf("verifier не должен попадать в адрес: %s", authURL)
+	}
+	if query.Get("scope") != "openid email profile" {
+		t.Fatalf("scope %q, ожидался %q", query.Get("scope"), "openid email profile")
+	}
+
+	code := fake.authorize(t, authURL, state)
+	identity, err := provider.Authenticate(ctx, code, verifier, nonce)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	want := Identity{Issuer: fake.server.URL, Subject: "provider-user-42", Email: "alice@example.com", EmailVerified: true, Name: "Alice"}
+	if *identity != want {
+		t.Fatalf("получен %+v, ожидался %+v", *identity, want)
+	}
+
+	// Код одноразовый
+	if _, err := provider.Authenticate(ctx, code, verifier, nonce); !errors.Is(err, ErrTokenExchange) {
+		t.Fatalf("повторный обмен кода: ожидалась ErrTokenExchange, получено %v", err)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := fake.provider()
+	ctx := context.Background()
+	state, nonce := mustRandom(t), mustRandom(t)
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, mustRandom(t))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code := fake.authorize(t, authURL, state)
+
+	// Перехваченный код бесполезен без verifier, который знает только клиент
+	if _, err := provider.Authenticate(ctx, code, mustRandom(t), nonce); !errors.Is(err, ErrTokenExchange) {
+		t.Fatalf("ожидалась ErrTokenExchange, получено %v", err)
+	}
+}
+
+func TestExchangeUsesClientSecretPost(t *testing.T) {
+	fake := newFakeProvider(t)
+	fake.authMethods = []string{"client_secret_post"}
+	provider := fake.provider()
+	ctx := context.Background()
+	state, nonce, verifier := mustRandom(t), mustRandom(t), mustRandom(t)
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	if _, err := provider.Authenticate(ctx, fake.authorize(t, authURL, state), verifier, nonce); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	fake := newFakeProvider(t)
+	fake.issuer = "https://evil.example.com"
+
+	_, err := fake.provider().AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+	if !errors.Is(err, ErrDiscovery) {
+		t.Fatalf("ожидалась ErrDiscovery, получено %v", err)
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	fake := newFakeProvider(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	const nonce = "expected-nonce"
+
+	tests := []struct {
+		name    string
+		token   func() string
+		wantErr bool
+		check   func(t *testing.T, identity *Identity)
+	}{
+		{
+			name:  "Valid",
+			token: func() string { return fake.idToken(nonce) },
+		},
+		{
+			name: "EmailVerifiedAsString",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) { c["email_verified"] = "true" }, nonce)
+			},
+			check: func(t *testing.T, identity *Identity) {
+				if !identity.EmailVerified {
+					t.Fatal("email_verified \"true\" должен считаться подтверждением")
+				}
+			},
+		},
+		{
+			name: "EmailNotVerified",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) { c["email_verified"] = false }, nonce)
+			},
+			check: func(t *testing.T, identity *Identity) {
+				if identity.EmailVerified {
+					t.Fatal("email_verified false не должен считаться подтверждением")
+				}
+			},
+		},
+		{
+			name:    "WrongNonce",
+			token:   func() string { return fake.idToken("other-nonce") },
+			wantErr: true,
+		},
+		{
+			name: "WrongAudience",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) { c["aud"] = "other-client" }, nonce)
+			},
+			wantErr: true,
+		},
+		{
+			name: "MultipleAudiencesWithoutAzp",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) { c["aud"] = []string{testClientID, "other-client"} }, nonce)
+			},
+			wantErr: true,
+		},
+		{
+			name: "MultipleAudiencesWithAzp",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) {
+					c["aud"] = []string{testClientID, "other-client"}
+					c["azp"] = testClientID
+				}, nonce)
+			},
+		},
+		{
+			name: "WrongIssuer",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, nonce)
+			},
+			wantErr: true,
+		},
+		{
+			name: "Expired",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }, nonce)
+			},
+			wantErr: true,
+		},
+		{
+			name: "MissingSubject",
+			token: func() string {
+				return fake.withClaims(func(c jwt.MapClaims) { delete(c, "sub") }, nonce)
+			},
+			wantErr: true,
+		},
+		{
+			name: "ForeignKeyWithKnownKid",
+			token: func() string {
+				return fake.sign(jwt.MapClaims{
+					"iss": fake.server.URL, "sub": "attacker", "aud": testClientID, "nonce": nonce,
+					"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+				}, otherKey, fake.kid)
+			},
+			wantErr: true,
+		},
+		{
+			name: "UnknownKid",
+			token: func() string {
+				return fake.sign(jwt.MapClaims{
+					"iss": fake.server.URL, "sub": "attacker", "aud": testClientID, "nonce": nonce,
+					"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+				}, otherKey, "unknown-key")
+			},
+			wantErr: true,
+		},
+		{
+			name: "SymmetricAlgorithm",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"iss": fake.server.URL, "sub": "attacker", "aud": testClientID, "nonce": nonce,
+					"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+				})
+				token.Header["kid"] = fake.kid
+				raw, err := token.SignedString([]byte(testClientSecret))
+				if err != nil {
+					t.Fatalf("SignedString: %v", err)
+				}
+				return raw
+			},
+			wantErr: true,
+		},
+	}
+
+	provider := fake.provider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := provider.VerifyIDToken(context.Background(), tt.token(), nonce)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidIDToken) {
+					t.Fatalf("ожидалась ErrInvalidIDToken, получено %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyIDToken: %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, identity)
+			}
+		})
+	}
+}
+
+// withClaims выпускает ID-токен с измененными утверждениями
+func (f *fakeProvider) withClaims(modify func(claims jwt.MapClaims), nonce string) string {
+	f.claims = modify
+	defer func() { f.claims = nil }()
+	return f.idToken(nonce)
+}
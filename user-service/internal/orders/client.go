@@ -0,0 +1,98 @@
+// Package orders - HTTP-клиент order-service для выгрузки заказов пользователя
+// и уведомления об обезличивании его данных.
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+)
+
+// ErrForbidden возвращается, если order-service отклонил запрос из-за недостатка прав
+var ErrForbidden = errors.New("order-service отклонил запрос: недостаточно прав")
+
+// TokenSource выпускает служебный токен для запросов, которые выполняются не от имени пользователя
+type TokenSource func() (string, error)
+
+// Client обращается к REST API order-service
+type Client struct {
+	baseURL      string
+	client       *http.Client
+	serviceToken TokenSource
+}
+
+// NewClient создает клиент order-service с адресом baseURL.
+// serviceToken используется для служебных запросов (уведомление об обезличивании).
+func NewClient(baseURL string, serviceToken TokenSource) *Client {
+	return &Client{
+		baseURL:      baseURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		serviceToken: serviceToken,
+	}
+}
+
+// UserOrders возвращает оформленные заказы пользователя в формате ответа order-service.
+// Запрос выполняется от имени пользователя, токен или API-ключ которого передан в контексте.
+func (c *Client) UserOrders(ctx context.Context, userID string) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/users/%s/orders", c.baseURL, userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if authorization, ok := auth.AuthorizationFromContext(ctx); ok {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(body) {
+		return nil, errors.New("order-service вернул некорректный JSON")
+	}
+	return body, nil
+}
+
+// EraseUser просит order-service обезличить заказы пользователя. Повторный вызов безопасен.
+func (c *Client) EraseUser(ctx context.Context, userID string) error {
+	token, err := c.serviceToken()
+	if err != nil {
+		return fmt.Errorf("ошибка выпуска служебного токена: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/api/users/%s/erase", c.baseURL, userID), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	_, err = c.do(req)
+	return err
+}
+
+// do выполняет запрос и возвращает тело успешного ответа
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка соединения с order-service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения ответа order-service: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, ErrForbidden
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("ошибка order-service: код %d", resp.StatusCode)
+	}
+	return body, nil
+}
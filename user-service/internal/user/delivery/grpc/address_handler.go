@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	pb "github.com/Hayzerr/go-microservice-project/pb"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mapAddressModelToProto преобразует модель Address в proto-сообщение Address.
+func mapAddressModelToProto(address *models.Address) *pb.Address {
+	return &pb.Address{
+		Id:         address.ID,
+		UserId:     address.UserID,
+		Label:      address.Label,
+		Recipient:  address.Recipient,
+		Phone:      address.Phone,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+		IsDefault:  address.IsDefault,
+		CreatedAt:  timestamppb.New(address.CreatedAt),
+		UpdatedAt:  timestamppb.New(address.UpdatedAt),
+	}
+}
+
+// mapAddressProtoToInput преобразует proto-сообщение Address во входные данные usecase.
+func mapAddressProtoToInput(address *pb.Address) usecase.AddressInput {
+	return usecase.AddressInput{
+		Label:      address.GetLabel(),
+		Recipient:  address.GetRecipient(),
+		Phone:      address.GetPhone(),
+		Line1:      address.GetLine1(),
+		Line2:      address.GetLine2(),
+		City:       address.GetCity(),
+		Region:     address.GetRegion(),
+		PostalCode: address.GetPostalCode(),
+		Country:    address.GetCountry(),
+		Default:    address.GetIsDefault(),
+	}
+}
+
+// checkAddressAccess проверяет, что указан пользователь и вызов выполняет он сам или ADMIN
+func checkAddressAccess(ctx context.Context, userID string) error {
+	if userID == "" {
+		return status.Errorf(codes.InvalidArgument, "ID пользователя не может быть пустым")
+	}
+	if !isCurrentUserOrRole(ctx, userID, auth.RoleAdmin) {
+		return status.Errorf(codes.PermissionDenied, "Недостаточно прав для работы с адресами пользователя")
+	}
+	return nil
+}
+
+// mapAddressError переводит ошибки адресной книги в gRPC статусы.
+func mapAddressError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return status.Errorf(codes.NotFound, "Пользователь не найден: %v", err)
+	case errors.Is(err, usecase.ErrAddressNotFound):
+		return status.Errorf(codes.NotFound, "Адрес не найден: %v", err)
+	case errors.Is(err, usecase.ErrInvalidProfile):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, usecase.ErrTooManyAddresses), errors.Is(err, usecase.ErrAccountErased):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "Ошибка при работе с адресами: %v", err)
+	}
+}
+
+// ListAddresses обрабатывает gRPC запрос на получение адресной книги пользователя.
+func (h *UserGRPCHandler) ListAddresses(ctx context.Context, req *pb.ListAddressesRequest) (*pb.ListAddressesResponse, error) {
+	if err := checkAddressAccess(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	addresses, err := h.addressUsecase.ListAddresses(ctx, req.GetUserId())
+	if err != nil {
+		return nil, mapAddressError(err)
+	}
+	response := &pb.ListAddressesResponse{Addresses: make([]*pb.Address, 0, len(addresses))}
+	for _, address := range addresses {
+		response.Addresses = append(response.Addresses, mapAddressModelToProto(address))
+	}
+	return response, nil
+}
+
+// GetAddress обрабатывает gRPC запрос на получение адреса пользователя.
+func (h *UserGRPCHandler) GetAddress(ctx context.Context, req *pb.AddressRequest) (*pb.Address, error) {
+	if err := checkAddressAccess(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	address, err := h.addressUsecase.GetAddress(ctx, req.GetUserId(), req.GetId())
+	if err != nil {
+		return nil, mapAddressError(err)
+	}
+	return mapAddressModelToProto(address), nil
+}
+
+// CreateAddress обрабатывает gRPC запрос на добавление адреса.
+func (h *UserGRPCHandler) CreateAddress(ctx context.Context, req *pb.SaveAddressRequest) (*pb.Address, error) {
+	if err := checkAddressAccess(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	address, err := h.addressUsecase.CreateAddress(ctx, req.GetUserId(), mapAddressProtoToInput(req.GetAddress()))
+	if err != nil {
+		return nil, mapAddressError(err)
+	}
+	return mapAddressModelToProto(address), nil
+}
+
+// UpdateAddress обрабатывает gRPC запрос на изменение адреса.
+func (h *UserGRPCHandler) UpdateAddress(ctx context.Context, req *pb.SaveAddressRequest) (*pb.Address, error) {
+	if err := checkAddressAccess(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	address, err := h.addressUsecase.UpdateAddress(ctx, req.GetUserId(), req.GetId(), mapAddressProtoToInput(req.GetAddress()))
+	if err != nil {
+		return nil, mapAddressError(err)
+	}
+	return mapAddressModelToProto(address), nil
+}
+
+// DeleteAddress обрабатывает gRPC запрос на удаление адреса.
+func (h *UserGRPCHandler) DeleteAddress(ctx context.Context, req *pb.AddressRequest) (*emptypb.Empty, error) {
+	if err := checkAddressAccess(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	if err := h.addressUsecase.DeleteAddress(ctx, req.GetUserId(), req.GetId()); err != nil {
+		return nil, mapAddressError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// SetDefaultAddress обрабатывает gRPC запрос на выбор адреса по умолчанию.
+func (h *UserGRPCHandler) SetDefaultAddress(ctx context.Context, req *pb.AddressRequest) (*pb.Address, error) {
+	if err := checkAddressAccess(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	address, err := h.addressUsecase.SetDefaultAddress(ctx, req.GetUserId(), req.GetId())
+	if err != nil {
+		return nil, mapAddressError(err)
+	}
+	return mapAddressModelToProto(address), nil
+}
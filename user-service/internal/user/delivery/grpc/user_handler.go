@@ -3,26 +3,70 @@ package grpc
 import (
 	"context"
 	"errors" // Для проверки типов ошибок из usecase
+	"math"
+	"net"
+	"strconv"
 
 	// ВАЖНО: Замените 'your_project_module' на имя вашего модуля из go.mod
+	"github.com/Hayzerr/go-microservice-project/auth"
 	pb "github.com/Hayzerr/go-microservice-project/pb" // Сгенерированные proto-файлы
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
 
+	"github.com/google/uuid"
+
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb" // Для преобразования time.Time в google.protobuf.Timestamp
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
+// PublicMethods перечисляет методы UserService, доступные без токена.
+// Остальные методы проверяются перехватчиками auth, подключенными в main.go.
+var PublicMethods = append([]string{
+	pb.UserService_CreateUser_FullMethodName,
+	pb.UserService_RefreshToken_FullMethodName,
+	pb.UserService_RequestPasswordReset_FullMethodName,
+	pb.UserService_ResetPassword_FullMethodName,
+	pb.UserService_VerifyEmail_FullMethodName,
+	pb.UserService_Login_FullMethodName,
+	pb.UserService_VerifyTwoFactor_FullMethodName,
+	pb.UserService_ValidateToken_FullMethodName,
+}, auth.ReflectionMethods...)
+
+// RolePolicy перечисляет методы UserService, доступные только пользователям с определенными ролями.
+// Проверяется перехватчиками auth.UnaryRoleInterceptor и auth.StreamRoleInterceptor.
+var RolePolicy = auth.RolePolicy{
+	pb.UserService_GrantRole_FullMethodName:     {auth.RoleAdmin},
+	pb.UserService_RevokeRole_FullMethodName:    {auth.RoleAdmin},
+	pb.UserService_ListUsers_FullMethodName:     {auth.RoleAdmin},
+	pb.UserService_BatchGetUsers_FullMethodName: {auth.RoleAdmin, auth.RoleStaff},
+}
+
+// ScopePolicy перечисляет методы UserService, доступные с токеном клиента OAuth2: только чтение профиля.
+// Управление учетной записью требует токен входа пользователя.
+// Проверяется перехватчиками auth.UnaryScopeInterceptor и auth.StreamScopeInterceptor.
+var ScopePolicy = auth.ScopePolicy{
+	pb.UserService_GetUser_FullMethodName: {auth.ScopeUsersRead, auth.ScopeOrdersWrite},
+}
+
 // UserGRPCHandler реализует gRPC сервер для UserService.
 type UserGRPCHandler struct {
 	pb.UnimplementedUserServiceServer // Встраивание для обратной совместимости
 	userUsecase                       usecase.UserUsecase
+	authUsecase                       usecase.AuthUsecase
+	accountUsecase                    usecase.AccountUsecase
+	addressUsecase                    usecase.AddressUsecase
 }
 
 // NewUserGRPCHandler создает новый экземпляр UserGRPCHandler.
-func NewUserGRPCHandler(uc usecase.UserUsecase) *UserGRPCHandler {
-	return &UserGRPCHandler{userUsecase: uc}
+func NewUserGRPCHandler(uc usecase.UserUsecase, au usecase.AuthUsecase, acu usecase.AccountUsecase, adu usecase.AddressUsecase) *UserGRPCHandler {
+	return &UserGRPCHandler{userUsecase: uc, authUsecase: au, accountUsecase: acu, addressUsecase: adu}
 }
 
 // mapUserModelToProto преобразует модель User в proto-сообщение User.
@@ -30,13 +74,31 @@ func mapUserModelToProto(user *models.User) *pb.User {
 	if user == nil {
 		return nil
 	}
-	return &pb.User{
+	protoUser := &pb.User{
 		Id:        user.ID,
 		Username:  user.Username,
 		Email:     user.Email,
+		Roles:     user.Roles,
 		CreatedAt: timestamppb.New(user.CreatedAt), // Преобразование time.Time
 		UpdatedAt: timestamppb.New(user.UpdatedAt), // Преобразование time.Time
+
+		FullName:    user.FullName,
+		Phone:       user.Phone,
+		AvatarUrl:   user.AvatarURL,
+		DateOfBirth: user.DateOfBirth,
+		Locale:      user.Locale,
+	}
+	if user.EmailVerifiedAt != nil {
+		protoUser.EmailVerifiedAt = timestamppb.New(*user.EmailVerifiedAt)
 	}
+	return protoUser
+}
+
+// isCurrentUserOrRole проверяет, что вызов выполняет сам пользователь userID
+// или пользователь с одной из ролей roles.
+func isCurrentUserOrRole(ctx context.Context, userID string, roles ...string) bool {
+	currentUserID, ok := auth.UserIDFromContext(ctx)
+	return (ok && currentUserID == userID) || auth.HasRole(ctx, roles...)
 }
 
 // CreateUser обрабатывает gRPC запрос на создание пользователя.
@@ -51,8 +113,8 @@ func (h *UserGRPCHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequ
 		if errors.Is(err, usecase.ErrEmailExists) {
 			return nil, status.Errorf(codes.AlreadyExists, "Пользователь с таким email уже существует: %v", err)
 		}
-		if errors.Is(err, usecase.ErrPasswordTooShort) {
-			return nil, status.Errorf(codes.InvalidArgument, "Пароль слишком короткий: %v", err)
+		if usecase.IsPasswordPolicyError(err) {
+			return nil, status.Errorf(codes.InvalidArgument, "Недопустимый пароль: %v", err)
 		}
 		// TODO: Добавить обработку других специфичных ошибок usecase
 		return nil, status.Errorf(codes.Internal, "Ошибка при создании пользователя: %v", err)
@@ -66,6 +128,9 @@ func (h *UserGRPCHandler) GetUser(ctx context.Context, req *pb.GetUserRequest) (
 	if req.GetId() == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "ID пользователя не может быть пустым")
 	}
+	if !isCurrentUserOrRole(ctx, req.GetId(), auth.RoleAdmin, auth.RoleStaff) {
+		return nil, status.Errorf(codes.PermissionDenied, "Недостаточно прав для просмотра пользователя")
+	}
 
 	user, err := h.userUsecase.FindUserByID(ctx, req.GetId())
 	if err != nil {
@@ -84,6 +149,9 @@ func (h *UserGRPCHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequ
 	if userID == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "ID пользователя для обновления не может быть пустым")
 	}
+	if !isCurrentUserOrRole(ctx, userID, auth.RoleAdmin) {
+		return nil, status.Errorf(codes.PermissionDenied, "Недостаточно прав для изменения пользователя")
+	}
 
 	// Подготовка входных данных для usecase.UpdateUser
 	updateInput := usecase.UpdateUserInput{}
@@ -100,9 +168,14 @@ func (h *UserGRPCHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequ
 		emailVal := req.GetEmail().GetValue() // Для wrapperspb.StringValue
 		updateInput.Email = &emailVal
 	}
+	updateInput.FullName = optionalString(req.GetFullName())
+	updateInput.Phone = optionalString(req.GetPhone())
+	updateInput.AvatarURL = optionalString(req.GetAvatarUrl())
+	updateInput.DateOfBirth = optionalString(req.GetDateOfBirth())
+	updateInput.Locale = optionalString(req.GetLocale())
 
 	// Проверка, есть ли вообще что обновлять
-	if updateInput.Username == nil && updateInput.Email == nil {
+	if updateInput.Empty() {
 		// Можно вернуть ошибку или текущее состояние пользователя
 		// Здесь мы возвращаем ошибку, т.к. запрос на обновление без данных для обновления бессмысленен
 		return nil, status.Errorf(codes.InvalidArgument, "Нет данных для обновления")
@@ -115,6 +188,8 @@ func (h *UserGRPCHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequ
 			return nil, status.Errorf(codes.NotFound, "Пользователь для обновления не найден: %v", err)
 		case errors.Is(err, usecase.ErrEmailExists):
 			return nil, status.Errorf(codes.AlreadyExists, "Новый email уже используется другим пользователем: %v", err)
+		case errors.Is(err, usecase.ErrInvalidProfile):
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
 		// TODO: Обработать другие специфичные ошибки usecase, если они появятся
 		default:
 			return nil, status.Errorf(codes.Internal, "Ошибка при обновлении пользователя: %v", err)
@@ -124,5 +199,387 @@ func (h *UserGRPCHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequ
 	return &pb.UpdateUserResponse{User: mapUserModelToProto(updatedUser)}, nil
 }
 
-// TODO: Реализуйте другие gRPC методы, определенные в вашем user.proto
-// Например, AuthenticateUser, DeleteUser и т.д.
+// optionalString возвращает значение обертки или nil, если поле не передано
+func optionalString(value *wrapperspb.StringValue) *string {
+	if value == nil {
+		return nil
+	}
+	v := value.GetValue()
+	return &v
+}
+
+// GrantRole обрабатывает gRPC запрос на выдачу роли пользователю (только ADMIN, см. RolePolicy).
+func (h *UserGRPCHandler) GrantRole(ctx context.Context, req *pb.GrantRoleRequest) (*pb.RoleResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID пользователя не может быть пустым")
+	}
+
+	user, err := h.userUsecase.GrantRole(ctx, req.GetUserId(), req.GetRole())
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+	return &pb.RoleResponse{User: mapUserModelToProto(user)}, nil
+}
+
+// RevokeRole обрабатывает gRPC запрос на отзыв роли у пользователя (только ADMIN, см. RolePolicy).
+func (h *UserGRPCHandler) RevokeRole(ctx context.Context, req *pb.RevokeRoleRequest) (*pb.RoleResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID пользователя не может быть пустым")
+	}
+
+	user, err := h.userUsecase.RevokeRole(ctx, req.GetUserId(), req.GetRole())
+	if err != nil {
+		return nil, mapRoleError(err)
+	}
+	return &pb.RoleResponse{User: mapUserModelToProto(user)}, nil
+}
+
+// mapRoleError преобразует ошибки изменения ролей в статусы gRPC.
+func mapRoleError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		return status.Errorf(codes.NotFound, "Пользователь не найден: %v", err)
+	case errors.Is(err, usecase.ErrInvalidRole):
+		return status.Errorf(codes.InvalidArgument, "Неизвестная роль: %v", err)
+	default:
+		return status.Errorf(codes.Internal, "Ошибка при изменении ролей: %v", err)
+	}
+}
+
+// mapTokenPairToProto преобразует пару токенов в proto-сообщение TokenPair.
+func mapTokenPairToProto(pair *usecase.TokenPair) *pb.TokenPair {
+	return &pb.TokenPair{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(pair.ExpiresIn.Seconds()),
+	}
+}
+
+// RefreshToken обрабатывает gRPC запрос на обмен токена обновления на новую пару токенов.
+func (h *UserGRPCHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.TokenPair, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Токен обновления не может быть пустым")
+	}
+
+	pair, err := h.authUsecase.Refresh(ctx, req.GetRefreshToken())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRefreshTokenReused), errors.Is(err, usecase.ErrInvalidRefreshToken):
+			return nil, status.Errorf(codes.Unauthenticated, "Не удалось обновить токен: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "Ошибка при обновлении токена: %v", err)
+		}
+	}
+
+	return mapTokenPairToProto(pair), nil
+}
+
+// Logout обрабатывает gRPC запрос на выход: отзывает токен доступа из метаданных и токены обновления.
+func (h *UserGRPCHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*emptypb.Empty, error) {
+	if err := h.authUsecase.Logout(ctx, req.GetRefreshToken(), req.GetAllSessions()); err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			return nil, status.Errorf(codes.InvalidArgument, "Недействительный токен обновления: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "Ошибка при выходе: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ChangePassword обрабатывает gRPC запрос на смену пароля текущего пользователя.
+func (h *UserGRPCHandler) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*emptypb.Empty, error) {
+	if req.GetCurrentPassword() == "" || req.GetNewPassword() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Текущий и новый пароль не могут быть пустыми")
+	}
+
+	userID, _ := auth.UserIDFromContext(ctx)
+	if err := h.userUsecase.ChangePassword(ctx, userID, req.GetCurrentPassword(), req.GetNewPassword()); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			return nil, status.Errorf(codes.PermissionDenied, "Неверный текущий пароль: %v", err)
+		case usecase.IsPasswordPolicyError(err):
+			return nil, status.Errorf(codes.InvalidArgument, "Недопустимый пароль: %v", err)
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, "Пользователь не найден: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "Ошибка при смене пароля: %v", err)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RequestPasswordReset обрабатывает gRPC запрос на отправку токена сброса пароля.
+func (h *UserGRPCHandler) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*emptypb.Empty, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Email не может быть пустым")
+	}
+
+	if err := h.userUsecase.RequestPasswordReset(ctx, req.GetEmail()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Ошибка при отправке токена сброса пароля: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ResetPassword обрабатывает gRPC запрос на установку нового пароля по токену сброса.
+func (h *UserGRPCHandler) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*emptypb.Empty, error) {
+	if req.GetToken() == "" || req.GetNewPassword() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Токен и новый пароль не могут быть пустыми")
+	}
+
+	if err := h.userUsecase.ResetPassword(ctx, req.GetToken(), req.GetNewPassword()); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidResetToken), errors.Is(err, usecase.ErrUserNotFound):
+			return nil, status.Errorf(codes.InvalidArgument, "Недействительный или истекший токен сброса пароля: %v", err)
+		case usecase.IsPasswordPolicyError(err):
+			return nil, status.Errorf(codes.InvalidArgument, "Недопустимый пароль: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "Ошибка при сбросе пароля: %v", err)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// VerifyEmail обрабатывает gRPC запрос на подтверждение email по токену.
+func (h *UserGRPCHandler) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Токен не может быть пустым")
+	}
+
+	user, err := h.userUsecase.VerifyEmail(ctx, req.GetToken())
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidVerificationToken) {
+			return nil, status.Errorf(codes.InvalidArgument, "Недействительный или истекший токен подтверждения email: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "Ошибка при подтверждении email: %v", err)
+	}
+	return &pb.VerifyEmailResponse{User: mapUserModelToProto(user)}, nil
+}
+
+// ResendVerificationEmail обрабатывает gRPC запрос на повторную отправку токена подтверждения email.
+func (h *UserGRPCHandler) ResendVerificationEmail(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	userID, _ := auth.UserIDFromContext(ctx)
+	if err := h.userUsecase.SendVerificationEmail(ctx, userID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrEmailAlreadyVerified):
+			return nil, status.Errorf(codes.FailedPrecondition, "Email уже подтвержден: %v", err)
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, "Пользователь не найден: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "Ошибка при отправке токена подтверждения email: %v", err)
+		}
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Login обрабатывает gRPC запрос на вход по email и паролю.
+// Ошибки соответствуют REST /api/auth/login: неверные данные - Unauthenticated,
+// превышение попыток - ResourceExhausted с заголовком retry-after в секундах.
+func (h *UserGRPCHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	if req.GetEmail() == "" || req.GetPassword() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Email и пароль не могут быть пустыми")
+	}
+
+	result, err := h.authUsecase.Login(ctx, req.GetEmail(), req.GetPassword(), clientIP(ctx))
+	if err != nil {
+		var throttled *usecase.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			retryAfter := strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds())))
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter))
+			return nil, status.Errorf(codes.ResourceExhausted, "Слишком много неудачных попыток входа: %v", err)
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			return nil, status.Errorf(codes.Unauthenticated, "Неверный email или пароль")
+		case errors.Is(err, usecase.ErrAccountDeactivated), errors.Is(err, usecase.ErrAccountDeleted):
+			return nil, status.Errorf(codes.PermissionDenied, "Вход запрещен: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "Ошибка при входе: %v", err)
+		}
+	}
+	return mapLoginResultToProto(result), nil
+}
+
+// VerifyTwoFactor обрабатывает gRPC запрос на завершение входа кодом двухфакторной аутентификации.
+func (h *UserGRPCHandler) VerifyTwoFactor(ctx context.Context, req *pb.VerifyTwoFactorRequest) (*pb.LoginResponse, error) {
+	if req.GetChallenge() == "" || req.GetCode() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Challenge и код не могут быть пустыми")
+	}
+
+	result, err := h.authUsecase.VerifyTwoFactor(ctx, req.GetChallenge(), req.GetCode())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidLoginChallenge), errors.Is(err, usecase.ErrInvalidTwoFactorCode):
+			return nil, status.Errorf(codes.Unauthenticated, "Не удалось выполнить вход: %v", err)
+		case errors.Is(err, usecase.ErrAccountDeactivated), errors.Is(err, usecase.ErrAccountDeleted):
+			return nil, status.Errorf(codes.PermissionDenied, "Вход запрещен: %v", err)
+		default:
+			return nil, status.Errorf(codes.Internal, "Ошибка при входе: %v", err)
+		}
+	}
+	return mapLoginResultToProto(result), nil
+}
+
+// mapLoginResultToProto преобразует результат входа в proto-сообщение LoginResponse.
+func mapLoginResultToProto(result *usecase.LoginResult) *pb.LoginResponse {
+	response := &pb.LoginResponse{
+		User:                        mapUserModelToProto(result.User),
+		TwoFactorEnrollmentRequired: result.TwoFactorEnrollmentRequired,
+	}
+	if result.Challenge != nil {
+		response.TwoFactorRequired = true
+		response.Challenge = result.Challenge.Token
+		response.ChallengeExpiresIn = int64(result.Challenge.ExpiresIn.Seconds())
+	}
+	if result.Tokens != nil {
+		response.Tokens = mapTokenPairToProto(result.Tokens)
+	}
+	return response
+}
+
+// clientIP возвращает IP-адрес клиента из адреса соединения для учета попыток входа.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// ValidateToken обрабатывает gRPC запрос на проверку токена доступа другим сервисом.
+// Для токена клиента OAuth2 возвращаются его разрешения и client_id: права ролей такого токена
+// ограничены разрешениями, и вызывающий не должен принимать его за токен входа пользователя.
+func (h *UserGRPCHandler) ValidateToken(ctx context.Context, req *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Токен не может быть пустым")
+	}
+
+	claims, err := h.authUsecase.ValidateToken(req.GetToken())
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Недействительный токен: %v", err)
+	}
+
+	response := &pb.ValidateTokenResponse{
+		UserId:   claims.UserID,
+		Roles:    claims.Roles,
+		TokenId:  claims.ID,
+		Scopes:   claims.Scopes,
+		ClientId: claims.ClientID,
+	}
+	if claims.ExpiresAt != nil {
+		response.ExpiresAt = timestamppb.New(claims.ExpiresAt.Time)
+	}
+	return response, nil
+}
+
+// DeleteUser обрабатывает gRPC запрос на удаление пользователя (сам пользователь или ADMIN).
+// Учетная запись отмечается удаленной и обезличивается после срока ожидания.
+func (h *UserGRPCHandler) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if req.GetId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ID пользователя не может быть пустым")
+	}
+	if !isCurrentUserOrRole(ctx, req.GetId(), auth.RoleAdmin) {
+		return nil, status.Errorf(codes.PermissionDenied, "Недостаточно прав для удаления пользователя")
+	}
+
+	actorID, _ := auth.UserIDFromContext(ctx)
+	if _, err := h.accountUsecase.DeleteUser(ctx, req.GetId(), actorID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFound):
+			return nil, status.Errorf(codes.NotFound, "Пользователь не найден: %v", err)
+		case errors.Is(err, usecase.ErrAccountErased):
+			return nil, status.Errorf(codes.FailedPrecondition, "Учетная запись уже обезличена: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "Ошибка при удалении пользователя: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListUsers передает пользователей страницами, начиная с page_token, пока они не закончатся
+// или клиент не отменит вызов (только ADMIN, см. RolePolicy). Общее число total считается
+// один раз, на первой странице, и повторяется в остальных.
+func (h *UserGRPCHandler) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
+	ctx := stream.Context()
+	query := usecase.UserListQuery{
+		Filter: repository.UserFilter{
+			EmailPrefix:    req.GetEmailPrefix(),
+			UsernamePrefix: req.GetUsernamePrefix(),
+			Role:           req.GetRole(),
+		},
+		Sort:      req.GetSort(),
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+		WithTotal: true,
+	}
+	if req.GetCreatedFrom() != nil {
+		query.Filter.CreatedFrom = req.GetCreatedFrom().AsTime()
+	}
+	if req.GetCreatedTo() != nil {
+		query.Filter.CreatedTo = req.GetCreatedTo().AsTime()
+	}
+	if req.GetVerified() != nil {
+		verified := req.GetVerified().GetValue()
+		query.Filter.Verified = &verified
+	}
+
+	var total int64
+	for {
+		page, err := h.userUsecase.ListUsersPage(ctx, query)
+		if err != nil {
+			switch {
+			case errors.Is(err, usecase.ErrInvalidPageToken):
+				return status.Errorf(codes.InvalidArgument, "Некорректный токен страницы: %v", err)
+			case errors.Is(err, usecase.ErrInvalidSort), errors.Is(err, usecase.ErrInvalidRole):
+				return status.Errorf(codes.InvalidArgument, "Некорректный запрос: %v", err)
+			}
+			return status.Errorf(codes.Internal, "Ошибка при получении списка пользователей: %v", err)
+		}
+		if query.WithTotal {
+			total = page.Total
+			query.WithTotal = false
+		}
+
+		response := &pb.ListUsersResponse{
+			Users:         make([]*pb.User, 0, len(page.Users)),
+			NextPageToken: page.NextPageToken,
+			Total:         total,
+		}
+		for _, user := range page.Users {
+			response.Users = append(response.Users, mapUserModelToProto(user))
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+
+		if page.NextPageToken == "" {
+			return nil
+		}
+		query.PageToken = page.NextPageToken
+	}
+}
+
+// BatchGetUsers обрабатывает gRPC запрос на получение нескольких пользователей (ADMIN и STAFF, см. RolePolicy).
+func (h *UserGRPCHandler) BatchGetUsers(ctx context.Context, req *pb.BatchGetUsersRequest) (*pb.BatchGetUsersResponse, error) {
+	users, err := h.userUsecase.BatchGetUsers(ctx, req.GetIds())
+	if err != nil {
+		if errors.Is(err, usecase.ErrTooManyIDs) {
+			return nil, status.Errorf(codes.InvalidArgument, "Не больше %d ID за запрос: %v", usecase.MaxBatchGetUsers, err)
+		}
+		return nil, status.Errorf(codes.Internal, "Ошибка при получении пользователей: %v", err)
+	}
+
+	response := &pb.BatchGetUsersResponse{Users: make([]*pb.User, 0, len(users))}
+	found := make(map[string]bool, len(users))
+	for _, user := range users {
+		response.Users = append(response.Users, mapUserModelToProto(user))
+		found[user.ID] = true
+	}
+	for _, id := range req.GetIds() {
+		if parsed, err := uuid.Parse(id); err != nil || !found[parsed.String()] {
+			response.MissingIds = append(response.MissingIds, id)
+		}
+	}
+	return response, nil
+}
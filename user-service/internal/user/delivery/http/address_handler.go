@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
+)
+
+// addressRequest - тело запроса на создание или изменение адреса
+type addressRequest struct {
+	Label      string `json:"label"`
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+// input преобразует тело запроса во входные данные usecase
+func (req addressRequest) input() usecase.AddressInput {
+	return usecase.AddressInput{
+		Label:      req.Label,
+		Recipient:  req.Recipient,
+		Phone:      req.Phone,
+		Line1:      req.Line1,
+		Line2:      req.Line2,
+		City:       req.City,
+		Region:     req.Region,
+		PostalCode: req.PostalCode,
+		Country:    req.Country,
+		Default:    req.IsDefault,
+	}
+}
+
+// handleAddresses обрабатывает адресную книгу /api/users/{id}/addresses. rest - части пути после "addresses":
+//
+//	GET, POST        /api/users/{id}/addresses
+//	GET, PUT, DELETE /api/users/{id}/addresses/{address_id}
+//	POST             /api/users/{id}/addresses/{address_id}/default
+func (h *UserHTTPHandler) handleAddresses(w http.ResponseWriter, r *http.Request, userID string, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		addresses, err := h.addressUsecase.ListAddresses(r.Context(), userID)
+		if err != nil {
+			writeAddressError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(addresses)
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		req, ok := decodeAddressRequest(w, r)
+		if !ok {
+			return
+		}
+		address, err := h.addressUsecase.CreateAddress(r.Context(), userID, req.input())
+		writeAddressResult(w, http.StatusCreated, address, err)
+	case len(rest) == 1 && r.Method == http.MethodGet:
+		address, err := h.addressUsecase.GetAddress(r.Context(), userID, rest[0])
+		writeAddressResult(w, http.StatusOK, address, err)
+	case len(rest) == 1 && r.Method == http.MethodPut:
+		req, ok := decodeAddressRequest(w, r)
+		if !ok {
+			return
+		}
+		address, err := h.addressUsecase.UpdateAddress(r.Context(), userID, rest[0], req.input())
+		writeAddressResult(w, http.StatusOK, address, err)
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		if err := h.addressUsecase.DeleteAddress(r.Context(), userID, rest[0]); err != nil {
+			writeAddressError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(rest) == 2 && rest[1] == "default" && r.Method == http.MethodPost:
+		address, err := h.addressUsecase.SetDefaultAddress(r.Context(), userID, rest[0])
+		writeAddressResult(w, http.StatusOK, address, err)
+	case len(rest) == 2 && rest[1] != "default":
+		http.Error(w, "Некорректный путь адреса", http.StatusNotFound)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// decodeAddressRequest читает тело запроса с адресом; при ошибке отвечает 400
+func decodeAddressRequest(w http.ResponseWriter, r *http.Request) (addressRequest, bool) {
+	var req addressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return req, false
+	}
+	return req, true
+}
+
+// writeAddressResult отвечает адресом или ошибкой.
+func writeAddressResult(w http.ResponseWriter, code int, address *models.Address, err error) {
+	if err != nil {
+		writeAddressError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(address)
+}
+
+// writeAddressError переводит ошибки адресной книги в HTTP-статусы.
+func writeAddressError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrAddressNotFound):
+		http.Error(w, "Адрес не найден", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvalidProfile):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrTooManyAddresses), errors.Is(err, usecase.ErrAccountErased):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Ошибка при работе с адресами: "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
+)
+
+// apiKeyRequest - тело запроса на выпуск API-ключа
+type apiKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"` // RFC 3339; не указан - бессрочный ключ
+	RateLimit int        `json:"rate_limit"` // Запросов в минуту; 0 - значение по умолчанию
+}
+
+// handleAPIKeys обрабатывает API-ключи пользователя /api/users/{id}/api-keys. rest - части пути после "api-keys":
+//
+//	GET, POST /api/users/{id}/api-keys
+//	DELETE    /api/users/{id}/api-keys/{key_id}
+func (h *UserHTTPHandler) handleAPIKeys(w http.ResponseWriter, r *http.Request, userID string, rest []string) {
+	actorID, _ := auth.UserIDFromContext(r.Context())
+
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		keys, err := h.apiKeyUsecase.ListAPIKeys(r.Context(), userID)
+		if err != nil {
+			writeAPIKeyError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(keys)
+	case len(rest) == 0 && r.Method == http.MethodPost:
+		var req apiKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		apiKey, key, err := h.apiKeyUsecase.CreateAPIKey(r.Context(), userID, actorID, usecase.APIKeyInput{
+			Name:      req.Name,
+			Scopes:    req.Scopes,
+			ExpiresAt: req.ExpiresAt,
+			RateLimit: req.RateLimit,
+		})
+		if err != nil {
+			writeAPIKeyError(w, err)
+			return
+		}
+		// Сам ключ возвращается только в этом ответе
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"api_key": apiKey,
+			"key":     key,
+		})
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		if err := h.apiKeyUsecase.RevokeAPIKey(r.Context(), userID, rest[0], actorID); err != nil {
+			writeAPIKeyError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleVerifyAPIKey проверяет API-ключ по запросу другого сервиса (auth.RemoteAPIKeys).
+// Отвечает сведениями о ключе или 401, если ключ недействителен.
+func (h *UserHTTPHandler) handleVerifyAPIKey(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	info, err := h.apiKeyUsecase.LookupAPIKey(r.Context(), requestBody.Key)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "Ошибка проверки API-ключа: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(info)
+}
+
+// allowAPIKeyReads ограничивает запросы с API-ключом или токеном OAuth2 к /api/users/: с ними можно только читать
+// профиль (GET /api/users/{id}) и адреса (GET /api/users/{id}/addresses[/{address_id}]),
+// и только с разрешением users:read или orders:write - order-service читает адрес доставки
+// при оформлении заказа от имени вызывающего. Остальные операции требуют токен входа.
+func allowAPIKeyReads(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := auth.ClaimsFromContext(r.Context())
+		if !ok || !claims.Scoped() {
+			next(w, r)
+			return
+		}
+
+		pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		readable := r.Method == http.MethodGet && (len(pathParts) == 3 ||
+			(len(pathParts) >= 4 && len(pathParts) <= 5 && pathParts[3] == "addresses"))
+		if !readable {
+			http.Error(w, "Операция недоступна по API-ключу или токену OAuth2", http.StatusForbidden)
+			return
+		}
+		if !claims.HasScope(auth.ScopeUsersRead, auth.ScopeOrdersWrite) {
+			http.Error(w, "Недостаточно разрешений API-ключа или токена OAuth2", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// writeAPIKeyError переводит ошибки API-ключей в HTTP-статусы.
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrAPIKeyNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvalidAPIKeyInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, usecase.ErrTooManyAPIKeys), errors.Is(err, usecase.ErrAccountErased):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, usecase.ErrAccountDeactivated), errors.Is(err, usecase.ErrAccountDeleted):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, "Ошибка при работе с API-ключами: "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,366 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
+)
+
+// loginTokenValidator пропускает только токены входа пользователя: токены OAuth2 и API-ключи
+// с ограниченными разрешениями не дают доступа к управлению учетной записью.
+type loginTokenValidator struct {
+	next auth.TokenValidator
+}
+
+// ValidateToken проверяет токен и отклоняет токены с разрешениями.
+func (v loginTokenValidator) ValidateToken(token string) (*auth.Claims, error) {
+	claims, err := v.next.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Scoped() {
+		return nil, fmt.Errorf("%w: нужен токен входа пользователя", auth.ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// handleAuthorize обрабатывает запрос авторизации OAuth2 от имени вошедшего пользователя.
+// Конечную точку вызывает собственная страница входа: она получает параметры запроса клиента,
+// показывает пользователю экран согласия (GET) и передает его решение (POST с approve=true|false).
+//
+//	GET  /oauth/authorize?response_type=code&client_id=...&redirect_uri=...&scope=...&state=...&code_challenge=...&code_challenge_method=S256
+//	POST /oauth/authorize (те же параметры и approve)
+func (h *UserHTTPHandler) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	userID, ok := auth.UserIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Требуется аутентификация", http.StatusUnauthorized)
+		return
+	}
+	req := usecase.AuthorizationRequest{
+		ResponseType:        r.FormValue("response_type"),
+		ClientID:            r.FormValue("client_id"),
+		RedirectURI:         r.FormValue("redirect_uri"),
+		Scope:               r.FormValue("scope"),
+		State:               r.FormValue("state"),
+		CodeChallenge:       r.FormValue("code_challenge"),
+		CodeChallengeMethod: r.FormValue("code_challenge_method"),
+		Nonce:               r.FormValue("nonce"),
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		prompt, err := h.oauthUsecase.Authorize(r.Context(), userID, req)
+		if err != nil {
+			writeAuthorizeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"client":           map[string]string{"client_id": prompt.Client.ID, "name": prompt.Client.Name},
+			"scopes":           prompt.Scopes,
+			"consent_required": prompt.ConsentRequired,
+		})
+	case http.MethodPost:
+		approved, err := strconv.ParseBool(r.FormValue("approve"))
+		if err != nil {
+			http.Error(w, "Параметр approve должен быть true или false", http.StatusBadRequest)
+			return
+		}
+		redirectTo, err := h.oauthUsecase.Decide(r.Context(), userID, req, approved)
+		if err != nil {
+			writeAuthorizeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"redirect_to": redirectTo})
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeAuthorizeError отвечает на ошибку запроса авторизации. Если клиент и адрес возврата проверены,
+// страница входа должна перенаправить пользователя на redirect_to; иначе ошибку нужно показать пользователю.
+func writeAuthorizeError(w http.ResponseWriter, err error) {
+	var oauthErr *usecase.OAuthError
+	if !errors.As(err, &oauthErr) {
+		if errors.Is(err, usecase.ErrAccountDeactivated) || errors.Is(err, usecase.ErrAccountDeleted) {
+			http.Error(w, "Авторизация запрещена: "+err.Error(), http.StatusForbidden)
+			return
+		}
+		writeAccountError(w, "Ошибка авторизации клиента: ", err)
+		return
+	}
+	response := map[string]string{"error": oauthErr.Code, "error_description": oauthErr.Description}
+	if oauthErr.RedirectURI != "" {
+		response["redirect_to"] = oauthErr.RedirectURI
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleToken выдает токены клиенту OAuth2 (RFC 6749, раздел 3.2). Параметры передаются формой,
+// клиент аутентифицируется заголовком Authorization: Basic или полями client_id и client_secret.
+func (h *UserHTTPHandler) handleToken(w http.ResponseWriter, r *http.Request) {
+	client, ok := clientAuthentication(w, r)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.oauthUsecase.Token(r.Context(), client, usecase.TokenRequest{
+		GrantType:    r.PostFormValue("grant_type"),
+		Code:         r.PostFormValue("code"),
+		RedirectURI:  r.PostFormValue("redirect_uri"),
+		CodeVerifier: r.PostFormValue("code_verifier"),
+		RefreshToken: r.PostFormValue("refresh_token"),
+		Scope:        r.PostFormValue("scope"),
+	})
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	response := struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int64  `json:"expires_in"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		Scope        string `json:"scope,omitempty"`
+		IDToken      string `json:"id_token,omitempty"`
+	}{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		RefreshToken: tokens.RefreshToken,
+		Scope:        strings.Join(tokens.Scopes, " "),
+		IDToken:      tokens.IDToken,
+	}
+	writeOAuthJSON(w, http.StatusOK, response)
+}
+
+// handleIntrospect сообщает конфиденциальному клиенту, действителен ли токен (RFC 7662).
+func (h *UserHTTPHandler) handleIntrospect(w http.ResponseWriter, r *http.Request) {
+	client, ok := clientAuthentication(w, r)
+	if !ok {
+		return
+	}
+
+	introspection, err := h.oauthUsecase.Introspect(r.Context(), client, r.PostFormValue("token"))
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+	writeOAuthJSON(w, http.StatusOK, introspection)
+}
+
+// handleRevoke отзывает токен клиента (RFC 7009). Неизвестный токен тоже считается отозванным.
+func (h *UserHTTPHandler) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	client, ok := clientAuthentication(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.oauthUsecase.Revoke(r.Context(), client, r.PostFormValue("token")); err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleOAuthMetadata отдает документ discovery. Один и тот же документ доступен по адресам
+// /.well-known/openid-configuration и /.well-known/oauth-authorization-server.
+func (h *UserHTTPHandler) handleOAuthMetadata(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.oauthUsecase.Metadata())
+}
+
+// clientAuthentication разбирает форму запроса и учетные данные клиента.
+// Клиент должен использовать один способ аутентификации: заголовок или поля формы.
+func clientAuthentication(w http.ResponseWriter, r *http.Request) (usecase.ClientAuthentication, bool) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, &usecase.OAuthError{Code: usecase.OAuthInvalidRequest, Description: "некорректная форма запроса"})
+		return usecase.ClientAuthentication{}, false
+	}
+
+	clientID, clientSecret, hasBasic := r.BasicAuth()
+	if hasBasic {
+		if r.PostForm.Has("client_secret") {
+			writeOAuthError(w, &usecase.OAuthError{Code: usecase.OAuthInvalidRequest, Description: "используйте один способ аутентификации клиента"})
+			return usecase.ClientAuthentication{}, false
+		}
+		// client_id и секрет в заголовке кодируются как в форме (RFC 6749, раздел 2.3.1)
+		if id, err := url.QueryUnescape(clientID); err == nil {
+			clientID = id
+		}
+		if secret, err := url.QueryUnescape(clientSecret); err == nil {
+			clientSecret = secret
+		}
+		return usecase.ClientAuthentication{ClientID: clientID, ClientSecret: clientSecret}, true
+	}
+	return usecase.ClientAuthentication{
+		ClientID:     r.PostFormValue("client_id"),
+		ClientSecret: r.PostFormValue("client_secret"),
+	}, true
+}
+
+// writeOAuthError отвечает ошибкой в формате OAuth2 (RFC 6749, раздел 5.2).
+func writeOAuthError(w http.ResponseWriter, err error) {
+	var oauthErr *usecase.OAuthError
+	if !errors.As(err, &oauthErr) {
+		writeOAuthJSON(w, http.StatusInternalServerError, map[string]string{
+			"error":             "server_error",
+			"error_description": "внутренняя ошибка сервера: " + err.Error(),
+		})
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == usecase.OAuthInvalidClient {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		status = http.StatusUnauthorized
+	}
+	writeOAuthJSON(w, status, map[string]string{"error": oauthErr.Code, "error_description": oauthErr.Description})
+}
+
+// writeOAuthJSON отвечает JSON, запрещая кеширование токенов.
+func writeOAuthJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// handleOAuthClients обрабатывает регистрацию клиентов OAuth2 (только ADMIN):
+//
+//	GET    /api/oauth/clients
+//	POST   /api/oauth/clients
+//	GET    /api/oauth/clients/{id}
+//	DELETE /api/oauth/clients/{id}
+func (h *UserHTTPHandler) handleOAuthClients(w http.ResponseWriter, r *http.Request) {
+	clientID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/oauth/clients"), "/")
+
+	switch {
+	case clientID == "" && r.Method == http.MethodGet:
+		clients, err := h.oauthUsecase.ListClients(r.Context())
+		if err != nil {
+			writeOAuthClientError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(clients)
+	case clientID == "" && r.Method == http.MethodPost:
+		h.createOAuthClient(w, r)
+	case clientID != "" && !strings.Contains(clientID, "/") && r.Method == http.MethodGet:
+		client, err := h.oauthUsecase.GetClient(r.Context(), clientID)
+		if err != nil {
+			writeOAuthClientError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(client)
+	case clientID != "" && !strings.Contains(clientID, "/") && r.Method == http.MethodDelete:
+		if err := h.oauthUsecase.DeleteClient(r.Context(), clientID); err != nil {
+			writeOAuthClientError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case strings.Contains(clientID, "/"):
+		http.Error(w, "Некорректный путь клиента OAuth2", http.StatusNotFound)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// createOAuthClient регистрирует клиента. Секрет конфиденциального клиента возвращается только в этом ответе.
+func (h *UserHTTPHandler) createOAuthClient(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		Name         string   `json:"name"`
+		RedirectURIs []string `json:"redirect_uris"`
+		GrantTypes   []string `json:"grant_types"`
+		Scopes       []string `json:"scopes"`
+		Confidential bool     `json:"confidential"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	client, secret, err := h.oauthUsecase.CreateClient(r.Context(), usecase.OAuthClientInput{
+		Name:         requestBody.Name,
+		RedirectURIs: requestBody.RedirectURIs,
+		GrantTypes:   requestBody.GrantTypes,
+		Scopes:       requestBody.Scopes,
+		Confidential: requestBody.Confidential,
+	})
+	if err != nil {
+		writeOAuthClientError(w, err)
+		return
+	}
+
+	response := map[string]interface{}{"client": client}
+	if secret != "" {
+		response["client_secret"] = secret
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleConsents обрабатывает согласия пользователя клиентам OAuth2 /api/users/{id}/consents:
+//
+//	GET    /api/users/{id}/consents
+//	DELETE /api/users/{id}/consents/{client_id}
+func (h *UserHTTPHandler) handleConsents(w http.ResponseWriter, r *http.Request, userID string, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		consents, err := h.oauthUsecase.ListConsents(r.Context(), userID)
+		if err != nil {
+			writeOAuthClientError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(consents)
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		actorID, _ := auth.UserIDFromContext(r.Context())
+		if err := h.oauthUsecase.RevokeConsent(r.Context(), userID, rest[0], actorID); err != nil {
+			writeOAuthClientError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeOAuthClientError переводит ошибки управления клиентами и согласиями в HTTP-статусы.
+func writeOAuthClientError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrOAuthClientNotFound), errors.Is(err, usecase.ErrConsentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvalidOAuthClientInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+	}
+}
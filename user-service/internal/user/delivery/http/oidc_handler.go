@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
+)
+
+// handleOIDC обрабатывает вход через внешних провайдеров OpenID Connect:
+//
+//	GET  /api/auth/oidc/providers
+//	POST /api/auth/oidc/{provider}/start
+//	POST /api/auth/oidc/{provider}/callback
+func (h *UserHTTPHandler) handleOIDC(w http.ResponseWriter, r *http.Request) {
+	pathParts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/auth/oidc/"), "/"), "/")
+
+	switch {
+	case len(pathParts) == 1 && pathParts[0] == "providers" && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string][]string{"providers": h.oidcUsecase.Providers()})
+	case len(pathParts) == 2 && pathParts[1] == "start" && r.Method == http.MethodPost:
+		h.startOIDCLogin(w, r, pathParts[0])
+	case len(pathParts) == 2 && pathParts[1] == "callback" && r.Method == http.MethodPost:
+		h.completeOIDCLogin(w, r, pathParts[0])
+	case len(pathParts) == 2 && (pathParts[1] == "start" || pathParts[1] == "callback"),
+		len(pathParts) == 1 && pathParts[0] == "providers":
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	default:
+		http.Error(w, "Некорректный путь входа через провайдер", http.StatusNotFound)
+	}
+}
+
+// startOIDCLogin возвращает адрес страницы входа провайдера. Клиент отправляет туда пользователя,
+// а после возврата на OIDC_{PROVIDER}_REDIRECT_URL передает code и state в callback.
+func (h *UserHTTPHandler) startOIDCLogin(w http.ResponseWriter, r *http.Request, provider string) {
+	authorization, err := h.oidcUsecase.StartLogin(r.Context(), provider)
+	if err != nil {
+		writeOIDCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"authorization_url": authorization.URL,
+		"state":             authorization.State,
+		"expires_at":        authorization.ExpiresAt,
+	})
+}
+
+// completeOIDCLogin завершает вход кодом авторизации провайдера. Ответ совпадает с ответом /api/auth/login.
+func (h *UserHTTPHandler) completeOIDCLogin(w http.ResponseWriter, r *http.Request, provider string) {
+	var requestBody struct {
+		Code  string `json:"code"`
+		State string `json:"state"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if requestBody.Code == "" || requestBody.State == "" {
+		http.Error(w, "Code и state не могут быть пустыми", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.oidcUsecase.CompleteLogin(r.Context(), provider, requestBody.Code, requestBody.State, clientIP(r))
+	if err != nil {
+		writeOIDCError(w, err)
+		return
+	}
+	writeLoginResult(w, result)
+}
+
+// handleIdentities обрабатывает привязанные учетные записи провайдеров /api/users/{id}/identities:
+//
+//	GET    /api/users/{id}/identities
+//	DELETE /api/users/{id}/identities/{provider}
+func (h *UserHTTPHandler) handleIdentities(w http.ResponseWriter, r *http.Request, userID string, rest []string) {
+	switch {
+	case len(rest) == 0 && r.Method == http.MethodGet:
+		identities, err := h.oidcUsecase.ListIdentities(r.Context(), userID)
+		if err != nil {
+			writeOIDCError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(identities)
+	case len(rest) == 1 && r.Method == http.MethodDelete:
+		actorID, _ := auth.UserIDFromContext(r.Context())
+		if err := h.oidcUsecase.UnlinkIdentity(r.Context(), userID, rest[0], actorID); err != nil {
+			writeOIDCError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeOIDCError переводит ошибки входа через провайдер в HTTP-статусы.
+func writeOIDCError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrOIDCProviderNotFound), errors.Is(err, usecase.ErrIdentityNotFound),
+		errors.Is(err, usecase.ErrOIDCAccountNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, usecase.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrInvalidOIDCState), errors.Is(err, usecase.ErrOIDCAuthentication):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, usecase.ErrOIDCEmailNotVerified), errors.Is(err, usecase.ErrOIDCLinkNotAllowed),
+		errors.Is(err, usecase.ErrAccountDeactivated), errors.Is(err, usecase.ErrAccountDeleted):
+		http.Error(w, "Вход запрещен: "+err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.ErrIdentityConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, usecase.ErrOIDCProviderUnavailable):
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	default:
+		http.Error(w, "Ошибка входа через провайдер: "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -3,9 +3,16 @@ package http
 import (
 	"encoding/json"
 	"errors"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"strings" // Для извлечения ID из URL в handleUserByID, если используется стандартный ServeMux
+	"time"
 
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
 	// Для более удобной маршрутизации и извлечения параметров URL можно использовать
 	// "github.com/go-chi/chi/v5" или "github.com/gorilla/mux".
@@ -17,41 +24,73 @@ import (
 
 // UserHTTPHandler обрабатывает HTTP запросы, связанные с пользователями.
 type UserHTTPHandler struct {
-	userUsecase usecase.UserUsecase
-	jwtManager  usecase.JWTManager
+	userUsecase    usecase.UserUsecase
+	authUsecase    usecase.AuthUsecase
+	accountUsecase usecase.AccountUsecase
+	addressUsecase usecase.AddressUsecase
+	apiKeyUsecase  usecase.APIKeyUsecase
+	oidcUsecase    usecase.OIDCUsecase
+	oauthUsecase   usecase.OAuthUsecase
+	jwtManager     usecase.JWTManager
+	// apiKeyValidator принимает кроме токенов API-ключи; используется только для маршрутов /api/users/
+	apiKeyValidator auth.TokenValidator
+	// loginTokens принимает только токены входа пользователя, без токенов OAuth2
+	loginTokens auth.TokenValidator
 }
 
 // NewUserHTTPHandler создает новый экземпляр UserHTTPHandler.
-func NewUserHTTPHandler(uc usecase.UserUsecase, jm usecase.JWTManager) *UserHTTPHandler {
+func NewUserHTTPHandler(uc usecase.UserUsecase, au usecase.AuthUsecase, acu usecase.AccountUsecase, adu usecase.AddressUsecase,
+	aku usecase.APIKeyUsecase, ou usecase.OIDCUsecase, oau usecase.OAuthUsecase, jm usecase.JWTManager) *UserHTTPHandler {
 	return &UserHTTPHandler{
-		userUsecase: uc,
-		jwtManager:  jm,
+		userUsecase:     uc,
+		authUsecase:     au,
+		accountUsecase:  acu,
+		addressUsecase:  adu,
+		apiKeyUsecase:   aku,
+		oidcUsecase:     ou,
+		oauthUsecase:    oau,
+		jwtManager:      jm,
+		apiKeyValidator: auth.WithAPIKeys(jm, aku),
+		loginTokens:     loginTokenValidator{next: jm},
 	}
 }
 
 // RegisterRoutes регистрирует HTTP маршруты для обработчика пользователей.
 // Принимает *http.ServeMux, но можно адаптировать для других роутеров.
 func (h *UserHTTPHandler) RegisterRoutes(router *http.ServeMux) {
-	// Для /api/users: POST для создания
+	// Для /api/users: POST для создания (открыт), GET для списка (только ADMIN)
 	router.HandleFunc("/api/users", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
+		switch r.Method {
+		case http.MethodPost:
 			h.createUser(w, r)
-		} else {
+		case http.MethodGet:
+			auth.RequireAuthRole(h.loginTokens, h.listUsers, auth.RoleAdmin)(w, r)
+		default:
 			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
 		}
 	})
 
-	// Для /api/users/{id}: GET для получения, PUT для обновления
+	// Для /api/users/{id}: GET для получения, PUT для обновления, DELETE для удаления.
+	// Для /api/users/{id}/roles: POST для выдачи роли, для /api/users/{id}/roles/{role}: DELETE для отзыва.
+	// Для /api/users/{id}/unlock: POST снимает блокировку входа после неудачных попыток.
+	// Для /api/users/{id}/deactivate, /reactivate и /restore: POST меняет состояние учетной записи,
+	// для /api/users/{id}/export: GET выгружает данные пользователя вместе с заказами.
+	// Для /api/users/{id}/addresses[/{address_id}[/default]]: адресная книга пользователя.
+	// Для /api/users/{id}/api-keys[/{key_id}]: выпуск, список и отзыв API-ключей.
+	// Для /api/users/{id}/identities[/{provider}]: привязанные учетные записи провайдеров OpenID Connect.
+	// Для /api/users/{id}/consents[/{client_id}]: согласия, выданные клиентам OAuth2.
 	// Обратите внимание: стандартный ServeMux не поддерживает параметры пути типа {id} напрямую.
 	// Мы будем извлекать ID из r.URL.Path.
 	// Более продвинутые роутеры (chi, gorilla/mux) делают это элегантнее.
-	router.HandleFunc("/api/users/", func(w http.ResponseWriter, r *http.Request) {
-		// Извлекаем ID из пути. Пример: /api/users/some-uuid-string
-		// Этот способ извлечения ID подходит для путей, заканчивающихся на ID.
-		// Если есть еще что-то после ID, потребуется более сложный парсинг.
+	// Все операции требуют токен. Читать учетную запись может сам пользователь, ADMIN или STAFF,
+	// изменять, удалять, деактивировать, выгружать, вести адресную книгу, API-ключи, привязки и согласия - сам пользователь или ADMIN,
+	// управлять ролями и блокировкой, снимать деактивацию и отменять удаление - только ADMIN.
+	// По API-ключу или токену OAuth2 доступно только чтение профиля и адресов (см. allowAPIKeyReads).
+	router.HandleFunc("/api/users/", auth.RequireAuth(h.apiKeyValidator, allowAPIKeyReads(func(w http.ResponseWriter, r *http.Request) {
+		// Извлекаем ID и, если есть, подресурс из пути. Пример: /api/users/some-uuid-string/roles/STAFF
 		pathParts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
 		var userID string
-		if len(pathParts) == 3 && pathParts[0] == "api" && pathParts[1] == "users" {
+		if len(pathParts) >= 3 && pathParts[0] == "api" && pathParts[1] == "users" {
 			userID = pathParts[2]
 		}
 
@@ -60,27 +99,152 @@ func (h *UserHTTPHandler) RegisterRoutes(router *http.ServeMux) {
 			return
 		}
 
-		switch r.Method {
-		case http.MethodGet:
-			h.getUserByID(w, r, userID)
-		case http.MethodPut:
-			h.updateUser(w, r, userID)
-		case http.MethodDelete:
-			h.deleteUser(w, r, userID) // TODO: Реализовать
-		default:
+		switch {
+		case len(pathParts) == 3:
+			h.handleUser(w, r, userID)
+		case len(pathParts) == 4 && pathParts[3] == "roles" && r.Method == http.MethodPost:
+			auth.RequireRole(auth.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.grantRole(w, r, userID)
+			})).ServeHTTP(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "unlock" && r.Method == http.MethodPost:
+			auth.RequireRole(auth.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.unlockUser(w, r, userID)
+			})).ServeHTTP(w, r)
+		case len(pathParts) == 5 && pathParts[3] == "roles" && r.Method == http.MethodDelete:
+			auth.RequireRole(auth.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.revokeRole(w, r, userID, pathParts[4])
+			})).ServeHTTP(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "deactivate" && r.Method == http.MethodPost:
+			if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+				http.Error(w, "Недостаточно прав для деактивации пользователя", http.StatusForbidden)
+				return
+			}
+			h.deactivateUser(w, r, userID)
+		case len(pathParts) == 4 && pathParts[3] == "reactivate" && r.Method == http.MethodPost:
+			auth.RequireRole(auth.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.reactivateUser(w, r, userID)
+			})).ServeHTTP(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "restore" && r.Method == http.MethodPost:
+			auth.RequireRole(auth.RoleAdmin)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.restoreUser(w, r, userID)
+			})).ServeHTTP(w, r)
+		case len(pathParts) == 4 && pathParts[3] == "export" && r.Method == http.MethodGet:
+			if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+				http.Error(w, "Недостаточно прав для выгрузки данных пользователя", http.StatusForbidden)
+				return
+			}
+			h.exportUserData(w, r, userID)
+		case len(pathParts) >= 4 && len(pathParts) <= 6 && pathParts[3] == "addresses":
+			if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+				http.Error(w, "Недостаточно прав для работы с адресами пользователя", http.StatusForbidden)
+				return
+			}
+			h.handleAddresses(w, r, userID, pathParts[4:])
+		case (len(pathParts) == 4 || len(pathParts) == 5) && pathParts[3] == "api-keys":
+			if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+				http.Error(w, "Недостаточно прав для работы с API-ключами пользователя", http.StatusForbidden)
+				return
+			}
+			h.handleAPIKeys(w, r, userID, pathParts[4:])
+		case (len(pathParts) == 4 || len(pathParts) == 5) && pathParts[3] == "identities":
+			if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+				http.Error(w, "Недостаточно прав для работы с привязками пользователя", http.StatusForbidden)
+				return
+			}
+			h.handleIdentities(w, r, userID, pathParts[4:])
+		case (len(pathParts) == 4 || len(pathParts) == 5) && pathParts[3] == "consents":
+			if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+				http.Error(w, "Недостаточно прав для работы с согласиями пользователя", http.StatusForbidden)
+				return
+			}
+			h.handleConsents(w, r, userID, pathParts[4:])
+		case len(pathParts) == 4 || len(pathParts) == 5:
 			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+		default:
+			http.Error(w, "ID пользователя не указан в пути или путь некорректен", http.StatusBadRequest)
 		}
-	})
+	})))
+
+	router.HandleFunc("/api/auth/login", allowMethod(http.MethodPost, h.handleLogin))
+	router.HandleFunc("/api/auth/refresh", allowMethod(http.MethodPost, h.handleRefresh))
+	router.HandleFunc("/api/auth/logout", allowMethod(http.MethodPost, auth.RequireAuth(h.loginTokens, h.handleLogout)))
+	router.HandleFunc("/api/auth/password/change", allowMethod(http.MethodPost, auth.RequireAuth(h.loginTokens, h.handleChangePassword)))
+	router.HandleFunc("/api/auth/password/forgot", allowMethod(http.MethodPost, h.handleForgotPassword))
+	router.HandleFunc("/api/auth/password/reset", allowMethod(http.MethodPost, h.handleResetPassword))
+	router.HandleFunc("/api/auth/verify-email", allowMethod(http.MethodPost, h.handleVerifyEmail))
+	// Вход через внешних провайдеров OpenID Connect (authorization code flow с PKCE)
+	router.HandleFunc("/api/auth/oidc/", h.handleOIDC)
+	router.HandleFunc("/api/auth/2fa/verify", allowMethod(http.MethodPost, h.handleVerifyTwoFactor))
+	router.HandleFunc("/api/auth/2fa/setup", allowMethod(http.MethodPost, auth.RequireAuth(h.loginTokens, h.handleSetupTwoFactor)))
+	router.HandleFunc("/api/auth/2fa/confirm", allowMethod(http.MethodPost, auth.RequireAuth(h.loginTokens, h.handleConfirmTwoFactor)))
+	router.HandleFunc("/api/auth/2fa/disable", allowMethod(http.MethodPost, auth.RequireAuth(h.loginTokens, h.handleDisableTwoFactor)))
+	router.HandleFunc("/api/auth/2fa/recovery-codes", allowMethod(http.MethodPost, auth.RequireAuth(h.loginTokens, h.handleRegenerateRecoveryCodes)))
+	router.HandleFunc("/api/auth/verify-email/resend", allowMethod(http.MethodPost, auth.RequireAuth(h.loginTokens, h.handleResendVerification)))
+	// Журнал аутентификации (только ADMIN)
+	router.HandleFunc("/api/auth/audit", allowMethod(http.MethodGet, auth.RequireAuthRole(h.loginTokens, h.listAuthEvents, auth.RoleAdmin)))
+	// Список отозванных токенов доступа загружают другие сервисы (auth.RemoteDenylist)
+	router.HandleFunc("/api/auth/revoked", allowMethod(http.MethodGet, h.listRevokedTokens))
+	// Проверка API-ключей для других сервисов (auth.RemoteAPIKeys)
+	router.HandleFunc("/api/auth/api-keys/verify", allowMethod(http.MethodPost, h.handleVerifyAPIKey))
+	// Открытые ключи проверки подписи токенов (кешируются другими сервисами, auth.JWKSCache)
+	router.HandleFunc("/.well-known/jwks.json", allowMethod(http.MethodGet, h.handleJWKS))
 
-	router.HandleFunc("/api/auth/login", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodPost {
-			h.handleLogin(w, r)
-		} else {
+	// Сервер авторизации OAuth2: authorization code с PKCE и client credentials.
+	// /oauth/authorize вызывает собственная страница входа с токеном вошедшего пользователя,
+	// остальные конечные точки - клиенты со своими учетными данными.
+	router.HandleFunc("/oauth/authorize", auth.RequireAuth(h.loginTokens, h.handleAuthorize))
+	router.HandleFunc("/oauth/token", allowMethod(http.MethodPost, h.handleToken))
+	router.HandleFunc("/oauth/introspect", allowMethod(http.MethodPost, h.handleIntrospect))
+	router.HandleFunc("/oauth/revoke", allowMethod(http.MethodPost, h.handleRevoke))
+	router.HandleFunc("/.well-known/openid-configuration", allowMethod(http.MethodGet, h.handleOAuthMetadata))
+	router.HandleFunc("/.well-known/oauth-authorization-server", allowMethod(http.MethodGet, h.handleOAuthMetadata))
+	// Регистрация клиентов OAuth2 (только ADMIN)
+	router.HandleFunc("/api/oauth/clients", auth.RequireAuthRole(h.loginTokens, h.handleOAuthClients, auth.RoleAdmin))
+	router.HandleFunc("/api/oauth/clients/", auth.RequireAuthRole(h.loginTokens, h.handleOAuthClients, auth.RoleAdmin))
+}
+
+// allowMethod пропускает к обработчику только запросы с указанным методом.
+func allowMethod(method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
 			http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+			return
 		}
-	})
+		next(w, r)
+	}
+}
+
+// handleUser обрабатывает операции с учетной записью /api/users/{id}.
+func (h *UserHTTPHandler) handleUser(w http.ResponseWriter, r *http.Request, userID string) {
+	switch r.Method {
+	case http.MethodGet:
+		if !isCurrentUserOrRole(r, userID, auth.RoleAdmin, auth.RoleStaff) {
+			http.Error(w, "Недостаточно прав для просмотра пользователя", http.StatusForbidden)
+			return
+		}
+		h.getUserByID(w, r, userID)
+	case http.MethodPut:
+		if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+			http.Error(w, "Недостаточно прав для изменения пользователя", http.StatusForbidden)
+			return
+		}
+		h.updateUser(w, r, userID)
+	case http.MethodDelete:
+		if !isCurrentUserOrRole(r, userID, auth.RoleAdmin) {
+			http.Error(w, "Недостаточно прав для удаления пользователя", http.StatusForbidden)
+			return
+		}
+		h.deleteUser(w, r, userID)
+	default:
+		http.Error(w, "Метод не разрешен", http.StatusMethodNotAllowed)
+	}
+}
 
-	// router.HandleFunc("/api/auth/login", h.handleLogin) // TODO: Реализовать
+// isCurrentUserOrRole проверяет, что запрос выполняет сам пользователь userID
+// или пользователь с одной из ролей roles.
+func isCurrentUserOrRole(r *http.Request, userID string, roles ...string) bool {
+	currentUserID, ok := auth.UserIDFromContext(r.Context())
+	return (ok && currentUserID == userID) || auth.HasRole(r.Context(), roles...)
 }
 
 // createUser обрабатывает запрос на создание нового пользователя.
@@ -108,8 +272,8 @@ func (h *UserHTTPHandler) createUser(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Пользователь с таким email уже существует", http.StatusConflict)
 			return
 		}
-		if errors.Is(err, usecase.ErrPasswordTooShort) {
-			http.Error(w, "Пароль слишком короткий", http.StatusBadRequest)
+		if usecase.IsPasswordPolicyError(err) {
+			http.Error(w, "Недопустимый пароль: "+err.Error(), http.StatusBadRequest)
 			return
 		}
 		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
@@ -147,6 +311,12 @@ func (h *UserHTTPHandler) updateUser(w http.ResponseWriter, r *http.Request, use
 	var requestBody struct {
 		Username *string `json:"username,omitempty"` // omitempty, чтобы null не превращался в пустую строку при десериализации
 		Email    *string `json:"email,omitempty"`
+		// Поля профиля; пустая строка очищает поле
+		FullName    *string `json:"full_name,omitempty"`
+		Phone       *string `json:"phone,omitempty"`
+		AvatarURL   *string `json:"avatar_url,omitempty"`
+		DateOfBirth *string `json:"date_of_birth,omitempty"`
+		Locale      *string `json:"locale,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
@@ -156,12 +326,17 @@ func (h *UserHTTPHandler) updateUser(w http.ResponseWriter, r *http.Request, use
 	defer r.Body.Close()
 
 	updateInput := usecase.UpdateUserInput{
-		Username: requestBody.Username,
-		Email:    requestBody.Email,
+		Username:    requestBody.Username,
+		Email:       requestBody.Email,
+		FullName:    requestBody.FullName,
+		Phone:       requestBody.Phone,
+		AvatarURL:   requestBody.AvatarURL,
+		DateOfBirth: requestBody.DateOfBirth,
+		Locale:      requestBody.Locale,
 	}
 
 	// Проверка, есть ли вообще что обновлять
-	if updateInput.Username == nil && updateInput.Email == nil {
+	if updateInput.Empty() {
 		http.Error(w, "Нет данных для обновления", http.StatusBadRequest)
 		return
 	}
@@ -173,6 +348,8 @@ func (h *UserHTTPHandler) updateUser(w http.ResponseWriter, r *http.Request, use
 			http.Error(w, "Пользователь для обновления не найден", http.StatusNotFound)
 		case errors.Is(err, usecase.ErrEmailExists):
 			http.Error(w, "Новый email уже используется другим пользователем", http.StatusConflict)
+		case errors.Is(err, usecase.ErrInvalidProfile):
+			http.Error(w, err.Error(), http.StatusBadRequest)
 		default:
 			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
 		}
@@ -186,35 +363,298 @@ func (h *UserHTTPHandler) updateUser(w http.ResponseWriter, r *http.Request, use
 	json.NewEncoder(w).Encode(updatedUser)
 }
 
+// userListResponse - страница списка пользователей
+type userListResponse struct {
+	Users         []*models.User `json:"users"`
+	NextPageToken string         `json:"next_page_token,omitempty"`
+	Total         int64          `json:"total"` // Число пользователей по фильтру на всех страницах
+}
+
+// listUsers возвращает страницу пользователей (только ADMIN).
+// Параметры: page_size, page_token, email и username (префиксы), created_from и created_to (RFC 3339),
+// role, verified (true/false), sort (created_at, email, username; "-" в начале - по убыванию).
 func (h *UserHTTPHandler) listUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.userUsecase.ListUsers(r.Context())
+	query := r.URL.Query()
+	listQuery := usecase.UserListQuery{
+		Filter: repository.UserFilter{
+			EmailPrefix:    query.Get("email"),
+			UsernamePrefix: query.Get("username"),
+			Role:           query.Get("role"),
+		},
+		Sort:      query.Get("sort"),
+		PageToken: query.Get("page_token"),
+		WithTotal: true,
+	}
+
+	for param, target := range map[string]*time.Time{"created_from": &listQuery.Filter.CreatedFrom, "created_to": &listQuery.Filter.CreatedTo} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Некорректное значение "+param+": ожидается время в формате RFC 3339", http.StatusBadRequest)
+			return
+		}
+		*target = parsed
+	}
+	if value := query.Get("verified"); value != "" {
+		verified, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Некорректное значение verified: ожидается true или false", http.StatusBadRequest)
+			return
+		}
+		listQuery.Filter.Verified = &verified
+	}
+	if value := query.Get("page_size"); value != "" {
+		pageSize, err := strconv.Atoi(value)
+		if err != nil || pageSize <= 0 {
+			http.Error(w, "Некорректное значение page_size", http.StatusBadRequest)
+			return
+		}
+		listQuery.PageSize = pageSize
+	}
+
+	page, err := h.userUsecase.ListUsersPage(r.Context(), listQuery)
 	if err != nil {
-		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, usecase.ErrInvalidPageToken):
+			http.Error(w, "Некорректный page_token: токен выдан для других фильтров или поврежден", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrInvalidSort):
+			http.Error(w, "Некорректное значение sort: допустимы created_at, email, username", http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrInvalidRole):
+			http.Error(w, "Неизвестная роль", http.StatusBadRequest)
+		default:
+			http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
-	// Убираем пароли
-	for i := range users {
-		users[i].Password = ""
+	users := page.Users
+	if users == nil {
+		users = []*models.User{}
 	}
-
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(users)
+	json.NewEncoder(w).Encode(userListResponse{Users: users, NextPageToken: page.NextPageToken, Total: page.Total})
 }
 
+// deleteUser отмечает учетную запись удаленной. Данные обезличиваются после срока ожидания,
+// до этого ADMIN может отменить удаление.
 func (h *UserHTTPHandler) deleteUser(w http.ResponseWriter, r *http.Request, userID string) {
-	err := h.userUsecase.DeleteUser(r.Context(), userID)
+	actorID, _ := auth.UserIDFromContext(r.Context())
+	if _, err := h.accountUsecase.DeleteUser(r.Context(), userID, actorID); err != nil {
+		writeAccountError(w, "Ошибка при удалении пользователя: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent) // 204 No Content
+}
+
+// deactivateUser запрещает вход пользователя, сохраняя его данные (сам пользователь или ADMIN).
+func (h *UserHTTPHandler) deactivateUser(w http.ResponseWriter, r *http.Request, userID string) {
+	actorID, _ := auth.UserIDFromContext(r.Context())
+	user, err := h.accountUsecase.DeactivateUser(r.Context(), userID, actorID)
+	writeAccountResult(w, "Ошибка при деактивации пользователя: ", user, err)
+}
+
+// reactivateUser снимает деактивацию учетной записи (только ADMIN).
+func (h *UserHTTPHandler) reactivateUser(w http.ResponseWriter, r *http.Request, userID string) {
+	actorID, _ := auth.UserIDFromContext(r.Context())
+	user, err := h.accountUsecase.ReactivateUser(r.Context(), userID, actorID)
+	writeAccountResult(w, "Ошибка при снятии деактивации: ", user, err)
+}
+
+// restoreUser отменяет удаление учетной записи до обезличивания (только ADMIN).
+func (h *UserHTTPHandler) restoreUser(w http.ResponseWriter, r *http.Request, userID string) {
+	actorID, _ := auth.UserIDFromContext(r.Context())
+	user, err := h.accountUsecase.RestoreUser(r.Context(), userID, actorID)
+	writeAccountResult(w, "Ошибка при восстановлении пользователя: ", user, err)
+}
+
+// exportUserData отдает выгрузку данных пользователя вместе с его заказами из order-service.
+func (h *UserHTTPHandler) exportUserData(w http.ResponseWriter, r *http.Request, userID string) {
+	export, err := h.accountUsecase.ExportUserData(r.Context(), userID)
+	if err != nil {
+		writeAccountError(w, "Ошибка при выгрузке данных пользователя: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="user-`+userID+`.json"`)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(export)
+}
+
+// writeAccountResult отвечает пользователем в новом состоянии или ошибкой.
+func writeAccountResult(w http.ResponseWriter, prefix string, user *models.User, err error) {
 	if err != nil {
+		writeAccountError(w, prefix, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
+
+// writeAccountError переводит ошибки жизненного цикла учетной записи в HTTP-статусы.
+func writeAccountError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	case errors.Is(err, usecase.ErrAccountErased), errors.Is(err, usecase.ErrAccountNotDeactivated),
+		errors.Is(err, usecase.ErrAccountNotDeleted):
+		http.Error(w, prefix+err.Error(), http.StatusConflict)
+	case errors.Is(err, usecase.ErrOrdersUnavailable):
+		http.Error(w, prefix+err.Error(), http.StatusBadGateway)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// grantRole обрабатывает запрос на выдачу роли пользователю (только ADMIN).
+func (h *UserHTTPHandler) grantRole(w http.ResponseWriter, r *http.Request, userID string) {
+	var requestBody struct {
+		Role string `json:"role"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	user, err := h.userUsecase.GrantRole(r.Context(), userID, requestBody.Role)
+	h.writeRoleResult(w, user, err)
+}
+
+// revokeRole обрабатывает запрос на отзыв роли у пользователя (только ADMIN).
+func (h *UserHTTPHandler) revokeRole(w http.ResponseWriter, r *http.Request, userID, role string) {
+	user, err := h.userUsecase.RevokeRole(r.Context(), userID, role)
+	h.writeRoleResult(w, user, err)
+}
+
+// writeRoleResult отвечает пользователем с обновленными ролями или ошибкой.
+func (h *UserHTTPHandler) writeRoleResult(w http.ResponseWriter, user *models.User, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUserNotFound):
+			http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		case errors.Is(err, usecase.ErrInvalidRole):
+			http.Error(w, "Неизвестная роль", http.StatusBadRequest)
+		default:
+			http.Error(w, "Ошибка при изменении ролей: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
+
+// unlockUser снимает блокировку входа пользователя (только ADMIN).
+func (h *UserHTTPHandler) unlockUser(w http.ResponseWriter, r *http.Request, userID string) {
+	if err := h.userUsecase.UnlockUser(r.Context(), userID); err != nil {
 		if errors.Is(err, usecase.ErrUserNotFound) {
 			http.Error(w, "Пользователь не найден", http.StatusNotFound)
 			return
 		}
-		http.Error(w, "Ошибка при удалении пользователя: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Не удалось снять блокировку: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent) // 204 No Content
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listAuthEvents возвращает записи журнала аутентификации (только ADMIN).
+// Параметры запроса: user_id, email, ip, event, since и until (RFC 3339), limit.
+func (h *UserHTTPHandler) listAuthEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := repository.AuthEventFilter{
+		UserID: query.Get("user_id"),
+		Email:  query.Get("email"),
+		IP:     query.Get("ip"),
+		Event:  strings.ToUpper(query.Get("event")),
+	}
+
+	for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "Некорректное значение "+param+": ожидается время в формате RFC 3339", http.StatusBadRequest)
+			return
+		}
+		*target = parsed
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Некорректное значение limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := h.userUsecase.ListAuthEvents(r.Context(), filter)
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// tokenResponse - ответ на вход и обновление токенов.
+// Поле token сохранено для клиентов, которые использовали его до появления токенов обновления.
+type tokenResponse struct {
+	User         *models.User `json:"user,omitempty"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"refresh_token"`
+	TokenType    string       `json:"token_type"`
+	ExpiresIn    int64        `json:"expires_in"` // Срок действия токена доступа в секундах
+	// TwoFactorEnrollmentRequired - токен выдан без роли ADMIN, пока не подключена 2FA
+	TwoFactorEnrollmentRequired bool `json:"two_factor_enrollment_required,omitempty"`
+}
+
+// newTokenResponse собирает ответ с парой токенов.
+func newTokenResponse(user *models.User, pair *usecase.TokenPair) tokenResponse {
+	return tokenResponse{
+		User:         user,
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(pair.ExpiresIn.Seconds()),
+	}
+}
+
+// twoFactorChallengeResponse - ответ на вход, если требуется второй фактор
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	Challenge         string `json:"challenge"`
+	ExpiresIn         int64  `json:"expires_in"` // Сколько секунд действителен challenge
+}
+
+// writeLoginResult отправляет пару токенов или, если нужен второй фактор, токен незавершенного входа.
+func writeLoginResult(w http.ResponseWriter, result *usecase.LoginResult) {
+	w.Header().Set("Content-Type", "application/json")
+	if result.Challenge != nil {
+		json.NewEncoder(w).Encode(twoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			Challenge:         result.Challenge.Token,
+			ExpiresIn:         int64(result.Challenge.ExpiresIn.Seconds()),
+		})
+		return
+	}
+
+	response := newTokenResponse(result.User, result.Tokens)
+	response.TwoFactorEnrollmentRequired = result.TwoFactorEnrollmentRequired
+	json.NewEncoder(w).Encode(response)
 }
 
 func (h *UserHTTPHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
@@ -234,28 +674,407 @@ func (h *UserHTTPHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userUsecase.AuthenticateUser(r.Context(), requestBody.Email, requestBody.Password)
+	result, err := h.authUsecase.Login(r.Context(), requestBody.Email, requestBody.Password, clientIP(r))
 	if err != nil {
-		http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+		var throttled *usecase.LoginThrottledError
+		if errors.As(err, &throttled) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			if throttled.Locked {
+				http.Error(w, "Вход временно заблокирован из-за неудачных попыток", http.StatusTooManyRequests)
+				return
+			}
+			http.Error(w, "Слишком много неудачных попыток входа, повторите позже", http.StatusTooManyRequests)
+			return
+		}
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			http.Error(w, "Неверный email или пароль", http.StatusUnauthorized)
+		case errors.Is(err, usecase.ErrAccountDeactivated), errors.Is(err, usecase.ErrAccountDeleted):
+			http.Error(w, "Вход запрещен: "+err.Error(), http.StatusForbidden)
+		default:
+			http.Error(w, "Не удалось выполнить вход: "+err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
-	token, err := h.jwtManager.GenerateToken(user.ID)
+	writeLoginResult(w, result)
+}
+
+// clientIP возвращает IP-адрес клиента для учета попыток входа.
+// Заголовки X-Forwarded-For не используются: клиент может подставить в них любой адрес.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
-		http.Error(w, "Не удалось сгенерировать токен", http.StatusInternalServerError)
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// handleRefresh обменивает токен обновления на новую пару токенов.
+func (h *UserHTTPHandler) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	defer r.Body.Close()
 
-	response := map[string]interface{}{
-		"user":  user,
-		"token": token,
+	pair, err := h.authUsecase.Refresh(r.Context(), requestBody.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrRefreshTokenReused):
+			http.Error(w, "Токен обновления использован повторно, войдите заново", http.StatusUnauthorized)
+		case errors.Is(err, usecase.ErrInvalidRefreshToken):
+			http.Error(w, "Недействительный токен обновления", http.StatusUnauthorized)
+		default:
+			http.Error(w, "Не удалось обновить токен: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(newTokenResponse(nil, pair))
+}
+
+// handleLogout отзывает текущий токен доступа и токен обновления (или все сеансы при "all": true).
+func (h *UserHTTPHandler) handleLogout(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		RefreshToken string `json:"refresh_token"`
+		All          bool   `json:"all"`
+	}
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+	}
+
+	if err := h.authUsecase.Logout(r.Context(), requestBody.RefreshToken, requestBody.All); err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			http.Error(w, "Недействительный токен обновления", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Не удалось выполнить выход: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleChangePassword меняет пароль текущего пользователя. Все его токены обновления отзываются.
+func (h *UserHTTPHandler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if requestBody.CurrentPassword == "" || requestBody.NewPassword == "" {
+		http.Error(w, "Текущий и новый пароль не могут быть пустыми", http.StatusBadRequest)
+		return
+	}
+
+	userID, _ := auth.UserIDFromContext(r.Context())
+	err := h.userUsecase.ChangePassword(r.Context(), userID, requestBody.CurrentPassword, requestBody.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			http.Error(w, "Неверный текущий пароль", http.StatusForbidden)
+		case usecase.IsPasswordPolicyError(err):
+			http.Error(w, "Недопустимый пароль: "+err.Error(), http.StatusBadRequest)
+		case errors.Is(err, usecase.ErrUserNotFound):
+			http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		default:
+			http.Error(w, "Не удалось сменить пароль: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleForgotPassword отправляет токен сброса пароля. Ответ не зависит от того,
+// зарегистрирован ли email, чтобы по нему нельзя было проверять адреса.
+func (h *UserHTTPHandler) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if requestBody.Email == "" {
+		http.Error(w, "Email не может быть пустым", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userUsecase.RequestPasswordReset(r.Context(), requestBody.Email); err != nil {
+		http.Error(w, "Не удалось отправить токен сброса пароля: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleResetPassword устанавливает новый пароль по токену сброса.
+func (h *UserHTTPHandler) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if requestBody.Token == "" || requestBody.NewPassword == "" {
+		http.Error(w, "Токен и новый пароль не могут быть пустыми", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userUsecase.ResetPassword(r.Context(), requestBody.Token, requestBody.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidResetToken), errors.Is(err, usecase.ErrUserNotFound):
+			http.Error(w, "Недействительный или истекший токен сброса пароля", http.StatusBadRequest)
+		case usecase.IsPasswordPolicyError(err):
+			http.Error(w, "Недопустимый пароль: "+err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Не удалось сбросить пароль: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleVerifyEmail подтверждает email по токену из письма и возвращает пользователя.
+func (h *UserHTTPHandler) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		Token string `json:"token"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if requestBody.Token == "" {
+		http.Error(w, "Токен не может быть пустым", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userUsecase.VerifyEmail(r.Context(), requestBody.Token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidVerificationToken) {
+			http.Error(w, "Недействительный или истекший токен подтверждения email", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Не удалось подтвердить email: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// handleResendVerification повторно отправляет токен подтверждения email текущему пользователю.
+func (h *UserHTTPHandler) handleResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, _ := auth.UserIDFromContext(r.Context())
+	if err := h.userUsecase.SendVerificationEmail(r.Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrEmailAlreadyVerified):
+			http.Error(w, "Email уже подтвержден", http.StatusConflict)
+		case errors.Is(err, usecase.ErrUserNotFound):
+			http.Error(w, "Пользователь не найден", http.StatusNotFound)
+		default:
+			http.Error(w, "Не удалось отправить токен подтверждения email: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleVerifyTwoFactor завершает вход кодом TOTP или кодом восстановления.
+func (h *UserHTTPHandler) handleVerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var requestBody struct {
+		Challenge string `json:"challenge"`
+		Code      string `json:"code"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if requestBody.Challenge == "" || requestBody.Code == "" {
+		http.Error(w, "Challenge и код не могут быть пустыми", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.authUsecase.VerifyTwoFactor(r.Context(), requestBody.Challenge, requestBody.Code)
+	if err != nil {
+		writeTwoFactorError(w, "Не удалось выполнить вход: ", err)
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+// handleSetupTwoFactor выдает текущему пользователю новый секрет TOTP.
+func (h *UserHTTPHandler) handleSetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, _ := auth.UserIDFromContext(r.Context())
+	setup, err := h.authUsecase.SetupTwoFactor(r.Context(), userID)
+	if err != nil {
+		writeTwoFactorError(w, "Не удалось подключить двухфакторную аутентификацию: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"secret":      setup.Secret,
+		"otpauth_uri": setup.URI,
+	})
+}
+
+// handleConfirmTwoFactor включает 2FA по первому коду и возвращает коды восстановления.
+func (h *UserHTTPHandler) handleConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := auth.UserIDFromContext(r.Context())
+	codes, err := h.authUsecase.ConfirmTwoFactor(r.Context(), userID, code)
+	if err != nil {
+		writeTwoFactorError(w, "Не удалось подключить двухфакторную аутентификацию: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"recovery_codes": codes})
+}
+
+// handleDisableTwoFactor отключает 2FA текущего пользователя.
+func (h *UserHTTPHandler) handleDisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := auth.UserIDFromContext(r.Context())
+	if err := h.authUsecase.DisableTwoFactor(r.Context(), userID, code); err != nil {
+		writeTwoFactorError(w, "Не удалось отключить двухфакторную аутентификацию: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRegenerateRecoveryCodes выдает новые коды восстановления взамен прежних.
+func (h *UserHTTPHandler) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := auth.UserIDFromContext(r.Context())
+	codes, err := h.authUsecase.RegenerateRecoveryCodes(r.Context(), userID, code)
+	if err != nil {
+		writeTwoFactorError(w, "Не удалось выдать коды восстановления: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"recovery_codes": codes})
+}
+
+// decodeTwoFactorCode читает из тела запроса {"code": "..."}
+func decodeTwoFactorCode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var requestBody struct {
+		Code string `json:"code"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Некорректное тело запроса: "+err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	defer r.Body.Close()
+
+	if requestBody.Code == "" {
+		http.Error(w, "Код не может быть пустым", http.StatusBadRequest)
+		return "", false
+	}
+	return requestBody.Code, true
+}
+
+// writeTwoFactorError преобразует ошибки двухфакторной аутентификации в HTTP-ответ
+func writeTwoFactorError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidLoginChallenge):
+		http.Error(w, "Недействительный или истекший вход, войдите заново", http.StatusUnauthorized)
+	case errors.Is(err, usecase.ErrInvalidTwoFactorCode):
+		http.Error(w, "Неверный код двухфакторной аутентификации", http.StatusUnauthorized)
+	case errors.Is(err, usecase.ErrTooManyTwoFactorAttempts):
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+	case errors.Is(err, usecase.ErrTwoFactorAlreadyEnabled):
+		http.Error(w, "Двухфакторная аутентификация уже включена", http.StatusConflict)
+	case errors.Is(err, usecase.ErrTwoFactorNotEnabled), errors.Is(err, usecase.ErrTwoFactorSetupMissing):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, usecase.ErrTwoFactorRequired):
+		http.Error(w, "Для роли ADMIN двухфакторная аутентификация обязательна", http.StatusForbidden)
+	case errors.Is(err, usecase.ErrAccountDeactivated), errors.Is(err, usecase.ErrAccountDeleted):
+		http.Error(w, "Вход запрещен: "+err.Error(), http.StatusForbidden)
+	case errors.Is(err, usecase.ErrUserNotFound):
+		http.Error(w, "Пользователь не найден", http.StatusNotFound)
+	default:
+		http.Error(w, prefix+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// listRevokedTokens возвращает отозванные токены доступа, срок которых еще не истек.
+func (h *UserHTTPHandler) listRevokedTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := h.authUsecase.RevokedTokens(r.Context())
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// handleJWKS публикует открытые ключи, которыми проверяются токены доступа.
+func (h *UserHTTPHandler) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	set, err := h.jwtManager.JWKS()
+	if err != nil {
+		http.Error(w, "Внутренняя ошибка сервера: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	// Короткий срок кеширования: после ротации новый ключ должен появиться у клиентов быстро
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(set)
 }
 
 // TODO: Реализуйте другие HTTP обработчики (login, delete, list).
 // Для login (аутентификации) вам, вероятно, понадобится возвращать JWT или другой токен сессии.
 // Для deleteUser: метод DELETE /api/users/{id}
-// Для listUsers: метод GET /api/users (потребует доработки RegisterRoutes и handleUsers)
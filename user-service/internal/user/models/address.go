@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// Address - адрес доставки из адресной книги пользователя.
+// У пользователя не больше одного адреса по умолчанию; его order-service
+// подставляет в заказ, если адрес не выбран явно.
+type Address struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	Label      string    `json:"label,omitempty"` // Название для пользователя ("Дом", "Работа")
+	Recipient  string    `json:"recipient"`       // Получатель
+	Phone      string    `json:"phone,omitempty"` // Телефон получателя в формате E.164
+	Line1      string    `json:"line1"`           // Улица, дом
+	Line2      string    `json:"line2,omitempty"` // Квартира, офис
+	City       string    `json:"city"`
+	Region     string    `json:"region,omitempty"`
+	PostalCode string    `json:"postal_code"`
+	Country    string    `json:"country"` // Код страны ISO 3166-1 alpha-2
+	IsDefault  bool      `json:"is_default"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
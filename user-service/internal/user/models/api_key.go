@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// APIKey - ключ доступа для машинных клиентов (кассы, партнеры-реселлеры).
+// Ключ действует от имени владельца UserID в пределах разрешений Scopes.
+// Сам ключ показывается только при создании; хранится его SHA-256 хеш.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // Начало ключа, по которому его можно узнать в списке
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  int        `json:"rate_limit"` // Запросов в минуту
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Valid сообщает, что ключ не отозван и не истек к моменту now
+func (k *APIKey) Valid(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// Типы событий журнала аутентификации
+const (
+	AuthEventLoginSucceeded  = "LOGIN_SUCCEEDED"  // Успешный вход
+	AuthEventLoginFailed     = "LOGIN_FAILED"     // Неверный email или пароль
+	AuthEventLoginBlocked    = "LOGIN_BLOCKED"    // Попытка входа отклонена из-за ограничения попыток
+	AuthEventAccountUnlocked = "ACCOUNT_UNLOCKED" // Администратор снял блокировку входа
+
+	AuthEventAccountDeactivated = "ACCOUNT_DEACTIVATED" // Учетная запись деактивирована
+	AuthEventAccountReactivated = "ACCOUNT_REACTIVATED" // Деактивация снята администратором
+	AuthEventAccountDeleted     = "ACCOUNT_DELETED"     // Учетная запись удалена, ожидает обезличивания
+	AuthEventAccountRestored    = "ACCOUNT_RESTORED"    // Удаление отменено администратором до обезличивания
+	AuthEventAccountErased      = "ACCOUNT_ERASED"      // Персональные данные обезличены
+
+	AuthEventAPIKeyCreated = "API_KEY_CREATED" // Выдан API-ключ
+	AuthEventAPIKeyRevoked = "API_KEY_REVOKED" // API-ключ отозван
+
+	AuthEventIdentityLinked   = "IDENTITY_LINKED"   // Привязана учетная запись внешнего провайдера
+	AuthEventIdentityUnlinked = "IDENTITY_UNLINKED" // Учетная запись внешнего провайдера отвязана
+
+	AuthEventOAuthConsentGranted = "OAUTH_CONSENT_GRANTED" // Пользователь разрешил клиенту OAuth2 доступ
+	AuthEventOAuthConsentRevoked = "OAUTH_CONSENT_REVOKED" // Согласие клиенту OAuth2 отозвано
+)
+
+// AuthEvent - запись журнала аутентификации.
+// UserID пуст, если попытка входа была с незарегистрированным email.
+type AuthEvent struct {
+	ID        int64     `json:"id"`
+	Event     string    `json:"event"`
+	UserID    string    `json:"user_id,omitempty"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginThrottle - состояние ограничения попыток входа для одного ключа (email или IP-адреса)
+type LoginThrottle struct {
+	Key           string     `json:"key"`
+	Failures      int        `json:"failures"`               // Неудачные попытки подряд в пределах окна
+	LastFailureAt time.Time  `json:"last_failure_at"`        // Время последней неудачной попытки
+	LockedUntil   *time.Time `json:"locked_until,omitempty"` // До какого времени вход запрещен
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+)
+
+// ExternalIdentity - учетная запись внешнего провайдера OpenID Connect (Google, Apple), привязанная к пользователю.
+// Subject - постоянный ID пользователя у провайдера; email может меняться и для входа не используется.
+type ExternalIdentity struct {
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	UserID      string     `json:"user_id"`
+	Email       string     `json:"email"` // Email у провайдера на момент привязки
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// OIDCLoginState - начатый вход через провайдер OpenID Connect, ожидающий возврата пользователя с кодом.
+// Хранится хеш state; CodeVerifier (PKCE) и Nonce нужны для обмена кода и проверки ID-токена.
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
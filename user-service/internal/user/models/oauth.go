@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+// Типы разрешений на получение токена (grant_type), которые может использовать клиент OAuth2
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantRefreshToken      = "refresh_token"
+	GrantClientCredentials = "client_credentials"
+)
+
+// OAuthClient - приложение, зарегистрированное для получения токенов OAuth2.
+// Конфиденциальный клиент (серверное приложение) подтверждает себя секретом, хранится его SHA-256 хеш;
+// публичный клиент (SPA, мобильное приложение) секрета не имеет.
+type OAuthClient struct {
+	ID           string    `json:"client_id"`
+	Name         string    `json:"name"`
+	Confidential bool      `json:"confidential"`
+	SecretHash   string    `json:"-"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	GrantTypes   []string  `json:"grant_types"`
+	Scopes       []string  `json:"scopes"` // Разрешения, которые клиент может запросить
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AllowsGrant сообщает, может ли клиент получать токены способом grantType
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	for _, g := range c.GrantTypes {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// OAuthConsent - разрешения, которые пользователь уже выдал клиенту.
+// Пока запрошенные разрешения входят в выданные, повторное согласие не запрашивается.
+type OAuthConsent struct {
+	UserID     string    `json:"user_id"`
+	ClientID   string    `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OAuthAuthorizationCode - одноразовый код авторизации, выданный клиенту после согласия пользователя.
+// Хранится хеш кода; CodeChallenge (PKCE, S256) проверяется при обмене кода на токены.
+type OAuthAuthorizationCode struct {
+	CodeHash      string
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	Nonce         string // nonce OpenID Connect, возвращается в ID-токене
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// RefreshToken представляет сохраненный токен обновления.
+// Сам токен не хранится: в базе лежит только его SHA-256 хеш.
+// Токены, выпущенные при ротации одного входа, образуют семейство (FamilyID):
+// повторное использование уже обмененного токена отзывает все семейство.
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`    // Время обмена на новую пару токенов
+	RevokedAt *time.Time `json:"revoked_at,omitempty"` // Время отзыва (выход или обнаружение повторного использования)
+	// ClientID - клиент OAuth2, которому выдан токен; пуст для токенов входа пользователя
+	ClientID string   `json:"client_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"` // Разрешения токенов доступа, выдаваемых клиенту
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// TwoFactor - настройка двухфакторной аутентификации (TOTP, RFC 6238) пользователя.
+// Пока ConfirmedAt не задано, секрет выдан, но вход по нему не требуется.
+type TwoFactor struct {
+	UserID       string     `json:"user_id"`
+	Secret       string     `json:"-"` // Секрет в кодировке base32
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+	LastUsedStep int64      `json:"-"` // Последний принятый временной шаг: код нельзя использовать повторно
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Enabled сообщает, подтверждена ли двухфакторная аутентификация
+func (t *TwoFactor) Enabled() bool {
+	return t != nil && t.ConfirmedAt != nil
+}
+
+// LoginChallenge - незавершенный вход: пароль проверен, ожидается второй фактор.
+// Сам токен не хранится: в базе лежит только его SHA-256 хеш.
+type LoginChallenge struct {
+	TokenHash string     `json:"-"`
+	UserID    string     `json:"user_id"`
+	Attempts  int        `json:"attempts"` // Неверные коды, введенные по этому входу
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
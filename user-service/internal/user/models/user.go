@@ -6,12 +6,32 @@ import (
 
 // User представляет модель пользователя в системе.
 type User struct {
-	ID        string    `json:"id"`         // Уникальный идентификатор пользователя (например, UUID)
-	Username  string    `json:"username"`   // Имя пользователя
-	Email     string    `json:"email"`      // Адрес электронной почты (должен быть уникальным)
-	Password  string    `json:"-"`          // Хеш пароля (не включаем в JSON ответы напрямую)
-	CreatedAt time.Time `json:"created_at"` // Время создания записи пользователя
-	UpdatedAt time.Time `json:"updated_at"` // Время последнего обновления записи пользователя
+	ID              string     `json:"id"`                       // Уникальный идентификатор пользователя (например, UUID)
+	Username        string     `json:"username"`                 // Имя пользователя
+	Email           string     `json:"email"`                    // Адрес электронной почты (должен быть уникальным)
+	Password        string     `json:"-"`                        // Хеш пароля (не включаем в JSON ответы напрямую)
+	Roles           []string   `json:"roles"`                    // Роли пользователя (ADMIN, CUSTOMER, STAFF)
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`        // Время подтверждения email (nil, пока не подтвержден)
+	FullName        string     `json:"full_name,omitempty"`      // Полное имя для доставки и билетов
+	Phone           string     `json:"phone,omitempty"`          // Телефон в формате E.164 (+79991234567)
+	AvatarURL       string     `json:"avatar_url,omitempty"`     // Ссылка на аватар (http или https)
+	DateOfBirth     string     `json:"date_of_birth,omitempty"`  // Дата рождения ГГГГ-ММ-ДД (для билетов с возрастным ограничением)
+	Locale          string     `json:"locale,omitempty"`         // Язык интерфейса и писем (ru, en-US)
+	DeactivatedAt   *time.Time `json:"deactivated_at,omitempty"` // Время деактивации: вход запрещен, данные сохраняются
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`     // Время удаления: после срока ожидания данные обезличиваются
+	AnonymizedAt    *time.Time `json:"anonymized_at,omitempty"`  // Время обезличивания персональных данных
+	CreatedAt       time.Time  `json:"created_at"`               // Время создания записи пользователя
+	UpdatedAt       time.Time  `json:"updated_at"`               // Время последнего обновления записи пользователя
+}
+
+// EmailVerified сообщает, подтвержден ли текущий email пользователя
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// Active сообщает, может ли пользователь входить: учетная запись не деактивирована и не удалена
+func (u *User) Active() bool {
+	return u.DeactivatedAt == nil && u.DeletedAt == nil
 }
 
 // Вы можете добавить сюда методы для структуры User, если это необходимо.
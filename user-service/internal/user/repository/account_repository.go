@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+)
+
+// AccountRepository определяет интерфейс хранилища состояния учетных записей:
+// деактивация, удаление с отложенным обезличиванием и уведомление order-service об обезличивании.
+type AccountRepository interface {
+	// SetDeactivated деактивирует (at != nil) или снова активирует (at == nil) пользователя.
+	// Возвращает nil, nil, если пользователь не найден или уже обезличен.
+	SetDeactivated(ctx context.Context, id string, at *time.Time) (*models.User, error)
+	// MarkDeleted отмечает пользователя удаленным. При повторном удалении сохраняется исходное время,
+	// чтобы срок ожидания не продлевался. Возвращает nil, nil, если пользователь не найден или уже обезличен.
+	MarkDeleted(ctx context.Context, id string, at time.Time) (*models.User, error)
+	// Restore отменяет удаление. Возвращает nil, nil, если пользователь не найден или уже обезличен.
+	Restore(ctx context.Context, id string) (*models.User, error)
+	// ListDeletedBefore возвращает до limit ID пользователей, удаленных раньше before и еще не обезличенных
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error)
+	// Anonymize заменяет персональные данные удаленного пользователя, удаляет его токены, API-ключи, настройки 2FA, адреса,
+	// привязки к внешним провайдерам, согласия клиентам OAuth2 и записи журнала аутентификации.
+	// Возвращает sql.ErrNoRows, если пользователь не удален или уже обезличен.
+	Anonymize(ctx context.Context, id string) error
+	// ListUnnotifiedErasures возвращает до limit ID обезличенных пользователей, об обезличивании которых
+	// еще не сообщено order-service
+	ListUnnotifiedErasures(ctx context.Context, limit int) ([]string, error)
+	// MarkErasureNotified отмечает, что order-service обезличил данные пользователя
+	MarkErasureNotified(ctx context.Context, id string) error
+}
+
+// postgresAccountRepository реализует AccountRepository для PostgreSQL.
+type postgresAccountRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAccountRepository создает новый экземпляр postgresAccountRepository.
+func NewPostgresAccountRepository(db *sql.DB) AccountRepository {
+	return &postgresAccountRepository{db: db}
+}
+
+// SetDeactivated меняет признак деактивации пользователя.
+func (r *postgresAccountRepository) SetDeactivated(ctx context.Context, id string, at *time.Time) (*models.User, error) {
+	query := `UPDATE users
+			   SET deactivated_at = $2, updated_at = $3
+			   WHERE id = $1 AND anonymized_at IS NULL
+			   RETURNING ` + userColumns
+	return scanUser(r.db.QueryRowContext(ctx, query, id, at, time.Now().UTC()))
+}
+
+// MarkDeleted отмечает пользователя удаленным.
+func (r *postgresAccountRepository) MarkDeleted(ctx context.Context, id string, at time.Time) (*models.User, error) {
+	query := `UPDATE users
+			   SET deleted_at = COALESCE(deleted_at, $2), updated_at = $2
+			   WHERE id = $1 AND anonymized_at IS NULL
+			   RETURNING ` + userColumns
+	return scanUser(r.db.QueryRowContext(ctx, query, id, at))
+}
+
+// Restore снимает отметку об удалении.
+func (r *postgresAccountRepository) Restore(ctx context.Context, id string) (*models.User, error) {
+	query := `UPDATE users
+			   SET deleted_at = NULL, updated_at = $2
+			   WHERE id = $1 AND anonymized_at IS NULL
+			   RETURNING ` + userColumns
+	return scanUser(r.db.QueryRowContext(ctx, query, id, time.Now().UTC()))
+}
+
+// ListDeletedBefore выбирает пользователей, у которых истек срок ожидания после удаления.
+func (r *postgresAccountRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	return r.queryIDs(ctx,
+		`SELECT id FROM users WHERE deleted_at < $1 AND anonymized_at IS NULL ORDER BY deleted_at LIMIT $2`,
+		before, limit)
+}
+
+// Anonymize в одной транзакции обезличивает пользователя и удаляет связанные с ним записи.
+// Email заменяется уникальным адресом в зарезервированном домене .invalid, пароль - пустым хешем,
+// с которым вход невозможен. Строка пользователя сохраняется, чтобы его ID не был выдан повторно.
+func (r *postgresAccountRepository) Anonymize(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var email string
+	err = tx.QueryRowContext(ctx,
+		`SELECT email FROM users WHERE id = $1 AND deleted_at IS NOT NULL AND anonymized_at IS NULL FOR UPDATE`,
+		id).Scan(&email)
+	if err != nil {
+		return err // sql.ErrNoRows, если пользователь не удален или уже обезличен
+	}
+
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE users
+		 SET username = 'deleted-user', email = 'deleted-' || id::TEXT || '@users.invalid', password_hash = '',
+		     roles = ARRAY[]::TEXT[], email_verified_at = NULL, full_name = '', phone = '', avatar_url = '',
+		     date_of_birth = NULL, locale = '', anonymized_at = $2, updated_at = $2
+		 WHERE id = $1`,
+		id, now); err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM password_reset_tokens WHERE user_id = $1`,
+		`DELETE FROM email_verification_tokens WHERE user_id = $1`,
+		`DELETE FROM login_challenges WHERE user_id = $1`,
+		`DELETE FROM two_factor_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_two_factor WHERE user_id = $1`,
+		`DELETE FROM user_addresses WHERE user_id = $1`,
+		`DELETE FROM api_keys WHERE user_id = $1`,
+		`DELETE FROM external_identities WHERE user_id = $1`,
+		`DELETE FROM oauth_consents WHERE user_id = $1`,
+		`DELETE FROM oauth_authorization_codes WHERE user_id = $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+	// Журнал хранит email и IP-адреса: удаляются записи и по ID, и по прежнему email
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM auth_audit_log WHERE user_id = $1 OR lower(email) = lower($2)`,
+		id, email); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListUnnotifiedErasures выбирает обезличенных пользователей, о которых не сообщено order-service.
+func (r *postgresAccountRepository) ListUnnotifiedErasures(ctx context.Context, limit int) ([]string, error) {
+	return r.queryIDs(ctx,
+		`SELECT id FROM users WHERE anonymized_at IS NOT NULL AND erasure_notified_at IS NULL ORDER BY anonymized_at LIMIT $1`,
+		limit)
+}
+
+// MarkErasureNotified сохраняет время уведомления order-service.
+func (r *postgresAccountRepository) MarkErasureNotified(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET erasure_notified_at = $2 WHERE id = $1`,
+		id, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// queryIDs выполняет запрос, возвращающий столбец id.
+func (r *postgresAccountRepository) queryIDs(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+
+	"github.com/google/uuid"
+)
+
+// AddressRepository определяет интерфейс хранилища адресной книги пользователей.
+// Все методы ищут адрес только среди адресов пользователя userID.
+type AddressRepository interface {
+	// ListAddresses возвращает адреса пользователя: сначала адрес по умолчанию, затем по времени создания
+	ListAddresses(ctx context.Context, userID string) ([]*models.Address, error)
+	// GetAddress возвращает адрес. Возвращает nil, nil, если адрес не найден.
+	GetAddress(ctx context.Context, userID, id string) (*models.Address, error)
+	// CreateAddress сохраняет новый адрес. Первый адрес пользователя всегда становится адресом по умолчанию.
+	// Если адресов у пользователя уже maxAddresses, возвращает ErrAddressLimit.
+	CreateAddress(ctx context.Context, address *models.Address, maxAddresses int) (*models.Address, error)
+	// UpdateAddress сохраняет поля адреса. Признак по умолчанию меняется только через SetDefaultAddress.
+	// Возвращает nil, nil, если адрес не найден.
+	UpdateAddress(ctx context.Context, address *models.Address) (*models.Address, error)
+	// DeleteAddress удаляет адрес. Если он был адресом по умолчанию, им становится самый новый из оставшихся.
+	// Возвращает sql.ErrNoRows, если адрес не найден.
+	DeleteAddress(ctx context.Context, userID, id string) error
+	// SetDefaultAddress делает адрес адресом по умолчанию. Возвращает nil, nil, если адрес не найден.
+	SetDefaultAddress(ctx context.Context, userID, id string) (*models.Address, error)
+}
+
+// ErrAddressLimit возвращается при попытке сохранить адрес сверх допустимого количества
+var ErrAddressLimit = errors.New("достигнуто максимальное количество адресов")
+
+// postgresAddressRepository реализует AddressRepository для PostgreSQL.
+type postgresAddressRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAddressRepository создает новый экземпляр postgresAddressRepository.
+func NewPostgresAddressRepository(db *sql.DB) AddressRepository {
+	return &postgresAddressRepository{db: db}
+}
+
+// addressColumns - столбцы таблицы user_addresses в порядке, ожидаемом scanAddress
+const addressColumns = `id, user_id, label, recipient, phone, line1, line2, city, region, postal_code, country,
+	is_default, created_at, updated_at`
+
+// ListAddresses выбирает адреса пользователя.
+func (r *postgresAddressRepository) ListAddresses(ctx context.Context, userID string) ([]*models.Address, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+addressColumns+` FROM user_addresses WHERE user_id = $1 ORDER BY is_default DESC, created_at, id`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := make([]*models.Address, 0)
+	for rows.Next() {
+		address, err := scanAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+// GetAddress выбирает адрес пользователя по ID.
+func (r *postgresAddressRepository) GetAddress(ctx context.Context, userID, id string) (*models.Address, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+	return scanAddress(r.db.QueryRowContext(ctx,
+		`SELECT `+addressColumns+` FROM user_addresses WHERE id = $1 AND user_id = $2`,
+		id, userID))
+}
+
+// CreateAddress сохраняет адрес. Строка пользователя блокируется, чтобы параллельные запросы
+// не превысили лимит и не оставили пользователя с двумя адресами по умолчанию.
+func (r *postgresAddressRepository) CreateAddress(ctx context.Context, address *models.Address, maxAddresses int) (*models.Address, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, address.UserID); err != nil {
+		return nil, err
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_addresses WHERE user_id = $1`, address.UserID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count >= maxAddresses {
+		return nil, ErrAddressLimit
+	}
+
+	isDefault := address.IsDefault || count == 0
+	if isDefault {
+		if err := clearDefaultAddress(ctx, tx, address.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now().UTC()
+	created, err := scanAddress(tx.QueryRowContext(ctx,
+		`INSERT INTO user_addresses (id, user_id, label, recipient, phone, line1, line2, city, region, postal_code, country,
+		                             is_default, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13)
+		 RETURNING `+addressColumns,
+		uuid.NewString(), address.UserID, address.Label, address.Recipient, address.Phone, address.Line1, address.Line2,
+		address.City, address.Region, address.PostalCode, address.Country, isDefault, now))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateAddress обновляет поля адреса.
+func (r *postgresAddressRepository) UpdateAddress(ctx context.Context, address *models.Address) (*models.Address, error) {
+	if _, err := uuid.Parse(address.ID); err != nil {
+		return nil, nil
+	}
+	return scanAddress(r.db.QueryRowContext(ctx,
+		`UPDATE user_addresses
+		 SET label = $3, recipient = $4, phone = $5, line1 = $6, line2 = $7, city = $8, region = $9,
+		     postal_code = $10, country = $11, updated_at = $12
+		 WHERE id = $1 AND user_id = $2
+		 RETURNING `+addressColumns,
+		address.ID, address.UserID, address.Label, address.Recipient, address.Phone, address.Line1, address.Line2,
+		address.City, address.Region, address.PostalCode, address.Country, time.Now().UTC()))
+}
+
+// DeleteAddress удаляет адрес и при необходимости назначает новый адрес по умолчанию.
+func (r *postgresAddressRepository) DeleteAddress(ctx context.Context, userID, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return sql.ErrNoRows
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var wasDefault bool
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM user_addresses WHERE id = $1 AND user_id = $2 RETURNING is_default`,
+		id, userID).Scan(&wasDefault)
+	if err != nil {
+		return err
+	}
+	if wasDefault {
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE user_addresses SET is_default = TRUE, updated_at = $2
+			 WHERE id = (SELECT id FROM user_addresses WHERE user_id = $1 ORDER BY created_at DESC, id LIMIT 1)`,
+			userID, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetDefaultAddress переносит признак по умолчанию на адрес id.
+func (r *postgresAddressRepository) SetDefaultAddress(ctx context.Context, userID, id string) (*models.Address, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Сначала проверяем адрес, чтобы не снять признак с текущего адреса по умолчанию понапрасну
+	address, err := scanAddress(tx.QueryRowContext(ctx,
+		`SELECT `+addressColumns+` FROM user_addresses WHERE id = $1 AND user_id = $2 FOR UPDATE`,
+		id, userID))
+	if err != nil || address == nil || address.IsDefault {
+		return address, err
+	}
+
+	if err := clearDefaultAddress(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	address, err = scanAddress(tx.QueryRowContext(ctx,
+		`UPDATE user_addresses SET is_default = TRUE, updated_at = $3
+		 WHERE id = $1 AND user_id = $2
+		 RETURNING `+addressColumns,
+		id, userID, time.Now().UTC()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// clearDefaultAddress снимает признак по умолчанию с адресов пользователя
+func clearDefaultAddress(ctx context.Context, tx *sql.Tx, userID string) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE user_addresses SET is_default = FALSE, updated_at = $2 WHERE user_id = $1 AND is_default`,
+		userID, time.Now().UTC())
+	return err
+}
+
+// scanAddress читает адрес из строки результата со столбцами addressColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanAddress(row rowScanner) (*models.Address, error) {
+	address := &models.Address{}
+	err := row.Scan(
+		&address.ID,
+		&address.UserID,
+		&address.Label,
+		&address.Recipient,
+		&address.Phone,
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.Region,
+		&address.PostalCode,
+		&address.Country,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return address, nil
+}
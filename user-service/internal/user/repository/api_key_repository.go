@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// APIKeyRepository определяет интерфейс хранилища API-ключей.
+type APIKeyRepository interface {
+	// CreateAPIKey сохраняет новый ключ. Если у пользователя уже maxKeys действующих ключей,
+	// возвращает ErrAPIKeyLimit.
+	CreateAPIKey(ctx context.Context, key *models.APIKey, maxKeys int) (*models.APIKey, error)
+	// ListAPIKeys возвращает ключи пользователя, включая отозванные и истекшие, начиная с новых
+	ListAPIKeys(ctx context.Context, userID string) ([]*models.APIKey, error)
+	// GetAPIKeyByHash возвращает ключ по SHA-256 хешу. Возвращает nil, nil, если ключ не найден.
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error)
+	// RevokeAPIKey отзывает ключ пользователя. Возвращает sql.ErrNoRows, если ключ не найден или уже отозван.
+	RevokeAPIKey(ctx context.Context, userID, id string) (*models.APIKey, error)
+	// TouchAPIKey отмечает использование ключа. Время обновляется не чаще раза в минуту.
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// ErrAPIKeyLimit возвращается при попытке выдать ключ сверх допустимого количества
+var ErrAPIKeyLimit = errors.New("достигнуто максимальное количество API-ключей")
+
+// postgresAPIKeyRepository реализует APIKeyRepository для PostgreSQL.
+type postgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAPIKeyRepository создает новый экземпляр postgresAPIKeyRepository.
+func NewPostgresAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &postgresAPIKeyRepository{db: db}
+}
+
+// apiKeyColumns - столбцы таблицы api_keys в порядке, ожидаемом scanAPIKey
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, rate_limit, expires_at, last_used_at, revoked_at, created_at`
+
+// CreateAPIKey сохраняет ключ. Строка пользователя блокируется, чтобы параллельные запросы не превысили лимит.
+func (r *postgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey, maxKeys int) (*models.APIKey, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, key.UserID); err != nil {
+		return nil, err
+	}
+	var count int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM api_keys
+		 WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)`,
+		key.UserID, now).Scan(&count)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxKeys {
+		return nil, ErrAPIKeyLimit
+	}
+
+	created, err := scanAPIKey(tx.QueryRowContext(ctx,
+		`INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, rate_limit, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING `+apiKeyColumns,
+		uuid.NewString(), key.UserID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.RateLimit,
+		key.ExpiresAt, now))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ListAPIKeys выбирает ключи пользователя.
+func (r *postgresAPIKeyRepository) ListAPIKeys(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC, id`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]*models.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// GetAPIKeyByHash выбирает ключ по хешу.
+func (r *postgresAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = $1`,
+		keyHash))
+}
+
+// RevokeAPIKey отмечает ключ отозванным.
+func (r *postgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, userID, id string) (*models.APIKey, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, sql.ErrNoRows
+	}
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx,
+		`UPDATE api_keys SET revoked_at = $3
+		 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+		 RETURNING `+apiKeyColumns,
+		id, userID, time.Now().UTC()))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+// TouchAPIKey обновляет время последнего использования ключа.
+func (r *postgresAPIKeyRepository) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET last_used_at = $2
+		 WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - INTERVAL '1 minute')`,
+		id, usedAt.UTC())
+	return err
+}
+
+// scanAPIKey читает ключ из строки результата со столбцами apiKeyColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		pq.Array(&key.Scopes),
+		&key.RateLimit,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return key, nil
+}
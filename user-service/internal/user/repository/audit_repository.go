@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+)
+
+// AuthEventFilter задает условия выборки из журнала аутентификации.
+// Пустые поля не ограничивают выборку.
+type AuthEventFilter struct {
+	UserID string
+	Email  string
+	IP     string
+	Event  string
+	Since  time.Time
+	Until  time.Time
+	Limit  int // Не больше MaxAuthEventsLimit; 0 - значение по умолчанию
+}
+
+// Ограничения размера выборки из журнала аутентификации
+const (
+	DefaultAuthEventsLimit = 100
+	MaxAuthEventsLimit     = 1000
+)
+
+// AuditRepository определяет интерфейс журнала аутентификации.
+type AuditRepository interface {
+	RecordAuthEvent(ctx context.Context, event *models.AuthEvent) error
+	// ListAuthEvents возвращает события по фильтру, новые первыми
+	ListAuthEvents(ctx context.Context, filter AuthEventFilter) ([]*models.AuthEvent, error)
+}
+
+// postgresAuditRepository реализует AuditRepository для PostgreSQL.
+type postgresAuditRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditRepository создает новый экземпляр postgresAuditRepository.
+func NewPostgresAuditRepository(db *sql.DB) AuditRepository {
+	return &postgresAuditRepository{db: db}
+}
+
+// RecordAuthEvent сохраняет событие в журнале.
+func (r *postgresAuditRepository) RecordAuthEvent(ctx context.Context, event *models.AuthEvent) error {
+	event.CreatedAt = time.Now().UTC()
+	return r.db.QueryRowContext(ctx,
+		`INSERT INTO auth_audit_log (event, user_id, email, ip, details, created_at)
+		 VALUES ($1, NULLIF($2, '')::UUID, $3, $4, $5, $6)
+		 RETURNING id`,
+		event.Event, event.UserID, event.Email, event.IP, event.Details, event.CreatedAt).Scan(&event.ID)
+}
+
+// ListAuthEvents выбирает события журнала по фильтру.
+func (r *postgresAuditRepository) ListAuthEvents(ctx context.Context, filter AuthEventFilter) ([]*models.AuthEvent, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != "" {
+		addCondition("user_id = $%d", filter.UserID)
+	}
+	if filter.Email != "" {
+		addCondition("lower(email) = lower($%d)", filter.Email)
+	}
+	if filter.IP != "" {
+		addCondition("ip = $%d", filter.IP)
+	}
+	if filter.Event != "" {
+		addCondition("event = $%d", filter.Event)
+	}
+	if !filter.Since.IsZero() {
+		addCondition("created_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addCondition("created_at < $%d", filter.Until)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultAuthEventsLimit
+	}
+	if limit > MaxAuthEventsLimit {
+		limit = MaxAuthEventsLimit
+	}
+
+	query := `SELECT id, event, COALESCE(user_id::TEXT, ''), email, ip, details, created_at FROM auth_audit_log`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.AuthEvent{}
+	for rows.Next() {
+		event := &models.AuthEvent{}
+		if err := rows.Scan(&event.ID, &event.Event, &event.UserID, &event.Email, &event.IP, &event.Details, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
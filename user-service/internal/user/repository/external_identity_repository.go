@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+)
+
+// ExternalIdentityRepository определяет интерфейс хранилища привязок к провайдерам OpenID Connect
+// и начатых через них входов.
+type ExternalIdentityRepository interface {
+	// CreateLoginState сохраняет начатый вход
+	CreateLoginState(ctx context.Context, state *models.OIDCLoginState) error
+	// ConsumeLoginState удаляет начатый вход и возвращает его, чтобы state нельзя было использовать повторно.
+	// Возвращает nil, nil, если вход не найден; срок действия проверяет вызывающий.
+	ConsumeLoginState(ctx context.Context, stateHash string) (*models.OIDCLoginState, error)
+	// GetIdentity возвращает привязку по провайдеру и ID пользователя у него. Возвращает nil, nil, если привязки нет.
+	GetIdentity(ctx context.Context, provider, subject string) (*models.ExternalIdentity, error)
+	// LinkIdentity привязывает учетную запись провайдера к пользователю. Возвращает ErrIdentityConflict,
+	// если она уже привязана или у пользователя уже есть другая учетная запись этого провайдера.
+	LinkIdentity(ctx context.Context, identity *models.ExternalIdentity) (*models.ExternalIdentity, error)
+	// TouchIdentity отмечает вход через привязанную учетную запись
+	TouchIdentity(ctx context.Context, provider, subject string, loginAt time.Time) error
+	// ListIdentities возвращает привязки пользователя
+	ListIdentities(ctx context.Context, userID string) ([]*models.ExternalIdentity, error)
+	// DeleteIdentity отвязывает учетную запись провайдера. Возвращает sql.ErrNoRows, если привязки нет.
+	DeleteIdentity(ctx context.Context, userID, provider string) error
+}
+
+// ErrIdentityConflict возвращается, если привязка нарушает уникальность провайдера или его пользователя
+var ErrIdentityConflict = errors.New("учетная запись провайдера уже привязана")
+
+// postgresExternalIdentityRepository реализует ExternalIdentityRepository для PostgreSQL.
+type postgresExternalIdentityRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresExternalIdentityRepository создает новый экземпляр postgresExternalIdentityRepository.
+func NewPostgresExternalIdentityRepository(db *sql.DB) ExternalIdentityRepository {
+	return &postgresExternalIdentityRepository{db: db}
+}
+
+// externalIdentityColumns - столбцы таблицы external_identities в порядке, ожидаемом scanExternalIdentity
+const externalIdentityColumns = `provider, subject, user_id, email, created_at, last_login_at`
+
+// CreateLoginState сохраняет начатый вход.
+func (r *postgresExternalIdentityRepository) CreateLoginState(ctx context.Context, state *models.OIDCLoginState) error {
+	state.CreatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)`,
+		state.StateHash, state.Provider, state.CodeVerifier, state.Nonce, state.ExpiresAt, state.CreatedAt)
+	return err
+}
+
+// ConsumeLoginState удаляет начатый вход одним запросом, поэтому параллельные попытки завершить его не пройдут обе.
+func (r *postgresExternalIdentityRepository) ConsumeLoginState(ctx context.Context, stateHash string) (*models.OIDCLoginState, error) {
+	state := &models.OIDCLoginState{}
+	err := r.db.QueryRowContext(ctx,
+		`DELETE FROM oidc_login_states WHERE state_hash = $1
+		 RETURNING state_hash, provider, code_verifier, nonce, expires_at, created_at`,
+		stateHash).Scan(&state.StateHash, &state.Provider, &state.CodeVerifier, &state.Nonce, &state.ExpiresAt, &state.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+// GetIdentity выбирает привязку по провайдеру и ID пользователя у него.
+func (r *postgresExternalIdentityRepository) GetIdentity(ctx context.Context, provider, subject string) (*models.ExternalIdentity, error) {
+	return scanExternalIdentity(r.db.QueryRowContext(ctx,
+		`SELECT `+externalIdentityColumns+` FROM external_identities WHERE provider = $1 AND subject = $2`,
+		provider, subject))
+}
+
+// LinkIdentity сохраняет привязку. Конфликт по любому из уникальных ключей означает ErrIdentityConflict.
+func (r *postgresExternalIdentityRepository) LinkIdentity(ctx context.Context, identity *models.ExternalIdentity) (*models.ExternalIdentity, error) {
+	linked, err := scanExternalIdentity(r.db.QueryRowContext(ctx,
+		`INSERT INTO external_identities (provider, subject, user_id, email, created_at)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT DO NOTHING
+		 RETURNING `+externalIdentityColumns,
+		identity.Provider, identity.Subject, identity.UserID, identity.Email, time.Now().UTC()))
+	if err != nil {
+		return nil, err
+	}
+	if linked == nil {
+		return nil, ErrIdentityConflict
+	}
+	return linked, nil
+}
+
+// TouchIdentity обновляет время последнего входа через привязку.
+func (r *postgresExternalIdentityRepository) TouchIdentity(ctx context.Context, provider, subject string, loginAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE external_identities SET last_login_at = $3 WHERE provider = $1 AND subject = $2`,
+		provider, subject, loginAt.UTC())
+	return err
+}
+
+// ListIdentities выбирает привязки пользователя в порядке создания.
+func (r *postgresExternalIdentityRepository) ListIdentities(ctx context.Context, userID string) ([]*models.ExternalIdentity, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+externalIdentityColumns+` FROM external_identities WHERE user_id = $1 ORDER BY created_at, provider`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := make([]*models.ExternalIdentity, 0)
+	for rows.Next() {
+		identity, err := scanExternalIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+// DeleteIdentity удаляет привязку пользователя к провайдеру.
+func (r *postgresExternalIdentityRepository) DeleteIdentity(ctx context.Context, userID, provider string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM external_identities WHERE user_id = $1 AND provider = $2`,
+		userID, provider)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// scanExternalIdentity читает привязку из строки результата со столбцами externalIdentityColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanExternalIdentity(row rowScanner) (*models.ExternalIdentity, error) {
+	identity := &models.ExternalIdentity{}
+	err := row.Scan(
+		&identity.Provider,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return identity, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+)
+
+// LoginAttemptStore хранит счетчики неудачных попыток входа и блокировки по ключу (email или IP-адрес).
+// Реализации: NewPostgresLoginAttemptStore (общее состояние для нескольких экземпляров сервиса)
+// и NewMemoryLoginAttemptStore (один экземпляр, состояние теряется при перезапуске).
+type LoginAttemptStore interface {
+	// GetLoginThrottle возвращает nil, nil, если для ключа нет записей
+	GetLoginThrottle(ctx context.Context, key string) (*models.LoginThrottle, error)
+	// RecordLoginFailure атомарно увеличивает счетчик неудач. Если последняя неудача была раньше
+	// now - window, счетчик начинается заново.
+	RecordLoginFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginThrottle, error)
+	// LockLogin запрещает вход по ключу до until
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	// ResetLogin сбрасывает счетчик и блокировку (успешный вход или разблокировка администратором)
+	ResetLogin(ctx context.Context, key string) error
+	// DeleteStaleLoginThrottles удаляет записи без действующей блокировки с последней неудачей раньше before
+	DeleteStaleLoginThrottles(ctx context.Context, before time.Time) (int64, error)
+}
+
+// postgresLoginAttemptStore реализует LoginAttemptStore для PostgreSQL.
+type postgresLoginAttemptStore struct {
+	db *sql.DB
+}
+
+// NewPostgresLoginAttemptStore создает новый экземпляр postgresLoginAttemptStore.
+func NewPostgresLoginAttemptStore(db *sql.DB) LoginAttemptStore {
+	return &postgresLoginAttemptStore{db: db}
+}
+
+// GetLoginThrottle извлекает состояние ограничения попыток по ключу.
+func (s *postgresLoginAttemptStore) GetLoginThrottle(ctx context.Context, key string) (*models.LoginThrottle, error) {
+	throttle := &models.LoginThrottle{}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT key, failures, last_failure_at, locked_until FROM login_attempts WHERE key = $1`,
+		key).Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return throttle, nil
+}
+
+// RecordLoginFailure увеличивает счетчик неудач одним запросом, поэтому параллельные попытки не теряются.
+func (s *postgresLoginAttemptStore) RecordLoginFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginThrottle, error) {
+	throttle := &models.LoginThrottle{}
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO login_attempts (key, failures, last_failure_at) VALUES ($1, 1, $2)
+		 ON CONFLICT (key) DO UPDATE SET
+		     failures = CASE WHEN login_attempts.last_failure_at < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+		     last_failure_at = $2
+		 RETURNING key, failures, last_failure_at, locked_until`,
+		key, now, now.Add(-window)).Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+// LockLogin устанавливает время окончания блокировки.
+func (s *postgresLoginAttemptStore) LockLogin(ctx context.Context, key string, until time.Time) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE login_attempts SET locked_until = $2 WHERE key = $1`, key, until)
+	return err
+}
+
+// ResetLogin удаляет запись о попытках входа.
+func (s *postgresLoginAttemptStore) ResetLogin(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE key = $1`, key)
+	return err
+}
+
+// DeleteStaleLoginThrottles удаляет устаревшие записи о попытках входа.
+func (s *postgresLoginAttemptStore) DeleteStaleLoginThrottles(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM login_attempts
+		 WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until <= now())`,
+		before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// memoryLoginAttemptStore реализует LoginAttemptStore в памяти процесса.
+type memoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	throttles map[string]models.LoginThrottle
+}
+
+// NewMemoryLoginAttemptStore создает новый экземпляр memoryLoginAttemptStore.
+func NewMemoryLoginAttemptStore() LoginAttemptStore {
+	return &memoryLoginAttemptStore{throttles: make(map[string]models.LoginThrottle)}
+}
+
+// GetLoginThrottle возвращает копию состояния по ключу.
+func (s *memoryLoginAttemptStore) GetLoginThrottle(_ context.Context, key string) (*models.LoginThrottle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	throttle, ok := s.throttles[key]
+	if !ok {
+		return nil, nil
+	}
+	return &throttle, nil
+}
+
+// RecordLoginFailure увеличивает счетчик неудач.
+func (s *memoryLoginAttemptStore) RecordLoginFailure(_ context.Context, key string, now time.Time, window time.Duration) (*models.LoginThrottle, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	throttle, ok := s.throttles[key]
+	if !ok || throttle.LastFailureAt.Before(now.Add(-window)) {
+		throttle.Key = key
+		throttle.Failures = 0
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = now
+	s.throttles[key] = throttle
+	return &throttle, nil
+}
+
+// LockLogin устанавливает время окончания блокировки.
+func (s *memoryLoginAttemptStore) LockLogin(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	throttle, ok := s.throttles[key]
+	if !ok {
+		return nil
+	}
+	throttle.LockedUntil = &until
+	s.throttles[key] = throttle
+	return nil
+}
+
+// ResetLogin удаляет состояние по ключу.
+func (s *memoryLoginAttemptStore) ResetLogin(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.throttles, key)
+	return nil
+}
+
+// DeleteStaleLoginThrottles удаляет устаревшие записи.
+func (s *memoryLoginAttemptStore) DeleteStaleLoginThrottles(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for key, throttle := range s.throttles {
+		if throttle.LastFailureAt.Before(before) && (throttle.LockedUntil == nil || !throttle.LockedUntil.After(now)) {
+			delete(s.throttles, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// OAuthRepository определяет интерфейс хранилища клиентов OAuth2, согласий пользователей и кодов авторизации.
+type OAuthRepository interface {
+	// CreateClient сохраняет нового клиента и присваивает ему ID
+	CreateClient(ctx context.Context, client *models.OAuthClient) (*models.OAuthClient, error)
+	// SaveClient сохраняет клиента с заданным ID или обновляет параметры и секрет существующего
+	SaveClient(ctx context.Context, client *models.OAuthClient) (*models.OAuthClient, error)
+	// GetClient возвращает клиента по ID. Возвращает nil, nil, если клиент не найден.
+	GetClient(ctx context.Context, id string) (*models.OAuthClient, error)
+	// ListClients возвращает всех клиентов в порядке регистрации
+	ListClients(ctx context.Context) ([]*models.OAuthClient, error)
+	// DeleteClient удаляет клиента вместе с его кодами, согласиями и токенами обновления.
+	// Возвращает sql.ErrNoRows, если клиент не найден.
+	DeleteClient(ctx context.Context, id string) error
+
+	// GetConsent возвращает согласие пользователя клиенту. Возвращает nil, nil, если согласия нет.
+	GetConsent(ctx context.Context, userID, clientID string) (*models.OAuthConsent, error)
+	// SaveConsent добавляет разрешения scopes к согласию пользователя клиенту
+	SaveConsent(ctx context.Context, userID, clientID string, scopes []string) error
+	// ListConsents возвращает согласия пользователя с названиями клиентов
+	ListConsents(ctx context.Context, userID string) ([]*models.OAuthConsent, error)
+	// DeleteConsent отзывает согласие, а вместе с ним токены обновления и неиспользованные коды клиента
+	// для этого пользователя. Возвращает sql.ErrNoRows, если согласия нет.
+	DeleteConsent(ctx context.Context, userID, clientID string) error
+
+	// CreateAuthorizationCode сохраняет код авторизации
+	CreateAuthorizationCode(ctx context.Context, code *models.OAuthAuthorizationCode) error
+	// ConsumeAuthorizationCode удаляет код и возвращает его, чтобы код нельзя было использовать повторно.
+	// Возвращает nil, nil, если код не найден; срок действия проверяет вызывающий.
+	ConsumeAuthorizationCode(ctx context.Context, codeHash string) (*models.OAuthAuthorizationCode, error)
+}
+
+// postgresOAuthRepository реализует OAuthRepository для PostgreSQL.
+type postgresOAuthRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresOAuthRepository создает новый экземпляр postgresOAuthRepository.
+func NewPostgresOAuthRepository(db *sql.DB) OAuthRepository {
+	return &postgresOAuthRepository{db: db}
+}
+
+// oauthClientColumns - столбцы таблицы oauth_clients в порядке, ожидаемом scanOAuthClient
+const oauthClientColumns = `id, name, secret_hash, redirect_uris, grant_types, scopes, created_at`
+
+// CreateClient сохраняет клиента. Для публичного клиента хеш секрета не сохраняется.
+func (r *postgresOAuthRepository) CreateClient(ctx context.Context, client *models.OAuthClient) (*models.OAuthClient, error) {
+	var secretHash sql.NullString
+	if client.Confidential {
+		secretHash = sql.NullString{String: client.SecretHash, Valid: true}
+	}
+	return scanOAuthClient(r.db.QueryRowContext(ctx,
+		`INSERT INTO oauth_clients (id, name, secret_hash, redirect_uris, grant_types, scopes, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING `+oauthClientColumns,
+		uuid.NewString(), client.Name, secretHash, pq.Array(client.RedirectURIs), pq.Array(client.GrantTypes),
+		pq.Array(client.Scopes), time.Now().UTC()))
+}
+
+// SaveClient вставляет клиента с его ID; при конфликте по ID заменяет параметры, сохраняя дату регистрации.
+func (r *postgresOAuthRepository) SaveClient(ctx context.Context, client *models.OAuthClient) (*models.OAuthClient, error) {
+	var secretHash sql.NullString
+	if client.Confidential {
+		secretHash = sql.NullString{String: client.SecretHash, Valid: true}
+	}
+	return scanOAuthClient(r.db.QueryRowContext(ctx,
+		`INSERT INTO oauth_clients (id, name, secret_hash, redirect_uris, grant_types, scopes, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, secret_hash = EXCLUDED.secret_hash,
+		     redirect_uris = EXCLUDED.redirect_uris, grant_types = EXCLUDED.grant_types, scopes = EXCLUDED.scopes
+		 RETURNING `+oauthClientColumns,
+		client.ID, client.Name, secretHash, pq.Array(client.RedirectURIs), pq.Array(client.GrantTypes),
+		pq.Array(client.Scopes), time.Now().UTC()))
+}
+
+// GetClient выбирает клиента по ID.
+func (r *postgresOAuthRepository) GetClient(ctx context.Context, id string) (*models.OAuthClient, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, nil
+	}
+	return scanOAuthClient(r.db.QueryRowContext(ctx,
+		`SELECT `+oauthClientColumns+` FROM oauth_clients WHERE id = $1`, id))
+}
+
+// ListClients выбирает всех клиентов.
+func (r *postgresOAuthRepository) ListClients(ctx context.Context) ([]*models.OAuthClient, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+oauthClientColumns+` FROM oauth_clients ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients := make([]*models.OAuthClient, 0)
+	for rows.Next() {
+		client, err := scanOAuthClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+	return clients, rows.Err()
+}
+
+// DeleteClient удаляет клиента; связанные записи удаляются каскадно.
+func (r *postgresOAuthRepository) DeleteClient(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return sql.ErrNoRows
+	}
+	result, err := r.db.ExecContext(ctx, `DELETE FROM oauth_clients WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetConsent выбирает согласие пользователя клиенту.
+func (r *postgresOAuthRepository) GetConsent(ctx context.Context, userID, clientID string) (*models.OAuthConsent, error) {
+	return scanOAuthConsent(r.db.QueryRowContext(ctx,
+		`SELECT c.user_id, c.client_id, cl.name, c.scopes, c.created_at, c.updated_at
+		 FROM oauth_consents c JOIN oauth_clients cl ON cl.id = c.client_id
+		 WHERE c.user_id = $1 AND c.client_id = $2`,
+		userID, clientID))
+}
+
+// SaveConsent создает согласие или объединяет его разрешения с новыми.
+func (r *postgresOAuthRepository) SaveConsent(ctx context.Context, userID, clientID string, scopes []string) error {
+	now := time.Now().UTC()
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO oauth_consents (user_id, client_id, scopes, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $4)
+		 ON CONFLICT (user_id, client_id) DO UPDATE
+		 SET scopes = ARRAY(SELECT DISTINCT s FROM unnest(oauth_consents.scopes || EXCLUDED.scopes) AS s ORDER BY s),
+		     updated_at = EXCLUDED.updated_at`,
+		userID, clientID, pq.Array(scopes), now)
+	return err
+}
+
+// ListConsents выбирает согласия пользователя, начиная с последних измененных.
+func (r *postgresOAuthRepository) ListConsents(ctx context.Context, userID string) ([]*models.OAuthConsent, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT c.user_id, c.client_id, cl.name, c.scopes, c.created_at, c.updated_at
+		 FROM oauth_consents c JOIN oauth_clients cl ON cl.id = c.client_id
+		 WHERE c.user_id = $1
+		 ORDER BY c.updated_at DESC, c.client_id`,
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	consents := make([]*models.OAuthConsent, 0)
+	for rows.Next() {
+		consent, err := scanOAuthConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		consents = append(consents, consent)
+	}
+	return consents, rows.Err()
+}
+
+// DeleteConsent в одной транзакции удаляет согласие, отзывает токены обновления клиента и удаляет его коды.
+func (r *postgresOAuthRepository) DeleteConsent(ctx context.Context, userID, clientID string) error {
+	if _, err := uuid.Parse(clientID); err != nil {
+		return sql.ErrNoRows
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM oauth_consents WHERE user_id = $1 AND client_id = $2`, userID, clientID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $3 WHERE user_id = $1 AND client_id = $2 AND revoked_at IS NULL`,
+		userID, clientID, time.Now().UTC()); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM oauth_authorization_codes WHERE user_id = $1 AND client_id = $2`, userID, clientID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateAuthorizationCode сохраняет код авторизации.
+func (r *postgresOAuthRepository) CreateAuthorizationCode(ctx context.Context, code *models.OAuthAuthorizationCode) error {
+	code.CreatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO oauth_authorization_codes
+		 (code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, nonce, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		code.CodeHash, code.ClientID, code.UserID, code.RedirectURI, pq.Array(code.Scopes), code.CodeChallenge,
+		code.Nonce, code.ExpiresAt, code.CreatedAt)
+	return err
+}
+
+// ConsumeAuthorizationCode удаляет код одним запросом, поэтому параллельные обмены одного кода не пройдут оба.
+func (r *postgresOAuthRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (*models.OAuthAuthorizationCode, error) {
+	code := &models.OAuthAuthorizationCode{}
+	err := r.db.QueryRowContext(ctx,
+		`DELETE FROM oauth_authorization_codes WHERE code_hash = $1
+		 RETURNING code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, nonce, expires_at, created_at`,
+		codeHash).Scan(
+		&code.CodeHash,
+		&code.ClientID,
+		&code.UserID,
+		&code.RedirectURI,
+		pq.Array(&code.Scopes),
+		&code.CodeChallenge,
+		&code.Nonce,
+		&code.ExpiresAt,
+		&code.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return code, nil
+}
+
+// scanOAuthClient читает клиента из строки результата со столбцами oauthClientColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanOAuthClient(row rowScanner) (*models.OAuthClient, error) {
+	client := &models.OAuthClient{}
+	var secretHash sql.NullString
+	err := row.Scan(
+		&client.ID,
+		&client.Name,
+		&secretHash,
+		pq.Array(&client.RedirectURIs),
+		pq.Array(&client.GrantTypes),
+		pq.Array(&client.Scopes),
+		&client.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	client.SecretHash = secretHash.String
+	client.Confidential = secretHash.Valid
+	return client, nil
+}
+
+// scanOAuthConsent читает согласие вместе с названием клиента.
+// Возвращает nil, nil, если строка не найдена.
+func scanOAuthConsent(row rowScanner) (*models.OAuthConsent, error) {
+	consent := &models.OAuthConsent{}
+	err := row.Scan(
+		&consent.UserID,
+		&consent.ClientID,
+		&consent.ClientName,
+		pq.Array(&consent.Scopes),
+		&consent.CreatedAt,
+		&consent.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return consent, nil
+}
@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrRefreshTokenUsed возвращается, если токен обновления уже обменян или отозван
+var ErrRefreshTokenUsed = errors.New("токен обновления уже использован")
+
+// TokenRepository определяет интерфейс хранилища токенов обновления и отозванных токенов доступа.
+// Реализует auth.Denylist, поэтому может использоваться для проверки токенов напрямую.
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	// GetRefreshTokenByHash возвращает nil, nil, если токен не найден
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// RotateRefreshToken отмечает токен oldID использованным и сохраняет следующий токен семейства.
+	// Возвращает ErrRefreshTokenUsed, если oldID уже обменян или отозван (в том числе параллельным запросом).
+	RotateRefreshToken(ctx context.Context, oldID string, next *models.RefreshToken) error
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+	RevokeUserTokens(ctx context.Context, userID string) error
+
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	ListRevokedAccessTokens(ctx context.Context) ([]auth.RevokedToken, error)
+
+	// CreatePasswordResetToken сохраняет хеш токена сброса пароля.
+	// Ранее выданные неиспользованные токены пользователя становятся недействительными.
+	CreatePasswordResetToken(ctx context.Context, userID, hash string, expiresAt time.Time) error
+	// ConsumePasswordResetToken отмечает токен использованным и возвращает ID пользователя.
+	// Возвращает пустую строку, если токен не найден, уже использован или истек.
+	ConsumePasswordResetToken(ctx context.Context, hash string) (string, error)
+
+	// CreateEmailVerificationToken сохраняет хеш токена подтверждения адреса email.
+	// Ранее выданные неиспользованные токены пользователя становятся недействительными.
+	CreateEmailVerificationToken(ctx context.Context, userID, email, hash string, expiresAt time.Time) error
+	// ConsumeEmailVerificationToken отмечает токен использованным и возвращает ID пользователя
+	// и подтверждаемый email. Возвращает пустые строки, если токен не найден, уже использован или истек.
+	ConsumeEmailVerificationToken(ctx context.Context, hash string) (userID, email string, err error)
+
+	// CreateLoginChallenge сохраняет незавершенный вход, ожидающий второй фактор
+	CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error
+	// GetLoginChallenge возвращает nil, nil, если вход не найден
+	GetLoginChallenge(ctx context.Context, hash string) (*models.LoginChallenge, error)
+	// RecordLoginChallengeFailure увеличивает счетчик неверных кодов и возвращает его новое значение
+	RecordLoginChallengeFailure(ctx context.Context, hash string) (int, error)
+	// ConsumeLoginChallenge атомарно завершает вход. Возвращает false, если вход уже завершен или истек.
+	ConsumeLoginChallenge(ctx context.Context, hash string) (bool, error)
+
+	// DeleteExpiredTokens удаляет истекшие токены обновления, сброса пароля, подтверждения email, коды авторизации OAuth2,
+	// незавершенные входы (в том числе через провайдеры OpenID Connect) и записи об отозванных токенах доступа
+	DeleteExpiredTokens(ctx context.Context) (int64, error)
+}
+
+// postgresTokenRepository реализует TokenRepository для PostgreSQL.
+type postgresTokenRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTokenRepository создает новый экземпляр postgresTokenRepository.
+func NewPostgresTokenRepository(db *sql.DB) TokenRepository {
+	return &postgresTokenRepository{db: db}
+}
+
+// CreateRefreshToken сохраняет новый токен обновления.
+func (r *postgresTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	return insertRefreshToken(ctx, r.db, token)
+}
+
+// execer - общий интерфейс *sql.DB и *sql.Tx для запросов без результата
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertRefreshToken сохраняет токен обновления в рамках соединения или транзакции.
+func insertRefreshToken(ctx context.Context, db execer, token *models.RefreshToken) error {
+	token.ID = uuid.NewString()
+	token.CreatedAt = time.Now().UTC()
+
+	var clientID sql.NullString
+	var scopes interface{}
+	if token.ClientID != "" {
+		clientID = sql.NullString{String: token.ClientID, Valid: true}
+		scopes = pq.Array(token.Scopes)
+	}
+
+	query := `INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at, client_id, scopes)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := db.ExecContext(ctx, query, token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+		clientID, scopes)
+	return err
+}
+
+// GetRefreshTokenByHash извлекает токен обновления по хешу.
+func (r *postgresTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	token := &models.RefreshToken{}
+	var clientID sql.NullString
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, created_at, used_at, revoked_at, client_id, scopes
+			   FROM refresh_tokens
+			   WHERE token_hash = $1`
+
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&clientID,
+		pq.Array(&token.Scopes),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	token.ClientID = clientID.String
+	return token, nil
+}
+
+// RotateRefreshToken в одной транзакции отмечает старый токен использованным и сохраняет новый.
+// Условное обновление гарантирует, что из двух параллельных обменов одного токена успешен только один.
+func (r *postgresTokenRepository) RotateRefreshToken(ctx context.Context, oldID string, next *models.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET used_at = $2
+		 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL`,
+		oldID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRefreshTokenUsed
+	}
+
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RevokeTokenFamily отзывает все токены семейства.
+func (r *postgresTokenRepository) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL`,
+		familyID, time.Now().UTC())
+	return err
+}
+
+// RevokeUserTokens отзывает все токены обновления пользователя (выход на всех устройствах).
+func (r *postgresTokenRepository) RevokeUserTokens(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`,
+		userID, time.Now().UTC())
+	return err
+}
+
+// RevokeAccessToken добавляет токен доступа в список отозванных до истечения его срока.
+func (r *postgresTokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO revoked_access_tokens (jti, expires_at) VALUES ($1, $2)
+		 ON CONFLICT (jti) DO NOTHING`,
+		jti, expiresAt)
+	return err
+}
+
+// IsRevoked сообщает, отозван ли токен доступа с указанным jti.
+func (r *postgresTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1 AND expires_at > now())`,
+		jti).Scan(&revoked)
+	return revoked, err
+}
+
+// ListRevokedAccessTokens возвращает отозванные токены доступа, срок которых еще не истек.
+func (r *postgresTokenRepository) ListRevokedAccessTokens(ctx context.Context) ([]auth.RevokedToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT jti, expires_at FROM revoked_access_tokens WHERE expires_at > now() ORDER BY expires_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []auth.RevokedToken{}
+	for rows.Next() {
+		var token auth.RevokedToken
+		if err := rows.Scan(&token.JTI, &token.ExpiresAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, rows.Err()
+}
+
+// CreatePasswordResetToken сохраняет токен сброса пароля, удаляя прежние неиспользованные токены пользователя.
+func (r *postgresTokenRepository) CreatePasswordResetToken(ctx context.Context, userID, hash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM password_reset_tokens WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO password_reset_tokens (token_hash, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4)`,
+		hash, userID, expiresAt, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConsumePasswordResetToken атомарно отмечает действующий токен использованным,
+// поэтому один токен нельзя применить дважды даже параллельными запросами.
+func (r *postgresTokenRepository) ConsumePasswordResetToken(ctx context.Context, hash string) (string, error) {
+	var userID string
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE password_reset_tokens SET used_at = now()
+		 WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		 RETURNING user_id`,
+		hash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+// CreateEmailVerificationToken сохраняет токен подтверждения email, удаляя прежние неиспользованные токены пользователя.
+func (r *postgresTokenRepository) CreateEmailVerificationToken(ctx context.Context, userID, email, hash string, expiresAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM email_verification_tokens WHERE user_id = $1 AND used_at IS NULL`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO email_verification_tokens (token_hash, user_id, email, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		hash, userID, email, expiresAt, time.Now().UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConsumeEmailVerificationToken атомарно отмечает действующий токен подтверждения email использованным.
+func (r *postgresTokenRepository) ConsumeEmailVerificationToken(ctx context.Context, hash string) (string, string, error) {
+	var userID, email string
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE email_verification_tokens SET used_at = now()
+		 WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		 RETURNING user_id, email`,
+		hash).Scan(&userID, &email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+	return userID, email, nil
+}
+
+// CreateLoginChallenge сохраняет незавершенный вход.
+func (r *postgresTokenRepository) CreateLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error {
+	challenge.CreatedAt = time.Now().UTC()
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO login_challenges (token_hash, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4)`,
+		challenge.TokenHash, challenge.UserID, challenge.ExpiresAt, challenge.CreatedAt)
+	return err
+}
+
+// GetLoginChallenge извлекает незавершенный вход по хешу токена.
+func (r *postgresTokenRepository) GetLoginChallenge(ctx context.Context, hash string) (*models.LoginChallenge, error) {
+	challenge := &models.LoginChallenge{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT token_hash, user_id, attempts, expires_at, created_at, used_at FROM login_challenges WHERE token_hash = $1`,
+		hash).Scan(&challenge.TokenHash, &challenge.UserID, &challenge.Attempts, &challenge.ExpiresAt, &challenge.CreatedAt, &challenge.UsedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// RecordLoginChallengeFailure увеличивает счетчик неверных кодов.
+func (r *postgresTokenRepository) RecordLoginChallengeFailure(ctx context.Context, hash string) (int, error) {
+	var attempts int
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE login_challenges SET attempts = attempts + 1 WHERE token_hash = $1 RETURNING attempts`,
+		hash).Scan(&attempts)
+	return attempts, err
+}
+
+// ConsumeLoginChallenge отмечает вход завершенным.
+func (r *postgresTokenRepository) ConsumeLoginChallenge(ctx context.Context, hash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE login_challenges SET used_at = now()
+		 WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()`,
+		hash)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteExpiredTokens удаляет истекшие токены обновления, сброса пароля, подтверждения email, коды авторизации OAuth2,
+// незавершенные входы (в том числе через провайдеры OpenID Connect) и записи об отозванных токенах доступа.
+func (r *postgresTokenRepository) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	var deleted int64
+	for _, query := range []string{
+		`DELETE FROM refresh_tokens WHERE expires_at <= now()`,
+		`DELETE FROM password_reset_tokens WHERE expires_at <= now()`,
+		`DELETE FROM email_verification_tokens WHERE expires_at <= now()`,
+		`DELETE FROM login_challenges WHERE expires_at <= now()`,
+		`DELETE FROM oidc_login_states WHERE expires_at <= now()`,
+		`DELETE FROM oauth_authorization_codes WHERE expires_at <= now()`,
+		`DELETE FROM revoked_access_tokens WHERE expires_at <= now()`,
+	} {
+		result, err := r.db.ExecContext(ctx, query)
+		if err != nil {
+			return deleted, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += rowsAffected
+	}
+	return deleted, nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+)
+
+// TwoFactorRepository определяет интерфейс хранилища настроек двухфакторной аутентификации
+// и кодов восстановления.
+type TwoFactorRepository interface {
+	// GetTwoFactor возвращает nil, nil, если пользователь не начинал подключение 2FA
+	GetTwoFactor(ctx context.Context, userID string) (*models.TwoFactor, error)
+	// SaveTwoFactorSecret сохраняет новый неподтвержденный секрет. Подтвержденный секрет не заменяется:
+	// в этом случае возвращает false.
+	SaveTwoFactorSecret(ctx context.Context, userID, secret string) (bool, error)
+	// ConfirmTwoFactor включает 2FA, запоминает принятый шаг и заменяет коды восстановления
+	ConfirmTwoFactor(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error
+	// UseTwoFactorStep атомарно запоминает принятый шаг TOTP. Возвращает false, если код
+	// этого или более позднего шага уже использован.
+	UseTwoFactorStep(ctx context.Context, userID string, step int64) (bool, error)
+	// ReplaceRecoveryCodes удаляет все коды восстановления пользователя и сохраняет новые
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	// UseRecoveryCode отмечает код восстановления использованным. Возвращает false, если код
+	// не найден или уже использован.
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	// DeleteTwoFactor отключает 2FA и удаляет коды восстановления
+	DeleteTwoFactor(ctx context.Context, userID string) error
+}
+
+// postgresTwoFactorRepository реализует TwoFactorRepository для PostgreSQL.
+type postgresTwoFactorRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTwoFactorRepository создает новый экземпляр postgresTwoFactorRepository.
+func NewPostgresTwoFactorRepository(db *sql.DB) TwoFactorRepository {
+	return &postgresTwoFactorRepository{db: db}
+}
+
+// GetTwoFactor извлекает настройку 2FA пользователя.
+func (r *postgresTwoFactorRepository) GetTwoFactor(ctx context.Context, userID string) (*models.TwoFactor, error) {
+	twoFactor := &models.TwoFactor{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id, secret, confirmed_at, last_used_step, created_at FROM user_two_factor WHERE user_id = $1`,
+		userID).Scan(&twoFactor.UserID, &twoFactor.Secret, &twoFactor.ConfirmedAt, &twoFactor.LastUsedStep, &twoFactor.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return twoFactor, nil
+}
+
+// SaveTwoFactorSecret сохраняет неподтвержденный секрет, заменяя прежний неподтвержденный.
+func (r *postgresTwoFactorRepository) SaveTwoFactorSecret(ctx context.Context, userID, secret string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_two_factor (user_id, secret, created_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = EXCLUDED.created_at
+		 WHERE user_two_factor.confirmed_at IS NULL`,
+		userID, secret, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// ConfirmTwoFactor в одной транзакции включает 2FA и сохраняет коды восстановления.
+func (r *postgresTwoFactorRepository) ConfirmTwoFactor(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE user_two_factor SET confirmed_at = $2, last_used_step = $3
+		 WHERE user_id = $1 AND confirmed_at IS NULL`,
+		userID, time.Now().UTC(), step)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UseTwoFactorStep запоминает шаг TOTP, только если он больше последнего принятого.
+func (r *postgresTwoFactorRepository) UseTwoFactorStep(ctx context.Context, userID string, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_two_factor SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`,
+		userID, step)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// ReplaceRecoveryCodes заменяет коды восстановления пользователя.
+func (r *postgresTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceRecoveryCodes заменяет коды восстановления в рамках транзакции.
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID string, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO two_factor_recovery_codes (code_hash, user_id, created_at) VALUES ($1, $2, $3)`,
+			hash, userID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode атомарно отмечает код восстановления использованным.
+func (r *postgresTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE two_factor_recovery_codes SET used_at = $3
+		 WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userID, codeHash, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteTwoFactor удаляет настройку 2FA и коды восстановления пользователя.
+func (r *postgresTwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_two_factor WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors" // Для стандартных ошибок, таких как sql.ErrNoRows
+	"fmt"
+	"strings"
 	"time"
 
 	// ВАЖНО: Замените 'your_project_module' на имя вашего модуля из go.mod
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
 
 	"github.com/google/uuid" // Для генерации UUID в качестве ID
+	"github.com/lib/pq"
 )
 
 // UserRepository определяет интерфейс для взаимодействия с хранилищем данных пользователей.
@@ -18,11 +21,88 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) (*models.User, error)
-	Delete(ctx context.Context, id string) error // Новый метод
-	List(ctx context.Context) ([]*models.User, error)
+	// ListPage возвращает до opts.Limit пользователей по фильтру в порядке opts.SortBy,
+	// следующих за opts.After (nil - с начала)
+	ListPage(ctx context.Context, opts UserListOptions) ([]*models.User, error)
+	// Count возвращает число пользователей, подходящих под фильтр
+	Count(ctx context.Context, filter UserFilter) (int64, error)
+	// GetByIDs возвращает найденных пользователей из ids в произвольном порядке
+	GetByIDs(ctx context.Context, ids []string) ([]*models.User, error)
+	// AddRole добавляет роль пользователю (повторное добавление ничего не меняет).
+	// Возвращает nil, nil, если пользователь не найден.
+	AddRole(ctx context.Context, id, role string) (*models.User, error)
+	// RemoveRole отзывает роль у пользователя. Возвращает nil, nil, если пользователь не найден.
+	RemoveRole(ctx context.Context, id, role string) (*models.User, error)
+	// UpdatePassword сохраняет новый хеш пароля. Возвращает sql.ErrNoRows, если пользователь не найден.
+	UpdatePassword(ctx context.Context, id, passwordHash string) error
+	// ReplacePasswordHash заменяет хеш пароля, только если текущий хеш равен oldHash
+	// (пересчет хеша при входе не должен затирать пароль, смененный параллельно).
+	// Возвращает sql.ErrNoRows, если пользователь не найден или хеш уже изменен.
+	ReplacePasswordHash(ctx context.Context, id, oldHash, newHash string) error
+	// MarkEmailVerified отмечает email пользователя подтвержденным, если текущий email совпадает с email.
+	// Возвращает nil, nil, если пользователь не найден или email уже изменен.
+	MarkEmailVerified(ctx context.Context, id, email string) (*models.User, error)
 	// TODO: Добавьте другие методы по мере необходимости (List и т.д.)
 }
 
+// Поля сортировки списка пользователей
+const (
+	UserSortCreatedAt = "created_at"
+	UserSortEmail     = "email"
+	UserSortUsername  = "username"
+)
+
+// userSortColumns - поля сортировки и соответствующие им столбцы.
+// Вторым ключом сортировки всегда служит id, чтобы порядок был однозначным.
+var userSortColumns = map[string]string{
+	UserSortCreatedAt: "created_at",
+	UserSortEmail:     "email",
+	UserSortUsername:  "username",
+}
+
+// IsValidUserSort сообщает, поддерживается ли сортировка по полю field
+func IsValidUserSort(field string) bool {
+	_, ok := userSortColumns[field]
+	return ok
+}
+
+// UserSortValue возвращает значение поля сортировки field пользователя для курсора
+func UserSortValue(user *models.User, field string) string {
+	switch field {
+	case UserSortEmail:
+		return user.Email
+	case UserSortUsername:
+		return user.Username
+	default:
+		return user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// UserFilter задает условия выборки пользователей. Пустые поля не ограничивают выборку.
+type UserFilter struct {
+	EmailPrefix    string    // Без учета регистра
+	UsernamePrefix string    // Без учета регистра
+	CreatedFrom    time.Time // Включительно
+	CreatedTo      time.Time // Не включительно
+	Role           string
+	Verified       *bool // Подтвержден ли email
+}
+
+// UserCursor - позиция в списке: значение поля сортировки (UserSortValue) и id последнего пользователя
+type UserCursor struct {
+	Value string
+	ID    string
+}
+
+// UserListOptions задает страницу списка пользователей
+type UserListOptions struct {
+	Filter UserFilter
+	SortBy string // UserSort*; по умолчанию UserSortCreatedAt
+	Desc   bool
+	After  *UserCursor
+	Limit  int
+}
+
 // postgresUserRepository реализует UserRepository для PostgreSQL.
 type postgresUserRepository struct {
 	db *sql.DB // Пул соединений с базой данных
@@ -39,12 +119,12 @@ func (r *postgresUserRepository) Create(ctx context.Context, user *models.User)
 	user.CreatedAt = time.Now().UTC()
 	user.UpdatedAt = time.Now().UTC()
 
-	query := `INSERT INTO users (id, username, email, password_hash, created_at, updated_at)
-			   VALUES ($1, $2, $3, $4, $5, $6)
-			   RETURNING id, created_at, updated_at`
+	query := `INSERT INTO users (id, username, email, password_hash, roles, created_at, updated_at)
+			   VALUES ($1, $2, $3, $4, $5, $6, $7)
+			   RETURNING id, roles, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query, user.ID, user.Username, user.Email, user.Password, user.CreatedAt, user.UpdatedAt).
-		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, user.ID, user.Username, user.Email, user.Password, pq.Array(user.Roles), user.CreatedAt, user.UpdatedAt).
+		Scan(&user.ID, pq.Array(&user.Roles), &user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
 		return nil, err
@@ -56,86 +136,59 @@ func (r *postgresUserRepository) Create(ctx context.Context, user *models.User)
 
 // GetByID извлекает пользователя из базы данных по его ID.
 func (r *postgresUserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
-	user := &models.User{}
-	query := `SELECT id, username, email, password_hash, created_at, updated_at
+	query := `SELECT ` + userColumns + `
 			   FROM users
 			   WHERE id = $1`
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Password,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return user, nil
+	return scanUser(r.db.QueryRowContext(ctx, query, id))
 }
 
 // GetByEmail извлекает пользователя из базы данных по его email.
 func (r *postgresUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	user := &models.User{}
-	query := `SELECT id, username, email, password_hash, created_at, updated_at
+	query := `SELECT ` + userColumns + `
 			   FROM users
 			   WHERE email = $1`
 
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Username,
-		&user.Email,
-		&user.Password,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return user, nil
+	return scanUser(r.db.QueryRowContext(ctx, query, email))
 }
 
 // Update обновляет существующую запись пользователя в базе данных.
 func (r *postgresUserRepository) Update(ctx context.Context, user *models.User) (*models.User, error) {
 	user.UpdatedAt = time.Now().UTC()
 	query := `UPDATE users
-			   SET username = $1, email = $2, updated_at = $3
-			   WHERE id = $4
-			   RETURNING id, username, email, password_hash, created_at, updated_at`
-
-	updatedUser := &models.User{}
-	err := r.db.QueryRowContext(ctx, query, user.Username, user.Email, user.UpdatedAt, user.ID).Scan(
-		&updatedUser.ID,
-		&updatedUser.Username,
-		&updatedUser.Email,
-		&updatedUser.Password,
-		&updatedUser.CreatedAt,
-		&updatedUser.UpdatedAt,
-	)
+			   SET username = $1, email = $2, email_verified_at = $3, full_name = $4, phone = $5, avatar_url = $6,
+			       date_of_birth = NULLIF($7, '')::DATE, locale = $8, updated_at = $9
+			   WHERE id = $10
+			   RETURNING ` + userColumns
 
+	return scanUser(r.db.QueryRowContext(ctx, query, user.Username, user.Email, user.EmailVerifiedAt,
+		user.FullName, user.Phone, user.AvatarURL, user.DateOfBirth, user.Locale, user.UpdatedAt, user.ID))
+}
+
+// UpdatePassword сохраняет новый хеш пароля пользователя.
+func (r *postgresUserRepository) UpdatePassword(ctx context.Context, id, passwordHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3`,
+		passwordHash, time.Now().UTC(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
+		return err
 	}
-	return updatedUser, nil
-}
 
-// Delete удаляет пользователя из базы данных по его ID.
-func (r *postgresUserRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM users WHERE id = $1`
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+// ReplacePasswordHash заменяет хеш пароля без изменения updated_at: пароль пользователя не меняется.
+func (r *postgresUserRepository) ReplacePasswordHash(ctx context.Context, id, oldHash, newHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = $1 WHERE id = $2 AND password_hash = $3`,
+		newHash, id, oldHash)
 	if err != nil {
 		return err
 	}
@@ -144,33 +197,195 @@ func (r *postgresUserRepository) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-
 	if rowsAffected == 0 {
-		return sql.ErrNoRows // Используем sql.ErrNoRows, чтобы указать, что пользователь не был найден для удаления
+		return sql.ErrNoRows
 	}
-
 	return nil
 }
 
-// Реализация:
-func (r *postgresUserRepository) List(ctx context.Context) ([]*models.User, error) {
-	query := `SELECT id, username, email, password_hash, created_at, updated_at FROM users`
-	rows, err := r.db.QueryContext(ctx, query)
+// ListPage выбирает страницу пользователей по курсору (keyset-пагинация: без OFFSET,
+// вставки новых пользователей не сдвигают страницы).
+func (r *postgresUserRepository) ListPage(ctx context.Context, opts UserListOptions) ([]*models.User, error) {
+	column, ok := userSortColumns[opts.SortBy]
+	if !ok {
+		column = userSortColumns[UserSortCreatedAt]
+	}
+	direction, comparison := "ASC", ">"
+	if opts.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	conditions, args := userFilterConditions(opts.Filter)
+	if opts.After != nil {
+		args = append(args, opts.After.Value, opts.After.ID)
+		valueArg := fmt.Sprintf("$%d", len(args)-1)
+		if column == "created_at" {
+			valueArg += "::TIMESTAMPTZ"
+		}
+		conditions = append(conditions, fmt.Sprintf("(%s, id) %s (%s, $%d::UUID)", column, comparison, valueArg, len(args)))
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, opts.Limit)
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d", column, direction, direction, len(args))
+
+	return r.queryUsers(ctx, query, args...)
+}
+
+// Count считает пользователей по фильтру.
+func (r *postgresUserRepository) Count(ctx context.Context, filter UserFilter) (int64, error) {
+	conditions, args := userFilterConditions(filter)
+	query := `SELECT COUNT(*) FROM users`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&total)
+	return total, err
+}
+
+// userFilterConditions строит условия WHERE для фильтра с параметрами $1, $2, ...
+func userFilterConditions(filter UserFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	// Префиксы сравниваются через lower(...) LIKE, чтобы работали индексы text_pattern_ops
+	if filter.EmailPrefix != "" {
+		addCondition("lower(email) LIKE $%d", likePrefix(filter.EmailPrefix))
+	}
+	if filter.UsernamePrefix != "" {
+		addCondition("lower(username) LIKE $%d", likePrefix(filter.UsernamePrefix))
+	}
+	if !filter.CreatedFrom.IsZero() {
+		addCondition("created_at >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		addCondition("created_at < $%d", filter.CreatedTo)
+	}
+	if filter.Role != "" {
+		addCondition("roles @> ARRAY[$%d]::TEXT[]", filter.Role)
+	}
+	if filter.Verified != nil {
+		if *filter.Verified {
+			conditions = append(conditions, "email_verified_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "email_verified_at IS NULL")
+		}
+	}
+	return conditions, args
+}
+
+// likePrefix возвращает шаблон LIKE для поиска по префиксу с экранированными спецсимволами
+func likePrefix(prefix string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(prefix))
+	return escaped + "%"
+}
+
+// GetByIDs выбирает пользователей по списку ID.
+func (r *postgresUserRepository) GetByIDs(ctx context.Context, ids []string) ([]*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ANY($1::UUID[])`
+	return r.queryUsers(ctx, query, pq.Array(ids))
+}
+
+// queryUsers выполняет запрос и сканирует всех пользователей из результата
+func (r *postgresUserRepository) queryUsers(ctx context.Context, query string, args ...interface{}) ([]*models.User, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []*models.User
+	users := []*models.User{}
 	for rows.Next() {
-		var u models.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.Email, &u.Password, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
-		users = append(users, &u)
+		users = append(users, u)
 	}
+	return users, rows.Err()
+}
+
+// AddRole добавляет роль пользователю, если ее еще нет.
+func (r *postgresUserRepository) AddRole(ctx context.Context, id, role string) (*models.User, error) {
+	query := `UPDATE users
+			   SET roles = CASE WHEN $2 = ANY(roles) THEN roles ELSE array_append(roles, $2) END,
+			       updated_at = $3
+			   WHERE id = $1
+			   RETURNING ` + userColumns
+	return r.updateRoles(ctx, query, id, role)
+}
+
+// RemoveRole отзывает роль у пользователя.
+func (r *postgresUserRepository) RemoveRole(ctx context.Context, id, role string) (*models.User, error) {
+	query := `UPDATE users
+			   SET roles = array_remove(roles, $2), updated_at = $3
+			   WHERE id = $1
+			   RETURNING ` + userColumns
+	return r.updateRoles(ctx, query, id, role)
+}
+
+// updateRoles выполняет запрос изменения ролей и возвращает обновленного пользователя.
+func (r *postgresUserRepository) updateRoles(ctx context.Context, query, id, role string) (*models.User, error) {
+	return scanUser(r.db.QueryRowContext(ctx, query, id, role, time.Now().UTC()))
+}
+
+// MarkEmailVerified отмечает email подтвержденным, если он не изменился с момента выдачи токена.
+func (r *postgresUserRepository) MarkEmailVerified(ctx context.Context, id, email string) (*models.User, error) {
+	query := `UPDATE users
+			   SET email_verified_at = COALESCE(email_verified_at, $3), updated_at = $3
+			   WHERE id = $1 AND email = $2
+			   RETURNING ` + userColumns
+	return scanUser(r.db.QueryRowContext(ctx, query, id, email, time.Now().UTC()))
+}
+
+// userColumns - столбцы таблицы users в порядке, ожидаемом scanUser
+const userColumns = `id, username, email, password_hash, roles, email_verified_at,
+	full_name, phone, avatar_url, COALESCE(to_char(date_of_birth, 'YYYY-MM-DD'), ''), locale,
+	deactivated_at, deleted_at, anonymized_at, created_at, updated_at`
+
+// rowScanner - общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	return users, nil
+// scanUser читает пользователя из строки результата со столбцами userColumns.
+// Возвращает nil, nil, если строка не найдена.
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		pq.Array(&user.Roles),
+		&user.EmailVerifiedAt,
+		&user.FullName,
+		&user.Phone,
+		&user.AvatarURL,
+		&user.DateOfBirth,
+		&user.Locale,
+		&user.DeactivatedAt,
+		&user.DeletedAt,
+		&user.AnonymizedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user, nil
 }
 
 // Пример схемы таблицы 'users' для PostgreSQL:
@@ -180,6 +395,17 @@ CREATE TABLE IF NOT EXISTS users (
     username VARCHAR(100) NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
+    roles TEXT[] NOT NULL DEFAULT ARRAY['CUSTOMER']::TEXT[],
+    email_verified_at TIMESTAMP WITH TIME ZONE,
+    full_name VARCHAR(200) NOT NULL DEFAULT '',
+    phone VARCHAR(16) NOT NULL DEFAULT '',
+    avatar_url VARCHAR(2048) NOT NULL DEFAULT '',
+    date_of_birth DATE,
+    locale VARCHAR(35) NOT NULL DEFAULT '',
+    deactivated_at TIMESTAMP WITH TIME ZONE,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    anonymized_at TIMESTAMP WITH TIME ZONE,
+    erasure_notified_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+)
+
+var (
+	ErrAccountDeactivated    = errors.New("учетная запись деактивирована")
+	ErrAccountDeleted        = errors.New("учетная запись удалена")
+	ErrAccountErased         = errors.New("данные учетной записи уже обезличены")
+	ErrAccountNotDeactivated = errors.New("учетная запись не деактивирована")
+	ErrAccountNotDeleted     = errors.New("учетная запись не удалена")
+	ErrOrdersUnavailable     = errors.New("не удалось получить заказы из order-service")
+)
+
+// OrderService - данные пользователя в order-service
+type OrderService interface {
+	// UserOrders возвращает заказы пользователя в формате ответа order-service
+	UserOrders(ctx context.Context, userID string) (json.RawMessage, error)
+	// EraseUser просит order-service обезличить заказы пользователя
+	EraseUser(ctx context.Context, userID string) error
+}
+
+// AccountConfig задает параметры удаления учетных записей
+type AccountConfig struct {
+	// DeletionGracePeriod - сколько удаленная учетная запись ждет обезличивания;
+	// в этот срок администратор может отменить удаление
+	DeletionGracePeriod time.Duration
+	// PurgeBatchSize - сколько учетных записей обрабатывается за один проход
+	PurgeBatchSize int
+}
+
+// UserExport - выгрузка данных пользователя
+type UserExport struct {
+	ExportedAt       time.Time                  `json:"exported_at"`
+	User             *models.User               `json:"user"`
+	Addresses        []*models.Address          `json:"addresses"`
+	APIKeys          []*models.APIKey           `json:"api_keys"`   // Без самих ключей и их хешей
+	Identities       []*models.ExternalIdentity `json:"identities"` // Привязанные учетные записи провайдеров
+	Consents         []*models.OAuthConsent     `json:"consents"`   // Согласия, выданные клиентам OAuth2
+	TwoFactorEnabled bool                       `json:"two_factor_enabled"`
+	AuthEvents       []*models.AuthEvent        `json:"auth_events"`
+	Orders           json.RawMessage            `json:"orders"` // Заказы в формате order-service
+}
+
+// AccountUsecase определяет интерфейс бизнес-логики жизненного цикла учетной записи.
+// actorID - пользователь, выполняющий действие (записывается в журнал аутентификации).
+type AccountUsecase interface {
+	// DeactivateUser запрещает вход и отзывает токены обновления; данные сохраняются
+	DeactivateUser(ctx context.Context, id, actorID string) (*models.User, error)
+	// ReactivateUser снимает деактивацию
+	ReactivateUser(ctx context.Context, id, actorID string) (*models.User, error)
+	// DeleteUser отмечает учетную запись удаленной и отзывает токены обновления.
+	// Персональные данные обезличиваются через AccountConfig.DeletionGracePeriod.
+	DeleteUser(ctx context.Context, id, actorID string) (*models.User, error)
+	// RestoreUser отменяет удаление, пока данные не обезличены
+	RestoreUser(ctx context.Context, id, actorID string) (*models.User, error)
+	// ExportUserData собирает данные пользователя вместе с заказами из order-service
+	ExportUserData(ctx context.Context, id string) (*UserExport, error)
+	// PurgeDeletedUsers обезличивает учетные записи с истекшим сроком ожидания
+	// и сообщает об этом order-service. Возвращает количество обезличенных записей.
+	PurgeDeletedUsers(ctx context.Context) (int, error)
+}
+
+type accountUsecase struct {
+	userRepo      repository.UserRepository
+	accountRepo   repository.AccountRepository
+	addressRepo   repository.AddressRepository
+	apiKeyRepo    repository.APIKeyRepository
+	identityRepo  repository.ExternalIdentityRepository
+	oauthRepo     repository.OAuthRepository
+	tokenRepo     repository.TokenRepository
+	twoFactorRepo repository.TwoFactorRepository
+	audit         repository.AuditRepository
+	orders        OrderService
+	config        AccountConfig
+}
+
+// NewAccountUsecase создает новый экземпляр accountUsecase.
+func NewAccountUsecase(userRepo repository.UserRepository, accountRepo repository.AccountRepository, addressRepo repository.AddressRepository,
+	apiKeyRepo repository.APIKeyRepository, identityRepo repository.ExternalIdentityRepository, oauthRepo repository.OAuthRepository,
+	tokenRepo repository.TokenRepository, twoFactorRepo repository.TwoFactorRepository, audit repository.AuditRepository, orders OrderService, config AccountConfig) AccountUsecase {
+	return &accountUsecase{
+		userRepo:      userRepo,
+		accountRepo:   accountRepo,
+		addressRepo:   addressRepo,
+		apiKeyRepo:    apiKeyRepo,
+		identityRepo:  identityRepo,
+		oauthRepo:     oauthRepo,
+		tokenRepo:     tokenRepo,
+		twoFactorRepo: twoFactorRepo,
+		audit:         audit,
+		orders:        orders,
+		config:        config,
+	}
+}
+
+// accountStatusError возвращает причину, по которой пользователь не может войти, или nil
+func accountStatusError(user *models.User) error {
+	switch {
+	case user.DeletedAt != nil:
+		return ErrAccountDeleted
+	case user.DeactivatedAt != nil:
+		return ErrAccountDeactivated
+	}
+	return nil
+}
+
+// DeactivateUser деактивирует учетную запись. Повторная деактивация не меняет ее время.
+func (a *accountUsecase) DeactivateUser(ctx context.Context, id, actorID string) (*models.User, error) {
+	return a.changeState(ctx, id, actorID, models.AuthEventAccountDeactivated, true, func(user *models.User) (*models.User, error) {
+		if user.DeactivatedAt != nil {
+			return user, nil
+		}
+		now := time.Now().UTC()
+		return a.accountRepo.SetDeactivated(ctx, id, &now)
+	})
+}
+
+// ReactivateUser снимает деактивацию.
+func (a *accountUsecase) ReactivateUser(ctx context.Context, id, actorID string) (*models.User, error) {
+	return a.changeState(ctx, id, actorID, models.AuthEventAccountReactivated, false, func(user *models.User) (*models.User, error) {
+		if user.DeactivatedAt == nil {
+			return nil, ErrAccountNotDeactivated
+		}
+		return a.accountRepo.SetDeactivated(ctx, id, nil)
+	})
+}
+
+// DeleteUser отмечает учетную запись удаленной. Повторное удаление не продлевает срок ожидания.
+func (a *accountUsecase) DeleteUser(ctx context.Context, id, actorID string) (*models.User, error) {
+	return a.changeState(ctx, id, actorID, models.AuthEventAccountDeleted, true, func(user *models.User) (*models.User, error) {
+		return a.accountRepo.MarkDeleted(ctx, id, time.Now().UTC())
+	})
+}
+
+// RestoreUser отменяет удаление учетной записи.
+func (a *accountUsecase) RestoreUser(ctx context.Context, id, actorID string) (*models.User, error) {
+	return a.changeState(ctx, id, actorID, models.AuthEventAccountRestored, false, func(user *models.User) (*models.User, error) {
+		if user.DeletedAt == nil {
+			return nil, ErrAccountNotDeleted
+		}
+		return a.accountRepo.Restore(ctx, id)
+	})
+}
+
+// changeState проверяет, что пользователь существует и не обезличен, применяет change,
+// при revokeTokens отзывает токены обновления и записывает событие в журнал.
+// change возвращает пользователя в новом состоянии или nil, если его успели обезличить.
+func (a *accountUsecase) changeState(ctx context.Context, id, actorID, event string, revokeTokens bool, change func(user *models.User) (*models.User, error)) (*models.User, error) {
+	user, err := a.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.AnonymizedAt != nil {
+		return nil, ErrAccountErased
+	}
+
+	updated, err := change(user)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrAccountErased
+	}
+	if revokeTokens {
+		if err := a.tokenRepo.RevokeUserTokens(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	a.record(ctx, &models.AuthEvent{Event: event, UserID: id, Email: user.Email, Details: "инициатор " + actorID})
+
+	updated.Password = ""
+	return updated, nil
+}
+
+// ExportUserData собирает профиль, адреса, API-ключи, привязки к провайдерам, состояние 2FA, журнал аутентификации и заказы пользователя.
+// Заказы запрашиваются у order-service от имени пользователя, токен которого передан в контексте.
+func (a *accountUsecase) ExportUserData(ctx context.Context, id string) (*UserExport, error) {
+	user, err := a.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.AnonymizedAt != nil {
+		return nil, ErrAccountErased
+	}
+	user.Password = ""
+
+	addresses, err := a.addressRepo.ListAddresses(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	apiKeys, err := a.apiKeyRepo.ListAPIKeys(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := a.identityRepo.ListIdentities(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	consents, err := a.oauthRepo.ListConsents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	twoFactor, err := a.twoFactorRepo.GetTwoFactor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := a.audit.ListAuthEvents(ctx, repository.AuthEventFilter{UserID: id, Limit: repository.MaxAuthEventsLimit})
+	if err != nil {
+		return nil, err
+	}
+	orders, err := a.orders.UserOrders(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOrdersUnavailable, err)
+	}
+
+	return &UserExport{
+		ExportedAt:       time.Now().UTC(),
+		User:             user,
+		Addresses:        addresses,
+		APIKeys:          apiKeys,
+		Identities:       identities,
+		Consents:         consents,
+		TwoFactorEnabled: twoFactor.Enabled(),
+		AuthEvents:       events,
+		Orders:           orders,
+	}, nil
+}
+
+// PurgeDeletedUsers обезличивает учетные записи, удаленные раньше DeletionGracePeriod,
+// и сообщает order-service обо всех обезличенных записях, о которых он еще не знает.
+// Если order-service недоступен, уведомление повторяется при следующем запуске.
+func (a *accountUsecase) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	ids, err := a.accountRepo.ListDeletedBefore(ctx, time.Now().Add(-a.config.DeletionGracePeriod), a.config.PurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, id := range ids {
+		if err := a.accountRepo.Anonymize(ctx, id); err != nil {
+			// Удаление успели отменить
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return erased, err
+		}
+		a.record(ctx, &models.AuthEvent{Event: models.AuthEventAccountErased, UserID: id})
+		erased++
+	}
+
+	return erased, a.notifyErasures(ctx)
+}
+
+// notifyErasures передает order-service обезличенные учетные записи, пока он отвечает успешно
+func (a *accountUsecase) notifyErasures(ctx context.Context) error {
+	ids, err := a.accountRepo.ListUnnotifiedErasures(ctx, a.config.PurgeBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := a.orders.EraseUser(ctx, id); err != nil {
+			return fmt.Errorf("не удалось сообщить order-service об обезличивании пользователя %s: %w", id, err)
+		}
+		if err := a.accountRepo.MarkErasureNotified(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record сохраняет событие журнала. Ошибка журнала не отменяет уже выполненное действие, поэтому только логируется.
+func (a *accountUsecase) record(ctx context.Context, event *models.AuthEvent) {
+	if err := a.audit.RecordAuthEvent(ctx, event); err != nil {
+		log.Printf("Не удалось записать событие %s в журнал аутентификации: %v", event.Event, err)
+	}
+}
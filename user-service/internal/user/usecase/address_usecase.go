@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+)
+
+var (
+	ErrAddressNotFound  = errors.New("адрес не найден")
+	ErrTooManyAddresses = errors.New("достигнуто максимальное количество адресов")
+)
+
+// MaxAddresses - сколько адресов можно сохранить в адресной книге одного пользователя
+const MaxAddresses = 20
+
+// countryPattern - код страны ISO 3166-1 alpha-2
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// AddressInput - поля адреса при создании и изменении
+type AddressInput struct {
+	Label      string
+	Recipient  string
+	Phone      string
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
+	// Default делает адрес адресом по умолчанию. При изменении адреса false
+	// не снимает признак: адрес по умолчанию меняется выбором другого адреса.
+	Default bool
+}
+
+// AddressUsecase определяет интерфейс бизнес-логики адресной книги пользователя.
+type AddressUsecase interface {
+	// ListAddresses возвращает адреса пользователя, первым - адрес по умолчанию
+	ListAddresses(ctx context.Context, userID string) ([]*models.Address, error)
+	GetAddress(ctx context.Context, userID, id string) (*models.Address, error)
+	// CreateAddress добавляет адрес; первый адрес пользователя становится адресом по умолчанию
+	CreateAddress(ctx context.Context, userID string, input AddressInput) (*models.Address, error)
+	UpdateAddress(ctx context.Context, userID, id string, input AddressInput) (*models.Address, error)
+	// DeleteAddress удаляет адрес; вместо удаленного адреса по умолчанию назначается самый новый из оставшихся
+	DeleteAddress(ctx context.Context, userID, id string) error
+	SetDefaultAddress(ctx context.Context, userID, id string) (*models.Address, error)
+}
+
+type addressUsecase struct {
+	userRepo    repository.UserRepository
+	addressRepo repository.AddressRepository
+}
+
+// NewAddressUsecase создает новый экземпляр addressUsecase.
+func NewAddressUsecase(userRepo repository.UserRepository, addressRepo repository.AddressRepository) AddressUsecase {
+	return &addressUsecase{userRepo: userRepo, addressRepo: addressRepo}
+}
+
+// ListAddresses возвращает адресную книгу пользователя.
+func (a *addressUsecase) ListAddresses(ctx context.Context, userID string) ([]*models.Address, error) {
+	if err := a.requireUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return a.addressRepo.ListAddresses(ctx, userID)
+}
+
+// GetAddress возвращает адрес пользователя по ID.
+func (a *addressUsecase) GetAddress(ctx context.Context, userID, id string) (*models.Address, error) {
+	address, err := a.addressRepo.GetAddress(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil {
+		return nil, ErrAddressNotFound
+	}
+	return address, nil
+}
+
+// CreateAddress проверяет и сохраняет новый адрес.
+func (a *addressUsecase) CreateAddress(ctx context.Context, userID string, input AddressInput) (*models.Address, error) {
+	if err := a.requireUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	address, err := newAddress(userID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := a.addressRepo.CreateAddress(ctx, address, MaxAddresses)
+	if err != nil {
+		if errors.Is(err, repository.ErrAddressLimit) {
+			return nil, ErrTooManyAddresses
+		}
+		return nil, err
+	}
+	return created, nil
+}
+
+// UpdateAddress проверяет и сохраняет поля адреса, а при input.Default делает его адресом по умолчанию.
+func (a *addressUsecase) UpdateAddress(ctx context.Context, userID, id string, input AddressInput) (*models.Address, error) {
+	address, err := newAddress(userID, input)
+	if err != nil {
+		return nil, err
+	}
+	address.ID = id
+
+	updated, err := a.addressRepo.UpdateAddress(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, ErrAddressNotFound
+	}
+	if input.Default && !updated.IsDefault {
+		return a.SetDefaultAddress(ctx, userID, id)
+	}
+	return updated, nil
+}
+
+// DeleteAddress удаляет адрес пользователя.
+func (a *addressUsecase) DeleteAddress(ctx context.Context, userID, id string) error {
+	if err := a.addressRepo.DeleteAddress(ctx, userID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAddressNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// SetDefaultAddress делает адрес адресом по умолчанию.
+func (a *addressUsecase) SetDefaultAddress(ctx context.Context, userID, id string) (*models.Address, error) {
+	address, err := a.addressRepo.SetDefaultAddress(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil {
+		return nil, ErrAddressNotFound
+	}
+	return address, nil
+}
+
+// requireUser проверяет, что пользователь существует и его данные не обезличены
+func (a *addressUsecase) requireUser(ctx context.Context, userID string) error {
+	user, err := a.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if user.AnonymizedAt != nil {
+		return ErrAccountErased
+	}
+	return nil
+}
+
+// newAddress проверяет поля адреса и собирает модель.
+// Ошибки проверки оборачивают ErrInvalidProfile.
+func newAddress(userID string, input AddressInput) (*models.Address, error) {
+	address := &models.Address{
+		UserID:     userID,
+		Label:      strings.TrimSpace(input.Label),
+		Line2:      strings.TrimSpace(input.Line2),
+		Region:     strings.TrimSpace(input.Region),
+		PostalCode: strings.ToUpper(strings.TrimSpace(input.PostalCode)),
+		Country:    strings.ToUpper(strings.TrimSpace(input.Country)),
+		IsDefault:  input.Default,
+	}
+
+	var err error
+	if address.Recipient, err = normalizeFullName("recipient", input.Recipient); err != nil {
+		return nil, err
+	}
+	if address.Phone, err = normalizePhone("phone", input.Phone); err != nil {
+		return nil, err
+	}
+	address.Line1 = strings.TrimSpace(input.Line1)
+	address.City = strings.TrimSpace(input.City)
+
+	for _, field := range []struct {
+		name     string
+		value    string
+		required bool
+		max      int
+	}{
+		{"label", address.Label, false, 50},
+		{"recipient", address.Recipient, true, 200},
+		{"line1", address.Line1, true, 200},
+		{"line2", address.Line2, false, 200},
+		{"city", address.City, true, 100},
+		{"region", address.Region, false, 100},
+		{"postal_code", address.PostalCode, true, 20},
+	} {
+		if field.required && field.value == "" {
+			return nil, profileError(field.name, "обязательное поле")
+		}
+		if utf8.RuneCountInString(field.value) > field.max {
+			return nil, profileError(field.name, fmt.Sprintf("не длиннее %d символов", field.max))
+		}
+	}
+	if !countryPattern.MatchString(address.Country) {
+		return nil, profileError("country", "ожидается код страны ISO 3166-1 alpha-2, например RU")
+	}
+	return address, nil
+}
@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+)
+
+var (
+	ErrAPIKeyNotFound     = errors.New("API-ключ не найден")
+	ErrTooManyAPIKeys     = errors.New("достигнуто максимальное количество действующих API-ключей")
+	ErrInvalidAPIKeyInput = errors.New("некорректные параметры API-ключа")
+)
+
+// apiKeyPrefixLength - сколько символов ключа (вместе с префиксом "tk_") хранится открыто для списка ключей
+const apiKeyPrefixLength = len(auth.APIKeyPrefix) + 8
+
+// maxAPIKeyNameLength - максимальная длина названия ключа
+const maxAPIKeyNameLength = 100
+
+// APIKeyConfig задает ограничения API-ключей
+type APIKeyConfig struct {
+	DefaultRateLimit int // Лимит запросов в минуту, если при создании он не указан
+	MaxRateLimit     int // Наибольший допустимый лимит запросов в минуту
+	MaxKeysPerUser   int // Сколько действующих ключей может быть у одного пользователя
+	// RequireTwoFactorForAdmin - ключ получает роль ADMIN владельца, только если у него включена 2FA
+	// (та же политика, что и для токенов доступа)
+	RequireTwoFactorForAdmin bool
+}
+
+// APIKeyInput - параметры нового ключа
+type APIKeyInput struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time // nil - бессрочный ключ
+	RateLimit int        // 0 - APIKeyConfig.DefaultRateLimit
+}
+
+// APIKeyUsecase определяет интерфейс бизнес-логики API-ключей.
+// Реализует auth.APIKeySource, поэтому user-service проверяет ключи без обращения по сети.
+type APIKeyUsecase interface {
+	// CreateAPIKey выдает ключ пользователю userID и возвращает его вместе с самим ключом.
+	// Ключ показывается только здесь: хранится лишь его хеш.
+	CreateAPIKey(ctx context.Context, userID, actorID string, input APIKeyInput) (*models.APIKey, string, error)
+	// ListAPIKeys возвращает ключи пользователя, включая отозванные и истекшие
+	ListAPIKeys(ctx context.Context, userID string) ([]*models.APIKey, error)
+	// RevokeAPIKey отзывает ключ. В других сервисах отзыв вступает в силу после истечения их кеша.
+	RevokeAPIKey(ctx context.Context, userID, id, actorID string) error
+	// LookupAPIKey проверяет ключ, отмечает его использование и возвращает сведения для других сервисов.
+	// Для неизвестного, отозванного или истекшего ключа, а также ключа неактивного пользователя
+	// возвращает auth.ErrInvalidAPIKey.
+	LookupAPIKey(ctx context.Context, key string) (*auth.APIKeyInfo, error)
+}
+
+type apiKeyUsecase struct {
+	userRepo      repository.UserRepository
+	apiKeyRepo    repository.APIKeyRepository
+	twoFactorRepo repository.TwoFactorRepository
+	audit         repository.AuditRepository
+	config        APIKeyConfig
+}
+
+// NewAPIKeyUsecase создает новый экземпляр apiKeyUsecase.
+func NewAPIKeyUsecase(userRepo repository.UserRepository, apiKeyRepo repository.APIKeyRepository,
+	twoFactorRepo repository.TwoFactorRepository, audit repository.AuditRepository, config APIKeyConfig) APIKeyUsecase {
+	return &apiKeyUsecase{
+		userRepo:      userRepo,
+		apiKeyRepo:    apiKeyRepo,
+		twoFactorRepo: twoFactorRepo,
+		audit:         audit,
+		config:        config,
+	}
+}
+
+// CreateAPIKey проверяет параметры, генерирует ключ и сохраняет его хеш.
+func (a *apiKeyUsecase) CreateAPIKey(ctx context.Context, userID, actorID string, input APIKeyInput) (*models.APIKey, string, error) {
+	user, err := a.activeUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey, err := a.newAPIKey(userID, input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	key := auth.APIKeyPrefix + secret
+	apiKey.Prefix = key[:apiKeyPrefixLength]
+	apiKey.KeyHash = hashToken(key)
+
+	created, err := a.apiKeyRepo.CreateAPIKey(ctx, apiKey, a.config.MaxKeysPerUser)
+	if err != nil {
+		if errors.Is(err, repository.ErrAPIKeyLimit) {
+			return nil, "", ErrTooManyAPIKeys
+		}
+		return nil, "", err
+	}
+
+	a.record(ctx, &models.AuthEvent{
+		Event:   models.AuthEventAPIKeyCreated,
+		UserID:  userID,
+		Email:   user.Email,
+		Details: fmt.Sprintf("ключ %s (%s), инициатор %s", created.Prefix, strings.Join(created.Scopes, " "), actorID),
+	})
+	return created, key, nil
+}
+
+// newAPIKey проверяет параметры ключа и собирает модель без самого ключа.
+// Ошибки проверки оборачивают ErrInvalidAPIKeyInput.
+func (a *apiKeyUsecase) newAPIKey(userID string, input APIKeyInput) (*models.APIKey, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, fmt.Errorf("%w: name: от 1 до %d символов", ErrInvalidAPIKeyInput, maxAPIKeyNameLength)
+	}
+
+	if len(input.Scopes) == 0 {
+		return nil, fmt.Errorf("%w: scopes: укажите хотя бы одно разрешение (%s)", ErrInvalidAPIKeyInput, strings.Join(auth.Scopes, ", "))
+	}
+	scopes := make([]string, 0, len(input.Scopes))
+	for _, raw := range input.Scopes {
+		scope, ok := auth.NormalizeScope(raw)
+		if !ok {
+			return nil, fmt.Errorf("%w: scopes: неизвестное разрешение %q", ErrInvalidAPIKeyInput, raw)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	rateLimit := input.RateLimit
+	if rateLimit == 0 {
+		rateLimit = a.config.DefaultRateLimit
+	}
+	if rateLimit < 1 || rateLimit > a.config.MaxRateLimit {
+		return nil, fmt.Errorf("%w: rate_limit: от 1 до %d запросов в минуту", ErrInvalidAPIKeyInput, a.config.MaxRateLimit)
+	}
+
+	var expiresAt *time.Time
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("%w: expires_at: срок действия должен быть в будущем", ErrInvalidAPIKeyInput)
+		}
+		utc := input.ExpiresAt.UTC()
+		expiresAt = &utc
+	}
+
+	return &models.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ListAPIKeys возвращает ключи пользователя без хешей.
+func (a *apiKeyUsecase) ListAPIKeys(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	user, err := a.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return a.apiKeyRepo.ListAPIKeys(ctx, userID)
+}
+
+// RevokeAPIKey отзывает ключ пользователя.
+func (a *apiKeyUsecase) RevokeAPIKey(ctx context.Context, userID, id, actorID string) error {
+	revoked, err := a.apiKeyRepo.RevokeAPIKey(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	a.record(ctx, &models.AuthEvent{
+		Event:   models.AuthEventAPIKeyRevoked,
+		UserID:  userID,
+		Details: fmt.Sprintf("ключ %s, инициатор %s", revoked.Prefix, actorID),
+	})
+	return nil
+}
+
+// LookupAPIKey находит ключ по хешу и собирает сведения о нем с актуальными ролями владельца.
+func (a *apiKeyUsecase) LookupAPIKey(ctx context.Context, key string) (*auth.APIKeyInfo, error) {
+	if !strings.HasPrefix(key, auth.APIKeyPrefix) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	apiKey, err := a.apiKeyRepo.GetAPIKeyByHash(ctx, hashToken(key))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if apiKey == nil || !apiKey.Valid(now) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	user, err := a.userRepo.GetByID(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.Active() {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	roles, _, err := effectiveRoles(ctx, a.twoFactorRepo, a.config.RequireTwoFactorForAdmin, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Отметка использования не должна мешать запросу
+	if err := a.apiKeyRepo.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+		log.Printf("Не удалось отметить использование API-ключа %s: %v", apiKey.Prefix, err)
+	}
+
+	return &auth.APIKeyInfo{
+		ID:        apiKey.ID,
+		UserID:    apiKey.UserID,
+		Roles:     roles,
+		Scopes:    apiKey.Scopes,
+		RateLimit: apiKey.RateLimit,
+		ExpiresAt: apiKey.ExpiresAt,
+	}, nil
+}
+
+// activeUser возвращает пользователя, которому можно выдать ключ
+func (a *apiKeyUsecase) activeUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := a.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.AnonymizedAt != nil {
+		return nil, ErrAccountErased
+	}
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// record сохраняет событие журнала. Ошибка журнала не отменяет уже выполненное действие, поэтому только логируется.
+func (a *apiKeyUsecase) record(ctx context.Context, event *models.AuthEvent) {
+	if err := a.audit.RecordAuthEvent(ctx, event); err != nil {
+		log.Printf("Не удалось записать событие %s в журнал аутентификации: %v", event.Event, err)
+	}
+}
@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+)
+
+// testAPIKeyConfig - ограничения API-ключей для тестов
+func testAPIKeyConfig() APIKeyConfig {
+	return APIKeyConfig{DefaultRateLimit: 60, MaxRateLimit: 600, MaxKeysPerUser: 2, RequireTwoFactorForAdmin: true}
+}
+
+// newTestAPIKeyUsecase создает apiKeyUsecase с хранилищами в памяти
+func newTestAPIKeyUsecase(users ...*models.User) (APIKeyUsecase, *fakeAPIKeyRepository, *fakeTwoFactorRepository) {
+	keys := newFakeAPIKeyRepository()
+	twoFactors := newFakeTwoFactorRepository()
+	uc := NewAPIKeyUsecase(newFakeUserRepository(users...), keys, twoFactors, &fakeAuditRepository{}, testAPIKeyConfig())
+	return uc, keys, twoFactors
+}
+
+func TestCreateAPIKeyStoresOnlyHash(t *testing.T) {
+	ctx := context.Background()
+	uc, keys, _ := newTestAPIKeyUsecase(&models.User{ID: "user-1", Email: "pos@example.com", Roles: []string{auth.RoleStaff}})
+
+	created, key, err := uc.CreateAPIKey(ctx, "user-1", "user-1", APIKeyInput{Name: " Касса 1 ", Scopes: []string{"Orders:Write", "orders:write", " products:read"}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if !strings.HasPrefix(key, auth.APIKeyPrefix) || !strings.HasPrefix(key, created.Prefix) || len(created.Prefix) != apiKeyPrefixLength {
+		t.Fatalf("ключ %q не соответствует префиксу %q", key, created.Prefix)
+	}
+
+	stored := keys.stored(created.ID)
+	if stored.KeyHash != hashToken(key) || strings.Contains(stored.KeyHash, key) {
+		t.Fatalf("сохранен не SHA-256 ключа: %q", stored.KeyHash)
+	}
+	if stored.Name != "Касса 1" || stored.RateLimit != 60 {
+		t.Fatalf("название %q и лимит %d, ожидались \"Касса 1\" и 60 по умолчанию", stored.Name, stored.RateLimit)
+	}
+	if want := []string{auth.ScopeOrdersWrite, auth.ScopeProductsRead}; strings.Join(stored.Scopes, " ") != strings.Join(want, " ") {
+		t.Fatalf("разрешения %v, ожидались %v", stored.Scopes, want)
+	}
+
+	info, err := uc.LookupAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("LookupAPIKey: %v", err)
+	}
+	if info.ID != created.ID || info.UserID != "user-1" || info.RateLimit != 60 {
+		t.Fatalf("неожиданные сведения о ключе: %+v", info)
+	}
+	if _, ok := keys.lastUsed(created.ID); !ok {
+		t.Fatalf("использование ключа не отмечено")
+	}
+
+	// Ключ ограничен своими разрешениями, даже если у владельца есть роль
+	claims := info.Claims()
+	if !claims.HasScope(auth.ScopeOrdersWrite) || claims.HasScope(auth.ScopeUsersRead) {
+		t.Fatalf("разрешения ключа: %v", claims.Scopes)
+	}
+	if !claims.AllowsService(auth.ScopeOrdersWrite, auth.RoleStaff) || claims.AllowsService(auth.ScopeInventoryWrite, auth.RoleStaff) {
+		t.Fatalf("ключ STAFF без inventory:write получил доступ к резервам")
+	}
+}
+
+func TestCreateAPIKeyValidatesInput(t *testing.T) {
+	ctx := context.Background()
+	uc, _, _ := newTestAPIKeyUsecase(&models.User{ID: "user-1", Email: "pos@example.com"})
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		input APIKeyInput
+	}{
+		{"без названия", APIKeyInput{Name: "  ", Scopes: []string{auth.ScopeOrdersRead}}},
+		{"длинное название", APIKeyInput{Name: strings.Repeat("я", maxAPIKeyNameLength+1), Scopes: []string{auth.ScopeOrdersRead}}},
+		{"без разрешений", APIKeyInput{Name: "ключ"}},
+		{"неизвестное разрешение", APIKeyInput{Name: "ключ", Scopes: []string{auth.ScopeOrdersRead, "orders:delete"}}},
+		{"отрицательный лимит", APIKeyInput{Name: "ключ", Scopes: []string{auth.ScopeOrdersRead}, RateLimit: -1}},
+		{"лимит больше допустимого", APIKeyInput{Name: "ключ", Scopes: []string{auth.ScopeOrdersRead}, RateLimit: 601}},
+		{"срок в прошлом", APIKeyInput{Name: "ключ", Scopes: []string{auth.ScopeOrdersRead}, ExpiresAt: &past}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := uc.CreateAPIKey(ctx, "user-1", "user-1", tt.input); !errors.Is(err, ErrInvalidAPIKeyInput) {
+				t.Fatalf("ожидалась ErrInvalidAPIKeyInput, получено %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyLimitAndAccountState(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	uc, _, _ := newTestAPIKeyUsecase(
+		&models.User{ID: "user-1", Email: "pos@example.com"},
+		&models.User{ID: "user-2", Email: "gone@example.com", DeactivatedAt: &now},
+	)
+	input := APIKeyInput{Name: "ключ", Scopes: []string{auth.ScopeOrdersRead}}
+
+	var first *models.APIKey
+	for i := 0; i < 2; i++ {
+		created, _, err := uc.CreateAPIKey(ctx, "user-1", "user-1", input)
+		if err != nil {
+			t.Fatalf("CreateAPIKey %d: %v", i+1, err)
+		}
+		if first == nil {
+			first = created
+		}
+	}
+	if _, _, err := uc.CreateAPIKey(ctx, "user-1", "user-1", input); !errors.Is(err, ErrTooManyAPIKeys) {
+		t.Fatalf("ключ сверх лимита: ожидалась ErrTooManyAPIKeys, получено %v", err)
+	}
+	// Отозванный ключ освобождает место
+	if err := uc.RevokeAPIKey(ctx, "user-1", first.ID, "user-1"); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if _, _, err := uc.CreateAPIKey(ctx, "user-1", "user-1", input); err != nil {
+		t.Fatalf("CreateAPIKey после отзыва: %v", err)
+	}
+
+	if _, _, err := uc.CreateAPIKey(ctx, "user-2", "user-2", input); !errors.Is(err, ErrAccountDeactivated) {
+		t.Fatalf("деактивированный пользователь: ожидалась ErrAccountDeactivated, получено %v", err)
+	}
+	if _, _, err := uc.CreateAPIKey(ctx, "user-3", "user-3", input); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("неизвестный пользователь: ожидалась ErrUserNotFound, получено %v", err)
+	}
+}
+
+func TestLookupAPIKeyRejectsInvalidKeys(t *testing.T) {
+	ctx := context.Background()
+	user := &models.User{ID: "user-1", Email: "pos@example.com"}
+	uc, keys, _ := newTestAPIKeyUsecase(user)
+	input := APIKeyInput{Name: "ключ", Scopes: []string{auth.ScopeOrdersRead}}
+
+	revoked, revokedKey, err := uc.CreateAPIKey(ctx, "user-1", "user-1", input)
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if err := uc.RevokeAPIKey(ctx, "user-1", revoked.ID, "user-1"); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if err := uc.RevokeAPIKey(ctx, "user-1", revoked.ID, "user-1"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Fatalf("повторный отзыв: ожидалась ErrAPIKeyNotFound, получено %v", err)
+	}
+
+	expired, expiredKey, err := uc.CreateAPIKey(ctx, "user-1", "user-1", input)
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	keys.expire(expired.ID)
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"отозванный ключ", revokedKey},
+		{"истекший ключ", expiredKey},
+		{"неизвестный ключ", auth.APIKeyPrefix + "unknown"},
+		{"без префикса", strings.TrimPrefix(revokedKey, auth.APIKeyPrefix)},
+		{"пустой ключ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.LookupAPIKey(ctx, tt.key); !errors.Is(err, auth.ErrInvalidAPIKey) {
+				t.Fatalf("ожидалась auth.ErrInvalidAPIKey, получено %v", err)
+			}
+		})
+	}
+	if _, ok := keys.lastUsed(revoked.ID); ok {
+		t.Fatalf("использование отозванного ключа отмечено")
+	}
+
+	// Другой пользователь не может отозвать чужой ключ
+	other, _, err := uc.CreateAPIKey(ctx, "user-1", "user-1", input)
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if err := uc.RevokeAPIKey(ctx, "user-2", other.ID, "user-2"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Fatalf("отзыв чужого ключа: ожидалась ErrAPIKeyNotFound, получено %v", err)
+	}
+}
+
+func TestLookupAPIKeyUsesCurrentOwnerState(t *testing.T) {
+	ctx := context.Background()
+	users := newFakeUserRepository(&models.User{ID: "admin-1", Email: "admin@example.com", Roles: []string{auth.RoleAdmin, auth.RoleStaff}})
+	keys := newFakeAPIKeyRepository()
+	twoFactors := newFakeTwoFactorRepository()
+	uc := NewAPIKeyUsecase(users, keys, twoFactors, &fakeAuditRepository{}, testAPIKeyConfig())
+
+	_, key, err := uc.CreateAPIKey(ctx, "admin-1", "admin-1", APIKeyInput{Name: "ключ", Scopes: []string{auth.ScopeProductsWrite}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	// Без 2FA ключ не получает роль ADMIN владельца
+	info, err := uc.LookupAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("LookupAPIKey: %v", err)
+	}
+	if hasRole(info.Roles, auth.RoleAdmin) || !hasRole(info.Roles, auth.RoleStaff) {
+		t.Fatalf("роли без 2FA: %v", info.Roles)
+	}
+
+	twoFactors.enable("admin-1", "secret")
+	if info, err = uc.LookupAPIKey(ctx, key); err != nil || !hasRole(info.Roles, auth.RoleAdmin) {
+		t.Fatalf("роли с 2FA: %v, %v", info, err)
+	}
+
+	// Ключ деактивированного владельца недействителен
+	now := time.Now()
+	users.mu.Lock()
+	users.users["admin-1"].DeactivatedAt = &now
+	users.mu.Unlock()
+	if _, err := uc.LookupAPIKey(ctx, key); !errors.Is(err, auth.ErrInvalidAPIKey) {
+		t.Fatalf("деактивированный владелец: ожидалась auth.ErrInvalidAPIKey, получено %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("недействительный токен обновления")
+	ErrRefreshTokenReused  = errors.New("токен обновления использован повторно, сеанс входа отозван")
+)
+
+// TokenPair - короткоживущий токен доступа и токен обновления, выданные при входе или обновлении
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string        // Пусто, если клиенту OAuth2 не разрешено обновлять токены
+	ExpiresIn    time.Duration // Срок действия токена доступа
+	Scopes       []string      // Разрешения токена клиента OAuth2; пусто для токенов входа
+}
+
+// LoginResult - результат входа. Если у пользователя включена двухфакторная аутентификация,
+// токены не выдаются: вход нужно завершить кодом через VerifyTwoFactor с токеном Challenge.
+type LoginResult struct {
+	User      *models.User
+	Tokens    *TokenPair          // nil, пока не пройден второй фактор
+	Challenge *TwoFactorChallenge // nil, если второй фактор не требуется
+	// TwoFactorEnrollmentRequired - политика требует 2FA для роли ADMIN, а пользователь ее не подключил:
+	// токен выдан без роли ADMIN до подключения 2FA
+	TwoFactorEnrollmentRequired bool
+}
+
+// AuthUsecase определяет интерфейс бизнес-логики входа, обновления токенов и выхода.
+type AuthUsecase interface {
+	// Login проверяет email и пароль и выдает новую пару токенов (новое семейство токенов обновления)
+	// или, если включена 2FA, токен незавершенного входа. clientIP используется для ограничения попыток входа.
+	Login(ctx context.Context, email, rawPassword, clientIP string) (*LoginResult, error)
+	// LoginExternal завершает вход пользователя, которого уже подтвердил внешний провайдер (OpenID Connect):
+	// выдает пару токенов или, если включена 2FA, токен незавершенного входа
+	LoginExternal(ctx context.Context, user *models.User) (*LoginResult, error)
+	// Refresh обменивает токен обновления на новую пару. Повторное использование уже обменянного
+	// токена отзывает все семейство и возвращает ErrRefreshTokenReused.
+	// Токены обновления клиентов OAuth2 обмениваются только через RefreshClientTokens.
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	// IssueClientTokens выдает клиенту OAuth2 clientID токен доступа пользователя, ограниченный разрешениями scopes,
+	// и, если withRefresh, токен обновления нового семейства, привязанный к клиенту
+	IssueClientTokens(ctx context.Context, user *models.User, clientID string, scopes []string, withRefresh bool) (*TokenPair, error)
+	// RefreshClientTokens обменивает токен обновления клиента OAuth2 clientID на новую пару с теми же разрешениями
+	RefreshClientTokens(ctx context.Context, refreshToken, clientID string) (*TokenPair, error)
+	// Logout отзывает текущий токен доступа (из контекста) и семейство переданного токена обновления,
+	// а при allSessions - все токены обновления пользователя.
+	Logout(ctx context.Context, refreshToken string, allSessions bool) error
+	// ValidateToken проверяет токен доступа (подпись, срок действия, отзыв) и возвращает его утверждения
+	ValidateToken(token string) (*auth.Claims, error)
+	// RevokedTokens возвращает отозванные токены доступа, срок которых еще не истек
+	RevokedTokens(ctx context.Context) ([]auth.RevokedToken, error)
+	// PurgeExpiredTokens удаляет истекшие токены обновления и записи об отозванных токенах
+	PurgeExpiredTokens(ctx context.Context) (int64, error)
+
+	// VerifyTwoFactor завершает вход кодом TOTP или кодом восстановления и выдает пару токенов
+	VerifyTwoFactor(ctx context.Context, challenge, code string) (*LoginResult, error)
+	// SetupTwoFactor выдает новый секрет TOTP. 2FA включается только после ConfirmTwoFactor.
+	SetupTwoFactor(ctx context.Context, userID string) (*TwoFactorSetup, error)
+	// ConfirmTwoFactor включает 2FA после проверки первого кода и возвращает коды восстановления
+	// (показываются один раз)
+	ConfirmTwoFactor(ctx context.Context, userID, code string) ([]string, error)
+	// DisableTwoFactor отключает 2FA после проверки кода TOTP или кода восстановления
+	DisableTwoFactor(ctx context.Context, userID, code string) error
+	// RegenerateRecoveryCodes заменяет коды восстановления после проверки кода
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+}
+
+type authUsecase struct {
+	userUsecase     UserUsecase
+	tokenRepo       repository.TokenRepository
+	twoFactorRepo   repository.TwoFactorRepository
+	jwtManager      JWTManager
+	refreshDuration time.Duration
+	twoFactor       TwoFactorConfig
+	loginGuard      *LoginGuard
+}
+
+// NewAuthUsecase создает новый экземпляр authUsecase.
+// refreshDuration - срок действия токена обновления, loginGuard ограничивает перебор кодов 2FA.
+func NewAuthUsecase(uc UserUsecase, tokenRepo repository.TokenRepository, twoFactorRepo repository.TwoFactorRepository, jm JWTManager, refreshDuration time.Duration, twoFactor TwoFactorConfig, loginGuard *LoginGuard) AuthUsecase {
+	return &authUsecase{
+		userUsecase:     uc,
+		tokenRepo:       tokenRepo,
+		twoFactorRepo:   twoFactorRepo,
+		jwtManager:      jm,
+		refreshDuration: refreshDuration,
+		twoFactor:       twoFactor,
+		loginGuard:      loginGuard,
+	}
+}
+
+// Login аутентифицирует пользователя и выдает пару токенов или запрашивает второй фактор.
+func (a *authUsecase) Login(ctx context.Context, email, rawPassword, clientIP string) (*LoginResult, error) {
+	user, err := a.userUsecase.AuthenticateUser(ctx, email, rawPassword, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return a.completeLogin(ctx, user)
+}
+
+// LoginExternal завершает вход через внешнего провайдера. Состояние учетной записи проверяет вызывающий.
+func (a *authUsecase) LoginExternal(ctx context.Context, user *models.User) (*LoginResult, error) {
+	return a.completeLogin(ctx, user)
+}
+
+// completeLogin запрашивает второй фактор, если он включен, иначе выдает пару токенов.
+func (a *authUsecase) completeLogin(ctx context.Context, user *models.User) (*LoginResult, error) {
+	twoFactor, err := a.twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled() {
+		challenge, err := a.newLoginChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, Challenge: challenge}, nil
+	}
+
+	return a.issueTokens(ctx, user)
+}
+
+// issueTokens начинает новое семейство токенов обновления и выдает пару токенов.
+func (a *authUsecase) issueTokens(ctx context.Context, user *models.User) (*LoginResult, error) {
+	refreshToken, record, err := a.newRefreshToken(user.ID, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+	if err := a.tokenRepo.CreateRefreshToken(ctx, record); err != nil {
+		return nil, err
+	}
+
+	pair, enrollmentRequired, err := a.tokenPair(ctx, user, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{User: user, Tokens: pair, TwoFactorEnrollmentRequired: enrollmentRequired}, nil
+}
+
+// IssueClientTokens начинает новое семейство токенов обновления клиента и выдает пару токенов.
+func (a *authUsecase) IssueClientTokens(ctx context.Context, user *models.User, clientID string, scopes []string, withRefresh bool) (*TokenPair, error) {
+	var refreshToken string
+	if withRefresh {
+		token, record, err := a.newRefreshToken(user.ID, uuid.NewString())
+		if err != nil {
+			return nil, err
+		}
+		record.ClientID, record.Scopes = clientID, scopes
+		if err := a.tokenRepo.CreateRefreshToken(ctx, record); err != nil {
+			return nil, err
+		}
+		refreshToken = token
+	}
+
+	pair, _, err := a.clientTokenPair(ctx, user, clientID, scopes, refreshToken)
+	return pair, err
+}
+
+// Refresh проверяет токен обновления, выполняет ротацию и выдает новую пару токенов.
+// Роли берутся из актуальной учетной записи, поэтому их изменение применяется при обновлении.
+func (a *authUsecase) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	return a.refresh(ctx, refreshToken, "")
+}
+
+// RefreshClientTokens обменивает токен обновления, только если он выдан клиенту clientID.
+func (a *authUsecase) RefreshClientTokens(ctx context.Context, refreshToken, clientID string) (*TokenPair, error) {
+	return a.refresh(ctx, refreshToken, clientID)
+}
+
+// refresh выполняет ротацию токена обновления, выданного клиенту clientID (пустая строка - токена входа).
+// Разрешения и клиент переходят к следующему токену семейства.
+func (a *authUsecase) refresh(ctx context.Context, refreshToken, clientID string) (*TokenPair, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	current, err := a.tokenRepo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.ClientID != clientID || current.RevokedAt != nil || !time.Now().Before(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		return nil, a.revokeReusedFamily(ctx, current.FamilyID)
+	}
+
+	user, err := a.userUsecase.FindUserByID(ctx, current.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	// Токены отзываются при деактивации и удалении, но обмен мог начаться параллельно
+	if !user.Active() {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	nextToken, next, err := a.newRefreshToken(user.ID, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	next.ClientID, next.Scopes = current.ClientID, current.Scopes
+	if err := a.tokenRepo.RotateRefreshToken(ctx, current.ID, next); err != nil {
+		// Токен успели обменять или отозвать параллельным запросом
+		if errors.Is(err, repository.ErrRefreshTokenUsed) {
+			return nil, a.revokeReusedFamily(ctx, current.FamilyID)
+		}
+		return nil, err
+	}
+
+	pair, _, err := a.clientTokenPair(ctx, user, current.ClientID, current.Scopes, nextToken)
+	return pair, err
+}
+
+// revokeReusedFamily отзывает семейство токенов, в котором обнаружено повторное использование
+func (a *authUsecase) revokeReusedFamily(ctx context.Context, familyID string) error {
+	if err := a.tokenRepo.RevokeTokenFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Logout отзывает токены текущего пользователя.
+func (a *authUsecase) Logout(ctx context.Context, refreshToken string, allSessions bool) error {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return auth.ErrMissingToken
+	}
+
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := a.tokenRepo.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+
+	if allSessions {
+		return a.tokenRepo.RevokeUserTokens(ctx, claims.UserID)
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	current, err := a.tokenRepo.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	if current == nil || current.UserID != claims.UserID {
+		return ErrInvalidRefreshToken
+	}
+	return a.tokenRepo.RevokeTokenFamily(ctx, current.FamilyID)
+}
+
+// ValidateToken проверяет токен доступа для других сервисов.
+func (a *authUsecase) ValidateToken(token string) (*auth.Claims, error) {
+	return a.jwtManager.ValidateToken(token)
+}
+
+// RevokedTokens возвращает список отозванных токенов доступа для других сервисов.
+func (a *authUsecase) RevokedTokens(ctx context.Context) ([]auth.RevokedToken, error) {
+	return a.tokenRepo.ListRevokedAccessTokens(ctx)
+}
+
+// PurgeExpiredTokens удаляет истекшие записи о токенах.
+func (a *authUsecase) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	return a.tokenRepo.DeleteExpiredTokens(ctx)
+}
+
+// tokenPair выпускает токен доступа и собирает его в пару с токеном обновления.
+// Второе значение сообщает, что роль ADMIN не включена в токен до подключения 2FA.
+func (a *authUsecase) tokenPair(ctx context.Context, user *models.User, refreshToken string) (*TokenPair, bool, error) {
+	return a.clientTokenPair(ctx, user, "", nil, refreshToken)
+}
+
+// clientTokenPair выпускает токен доступа клиента clientID с разрешениями scopes (для пустого clientID -
+// неограниченный токен входа) и собирает его в пару с токеном обновления.
+func (a *authUsecase) clientTokenPair(ctx context.Context, user *models.User, clientID string, scopes []string, refreshToken string) (*TokenPair, bool, error) {
+	roles, enrollmentRequired, err := a.tokenRoles(ctx, user)
+	if err != nil {
+		return nil, false, err
+	}
+
+	accessToken, err := a.jwtManager.GenerateClientToken(user.ID, roles, clientID, scopes)
+	if err != nil {
+		return nil, false, err
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    a.jwtManager.TokenDuration(),
+		Scopes:       scopes,
+	}, enrollmentRequired, nil
+}
+
+// newRefreshToken генерирует случайный токен обновления и запись для хранения его хеша
+func (a *authUsecase) newRefreshToken(userID, familyID string) (string, *models.RefreshToken, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().UTC().Add(a.refreshDuration),
+	}, nil
+}
+
+// generateToken возвращает 256 случайных бит в кодировке base64url
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken возвращает SHA-256 хеш токена в шестнадцатеричном виде.
+// Токен обладает достаточной энтропией, поэтому медленный хеш (как для паролей) не нужен.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+)
+
+// newRefreshTestUsecase создает authUsecase с хранилищами в памяти для проверки обмена токенов обновления
+func newRefreshTestUsecase(users ...*models.User) (*authUsecase, *fakeTokenRepository, *fakeUserRepository) {
+	userRepo := newFakeUserRepository(users...)
+	tokens := newFakeTokenRepository()
+	userUsecase := NewUserUsecase(userRepo, nil, NewArgon2idPasswordHasher(testArgon2Params()), DefaultPasswordPolicy(), nil,
+		EmailTokenConfig{}, EmailTokenConfig{}, newTestLoginGuard())
+	uc := NewAuthUsecase(userUsecase, tokens, newFakeTwoFactorRepository(), NewJWTManager("test-secret", 15*time.Minute, nil),
+		24*time.Hour, TwoFactorConfig{}, newTestLoginGuard())
+	return uc.(*authUsecase), tokens, userRepo
+}
+
+// mustIssueRefreshToken выдает пользователю userID токен обновления клиента clientID (пустой - токен входа)
+// и возвращает его вместе с ID семейства
+func mustIssueRefreshToken(t *testing.T, uc *authUsecase, tokens *fakeTokenRepository, userID, clientID string, scopes ...string) (string, string) {
+	t.Helper()
+	ctx := context.Background()
+	user := &models.User{ID: userID}
+	var refreshToken string
+	if clientID == "" {
+		result, err := uc.issueTokens(ctx, user)
+		if err != nil {
+			t.Fatalf("issueTokens: %v", err)
+		}
+		refreshToken = result.Tokens.RefreshToken
+	} else {
+		pair, err := uc.IssueClientTokens(ctx, user, clientID, scopes, true)
+		if err != nil {
+			t.Fatalf("IssueClientTokens: %v", err)
+		}
+		refreshToken = pair.RefreshToken
+	}
+	stored, _ := tokens.GetRefreshTokenByHash(ctx, hashToken(refreshToken))
+	return refreshToken, stored.FamilyID
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	ctx := context.Background()
+	uc, tokens, users := newRefreshTestUsecase(&models.User{ID: "user-1", Roles: []string{auth.RoleCustomer}})
+	first, family := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+
+	// Роли берутся из текущей учетной записи
+	users.mu.Lock()
+	users.users["user-1"].Roles = []string{auth.RoleStaff}
+	users.mu.Unlock()
+
+	pair, err := uc.Refresh(ctx, first)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if pair.RefreshToken == "" || pair.RefreshToken == first {
+		t.Fatalf("токен обновления не заменен")
+	}
+	claims, err := uc.ValidateToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.UserID != "user-1" || !claims.HasRole(auth.RoleStaff) || claims.Scoped() {
+		t.Fatalf("неожиданное содержимое токена доступа: %+v", claims)
+	}
+
+	next, _ := tokens.GetRefreshTokenByHash(ctx, hashToken(pair.RefreshToken))
+	if next == nil || next.FamilyID != family {
+		t.Fatalf("новый токен не продолжает семейство %s: %+v", family, next)
+	}
+	if used, _ := tokens.GetRefreshTokenByHash(ctx, hashToken(first)); used.UsedAt == nil {
+		t.Fatalf("обмененный токен не отмечен использованным")
+	}
+
+	if _, err := uc.Refresh(ctx, pair.RefreshToken); err != nil {
+		t.Fatalf("Refresh новым токеном: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	uc, tokens, _ := newRefreshTestUsecase(&models.User{ID: "user-1"})
+	first, family := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+
+	pair, err := uc.Refresh(ctx, first)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	// Повторное использование обмененного токена - признак кражи: отзывается все семейство
+	if _, err := uc.Refresh(ctx, first); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("повторный обмен: ожидалась ErrRefreshTokenReused, получено %v", err)
+	}
+	if active := tokens.activeInFamily(family); active != 0 {
+		t.Fatalf("в семействе осталось %d действующих токенов", active)
+	}
+	if _, err := uc.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("токен отозванного семейства: ожидалась ErrInvalidRefreshToken, получено %v", err)
+	}
+
+	// Другие сеансы пользователя не затрагиваются
+	other, _ := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+	if _, err := uc.Refresh(ctx, other); err != nil {
+		t.Fatalf("Refresh другого сеанса: %v", err)
+	}
+}
+
+func TestRefreshRejectsInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	uc, tokens, _ := newRefreshTestUsecase(
+		&models.User{ID: "user-1"},
+		&models.User{ID: "user-2", DeactivatedAt: &now},
+	)
+
+	expired, _ := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+	tokens.update(hashToken(expired), func(token *models.RefreshToken) { token.ExpiresAt = now.Add(-time.Second) })
+
+	revoked, revokedFamily := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+	tokens.RevokeTokenFamily(ctx, revokedFamily)
+
+	deactivated, _ := mustIssueRefreshToken(t, uc, tokens, "user-2", "")
+	deleted, _ := mustIssueRefreshToken(t, uc, tokens, "user-3", "")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"пустой токен", ""},
+		{"неизвестный токен", "unknown"},
+		{"истекший токен", expired},
+		{"отозванный токен", revoked},
+		{"деактивированный пользователь", deactivated},
+		{"удаленный пользователь", deleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.Refresh(ctx, tt.token); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Fatalf("ожидалась ErrInvalidRefreshToken, получено %v", err)
+			}
+		})
+	}
+
+	// Отклоненный токен не обменивается и не отзывает семейство
+	if stored, _ := tokens.GetRefreshTokenByHash(ctx, hashToken(expired)); stored.UsedAt != nil || stored.RevokedAt != nil {
+		t.Fatalf("истекший токен изменен: %+v", stored)
+	}
+}
+
+func TestRefreshClientMismatch(t *testing.T) {
+	ctx := context.Background()
+	uc, tokens, _ := newRefreshTestUsecase(&models.User{ID: "user-1"})
+	login, _ := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+	client, _ := mustIssueRefreshToken(t, uc, tokens, "user-1", "pos-app", auth.ScopeOrdersRead)
+
+	tests := []struct {
+		name     string
+		token    string
+		clientID string
+	}{
+		{"токен входа через клиента OAuth2", login, "pos-app"},
+		{"токен клиента через обычное обновление", client, ""},
+		{"токен клиента через другого клиента", client, "other-app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.refresh(ctx, tt.token, tt.clientID); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Fatalf("ожидалась ErrInvalidRefreshToken, получено %v", err)
+			}
+		})
+	}
+
+	// Токен клиента обменивается только своим клиентом и сохраняет разрешения
+	pair, err := uc.RefreshClientTokens(ctx, client, "pos-app")
+	if err != nil {
+		t.Fatalf("RefreshClientTokens: %v", err)
+	}
+	claims, err := uc.ValidateToken(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.ClientID != "pos-app" || !claims.HasScope(auth.ScopeOrdersRead) || claims.HasScope(auth.ScopeOrdersWrite) {
+		t.Fatalf("неожиданное содержимое токена клиента: %+v", claims)
+	}
+	next, _ := tokens.GetRefreshTokenByHash(ctx, hashToken(pair.RefreshToken))
+	if next.ClientID != "pos-app" || len(next.Scopes) != 1 || next.Scopes[0] != auth.ScopeOrdersRead {
+		t.Fatalf("клиент и разрешения не перешли к следующему токену: %+v", next)
+	}
+}
+
+func TestRefreshConcurrentRotation(t *testing.T) {
+	ctx := context.Background()
+	uc, tokens, _ := newRefreshTestUsecase(&models.User{ID: "user-1"})
+	first, family := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+
+	// Параллельный запрос обменял токен между чтением и ротацией: RotateRefreshToken возвращает ErrRefreshTokenUsed
+	tokens.beforeRotate = tokens.markUsed
+	if _, err := uc.Refresh(ctx, first); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("ожидалась ErrRefreshTokenReused, получено %v", err)
+	}
+	if active := tokens.activeInFamily(family); active != 0 {
+		t.Fatalf("в семействе осталось %d действующих токенов", active)
+	}
+	tokens.beforeRotate = nil
+
+	// Одновременный обмен одного токена: успешен не больше чем один запрос, остальные отзывают семейство
+	second, secondFamily := mustIssueRefreshToken(t, uc, tokens, "user-1", "")
+	const parallel = 8
+	errs := make(chan error, parallel)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.Refresh(ctx, second)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrRefreshTokenReused) && !errors.Is(err, ErrInvalidRefreshToken):
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+	}
+	if succeeded > 1 {
+		t.Fatalf("один токен обменян %d раз", succeeded)
+	}
+	if active := tokens.activeInFamily(secondFamily); active != 0 {
+		t.Fatalf("после одновременного обмена в семействе осталось %d действующих токенов", active)
+	}
+}
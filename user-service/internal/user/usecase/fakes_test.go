@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+)
+
+// Фейковые хранилища в памяти для тестов usecase. Встроенный интерфейс репозитория остается nil:
+// вызов метода, который фейк не реализует, завершит тест паникой и сразу покажет, чего не хватает.
+
+// fakeUserRepository хранит пользователей в памяти
+type fakeUserRepository struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[string]*models.User
+}
+
+func newFakeUserRepository(users ...*models.User) *fakeUserRepository {
+	repo := &fakeUserRepository{users: make(map[string]*models.User)}
+	for _, user := range users {
+		stored := *user
+		repo.users[user.ID] = &stored
+	}
+	return repo
+}
+
+func (r *fakeUserRepository) GetByID(_ context.Context, id string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	found := *user
+	return &found, nil
+}
+
+func (r *fakeUserRepository) GetByEmail(_ context.Context, email string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepository) ReplacePasswordHash(_ context.Context, id, oldHash, newHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok || user.Password != oldHash {
+		return sql.ErrNoRows
+	}
+	user.Password = newHash
+	return nil
+}
+
+// passwordHash возвращает сохраненный хеш пароля пользователя
+func (r *fakeUserRepository) passwordHash(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.users[id].Password
+}
+
+// fakeAuditRepository запоминает события журнала аутентификации
+type fakeAuditRepository struct {
+	mu     sync.Mutex
+	events []*models.AuthEvent
+}
+
+func (r *fakeAuditRepository) RecordAuthEvent(_ context.Context, event *models.AuthEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *fakeAuditRepository) ListAuthEvents(_ context.Context, _ repository.AuthEventFilter) ([]*models.AuthEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*models.AuthEvent(nil), r.events...), nil
+}
+
+// newTestLoginGuard создает LoginGuard с хранилищем попыток в памяти и политикой по умолчанию
+func newTestLoginGuard() *LoginGuard {
+	return NewLoginGuard(repository.NewMemoryLoginAttemptStore(), &fakeAuditRepository{}, DefaultLoginPolicy())
+}
+
+// fakeTwoFactorRepository хранит настройки 2FA и хеши кодов восстановления в памяти
+type fakeTwoFactorRepository struct {
+	repository.TwoFactorRepository
+
+	mu            sync.Mutex
+	twoFactors    map[string]*models.TwoFactor
+	recoveryCodes map[string]map[string]bool // userID -> хеш кода -> использован
+}
+
+func newFakeTwoFactorRepository() *fakeTwoFactorRepository {
+	return &fakeTwoFactorRepository{
+		twoFactors:    make(map[string]*models.TwoFactor),
+		recoveryCodes: make(map[string]map[string]bool),
+	}
+}
+
+// enable включает 2FA пользователя с секретом secret и кодами восстановления с хешами hashes
+func (r *fakeTwoFactorRepository) enable(userID, secret string, hashes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.twoFactors[userID] = &models.TwoFactor{UserID: userID, Secret: secret, ConfirmedAt: &now, CreatedAt: now}
+	r.recoveryCodes[userID] = make(map[string]bool)
+	for _, hash := range hashes {
+		r.recoveryCodes[userID][hash] = false
+	}
+}
+
+func (r *fakeTwoFactorRepository) GetTwoFactor(_ context.Context, userID string) (*models.TwoFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	twoFactor, ok := r.twoFactors[userID]
+	if !ok {
+		return nil, nil
+	}
+	found := *twoFactor
+	return &found, nil
+}
+
+func (r *fakeTwoFactorRepository) UseTwoFactorStep(_ context.Context, userID string, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	twoFactor, ok := r.twoFactors[userID]
+	if !ok || step <= twoFactor.LastUsedStep {
+		return false, nil
+	}
+	twoFactor.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) ReplaceRecoveryCodes(_ context.Context, userID string, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recoveryCodes[userID] = make(map[string]bool)
+	for _, hash := range codeHashes {
+		r.recoveryCodes[userID][hash] = false
+	}
+	return nil
+}
+
+func (r *fakeTwoFactorRepository) UseRecoveryCode(_ context.Context, userID, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	used, ok := r.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recoveryCodes[userID][codeHash] = true
+	return true, nil
+}
+
+func (r *fakeTwoFactorRepository) DeleteTwoFactor(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.twoFactors, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
+
+// enabled сообщает, включена ли 2FA пользователя
+func (r *fakeTwoFactorRepository) enabled(userID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.twoFactors[userID].Enabled()
+}
+
+// fakeAPIKeyRepository хранит API-ключи в памяти
+type fakeAPIKeyRepository struct {
+	mu      sync.Mutex
+	keys    map[string]*models.APIKey
+	nextID  int
+	touched map[string]time.Time // ID ключа -> время последнего использования
+}
+
+func newFakeAPIKeyRepository() *fakeAPIKeyRepository {
+	return &fakeAPIKeyRepository{keys: make(map[string]*models.APIKey), touched: make(map[string]time.Time)}
+}
+
+func (r *fakeAPIKeyRepository) CreateAPIKey(_ context.Context, key *models.APIKey, maxKeys int) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	active := 0
+	for _, stored := range r.keys {
+		if stored.UserID == key.UserID && stored.Valid(now) {
+			active++
+		}
+	}
+	if active >= maxKeys {
+		return nil, repository.ErrAPIKeyLimit
+	}
+	r.nextID++
+	stored := *key
+	stored.ID = fmt.Sprintf("key-%d", r.nextID)
+	stored.CreatedAt = now
+	r.keys[stored.ID] = &stored
+	created := stored
+	return &created, nil
+}
+
+func (r *fakeAPIKeyRepository) ListAPIKeys(_ context.Context, userID string) ([]*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []*models.APIKey
+	for _, stored := range r.keys {
+		if stored.UserID == userID {
+			found := *stored
+			keys = append(keys, &found)
+		}
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepository) GetAPIKeyByHash(_ context.Context, keyHash string) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.keys {
+		if stored.KeyHash == keyHash {
+			found := *stored
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAPIKeyRepository) RevokeAPIKey(_ context.Context, userID, id string) (*models.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.keys[id]
+	if !ok || stored.UserID != userID || stored.RevokedAt != nil {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now()
+	stored.RevokedAt = &now
+	revoked := *stored
+	return &revoked, nil
+}
+
+func (r *fakeAPIKeyRepository) TouchAPIKey(_ context.Context, id string, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.touched[id] = usedAt
+	return nil
+}
+
+// expire переносит срок действия ключа id в прошлое
+func (r *fakeAPIKeyRepository) expire(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	past := time.Now().Add(-time.Minute)
+	r.keys[id].ExpiresAt = &past
+}
+
+// stored возвращает сохраненный ключ id
+func (r *fakeAPIKeyRepository) stored(id string) models.APIKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.keys[id]
+}
+
+// lastUsed возвращает время последнего использования ключа id
+func (r *fakeAPIKeyRepository) lastUsed(id string) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usedAt, ok := r.touched[id]
+	return usedAt, ok
+}
+
+// fakeTokenRepository хранит токены обновления в памяти
+type fakeTokenRepository struct {
+	repository.TokenRepository
+
+	mu     sync.Mutex
+	tokens map[string]*models.RefreshToken // ID -> токен
+	nextID int
+	// beforeRotate, если задан, вызывается в RotateRefreshToken до проверки токена oldID;
+	// так тест имитирует параллельный запрос, успевший обменять тот же токен
+	beforeRotate func(oldID string)
+}
+
+func newFakeTokenRepository() *fakeTokenRepository {
+	return &fakeTokenRepository{tokens: make(map[string]*models.RefreshToken)}
+}
+
+func (r *fakeTokenRepository) CreateRefreshToken(_ context.Context, token *models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.insert(token)
+	return nil
+}
+
+// insert сохраняет токен; вызывается под r.mu
+func (r *fakeTokenRepository) insert(token *models.RefreshToken) {
+	r.nextID++
+	token.ID = fmt.Sprintf("token-%d", r.nextID)
+	token.CreatedAt = time.Now().UTC()
+	stored := *token
+	r.tokens[token.ID] = &stored
+}
+
+func (r *fakeTokenRepository) GetRefreshTokenByHash(_ context.Context, hash string) (*models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeTokenRepository) RotateRefreshToken(_ context.Context, oldID string, next *models.RefreshToken) error {
+	if r.beforeRotate != nil {
+		r.beforeRotate(oldID)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.tokens[oldID]
+	if !ok || old.UsedAt != nil || old.RevokedAt != nil {
+		return repository.ErrRefreshTokenUsed
+	}
+	now := time.Now().UTC()
+	old.UsedAt = &now
+	r.insert(next)
+	return nil
+}
+
+func (r *fakeTokenRepository) RevokeTokenFamily(_ context.Context, familyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// markUsed отмечает токен id обмененным, как это сделал бы параллельный запрос
+func (r *fakeTokenRepository) markUsed(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	r.tokens[id].UsedAt = &now
+}
+
+// update изменяет сохраненный токен с хешем hash
+func (r *fakeTokenRepository) update(hash string, change func(token *models.RefreshToken)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			change(token)
+		}
+	}
+}
+
+// activeInFamily возвращает количество неотозванных токенов семейства familyID
+func (r *fakeTokenRepository) activeInFamily(familyID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	active := 0
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			active++
+		}
+	}
+	return active
+}
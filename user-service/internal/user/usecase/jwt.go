@@ -3,31 +3,107 @@ package usecase
 import (
 	"time"
 
+	"github.com/Hayzerr/go-microservice-project/auth"
 	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// JWTManager выпускает токены доступа и проверяет их.
+// Проверка делегируется общему модулю auth, которым пользуются и другие сервисы.
+// Роли пользователя попадают в токен, поэтому изменение ролей вступает в силу после повторного входа
+// или обновления токена.
 type JWTManager interface {
-	GenerateToken(userID string) (string, error)
+	GenerateToken(userID string, roles []string) (string, error)
+	// GenerateClientToken выпускает токен доступа клиента OAuth2 clientID, ограниченный разрешениями scopes.
+	// Для токена клиента без пользователя (client credentials) userID совпадает с clientID.
+	GenerateClientToken(userID string, roles []string, clientID string, scopes []string) (string, error)
+	// Sign подписывает произвольные утверждения текущим ключом (например, ID-токен OpenID Connect)
+	Sign(claims jwt.Claims) (string, error)
+	ValidateToken(token string) (*auth.Claims, error)
+	// TokenDuration возвращает срок действия выпускаемых токенов доступа
+	TokenDuration() time.Duration
+	// JWKS возвращает открытые ключи проверки подписи (пустой набор для HS256)
+	JWKS() (auth.JWKS, error)
 }
 
 type jwtManager struct {
 	secretKey     string
+	keyRing       *KeyRing
 	tokenDuration time.Duration
+	validator     auth.TokenValidator
 }
 
-func NewJWTManager(secretKey string, duration time.Duration) JWTManager {
+// NewJWTManager создает JWTManager, подписывающий токены общим секретом (HS256).
+// Если denylist не nil, отозванные токены (по jti) не проходят проверку.
+func NewJWTManager(secretKey string, duration time.Duration, denylist auth.Denylist) JWTManager {
 	return &jwtManager{
 		secretKey:     secretKey,
 		tokenDuration: duration,
+		validator:     withDenylist(auth.NewValidator(secretKey), denylist),
 	}
 }
 
-func (j *jwtManager) GenerateToken(userID string) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(j.tokenDuration).Unix(),
+// NewKeyRingJWTManager создает JWTManager, подписывающий токены текущим ключом keyRing (RS256 или EdDSA).
+// Другие сервисы проверяют такие токены по открытым ключам из JWKS без общего секрета.
+func NewKeyRingJWTManager(keyRing *KeyRing, duration time.Duration, denylist auth.Denylist) JWTManager {
+	return &jwtManager{
+		keyRing:       keyRing,
+		tokenDuration: duration,
+		validator:     withDenylist(auth.NewKeyValidator(keyRing), denylist),
+	}
+}
+
+// withDenylist подключает проверку отозванных токенов, если список задан
+func withDenylist(validator auth.TokenValidator, denylist auth.Denylist) auth.TokenValidator {
+	if denylist == nil {
+		return validator
 	}
+	return auth.WithDenylist(validator, denylist)
+}
+
+func (j *jwtManager) GenerateToken(userID string, roles []string) (string, error) {
+	return j.GenerateClientToken(userID, roles, "", nil)
+}
+
+func (j *jwtManager) GenerateClientToken(userID string, roles []string, clientID string, scopes []string) (string, error) {
+	now := time.Now()
+	return j.Sign(auth.Claims{
+		UserID:   userID,
+		Roles:    roles,
+		Scopes:   scopes,
+		ClientID: clientID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(), // jti: по нему токен можно отозвать до истечения срока
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.tokenDuration)),
+		},
+	})
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+func (j *jwtManager) Sign(claims jwt.Claims) (string, error) {
+	if j.keyRing == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(j.secretKey))
+	}
+
+	key := j.keyRing.Current()
+	token := jwt.NewWithClaims(key.Method(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+func (j *jwtManager) ValidateToken(token string) (*auth.Claims, error) {
+	return j.validator.ValidateToken(token)
+}
+
+func (j *jwtManager) TokenDuration() time.Duration {
+	return j.tokenDuration
+}
+
+func (j *jwtManager) JWKS() (auth.JWKS, error) {
+	if j.keyRing == nil {
+		return auth.JWKS{Keys: []auth.JWK{}}, nil
+	}
+	return j.keyRing.JWKS()
 }
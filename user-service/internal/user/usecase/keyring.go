@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"crypto"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+)
+
+// KeyRing хранит ключи подписи токенов: текущий ключ подписывает новые токены,
+// а ключи, выведенные из оборота ротацией, остаются в JWKS, пока не истекут подписанные ими токены.
+type KeyRing struct {
+	mu        sync.RWMutex
+	algorithm string
+	current   *auth.SigningKey
+	keys      []*auth.SigningKey
+	retiredAt map[string]time.Time
+	retention time.Duration
+}
+
+// NewKeyRing создает KeyRing. Первый из keys становится текущим ключом, остальные используются
+// только для проверки подписи (например, ключи предыдущего развертывания). Если keys не переданы,
+// генерируется новый ключ алгоритма algorithm. retention - сколько хранить ключ после ротации
+// (не меньше срока действия токена доступа).
+func NewKeyRing(algorithm string, retention time.Duration, keys ...*auth.SigningKey) (*KeyRing, error) {
+	if len(keys) == 0 {
+		key, err := auth.GenerateSigningKey(algorithm)
+		if err != nil {
+			return nil, err
+		}
+		keys = []*auth.SigningKey{key}
+	}
+
+	return &KeyRing{
+		algorithm: keys[0].Algorithm,
+		current:   keys[0],
+		keys:      keys,
+		retiredAt: make(map[string]time.Time),
+		retention: retention,
+	}, nil
+}
+
+// Current возвращает ключ, которым подписываются новые токены
+func (k *KeyRing) Current() *auth.SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current
+}
+
+// Rotate генерирует новый текущий ключ. Прежний ключ остается доступным для проверки
+// в течение retention, после чего удаляется вместе с другими устаревшими ключами.
+func (k *KeyRing) Rotate() error {
+	key, err := auth.GenerateSigningKey(k.algorithm)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	k.retiredAt[k.current.ID] = now
+	k.current = key
+
+	keys := []*auth.SigningKey{key}
+	for _, existing := range k.keys {
+		retired, ok := k.retiredAt[existing.ID]
+		if ok && now.Sub(retired) > k.retention {
+			delete(k.retiredAt, existing.ID)
+			continue
+		}
+		keys = append(keys, existing)
+	}
+	k.keys = keys
+	return nil
+}
+
+// RunRotation выполняет ротацию каждые interval, пока не закрыт stop
+func (k *KeyRing) RunRotation(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := k.Rotate(); err != nil {
+				log.Printf("Ошибка ротации ключа подписи: %v", err)
+				continue
+			}
+			log.Printf("Выполнена ротация ключа подписи, новый kid: %s", k.Current().ID)
+		}
+	}
+}
+
+// PublicKey возвращает открытый ключ по kid (реализует auth.KeySource)
+func (k *KeyRing) PublicKey(kid string) (crypto.PublicKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.ID != kid {
+			continue
+		}
+		// Ключ, срок хранения которого истек, но который еще не удален очередной ротацией
+		if retired, ok := k.retiredAt[kid]; ok && time.Since(retired) > k.retention {
+			break
+		}
+		return key.PublicKey(), nil
+	}
+	return nil, fmt.Errorf("%w: %s", auth.ErrUnknownKey, kid)
+}
+
+// JWKS возвращает открытые ключи для публикации по адресу /.well-known/jwks.json
+func (k *KeyRing) JWKS() (auth.JWKS, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := auth.JWKS{Keys: make([]auth.JWK, 0, len(k.keys))}
+	for _, key := range k.keys {
+		if retired, ok := k.retiredAt[key.ID]; ok && time.Since(retired) > k.retention {
+			continue
+		}
+		jwk, err := auth.NewJWK(key.ID, key.PublicKey())
+		if err != nil {
+			return auth.JWKS{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+)
+
+// ErrTooManyLoginAttempts возвращается (внутри *LoginThrottledError), если вход временно запрещен
+var ErrTooManyLoginAttempts = errors.New("слишком много неудачных попыток входа")
+
+// LoginThrottledError сообщает, через сколько можно повторить вход.
+// errors.Is(err, ErrTooManyLoginAttempts) возвращает true.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool // Учетная запись или адрес заблокированы (а не только замедлены)
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("%v: вход заблокирован, повторите через %s", ErrTooManyLoginAttempts, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("%v: повторите через %s", ErrTooManyLoginAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// LoginLimit задает пороги для одного вида ключа (email или IP-адрес)
+type LoginLimit struct {
+	BackoffAfter int // После стольких неудач подряд каждая следующая попытка откладывается
+	MaxFailures  int // После стольких неудач подряд вход блокируется на LoginPolicy.Lockout
+}
+
+// LoginPolicy задает ограничение попыток входа
+type LoginPolicy struct {
+	Email       LoginLimit
+	IP          LoginLimit
+	BackoffBase time.Duration // Задержка после первой неудачи сверх BackoffAfter, далее удваивается
+	BackoffMax  time.Duration // Верхняя граница задержки
+	Lockout     time.Duration // Длительность блокировки после MaxFailures неудач
+	Window      time.Duration // Неудачи старше окна не учитываются
+}
+
+// DefaultLoginPolicy возвращает политику по умолчанию: по email задержка со второй неудачи
+// (1s, 2s, 4s...) и блокировка на 15 минут после 5 неудач; по IP-адресу - задержка после 10
+// и блокировка после 50 неудач, чтобы не мешать пользователям за общим NAT.
+func DefaultLoginPolicy() LoginPolicy {
+	return LoginPolicy{
+		Email:       LoginLimit{BackoffAfter: 1, MaxFailures: 5},
+		IP:          LoginLimit{BackoffAfter: 10, MaxFailures: 50},
+		BackoffBase: time.Second,
+		BackoffMax:  time.Minute,
+		Lockout:     15 * time.Minute,
+		Window:      time.Hour,
+	}
+}
+
+// delay возвращает, на сколько запретить вход после failures неудач подряд, и признак блокировки
+func (p LoginPolicy) delay(limit LoginLimit, failures int) (time.Duration, bool) {
+	if limit.MaxFailures > 0 && failures >= limit.MaxFailures {
+		return p.Lockout, true
+	}
+	if failures <= limit.BackoffAfter {
+		return 0, false
+	}
+
+	delay := p.BackoffBase
+	for i := limit.BackoffAfter + 1; i < failures && delay < p.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > p.BackoffMax {
+		delay = p.BackoffMax
+	}
+	return delay, false
+}
+
+// LoginGuard ограничивает попытки входа по email и IP-адресу клиента
+// и записывает результаты входа в журнал аутентификации.
+type LoginGuard struct {
+	store  repository.LoginAttemptStore
+	audit  repository.AuditRepository
+	policy LoginPolicy
+}
+
+// NewLoginGuard создает новый экземпляр LoginGuard
+func NewLoginGuard(store repository.LoginAttemptStore, audit repository.AuditRepository, policy LoginPolicy) *LoginGuard {
+	return &LoginGuard{store: store, audit: audit, policy: policy}
+}
+
+// emailKey и ipKey - ключи хранилища попыток
+func emailKey(email string) string { return "email:" + strings.ToLower(strings.TrimSpace(email)) }
+func ipKey(ip string) string       { return "ip:" + ip }
+
+// loginKeys возвращает ключи, по которым учитываются попытки входа
+func (g *LoginGuard) loginKeys(email, ip string) []string {
+	keys := []string{emailKey(email)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+// Check возвращает *LoginThrottledError, если вход по email или с адреса ip сейчас запрещен.
+// Проверка выполняется до сравнения пароля, чтобы перебор не тратил время на bcrypt.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	var throttled *LoginThrottledError
+	for _, key := range g.loginKeys(email, ip) {
+		throttle, err := g.store.GetLoginThrottle(ctx, key)
+		if err != nil {
+			return err
+		}
+		if throttle == nil || throttle.LockedUntil == nil || !throttle.LockedUntil.After(now) {
+			continue
+		}
+
+		retryAfter := throttle.LockedUntil.Sub(now)
+		if throttled == nil || retryAfter > throttled.RetryAfter {
+			_, locked := g.policy.delay(g.limitFor(key), throttle.Failures)
+			throttled = &LoginThrottledError{RetryAfter: retryAfter, Locked: locked}
+		}
+	}
+
+	if throttled != nil {
+		g.record(ctx, &models.AuthEvent{Event: models.AuthEventLoginBlocked, Email: email, IP: ip, Details: throttled.Error()})
+		return throttled
+	}
+	return nil
+}
+
+// limitFor возвращает пороги для ключа
+func (g *LoginGuard) limitFor(key string) LoginLimit {
+	if strings.HasPrefix(key, "ip:") {
+		return g.policy.IP
+	}
+	return g.policy.Email
+}
+
+// Failure учитывает неудачную попытку входа и при необходимости откладывает или блокирует вход.
+// userID пуст, если email не зарегистрирован.
+func (g *LoginGuard) Failure(ctx context.Context, userID, email, ip string) error {
+	now := time.Now().UTC()
+	details := ""
+	for _, key := range g.loginKeys(email, ip) {
+		throttle, err := g.store.RecordLoginFailure(ctx, key, now, g.policy.Window)
+		if err != nil {
+			return err
+		}
+
+		delay, locked := g.policy.delay(g.limitFor(key), throttle.Failures)
+		if delay <= 0 {
+			continue
+		}
+		if err := g.store.LockLogin(ctx, key, now.Add(delay)); err != nil {
+			return err
+		}
+		if locked {
+			details = fmt.Sprintf("%s заблокирован на %s после %d неудачных попыток", key, delay, throttle.Failures)
+			log.Printf("Вход %s", details)
+		}
+	}
+
+	g.record(ctx, &models.AuthEvent{Event: models.AuthEventLoginFailed, UserID: userID, Email: email, IP: ip, Details: details})
+	return nil
+}
+
+// Success сбрасывает счетчик неудач по email и записывает успешный вход.
+// Счетчик по IP-адресу не сбрасывается: иначе перебор по многим учетным записям
+// с одного адреса можно было бы обнулять входом в собственную.
+func (g *LoginGuard) Success(ctx context.Context, userID, email, ip string) error {
+	if err := g.store.ResetLogin(ctx, emailKey(email)); err != nil {
+		return err
+	}
+	g.record(ctx, &models.AuthEvent{Event: models.AuthEventLoginSucceeded, UserID: userID, Email: email, IP: ip})
+	return nil
+}
+
+// Unlock снимает блокировку входа по email. actorID - администратор, снявший блокировку.
+func (g *LoginGuard) Unlock(ctx context.Context, userID, email, actorID string) error {
+	if err := g.store.ResetLogin(ctx, emailKey(email)); err != nil {
+		return err
+	}
+	g.record(ctx, &models.AuthEvent{Event: models.AuthEventAccountUnlocked, UserID: userID, Email: email, Details: "администратор " + actorID})
+	return nil
+}
+
+// Events возвращает записи журнала аутентификации
+func (g *LoginGuard) Events(ctx context.Context, filter repository.AuthEventFilter) ([]*models.AuthEvent, error) {
+	return g.audit.ListAuthEvents(ctx, filter)
+}
+
+// secondFactorKey - ключ хранилища попыток для кодов 2FA пользователя вне входа
+func secondFactorKey(userID string) string { return "2fa:" + userID }
+
+// CheckSecondFactor возвращает ErrTooManyTwoFactorAttempts, если проверка кодов 2FA пользователя
+// (отключение 2FA, замена кодов восстановления) сейчас заблокирована.
+func (g *LoginGuard) CheckSecondFactor(ctx context.Context, userID string) error {
+	throttle, err := g.store.GetLoginThrottle(ctx, secondFactorKey(userID))
+	if err != nil {
+		return err
+	}
+	if throttle != nil && throttle.LockedUntil != nil && throttle.LockedUntil.After(time.Now()) {
+		return ErrTooManyTwoFactorAttempts
+	}
+	return nil
+}
+
+// SecondFactorFailure учитывает неверный код 2FA и после maxAttempts неудач подряд
+// блокирует проверку кодов пользователя на LoginPolicy.Lockout.
+func (g *LoginGuard) SecondFactorFailure(ctx context.Context, userID string, maxAttempts int) error {
+	now := time.Now().UTC()
+	key := secondFactorKey(userID)
+	throttle, err := g.store.RecordLoginFailure(ctx, key, now, g.policy.Window)
+	if err != nil {
+		return err
+	}
+	if throttle.Failures < maxAttempts {
+		return nil
+	}
+	if err := g.store.LockLogin(ctx, key, now.Add(g.policy.Lockout)); err != nil {
+		return err
+	}
+	log.Printf("Проверка кодов 2FA пользователя %s заблокирована на %s после %d неверных кодов", userID, g.policy.Lockout, throttle.Failures)
+	return nil
+}
+
+// SecondFactorSuccess сбрасывает счетчик неверных кодов 2FA пользователя
+func (g *LoginGuard) SecondFactorSuccess(ctx context.Context, userID string) error {
+	return g.store.ResetLogin(ctx, secondFactorKey(userID))
+}
+
+// PurgeStale удаляет записи о попытках входа, вышедшие за окно учета
+func (g *LoginGuard) PurgeStale(ctx context.Context) (int64, error) {
+	return g.store.DeleteStaleLoginThrottles(ctx, time.Now().Add(-g.policy.Window))
+}
+
+// record сохраняет событие журнала. Ошибка журнала не должна мешать входу, поэтому только логируется.
+func (g *LoginGuard) record(ctx context.Context, event *models.AuthEvent) {
+	if err := g.audit.RecordAuthEvent(ctx, event); err != nil {
+		log.Printf("Не удалось записать событие %s в журнал аутентификации: %v", event.Event, err)
+	}
+}
@@ -0,0 +1,905 @@
+package usecase
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/oidc"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOAuthClientNotFound     = errors.New("клиент OAuth2 не найден")
+	ErrInvalidOAuthClientInput = errors.New("некорректные параметры клиента OAuth2")
+	ErrConsentNotFound         = errors.New("согласие клиенту OAuth2 не найдено")
+)
+
+// Коды ошибок OAuth2 (RFC 6749, разделы 4.1.2.1 и 5.2)
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthAccessDenied            = "access_denied"
+)
+
+// Разрешения OpenID Connect: openid выдает ID-токен, email и profile добавляют в него email и профиль.
+// Доступны, только если токены подписываются асимметричными ключами (RS256 или EdDSA).
+const (
+	ScopeOpenID  = "openid"
+	ScopeEmail   = "email"
+	ScopeProfile = "profile"
+)
+
+// maxOAuthClientNameLength - максимальная длина названия клиента
+const maxOAuthClientNameLength = 100
+
+// minServiceClientSecretLength - минимальная длина секрета, заданного клиенту в окружении
+const minServiceClientSecretLength = 32
+
+// OAuthError - ошибка протокола OAuth2: Code и Description передаются клиенту в полях error и error_description
+type OAuthError struct {
+	Code        string
+	Description string
+	// RedirectURI - проверенный адрес клиента, на который ошибку нужно вернуть перенаправлением.
+	// Пуст, если клиенту или адресу нельзя доверять: тогда ошибка показывается пользователю.
+	RedirectURI string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// oauthError создает ошибку OAuth2 с кодом code
+func oauthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// OAuthConfig задает параметры сервера авторизации OAuth2
+type OAuthConfig struct {
+	// Issuer - внешний адрес user-service (например, https://auth.example.com): идентификатор
+	// сервера в ID-токенах и основа адресов в документе discovery
+	Issuer  string
+	CodeTTL time.Duration // Срок действия кода авторизации
+}
+
+// OAuthClientInput - параметры регистрируемого клиента
+type OAuthClientInput struct {
+	Name         string
+	RedirectURIs []string
+	GrantTypes   []string
+	Scopes       []string
+	Confidential bool // Серверное приложение, способное хранить секрет
+}
+
+// AuthorizationRequest - параметры запроса авторизации (RFC 6749, раздел 4.1.1; RFC 7636)
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string // Разрешения через пробел; пусто - все разрешения клиента
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+// AuthorizationPrompt - проверенный запрос авторизации, который нужно показать пользователю
+type AuthorizationPrompt struct {
+	Client          *models.OAuthClient
+	Scopes          []string
+	ConsentRequired bool // false, если пользователь уже разрешил клиенту все запрошенные разрешения
+}
+
+// ClientAuthentication - учетные данные клиента из заголовка Authorization (client_secret_basic)
+// или тела запроса (client_secret_post). У публичного клиента секрет пуст.
+type ClientAuthentication struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// TokenRequest - параметры запроса к конечной точке токенов (RFC 6749, разделы 4.1.3, 4.4.2 и 6)
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+// OAuthTokens - ответ конечной точки токенов
+type OAuthTokens struct {
+	AccessToken  string
+	RefreshToken string // Пусто, если клиенту не разрешен refresh_token
+	IDToken      string // Пусто, если не запрошено разрешение openid
+	ExpiresIn    time.Duration
+	Scopes       []string
+}
+
+// TokenIntrospection - сведения о токене (RFC 7662). Для недействительного токена заполнено только Active.
+type TokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"` // access_token или refresh_token
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	JTI       string `json:"jti,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+}
+
+// OAuthServerMetadata - документ discovery сервера авторизации (RFC 8414, OpenID Connect Discovery 1.0)
+type OAuthServerMetadata struct {
+	Issuer                                    string   `json:"issuer"`
+	AuthorizationEndpoint                     string   `json:"authorization_endpoint"`
+	TokenEndpoint                             string   `json:"token_endpoint"`
+	IntrospectionEndpoint                     string   `json:"introspection_endpoint"`
+	RevocationEndpoint                        string   `json:"revocation_endpoint"`
+	JWKSURI                                   string   `json:"jwks_uri"`
+	ScopesSupported                           []string `json:"scopes_supported"`
+	ResponseTypesSupported                    []string `json:"response_types_supported"`
+	GrantTypesSupported                       []string `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported             []string `json:"code_challenge_methods_supported"`
+	TokenEndpointAuthMethodsSupported         []string `json:"token_endpoint_auth_methods_supported"`
+	IntrospectionEndpointAuthMethodsSupported []string `json:"introspection_endpoint_auth_methods_supported"`
+	RevocationEndpointAuthMethodsSupported    []string `json:"revocation_endpoint_auth_methods_supported"`
+	AuthorizationResponseISSParameter         bool     `json:"authorization_response_iss_parameter_supported"`
+	SubjectTypesSupported                     []string `json:"subject_types_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported          []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	ClaimsSupported                           []string `json:"claims_supported,omitempty"`
+}
+
+// OAuthUsecase определяет интерфейс сервера авторизации OAuth2 для собственных приложений:
+// authorization code с PKCE, client credentials, обновление, проверка и отзыв токенов.
+// Токены доступа - те же JWT, что и при входе, но с client_id и разрешениями, которые проверяют другие сервисы.
+type OAuthUsecase interface {
+	// CreateClient регистрирует клиента и возвращает его вместе с секретом (только для конфиденциального клиента).
+	// Секрет показывается только здесь: хранится лишь его хеш.
+	CreateClient(ctx context.Context, input OAuthClientInput) (*models.OAuthClient, string, error)
+	// EnsureClient регистрирует конфиденциального клиента с заданными ID и секретом или обновляет существующего.
+	// Так сервисы получают учетные данные из окружения, а не из ответа CreateClient.
+	EnsureClient(ctx context.Context, id, secret string, input OAuthClientInput) (*models.OAuthClient, error)
+	// ListClients возвращает зарегистрированных клиентов
+	ListClients(ctx context.Context) ([]*models.OAuthClient, error)
+	// GetClient возвращает клиента по ID
+	GetClient(ctx context.Context, id string) (*models.OAuthClient, error)
+	// DeleteClient удаляет клиента вместе с согласиями и токенами обновления.
+	// Выданные токены доступа действуют до истечения срока.
+	DeleteClient(ctx context.Context, id string) error
+
+	// Authorize проверяет запрос авторизации от имени вошедшего пользователя userID
+	// и сообщает, нужно ли спрашивать его согласие. Ошибки - *OAuthError.
+	Authorize(ctx context.Context, userID string, req AuthorizationRequest) (*AuthorizationPrompt, error)
+	// Decide завершает запрос авторизации решением пользователя и возвращает адрес перенаправления клиенту:
+	// с кодом авторизации, если доступ разрешен, или с ошибкой access_denied
+	Decide(ctx context.Context, userID string, req AuthorizationRequest, approved bool) (string, error)
+	// Token выдает токены клиенту по коду авторизации, токену обновления или учетным данным клиента.
+	// Ошибки - *OAuthError.
+	Token(ctx context.Context, client ClientAuthentication, req TokenRequest) (*OAuthTokens, error)
+	// Introspect сообщает конфиденциальному клиенту, действителен ли токен, и возвращает его сведения
+	Introspect(ctx context.Context, client ClientAuthentication, token string) (*TokenIntrospection, error)
+	// Revoke отзывает токен доступа или семейство токена обновления, выданные клиенту.
+	// Неизвестный или чужой токен не считается ошибкой (RFC 7009).
+	Revoke(ctx context.Context, client ClientAuthentication, token string) error
+	// Metadata возвращает документ discovery
+	Metadata() *OAuthServerMetadata
+
+	// ListConsents возвращает согласия пользователя клиентам
+	ListConsents(ctx context.Context, userID string) ([]*models.OAuthConsent, error)
+	// RevokeConsent отзывает согласие вместе с токенами обновления клиента
+	RevokeConsent(ctx context.Context, userID, clientID, actorID string) error
+}
+
+type oauthUsecase struct {
+	userRepo    repository.UserRepository
+	oauthRepo   repository.OAuthRepository
+	tokenRepo   repository.TokenRepository
+	authUsecase AuthUsecase
+	jwtManager  JWTManager
+	audit       repository.AuditRepository
+	config      OAuthConfig
+}
+
+// NewOAuthUsecase создает новый экземпляр oauthUsecase.
+func NewOAuthUsecase(userRepo repository.UserRepository, oauthRepo repository.OAuthRepository, tokenRepo repository.TokenRepository,
+	authUsecase AuthUsecase, jm JWTManager, audit repository.AuditRepository, config OAuthConfig) OAuthUsecase {
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	return &oauthUsecase{
+		userRepo:    userRepo,
+		oauthRepo:   oauthRepo,
+		tokenRepo:   tokenRepo,
+		authUsecase: authUsecase,
+		jwtManager:  jm,
+		audit:       audit,
+		config:      config,
+	}
+}
+
+// CreateClient проверяет параметры клиента и генерирует секрет конфиденциальному клиенту.
+func (o *oauthUsecase) CreateClient(ctx context.Context, input OAuthClientInput) (*models.OAuthClient, string, error) {
+	client, err := o.newClient(input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var secret string
+	if client.Confidential {
+		if secret, err = generateToken(); err != nil {
+			return nil, "", err
+		}
+		client.SecretHash = hashToken(secret)
+	}
+
+	created, err := o.oauthRepo.CreateClient(ctx, client)
+	if err != nil {
+		return nil, "", err
+	}
+	return created, secret, nil
+}
+
+// EnsureClient проверяет параметры как CreateClient, но ID и секрет берет у вызывающего.
+func (o *oauthUsecase) EnsureClient(ctx context.Context, id, secret string, input OAuthClientInput) (*models.OAuthClient, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: client_id: нужен UUID", ErrInvalidOAuthClientInput)
+	}
+	if len(secret) < minServiceClientSecretLength {
+		return nil, fmt.Errorf("%w: client_secret: не короче %d символов", ErrInvalidOAuthClientInput, minServiceClientSecretLength)
+	}
+	input.Confidential = true
+	client, err := o.newClient(input)
+	if err != nil {
+		return nil, err
+	}
+	client.ID = id
+	client.SecretHash = hashToken(secret)
+	return o.oauthRepo.SaveClient(ctx, client)
+}
+
+// newClient проверяет параметры клиента и собирает модель без секрета.
+// Ошибки проверки оборачивают ErrInvalidOAuthClientInput.
+func (o *oauthUsecase) newClient(input OAuthClientInput) (*models.OAuthClient, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxOAuthClientNameLength {
+		return nil, fmt.Errorf("%w: name: от 1 до %d символов", ErrInvalidOAuthClientInput, maxOAuthClientNameLength)
+	}
+
+	grantTypes, err := uniqueValues("grant_types", input.GrantTypes,
+		[]string{models.GrantAuthorizationCode, models.GrantRefreshToken, models.GrantClientCredentials})
+	if err != nil {
+		return nil, err
+	}
+	client := &models.OAuthClient{Name: name, GrantTypes: grantTypes, Confidential: input.Confidential}
+	if client.AllowsGrant(models.GrantRefreshToken) && !client.AllowsGrant(models.GrantAuthorizationCode) {
+		return nil, fmt.Errorf("%w: grant_types: refresh_token выдается только вместе с authorization_code", ErrInvalidOAuthClientInput)
+	}
+	if client.AllowsGrant(models.GrantClientCredentials) && !client.Confidential {
+		return nil, fmt.Errorf("%w: grant_types: client_credentials доступен только конфиденциальному клиенту", ErrInvalidOAuthClientInput)
+	}
+
+	if client.Scopes, err = uniqueValues("scopes", input.Scopes, o.supportedScopes()); err != nil {
+		return nil, err
+	}
+
+	if !client.AllowsGrant(models.GrantAuthorizationCode) {
+		if len(input.RedirectURIs) > 0 {
+			return nil, fmt.Errorf("%w: redirect_uris: нужны только для authorization_code", ErrInvalidOAuthClientInput)
+		}
+		client.RedirectURIs = []string{}
+		return client, nil
+	}
+	if len(input.RedirectURIs) == 0 {
+		return nil, fmt.Errorf("%w: redirect_uris: укажите хотя бы один адрес возврата", ErrInvalidOAuthClientInput)
+	}
+	for _, raw := range input.RedirectURIs {
+		if err := validateRedirectURI(raw); err != nil {
+			return nil, fmt.Errorf("%w: redirect_uris: %q: %v", ErrInvalidOAuthClientInput, raw, err)
+		}
+		if !slices.Contains(client.RedirectURIs, raw) {
+			client.RedirectURIs = append(client.RedirectURIs, raw)
+		}
+	}
+	return client, nil
+}
+
+// uniqueValues проверяет, что values не пуст и состоит из значений known, и убирает повторы
+func uniqueValues(field string, values, known []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: %s: укажите хотя бы одно значение (%s)", ErrInvalidOAuthClientInput, field, strings.Join(known, ", "))
+	}
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !slices.Contains(known, value) {
+			return nil, fmt.Errorf("%w: %s: неизвестное значение %q", ErrInvalidOAuthClientInput, field, value)
+		}
+		if !slices.Contains(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+	return unique, nil
+}
+
+// validateRedirectURI проверяет адрес возврата: абсолютный URL без фрагмента (RFC 6749, раздел 3.1.2).
+// http допускается только для локального адреса (разработка, нативные приложения); собственные схемы
+// мобильных приложений (com.example.app:/callback) разрешены.
+func validateRedirectURI(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || !u.IsAbs() {
+		return errors.New("нужен абсолютный URL")
+	}
+	if u.Fragment != "" || strings.Contains(raw, "#") {
+		return errors.New("адрес не должен содержать фрагмент")
+	}
+	if u.Scheme == "http" {
+		switch u.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+		default:
+			return errors.New("http допускается только для localhost, используйте https")
+		}
+	}
+	return nil
+}
+
+// ListClients возвращает клиентов без хешей секретов.
+func (o *oauthUsecase) ListClients(ctx context.Context) ([]*models.OAuthClient, error) {
+	return o.oauthRepo.ListClients(ctx)
+}
+
+// GetClient возвращает клиента или ErrOAuthClientNotFound.
+func (o *oauthUsecase) GetClient(ctx context.Context, id string) (*models.OAuthClient, error) {
+	client, err := o.oauthRepo.GetClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, ErrOAuthClientNotFound
+	}
+	return client, nil
+}
+
+// DeleteClient удаляет клиента.
+func (o *oauthUsecase) DeleteClient(ctx context.Context, id string) error {
+	if err := o.oauthRepo.DeleteClient(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrOAuthClientNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Authorize проверяет запрос и сравнивает запрошенные разрешения с уже выданным согласием.
+func (o *oauthUsecase) Authorize(ctx context.Context, userID string, req AuthorizationRequest) (*AuthorizationPrompt, error) {
+	client, scopes, err := o.validateAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := o.oauthRepo.GetConsent(ctx, userID, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthorizationPrompt{
+		Client:          client,
+		Scopes:          scopes,
+		ConsentRequired: !consentCovers(consent, scopes),
+	}, nil
+}
+
+// Decide повторно проверяет запрос, запоминает согласие и выдает одноразовый код авторизации.
+func (o *oauthUsecase) Decide(ctx context.Context, userID string, req AuthorizationRequest, approved bool) (string, error) {
+	client, scopes, err := o.validateAuthorization(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if !approved {
+		return o.redirectURL(req.RedirectURI, url.Values{
+			"error":             {OAuthAccessDenied},
+			"error_description": {"пользователь отказал в доступе"},
+		}, req.State), nil
+	}
+
+	user, err := o.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+	if err := accountStatusError(user); err != nil {
+		return "", err
+	}
+
+	consent, err := o.oauthRepo.GetConsent(ctx, userID, client.ID)
+	if err != nil {
+		return "", err
+	}
+	if !consentCovers(consent, scopes) {
+		if err := o.oauthRepo.SaveConsent(ctx, userID, client.ID, scopes); err != nil {
+			return "", err
+		}
+		o.record(ctx, &models.AuthEvent{
+			Event:   models.AuthEventOAuthConsentGranted,
+			UserID:  user.ID,
+			Email:   user.Email,
+			Details: fmt.Sprintf("клиент %s (%s), разрешения %s", client.Name, client.ID, strings.Join(scopes, " ")),
+		})
+	}
+
+	code, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+	err = o.oauthRepo.CreateAuthorizationCode(ctx, &models.OAuthAuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+		ExpiresAt:     time.Now().Add(o.config.CodeTTL).UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return o.redirectURL(req.RedirectURI, url.Values{"code": {code}}, req.State), nil
+}
+
+// validateAuthorization проверяет запрос авторизации и возвращает клиента и запрошенные разрешения.
+// Пока клиент и адрес возврата не проверены, ошибка не содержит адреса перенаправления.
+func (o *oauthUsecase) validateAuthorization(ctx context.Context, req AuthorizationRequest) (*models.OAuthClient, []string, error) {
+	if req.ClientID == "" {
+		return nil, nil, oauthError(OAuthInvalidRequest, "не указан client_id")
+	}
+	client, err := o.oauthRepo.GetClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if client == nil {
+		return nil, nil, oauthError(OAuthInvalidRequest, "неизвестный client_id")
+	}
+	if req.RedirectURI == "" || !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return nil, nil, oauthError(OAuthInvalidRequest, "redirect_uri не зарегистрирован для клиента")
+	}
+
+	redirectError := func(code, description string) error {
+		return &OAuthError{Code: code, Description: description, RedirectURI: o.redirectURL(req.RedirectURI, url.Values{
+			"error":             {code},
+			"error_description": {description},
+		}, req.State)}
+	}
+	if req.ResponseType != "code" {
+		return nil, nil, redirectError(OAuthUnsupportedResponseType, "поддерживается только response_type=code")
+	}
+	if !client.AllowsGrant(models.GrantAuthorizationCode) {
+		return nil, nil, redirectError(OAuthUnauthorizedClient, "клиенту не разрешен authorization_code")
+	}
+	// PKCE обязателен для всех клиентов; code_challenge S256 - 43 символа base64url
+	if req.CodeChallengeMethod != "S256" || len(req.CodeChallenge) != 43 {
+		return nil, nil, redirectError(OAuthInvalidRequest, "требуется PKCE: code_challenge и code_challenge_method=S256")
+	}
+	if len(req.State) > 512 || len(req.Nonce) > 255 {
+		return nil, nil, redirectError(OAuthInvalidRequest, "слишком длинный state или nonce")
+	}
+
+	scopes, err := parseScopes(req.Scope, client.Scopes)
+	if err != nil {
+		var oauthErr *OAuthError
+		if errors.As(err, &oauthErr) {
+			return nil, nil, redirectError(oauthErr.Code, oauthErr.Description)
+		}
+		return nil, nil, err
+	}
+	return client, scopes, nil
+}
+
+// redirectURL добавляет к адресу возврата клиента параметры ответа, state и iss (RFC 9207)
+func (o *oauthUsecase) redirectURL(redirectURI string, params url.Values, state string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	query.Set("iss", o.config.Issuer)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// parseScopes разбирает разрешения, перечисленные через пробел. Пустая строка означает все разрешения allowed;
+// разрешение вне allowed - ошибка invalid_scope.
+func parseScopes(raw string, allowed []string) ([]string, error) {
+	requested := strings.Fields(raw)
+	if len(requested) == 0 {
+		return slices.Clone(allowed), nil
+	}
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		if !slices.Contains(allowed, scope) {
+			return nil, oauthError(OAuthInvalidScope, fmt.Sprintf("разрешение %q недоступно клиенту", scope))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// consentCovers сообщает, входят ли все разрешения scopes в выданное согласие
+func consentCovers(consent *models.OAuthConsent, scopes []string) bool {
+	if consent == nil {
+		return false
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(consent.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// Token аутентифицирует клиента и выдает токены способом grant_type.
+func (o *oauthUsecase) Token(ctx context.Context, credentials ClientAuthentication, req TokenRequest) (*OAuthTokens, error) {
+	client, err := o.authenticateClient(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case models.GrantAuthorizationCode:
+		return o.exchangeCode(ctx, client, req)
+	case models.GrantRefreshToken:
+		if !client.AllowsGrant(models.GrantRefreshToken) {
+			return nil, oauthError(OAuthUnauthorizedClient, "клиенту не разрешен refresh_token")
+		}
+		if req.RefreshToken == "" {
+			return nil, oauthError(OAuthInvalidRequest, "не указан refresh_token")
+		}
+		pair, err := o.authUsecase.RefreshClientTokens(ctx, req.RefreshToken, client.ID)
+		if err != nil {
+			if errors.Is(err, ErrInvalidRefreshToken) || errors.Is(err, ErrRefreshTokenReused) {
+				return nil, oauthError(OAuthInvalidGrant, err.Error())
+			}
+			return nil, err
+		}
+		return &OAuthTokens{AccessToken: pair.AccessToken, RefreshToken: pair.RefreshToken, ExpiresIn: pair.ExpiresIn, Scopes: pair.Scopes}, nil
+	case models.GrantClientCredentials:
+		return o.clientCredentials(client, req)
+	case "":
+		return nil, oauthError(OAuthInvalidRequest, "не указан grant_type")
+	default:
+		return nil, oauthError(OAuthUnsupportedGrantType, fmt.Sprintf("grant_type %q не поддерживается", req.GrantType))
+	}
+}
+
+// exchangeCode обменивает код авторизации на токены после проверки адреса возврата и verifier PKCE.
+func (o *oauthUsecase) exchangeCode(ctx context.Context, client *models.OAuthClient, req TokenRequest) (*OAuthTokens, error) {
+	if !client.AllowsGrant(models.GrantAuthorizationCode) {
+		return nil, oauthError(OAuthUnauthorizedClient, "клиенту не разрешен authorization_code")
+	}
+	if req.Code == "" || req.RedirectURI == "" || req.CodeVerifier == "" {
+		return nil, oauthError(OAuthInvalidRequest, "нужны code, redirect_uri и code_verifier")
+	}
+
+	// Код одноразовый: удаляется до остальных проверок
+	code, err := o.oauthRepo.ConsumeAuthorizationCode(ctx, hashToken(req.Code))
+	if err != nil {
+		return nil, err
+	}
+	if code == nil || code.ClientID != client.ID || !time.Now().Before(code.ExpiresAt) {
+		return nil, oauthError(OAuthInvalidGrant, "недействительный или истекший код авторизации")
+	}
+	if code.RedirectURI != req.RedirectURI {
+		return nil, oauthError(OAuthInvalidGrant, "redirect_uri не совпадает с запросом авторизации")
+	}
+	if len(req.CodeVerifier) < 43 || len(req.CodeVerifier) > 128 ||
+		subtle.ConstantTimeCompare([]byte(oidc.CodeChallenge(req.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, oauthError(OAuthInvalidGrant, "code_verifier не соответствует code_challenge")
+	}
+
+	user, err := o.userRepo.GetByID(ctx, code.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.Active() {
+		return nil, oauthError(OAuthInvalidGrant, "учетная запись пользователя недоступна")
+	}
+
+	pair, err := o.authUsecase.IssueClientTokens(ctx, user, client.ID, code.Scopes, client.AllowsGrant(models.GrantRefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	tokens := &OAuthTokens{AccessToken: pair.AccessToken, RefreshToken: pair.RefreshToken, ExpiresIn: pair.ExpiresIn, Scopes: pair.Scopes}
+	if slices.Contains(code.Scopes, ScopeOpenID) {
+		if tokens.IDToken, err = o.idToken(user, client.ID, code.Nonce, code.Scopes); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+// clientCredentials выдает конфиденциальному клиенту токен от его собственного имени: без пользователя и ролей,
+// только с разрешениями клиента. Токен обновления не выдается.
+func (o *oauthUsecase) clientCredentials(client *models.OAuthClient, req TokenRequest) (*OAuthTokens, error) {
+	if !client.Confidential || !client.AllowsGrant(models.GrantClientCredentials) {
+		return nil, oauthError(OAuthUnauthorizedClient, "клиенту не разрешен client_credentials")
+	}
+
+	allowed := make([]string, 0, len(client.Scopes))
+	for _, scope := range client.Scopes {
+		if _, ok := auth.NormalizeScope(scope); ok {
+			allowed = append(allowed, scope)
+		}
+	}
+	scopes, err := parseScopes(req.Scope, allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := o.jwtManager.GenerateClientToken(client.ID, nil, client.ID, scopes)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthTokens{AccessToken: accessToken, ExpiresIn: o.jwtManager.TokenDuration(), Scopes: scopes}, nil
+}
+
+// idTokenClaims - содержимое ID-токена OpenID Connect
+type idTokenClaims struct {
+	Nonce             string `json:"nonce,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Locale            string `json:"locale,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// idToken выпускает ID-токен для клиента clientID. email и profile добавляют соответствующие утверждения.
+func (o *oauthUsecase) idToken(user *models.User, clientID, nonce string, scopes []string) (string, error) {
+	now := time.Now()
+	claims := idTokenClaims{
+		Nonce: nonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    o.config.Issuer,
+			Subject:   user.ID,
+			Audience:  jwt.ClaimStrings{clientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(o.jwtManager.TokenDuration())),
+		},
+	}
+	if slices.Contains(scopes, ScopeEmail) {
+		verified := user.EmailVerified()
+		claims.Email, claims.EmailVerified = user.Email, &verified
+	}
+	if slices.Contains(scopes, ScopeProfile) {
+		claims.Name, claims.PreferredUsername, claims.Locale = user.FullName, user.Username, user.Locale
+	}
+	return o.jwtManager.Sign(claims)
+}
+
+// authenticateClient находит клиента и проверяет его секрет. Публичный клиент передает только client_id.
+func (o *oauthUsecase) authenticateClient(ctx context.Context, credentials ClientAuthentication) (*models.OAuthClient, error) {
+	if credentials.ClientID == "" {
+		return nil, oauthError(OAuthInvalidClient, "не указан client_id")
+	}
+	client, err := o.oauthRepo.GetClient(ctx, credentials.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, oauthError(OAuthInvalidClient, "неизвестный клиент")
+	}
+
+	if !client.Confidential {
+		if credentials.ClientSecret != "" {
+			return nil, oauthError(OAuthInvalidClient, "у публичного клиента нет секрета")
+		}
+		return client, nil
+	}
+	if credentials.ClientSecret == "" ||
+		subtle.ConstantTimeCompare([]byte(hashToken(credentials.ClientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, oauthError(OAuthInvalidClient, "неверный секрет клиента")
+	}
+	return client, nil
+}
+
+// Introspect проверяет токен доступа (подпись, срок, отзыв) или токен обновления, выданный этому клиенту.
+func (o *oauthUsecase) Introspect(ctx context.Context, credentials ClientAuthentication, token string) (*TokenIntrospection, error) {
+	client, err := o.authenticateClient(ctx, credentials)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Confidential {
+		return nil, oauthError(OAuthInvalidClient, "проверка токенов доступна только конфиденциальным клиентам")
+	}
+	if token == "" {
+		return nil, oauthError(OAuthInvalidRequest, "не указан token")
+	}
+
+	if claims, err := o.jwtManager.ValidateToken(token); err == nil {
+		introspection := &TokenIntrospection{
+			Active:    true,
+			Scope:     strings.Join(claims.Scopes, " "),
+			ClientID:  claims.ClientID,
+			Subject:   claims.UserID,
+			TokenType: "access_token",
+			JTI:       claims.ID,
+			Issuer:    o.config.Issuer,
+		}
+		if claims.ExpiresAt != nil {
+			introspection.ExpiresAt = claims.ExpiresAt.Unix()
+		}
+		if claims.IssuedAt != nil {
+			introspection.IssuedAt = claims.IssuedAt.Unix()
+		}
+		return introspection, nil
+	}
+
+	record, err := o.tokenRepo.GetRefreshTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.ClientID != client.ID || record.UsedAt != nil || record.RevokedAt != nil ||
+		!time.Now().Before(record.ExpiresAt) {
+		return &TokenIntrospection{Active: false}, nil
+	}
+	return &TokenIntrospection{
+		Active:    true,
+		Scope:     strings.Join(record.Scopes, " "),
+		ClientID:  record.ClientID,
+		Subject:   record.UserID,
+		TokenType: "refresh_token",
+		ExpiresAt: record.ExpiresAt.Unix(),
+		IssuedAt:  record.CreatedAt.Unix(),
+		Issuer:    o.config.Issuer,
+	}, nil
+}
+
+// Revoke отзывает токен доступа по jti или семейство токена обновления.
+func (o *oauthUsecase) Revoke(ctx context.Context, credentials ClientAuthentication, token string) error {
+	client, err := o.authenticateClient(ctx, credentials)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return oauthError(OAuthInvalidRequest, "не указан token")
+	}
+
+	if claims, err := o.jwtManager.ValidateToken(token); err == nil {
+		if claims.ClientID != client.ID || claims.ID == "" || claims.ExpiresAt == nil {
+			return nil
+		}
+		return o.tokenRepo.RevokeAccessToken(ctx, claims.ID, claims.ExpiresAt.Time)
+	}
+
+	record, err := o.tokenRepo.GetRefreshTokenByHash(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	if record == nil || record.ClientID != client.ID {
+		return nil
+	}
+	return o.tokenRepo.RevokeTokenFamily(ctx, record.FamilyID)
+}
+
+// Metadata собирает документ discovery из адреса Issuer.
+func (o *oauthUsecase) Metadata() *OAuthServerMetadata {
+	metadata := &OAuthServerMetadata{
+		Issuer:                            o.config.Issuer,
+		AuthorizationEndpoint:             o.config.Issuer + "/oauth/authorize",
+		TokenEndpoint:                     o.config.Issuer + "/oauth/token",
+		IntrospectionEndpoint:             o.config.Issuer + "/oauth/introspect",
+		RevocationEndpoint:                o.config.Issuer + "/oauth/revoke",
+		JWKSURI:                           o.config.Issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   o.supportedScopes(),
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{models.GrantAuthorizationCode, models.GrantRefreshToken, models.GrantClientCredentials},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		IntrospectionEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		RevocationEndpointAuthMethodsSupported:    []string{"client_secret_basic", "client_secret_post", "none"},
+		AuthorizationResponseISSParameter:         true,
+	}
+	if algorithms := o.idTokenAlgorithms(); len(algorithms) > 0 {
+		metadata.SubjectTypesSupported = []string{"public"}
+		metadata.IDTokenSigningAlgValuesSupported = algorithms
+		metadata.ClaimsSupported = []string{"iss", "sub", "aud", "exp", "iat", "nonce",
+			"email", "email_verified", "name", "preferred_username", "locale"}
+	}
+	return metadata
+}
+
+// supportedScopes возвращает разрешения, которые можно выдать клиентам.
+// Разрешения OpenID Connect доступны, только если ID-токены можно проверить по JWKS.
+func (o *oauthUsecase) supportedScopes() []string {
+	scopes := slices.Clone(auth.Scopes)
+	if len(o.idTokenAlgorithms()) > 0 {
+		scopes = append(scopes, ScopeOpenID, ScopeEmail, ScopeProfile)
+	}
+	return scopes
+}
+
+// idTokenAlgorithms возвращает алгоритмы опубликованных ключей подписи.
+// Для HS256 набор пуст: ID-токен, подписанный общим секретом, клиент проверить не сможет.
+func (o *oauthUsecase) idTokenAlgorithms() []string {
+	set, err := o.jwtManager.JWKS()
+	if err != nil {
+		return nil
+	}
+	var algorithms []string
+	for _, key := range set.Keys {
+		if key.Alg != "" && !slices.Contains(algorithms, key.Alg) {
+			algorithms = append(algorithms, key.Alg)
+		}
+	}
+	return algorithms
+}
+
+// ListConsents возвращает согласия существующего пользователя.
+func (o *oauthUsecase) ListConsents(ctx context.Context, userID string) ([]*models.OAuthConsent, error) {
+	user, err := o.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return o.oauthRepo.ListConsents(ctx, userID)
+}
+
+// RevokeConsent отзывает согласие. Выданные клиенту токены доступа действуют до истечения срока,
+// но обновить их клиент уже не сможет.
+func (o *oauthUsecase) RevokeConsent(ctx context.Context, userID, clientID, actorID string) error {
+	user, err := o.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if err := o.oauthRepo.DeleteConsent(ctx, userID, clientID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrConsentNotFound
+		}
+		return err
+	}
+
+	o.record(ctx, &models.AuthEvent{
+		Event:   models.AuthEventOAuthConsentRevoked,
+		UserID:  userID,
+		Email:   user.Email,
+		Details: fmt.Sprintf("клиент %s, инициатор %s", clientID, actorID),
+	})
+	return nil
+}
+
+// record сохраняет событие журнала. Ошибка журнала не отменяет уже выполненное действие, поэтому только логируется.
+func (o *oauthUsecase) record(ctx context.Context, event *models.AuthEvent) {
+	if err := o.audit.RecordAuthEvent(ctx, event); err != nil {
+		log.Printf("Не удалось записать событие %s в журнал аутентификации: %v", event.Event, err)
+	}
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/oidc"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+)
+
+var (
+	ErrOIDCProviderNotFound    = errors.New("провайдер OpenID Connect не настроен")
+	ErrOIDCProviderUnavailable = errors.New("провайдер OpenID Connect недоступен")
+	ErrInvalidOIDCState        = errors.New("недействительный или истекший state входа через провайдер")
+	ErrOIDCAuthentication      = errors.New("провайдер не подтвердил вход")
+	ErrOIDCEmailNotVerified    = errors.New("провайдер не подтвердил email пользователя")
+	ErrOIDCAccountNotFound     = errors.New("нет учетной записи с email провайдера: зарегистрируйтесь, чтобы входить через провайдер")
+	ErrOIDCLinkNotAllowed      = errors.New("email учетной записи не подтвержден: подтвердите его, чтобы входить через провайдер")
+	ErrIdentityConflict        = errors.New("к учетной записи уже привязан другой пользователь этого провайдера")
+	ErrIdentityNotFound        = errors.New("учетная запись провайдера не привязана")
+)
+
+// OIDCProvider - внешний провайдер OpenID Connect (реализуется *oidc.Provider)
+type OIDCProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Authenticate(ctx context.Context, code, verifier, nonce string) (*oidc.Identity, error)
+}
+
+// OIDCConfig задает параметры входа через внешних провайдеров
+type OIDCConfig struct {
+	StateTTL time.Duration // Сколько ждать возврата пользователя от провайдера
+}
+
+// OIDCAuthorization - начатый вход: пользователя нужно отправить на URL провайдера,
+// а затем передать полученные code и state в CompleteLogin
+type OIDCAuthorization struct {
+	URL       string
+	State     string
+	ExpiresAt time.Time
+}
+
+// OIDCUsecase определяет интерфейс входа через внешних провайдеров OpenID Connect
+// (authorization code flow с PKCE) и привязки их учетных записей к пользователям.
+type OIDCUsecase interface {
+	// Providers возвращает имена настроенных провайдеров
+	Providers() []string
+	// StartLogin начинает вход через провайдер и возвращает адрес его страницы входа
+	StartLogin(ctx context.Context, provider string) (*OIDCAuthorization, error)
+	// CompleteLogin обменивает код на ID-токен и входит от имени привязанного пользователя.
+	// Учетная запись провайдера без привязки привязывается к пользователю с тем же email,
+	// если email подтвержден и провайдером, и у нас.
+	CompleteLogin(ctx context.Context, provider, code, state, clientIP string) (*LoginResult, error)
+	// ListIdentities возвращает привязанные учетные записи провайдеров
+	ListIdentities(ctx context.Context, userID string) ([]*models.ExternalIdentity, error)
+	// UnlinkIdentity отвязывает учетную запись провайдера
+	UnlinkIdentity(ctx context.Context, userID, provider, actorID string) error
+}
+
+type oidcUsecase struct {
+	userRepo     repository.UserRepository
+	identityRepo repository.ExternalIdentityRepository
+	authUsecase  AuthUsecase
+	audit        repository.AuditRepository
+	providers    map[string]OIDCProvider
+	config       OIDCConfig
+}
+
+// NewOIDCUsecase создает новый экземпляр oidcUsecase.
+func NewOIDCUsecase(userRepo repository.UserRepository, identityRepo repository.ExternalIdentityRepository, authUsecase AuthUsecase,
+	audit repository.AuditRepository, providers []OIDCProvider, config OIDCConfig) OIDCUsecase {
+	byName := make(map[string]OIDCProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &oidcUsecase{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		authUsecase:  authUsecase,
+		audit:        audit,
+		providers:    byName,
+		config:       config,
+	}
+}
+
+// Providers возвращает имена провайдеров в алфавитном порядке.
+func (o *oidcUsecase) Providers() []string {
+	names := make([]string, 0, len(o.providers))
+	for name := range o.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// StartLogin генерирует state, nonce и verifier PKCE и запоминает их до возврата пользователя.
+func (o *oidcUsecase) StartLogin(ctx context.Context, providerName string) (*OIDCAuthorization, error) {
+	provider, ok := o.providers[providerName]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	var secrets [3]string
+	for i := range secrets {
+		s, err := oidc.NewRandomString()
+		if err != nil {
+			return nil, err
+		}
+		secrets[i] = s
+	}
+	state, nonce, verifier := secrets[0], secrets[1], secrets[2]
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+
+	expiresAt := time.Now().Add(o.config.StateTTL).UTC()
+	err = o.identityRepo.CreateLoginState(ctx, &models.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OIDCAuthorization{URL: authURL, State: state, ExpiresAt: expiresAt}, nil
+}
+
+// CompleteLogin проверяет state, подтверждает пользователя у провайдера и завершает вход.
+func (o *oidcUsecase) CompleteLogin(ctx context.Context, providerName, code, state, clientIP string) (*LoginResult, error) {
+	provider, ok := o.providers[providerName]
+	if !ok {
+		return nil, ErrOIDCProviderNotFound
+	}
+
+	// state одноразовый: удаляется до обращения к провайдеру
+	loginState, err := o.identityRepo.ConsumeLoginState(ctx, hashToken(state))
+	if err != nil {
+		return nil, err
+	}
+	if loginState == nil || loginState.Provider != providerName || !time.Now().Before(loginState.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	identity, err := provider.Authenticate(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrTokenExchange) || errors.Is(err, oidc.ErrInvalidIDToken) {
+			return nil, fmt.Errorf("%w: %v", ErrOIDCAuthentication, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+
+	user, err := o.identityUser(ctx, providerName, identity)
+	if err != nil {
+		return nil, err
+	}
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	if err := o.identityRepo.TouchIdentity(ctx, providerName, identity.Subject, time.Now()); err != nil {
+		log.Printf("Не удалось отметить вход через провайдер %s: %v", providerName, err)
+	}
+	o.record(ctx, &models.AuthEvent{
+		Event:   models.AuthEventLoginSucceeded,
+		UserID:  user.ID,
+		Email:   user.Email,
+		IP:      clientIP,
+		Details: "через провайдер " + providerName,
+	})
+
+	user.Password = ""
+	return o.authUsecase.LoginExternal(ctx, user)
+}
+
+// identityUser возвращает пользователя, к которому привязана учетная запись провайдера,
+// а если привязки нет - привязывает ее к пользователю с тем же подтвержденным email.
+func (o *oidcUsecase) identityUser(ctx context.Context, providerName string, identity *oidc.Identity) (*models.User, error) {
+	linked, err := o.identityRepo.GetIdentity(ctx, providerName, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if linked != nil {
+		user, err := o.userRepo.GetByID(ctx, linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, ErrOIDCAccountNotFound
+		}
+		return user, nil
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+	user, err := o.userRepo.GetByEmail(ctx, identity.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrOIDCAccountNotFound
+	}
+	// Иначе чужой человек мог бы заранее зарегистрировать учетную запись с этим email
+	// и получить доступ к ней вместе с владельцем email
+	if !user.EmailVerified() {
+		return nil, ErrOIDCLinkNotAllowed
+	}
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	_, err = o.identityRepo.LinkIdentity(ctx, &models.ExternalIdentity{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		UserID:   user.ID,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrIdentityConflict) {
+			return nil, ErrIdentityConflict
+		}
+		return nil, err
+	}
+	o.record(ctx, &models.AuthEvent{
+		Event:   models.AuthEventIdentityLinked,
+		UserID:  user.ID,
+		Email:   user.Email,
+		Details: fmt.Sprintf("провайдер %s, sub %s", providerName, identity.Subject),
+	})
+	return user, nil
+}
+
+// ListIdentities возвращает привязки существующего пользователя.
+func (o *oidcUsecase) ListIdentities(ctx context.Context, userID string) ([]*models.ExternalIdentity, error) {
+	user, err := o.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return o.identityRepo.ListIdentities(ctx, userID)
+}
+
+// UnlinkIdentity удаляет привязку. Вход паролем после этого по-прежнему доступен.
+func (o *oidcUsecase) UnlinkIdentity(ctx context.Context, userID, providerName, actorID string) error {
+	user, err := o.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if err := o.identityRepo.DeleteIdentity(ctx, userID, providerName); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrIdentityNotFound
+		}
+		return err
+	}
+
+	o.record(ctx, &models.AuthEvent{
+		Event:   models.AuthEventIdentityUnlinked,
+		UserID:  userID,
+		Email:   user.Email,
+		Details: fmt.Sprintf("провайдер %s, инициатор %s", providerName, actorID),
+	})
+	return nil
+}
+
+// record сохраняет событие журнала. Ошибка журнала не отменяет уже выполненное действие, поэтому только логируется.
+func (o *oidcUsecase) record(ctx context.Context, event *models.AuthEvent) {
+	if err := o.audit.RecordAuthEvent(ctx, event); err != nil {
+		log.Printf("Не удалось записать событие %s в журнал аутентификации: %v", event.Event, err)
+	}
+}
@@ -0,0 +1,397 @@
+package usecase
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrPasswordTooWeak       = errors.New("пароль не содержит обязательных классов символов")
+	ErrPasswordBreached      = errors.New("пароль встречается в утечках, выберите другой")
+	ErrPasswordMismatch      = errors.New("пароль не совпадает с хешем")
+	ErrUnknownPasswordHash   = errors.New("неизвестный формат хеша пароля")
+	ErrMalformedPasswordHash = errors.New("некорректный хеш пароля")
+)
+
+// IsPasswordPolicyError сообщает, что пароль отклонен политикой паролей
+// (ErrPasswordTooShort, ErrPasswordTooWeak или ErrPasswordBreached).
+func IsPasswordPolicyError(err error) bool {
+	return errors.Is(err, ErrPasswordTooShort) || errors.Is(err, ErrPasswordTooWeak) || errors.Is(err, ErrPasswordBreached)
+}
+
+// PasswordHasher определяет интерфейс для хеширования и проверки паролей.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hashedPassword, password string) error
+	// NeedsRehash сообщает, что хеш создан другим алгоритмом или с устаревшими параметрами
+	// и его стоит пересчитать при следующем успешном входе
+	NeedsRehash(hashedPassword string) bool
+	// Recognizes сообщает, что хеш записан в формате этого алгоритма
+	Recognizes(hashedPassword string) bool
+}
+
+// BcryptPasswordHasher реализует PasswordHasher с использованием bcrypt.
+type BcryptPasswordHasher struct {
+	cost int // Стоимость хеширования для bcrypt (например, bcrypt.DefaultCost)
+}
+
+// NewBcryptPasswordHasher создает новый экземпляр BcryptPasswordHasher.
+// Рекомендуемая стоимость cost: bcrypt.DefaultCost (обычно 10) или выше.
+func NewBcryptPasswordHasher(cost int) PasswordHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptPasswordHasher{cost: cost}
+}
+
+// Hash генерирует хеш bcrypt для заданного пароля.
+// Требования к паролю проверяет PasswordValidator до хеширования.
+func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(bytes), err
+}
+
+// Compare сравнивает хеш bcrypt с паролем.
+func (h *BcryptPasswordHasher) Compare(hashedPassword, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+}
+
+// NeedsRehash возвращает true, если хеш не bcrypt или его стоимость отличается от текущей.
+func (h *BcryptPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err != nil || cost != h.cost
+}
+
+// Recognizes сообщает, что хеш записан в формате bcrypt ($2a$, $2b$ или $2y$).
+func (h *BcryptPasswordHasher) Recognizes(hashedPassword string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(hashedPassword, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Argon2Params - параметры Argon2id. Memory задается в КиБ.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Допустимые границы параметров Argon2id. Параметры читаются из хеша в базе, поэтому без проверки
+// испорченный хеш мог бы уронить процесс (p=0) или занять при входе гигабайты памяти (большое m).
+const (
+	maxArgon2Memory      = 1024 * 1024 // 1 ГиБ в КиБ
+	maxArgon2Iterations  = 32
+	maxArgon2Parallelism = 16
+	minArgon2SaltLength  = 8
+	maxArgon2SaltLength  = 64
+	minArgon2KeyLength   = 16
+	maxArgon2KeyLength   = 64
+)
+
+// Validate проверяет, что параметры находятся в допустимых границах: p от 1 до 16, t от 1 до 32,
+// m от 8*p КиБ (минимум RFC 9106) до 1 ГиБ, соль от 8 до 64 байт и ключ от 16 до 64 байт.
+func (p Argon2Params) Validate() error {
+	switch {
+	case p.Parallelism < 1 || p.Parallelism > maxArgon2Parallelism:
+		return fmt.Errorf("параллелизм Argon2id должен быть от 1 до %d", maxArgon2Parallelism)
+	case p.Iterations < 1 || p.Iterations > maxArgon2Iterations:
+		return fmt.Errorf("число проходов Argon2id должно быть от 1 до %d", maxArgon2Iterations)
+	case p.Memory < 8*uint32(p.Parallelism) || p.Memory > maxArgon2Memory:
+		return fmt.Errorf("память Argon2id должна быть от %d до %d КиБ", 8*uint32(p.Parallelism), maxArgon2Memory)
+	case p.SaltLength < minArgon2SaltLength || p.SaltLength > maxArgon2SaltLength:
+		return fmt.Errorf("длина соли Argon2id должна быть от %d до %d байт", minArgon2SaltLength, maxArgon2SaltLength)
+	case p.KeyLength < minArgon2KeyLength || p.KeyLength > maxArgon2KeyLength:
+		return fmt.Errorf("длина ключа Argon2id должна быть от %d до %d байт", minArgon2KeyLength, maxArgon2KeyLength)
+	}
+	return nil
+}
+
+// DefaultArgon2Params возвращает параметры Argon2id по рекомендации RFC 9106
+// для систем с ограниченной памятью: 64 МиБ, 3 прохода.
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// argon2Prefix - начало строки Argon2id в формате PHC
+const argon2Prefix = "$argon2id$"
+
+// Argon2idPasswordHasher реализует PasswordHasher с использованием Argon2id.
+// Хеш хранится в формате PHC: $argon2id$v=19$m=65536,t=3,p=2$<соль>$<хеш>.
+type Argon2idPasswordHasher struct {
+	params Argon2Params
+}
+
+// NewArgon2idPasswordHasher создает новый экземпляр Argon2idPasswordHasher.
+func NewArgon2idPasswordHasher(params Argon2Params) PasswordHasher {
+	return &Argon2idPasswordHasher{params: params}
+}
+
+// Hash вычисляет хеш Argon2id со случайной солью.
+func (h *Argon2idPasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2Prefix, argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Compare сравнивает хеш Argon2id с паролем, используя параметры из самого хеша.
+func (h *Argon2idPasswordHasher) Compare(hashedPassword, password string) error {
+	params, salt, key, err := parseArgon2Hash(hashedPassword)
+	if err != nil {
+		return err
+	}
+	actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// NeedsRehash возвращает true, если хеш не Argon2id или вычислен с другими параметрами.
+func (h *Argon2idPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	params, _, _, err := parseArgon2Hash(hashedPassword)
+	if err != nil {
+		return true
+	}
+	return params != h.params
+}
+
+// Recognizes сообщает, что хеш записан в формате Argon2id.
+func (h *Argon2idPasswordHasher) Recognizes(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, argon2Prefix)
+}
+
+// parseArgon2Hash разбирает строку PHC и возвращает параметры, соль и хеш.
+// Возвращает ErrMalformedPasswordHash, если строка некорректна или параметры вне допустимых границ (Argon2Params.Validate).
+func parseArgon2Hash(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", соль, хеш
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrMalformedPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrMalformedPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrMalformedPasswordHash
+	}
+	// Sscanf не проверяет остаток строки, поэтому сверяем ее с канонической записью параметров
+	if parts[3] != fmt.Sprintf("m=%d,t=%d,p=%d", params.Memory, params.Iterations, params.Parallelism) {
+		return params, nil, nil, ErrMalformedPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return params, nil, nil, ErrMalformedPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrMalformedPasswordHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	if err := params.Validate(); err != nil {
+		return params, nil, nil, fmt.Errorf("%w: %v", ErrMalformedPasswordHash, err)
+	}
+	return params, salt, key, nil
+}
+
+// MultiPasswordHasher хеширует новые пароли основным алгоритмом и проверяет хеши
+// любого из известных алгоритмов. Хеши других алгоритмов или с устаревшими параметрами
+// помечаются для пересчета (NeedsRehash).
+type MultiPasswordHasher struct {
+	primary PasswordHasher
+	hashers []PasswordHasher
+}
+
+// NewMultiPasswordHasher создает MultiPasswordHasher. primary используется для новых хешей
+// и всегда участвует в проверке; legacy - алгоритмы, хеши которых еще могут храниться в базе.
+func NewMultiPasswordHasher(primary PasswordHasher, legacy ...PasswordHasher) PasswordHasher {
+	return &MultiPasswordHasher{
+		primary: primary,
+		hashers: append([]PasswordHasher{primary}, legacy...),
+	}
+}
+
+// Hash вычисляет хеш основным алгоритмом.
+func (h *MultiPasswordHasher) Hash(password string) (string, error) {
+	return h.primary.Hash(password)
+}
+
+// Compare проверяет пароль алгоритмом, которым создан хеш.
+func (h *MultiPasswordHasher) Compare(hashedPassword, password string) error {
+	for _, hasher := range h.hashers {
+		if hasher.Recognizes(hashedPassword) {
+			return hasher.Compare(hashedPassword, password)
+		}
+	}
+	return ErrUnknownPasswordHash
+}
+
+// NeedsRehash возвращает true, если хеш создан не основным алгоритмом или с другими параметрами.
+func (h *MultiPasswordHasher) NeedsRehash(hashedPassword string) bool {
+	return h.primary.NeedsRehash(hashedPassword)
+}
+
+// Recognizes сообщает, что хеш создан одним из известных алгоритмов.
+func (h *MultiPasswordHasher) Recognizes(hashedPassword string) bool {
+	for _, hasher := range h.hashers {
+		if hasher.Recognizes(hashedPassword) {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordValidator проверяет новый пароль перед сохранением.
+type PasswordValidator interface {
+	Validate(password string) error
+}
+
+// Классы символов для PasswordPolicy.RequiredClasses
+const (
+	CharClassLower  = "lower"
+	CharClassUpper  = "upper"
+	CharClassDigit  = "digit"
+	CharClassSymbol = "symbol"
+)
+
+// charClassNames - названия классов символов для сообщений об ошибках
+var charClassNames = map[string]string{
+	CharClassLower:  "строчные буквы",
+	CharClassUpper:  "заглавные буквы",
+	CharClassDigit:  "цифры",
+	CharClassSymbol: "спецсимволы",
+}
+
+// ParseCharClasses разбирает список классов символов через запятую ("lower,upper,digit,symbol").
+func ParseCharClasses(value string) ([]string, error) {
+	var classes []string
+	for _, class := range strings.Split(value, ",") {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if class == "" {
+			continue
+		}
+		if _, ok := charClassNames[class]; !ok {
+			return nil, fmt.Errorf("неизвестный класс символов: %q", class)
+		}
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+// PasswordPolicy - настраиваемая политика паролей.
+// Проверяется только при установке пароля: вход с паролем, заданным по прежней политике, не запрещается.
+type PasswordPolicy struct {
+	MinLength       int      // Минимальная длина в символах (не байтах)
+	RequiredClasses []string // Классы символов, которые должны встречаться в пароле (CharClass*)
+	// Breached - пароли из утечек в нижнем регистре (LoadBreachedPasswords)
+	Breached map[string]struct{}
+}
+
+// DefaultPasswordPolicy возвращает политику по умолчанию: не короче 8 символов.
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{MinLength: 8}
+}
+
+// Validate проверяет пароль по политике. Ошибка оборачивает ErrPasswordTooShort,
+// ErrPasswordTooWeak или ErrPasswordBreached и содержит подробности.
+func (p *PasswordPolicy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("%w: минимум %d символов", ErrPasswordTooShort, p.MinLength)
+	}
+
+	var missing []string
+	for _, class := range p.RequiredClasses {
+		if !containsCharClass(password, class) {
+			missing = append(missing, charClassNames[class])
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: добавьте %s", ErrPasswordTooWeak, strings.Join(missing, ", "))
+	}
+
+	if _, ok := p.Breached[strings.ToLower(password)]; ok {
+		return ErrPasswordBreached
+	}
+	return nil
+}
+
+// containsCharClass сообщает, есть ли в пароле символ класса class
+func containsCharClass(password, class string) bool {
+	for _, r := range password {
+		switch class {
+		case CharClassLower:
+			if unicode.IsLower(r) {
+				return true
+			}
+		case CharClassUpper:
+			if unicode.IsUpper(r) {
+				return true
+			}
+		case CharClassDigit:
+			if unicode.IsDigit(r) {
+				return true
+			}
+		case CharClassSymbol:
+			if unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadBreachedPasswords читает список паролей из утечек: по одному паролю в строке,
+// пустые строки и строки, начинающиеся с #, пропускаются. Сравнение без учета регистра.
+func LoadBreachedPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	breached := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		breached[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return breached, nil
+}
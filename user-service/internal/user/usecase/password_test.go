@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2Params - дешевые параметры Argon2id, чтобы тесты не тратили 64 МиБ на каждый хеш
+func testArgon2Params() Argon2Params {
+	return Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+func TestArgon2idHashRoundTrip(t *testing.T) {
+	params := testArgon2Params()
+	hasher := NewArgon2idPasswordHasher(params)
+
+	hash, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("неожиданный формат PHC: %s", hash)
+	}
+	if !hasher.Recognizes(hash) {
+		t.Fatalf("хеш не распознан: %s", hash)
+	}
+
+	parsed, salt, key, err := parseArgon2Hash(hash)
+	if err != nil {
+		t.Fatalf("parseArgon2Hash: %v", err)
+	}
+	if parsed != params {
+		t.Fatalf("параметры после разбора %+v, ожидались %+v", parsed, params)
+	}
+	if len(salt) != int(params.SaltLength) || len(key) != int(params.KeyLength) {
+		t.Fatalf("длина соли %d и ключа %d не совпадает с параметрами", len(salt), len(key))
+	}
+
+	if err := hasher.Compare(hash, "correct horse"); err != nil {
+		t.Fatalf("Compare с верным паролем: %v", err)
+	}
+	if err := hasher.Compare(hash, "wrong horse"); !errors.Is(err, ErrPasswordMismatch) {
+		t.Fatalf("Compare с неверным паролем: ожидалась ErrPasswordMismatch, получено %v", err)
+	}
+
+	other, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash (повторно): %v", err)
+	}
+	if other == hash {
+		t.Fatalf("два хеша одного пароля совпали: соль не случайна")
+	}
+
+	if hasher.NeedsRehash(hash) {
+		t.Fatalf("хеш с текущими параметрами помечен для пересчета")
+	}
+	stronger := params
+	stronger.Iterations = 2
+	if !NewArgon2idPasswordHasher(stronger).NeedsRehash(hash) {
+		t.Fatalf("хеш с устаревшими параметрами не помечен для пересчета")
+	}
+}
+
+func TestParseArgon2HashRejectsMalformed(t *testing.T) {
+	salt := base64.RawStdEncoding.EncodeToString(make([]byte, 16))
+	key := base64.RawStdEncoding.EncodeToString(make([]byte, 32))
+	hash := func(version, params, salt, key string) string {
+		return "$argon2id$" + version + "$" + params + "$" + salt + "$" + key
+	}
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"пустая строка", ""},
+		{"не argon2id", "$argon2i$v=19$m=64,t=1,p=1$" + salt + "$" + key},
+		{"лишняя часть", hash("v=19", "m=64,t=1,p=1", salt, key) + "$extra"},
+		{"другая версия", hash("v=16", "m=64,t=1,p=1", salt, key)},
+		{"нет параметров", hash("v=19", "", salt, key)},
+		{"мусор после параметров", hash("v=19", "m=64,t=1,p=1,x=2", salt, key)},
+		{"p=0", hash("v=19", "m=64,t=1,p=0", salt, key)},
+		{"p больше uint8", hash("v=19", "m=64,t=1,p=256", salt, key)},
+		{"p больше допустимого", hash("v=19", "m=1024,t=1,p=64", salt, key)},
+		{"t=0", hash("v=19", "m=64,t=0,p=1", salt, key)},
+		{"t больше допустимого", hash("v=19", "m=64,t=1000,p=1", salt, key)},
+		{"m меньше 8*p", hash("v=19", "m=8,t=1,p=2", salt, key)},
+		{"m больше 1 ГиБ", hash("v=19", "m=4194304,t=1,p=1", salt, key)},
+		{"m отрицательная", hash("v=19", "m=-1,t=1,p=1", salt, key)},
+		{"пустая соль", hash("v=19", "m=64,t=1,p=1", "", key)},
+		{"короткая соль", hash("v=19", "m=64,t=1,p=1", base64.RawStdEncoding.EncodeToString(make([]byte, 4)), key)},
+		{"соль не base64", hash("v=19", "m=64,t=1,p=1", "!!!", key)},
+		{"пустой ключ", hash("v=19", "m=64,t=1,p=1", salt, "")},
+		{"короткий ключ", hash("v=19", "m=64,t=1,p=1", salt, base64.RawStdEncoding.EncodeToString(make([]byte, 8)))},
+		{"длинный ключ", hash("v=19", "m=64,t=1,p=1", salt, base64.RawStdEncoding.EncodeToString(make([]byte, 1024)))},
+	}
+
+	hasher := NewArgon2idPasswordHasher(testArgon2Params())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := parseArgon2Hash(tt.hash); !errors.Is(err, ErrMalformedPasswordHash) {
+				t.Fatalf("ожидалась ErrMalformedPasswordHash, получено %v", err)
+			}
+			// Compare не должен паниковать или вычислять хеш с такими параметрами
+			if err := hasher.Compare(tt.hash, "password"); !errors.Is(err, ErrMalformedPasswordHash) {
+				t.Fatalf("Compare: ожидалась ErrMalformedPasswordHash, получено %v", err)
+			}
+			if !hasher.NeedsRehash(tt.hash) {
+				t.Fatalf("некорректный хеш не помечен для пересчета")
+			}
+		})
+	}
+}
+
+func TestArgon2ParamsValidate(t *testing.T) {
+	if err := DefaultArgon2Params().Validate(); err != nil {
+		t.Fatalf("параметры по умолчанию отклонены: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *Argon2Params)
+	}{
+		{"p=0", func(p *Argon2Params) { p.Parallelism = 0 }},
+		{"t=0", func(p *Argon2Params) { p.Iterations = 0 }},
+		{"m=0", func(p *Argon2Params) { p.Memory = 0 }},
+		{"m больше 1 ГиБ", func(p *Argon2Params) { p.Memory = maxArgon2Memory + 1 }},
+		{"короткая соль", func(p *Argon2Params) { p.SaltLength = 4 }},
+		{"длинный ключ", func(p *Argon2Params) { p.KeyLength = 128 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := DefaultArgon2Params()
+			tt.modify(&params)
+			if err := params.Validate(); err == nil {
+				t.Fatalf("параметры %+v приняты", params)
+			}
+		})
+	}
+}
+
+func TestMultiPasswordHasherVerifiesLegacyHashes(t *testing.T) {
+	argon := NewArgon2idPasswordHasher(testArgon2Params())
+	legacy := NewBcryptPasswordHasher(bcrypt.MinCost)
+	hasher := NewMultiPasswordHasher(argon, legacy)
+
+	bcryptHash, err := legacy.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("bcrypt Hash: %v", err)
+	}
+	if err := hasher.Compare(bcryptHash, "correct horse"); err != nil {
+		t.Fatalf("Compare bcrypt-хеша: %v", err)
+	}
+	if !hasher.NeedsRehash(bcryptHash) {
+		t.Fatalf("bcrypt-хеш не помечен для пересчета при основном Argon2id")
+	}
+
+	argonHash, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !argon.Recognizes(argonHash) || hasher.NeedsRehash(argonHash) {
+		t.Fatalf("новый хеш должен быть Argon2id с текущими параметрами: %s", argonHash)
+	}
+
+	if err := hasher.Compare("plaintext", "plaintext"); !errors.Is(err, ErrUnknownPasswordHash) {
+		t.Fatalf("ожидалась ErrUnknownPasswordHash, получено %v", err)
+	}
+}
+
+func TestAuthenticateUserRehashesBcryptToArgon2(t *testing.T) {
+	ctx := context.Background()
+	argon := NewArgon2idPasswordHasher(testArgon2Params())
+	legacy := NewBcryptPasswordHasher(bcrypt.MinCost)
+
+	bcryptHash, err := legacy.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("bcrypt Hash: %v", err)
+	}
+	users := newFakeUserRepository(&models.User{ID: "user-1", Email: "fan@example.com", Password: bcryptHash})
+	uc := NewUserUsecase(users, nil, NewMultiPasswordHasher(argon, legacy), DefaultPasswordPolicy(), nil,
+		EmailTokenConfig{}, EmailTokenConfig{}, newTestLoginGuard())
+
+	if _, err := uc.AuthenticateUser(ctx, "fan@example.com", "wrong horse", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("вход с неверным паролем: ожидалась ErrInvalidCredentials, получено %v", err)
+	}
+	if users.passwordHash("user-1") != bcryptHash {
+		t.Fatalf("хеш пересчитан после неудачного входа")
+	}
+
+	user, err := uc.AuthenticateUser(ctx, "fan@example.com", "correct horse", "")
+	if err != nil {
+		t.Fatalf("AuthenticateUser: %v", err)
+	}
+	if user.Password != "" {
+		t.Fatalf("хеш пароля возвращен вызывающему")
+	}
+
+	rehashed := users.passwordHash("user-1")
+	if !argon.Recognizes(rehashed) {
+		t.Fatalf("после входа ожидался хеш Argon2id, сохранен %s", rehashed)
+	}
+	if err := argon.Compare(rehashed, "correct horse"); err != nil {
+		t.Fatalf("пересчитанный хеш не подходит к паролю: %v", err)
+	}
+
+	// Повторный вход с актуальным хешем ничего не пересчитывает
+	if _, err := uc.AuthenticateUser(ctx, "fan@example.com", "correct horse", ""); err != nil {
+		t.Fatalf("AuthenticateUser (повторно): %v", err)
+	}
+	if users.passwordHash("user-1") != rehashed {
+		t.Fatalf("актуальный хеш пересчитан повторно")
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidProfile оборачивает ошибки проверки полей профиля и адресов
+var ErrInvalidProfile = errors.New("некорректные данные профиля")
+
+// Ограничения полей профиля
+const (
+	maxFullNameLength  = 200
+	maxAvatarURLLength = 2048
+	dateOfBirthLayout  = "2006-01-02"
+)
+
+var (
+	// phonePattern - номер в формате E.164: "+", код страны и до 15 цифр
+	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	// localePattern - упрощенный тег BCP 47: язык и, при необходимости, регион (ru, en-US)
+	localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+)
+
+// profileError возвращает ошибку проверки поля field
+func profileError(field, reason string) error {
+	return fmt.Errorf("%w: %s: %s", ErrInvalidProfile, field, reason)
+}
+
+// normalizeFullName убирает лишние пробелы и проверяет длину имени
+func normalizeFullName(field, name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if utf8.RuneCountInString(name) > maxFullNameLength {
+		return "", profileError(field, fmt.Sprintf("не длиннее %d символов", maxFullNameLength))
+	}
+	return name, nil
+}
+
+// normalizePhone убирает пробелы, скобки и дефисы и проверяет формат E.164.
+// Пустая строка означает отсутствие телефона.
+func normalizePhone(field, phone string) (string, error) {
+	phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(phone)
+	if phone != "" && !phonePattern.MatchString(phone) {
+		return "", profileError(field, "ожидается номер в формате E.164, например +79991234567")
+	}
+	return phone, nil
+}
+
+// validateAvatarURL проверяет, что ссылка на аватар - абсолютный http(s) URL
+func validateAvatarURL(avatarURL string) error {
+	if avatarURL == "" {
+		return nil
+	}
+	if len(avatarURL) > maxAvatarURLLength {
+		return profileError("avatar_url", fmt.Sprintf("не длиннее %d символов", maxAvatarURLLength))
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return profileError("avatar_url", "ожидается ссылка http или https")
+	}
+	return nil
+}
+
+// validateDateOfBirth проверяет дату рождения в формате ГГГГ-ММ-ДД: не в будущем и не раньше 1900 года
+func validateDateOfBirth(date string, now time.Time) error {
+	if date == "" {
+		return nil
+	}
+	parsed, err := time.Parse(dateOfBirthLayout, date)
+	if err != nil {
+		return profileError("date_of_birth", "ожидается дата в формате ГГГГ-ММ-ДД")
+	}
+	if parsed.Year() < 1900 || parsed.After(now) {
+		return profileError("date_of_birth", "дата вне допустимого диапазона")
+	}
+	return nil
+}
+
+// normalizeLocale приводит тег языка к виду "en-US" и проверяет его формат
+func normalizeLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+	parts := strings.SplitN(strings.ReplaceAll(locale, "_", "-"), "-", 2)
+	locale = strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		locale += "-" + strings.ToUpper(parts[1])
+	}
+	if !localePattern.MatchString(locale) {
+		return "", profileError("locale", "ожидается код языка, например ru или en-US")
+	}
+	return locale, nil
+}
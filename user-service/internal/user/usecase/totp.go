@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Параметры TOTP (RFC 6238) в варианте, который поддерживают все распространенные приложения-аутентификаторы
+const (
+	totpPeriod     = 30 // Длительность шага в секундах
+	totpDigits     = 6
+	totpSecretSize = 20 // 160 бит, рекомендуемый размер ключа HMAC-SHA1 (RFC 4226)
+	// totpSkew - сколько соседних шагов принимается, чтобы не мешало расхождение часов
+	totpSkew = 1
+)
+
+// totpEncoding - base32 без дополнения, как в otpauth:// URI
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateTOTPSecret возвращает случайный секрет в кодировке base32
+func generateTOTPSecret() (string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// totpStep возвращает номер временного шага для момента t
+func totpStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+// totpCode вычисляет код для шага step (HOTP из RFC 4226 со счетчиком step)
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulo := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%modulo)
+}
+
+// matchTOTP проверяет код для момента now с допуском totpSkew шагов и возвращает совпавший шаг
+func matchTOTP(encodedSecret, code string, now time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	secret, err := totpEncoding.DecodeString(strings.ToUpper(encodedSecret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := totpStep(now)
+	// Поздние шаги проверяются первыми, чтобы при повторе внутри окна сработала защита по last_used_step
+	for step := current + totpSkew; step >= current-totpSkew; step-- {
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpURI возвращает otpauth:// URI для добавления секрета в приложение-аутентификатор
+// (формат Key Uri Format, обычно передается в виде QR-кода)
+func totpURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
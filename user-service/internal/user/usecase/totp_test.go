@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret - ключ SHA-1 из приложения B RFC 6238
+var rfc6238Secret = []byte("12345678901234567890")
+
+func TestTOTPCodeRFC6238Vectors(t *testing.T) {
+	// Приложение B RFC 6238 приводит 8-значные коды; шестизначный код - их последние 6 цифр
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			want := tt.code[len(tt.code)-totpDigits:]
+			if got := totpCode(rfc6238Secret, totpStep(time.Unix(tt.unix, 0))); got != want {
+				t.Fatalf("код для T=%d: %s, ожидался %s", tt.unix, got, want)
+			}
+		})
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfc6238Secret)
+	now := time.Unix(1111111111, 0)
+	current := totpStep(now)
+	codeAt := func(step int64) string { return totpCode(rfc6238Secret, step) }
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"текущий шаг", secret, "050471", current, true},
+		{"предыдущий шаг в пределах допуска", secret, codeAt(current - 1), current - 1, true},
+		{"следующий шаг в пределах допуска", secret, codeAt(current + 1), current + 1, true},
+		{"два шага назад", secret, codeAt(current - 2), 0, false},
+		{"два шага вперед", secret, codeAt(current + 2), 0, false},
+		{"пробелы в коде", secret, " 050 471 ", current, true},
+		{"секрет в нижнем регистре", strings.ToLower(secret), "050471", current, true},
+		{"неверный код", secret, "000000", 0, false},
+		{"короткий код", secret, "05047", 0, false},
+		{"длинный код", secret, "0504710", 0, false},
+		{"8-значный код RFC", secret, "14050471", 0, false},
+		{"пустой код", secret, "", 0, false},
+		{"буквы вместо цифр", secret, "O5O471", 0, false},
+		{"секрет не base32", "not-base32!", "050471", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := matchTOTP(tt.secret, tt.code, now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Fatalf("matchTOTP = (%d, %v), ожидалось (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMatchTOTPPrefersLaterStep(t *testing.T) {
+	// У этого ключа коды шагов 37037037 и 37037038 совпадают (подобран перебором): при повторе кода
+	// внутри окна должен вернуться поздний шаг, иначе защита по last_used_step пропустит код дважды
+	key := []byte("totp-test-00898902")
+	now := time.Unix(1111111111, 0)
+	current := totpStep(now)
+	code := totpCode(key, current)
+	if code != totpCode(key, current+1) {
+		t.Fatalf("коды соседних шагов не совпадают: тестовый ключ подобран неверно")
+	}
+
+	step, ok := matchTOTP(totpEncoding.EncodeToString(key), code, now)
+	if !ok || step != current+1 {
+		t.Fatalf("matchTOTP = (%d, %v), ожидался поздний шаг %d", step, ok, current+1)
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatalf("generateTOTPSecret: %v", err)
+	}
+	decoded, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("секрет не в base32 без дополнения: %v", err)
+	}
+	if len(decoded) != totpSecretSize {
+		t.Fatalf("длина секрета %d байт, ожидалось %d", len(decoded), totpSecretSize)
+	}
+
+	now := time.Now()
+	if _, ok := matchTOTP(secret, totpCode(decoded, totpStep(now)), now); !ok {
+		t.Fatalf("код нового секрета не принят")
+	}
+}
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("двухфакторная аутентификация уже включена")
+	ErrTwoFactorNotEnabled     = errors.New("двухфакторная аутентификация не включена")
+	ErrTwoFactorSetupMissing   = errors.New("секрет двухфакторной аутентификации не выдан")
+	ErrInvalidTwoFactorCode    = errors.New("неверный код двухфакторной аутентификации")
+	ErrInvalidLoginChallenge   = errors.New("недействительный или истекший вход, войдите заново")
+	ErrTwoFactorRequired       = errors.New("для роли ADMIN двухфакторная аутентификация обязательна")
+	// ErrTooManyTwoFactorAttempts - проверка кодов временно заблокирована после MaxAttempts неверных кодов подряд
+	ErrTooManyTwoFactorAttempts = errors.New("слишком много неверных кодов двухфакторной аутентификации, повторите позже")
+)
+
+// TwoFactorConfig задает параметры двухфакторной аутентификации
+type TwoFactorConfig struct {
+	Issuer          string        // Название сервиса в приложении-аутентификаторе
+	RequireForAdmin bool          // Роль ADMIN попадает в токен только при включенной 2FA
+	ChallengeTTL    time.Duration // Сколько ждать второй фактор после проверки пароля
+	MaxAttempts     int           // Сколько неверных кодов допускается за один вход и подряд при изменении настроек 2FA
+}
+
+// TwoFactorSetup - данные для подключения приложения-аутентификатора
+type TwoFactorSetup struct {
+	Secret string // Секрет в кодировке base32 для ручного ввода
+	URI    string // otpauth:// URI для QR-кода
+}
+
+// TwoFactorChallenge - токен незавершенного входа, который нужно предъявить вместе с кодом
+type TwoFactorChallenge struct {
+	Token     string
+	ExpiresIn time.Duration
+}
+
+// recoveryCodeCount - сколько кодов восстановления выдается за раз
+const recoveryCodeCount = 10
+
+// recoveryCodeAlphabet не содержит похожих символов (0/O, 1/I/L)
+const recoveryCodeAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// recoveryCodeByteLimit - наибольшее кратное размеру алфавита число, не превышающее 256
+const recoveryCodeByteLimit = 256 - 256%len(recoveryCodeAlphabet)
+
+// tokenRoles возвращает роли для токена доступа. Если политика требует 2FA для ADMIN,
+// а пользователь ее не включил, роль ADMIN не выдается (второе значение true):
+// с таким токеном можно подключить 2FA, но нельзя выполнять действия администратора.
+func (a *authUsecase) tokenRoles(ctx context.Context, user *models.User) ([]string, bool, error) {
+	return effectiveRoles(ctx, a.twoFactorRepo, a.twoFactor.RequireForAdmin, user)
+}
+
+// effectiveRoles применяет политику 2FA для ADMIN к ролям пользователя (см. tokenRoles).
+// Используется и для токенов доступа, и для API-ключей.
+func effectiveRoles(ctx context.Context, twoFactorRepo repository.TwoFactorRepository, requireForAdmin bool, user *models.User) ([]string, bool, error) {
+	if !requireForAdmin || !hasRole(user.Roles, auth.RoleAdmin) {
+		return user.Roles, false, nil
+	}
+
+	twoFactor, err := twoFactorRepo.GetTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if twoFactor.Enabled() {
+		return user.Roles, false, nil
+	}
+
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		if role != auth.RoleAdmin {
+			roles = append(roles, role)
+		}
+	}
+	return roles, true, nil
+}
+
+// hasRole сообщает, есть ли role среди roles
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// newLoginChallenge сохраняет незавершенный вход и возвращает его токен
+func (a *authUsecase) newLoginChallenge(ctx context.Context, userID string) (*TwoFactorChallenge, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	err = a.tokenRepo.CreateLoginChallenge(ctx, &models.LoginChallenge{
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		ExpiresAt: time.Now().UTC().Add(a.twoFactor.ChallengeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorChallenge{Token: token, ExpiresIn: a.twoFactor.ChallengeTTL}, nil
+}
+
+// VerifyTwoFactor проверяет второй фактор незавершенного входа и выдает пару токенов.
+// После MaxAttempts неверных кодов вход нужно начинать заново с пароля.
+func (a *authUsecase) VerifyTwoFactor(ctx context.Context, challengeToken, code string) (*LoginResult, error) {
+	if challengeToken == "" {
+		return nil, ErrInvalidLoginChallenge
+	}
+	hash := hashToken(challengeToken)
+
+	challenge, err := a.tokenRepo.GetLoginChallenge(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || challenge.UsedAt != nil || !time.Now().Before(challenge.ExpiresAt) ||
+		challenge.Attempts >= a.twoFactor.MaxAttempts {
+		return nil, ErrInvalidLoginChallenge
+	}
+
+	ok, err := a.checkSecondFactor(ctx, challenge.UserID, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if _, err := a.tokenRepo.RecordLoginChallengeFailure(ctx, hash); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	consumed, err := a.tokenRepo.ConsumeLoginChallenge(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidLoginChallenge
+	}
+
+	user, err := a.userUsecase.FindUserByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidLoginChallenge
+		}
+		return nil, err
+	}
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+	return a.issueTokens(ctx, user)
+}
+
+// checkSecondFactor проверяет код TOTP или код восстановления включенной 2FA.
+// Каждый код принимается один раз.
+func (a *authUsecase) checkSecondFactor(ctx context.Context, userID, code string) (bool, error) {
+	twoFactor, err := a.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !twoFactor.Enabled() {
+		return false, nil
+	}
+
+	if step, ok := matchTOTP(twoFactor.Secret, code, time.Now()); ok {
+		return a.twoFactorRepo.UseTwoFactorStep(ctx, userID, step)
+	}
+	return a.twoFactorRepo.UseRecoveryCode(ctx, userID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// SetupTwoFactor генерирует и сохраняет неподтвержденный секрет TOTP.
+// Повторный вызов до подтверждения заменяет секрет.
+func (a *authUsecase) SetupTwoFactor(ctx context.Context, userID string) (*TwoFactorSetup, error) {
+	user, err := a.userUsecase.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	saved, err := a.twoFactorRepo.SaveTwoFactorSecret(ctx, user.ID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return &TwoFactorSetup{
+		Secret: secret,
+		URI:    totpURI(a.twoFactor.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor проверяет первый код из приложения и включает 2FA.
+func (a *authUsecase) ConfirmTwoFactor(ctx context.Context, userID, code string) ([]string, error) {
+	twoFactor, err := a.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, ErrTwoFactorSetupMissing
+	}
+	if twoFactor.Enabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := matchTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := a.twoFactorRepo.ConfirmTwoFactor(ctx, userID, step, hashes); err != nil {
+		// 2FA успели включить параллельным запросом
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTwoFactor отключает 2FA. Если политика требует 2FA для ADMIN, администратор отключить ее не может.
+func (a *authUsecase) DisableTwoFactor(ctx context.Context, userID, code string) error {
+	if a.twoFactor.RequireForAdmin {
+		user, err := a.userUsecase.FindUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if hasRole(user.Roles, auth.RoleAdmin) {
+			return ErrTwoFactorRequired
+		}
+	}
+
+	if err := a.requireSecondFactor(ctx, userID, code); err != nil {
+		return err
+	}
+	return a.twoFactorRepo.DeleteTwoFactor(ctx, userID)
+}
+
+// RegenerateRecoveryCodes выдает новые коды восстановления взамен прежних.
+func (a *authUsecase) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	if err := a.requireSecondFactor(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := a.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// requireSecondFactor проверяет, что 2FA включена и code действителен.
+// Как и при входе, допускается MaxAttempts неверных кодов подряд: дальше проверка блокируется
+// на LoginPolicy.Lockout, иначе украденный токен доступа позволил бы перебрать коды и отключить 2FA.
+func (a *authUsecase) requireSecondFactor(ctx context.Context, userID, code string) error {
+	twoFactor, err := a.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.Enabled() {
+		return ErrTwoFactorNotEnabled
+	}
+	if err := a.loginGuard.CheckSecondFactor(ctx, userID); err != nil {
+		return err
+	}
+
+	ok, err := a.checkSecondFactor(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if err := a.loginGuard.SecondFactorFailure(ctx, userID, a.twoFactor.MaxAttempts); err != nil {
+			return err
+		}
+		return ErrInvalidTwoFactorCode
+	}
+	return a.loginGuard.SecondFactorSuccess(ctx, userID)
+}
+
+// generateRecoveryCodes возвращает коды восстановления вида XXXXX-XXXXX и их хеши.
+// Коды содержат около 50 бит случайности и одноразовы, поэтому хранится SHA-256, как для токенов.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		chars, err := randomRecoveryChars(10)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := chars[:5] + "-" + chars[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+	return codes, hashes, nil
+}
+
+// randomRecoveryChars возвращает n случайных символов recoveryCodeAlphabet.
+// Байты не меньше recoveryCodeByteLimit отбрасываются: остаток от деления 256 на размер алфавита
+// иначе делал бы первые символы алфавита чаще остальных.
+func randomRecoveryChars(n int) (string, error) {
+	chars := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(chars) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= recoveryCodeByteLimit || len(chars) == n {
+				continue
+			}
+			chars = append(chars, recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+		}
+	}
+	return string(chars), nil
+}
+
+// normalizeRecoveryCode убирает разделители и приводит код к верхнему регистру
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generateRecoveryCodes: %v", err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("выдано %d кодов и %d хешей, ожидалось %d", len(codes), len(hashes), recoveryCodeCount)
+	}
+
+	seen := make(map[string]bool)
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Fatalf("код %q не в формате XXXXX-XXXXX", code)
+		}
+		for _, c := range strings.Replace(code, "-", "", 1) {
+			if !strings.ContainsRune(recoveryCodeAlphabet, c) {
+				t.Fatalf("код %q содержит символ %q вне алфавита", code, c)
+			}
+		}
+		if seen[code] {
+			t.Fatalf("код %q выдан дважды", code)
+		}
+		seen[code] = true
+		if hashes[i] != hashToken(normalizeRecoveryCode(strings.ToLower(code))) {
+			t.Fatalf("хеш кода %q не совпадает с хешем нормализованного ввода", code)
+		}
+	}
+}
+
+func TestRandomRecoveryCharsIsUnbiased(t *testing.T) {
+	if recoveryCodeByteLimit%len(recoveryCodeAlphabet) != 0 || 256-recoveryCodeByteLimit >= len(recoveryCodeAlphabet) {
+		t.Fatalf("recoveryCodeByteLimit = %d не является наибольшим кратным %d", recoveryCodeByteLimit, len(recoveryCodeAlphabet))
+	}
+
+	// При остатке от деления без отбрасывания первые 8 символов выпадали бы в 9/8 раза чаще
+	chars, err := randomRecoveryChars(len(recoveryCodeAlphabet) * 2000)
+	if err != nil {
+		t.Fatalf("randomRecoveryChars: %v", err)
+	}
+	counts := make(map[rune]int)
+	for _, c := range chars {
+		counts[c]++
+	}
+	first, last := 0, 0
+	for _, c := range recoveryCodeAlphabet[:8] {
+		first += counts[c]
+	}
+	for _, c := range recoveryCodeAlphabet[len(recoveryCodeAlphabet)-8:] {
+		last += counts[c]
+	}
+	// Ожидается по 16000 на каждую восьмерку; смещение дало бы около 1.12 при разбросе порядка 0.02
+	if ratio := float64(first) / float64(last); ratio > 1.08 || ratio < 0.92 {
+		t.Fatalf("первые символы алфавита выпадают в %.3f раза чаще последних", ratio)
+	}
+}
+
+// newTwoFactorTestUsecase создает authUsecase только с тем, что нужно для проверки второго фактора
+func newTwoFactorTestUsecase(repo *fakeTwoFactorRepository, maxAttempts int) *authUsecase {
+	return &authUsecase{
+		twoFactorRepo: repo,
+		twoFactor:     TwoFactorConfig{MaxAttempts: maxAttempts},
+		loginGuard:    newTestLoginGuard(),
+	}
+}
+
+func TestRequireSecondFactorLimitsAttempts(t *testing.T) {
+	ctx := context.Background()
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatalf("generateTOTPSecret: %v", err)
+	}
+	key, _ := totpEncoding.DecodeString(secret)
+	repo := newFakeTwoFactorRepository()
+	repo.enable("user-1", secret)
+	repo.enable("user-2", secret)
+	uc := newTwoFactorTestUsecase(repo, 3)
+
+	for i := 0; i < 3; i++ {
+		if _, err := uc.RegenerateRecoveryCodes(ctx, "user-1", "WRONG-CODE"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("попытка %d: ожидалась ErrInvalidTwoFactorCode, получено %v", i+1, err)
+		}
+	}
+
+	// После MaxAttempts неудач не принимается даже верный код, и лимит общий для обеих операций
+	valid := totpCode(key, totpStep(time.Now()))
+	if _, err := uc.RegenerateRecoveryCodes(ctx, "user-1", valid); !errors.Is(err, ErrTooManyTwoFactorAttempts) {
+		t.Fatalf("RegenerateRecoveryCodes после блокировки: ожидалась ErrTooManyTwoFactorAttempts, получено %v", err)
+	}
+	if err := uc.DisableTwoFactor(ctx, "user-1", valid); !errors.Is(err, ErrTooManyTwoFactorAttempts) {
+		t.Fatalf("DisableTwoFactor после блокировки: ожидалась ErrTooManyTwoFactorAttempts, получено %v", err)
+	}
+	if !repo.enabled("user-1") {
+		t.Fatalf("2FA отключена при заблокированной проверке")
+	}
+
+	// Блокировка действует только на пользователя, который ошибался
+	if err := uc.DisableTwoFactor(ctx, "user-2", valid); err != nil {
+		t.Fatalf("DisableTwoFactor другого пользователя: %v", err)
+	}
+	if repo.enabled("user-2") {
+		t.Fatalf("2FA пользователя user-2 не отключена")
+	}
+}
+
+func TestRequireSecondFactorResetsAfterSuccess(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeTwoFactorRepository()
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generateRecoveryCodes: %v", err)
+	}
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatalf("generateTOTPSecret: %v", err)
+	}
+	repo.enable("user-1", secret, hashes...)
+	uc := newTwoFactorTestUsecase(repo, 3)
+
+	fail := func() {
+		t.Helper()
+		if _, err := uc.RegenerateRecoveryCodes(ctx, "user-1", "AAAAA-AAAAA"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("ожидалась ErrInvalidTwoFactorCode, получено %v", err)
+		}
+	}
+
+	fail()
+	fail()
+	newCodes, err := uc.RegenerateRecoveryCodes(ctx, "user-1", codes[0])
+	if err != nil {
+		t.Fatalf("RegenerateRecoveryCodes с кодом восстановления: %v", err)
+	}
+
+	// Успешная проверка обнулила счетчик: снова доступны MaxAttempts попыток
+	fail()
+	fail()
+	if err := uc.DisableTwoFactor(ctx, "user-1", codes[1]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("код из замененного набора: ожидалась ErrInvalidTwoFactorCode, получено %v", err)
+	}
+	if err := uc.DisableTwoFactor(ctx, "user-1", newCodes[0]); !errors.Is(err, ErrTooManyTwoFactorAttempts) {
+		t.Fatalf("после третьей неудачи: ожидалась ErrTooManyTwoFactorAttempts, получено %v", err)
+	}
+}
+
+func TestRequireSecondFactorRejectsReusedTOTP(t *testing.T) {
+	ctx := context.Background()
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatalf("generateTOTPSecret: %v", err)
+	}
+	key, _ := totpEncoding.DecodeString(secret)
+	repo := newFakeTwoFactorRepository()
+	repo.enable("user-1", secret)
+	uc := newTwoFactorTestUsecase(repo, 3)
+
+	code := totpCode(key, totpStep(time.Now())+1)
+	if _, err := uc.RegenerateRecoveryCodes(ctx, "user-1", code); err != nil {
+		t.Fatalf("RegenerateRecoveryCodes: %v", err)
+	}
+	if err := uc.DisableTwoFactor(ctx, "user-1", code); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("повтор кода TOTP: ожидалась ErrInvalidTwoFactorCode, получено %v", err)
+	}
+
+	if err := uc.DisableTwoFactor(ctx, "nobody", code); !errors.Is(err, ErrTwoFactorNotEnabled) {
+		t.Fatalf("без 2FA: ожидалась ErrTwoFactorNotEnabled, получено %v", err)
+	}
+}
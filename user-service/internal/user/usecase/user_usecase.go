@@ -2,89 +2,177 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors" // Для создания кастомных ошибок
-	"time"   // Для обновления UpdatedAt
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time" // Для обновления UpdatedAt
 
 	// ВАЖНО: Замените 'your_project_module' на имя вашего модуля из go.mod
+	"github.com/Hayzerr/go-microservice-project/auth"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/notifier"
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
 
-	"golang.org/x/crypto/bcrypt" // Для хеширования паролей
+	"github.com/google/uuid"
 )
 
 var (
-	ErrUserNotFound       = errors.New("пользователь не найден")
-	ErrEmailExists        = errors.New("пользователь с таким email уже существует")
-	ErrInvalidCredentials = errors.New("неверные учетные данные")
-	ErrPasswordTooShort   = errors.New("пароль слишком короткий")
-	ErrUpdateConflict     = errors.New("конфликт при обновлении данных пользователя")
+	ErrUserNotFound             = errors.New("пользователь не найден")
+	ErrEmailExists              = errors.New("пользователь с таким email уже существует")
+	ErrInvalidCredentials       = errors.New("неверные учетные данные")
+	ErrPasswordTooShort         = errors.New("пароль слишком короткий")
+	ErrUpdateConflict           = errors.New("конфликт при обновлении данных пользователя")
+	ErrInvalidRole              = errors.New("неизвестная роль")
+	ErrInvalidResetToken        = errors.New("недействительный или истекший токен сброса пароля")
+	ErrInvalidVerificationToken = errors.New("недействительный или истекший токен подтверждения email")
+	ErrEmailAlreadyVerified     = errors.New("email уже подтвержден")
+	ErrInvalidPageToken         = errors.New("некорректный токен страницы")
+	ErrTooManyIDs               = errors.New("слишком много ID в одном запросе")
+	ErrInvalidSort              = errors.New("неизвестное поле сортировки")
 	// TODO: Добавьте другие специфичные для бизнес-логики ошибки
 )
 
-// PasswordHasher определяет интерфейс для хеширования и проверки паролей.
-type PasswordHasher interface {
-	Hash(password string) (string, error)
-	Compare(hashedPassword, password string) error
+// UpdateUserInput определяет структуру для входных данных при обновлении пользователя.
+// Позволяет обновлять только определенные поля. Пароль здесь не обновляется.
+type UpdateUserInput struct {
+	Username *string // Указатель, чтобы различать пустое значение и отсутствие поля
+	Email    *string
+	// Поля профиля; пустая строка очищает поле
+	FullName    *string
+	Phone       *string // E.164; пробелы, скобки и дефисы убираются
+	AvatarURL   *string
+	DateOfBirth *string // ГГГГ-ММ-ДД
+	Locale      *string // ru, en-US
 }
 
-// BcryptPasswordHasher реализует PasswordHasher с использованием bcrypt.
-type BcryptPasswordHasher struct {
-	cost int // Стоимость хеширования для bcrypt (например, bcrypt.DefaultCost)
+// Empty сообщает, что во входных данных нет ни одного поля для обновления
+func (in UpdateUserInput) Empty() bool {
+	return in.Username == nil && in.Email == nil && in.FullName == nil && in.Phone == nil &&
+		in.AvatarURL == nil && in.DateOfBirth == nil && in.Locale == nil
 }
 
-// NewBcryptPasswordHasher создает новый экземпляр BcryptPasswordHasher.
-// Рекомендуемая стоимость cost: bcrypt.DefaultCost (обычно 10) или выше.
-func NewBcryptPasswordHasher(cost int) PasswordHasher {
-	if cost == 0 {
-		cost = bcrypt.DefaultCost
-	}
-	return &BcryptPasswordHasher{cost: cost}
+// EmailTokenConfig задает параметры одноразовых токенов, отправляемых пользователю
+// (сброс пароля, подтверждение email).
+type EmailTokenConfig struct {
+	TokenTTL time.Duration // Срок действия токена
+	// URL страницы, принимающей токен; токен добавляется параметром token.
+	// Если не задан, в сообщении передается только сам токен.
+	URL string
 }
 
-// Hash генерирует хеш bcrypt для заданного пароля.
-func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
-	// Проверка минимальной длины пароля перед хешированием
-	if len(password) < 8 { // Пример: минимальная длина 8 символов
-		return "", ErrPasswordTooShort
+// link возвращает ссылку с токеном или пустую строку, если URL не задан или некорректен
+func (c EmailTokenConfig) link(token string) string {
+	if c.URL == "" {
+		return ""
+	}
+	link, err := url.Parse(c.URL)
+	if err != nil {
+		return ""
 	}
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
-	return string(bytes), err
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String()
 }
 
-// Compare сравнивает хеш bcrypt с паролем.
-func (h *BcryptPasswordHasher) Compare(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// Ограничения размера страницы списка пользователей и пакетной выборки
+const (
+	DefaultUsersPageSize = 100
+	MaxUsersPageSize     = 1000
+	MaxBatchGetUsers     = 100
+)
+
+// UserListQuery - параметры выборки страницы списка пользователей
+type UserListQuery struct {
+	Filter repository.UserFilter
+	// Sort - поле сортировки (created_at, email, username), с префиксом "-" - по убыванию.
+	// По умолчанию created_at.
+	Sort      string
+	PageSize  int
+	PageToken string // NextPageToken предыдущей страницы или пустая строка для первой
+	WithTotal bool   // Посчитать число всех пользователей по фильтру
 }
 
-// UpdateUserInput определяет структуру для входных данных при обновлении пользователя.
-// Позволяет обновлять только определенные поля. Пароль здесь не обновляется.
-type UpdateUserInput struct {
-	Username *string // Указатель, чтобы различать пустое значение и отсутствие поля
-	Email    *string
+// UserPage - страница списка пользователей
+type UserPage struct {
+	Users         []*models.User
+	NextPageToken string // Пусто на последней странице
+	Total         int64  // Заполняется, только если запрошено WithTotal
 }
 
 // UserUsecase определяет интерфейс для бизнес-логики, связанной с пользователями.
 type UserUsecase interface {
 	RegisterUser(ctx context.Context, username, email, rawPassword string) (*models.User, error)
-	AuthenticateUser(ctx context.Context, email, rawPassword string) (*models.User, error)
+	// AuthenticateUser проверяет email и пароль с учетом ограничения попыток входа (LoginGuard).
+	// clientIP - адрес клиента для учета попыток по IP (может быть пустым).
+	// Если вход временно запрещен, возвращает *LoginThrottledError; для деактивированной
+	// или удаленной учетной записи - ErrAccountDeactivated или ErrAccountDeleted.
+	AuthenticateUser(ctx context.Context, email, rawPassword, clientIP string) (*models.User, error)
 	FindUserByID(ctx context.Context, id string) (*models.User, error)
 	UpdateUser(ctx context.Context, id string, input UpdateUserInput) (*models.User, error)
-	// TODO: Добавьте другие методы бизнес-логики (например, DeleteUser, ChangePassword)
+	// TODO: Добавьте другие методы бизнес-логики
+
+	// ChangePassword меняет пароль после проверки текущего и отзывает все токены обновления пользователя
+	ChangePassword(ctx context.Context, id, currentPassword, newPassword string) error
+	// RequestPasswordReset отправляет токен сброса пароля на email. Для неизвестного email
+	// ничего не отправляется и ошибка не возвращается, чтобы нельзя было перебирать адреса.
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword устанавливает новый пароль по одноразовому токену сброса
+	// и отзывает все токены обновления пользователя
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	// SendVerificationEmail повторно отправляет токен подтверждения на текущий email пользователя
+	SendVerificationEmail(ctx context.Context, id string) error
+	// VerifyEmail подтверждает email по одноразовому токену и возвращает обновленного пользователя
+	VerifyEmail(ctx context.Context, token string) (*models.User, error)
+
+	// UnlockUser снимает блокировку входа, наложенную после неудачных попыток (для администратора)
+	UnlockUser(ctx context.Context, id string) error
+	// ListAuthEvents возвращает записи журнала аутентификации (для администратора)
+	ListAuthEvents(ctx context.Context, filter repository.AuthEventFilter) ([]*models.AuthEvent, error)
 
-	ListUsers(ctx context.Context) ([]*models.User, error)
-	DeleteUser(ctx context.Context, id string) error
+	// ListUsersPage возвращает страницу пользователей по фильтру и сортировке.
+	// Токен страницы действителен только для тех же фильтра и сортировки.
+	ListUsersPage(ctx context.Context, query UserListQuery) (*UserPage, error)
+	// BatchGetUsers возвращает пользователей в порядке ids, пропуская ненайденных (не больше MaxBatchGetUsers ID)
+	BatchGetUsers(ctx context.Context, ids []string) ([]*models.User, error)
+
+	// GrantRole выдает пользователю роль (ADMIN, CUSTOMER или STAFF)
+	GrantRole(ctx context.Context, id, role string) (*models.User, error)
+	// RevokeRole отзывает у пользователя роль
+	RevokeRole(ctx context.Context, id, role string) (*models.User, error)
 }
 
 type userUsecase struct {
 	userRepo       repository.UserRepository
+	tokenRepo      repository.TokenRepository
 	passwordHasher PasswordHasher // Интерфейс для хеширования паролей
+	passwordPolicy PasswordValidator
+	notifier       notifier.Notifier
+	passwordReset  EmailTokenConfig
+	verification   EmailTokenConfig
+	loginGuard     *LoginGuard
 }
 
 // NewUserUsecase создает новый экземпляр userUsecase.
-func NewUserUsecase(userRepo repository.UserRepository, hasher PasswordHasher) UserUsecase {
+// Через notifier пользователям доставляются токены сброса пароля и подтверждения email,
+// loginGuard ограничивает попытки входа и ведет журнал аутентификации,
+// policy проверяет новые пароли при регистрации, смене и сбросе.
+func NewUserUsecase(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, hasher PasswordHasher, policy PasswordValidator, n notifier.Notifier, passwordReset, verification EmailTokenConfig, loginGuard *LoginGuard) UserUsecase {
 	return &userUsecase{
 		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
 		passwordHasher: hasher,
+		passwordPolicy: policy,
+		notifier:       n,
+		passwordReset:  passwordReset,
+		verification:   verification,
+		loginGuard:     loginGuard,
 	}
 }
 
@@ -98,11 +186,8 @@ func (uc *userUsecase) RegisterUser(ctx context.Context, username, email, rawPas
 		return nil, ErrEmailExists
 	}
 
-	hashedPassword, err := uc.passwordHasher.Hash(rawPassword)
+	hashedPassword, err := uc.hashNewPassword(rawPassword)
 	if err != nil {
-		if errors.Is(err, ErrPasswordTooShort) {
-			return nil, ErrPasswordTooShort
-		}
 		return nil, err
 	}
 
@@ -110,35 +195,75 @@ func (uc *userUsecase) RegisterUser(ctx context.Context, username, email, rawPas
 		Username: username,
 		Email:    email,
 		Password: hashedPassword,
+		Roles:    []string{auth.RoleCustomer},
 	}
 
 	createdUser, err := uc.userRepo.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
+
+	// Регистрация не отменяется, если письмо не ушло: токен можно запросить повторно
+	if err := uc.sendVerification(ctx, createdUser); err != nil {
+		log.Printf("Не удалось отправить подтверждение email пользователю %s: %v", createdUser.ID, err)
+	}
 	return createdUser, nil
 }
 
 // AuthenticateUser аутентифицирует пользователя по email и паролю.
-func (uc *userUsecase) AuthenticateUser(ctx context.Context, email, rawPassword string) (*models.User, error) {
+func (uc *userUsecase) AuthenticateUser(ctx context.Context, email, rawPassword, clientIP string) (*models.User, error) {
+	if err := uc.loginGuard.Check(ctx, email, clientIP); err != nil {
+		return nil, err
+	}
+
 	user, err := uc.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
+		// Попытки с незарегистрированным email учитываются так же, чтобы ответы не отличались
+		if err := uc.loginGuard.Failure(ctx, "", email, clientIP); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
 
 	err = uc.passwordHasher.Compare(user.Password, rawPassword)
 	if err != nil {
+		if err := uc.loginGuard.Failure(ctx, user.ID, email, clientIP); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
+	// Состояние учетной записи сообщается только после верного пароля, чтобы его нельзя было узнать по email
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+	if err := uc.loginGuard.Success(ctx, user.ID, email, clientIP); err != nil {
+		return nil, err
+	}
+	if uc.passwordHasher.NeedsRehash(user.Password) {
+		uc.rehashPassword(ctx, user, rawPassword)
+	}
 
 	authenticatedUser := *user
 	authenticatedUser.Password = ""
 	return &authenticatedUser, nil
 }
 
+// rehashPassword пересчитывает хеш пароля текущим алгоритмом после успешного входа.
+// Политика паролей здесь не проверяется: пароль уже принят. Ошибка не мешает входу и только логируется.
+func (uc *userUsecase) rehashPassword(ctx context.Context, user *models.User, rawPassword string) {
+	hashedPassword, err := uc.passwordHasher.Hash(rawPassword)
+	if err == nil {
+		err = uc.userRepo.ReplacePasswordHash(ctx, user.ID, user.Password, hashedPassword)
+	}
+	// sql.ErrNoRows - пароль успели сменить, новый хеш уже актуален
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("Не удалось пересчитать хеш пароля пользователя %s: %v", user.ID, err)
+	}
+}
+
 // FindUserByID находит пользователя по его ID.
 func (uc *userUsecase) FindUserByID(ctx context.Context, id string) (*models.User, error) {
 	user, err := uc.userRepo.GetByID(ctx, id)
@@ -184,9 +309,17 @@ func (uc *userUsecase) UpdateUser(ctx context.Context, id string, input UpdateUs
 			return nil, ErrEmailExists // Новый email уже используется другим пользователем
 		}
 		userToUpdate.Email = *input.Email
+		// Новый адрес нужно подтвердить заново
+		userToUpdate.EmailVerifiedAt = nil
 		changed = true
 	}
 
+	profileChanged, err := applyProfile(&userToUpdate, input)
+	if err != nil {
+		return nil, err
+	}
+	changed = changed || profileChanged
+
 	// Если ничего не изменилось, просто возвращаем текущего пользователя
 	if !changed {
 		updatedUser := *currentUser
@@ -209,21 +342,427 @@ func (uc *userUsecase) UpdateUser(ctx context.Context, id string, input UpdateUs
 		// хотя это маловероятно, если мы только что получили пользователя по ID.
 		return nil, err
 	}
+	if updatedUser == nil {
+		return nil, ErrUserNotFound
+	}
+	if updatedUser.Email != currentUser.Email {
+		if err := uc.sendVerification(ctx, updatedUser); err != nil {
+			log.Printf("Не удалось отправить подтверждение email пользователю %s: %v", updatedUser.ID, err)
+		}
+	}
 	updatedUser.Password = "" // Убираем пароль перед возвратом
 	return updatedUser, nil
 }
 
-func (uc *userUsecase) ListUsers(ctx context.Context) ([]*models.User, error) {
-	users, err := uc.userRepo.List(ctx)
+// applyProfile проверяет поля профиля из input и переносит их в user.
+// Возвращает true, если хотя бы одно поле изменилось.
+func applyProfile(user *models.User, input UpdateUserInput) (bool, error) {
+	changed := false
+	set := func(field *string, value string) {
+		if *field != value {
+			*field = value
+			changed = true
+		}
+	}
+
+	if input.FullName != nil {
+		fullName, err := normalizeFullName("full_name", *input.FullName)
+		if err != nil {
+			return false, err
+		}
+		set(&user.FullName, fullName)
+	}
+	if input.Phone != nil {
+		phone, err := normalizePhone("phone", *input.Phone)
+		if err != nil {
+			return false, err
+		}
+		set(&user.Phone, phone)
+	}
+	if input.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*input.AvatarURL)
+		if err := validateAvatarURL(avatarURL); err != nil {
+			return false, err
+		}
+		set(&user.AvatarURL, avatarURL)
+	}
+	if input.DateOfBirth != nil {
+		dateOfBirth := strings.TrimSpace(*input.DateOfBirth)
+		if err := validateDateOfBirth(dateOfBirth, time.Now().UTC()); err != nil {
+			return false, err
+		}
+		set(&user.DateOfBirth, dateOfBirth)
+	}
+	if input.Locale != nil {
+		locale, err := normalizeLocale(strings.TrimSpace(*input.Locale))
+		if err != nil {
+			return false, err
+		}
+		set(&user.Locale, locale)
+	}
+	return changed, nil
+}
+
+// ListUsersPage выбирает страницу пользователей. Токен страницы - закодированный курсор
+// (значение поля сортировки и id последнего пользователя предыдущей страницы).
+func (uc *userUsecase) ListUsersPage(ctx context.Context, query UserListQuery) (*UserPage, error) {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultUsersPageSize
+	}
+	if pageSize > MaxUsersPageSize {
+		pageSize = MaxUsersPageSize
+	}
+
+	sortBy, desc, err := parseUserSort(query.Sort)
 	if err != nil {
 		return nil, err
 	}
-	for _, u := range users {
+	filter := query.Filter
+	if filter.Role != "" {
+		role, ok := auth.NormalizeRole(filter.Role)
+		if !ok {
+			return nil, ErrInvalidRole
+		}
+		filter.Role = role
+	}
+
+	// Токен привязан к фильтру и сортировке: курсор от другой выборки указывал бы не туда
+	fingerprint := userListFingerprint(filter, query.Sort)
+	opts := repository.UserListOptions{Filter: filter, SortBy: sortBy, Desc: desc, Limit: pageSize + 1}
+	if query.PageToken != "" {
+		cursor, err := decodeUserCursor(query.PageToken, fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		if sortBy == repository.UserSortCreatedAt {
+			if _, err := time.Parse(time.RFC3339Nano, cursor.Value); err != nil {
+				return nil, ErrInvalidPageToken
+			}
+		}
+		opts.After = cursor
+	}
+
+	// Запрашиваем на одного пользователя больше, чтобы узнать, есть ли следующая страница
+	users, err := uc.userRepo.ListPage(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &UserPage{Users: users}
+	if len(users) > pageSize {
+		page.Users = users[:pageSize]
+		last := page.Users[pageSize-1]
+		page.NextPageToken = encodeUserCursor(repository.UserCursor{
+			Value: repository.UserSortValue(last, sortBy),
+			ID:    last.ID,
+		}, fingerprint)
+	}
+	for _, u := range page.Users {
 		u.Password = ""
 	}
+
+	if query.WithTotal {
+		page.Total, err = uc.userRepo.Count(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// parseUserSort разбирает параметр сортировки вида "email" или "-created_at"
+func parseUserSort(sort string) (string, bool, error) {
+	if sort == "" {
+		return repository.UserSortCreatedAt, false, nil
+	}
+	field := strings.TrimPrefix(sort, "-")
+	if !repository.IsValidUserSort(field) {
+		return "", false, ErrInvalidSort
+	}
+	return field, field != sort, nil
+}
+
+// userListFingerprint возвращает короткий отпечаток фильтра и сортировки для токена страницы
+func userListFingerprint(filter repository.UserFilter, sort string) string {
+	data, _ := json.Marshal(struct {
+		Filter repository.UserFilter
+		Sort   string
+	}{filter, sort})
+	return hashToken(string(data))[:16]
+}
+
+// userCursorToken - содержимое токена страницы
+type userCursorToken struct {
+	Value       string `json:"v"`
+	ID          string `json:"i"`
+	Fingerprint string `json:"q"`
+}
+
+// encodeUserCursor кодирует курсор в непрозрачный для клиента токен страницы
+func encodeUserCursor(cursor repository.UserCursor, fingerprint string) string {
+	data, _ := json.Marshal(userCursorToken{Value: cursor.Value, ID: cursor.ID, Fingerprint: fingerprint})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeUserCursor разбирает токен страницы и проверяет, что он выдан для той же выборки
+func decodeUserCursor(token, fingerprint string) (*repository.UserCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var cursor userCursorToken
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" || cursor.Fingerprint != fingerprint {
+		return nil, ErrInvalidPageToken
+	}
+	if _, err := uuid.Parse(cursor.ID); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &repository.UserCursor{Value: cursor.Value, ID: cursor.ID}, nil
+}
+
+// BatchGetUsers выбирает пользователей одним запросом. Некорректные ID считаются ненайденными.
+func (uc *userUsecase) BatchGetUsers(ctx context.Context, ids []string) ([]*models.User, error) {
+	if len(ids) > MaxBatchGetUsers {
+		return nil, ErrTooManyIDs
+	}
+
+	// ID приводятся к каноническому виду, в котором их возвращает база
+	validIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if parsed, err := uuid.Parse(id); err == nil {
+			validIDs = append(validIDs, parsed.String())
+		}
+	}
+	if len(validIDs) == 0 {
+		return []*models.User{}, nil
+	}
+
+	found, err := uc.userRepo.GetByIDs(ctx, validIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.User, len(found))
+	for _, u := range found {
+		u.Password = ""
+		byID[u.ID] = u
+	}
+
+	users := make([]*models.User, 0, len(found))
+	seen := make(map[string]bool, len(found))
+	for _, id := range validIDs {
+		u, ok := byID[id]
+		if !ok || seen[u.ID] {
+			continue
+		}
+		seen[u.ID] = true
+		users = append(users, u)
+	}
 	return users, nil
 }
 
-func (uc *userUsecase) DeleteUser(ctx context.Context, id string) error {
-	return uc.userRepo.Delete(ctx, id)
+// GrantRole выдает пользователю роль. Повторная выдача уже имеющейся роли не считается ошибкой.
+func (uc *userUsecase) GrantRole(ctx context.Context, id, role string) (*models.User, error) {
+	return uc.changeRole(ctx, id, role, uc.userRepo.AddRole)
+}
+
+// RevokeRole отзывает у пользователя роль. Отзыв отсутствующей роли не считается ошибкой.
+func (uc *userUsecase) RevokeRole(ctx context.Context, id, role string) (*models.User, error) {
+	return uc.changeRole(ctx, id, role, uc.userRepo.RemoveRole)
+}
+
+// changeRole проверяет название роли и применяет к пользователю изменение change
+func (uc *userUsecase) changeRole(ctx context.Context, id, role string, change func(ctx context.Context, id, role string) (*models.User, error)) (*models.User, error) {
+	normalized, ok := auth.NormalizeRole(role)
+	if !ok {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := change(ctx, id, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// ChangePassword проверяет текущий пароль и устанавливает новый.
+func (uc *userUsecase) ChangePassword(ctx context.Context, id, currentPassword, newPassword string) error {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	if err := uc.passwordHasher.Compare(user.Password, currentPassword); err != nil {
+		return ErrInvalidCredentials
+	}
+	return uc.setPassword(ctx, id, newPassword)
+}
+
+// RequestPasswordReset выпускает токен сброса пароля и отправляет его пользователю.
+func (uc *userUsecase) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := uc.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().UTC().Add(uc.passwordReset.TokenTTL)
+	if err := uc.tokenRepo.CreatePasswordResetToken(ctx, user.ID, hashToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	return uc.notifier.Send(ctx, notifier.Message{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body:    tokenMessageBody("сброса пароля", uc.passwordReset, token),
+	})
+}
+
+// tokenMessageBody формирует текст сообщения со ссылкой или токеном для действия action
+func tokenMessageBody(action string, config EmailTokenConfig, token string) string {
+	ttl := config.TokenTTL.Round(time.Minute)
+	if link := config.link(token); link != "" {
+		return fmt.Sprintf("Для %s перейдите по ссылке: %s\nСсылка действует %s. Если вы не запрашивали это действие, проигнорируйте сообщение.", action, link, ttl)
+	}
+	return fmt.Sprintf("Токен для %s: %s\nТокен действует %s. Если вы не запрашивали это действие, проигнорируйте сообщение.", action, token, ttl)
+}
+
+// ResetPassword устанавливает новый пароль по токену сброса.
+func (uc *userUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+	// Пароль проверяется до погашения токена, чтобы слабый пароль не сжигал токен
+	hashedPassword, err := uc.hashNewPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uc.tokenRepo.ConsumePasswordResetToken(ctx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	if userID == "" {
+		return ErrInvalidResetToken
+	}
+	return uc.savePassword(ctx, userID, hashedPassword)
+}
+
+// hashNewPassword проверяет новый пароль по политике и хеширует его
+func (uc *userUsecase) hashNewPassword(password string) (string, error) {
+	if err := uc.passwordPolicy.Validate(password); err != nil {
+		return "", err
+	}
+	return uc.passwordHasher.Hash(password)
+}
+
+// setPassword хеширует и сохраняет новый пароль
+func (uc *userUsecase) setPassword(ctx context.Context, id, newPassword string) error {
+	hashedPassword, err := uc.hashNewPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	return uc.savePassword(ctx, id, hashedPassword)
+}
+
+// savePassword сохраняет хеш пароля и завершает все сеансы пользователя:
+// с прежними токенами обновления получить новый токен доступа уже нельзя
+func (uc *userUsecase) savePassword(ctx context.Context, id, hashedPassword string) error {
+	if err := uc.userRepo.UpdatePassword(ctx, id, hashedPassword); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return uc.tokenRepo.RevokeUserTokens(ctx, id)
+}
+
+// SendVerificationEmail повторно отправляет токен подтверждения email.
+func (uc *userUsecase) SendVerificationEmail(ctx context.Context, id string) error {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if user.EmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+	return uc.sendVerification(ctx, user)
+}
+
+// sendVerification выпускает токен подтверждения текущего email пользователя и отправляет его на этот адрес
+func (uc *userUsecase) sendVerification(ctx context.Context, user *models.User) error {
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().UTC().Add(uc.verification.TokenTTL)
+	if err := uc.tokenRepo.CreateEmailVerificationToken(ctx, user.ID, user.Email, hashToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	return uc.notifier.Send(ctx, notifier.Message{
+		To:      user.Email,
+		Subject: "Подтверждение email",
+		Body:    tokenMessageBody("подтверждения email", uc.verification, token),
+	})
+}
+
+// VerifyEmail подтверждает email по токену. Токен, выданный для прежнего адреса, недействителен.
+func (uc *userUsecase) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	userID, email, err := uc.tokenRepo.ConsumeEmailVerificationToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := uc.userRepo.MarkEmailVerified(ctx, userID, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// UnlockUser снимает блокировку входа по email пользователя.
+func (uc *userUsecase) UnlockUser(ctx context.Context, id string) error {
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	actorID, _ := auth.UserIDFromContext(ctx)
+	return uc.loginGuard.Unlock(ctx, user.ID, user.Email, actorID)
+}
+
+// ListAuthEvents возвращает записи журнала аутентификации по фильтру.
+func (uc *userUsecase) ListAuthEvents(ctx context.Context, filter repository.AuthEventFilter) ([]*models.AuthEvent, error) {
+	return uc.loginGuard.Events(ctx, filter)
 }
@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,9 +20,14 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/Hayzerr/go-microservice-project/auth"
 	pb "github.com/Hayzerr/go-microservice-project/pb"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/notifier"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/oidc"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/orders"
 	grpcDelivery "github.com/Hayzerr/go-microservice-project/user-service/internal/user/delivery/grpc"
 	httpDelivery "github.com/Hayzerr/go-microservice-project/user-service/internal/user/delivery/http"
+	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/models"
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/repository"
 	"github.com/Hayzerr/go-microservice-project/user-service/internal/user/usecase"
 )
@@ -31,6 +40,348 @@ func getenv(key, defaultValue string) string {
 	return value
 }
 
+// bootstrapAdmin выдает роль ADMIN зарегистрированному пользователю с указанным email
+func bootstrapAdmin(ctx context.Context, repo repository.UserRepository, uc usecase.UserUsecase, email string) {
+	user, err := repo.GetByEmail(ctx, email)
+	if err != nil {
+		log.Printf("Не удалось найти администратора %s: %v", email, err)
+		return
+	}
+	if user == nil {
+		log.Printf("Пользователь %s из ADMIN_EMAIL не зарегистрирован, роль ADMIN не выдана", email)
+		return
+	}
+	if _, err := uc.GrantRole(ctx, user.ID, auth.RoleAdmin); err != nil {
+		log.Printf("Не удалось выдать роль ADMIN пользователю %s: %v", email, err)
+		return
+	}
+	log.Printf("Пользователю %s выдана роль ADMIN", email)
+}
+
+// bootstrapServiceClient создает клиента OAuth2, от имени которого order-service резервирует товары.
+// Без него order-service не получит токен, поэтому ошибка останавливает запуск.
+func bootstrapServiceClient(ctx context.Context, uc usecase.OAuthUsecase, id, secret string) {
+	_, err := uc.EnsureClient(ctx, id, secret, usecase.OAuthClientInput{
+		Name:         "order-service",
+		GrantTypes:   []string{models.GrantClientCredentials},
+		Scopes:       []string{auth.ScopeInventoryWrite},
+		Confidential: true,
+	})
+	if err != nil {
+		log.Fatalf("Не удалось создать клиента OAuth2 для order-service: %v", err)
+	}
+	log.Printf("Клиент OAuth2 для order-service %s готов", id)
+}
+
+// newJWTManager настраивает подпись токенов по JWT_ALGORITHM:
+//   - RS256 (по умолчанию) или EdDSA - ключи из PEM-файлов JWT_PRIVATE_KEY_FILES (через запятую, первый
+//     подписывает токены) или сгенерированный при запуске ключ; ротация каждые JWT_KEY_ROTATION_INTERVAL;
+//   - HS256 - прежняя подпись общим секретом JWT_SECRET, который должен быть задан во всех сервисах.
+func newJWTManager(accessTTL time.Duration, denylist auth.Denylist, stop <-chan struct{}) (usecase.JWTManager, error) {
+	algorithm := getenv("JWT_ALGORITHM", auth.AlgRS256)
+	if algorithm == "HS256" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, errors.New("для JWT_ALGORITHM=HS256 необходимо задать JWT_SECRET")
+		}
+		return usecase.NewJWTManager(secret, accessTTL, denylist), nil
+	}
+
+	var keys []*auth.SigningKey
+	for _, path := range strings.Split(os.Getenv("JWT_PRIVATE_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		key, err := auth.LoadSigningKeyFile(path)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	keyRing, err := usecase.NewKeyRing(algorithm, accessTTL, keys...)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Токены подписываются ключом %s (kid %s)", keyRing.Current().Algorithm, keyRing.Current().ID)
+
+	// Ключами из файлов управляют извне, поэтому по умолчанию они не ротируются
+	defaultRotation := "24h"
+	if len(keys) > 0 {
+		defaultRotation = "0"
+	}
+	rotation, err := time.ParseDuration(getenv("JWT_KEY_ROTATION_INTERVAL", defaultRotation))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение JWT_KEY_ROTATION_INTERVAL: %w", err)
+	}
+	if rotation > 0 {
+		go keyRing.RunRotation(rotation, stop)
+	}
+
+	return usecase.NewKeyRingJWTManager(keyRing, accessTTL, denylist), nil
+}
+
+// newNotifier выбирает способ доставки сообщений пользователям по NOTIFIER:
+//   - log (по умолчанию) - сообщения выводятся в лог сервиса;
+//   - file - сообщения дописываются в файл NOTIFIER_FILE в формате JSON Lines.
+func newNotifier() (notifier.Notifier, error) {
+	switch kind := getenv("NOTIFIER", "log"); kind {
+	case "log":
+		return notifier.NewLogNotifier(), nil
+	case "file":
+		return notifier.NewFileNotifier(getenv("NOTIFIER_FILE", "notifications.jsonl")), nil
+	default:
+		return nil, fmt.Errorf("неизвестный NOTIFIER: %q", kind)
+	}
+}
+
+// newLoginAttemptStore выбирает хранилище попыток входа по LOGIN_ATTEMPT_STORE:
+// postgres (по умолчанию, общее для всех экземпляров сервиса) или memory.
+func newLoginAttemptStore(db *sql.DB) (repository.LoginAttemptStore, error) {
+	switch kind := getenv("LOGIN_ATTEMPT_STORE", "postgres"); kind {
+	case "postgres":
+		return repository.NewPostgresLoginAttemptStore(db), nil
+	case "memory":
+		return repository.NewMemoryLoginAttemptStore(), nil
+	default:
+		return nil, fmt.Errorf("неизвестный LOGIN_ATTEMPT_STORE: %q", kind)
+	}
+}
+
+// newLoginPolicy читает ограничения попыток входа из окружения поверх DefaultLoginPolicy
+func newLoginPolicy() (usecase.LoginPolicy, error) {
+	policy := usecase.DefaultLoginPolicy()
+	for key, target := range map[string]*int{
+		"LOGIN_MAX_FAILURES":    &policy.Email.MaxFailures,
+		"LOGIN_IP_MAX_FAILURES": &policy.IP.MaxFailures,
+	} {
+		if value := os.Getenv(key); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed <= 0 {
+				return policy, fmt.Errorf("некорректное значение %s: %q", key, value)
+			}
+			*target = parsed
+		}
+	}
+
+	lockout, err := time.ParseDuration(getenv("LOGIN_LOCKOUT_DURATION", policy.Lockout.String()))
+	if err != nil {
+		return policy, fmt.Errorf("некорректное значение LOGIN_LOCKOUT_DURATION: %w", err)
+	}
+	policy.Lockout = lockout
+	return policy, nil
+}
+
+// newPasswordHasher выбирает алгоритм хеширования новых паролей по PASSWORD_HASH_ALGORITHM:
+// argon2id (по умолчанию) или bcrypt. Хеши обоих алгоритмов проверяются при входе,
+// и хеш другого алгоритма или с другими параметрами пересчитывается текущим.
+func newPasswordHasher() (usecase.PasswordHasher, error) {
+	bcryptCost, err := strconv.Atoi(getenv("BCRYPT_COST", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение BCRYPT_COST: %w", err)
+	}
+	bcryptHasher := usecase.NewBcryptPasswordHasher(bcryptCost)
+
+	params := usecase.DefaultArgon2Params()
+	for key, target := range map[string]*uint32{
+		"ARGON2_MEMORY_KIB": &params.Memory,
+		"ARGON2_ITERATIONS": &params.Iterations,
+	} {
+		if value := os.Getenv(key); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || parsed == 0 {
+				return nil, fmt.Errorf("некорректное значение %s: %q", key, value)
+			}
+			*target = uint32(parsed)
+		}
+	}
+	if value := os.Getenv("ARGON2_PARALLELISM"); value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 8)
+		if err != nil || parsed == 0 {
+			return nil, fmt.Errorf("некорректное значение ARGON2_PARALLELISM: %q", value)
+		}
+		params.Parallelism = uint8(parsed)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("некорректные параметры ARGON2_*: %w", err)
+	}
+	argon2Hasher := usecase.NewArgon2idPasswordHasher(params)
+
+	switch algorithm := getenv("PASSWORD_HASH_ALGORITHM", "argon2id"); algorithm {
+	case "argon2id":
+		return usecase.NewMultiPasswordHasher(argon2Hasher, bcryptHasher), nil
+	case "bcrypt":
+		return usecase.NewMultiPasswordHasher(bcryptHasher, argon2Hasher), nil
+	default:
+		return nil, fmt.Errorf("неизвестный PASSWORD_HASH_ALGORITHM: %q", algorithm)
+	}
+}
+
+// newPasswordPolicy читает политику паролей из окружения поверх DefaultPasswordPolicy
+func newPasswordPolicy() (*usecase.PasswordPolicy, error) {
+	policy := usecase.DefaultPasswordPolicy()
+	if value := os.Getenv("PASSWORD_MIN_LENGTH"); value != "" {
+		minLength, err := strconv.Atoi(value)
+		if err != nil || minLength <= 0 {
+			return nil, fmt.Errorf("некорректное значение PASSWORD_MIN_LENGTH: %q", value)
+		}
+		policy.MinLength = minLength
+	}
+
+	classes, err := usecase.ParseCharClasses(os.Getenv("PASSWORD_REQUIRED_CLASSES"))
+	if err != nil {
+		return nil, fmt.Errorf("некорректное значение PASSWORD_REQUIRED_CLASSES: %w", err)
+	}
+	policy.RequiredClasses = classes
+
+	if path := os.Getenv("PASSWORD_BREACHED_LIST"); path != "" {
+		breached, err := usecase.LoadBreachedPasswords(path)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось загрузить PASSWORD_BREACHED_LIST: %w", err)
+		}
+		policy.Breached = breached
+		log.Printf("Загружен список паролей из утечек: %d", len(breached))
+	}
+	return policy, nil
+}
+
+// newTwoFactorConfig читает настройки двухфакторной аутентификации из окружения
+func newTwoFactorConfig() (usecase.TwoFactorConfig, error) {
+	config := usecase.TwoFactorConfig{
+		Issuer:      getenv("TOTP_ISSUER", "Go Microservices Demo"),
+		MaxAttempts: 5,
+	}
+
+	requireForAdmin, err := strconv.ParseBool(getenv("REQUIRE_2FA_FOR_ADMIN", "false"))
+	if err != nil {
+		return config, fmt.Errorf("некорректное значение REQUIRE_2FA_FOR_ADMIN: %w", err)
+	}
+	config.RequireForAdmin = requireForAdmin
+
+	challengeTTL, err := time.ParseDuration(getenv("TWO_FACTOR_CHALLENGE_TTL", "5m"))
+	if err != nil {
+		return config, fmt.Errorf("некорректное значение TWO_FACTOR_CHALLENGE_TTL: %w", err)
+	}
+	config.ChallengeTTL = challengeTTL
+	return config, nil
+}
+
+// purgeExpiredTokens раз в час удаляет истекшие токены обновления, сброса пароля, подтверждения email,
+// незавершенные входы с 2FA, записи об отозванных токенах и устаревшие счетчики попыток входа
+func purgeExpiredTokens(uc usecase.AuthUsecase, guard *usecase.LoginGuard, stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			deleted, err := uc.PurgeExpiredTokens(context.Background())
+			if err != nil {
+				log.Printf("Ошибка удаления истекших токенов: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Удалено истекших токенов: %d", deleted)
+			}
+			if _, err := guard.PurgeStale(context.Background()); err != nil {
+				log.Printf("Ошибка удаления устаревших попыток входа: %v", err)
+			}
+		}
+	}
+}
+
+// newAccountConfig читает настройки удаления учетных записей из окружения
+func newAccountConfig() (usecase.AccountConfig, error) {
+	config := usecase.AccountConfig{PurgeBatchSize: 100}
+
+	gracePeriod, err := time.ParseDuration(getenv("ACCOUNT_DELETION_GRACE_PERIOD", "720h"))
+	if err != nil {
+		return config, fmt.Errorf("некорректное значение ACCOUNT_DELETION_GRACE_PERIOD: %w", err)
+	}
+	if gracePeriod < 0 {
+		return config, errors.New("ACCOUNT_DELETION_GRACE_PERIOD не может быть отрицательным")
+	}
+	config.DeletionGracePeriod = gracePeriod
+	return config, nil
+}
+
+// newAPIKeyConfig читает ограничения API-ключей из окружения
+func newAPIKeyConfig(twoFactor usecase.TwoFactorConfig) (usecase.APIKeyConfig, error) {
+	config := usecase.APIKeyConfig{RequireTwoFactorForAdmin: twoFactor.RequireForAdmin}
+
+	for _, setting := range []struct {
+		name   string
+		value  string
+		target *int
+	}{
+		{"API_KEY_DEFAULT_RATE_LIMIT", "600", &config.DefaultRateLimit},
+		{"API_KEY_MAX_RATE_LIMIT", "6000", &config.MaxRateLimit},
+		{"API_KEY_MAX_PER_USER", "20", &config.MaxKeysPerUser},
+	} {
+		n, err := strconv.Atoi(getenv(setting.name, setting.value))
+		if err != nil || n <= 0 {
+			return config, fmt.Errorf("некорректное значение %s: %q", setting.name, os.Getenv(setting.name))
+		}
+		*setting.target = n
+	}
+	if config.DefaultRateLimit > config.MaxRateLimit {
+		return config, errors.New("API_KEY_DEFAULT_RATE_LIMIT не может превышать API_KEY_MAX_RATE_LIMIT")
+	}
+	return config, nil
+}
+
+// newOIDCProviders читает провайдеров OpenID Connect из окружения. OIDC_PROVIDERS - имена через запятую
+// (например, "google,apple"); для каждого имени NAME задаются OIDC_NAME_ISSUER, OIDC_NAME_CLIENT_ID,
+// OIDC_NAME_REDIRECT_URL, OIDC_NAME_CLIENT_SECRET (пусто для публичного клиента) и OIDC_NAME_SCOPES (через пробел).
+func newOIDCProviders() ([]usecase.OIDCProvider, error) {
+	var providers []usecase.OIDCProvider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		config := oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+			return nil, fmt.Errorf("для провайдера %s нужны %sISSUER, %sCLIENT_ID и %sREDIRECT_URL", name, prefix, prefix, prefix)
+		}
+		providers = append(providers, oidc.NewProvider(config))
+		log.Printf("Вход через провайдер OpenID Connect %s (%s)", name, config.Issuer)
+	}
+	return providers, nil
+}
+
+// purgeDeletedAccounts с интервалом interval обезличивает учетные записи,
+// срок ожидания которых после удаления истек
+func purgeDeletedAccounts(uc usecase.AccountUsecase, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			erased, err := uc.PurgeDeletedUsers(context.Background())
+			if err != nil {
+				log.Printf("Ошибка обезличивания удаленных учетных записей: %v", err)
+			}
+			if erased > 0 {
+				log.Printf("Обезличено удаленных учетных записей: %d", erased)
+			}
+		}
+	}
+}
+
 func main() {
 	grpcPort := getenv("GRPC_PORT", "50051")
 	httpPort := getenv("HTTP_PORT", "8081")
@@ -49,16 +400,124 @@ func main() {
 	}
 	log.Println("Успешное подключение к базе данных.")
 
+	accessTTL, err := time.ParseDuration(getenv("ACCESS_TOKEN_TTL", "15m"))
+	if err != nil {
+		log.Fatalf("Некорректное значение ACCESS_TOKEN_TTL: %v", err)
+	}
+	refreshTTL, err := time.ParseDuration(getenv("REFRESH_TOKEN_TTL", "720h"))
+	if err != nil {
+		log.Fatalf("Некорректное значение REFRESH_TOKEN_TTL: %v", err)
+	}
+	passwordResetTTL, err := time.ParseDuration(getenv("PASSWORD_RESET_TOKEN_TTL", "1h"))
+	if err != nil {
+		log.Fatalf("Некорректное значение PASSWORD_RESET_TOKEN_TTL: %v", err)
+	}
+	verificationTTL, err := time.ParseDuration(getenv("EMAIL_VERIFICATION_TOKEN_TTL", "48h"))
+	if err != nil {
+		log.Fatalf("Некорректное значение EMAIL_VERIFICATION_TOKEN_TTL: %v", err)
+	}
+	userNotifier, err := newNotifier()
+	if err != nil {
+		log.Fatalf("Ошибка настройки отправки сообщений: %v", err)
+	}
+	loginAttempts, err := newLoginAttemptStore(db)
+	if err != nil {
+		log.Fatalf("Ошибка настройки хранилища попыток входа: %v", err)
+	}
+	loginPolicy, err := newLoginPolicy()
+	if err != nil {
+		log.Fatalf("Ошибка настройки ограничения попыток входа: %v", err)
+	}
+	passwordHasher, err := newPasswordHasher()
+	if err != nil {
+		log.Fatalf("Ошибка настройки хеширования паролей: %v", err)
+	}
+	passwordPolicy, err := newPasswordPolicy()
+	if err != nil {
+		log.Fatalf("Ошибка настройки политики паролей: %v", err)
+	}
+	twoFactorConfig, err := newTwoFactorConfig()
+	if err != nil {
+		log.Fatalf("Ошибка настройки двухфакторной аутентификации: %v", err)
+	}
+	accountConfig, err := newAccountConfig()
+	if err != nil {
+		log.Fatalf("Ошибка настройки удаления учетных записей: %v", err)
+	}
+	apiKeyConfig, err := newAPIKeyConfig(twoFactorConfig)
+	if err != nil {
+		log.Fatalf("Ошибка настройки API-ключей: %v", err)
+	}
+	oidcProviders, err := newOIDCProviders()
+	if err != nil {
+		log.Fatalf("Ошибка настройки входа через провайдеров OpenID Connect: %v", err)
+	}
+	oidcStateTTL, err := time.ParseDuration(getenv("OIDC_STATE_TTL", "10m"))
+	if err != nil {
+		log.Fatalf("Некорректное значение OIDC_STATE_TTL: %v", err)
+	}
+	oauthCodeTTL, err := time.ParseDuration(getenv("OAUTH_CODE_TTL", "1m"))
+	if err != nil || oauthCodeTTL <= 0 {
+		log.Fatalf("Некорректное значение OAUTH_CODE_TTL: %q", os.Getenv("OAUTH_CODE_TTL"))
+	}
+	accountPurgeInterval, err := time.ParseDuration(getenv("ACCOUNT_PURGE_INTERVAL", "1h"))
+	if err != nil || accountPurgeInterval <= 0 {
+		log.Fatalf("Некорректное значение ACCOUNT_PURGE_INTERVAL: %q", os.Getenv("ACCOUNT_PURGE_INTERVAL"))
+	}
+
 	userRepo := repository.NewPostgresUserRepository(db)
-	hasher := usecase.NewBcryptPasswordHasher(0)
-	jwtManager := usecase.NewJWTManager("supersecretkey", 24*time.Hour)
-	userUsecase := usecase.NewUserUsecase(userRepo, hasher)
+	tokenRepo := repository.NewPostgresTokenRepository(db)
+	loginGuard := usecase.NewLoginGuard(loginAttempts, repository.NewPostgresAuditRepository(db), loginPolicy)
+	// Отозванные при выходе токены доступа проверяются по таблице revoked_access_tokens
+	stopRotation := make(chan struct{})
+	jwtManager, err := newJWTManager(accessTTL, tokenRepo, stopRotation)
+	if err != nil {
+		log.Fatalf("Ошибка настройки подписи токенов: %v", err)
+	}
+	userUsecase := usecase.NewUserUsecase(userRepo, tokenRepo, passwordHasher, passwordPolicy, userNotifier,
+		usecase.EmailTokenConfig{TokenTTL: passwordResetTTL, URL: os.Getenv("PASSWORD_RESET_URL")},
+		usecase.EmailTokenConfig{TokenTTL: verificationTTL, URL: os.Getenv("EMAIL_VERIFICATION_URL")},
+		loginGuard,
+	)
+	twoFactorRepo := repository.NewPostgresTwoFactorRepository(db)
+	authUsecase := usecase.NewAuthUsecase(userUsecase, tokenRepo, twoFactorRepo, jwtManager, refreshTTL, twoFactorConfig, loginGuard)
+
+	// Об обезличивании order-service узнает по служебному токену с ролью ADMIN
+	ordersClient := orders.NewClient(getenv("ORDER_SERVICE_URL", "http://localhost:8083"), func() (string, error) {
+		return jwtManager.GenerateToken("user-service", []string{auth.RoleAdmin})
+	})
+	addressRepo := repository.NewPostgresAddressRepository(db)
+	addressUsecase := usecase.NewAddressUsecase(userRepo, addressRepo)
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(db)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(userRepo, apiKeyRepo, twoFactorRepo, repository.NewPostgresAuditRepository(db), apiKeyConfig)
+	identityRepo := repository.NewPostgresExternalIdentityRepository(db)
+	oidcUsecase := usecase.NewOIDCUsecase(userRepo, identityRepo, authUsecase, repository.NewPostgresAuditRepository(db),
+		oidcProviders, usecase.OIDCConfig{StateTTL: oidcStateTTL})
+	oauthRepo := repository.NewPostgresOAuthRepository(db)
+	// OAUTH_ISSUER - внешний адрес user-service: с него клиенты загружают документ discovery
+	oauthUsecase := usecase.NewOAuthUsecase(userRepo, oauthRepo, tokenRepo, authUsecase, jwtManager, repository.NewPostgresAuditRepository(db),
+		usecase.OAuthConfig{Issuer: getenv("OAUTH_ISSUER", "http://localhost:"+httpPort), CodeTTL: oauthCodeTTL})
+	accountUsecase := usecase.NewAccountUsecase(userRepo, repository.NewPostgresAccountRepository(db), addressRepo, apiKeyRepo,
+		identityRepo, oauthRepo, tokenRepo, twoFactorRepo, repository.NewPostgresAuditRepository(db), ordersClient, accountConfig)
+
+	stopPurge := make(chan struct{})
+	go purgeExpiredTokens(authUsecase, loginGuard, stopPurge)
+	go purgeDeletedAccounts(accountUsecase, accountPurgeInterval, stopPurge)
+
+	// Первого администратора назначаем по email из окружения: выдавать роли может только ADMIN
+	if adminEmail := os.Getenv("ADMIN_EMAIL"); adminEmail != "" {
+		bootstrapAdmin(context.Background(), userRepo, userUsecase, adminEmail)
+	}
+	if clientID := os.Getenv("ORDER_SERVICE_CLIENT_ID"); clientID != "" {
+		bootstrapServiceClient(context.Background(), oauthUsecase, clientID, os.Getenv("ORDER_SERVICE_CLIENT_SECRET"))
+	}
 
 	// gRPC handler
-	userGRPCHandler := grpcDelivery.NewUserGRPCHandler(userUsecase)
+	userGRPCHandler := grpcDelivery.NewUserGRPCHandler(userUsecase, authUsecase, accountUsecase, addressUsecase)
 
 	// HTTP handler
-	userHTTPHandler := httpDelivery.NewUserHTTPHandler(userUsecase, jwtManager)
+	userHTTPHandler := httpDelivery.NewUserHTTPHandler(userUsecase, authUsecase, accountUsecase, addressUsecase, apiKeyUsecase, oidcUsecase,
+		oauthUsecase, jwtManager)
 
 	// gRPC сервер
 	go func() {
@@ -66,7 +525,18 @@ func main() {
 		if err != nil {
 			log.Fatalf("Ошибка прослушивания gRPC порта %s: %v", grpcPort, err)
 		}
-		gRPCServer := grpc.NewServer()
+		gRPCServer := grpc.NewServer(
+			grpc.ChainUnaryInterceptor(
+				auth.UnaryServerInterceptor(jwtManager, grpcDelivery.PublicMethods...),
+				auth.UnaryRoleInterceptor(grpcDelivery.RolePolicy),
+				auth.UnaryScopeInterceptor(grpcDelivery.ScopePolicy),
+			),
+			grpc.ChainStreamInterceptor(
+				auth.StreamServerInterceptor(jwtManager, grpcDelivery.PublicMethods...),
+				auth.StreamRoleInterceptor(grpcDelivery.RolePolicy),
+				auth.StreamScopeInterceptor(grpcDelivery.ScopePolicy),
+			),
+		)
 		pb.RegisterUserServiceServer(gRPCServer, userGRPCHandler)
 		reflection.Register(gRPCServer)
 		log.Printf("gRPC сервер запущен на порту: %s", grpcPort)
@@ -102,6 +572,9 @@ func main() {
 	<-quit
 	log.Println("Получен сигнал завершения, начинаю корректное выключение...")
 
+	close(stopPurge)
+	close(stopRotation)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := httpServer.Shutdown(ctx); err != nil {